@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/registry"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/ui"
+	"github.com/fgrehm/clotilde/internal/util"
+)
+
+// activeSession is a session found running when another one is launched.
+type activeSession struct {
+	Name     string
+	Project  string // Project root when it's not the current project
+	LastUsed time.Time
+}
+
+// warnActiveSessions warns on stderr, before claude is launched, when the
+// sessions already active across the registered projects reach the
+// "activeSessions.softLimit" config. launching is the session about to run,
+// which isn't counted even if a crash left it marked active.
+func warnActiveSessions(cmd *cobra.Command, clotildeRoot, launching string) {
+	limit, err := config.ActiveSessionsSoftLimit(clotildeRoot)
+	if err != nil || limit == 0 {
+		return
+	}
+	active := findActiveSessions(clotildeRoot, launching)
+	if len(active) < limit {
+		return
+	}
+	homeDir, _ := util.HomeDir()
+	writeActiveSessionsWarning(cmd.ErrOrStderr(), active, limit, homeDir)
+}
+
+// findActiveSessions returns the active sessions of the current project and
+// of every registered one, idle longest first. Projects that can't be read
+// are skipped: the warning is only a nudge.
+func findActiveSessions(clotildeRoot, launching string) []activeSession {
+	roots := []string{clotildeRoot}
+	if reg, err := registry.Load(); err == nil {
+		for _, p := range reg.Projects {
+			if root, found, _ := config.ProjectClotildeRoot(p.Path); found && root != clotildeRoot {
+				roots = append(roots, root)
+			}
+		}
+	}
+
+	var active []activeSession
+	for _, root := range roots {
+		store := session.NewFileStore(root)
+		sessions, err := store.List()
+		if err != nil {
+			continue
+		}
+		for _, sess := range sessions {
+			if sess.Status() != session.StatusActive || (root == clotildeRoot && sess.Name == launching) {
+				continue
+			}
+			_, lastUsed := extractModelAndLastUsed(root, sess, store)
+			found := activeSession{Name: sess.Name, LastUsed: lastUsed}
+			if root != clotildeRoot {
+				found.Project = config.ProjectRootOf(root)
+			}
+			active = append(active, found)
+		}
+	}
+	slices.SortFunc(active, func(a, b activeSession) int { return a.LastUsed.Compare(b.LastUsed) })
+	return active
+}
+
+// writeActiveSessionsWarning lists the active sessions, idle longest first,
+// as candidates for closing.
+func writeActiveSessionsWarning(w io.Writer, active []activeSession, limit int, homeDir string) {
+	_, _ = fmt.Fprintln(w, ui.Warning(fmt.Sprintf("%d session(s) already active (soft limit: %d), idle longest first:", len(active), limit)))
+	width := 0
+	for _, a := range active {
+		width = max(width, len(a.Name))
+	}
+	for _, a := range active {
+		line := fmt.Sprintf("  %-*s  last used %s", width, a.Name, util.FormatRelativeTime(a.LastUsed))
+		if a.Project != "" {
+			line += "  " + ui.DimStyle.Render(tildePath(homeDir, a.Project))
+		}
+		_, _ = fmt.Fprintln(w, line)
+	}
+	_, _ = fmt.Fprintln(w, "  Consider exiting the ones you're done with (set activeSessions.softLimit to change the limit).")
+	_, _ = fmt.Fprintln(w)
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/registry"
+	"github.com/fgrehm/clotilde/internal/session"
+)
+
+func TestFindActiveSessions(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	now := time.Now()
+
+	createSessions := func(projectRoot string, sessions map[string]time.Time, status string) string {
+		t.Helper()
+		clotildeRoot := filepath.Join(projectRoot, config.ClotildeDir)
+		store := session.NewFileStore(clotildeRoot)
+		for name, lastAccessed := range sessions {
+			sess := session.NewSession(name, name+"-uuid")
+			sess.Metadata.Status = status
+			sess.Metadata.LastAccessed = lastAccessed
+			if err := store.Create(sess); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return clotildeRoot
+	}
+
+	current := createSessions(t.TempDir(), map[string]time.Time{"recent": now.Add(-time.Minute), "launching": now}, session.StatusActive)
+	createSessions(config.ProjectRootOf(current), map[string]time.Time{"done": now}, session.StatusIdle)
+	otherProject := t.TempDir()
+	createSessions(otherProject, map[string]time.Time{"forgotten": now.Add(-48 * time.Hour)}, session.StatusActive)
+	if err := registry.Register(otherProject); err != nil {
+		t.Fatal(err)
+	}
+
+	active := findActiveSessions(current, "launching")
+	var names []string
+	for _, a := range active {
+		names = append(names, a.Name)
+	}
+	if strings.Join(names, ",") != "forgotten,recent" {
+		t.Fatalf("Expected the other project's session first, got %v", names)
+	}
+	if active[0].Project != otherProject || active[1].Project != "" {
+		t.Errorf("Expected only the other project's session to name its project, got %+v", active)
+	}
+}
+
+func TestWriteActiveSessionsWarning(t *testing.T) {
+	var out bytes.Buffer
+	writeActiveSessionsWarning(&out, []activeSession{
+		{Name: "forgotten", Project: "/home/me/src/api", LastUsed: time.Now().Add(-3 * time.Hour)},
+		{Name: "recent", LastUsed: time.Now()},
+	}, 2, "/home/me")
+
+	got := out.String()
+	for _, want := range []string{
+		"2 session(s) already active (soft limit: 2)",
+		"forgotten  last used 3 hours ago",
+		"~/src/api",
+		"recent     last used just now\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected %q in:\n%s", want, got)
+		}
+	}
+}
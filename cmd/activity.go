@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/fgrehm/clotilde/internal/claude"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/ui"
+	"github.com/fgrehm/clotilde/internal/util"
+)
+
+// activityWindow is how far back the dashboard's activity panel looks
+const activityWindow = 7 * 24 * time.Hour
+
+// activityLoader returns a loader for the dashboard's activity panel that
+// summarizes the last week of the sessions' transcripts.
+func activityLoader(clotildeRoot string, sessions []*session.Session) func() (ui.ActivityStats, error) {
+	return func() (ui.ActivityStats, error) {
+		homeDir, err := util.HomeDir()
+		if err != nil {
+			return ui.ActivityStats{}, fmt.Errorf("failed to find home directory: %w", err)
+		}
+		return collectActivity(clotildeRoot, homeDir, sessions, time.Now().Add(-activityWindow)), nil
+	}
+}
+
+// collectActivity adds up transcript stats since the cutoff across sessions.
+// Unreadable transcripts are skipped rather than failing the whole panel.
+func collectActivity(clotildeRoot, homeDir string, sessions []*session.Session, since time.Time) ui.ActivityStats {
+	activity := ui.ActivityStats{Period: "Last 7 days", Models: make(map[string]int)}
+
+	for _, sess := range sessions {
+		var total claude.TranscriptStats
+		for _, path := range claude.SessionTranscriptPaths(homeDir, clotildeRoot, sess) {
+			stats, err := claude.ReadTranscriptStats(path, since)
+			if err != nil {
+				continue
+			}
+			total.Add(stats)
+		}
+
+		activity.ActiveTime += total.ActiveTime
+		for model, n := range total.Models {
+			activity.Models[model] += n
+		}
+		if total.ActiveTime > 0 {
+			activity.Busiest = append(activity.Busiest, ui.SessionActivity{Name: sess.Name, ActiveTime: total.ActiveTime})
+		}
+	}
+
+	slices.SortFunc(activity.Busiest, func(a, b ui.SessionActivity) int {
+		return cmp.Compare(b.ActiveTime, a.ActiveTime)
+	})
+	return activity
+}
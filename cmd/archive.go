@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fgrehm/clotilde/internal/app"
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/errs"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/ui"
+)
+
+func newArchiveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "archive <name>...",
+		Short: "Mark sessions as archived",
+		Long: `Set the status of sessions you're done with for now to archived, so
+'clotilde list --status' can leave them out. Nothing is deleted, and resuming
+an archived session makes it active again.`,
+		Example:           `  clotilde archive auth-spike old-bugfix`,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: deleteCompletion,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return setSessionStatus(cmd, args, session.StatusArchived, "Archived")
+		},
+	}
+}
+
+func newUnarchiveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "unarchive <name>...",
+		Short: "Mark archived sessions as idle again",
+		Long: `Set archived sessions back to idle. This counts as using them, so the
+"autoArchiveAfter" policy leaves them alone for another period.`,
+		Example:           `  clotilde unarchive auth-spike`,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: sessionNameCompletion,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return setSessionStatus(cmd, args, session.StatusIdle, "Unarchived")
+		},
+	}
+}
+
+// setSessionStatus moves the named sessions between archived and idle. All
+// names are checked before any session is changed.
+func setSessionStatus(cmd *cobra.Command, names []string, status, verb string) error {
+	clotildeRoot, err := config.FindClotildeRoot()
+	if err != nil {
+		return errNoSessions()
+	}
+	store := session.NewFileStore(clotildeRoot)
+	archived := status == session.StatusArchived
+
+	var sessions []*session.Session
+	for _, name := range names {
+		sess, err := store.Get(name)
+		if err != nil {
+			return errs.NotFound("session '%s' not found", name)
+		}
+		if err := app.CheckArchive(sess, archived); err != nil {
+			return err
+		}
+		sessions = append(sessions, sess)
+	}
+
+	for _, sess := range sessions {
+		if err := app.ArchiveSession(store, sess, archived); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(cmd.OutOrStdout(), ui.Success(fmt.Sprintf("%s session '%s'", verb, sess.Name)))
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/claude"
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/errs"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/testutil"
+)
+
+var _ = Describe("Session status", func() {
+	var (
+		tempDir    string
+		originalWd string
+		claudeBin  string
+		store      session.Store
+	)
+
+	BeforeEach(func() {
+		tempDir = GinkgoT().TempDir()
+		GinkgoT().Setenv("XDG_CONFIG_HOME", filepath.Join(tempDir, "xdg"))
+
+		var err error
+		originalWd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Chdir(tempDir)).To(Succeed())
+
+		claudeBin, _, err = testutil.CreateFakeClaude(tempDir)
+		Expect(err).NotTo(HaveOccurred())
+		// The fake claude writes no transcript; resuming an empty session would remove it
+		claude.SessionUsedFunc = func(_ string, _ *session.Session) bool { return true }
+
+		Expect(config.EnsureClotildeStructure(tempDir)).To(Succeed())
+		store = session.NewFileStore(filepath.Join(tempDir, config.ClotildeDir))
+		Expect(store.Create(session.NewSession("done", "uuid-done"))).To(Succeed())
+		Expect(store.Create(session.NewSession("ongoing", "uuid-ongoing"))).To(Succeed())
+	})
+
+	AfterEach(func() {
+		_ = os.Chdir(originalWd)
+	})
+
+	run := func(args ...string) (string, error) {
+		return runClotilde(append([]string{"--claude-bin", claudeBin}, args...)...)
+	}
+
+	status := func(name string) string {
+		sess, err := store.Get(name)
+		Expect(err).NotTo(HaveOccurred())
+		return sess.Status()
+	}
+
+	It("archives and unarchives sessions", func() {
+		out, err := run("archive", "done")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(ContainSubstring("Archived session 'done'"))
+		Expect(status("done")).To(Equal(session.StatusArchived))
+
+		out, err = run("unarchive", "done")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(ContainSubstring("Unarchived session 'done'"))
+		Expect(status("done")).To(Equal(session.StatusIdle))
+	})
+
+	It("checks every name before archiving", func() {
+		_, err := run("archive", "done", "missing")
+		Expect(errs.ExitCode(err)).To(Equal(errs.ExitNotFound))
+		Expect(status("done")).To(Equal(session.StatusIdle))
+	})
+
+	It("refuses to unarchive sessions that aren't archived", func() {
+		_, err := run("unarchive", "ongoing")
+		Expect(err).To(MatchError(ContainSubstring("session 'ongoing' isn't archived (status: idle)")))
+	})
+
+	It("filters list by status", func() {
+		_, err := run("archive", "done")
+		Expect(err).NotTo(HaveOccurred())
+
+		out, err := run("list", "--status", "archived")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(ContainSubstring("Sessions (1 total)"))
+		Expect(out).To(MatchRegexp(`done.*archived`))
+		Expect(out).NotTo(ContainSubstring("ongoing"))
+
+		_, err = run("list", "--status", "sleeping")
+		Expect(err).To(MatchError(ContainSubstring("invalid status 'sleeping'")))
+	})
+
+	It("makes archived sessions idle again once claude exits", func() {
+		_, err := run("archive", "done")
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = run("resume", "done")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status("done")).To(Equal(session.StatusIdle))
+	})
+})
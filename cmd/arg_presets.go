@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/errs"
+)
+
+// registerWithFlag adds --with, which expands config "argPresets" into claude
+// CLI flags.
+func registerWithFlag(cmd *cobra.Command) {
+	cmd.Flags().StringSlice("with", nil, "Add the claude flags of these argPresets from config (e.g. --with debug)")
+	_ = cmd.RegisterFlagCompletionFunc("with", argPresetCompletion)
+}
+
+// claudeArgs returns the claude CLI flags for a launch: the --with presets,
+// in the order given, followed by anything after '--'.
+func claudeArgs(cmd *cobra.Command, args []string) ([]string, error) {
+	var passthrough []string
+	argsLenAtDash := cmd.Flags().ArgsLenAtDash()
+	if argsLenAtDash > 0 && len(args) > argsLenAtDash {
+		passthrough = args[argsLenAtDash:]
+	}
+
+	names, _ := cmd.Flags().GetStringSlice("with")
+	if len(names) == 0 {
+		return passthrough, nil
+	}
+	presets, err := argPresets()
+	if err != nil {
+		return nil, err
+	}
+	var expanded []string
+	for _, name := range names {
+		preset, ok := presets[name]
+		if !ok {
+			return nil, errs.NotFound("arg preset '%s' not found in config", name)
+		}
+		expanded = append(expanded, preset...)
+	}
+	return append(expanded, passthrough...), nil
+}
+
+// argPresets loads the merged presets, falling back to the global ones
+// outside a clotilde project.
+func argPresets() (map[string][]string, error) {
+	clotildeRoot, err := config.FindClotildeRoot()
+	if err != nil {
+		clotildeRoot = ""
+	}
+	return config.MergedArgPresets(clotildeRoot)
+}
+
+// argPresetCompletion completes --with with the configured preset names.
+func argPresetCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	presets, err := argPresets()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
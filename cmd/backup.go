@@ -0,0 +1,268 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+
+	"github.com/fgrehm/clotilde/internal/backup"
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/ui"
+	"github.com/fgrehm/clotilde/internal/util"
+)
+
+func newBackupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Back up or restore all sessions of a project",
+		Long: `Create a full backup of the project's clotilde sessions (metadata, settings,
+context, output styles, and config) together with the Claude Code transcripts
+they reference, or restore one into this project. Useful for moving sessions
+to another machine or checkout.`,
+	}
+
+	cmd.AddCommand(newBackupCreateCmd())
+	cmd.AddCommand(newBackupRestoreCmd())
+
+	return cmd
+}
+
+func newBackupCreateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Back up all sessions and their transcripts",
+		Long: `Back up the entire clotilde root plus every transcript referenced by its sessions.
+
+The output is written as a tarball when the path ends in .tar, .tar.gz, or .tgz,
+and as a directory otherwise.`,
+		Example: `  clotilde backup create
+  clotilde backup create --output ~/backups/myproject.tar.gz
+  clotilde backup create --output ./sessions-backup`,
+		Annotations: readOnly(),
+		Args:        cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clotildeRoot, err := findClotildeRoot()
+			if err != nil {
+				if projectRootOverride != "" {
+					return err
+				}
+				return errNoSessions()
+			}
+
+			homeDir, err := util.HomeDir()
+			if err != nil {
+				return fmt.Errorf("could not determine home directory: %w", err)
+			}
+
+			output, _ := cmd.Flags().GetString("output")
+			if output == "" {
+				output = fmt.Sprintf("clotilde-backup-%s.tar.gz", time.Now().Format("20060102-150405"))
+			}
+
+			var manifest *backup.Manifest
+			err = runWithProgress(cmd, "Backing up sessions", func(progress ui.ProgressReporter) error {
+				manifest, err = backup.Create(clotildeRoot, homeDir, output, progress)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("failed to create backup: %w", err)
+			}
+
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), ui.Success(fmt.Sprintf("Backed up %d session(s) and %d transcript(s) to %s",
+				len(manifest.Sessions), manifest.Transcripts, output)))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP("output", "o", "", "Output directory or tarball path (default: ./clotilde-backup-<timestamp>.tar.gz)")
+
+	return cmd
+}
+
+func newBackupRestoreCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore <path>",
+		Short: "Restore sessions from a backup into this project",
+		Long: `Merge a backup created with 'clotilde backup create' into the current project.
+
+When a session's name or UUID is already used here, you're asked what to do
+with it in a terminal: skip it, rename it (restored as <name>-restored, with
+new session IDs when it's a copy of a session already here), overwrite the
+existing session, or merge (for copies of the same session: keep the existing
+one and fill in its missing context, earlier transcripts, settings and env
+from the backup). --strategy answers the same for every
+conflict. Without a terminal or --strategy, copies of the same session are
+skipped and other conflicts renamed.
+
+Transcripts are placed in Claude Code's project directory for this checkout,
+so backups can be restored on another machine or at a different path.`,
+		Example: `  clotilde backup restore ~/backups/myproject.tar.gz
+  clotilde backup restore ./sessions-backup --strategy overwrite`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var strategy backup.Strategy
+			if s, _ := cmd.Flags().GetString("strategy"); s != "" {
+				var err error
+				if strategy, err = backup.ParseStrategy(s); err != nil {
+					return err
+				}
+			}
+
+			clotildeRoot, err := config.FindOrCreateClotildeRoot()
+			if err != nil {
+				return fmt.Errorf("failed to initialize session storage: %w", err)
+			}
+
+			homeDir, err := util.HomeDir()
+			if err != nil {
+				return fmt.Errorf("could not determine home directory: %w", err)
+			}
+
+			archive, err := backup.Open(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to restore backup: %w", err)
+			}
+			defer func() { _ = archive.Close() }()
+
+			conflicts, err := archive.Conflicts(clotildeRoot)
+			if err != nil {
+				return fmt.Errorf("failed to restore backup: %w", err)
+			}
+			strategies, err := resolveRestoreConflicts(conflicts, strategy)
+			if err != nil {
+				return err
+			}
+
+			var result *backup.RestoreResult
+			err = runWithProgress(cmd, "Restoring sessions", func(progress ui.ProgressReporter) error {
+				result, err = archive.Restore(clotildeRoot, homeDir, strategies, progress)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("failed to restore backup: %w", err)
+			}
+
+			out := cmd.OutOrStdout()
+			for _, r := range result.Restored {
+				if r.Renamed() {
+					_, _ = fmt.Fprintln(out, ui.Warning(fmt.Sprintf("Session '%s' already exists, restored as '%s'", r.From, r.Name)))
+				}
+			}
+			for _, name := range result.Overwritten {
+				_, _ = fmt.Fprintln(out, ui.Warning(fmt.Sprintf("Overwrote session '%s'", name)))
+			}
+			for _, r := range result.Merged {
+				_, _ = fmt.Fprintln(out, ui.Info(fmt.Sprintf("Merged '%s' into existing session '%s'", r.From, r.Name)))
+			}
+			for _, name := range result.Skipped {
+				_, _ = fmt.Fprintln(out, ui.Info(fmt.Sprintf("Skipped '%s' (already present)", name)))
+			}
+			if result.ConfigRestored {
+				_, _ = fmt.Fprintln(out, ui.Info("Restored config.json"))
+			}
+
+			_, _ = fmt.Fprintln(out, ui.Success(fmt.Sprintf("Restored %d session(s) and %d transcript(s)", len(result.Restored), result.Transcripts)))
+			return nil
+		},
+	}
+
+	cmd.Flags().String("strategy", "", "How to handle sessions whose name or UUID is taken: skip, rename, overwrite, merge")
+	_ = cmd.RegisterFlagCompletionFunc("strategy", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		names := make([]string, len(backup.Strategies))
+		for i, s := range backup.Strategies {
+			names[i] = string(s)
+		}
+		return names, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
+
+// resolveRestoreConflicts picks a strategy for each conflict, keyed by the
+// backup's session name: strategy for all of them when given, otherwise the
+// user's answer in a terminal. Without either, Restore's defaults apply.
+func resolveRestoreConflicts(conflicts []backup.Conflict, strategy backup.Strategy) (map[string]backup.Strategy, error) {
+	strategies := make(map[string]backup.Strategy)
+	if strategy != "" {
+		for _, c := range conflicts {
+			strategies[c.Session.Name] = strategy
+		}
+		return strategies, nil
+	}
+	if !isatty.IsTerminal(os.Stdin.Fd()) || !isatty.IsTerminal(os.Stdout.Fd()) {
+		return strategies, nil
+	}
+
+	var forAll backup.Strategy
+	for i, c := range conflicts {
+		if forAll != "" {
+			strategies[c.Session.Name] = forAll
+			continue
+		}
+
+		model := ui.NewChoice(fmt.Sprintf("Conflict %d of %d: %s", i+1, len(conflicts), restoreConflictSummary(c)), restoreConflictOptions(c)).
+			WithDetails(restoreConflictDetails(c))
+		if len(conflicts) > i+1 {
+			model = model.WithApplyToAll()
+		}
+		answer, err := ui.RunChoice(model)
+		if err != nil {
+			return nil, err
+		}
+		if answer.Selected < 0 {
+			return nil, fmt.Errorf("cancelled")
+		}
+
+		chosen := backup.Strategy(answer.Options[answer.Selected].Label)
+		strategies[c.Session.Name] = chosen
+		if answer.ApplyToAll {
+			forAll = chosen
+		}
+	}
+	return strategies, nil
+}
+
+func restoreConflictSummary(c backup.Conflict) string {
+	switch {
+	case c.SameSession():
+		return fmt.Sprintf("'%s' is already here", c.Session.Name)
+	case c.Existing.Name == c.Session.Name:
+		return fmt.Sprintf("the name '%s' is taken by another session", c.Session.Name)
+	default:
+		return fmt.Sprintf("'%s' has the same UUID as '%s'", c.Session.Name, c.Existing.Name)
+	}
+}
+
+func restoreConflictDetails(c backup.Conflict) []string {
+	describe := func(label string, sess *session.Session) string {
+		return fmt.Sprintf("%s: %s (%s), last used %s", label, sess.Name, sess.Metadata.SessionID, util.FormatRelativeTime(sess.Metadata.LastAccessed))
+	}
+	return []string{describe("backup", c.Session), describe("existing", c.Existing)}
+}
+
+// restoreConflictOptions offers the strategies that apply; merge only makes
+// sense for copies of the same session.
+func restoreConflictOptions(c backup.Conflict) []ui.ChoiceOption {
+	rename := "keep both, restore under a new name"
+	if c.SameSession() {
+		rename += " and new session IDs"
+	}
+	descriptions := map[backup.Strategy]string{
+		backup.StrategySkip:      "keep the existing session, don't restore",
+		backup.StrategyRename:    rename,
+		backup.StrategyOverwrite: fmt.Sprintf("delete '%s' and restore over it", c.Existing.Name),
+		backup.StrategyMerge:     "keep the existing session, fill in what it lacks from the backup",
+	}
+	var options []ui.ChoiceOption
+	for _, s := range backup.Strategies {
+		if s == backup.StrategyMerge && !c.SameSession() {
+			continue
+		}
+		options = append(options, ui.ChoiceOption{Key: string(s[0]), Label: string(s), Description: descriptions[s]})
+	}
+	return options
+}
@@ -0,0 +1,101 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/session"
+)
+
+var _ = Describe("Backup Command", func() {
+	var (
+		tempDir    string
+		originalWd string
+	)
+
+	BeforeEach(func() {
+		tempDir = GinkgoT().TempDir()
+		GinkgoT().Setenv("HOME", filepath.Join(tempDir, "home"))
+
+		var err error
+		originalWd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		_ = os.Chdir(originalWd)
+	})
+
+	run := func(dir string, args ...string) (string, error) {
+		Expect(os.Chdir(dir)).To(Succeed())
+		return runClotilde(args...)
+	}
+
+	It("backs up one project and restores it into another, renaming conflicts", func() {
+		sourceProject := filepath.Join(tempDir, "source")
+		Expect(config.EnsureClotildeStructure(sourceProject)).To(Succeed())
+		source := session.NewFileStore(filepath.Join(sourceProject, config.ClotildeDir))
+		Expect(source.Create(session.NewSession("alpha", "uuid-alpha"))).To(Succeed())
+		Expect(source.Create(session.NewSession("beta", "uuid-beta"))).To(Succeed())
+
+		targetProject := filepath.Join(tempDir, "target")
+		Expect(config.EnsureClotildeStructure(targetProject)).To(Succeed())
+		target := session.NewFileStore(filepath.Join(targetProject, config.ClotildeDir))
+		Expect(target.Create(session.NewSession("alpha", "uuid-other"))).To(Succeed())
+
+		archive := filepath.Join(tempDir, "backup.tar.gz")
+		out, err := run(sourceProject, "backup", "create", "--output", archive)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(ContainSubstring("Backed up 2 session(s)"))
+		Expect(archive).To(BeAnExistingFile())
+
+		out, err = run(targetProject, "backup", "restore", archive)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(ContainSubstring("Session 'alpha' already exists, restored as 'alpha-restored'"))
+		Expect(out).To(ContainSubstring("Restored 2 session(s)"))
+		Expect(target.Exists("alpha-restored")).To(BeTrue())
+		Expect(target.Exists("beta")).To(BeTrue())
+	})
+
+	It("applies --strategy to every conflict", func() {
+		sourceProject := filepath.Join(tempDir, "source")
+		Expect(config.EnsureClotildeStructure(sourceProject)).To(Succeed())
+		source := session.NewFileStore(filepath.Join(sourceProject, config.ClotildeDir))
+		Expect(source.Create(session.NewSession("alpha", "uuid-alpha"))).To(Succeed())
+		Expect(source.Create(session.NewSession("beta", "uuid-beta"))).To(Succeed())
+
+		targetProject := filepath.Join(tempDir, "target")
+		Expect(config.EnsureClotildeStructure(targetProject)).To(Succeed())
+		target := session.NewFileStore(filepath.Join(targetProject, config.ClotildeDir))
+		Expect(target.Create(session.NewSession("alpha", "uuid-other"))).To(Succeed())
+		Expect(target.Create(session.NewSession("beta", "uuid-beta"))).To(Succeed())
+
+		archive := filepath.Join(tempDir, "backup.tar.gz")
+		_, err := run(sourceProject, "backup", "create", "--output", archive)
+		Expect(err).NotTo(HaveOccurred())
+
+		out, err := run(targetProject, "backup", "restore", archive, "--strategy", "overwrite")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(ContainSubstring("Overwrote session 'alpha'"))
+		Expect(out).To(ContainSubstring("Overwrote session 'beta'"))
+
+		alpha, err := target.Get("alpha")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(alpha.Metadata.SessionID).To(Equal("uuid-alpha"))
+		Expect(target.Exists("alpha-restored")).To(BeFalse())
+	})
+
+	It("rejects unknown strategies", func() {
+		_, err := run(tempDir, "backup", "restore", filepath.Join(tempDir, "nope"), "--strategy", "clobber")
+		Expect(err).To(MatchError(ContainSubstring("invalid strategy 'clobber'")))
+	})
+
+	It("fails outside a clotilde project", func() {
+		_, err := run(tempDir, "backup", "create")
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,217 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/claude"
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/errs"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/testutil"
+	"github.com/fgrehm/clotilde/internal/util"
+)
+
+// These specs play scripted claude runs end to end: the fake writes real
+// transcripts and calls the real SessionStart hook, so nothing is stubbed.
+var _ = Describe("Claude scenarios", func() {
+	var (
+		tempDir      string
+		clotildeRoot string
+		binDir       string
+		originalWd   string
+		store        session.Store
+	)
+
+	BeforeEach(func() {
+		tempDir = GinkgoT().TempDir()
+		var err error
+		originalWd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Chdir(tempDir)).To(Succeed())
+
+		GinkgoT().Setenv("HOME", tempDir)
+		GinkgoT().Setenv("XDG_CONFIG_HOME", filepath.Join(tempDir, "xdg"))
+		GinkgoT().Setenv("CLAUDE_CONFIG_DIR", "")
+		GinkgoT().Setenv("CLAUDE_ENV_FILE", "")
+		GinkgoT().Setenv("CLOTILDE_SESSION_NAME", "")
+		GinkgoT().Setenv("CLOTILDE_HOOK_EXECUTED", "")
+		claude.SessionUsedFunc = claude.DefaultSessionUsed
+
+		binDir = filepath.Join(tempDir, "bin")
+		Expect(os.Mkdir(binDir, 0o755)).To(Succeed())
+		Expect(config.EnsureClotildeStructure(tempDir)).To(Succeed())
+		clotildeRoot = filepath.Join(tempDir, config.ClotildeDir)
+		store = session.NewFileStore(clotildeRoot)
+	})
+
+	AfterEach(func() {
+		claude.SessionUsedFunc = func(_ string, _ *session.Session) bool { return true }
+		_ = os.Chdir(originalWd)
+	})
+
+	run := func(scenario testutil.FakeClaudeScenario, args ...string) error {
+		claudeBin, _, err := testutil.CreateFakeClaudeScenario(binDir, scenario)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = runClotilde(append([]string{"--claude-bin", claudeBin}, args...)...)
+		return err
+	}
+
+	transcriptOf := func(sessionID string) string {
+		return claude.TranscriptPath(tempDir, clotildeRoot, sessionID)
+	}
+
+	Describe("empty sessions", func() {
+		It("removes a new session when claude exits before a message is sent", func() {
+			Expect(run(testutil.FakeClaudeScenario{}, "start", "idle")).To(Succeed())
+			Expect(store.Exists("idle")).To(BeFalse())
+		})
+
+		It("keeps a new session once claude writes its transcript", func() {
+			scenario := testutil.FakeClaudeScenario{Transcript: []string{`{"type":"user","message":{"content":"hi"}}`}}
+			Expect(run(scenario, "start", "chatty")).To(Succeed())
+
+			sess, err := store.Get("chatty")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sess.Metadata.PendingLaunch).To(BeFalse())
+			Expect(transcriptOf(sess.Metadata.SessionID)).To(BeAnExistingFile())
+		})
+	})
+
+	Describe("crashes", func() {
+		It("keeps a session whose claude was killed mid-conversation, with its error output", func() {
+			scenario := testutil.FakeClaudeScenario{
+				// Without a conversation, a failed launch is rolled back instead
+				Transcript: []string{`{"type":"user"}`},
+				Stderr:     "fatal: out of memory",
+				Signal:     "KILL",
+			}
+			err := run(scenario, "start", "doomed")
+			Expect(errs.ExitCode(err)).To(Equal(errs.ExitClaudeFailed))
+
+			sess, err := store.Get("doomed")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sess.Status()).To(Equal(session.StatusBroken))
+			Expect(sess.Metadata.LastExit.Code).To(Equal(137))
+			Expect(sess.Metadata.LastExit.Signal).To(Equal("killed"))
+
+			lastError, err := os.ReadFile(claude.LastErrorPath(clotildeRoot, "doomed"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(lastError)).To(ContainSubstring("fatal: out of memory"))
+		})
+	})
+
+	It("marks the session active while claude runs", func() {
+		Expect(store.Create(session.NewSession("busy", "uuid-busy"))).To(Succeed())
+
+		done := make(chan error, 1)
+		go func() {
+			defer GinkgoRecover()
+			done <- run(testutil.FakeClaudeScenario{Sleep: time.Second, Transcript: []string{`{"type":"user"}`}}, "resume", "busy")
+		}()
+		status := func() string {
+			sess, err := store.Get("busy")
+			Expect(err).NotTo(HaveOccurred())
+			return sess.Status()
+		}
+		Eventually(status).Should(Equal(session.StatusActive))
+		Eventually(done, "5s").Should(Receive(BeNil()))
+		Expect(status()).To(Equal(session.StatusIdle))
+	})
+
+	Describe("launchers", func() {
+		It("runs a session through its launcher, and its forks too", func() {
+			scenario := testutil.FakeClaudeScenario{Transcript: []string{`{"type":"user"}`}}
+			Expect(run(scenario, "start", "wrapped", "--launcher", "env LAUNCHED=yes {claude} --wrapped-as {name} {args}")).To(Succeed())
+
+			args, err := testutil.ReadClaudeArgs(filepath.Join(binDir, "claude-args.txt"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(args).To(HavePrefix("--wrapped-as wrapped --session-id "))
+
+			Expect(run(scenario, "fork", "wrapped", "wrapped-fork")).To(Succeed())
+			args, err = testutil.ReadClaudeArgs(filepath.Join(binDir, "claude-args.txt"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(args).To(HavePrefix("--wrapped-as wrapped-fork --resume "))
+		})
+
+		It("takes the launcher from the profile", func() {
+			cfg := config.NewConfig()
+			cfg.Profiles["wrapped"] = config.Profile{Launcher: []string{"{claude}", "--from-profile", "{args}"}}
+			Expect(util.WriteJSON(config.GetConfigPath(clotildeRoot), cfg)).To(Succeed())
+
+			Expect(run(testutil.FakeClaudeScenario{Transcript: []string{`{"type":"user"}`}}, "start", "work", "--profile", "wrapped")).To(Succeed())
+			sess, err := store.Get("work")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sess.Metadata.Launcher).To(Equal([]string{"{claude}", "--from-profile", "{args}"}))
+
+			args, err := testutil.ReadClaudeArgs(filepath.Join(binDir, "claude-args.txt"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(args).To(HavePrefix("--from-profile --session-id "))
+		})
+
+		It("rejects a launcher with unknown placeholders", func() {
+			err := run(testutil.FakeClaudeScenario{}, "start", "typo", "--launcher", "wrap {sesionId}")
+			Expect(err).To(MatchError(ContainSubstring("unknown launcher placeholder {sesionId}")))
+			Expect(store.Exists("typo")).To(BeFalse())
+		})
+
+		It("rejects an invalid defaults.launcher before running claude", func() {
+			cfg := config.NewConfig()
+			cfg.Defaults = &config.Profile{Launcher: []string{"wrap", "--args={args}"}}
+			Expect(util.WriteJSON(config.GetConfigPath(clotildeRoot), cfg)).To(Succeed())
+
+			err := run(testutil.FakeClaudeScenario{}, "start", "work")
+			Expect(err).To(MatchError(ContainSubstring(`invalid "defaults.launcher" config`)))
+			Expect(filepath.Join(binDir, "claude-args.txt")).NotTo(BeAnExistingFile())
+			Expect(store.Exists("work")).To(BeFalse())
+		})
+	})
+
+	Describe("the SessionStart hook", func() {
+		BeforeEach(func() {
+			sess := session.NewSession("work", "uuid-1")
+			sess.Metadata.Context = "auth refactor"
+			Expect(store.Create(sess)).To(Succeed())
+		})
+
+		It("saves the transcript path and injects the session's context on resume", func() {
+			scenario := testutil.FakeClaudeScenario{
+				Transcript: []string{`{"type":"user"}`},
+				Hook:       sessionStartHookCommand(),
+			}
+			Expect(run(scenario, "resume", "work")).To(Succeed())
+
+			sess, err := store.Get("work")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sess.Metadata.TranscriptPath).To(Equal(transcriptOf("uuid-1")))
+
+			output, err := os.ReadFile(testutil.FakeClaudeHookOutput(binDir))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(output)).To(ContainSubstring("Session name: work"))
+			Expect(string(output)).To(ContainSubstring("Context: auth refactor"))
+
+			env, err := os.ReadFile(testutil.FakeClaudeEnvFile(binDir))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(env)).To(ContainSubstring("CLOTILDE_SESSION=work"))
+		})
+
+		It("follows a /clear to the new session ID", func() {
+			scenario := testutil.FakeClaudeScenario{
+				Transcript:    []string{`{"type":"user"}`},
+				Hook:          sessionStartHookCommand(),
+				HookSource:    "clear",
+				HookSessionID: "uuid-2",
+			}
+			Expect(run(scenario, "resume", "work")).To(Succeed())
+
+			sess, err := store.Get("work")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sess.Metadata.SessionID).To(Equal("uuid-2"))
+			Expect(sess.Metadata.HasPreviousSessionID("uuid-1")).To(BeTrue())
+		})
+	})
+})
@@ -1,12 +1,44 @@
 package cmd_test
 
 import (
+	"os"
 	"testing"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/cmd"
+	"github.com/fgrehm/clotilde/internal/notify"
+	"github.com/fgrehm/clotilde/internal/util"
 )
 
+// runCLIEnv makes the test binary run as clotilde instead of running the
+// specs, so fake claude binaries can call it as their hooks. Its value is the
+// directory hook events are logged to.
+const runCLIEnv = "CLOTILDE_TEST_RUN_CLI"
+
+func TestMain(m *testing.M) {
+	if logDir := os.Getenv(runCLIEnv); logDir != "" {
+		notify.LogDir = logDir
+		cmd.Execute()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// sessionStartHookCommand returns the command a fake claude runs as its
+// SessionStart hook: this test binary, running 'clotilde hook sessionstart'.
+func sessionStartHookCommand() string {
+	self, err := os.Executable()
+	Expect(err).NotTo(HaveOccurred())
+	return runCLIEnv + "=" + util.ShellQuote(GinkgoT().TempDir()) + " " + util.ShellQuote(self) + " hook sessionstart"
+}
+
+// Keep every spec from writing to the real project registry.
+var _ = BeforeEach(func() {
+	GinkgoT().Setenv("XDG_DATA_HOME", GinkgoT().TempDir())
+})
+
 func TestCmd(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Cmd Suite")
@@ -9,33 +9,95 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/fgrehm/clotilde/internal/config"
-	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/util"
 )
 
+// sessionCompletionOptions tailors session name completion to a command.
+type sessionCompletionOptions struct {
+	firstArgOnly bool                         // Complete only the first positional argument
+	skip         func(completionSession) bool // Sessions not worth offering
+	describe     bool                         // Keep most-recent-first order, with last use and context as help text
+}
+
 // sessionNameCompletion provides dynamic completion for session names
 func sessionNameCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return completeSessionNames(args, toComplete, sessionCompletionOptions{})
+}
+
+// resumeCompletion offers the most recently used sessions first, described by
+// when they were last used and their context.
+func resumeCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return completeSessionNames(args, toComplete, sessionCompletionOptions{firstArgOnly: true, describe: true})
+}
+
+// deleteCompletion leaves out incognito sessions, which delete themselves on exit.
+func deleteCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return completeSessionNames(args, toComplete, sessionCompletionOptions{skip: isIncognitoCompletion})
+}
+
+// forkParentCompletion completes the parent only, leaving out incognito
+// sessions since they're gone once they exit.
+func forkParentCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return completeSessionNames(args, toComplete, sessionCompletionOptions{firstArgOnly: true, skip: isIncognitoCompletion})
+}
+
+func isIncognitoCompletion(sess completionSession) bool {
+	return sess.Incognito
+}
+
+// completeSessionNames returns the names starting with toComplete, from the
+// completion cache.
+func completeSessionNames(args []string, toComplete string, opts sessionCompletionOptions) ([]string, cobra.ShellCompDirective) {
+	if opts.firstArgOnly && len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
 	// Find clotilde root
-	clotildeRoot, err := config.FindClotildeRoot()
+	clotildeRoot, err := findClotildeRoot()
 	if err != nil {
 		return nil, cobra.ShellCompDirectiveNoFileComp
 	}
 
-	// Get all sessions
-	store := session.NewFileStore(clotildeRoot)
-	sessions, err := store.List()
+	sessions, err := completionSessions(clotildeRoot)
 	if err != nil {
 		return nil, cobra.ShellCompDirectiveNoFileComp
 	}
 
-	// Extract session names
+	// Extract matching session names
 	var names []string
 	for _, sess := range sessions {
-		names = append(names, sess.Metadata.Name)
+		if !strings.HasPrefix(sess.Name, toComplete) || opts.skip != nil && opts.skip(sess) {
+			continue
+		}
+		if opts.describe {
+			names = append(names, sess.Name+"\t"+describeCompletionSession(sess))
+		} else {
+			names = append(names, sess.Name)
+		}
 	}
 
+	if opts.describe {
+		return names, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+	}
 	return names, cobra.ShellCompDirectiveNoFileComp
 }
 
+// describeCompletionSession summarizes a session on one line, e.g.
+// "2 hours ago · fork · working on GH-123".
+func describeCompletionSession(sess completionSession) string {
+	parts := []string{util.FormatRelativeTime(sess.LastAccessed)}
+	if sess.Incognito {
+		parts = append(parts, "incognito")
+	}
+	if sess.Forked {
+		parts = append(parts, "fork")
+	}
+	if sess.Context != "" {
+		parts = append(parts, strings.Join(strings.Fields(sess.Context), " "))
+	}
+	return strings.Join(parts, " · ")
+}
+
 // modelCompletion provides completion for Claude model names
 func modelCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 	return []string{"haiku", "sonnet", "opus"}, cobra.ShellCompDirectiveNoFileComp
@@ -49,7 +111,7 @@ func effortCompletion(cmd *cobra.Command, args []string, toComplete string) ([]s
 // profileNameCompletion provides dynamic completion for profile names
 func profileNameCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 	// Find clotilde root
-	clotildeRoot, err := config.FindClotildeRoot()
+	clotildeRoot, err := findClotildeRoot()
 	if err != nil {
 		return nil, cobra.ShellCompDirectiveNoFileComp
 	}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/util"
+)
+
+// completionCacheFile keeps what shell completion needs about each session in
+// the sessions directory, so repeated TAB presses don't read every session.
+const completionCacheFile = ".completion-cache.json"
+
+// completionCacheTTL bounds how stale cached details (last access, context)
+// can get. Creating, renaming or deleting a session invalidates the cache
+// sooner, since it changes the sessions directory.
+const completionCacheTTL = 30 * time.Second
+
+// completionSession is the part of a session that completion uses.
+type completionSession struct {
+	Name         string    `json:"name"`
+	LastAccessed time.Time `json:"lastAccessed"`
+	Incognito    bool      `json:"incognito,omitempty"`
+	Forked       bool      `json:"forked,omitempty"`
+	Context      string    `json:"context,omitempty"`
+}
+
+type completionCache struct {
+	Created    time.Time           `json:"created"`
+	DirModTime time.Time           `json:"dirModTime"`
+	Sessions   []completionSession `json:"sessions"`
+}
+
+// completionSessions returns the sessions for completion, most recently used
+// first, from the cache while it's fresh. The cache is best effort: when it
+// can't be read or written the store is listed.
+func completionSessions(clotildeRoot string) ([]completionSession, error) {
+	sessionsDir := config.GetSessionsDir(clotildeRoot)
+	cachePath := filepath.Join(sessionsDir, completionCacheFile)
+
+	if info, err := os.Stat(sessionsDir); err == nil {
+		var cache completionCache
+		if util.ReadJSON(cachePath, &cache) == nil &&
+			time.Since(cache.Created) < completionCacheTTL &&
+			cache.DirModTime.Equal(info.ModTime()) {
+			return cache.Sessions, nil
+		}
+	}
+
+	sessions, err := session.NewFileStore(clotildeRoot).List()
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]completionSession, len(sessions))
+	for i, sess := range sessions {
+		entries[i] = completionSession{
+			Name:         sess.Name,
+			LastAccessed: sess.Metadata.LastAccessed,
+			Incognito:    sess.Metadata.IsIncognito,
+			Forked:       sess.Metadata.IsForkedSession,
+			Context:      sess.Metadata.Context,
+		}
+	}
+
+	// The cache would hold contexts in plain text
+	if encrypted, _ := config.EncryptionEnabled(clotildeRoot); encrypted {
+		_ = os.Remove(cachePath)
+		return entries, nil
+	}
+
+	// Creating the cache file changes the directory while rewriting it in
+	// place doesn't, so it's created before the directory's time is taken
+	if !util.FileExists(cachePath) && util.DirExists(sessionsDir) {
+		_ = util.WriteFile(cachePath, nil)
+	}
+	if info, err := os.Stat(sessionsDir); err == nil && util.FileExists(cachePath) {
+		_ = util.WriteJSON(cachePath, completionCache{Created: time.Now(), DirModTime: info.ModTime(), Sessions: entries})
+	}
+	return entries, nil
+}
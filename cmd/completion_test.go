@@ -0,0 +1,151 @@
+package cmd_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/session"
+)
+
+var _ = Describe("Session name completion", func() {
+	var (
+		tempDir     string
+		originalWd  string
+		sessionsDir string
+		store       session.Store
+	)
+
+	BeforeEach(func() {
+		tempDir = GinkgoT().TempDir()
+		GinkgoT().Setenv("HOME", filepath.Join(tempDir, "home"))
+		GinkgoT().Setenv("XDG_CONFIG_HOME", filepath.Join(tempDir, "xdg"))
+
+		var err error
+		originalWd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Chdir(tempDir)).To(Succeed())
+
+		Expect(config.EnsureClotildeStructure(tempDir)).To(Succeed())
+		clotildeRoot := filepath.Join(tempDir, config.ClotildeDir)
+		sessionsDir = config.GetSessionsDir(clotildeRoot)
+		store = session.NewFileStore(clotildeRoot)
+		Expect(store.Create(session.NewSession("alpha", "uuid-alpha"))).To(Succeed())
+		Expect(store.Create(session.NewSession("beta", "uuid-beta"))).To(Succeed())
+	})
+
+	AfterEach(func() {
+		_ = os.Chdir(originalWd)
+	})
+
+	// completeLines returns the raw candidates (with help text) offered for args
+	completeLines := func(args ...string) []string {
+		out, err := runClotilde(append([]string{"__complete"}, args...)...)
+		Expect(err).NotTo(HaveOccurred())
+
+		var lines []string
+		for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+			if !strings.HasPrefix(line, ":") {
+				lines = append(lines, line)
+			}
+		}
+		return lines
+	}
+
+	// completeFor returns the names offered for `clotilde <args...>`
+	completeFor := func(args ...string) []string {
+		var names []string
+		for _, line := range completeLines(args...) {
+			names = append(names, strings.SplitN(line, "\t", 2)[0])
+		}
+		return names
+	}
+
+	complete := func(prefix string) []string {
+		return completeFor("inspect", prefix)
+	}
+
+	It("filters names by the typed prefix", func() {
+		Expect(complete("al")).To(ConsistOf("alpha"))
+		Expect(complete("")).To(ConsistOf("alpha", "beta"))
+	})
+
+	It("serves names from the cache while it's fresh", func() {
+		Expect(complete("")).To(ConsistOf("alpha", "beta"))
+		cachePath := filepath.Join(sessionsDir, ".completion-cache.json")
+		Expect(cachePath).To(BeAnExistingFile())
+
+		// Rewrite the cache in place, as if it listed another session
+		var cache map[string]any
+		data, err := os.ReadFile(cachePath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(json.Unmarshal(data, &cache)).To(Succeed())
+		cache["sessions"] = []map[string]any{{"name": "cached-only"}}
+		data, err = json.Marshal(cache)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.WriteFile(cachePath, data, 0o644)).To(Succeed())
+
+		Expect(complete("")).To(ConsistOf("cached-only"))
+
+		cache["created"] = time.Now().Add(-time.Hour)
+		data, err = json.Marshal(cache)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.WriteFile(cachePath, data, 0o644)).To(Succeed())
+
+		Expect(complete("")).To(ConsistOf("alpha", "beta"))
+	})
+
+	Describe("per command", func() {
+		BeforeEach(func() {
+			ghost := session.NewIncognitoSession("ghost", "uuid-ghost")
+			ghost.Metadata.Context = "scratch\nwork"
+			Expect(store.Create(ghost)).To(Succeed())
+
+			// alpha was used most recently, then ghost, then beta
+			for name, ago := range map[string]time.Duration{"alpha": time.Minute, "ghost": time.Hour, "beta": 48 * time.Hour} {
+				sess, err := store.Get(name)
+				Expect(err).NotTo(HaveOccurred())
+				sess.Metadata.LastAccessed = time.Now().Add(-ago)
+				Expect(store.Update(sess)).To(Succeed())
+			}
+		})
+
+		It("orders resume candidates most recent first, with descriptions", func() {
+			Expect(completeLines("resume", "")).To(Equal([]string{
+				"alpha\t1 minute ago",
+				"ghost\t1 hour ago · incognito · scratch work",
+				"beta\t2 days ago",
+			}))
+			Expect(completeFor("resume", "alpha", "")).To(BeEmpty())
+		})
+
+		It("leaves incognito sessions out of delete and fork", func() {
+			Expect(completeFor("delete", "")).To(ConsistOf("alpha", "beta"))
+			Expect(completeFor("delete", "alpha", "--reparent", "")).To(ConsistOf("alpha", "beta"))
+			Expect(completeFor("fork", "")).To(ConsistOf("alpha", "beta"))
+			Expect(completeFor("fork", "alpha", "")).To(BeEmpty())
+		})
+
+		It("offers every session elsewhere", func() {
+			Expect(completeFor("inspect", "")).To(ConsistOf("alpha", "beta", "ghost"))
+		})
+	})
+
+	It("picks up new sessions right away", func() {
+		Expect(complete("")).To(ConsistOf("alpha", "beta"))
+
+		// Directory times can be coarse; make sure the new session changes it
+		past := time.Now().Add(-time.Minute)
+		Expect(os.Chtimes(sessionsDir, past, past)).To(Succeed())
+		Expect(complete("")).To(ConsistOf("alpha", "beta"))
+		Expect(store.Create(session.NewSession("gamma", "uuid-gamma"))).To(Succeed())
+
+		Expect(complete("")).To(ConsistOf("alpha", "beta", "gamma"))
+	})
+})
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fgrehm/clotilde/internal/claude"
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/util"
+)
+
+// confirmLevel is how deleting sessions gets confirmed.
+type confirmLevel int
+
+const (
+	confirmSkip  confirmLevel = iota // Delete without asking
+	confirmAsk                       // Yes or no
+	confirmTyped                     // Type the session name
+)
+
+// deletionConfirmation decides how deleting sess is confirmed under the
+// "confirm" config, shared by delete, prune and the dashboard. Large sessions
+// and sessions other sessions were forked from require typing, with the
+// reasons returned; sessions with fewer than confirm.skipBelowTurns turns are
+// deleted without asking.
+func deletionConfirmation(clotildeRoot string, sess *session.Session, store session.Store) (confirmLevel, []string) {
+	// Defaults are returned along with load errors
+	policy, _ := config.MergedConfirm(clotildeRoot)
+
+	if reasons := typedConfirmReasons(clotildeRoot, sess, store, policy); len(reasons) > 0 {
+		return confirmTyped, reasons
+	}
+	if policy.SkipBelowTurns > 0 && sessionTurns(clotildeRoot, sess) < policy.SkipBelowTurns {
+		return confirmSkip, nil
+	}
+	return confirmAsk, nil
+}
+
+// typedConfirmReasons explains why deleting sess warrants typed confirmation,
+// or returns nil when a plain confirmation is enough.
+func typedConfirmReasons(clotildeRoot string, sess *session.Session, store session.Store, policy config.Confirm) []string {
+	var reasons []string
+
+	if size := transcriptsSize(clotildeRoot, sess); size > int64(policy.TypedAboveKB)*1024 {
+		reasons = append(reasons, fmt.Sprintf("transcripts total %s", util.FormatSize(size)))
+	}
+
+	if sessions, err := store.List(); err == nil {
+		if forks := forkChildren(sessions, sess.Name); len(forks) > 0 {
+			reasons = append(reasons, fmt.Sprintf("parent of %d fork(s): %s", len(forks), joinSessionNames(forks)))
+		}
+	}
+
+	return reasons
+}
+
+// transcriptsSize returns the combined size of the session's current and
+// previous (from /clear) transcripts. Missing files count as zero.
+func transcriptsSize(clotildeRoot string, sess *session.Session) int64 {
+	homeDir, err := util.HomeDir()
+	if err != nil {
+		return 0
+	}
+
+	return filesSize(claude.SessionTranscriptPaths(homeDir, clotildeRoot, sess))
+}
+
+// sessionTurns counts Claude's replies across the session's transcripts.
+// Unreadable transcripts count as zero.
+func sessionTurns(clotildeRoot string, sess *session.Session) int {
+	homeDir, err := util.HomeDir()
+	if err != nil {
+		return 0
+	}
+
+	turns := 0
+	for _, path := range claude.SessionTranscriptPaths(homeDir, clotildeRoot, sess) {
+		if stats, err := claude.ReadTranscriptStats(path, time.Time{}); err == nil {
+			turns += stats.Messages
+		}
+	}
+	return turns
+}
+
+// promptConfirm asks question on out and reads the answer from stdin, for
+// when there's no terminal for a dialog. With phrase set, it must be typed
+// instead of answering y.
+func promptConfirm(out io.Writer, question, phrase string) (bool, error) {
+	if phrase != "" {
+		_, _ = fmt.Fprintf(out, "%s Type '%s' to confirm: ", question, phrase)
+	} else {
+		_, _ = fmt.Fprintf(out, "%s [y/N]: ", question)
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	response, err := reader.ReadString('\n')
+	if err != nil {
+		return false, fmt.Errorf("failed to read input: %w", err)
+	}
+	if phrase != "" {
+		return strings.TrimSpace(response) == phrase, nil
+	}
+	response = strings.TrimSpace(strings.ToLower(response))
+	return response == "y" || response == "yes", nil
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fgrehm/clotilde/internal/errs"
+	"github.com/fgrehm/clotilde/internal/hooks"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/ui"
+)
+
+func newContextCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "context",
+		Short: "Work with the context injected into sessions",
+	}
+	cmd.AddCommand(newContextPreviewCmd())
+	return cmd
+}
+
+func newContextPreviewCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "preview <name>",
+		Short: "Show the context the SessionStart hook injects for a session",
+		Long: `Print exactly what the SessionStart hook gives Claude Code when the session
+starts or resumes: the session name, its context and, for forks, the parent's
+name and context. Contexts are shown after the config's "redact" patterns are
+applied, so this is also a way to check those.
+
+On a terminal, labels are colored by where each line comes from (the session
+or its parent); redirected output is identical to the hook's.`,
+		Example:           `  clotilde context preview bugfix-auth-fork`,
+		Annotations:       readOnly(),
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: sessionNameCompletion,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			clotildeRoot, err := findClotildeRoot()
+			if err != nil {
+				if projectRootOverride != "" {
+					return err
+				}
+				return errNoSessions()
+			}
+			store := session.NewFileStore(clotildeRoot)
+			if !store.Exists(name) {
+				return errs.NotFound("session '%s' not found", name)
+			}
+
+			var buf bytes.Buffer
+			if err := hooks.WriteContexts(&buf, clotildeRoot, store, name); err != nil {
+				return fmt.Errorf("session context would not be injected: %w", err)
+			}
+			writeContextPreview(cmd.OutOrStdout(), buf.String())
+			return nil
+		},
+	}
+}
+
+// contextLabelStyles colors the labels hooks.WriteContexts uses by where the line
+// comes from.
+var contextLabelStyles = map[string]func(...string) string{
+	"Session name":           ui.InfoStyle.Render,
+	"Context":                ui.InfoStyle.Render,
+	"Forked from session":    ui.WarningStyle.Render,
+	"Parent session context": ui.WarningStyle.Render,
+}
+
+// writeContextPreview writes the hook output with its labels styled. Styles
+// render as plain text when colors are off, leaving the output unchanged.
+func writeContextPreview(w io.Writer, output string) {
+	for _, line := range strings.SplitAfter(output, "\n") {
+		label, rest, ok := strings.Cut(line, ": ")
+		style, known := contextLabelStyles[label]
+		if !ok || !known {
+			_, _ = io.WriteString(w, line)
+			continue
+		}
+		_, _ = io.WriteString(w, style(label+":")+" "+rest)
+	}
+}
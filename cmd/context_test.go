@@ -0,0 +1,96 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/snippet"
+	"github.com/fgrehm/clotilde/internal/util"
+)
+
+var _ = Describe("Context Command", func() {
+	var (
+		tempDir      string
+		clotildeRoot string
+		originalWd   string
+		store        session.Store
+	)
+
+	BeforeEach(func() {
+		tempDir = GinkgoT().TempDir()
+		GinkgoT().Setenv("XDG_CONFIG_HOME", filepath.Join(tempDir, "config"))
+
+		var err error
+		originalWd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Chdir(tempDir)).To(Succeed())
+
+		Expect(config.EnsureClotildeStructure(tempDir)).To(Succeed())
+		clotildeRoot = filepath.Join(tempDir, config.ClotildeDir)
+		store = session.NewFileStore(clotildeRoot)
+	})
+
+	AfterEach(func() {
+		_ = os.Chdir(originalWd)
+	})
+
+	preview := func(name string) (string, error) {
+		return runClotilde("context", "preview", name)
+	}
+
+	It("prints what the SessionStart hook injects for a fork", func() {
+		parent := session.NewSession("auth", "uuid-parent")
+		parent.Metadata.Context = "auth refactor"
+		Expect(store.Create(parent)).To(Succeed())
+		child := session.NewSession("auth-fork", "uuid-child")
+		child.Metadata.IsForkedSession = true
+		child.Metadata.ParentSession = "auth"
+		child.Metadata.Context = "token ghp_abc123 for the deploy"
+		Expect(store.Create(child)).To(Succeed())
+		Expect(util.WriteJSON(config.GetConfigPath(clotildeRoot), map[string]any{"redact": []string{`ghp_\w+`}})).To(Succeed())
+
+		out, err := preview("auth-fork")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(Equal("\nSession name: auth-fork\n" +
+			"Context: token [REDACTED] for the deploy\n" +
+			"Forked from session: auth\n" +
+			"Parent session context: auth refactor\n"))
+	})
+
+	It("prints only the name for sessions without context", func() {
+		Expect(store.Create(session.NewSession("plain", "uuid-plain"))).To(Succeed())
+
+		out, err := preview("plain")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(Equal("\nSession name: plain\n"))
+	})
+
+	It("includes the snippets pending for the launch", func() {
+		sess := session.NewSession("plain", "uuid-plain")
+		sess.Metadata.Context = "GH-123"
+		Expect(store.Create(sess)).To(Succeed())
+		Expect(snippet.WritePending(config.GetSessionDir(clotildeRoot, "plain"), "Snippet deploy:\nRun the migrations first\n")).To(Succeed())
+
+		out, err := preview("plain")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(Equal("\nSession name: plain\nContext: GH-123\nSnippet deploy:\nRun the migrations first\n"))
+	})
+
+	It("reports contexts that would be left out", func() {
+		Expect(store.Create(session.NewSession("plain", "uuid-plain"))).To(Succeed())
+		Expect(util.WriteJSON(config.GetConfigPath(clotildeRoot), map[string]any{"redact": []string{"("}})).To(Succeed())
+
+		_, err := preview("plain")
+		Expect(err).To(MatchError(ContainSubstring("would not be injected")))
+	})
+
+	It("fails for unknown sessions", func() {
+		_, err := preview("missing")
+		Expect(err).To(MatchError(ContainSubstring("session 'missing' not found")))
+	})
+})
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/daemon"
+)
+
+func newDaemonCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "daemon --stdio",
+		Short: "Serve JSON-RPC to editor extensions over stdin/stdout",
+		Long: `Serve the project's sessions over JSON-RPC 2.0, one message per line on
+stdin/stdout, so editor extensions can stay connected instead of spawning
+the CLI for every query. It runs until stdin is closed.
+
+Methods:
+  listSessions                                  session metadata, most recent first
+  createSession {name, context?, model?, effortLevel?}
+                                                creates without launching; returns its metadata
+  resumeSession {name, args?}                   {command, cwd} to run in a terminal
+  watchEvents                                   then "event" notifications for new events
+
+Session errors use code -32000 with the CLI's exit code in data.exitCode.`,
+		Example: `  echo '{"jsonrpc":"2.0","id":1,"method":"listSessions"}' | clotilde daemon --stdio`,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if stdio, _ := cmd.Flags().GetBool("stdio"); !stdio {
+				return fmt.Errorf("--stdio is required (it is the only transport)")
+			}
+
+			clotildeRoot, err := config.FindClotildeRoot()
+			if err != nil {
+				return errNoSessions()
+			}
+
+			return daemon.New(clotildeRoot).Serve(cmd.Context(), cmd.InOrStdin(), cmd.OutOrStdout())
+		},
+	}
+
+	cmd.Flags().Bool("stdio", false, "Speak JSON-RPC on stdin/stdout")
+	return cmd
+}
@@ -0,0 +1,50 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/session"
+)
+
+var _ = Describe("Daemon Command", func() {
+	var originalWd string
+
+	BeforeEach(func() {
+		tempDir := GinkgoT().TempDir()
+		GinkgoT().Setenv("HOME", filepath.Join(tempDir, "home"))
+
+		var err error
+		originalWd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Chdir(tempDir)).To(Succeed())
+
+		Expect(config.EnsureClotildeStructure(tempDir)).To(Succeed())
+		store := session.NewFileStore(filepath.Join(tempDir, config.ClotildeDir))
+		Expect(store.Create(session.NewSession("work", "uuid-work"))).To(Succeed())
+	})
+
+	AfterEach(func() {
+		_ = os.Chdir(originalWd)
+	})
+
+	run := func(stdin string, args ...string) (string, error) {
+		out, _, err := runClotildeWithInput(stdin, append([]string{"daemon"}, args...)...)
+		return out, err
+	}
+
+	It("answers requests from stdin until it closes", func() {
+		out, err := run(`{"jsonrpc":"2.0","id":7,"method":"listSessions"}`+"\n", "--stdio")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(HavePrefix(`{"jsonrpc":"2.0","id":7,"result":[{"name":"work"`))
+	})
+
+	It("requires --stdio", func() {
+		_, err := run("")
+		Expect(err).To(MatchError(ContainSubstring("--stdio is required")))
+	})
+})
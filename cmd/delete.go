@@ -1,9 +1,10 @@
 package cmd
 
 import (
-	"bufio"
 	"fmt"
+	"io"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/mattn/go-isatty"
@@ -11,179 +12,225 @@ import (
 
 	"github.com/fgrehm/clotilde/internal/claude"
 	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/errs"
 	"github.com/fgrehm/clotilde/internal/outputstyle"
 	"github.com/fgrehm/clotilde/internal/session"
 	"github.com/fgrehm/clotilde/internal/ui"
 	"github.com/fgrehm/clotilde/internal/util"
 )
 
-var deleteCmd = &cobra.Command{
-	Use:     "delete <name>",
-	Aliases: []string{"rm"},
-	Short:   "Delete a session and its Claude Code data",
-	Long: `Delete a session folder and associated Claude Code transcripts and logs.
-This operation cannot be undone.`,
-	Args:              cobra.ExactArgs(1),
-	ValidArgsFunction: sessionNameCompletion,
-	RunE: func(cmd *cobra.Command, args []string) error {
-		name := args[0]
-
-		// Find clotilde root
-		clotildeRoot, err := config.FindClotildeRoot()
-		if err != nil {
-			return fmt.Errorf("no sessions found (create one with 'clotilde start <name>')")
-		}
+func newDeleteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "delete <name>",
+		Aliases: []string{"rm"},
+		Short:   "Delete a session and its Claude Code data",
+		Long: `Delete a session folder and associated Claude Code transcripts and logs.
+This operation cannot be undone.
 
-		// Create store
-		store := session.NewFileStore(clotildeRoot)
+Forks of the deleted session are kept and detached from it (they become
+regular sessions). Use --cascade to delete them too, or --reparent to point
+them at another session.`,
+		Example: `  clotilde delete auth-feature
+  clotilde delete auth-feature --cascade
+  clotilde delete auth-feature --reparent main-work`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: deleteCompletion,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
 
-		// Load session to verify it exists
-		sess, err := store.Get(name)
-		if err != nil {
-			return fmt.Errorf("session '%s' not found", name)
-		}
+			// Find clotilde root
+			clotildeRoot, err := config.FindClotildeRoot()
+			if err != nil {
+				return errNoSessions()
+			}
 
-		// Get --force flag
-		force, _ := cmd.Flags().GetBool("force")
+			// Create store
+			store := session.NewFileStore(clotildeRoot)
 
-		// Confirmation prompt unless --force
-		if !force {
-			// Check if we're in a TTY (interactive terminal)
-			isTTY := isatty.IsTerminal(os.Stdout.Fd())
+			// Load session to verify it exists
+			sess, err := store.Get(name)
+			if err != nil {
+				return errs.NotFound("session '%s' not found", name)
+			}
 
-			if isTTY {
-				// Use TUI confirmation dialog
-				details := buildDeletionDetails(clotildeRoot, sess)
+			cascade, _ := cmd.Flags().GetBool("cascade")
+			reparent, _ := cmd.Flags().GetString("reparent")
 
-				confirmModel := ui.NewConfirm(
-					fmt.Sprintf("Delete session '%s'?", name),
-					"This will permanently delete:",
-				).WithDetails(details).WithDestructive()
+			sessions, err := store.List()
+			if err != nil {
+				return fmt.Errorf("failed to list sessions: %w", err)
+			}
+			if err := validateReparent(sessions, name, reparent); err != nil {
+				return err
+			}
 
-				confirmed, err := ui.RunConfirm(confirmModel)
-				if err != nil {
-					return fmt.Errorf("confirmation dialog failed: %w", err)
+			var descendants []*session.Session
+			var forkDetails []string
+			switch {
+			case cascade:
+				descendants = forkDescendants(sessions, name)
+				if len(descendants) > 0 {
+					forkDetails = append(forkDetails, fmt.Sprintf("%d fork(s): %s", len(descendants), joinSessionNames(descendants)))
+				}
+			case reparent != "" && reparent != reparentNone:
+				if children := forkChildren(sessions, name); len(children) > 0 {
+					forkDetails = append(forkDetails, fmt.Sprintf("Note: %d fork(s) will be reparented to '%s'", len(children), reparent))
+				}
+			}
+
+			// Confirmation prompt unless --force, or the "confirm" config says
+			// the session is too small to ask about (forks deleted along with it
+			// always are)
+			level, reasons := confirmSkip, []string(nil)
+			if force, _ := cmd.Flags().GetBool("force"); !force {
+				level, reasons = deletionConfirmation(clotildeRoot, sess, store)
+				if level == confirmSkip && len(descendants) > 0 {
+					level = confirmAsk
+				}
+			}
+			if level != confirmSkip {
+				var confirmed bool
+				if isatty.IsTerminal(os.Stdout.Fd()) {
+					// Use TUI confirmation dialog
+					confirmed, err = ui.RunConfirm(newDeleteConfirm(clotildeRoot, sess, store, reasons, forkDetails...))
+					if err != nil {
+						return fmt.Errorf("confirmation dialog failed: %w", err)
+					}
+				} else {
+					// Fallback to text prompt for non-TTY (scripts, pipes)
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Delete session '%s' (%s)?\n", name, sess.Metadata.SessionID)
+					for _, detail := range forkDetails {
+						_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  %s\n", detail)
+					}
+					for _, reason := range reasons {
+						_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  Warning: %s\n", reason)
+					}
+					phrase := ""
+					if level == confirmTyped {
+						phrase = name
+					}
+					confirmed, err = promptConfirm(cmd.OutOrStdout(), "This will delete the session folder and all Claude Code data.", phrase)
+					if err != nil {
+						return err
+					}
 				}
 
 				if !confirmed {
 					_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Cancelled.")
 					return nil
 				}
-			} else {
-				// Fallback to text prompt for non-TTY (scripts, pipes)
-				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Delete session '%s' (%s)?\n", name, sess.Metadata.SessionID)
-				_, _ = fmt.Fprint(cmd.OutOrStdout(), "This will delete the session folder and all Claude Code data. [y/N]: ")
-
-				reader := bufio.NewReader(os.Stdin)
-				response, err := reader.ReadString('\n')
-				if err != nil {
-					return fmt.Errorf("failed to read input: %w", err)
+			}
+
+			// Deepest forks go first so none is left pointing at a deleted parent
+			for _, fork := range slices.Backward(descendants) {
+				if err := deleteSession(cmd.OutOrStdout(), clotildeRoot, fork, store); err != nil {
+					return err
 				}
+			}
 
-				response = strings.TrimSpace(strings.ToLower(response))
-				if response != "y" && response != "yes" {
-					_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Cancelled.")
-					return nil
+			if reparent != "" && reparent != reparentNone {
+				if err := reparentForks(cmd.OutOrStdout(), store, sessions, name, reparent); err != nil {
+					return err
 				}
 			}
-		}
 
-		// Track all deleted files for verbose output
-		allDeletedFiles := &claude.DeletedFiles{
-			Transcript: []string{},
-			AgentLogs:  []string{},
-		}
+			return deleteSession(cmd.OutOrStdout(), clotildeRoot, sess, store)
+		},
+	}
+	cmd.Flags().BoolP("force", "f", false, "Skip confirmation prompt")
+	cmd.Flags().Bool("cascade", false, "Also delete the session's forks (and their forks)")
+	cmd.Flags().String("reparent", "", "Point the session's forks at another session, or 'none' to detach them (the default)")
+	cmd.MarkFlagsMutuallyExclusive("cascade", "reparent")
+	_ = cmd.RegisterFlagCompletionFunc("reparent", deleteCompletion)
+	return cmd
+}
 
-		// Delete Claude data for current session (transcript and agent logs)
-		deleted, err := claude.DeleteSessionData(clotildeRoot, sess.Metadata.SessionID, sess.Metadata.TranscriptPath)
-		if err != nil {
-			_, _ = fmt.Fprintln(cmd.OutOrStdout(), ui.Warning(fmt.Sprintf("Failed to delete Claude data for current session: %v", err)))
-		} else {
-			allDeletedFiles.Transcript = append(allDeletedFiles.Transcript, deleted.Transcript...)
-			allDeletedFiles.AgentLogs = append(allDeletedFiles.AgentLogs, deleted.AgentLogs...)
-		}
+// reparentNone is the --reparent value that detaches forks from their parent.
+const reparentNone = "none"
 
-		// Delete Claude data for previous sessions (from /clear operations, and defensively from /compact)
-		for _, prevSessionID := range sess.Metadata.PreviousSessionIDs {
-			deleted, err := claude.DeleteSessionData(clotildeRoot, prevSessionID, "")
-			if err != nil {
-				_, _ = fmt.Fprintln(cmd.OutOrStdout(), ui.Warning(fmt.Sprintf("Failed to delete Claude data for previous session %s: %v", prevSessionID, err)))
-			} else {
-				allDeletedFiles.Transcript = append(allDeletedFiles.Transcript, deleted.Transcript...)
-				allDeletedFiles.AgentLogs = append(allDeletedFiles.AgentLogs, deleted.AgentLogs...)
-			}
+// validateReparent checks that forks of name can be moved to target. A fork of
+// a fork would end up as its own ancestor, so only direct forks qualify.
+func validateReparent(sessions []*session.Session, name, target string) error {
+	if target == "" || target == reparentNone {
+		return nil
+	}
+	if target == name {
+		return fmt.Errorf("cannot reparent forks to the session being deleted")
+	}
+	if !slices.ContainsFunc(sessions, func(s *session.Session) bool { return s.Name == target }) {
+		return errs.NotFound("session '%s' not found", target)
+	}
+	for _, s := range forkDescendants(sessions, name) {
+		if s.Name == target && s.Metadata.ParentSession != name {
+			return fmt.Errorf("cannot reparent forks to '%s': it descends from them", target)
 		}
+	}
+	return nil
+}
 
-		// Delete session folder
-		if err := store.Delete(name); err != nil {
-			return fmt.Errorf("failed to delete session: %w", err)
-		}
+// forkDescendants returns the forks of the named session, their forks, and so
+// on, breadth first.
+func forkDescendants(sessions []*session.Session, name string) []*session.Session {
+	descendants := forkChildren(sessions, name)
+	for i := 0; i < len(descendants); i++ {
+		descendants = append(descendants, forkChildren(sessions, descendants[i].Name)...)
+	}
+	return descendants
+}
 
-		// Delete custom output style if it exists
-		if sess.Metadata.HasCustomOutputStyle {
-			if err := outputstyle.DeleteCustomStyleFile(clotildeRoot, name); err != nil {
-				_, _ = fmt.Fprintln(cmd.OutOrStdout(), ui.Warning(fmt.Sprintf("Failed to delete output style file: %v", err)))
-			}
+// reparentForks points the forks of parent at newParent. A fork chosen as the
+// new parent is detached instead.
+func reparentForks(out io.Writer, store session.Store, sessions []*session.Session, parent, newParent string) error {
+	forks := forkChildren(sessions, parent)
+	for _, fork := range forks {
+		if fork.Name == newParent {
+			fork.Metadata.IsForkedSession = false
+			fork.Metadata.ParentSession = ""
+		} else {
+			fork.Metadata.ParentSession = newParent
 		}
-
-		// Show summary of what was deleted
-		transcriptCount := len(allDeletedFiles.Transcript)
-		agentLogCount := len(allDeletedFiles.AgentLogs)
-		_, _ = fmt.Fprintln(cmd.OutOrStdout(), ui.Success(fmt.Sprintf("Deleted session '%s'", name)))
-		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  Session folder, %d transcript(s), %d agent log(s)\n", transcriptCount, agentLogCount)
-
-		// Show detailed file paths in verbose mode
-		if verbose {
-			if transcriptCount > 0 {
-				_, _ = fmt.Fprintln(cmd.OutOrStdout(), "\n  Deleted transcripts:")
-				for _, path := range allDeletedFiles.Transcript {
-					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "    %s\n", path)
-				}
-			}
-			if agentLogCount > 0 {
-				_, _ = fmt.Fprintln(cmd.OutOrStdout(), "\n  Deleted agent logs:")
-				for _, path := range allDeletedFiles.AgentLogs {
-					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "    %s\n", path)
-				}
-			}
+		if err := store.Update(fork); err != nil {
+			return fmt.Errorf("failed to reparent fork '%s': %w", fork.Name, err)
 		}
-		return nil
-	},
+	}
+	if len(forks) > 0 {
+		_, _ = fmt.Fprintf(out, "Reparented %d fork(s) to '%s'\n", len(forks), newParent)
+	}
+	return nil
 }
 
-func init() {
-	deleteCmd.Flags().BoolP("force", "f", false, "Skip confirmation prompt")
+// joinSessionNames lists session names for messages, e.g. "a, b".
+func joinSessionNames(sessions []*session.Session) string {
+	names := make([]string, len(sessions))
+	for i, s := range sessions {
+		names[i] = s.Name
+	}
+	return strings.Join(names, ", ")
 }
 
 // buildDeletionDetails builds a list of items that will be deleted
-func buildDeletionDetails(clotildeRoot string, sess *session.Session) []string {
+func buildDeletionDetails(clotildeRoot string, sess *session.Session, store session.Store) []string {
 	var details []string
 
 	// Session folder
 	sessionDir := config.GetSessionDir(clotildeRoot, sess.Name)
 	details = append(details, fmt.Sprintf("Session folder: %s", sessionDir))
 
-	// Claude transcript
-	if sess.Metadata.TranscriptPath != "" && util.FileExists(sess.Metadata.TranscriptPath) {
-		fileInfo, err := os.Stat(sess.Metadata.TranscriptPath)
-		if err == nil {
-			size := fileInfo.Size()
-			details = append(details, fmt.Sprintf("Claude transcript (%d KB)", size/1024))
-		} else {
-			details = append(details, "Claude transcript")
+	// Claude Code files, found the way deleting finds them
+	// Transcripts already pruned on /clear are gone
+	files, err := claude.FindSessionData(clotildeRoot, claude.SessionDataOf(sess))
+	if err != nil {
+		details = append(details, "Claude transcripts and agent logs")
+	} else {
+		if n := len(files.Transcripts); n > 0 {
+			details = append(details, fmt.Sprintf("%d Claude transcript(s) (%s)", n, util.FormatSize(filesSize(files.Transcripts))))
+		}
+		if n := len(files.AgentLogs); n > 0 {
+			details = append(details, fmt.Sprintf("%d agent log(s) (%s)", n, util.FormatSize(filesSize(files.AgentLogs))))
+		}
+		if n := len(files.Unchecked); n > 0 {
+			details = append(details, fmt.Sprintf("Warning: %d agent log(s) couldn't be read to check", n))
 		}
-	}
-
-	// Previous session UUIDs (from /clear operations)
-	if len(sess.Metadata.PreviousSessionIDs) > 0 {
-		details = append(details, fmt.Sprintf("%d previous session transcript(s) (from /clear)", len(sess.Metadata.PreviousSessionIDs)))
-	}
-
-	// Agent logs
-	if sess.Metadata.SessionID != "" {
-		// Note: We can't easily count agent logs here without reading them,
-		// so just mention they'll be cleaned up
-		details = append(details, "Agent logs (if any)")
 	}
 
 	// Fork safety note
@@ -192,9 +239,43 @@ func buildDeletionDetails(clotildeRoot string, sess *session.Session) []string {
 	}
 
 	// Custom output style
-	if sess.Metadata.HasCustomOutputStyle {
-		details = append(details, "Custom output style file")
+	if sess.Metadata.OwnsOutputStyle() {
+		if users, err := outputstyle.CustomStyleUsers(store, sess.Name); err == nil && len(users) > 0 {
+			details = append(details, fmt.Sprintf("Note: Custom output style kept, still used by: %s", strings.Join(users, ", ")))
+		} else {
+			details = append(details, "Custom output style file")
+		}
 	}
 
 	return details
 }
+
+// newDeleteConfirm builds the deletion dialog for sess, requiring the name to
+// be typed when reasons (from deletionConfirmation) are given. extraDetails are
+// appended to the list of what gets deleted.
+func newDeleteConfirm(clotildeRoot string, sess *session.Session, store session.Store, reasons []string, extraDetails ...string) ui.ConfirmModel {
+	details := append(buildDeletionDetails(clotildeRoot, sess, store), extraDetails...)
+	for _, reason := range reasons {
+		details = append(details, "Warning: "+reason)
+	}
+
+	confirmModel := ui.NewConfirm(
+		fmt.Sprintf("Delete session '%s'?", sess.Name),
+		"This will permanently delete:",
+	).WithDetails(details).WithDestructive()
+	if len(reasons) > 0 {
+		confirmModel = confirmModel.WithTypedConfirmation(sess.Name)
+	}
+	return confirmModel
+}
+
+// filesSize returns the combined size of paths. Missing files count as zero.
+func filesSize(paths []string) int64 {
+	var total int64
+	for _, path := range paths {
+		if info, err := os.Stat(path); err == nil {
+			total += info.Size()
+		}
+	}
+	return total
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/fgrehm/clotilde/cmd"
 	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/errs"
 	"github.com/fgrehm/clotilde/internal/session"
 	"github.com/fgrehm/clotilde/internal/testutil"
 )
@@ -90,6 +91,18 @@ var _ = Describe("Delete Command", func() {
 		Expect(err).To(HaveOccurred())
 	})
 
+	It("deletes without asking below confirm.skipBelowTurns", func() {
+		GinkgoT().Setenv("HOME", filepath.Join(tempDir, "home"))
+		GinkgoT().Setenv("XDG_CONFIG_HOME", filepath.Join(tempDir, "xdg"))
+		Expect(os.WriteFile(config.GetConfigPath(clotildeRoot), []byte(`{"confirm": {"skipBelowTurns": 3}}`), 0o644)).To(Succeed())
+		Expect(store.Create(session.NewSession("tiny", "uuid-tiny"))).To(Succeed())
+
+		// Without --force and without a terminal, a prompt would fail reading stdin
+		_, err := runClotilde("delete", "tiny")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(store.Exists("tiny")).To(BeFalse())
+	})
+
 	It("should return error for non-existent session", func() {
 		rootCmd := cmd.NewRootCmd()
 		rootCmd.SetOut(io.Discard)
@@ -99,6 +112,7 @@ var _ = Describe("Delete Command", func() {
 		err := rootCmd.Execute()
 		Expect(err).To(HaveOccurred())
 		Expect(err.Error()).To(ContainSubstring("not found"))
+		Expect(errs.ExitCode(err)).To(Equal(errs.ExitNotFound))
 	})
 
 	It("should delete session data including settings and prompts", func() {
@@ -132,4 +146,86 @@ var _ = Describe("Delete Command", func() {
 		Expect(err).To(HaveOccurred())
 		Expect(os.IsNotExist(err)).To(BeTrue())
 	})
+	Describe("sessions with forks", func() {
+		// parent <- child <- grandchild, parent <- sibling
+		BeforeEach(func() {
+			Expect(store.Create(session.NewSession("parent", "uuid-parent"))).To(Succeed())
+			Expect(store.Create(session.NewSession("other", "uuid-other"))).To(Succeed())
+			for name, parent := range map[string]string{"child": "parent", "sibling": "parent", "grandchild": "child"} {
+				fork := session.NewSession(name, "uuid-"+name)
+				fork.Metadata.IsForkedSession = true
+				fork.Metadata.ParentSession = parent
+				Expect(store.Create(fork)).To(Succeed())
+			}
+		})
+
+		runDelete := func(args ...string) error {
+			_, err := runClotilde(append([]string{"delete", "parent", "-f"}, args...)...)
+			return err
+		}
+
+		parentOf := func(name string) string {
+			sess, err := store.Get(name)
+			Expect(err).NotTo(HaveOccurred())
+			if !sess.Metadata.IsForkedSession {
+				return ""
+			}
+			return sess.Metadata.ParentSession
+		}
+
+		It("detaches forks by default", func() {
+			Expect(runDelete()).To(Succeed())
+
+			Expect(store.Exists("parent")).To(BeFalse())
+			Expect(parentOf("child")).To(BeEmpty())
+			Expect(parentOf("sibling")).To(BeEmpty())
+			Expect(parentOf("grandchild")).To(Equal("child"))
+		})
+
+		It("detaches forks with --reparent none", func() {
+			Expect(runDelete("--reparent", "none")).To(Succeed())
+
+			Expect(parentOf("child")).To(BeEmpty())
+			Expect(parentOf("sibling")).To(BeEmpty())
+		})
+
+		It("deletes forks and their forks with --cascade", func() {
+			Expect(runDelete("--cascade")).To(Succeed())
+
+			for _, name := range []string{"parent", "child", "sibling", "grandchild"} {
+				Expect(store.Exists(name)).To(BeFalse(), name)
+			}
+			Expect(store.Exists("other")).To(BeTrue())
+		})
+
+		It("moves forks to another session with --reparent", func() {
+			Expect(runDelete("--reparent", "other")).To(Succeed())
+
+			Expect(parentOf("child")).To(Equal("other"))
+			Expect(parentOf("sibling")).To(Equal("other"))
+			Expect(parentOf("grandchild")).To(Equal("child"))
+		})
+
+		It("promotes a fork chosen as the new parent", func() {
+			Expect(runDelete("--reparent", "child")).To(Succeed())
+
+			Expect(parentOf("child")).To(BeEmpty())
+			Expect(parentOf("sibling")).To(Equal("child"))
+		})
+
+		It("rejects reparenting to a fork of a fork", func() {
+			err := runDelete("--reparent", "grandchild")
+			Expect(err).To(MatchError(ContainSubstring("descends from them")))
+			Expect(store.Exists("parent")).To(BeTrue())
+		})
+
+		It("rejects reparenting to a missing session", func() {
+			Expect(runDelete("--reparent", "nope")).To(MatchError("session 'nope' not found"))
+		})
+
+		It("rejects --cascade together with --reparent", func() {
+			Expect(runDelete("--cascade", "--reparent", "other")).To(HaveOccurred())
+			Expect(store.Exists("parent")).To(BeTrue())
+		})
+	})
 })
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"runtime/pprof"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fgrehm/clotilde/internal/timing"
+	"github.com/fgrehm/clotilde/internal/ui"
+)
+
+// Set via --timings, --profile-cpu and --profile-mem
+var (
+	timingsEnabled bool
+	cpuProfilePath string
+	memProfilePath string
+)
+
+// cpuProfile is the open CPU profile while one is being recorded.
+var cpuProfile *os.File
+
+// registerDiagnosticFlags adds the flags used to diagnose slow commands. The
+// profiling flags are hidden: they are for bug reports, not everyday use.
+func registerDiagnosticFlags(root *cobra.Command) {
+	root.PersistentFlags().BoolVar(&timingsEnabled, "timings", false, "Report how long each phase of the command took (on stderr)")
+	root.PersistentFlags().StringVar(&cpuProfilePath, "profile-cpu", "", "Write a CPU profile to this file")
+	root.PersistentFlags().StringVar(&memProfilePath, "profile-mem", "", "Write a heap profile to this file when the command ends")
+	_ = root.PersistentFlags().MarkHidden("profile-cpu")
+	_ = root.PersistentFlags().MarkHidden("profile-mem")
+}
+
+// startDiagnostics starts the timings and CPU profile requested by flags.
+func startDiagnostics() error {
+	if timingsEnabled {
+		timing.Enable()
+	}
+	if cpuProfilePath != "" {
+		f, err := os.Create(cpuProfilePath)
+		if err != nil {
+			return fmt.Errorf("failed to create CPU profile: %w", err)
+		}
+		if err := pprof.StartCPUProfile(f); err != nil {
+			_ = f.Close()
+			return fmt.Errorf("failed to start CPU profile: %w", err)
+		}
+		cpuProfile = f
+	}
+	return nil
+}
+
+// stopDiagnostics finishes the profiles and prints the timings. It runs after
+// the command, whether or not it failed, so problems are only warnings.
+func stopDiagnostics(w io.Writer) {
+	if cpuProfile != nil {
+		pprof.StopCPUProfile()
+		if err := cpuProfile.Close(); err != nil {
+			_, _ = fmt.Fprintln(w, ui.Warning(fmt.Sprintf("Failed to write CPU profile: %v", err)))
+		}
+		cpuProfile = nil
+	}
+	if memProfilePath != "" {
+		if err := writeHeapProfile(memProfilePath); err != nil {
+			_, _ = fmt.Fprintln(w, ui.Warning(fmt.Sprintf("Failed to write heap profile: %v", err)))
+		}
+	}
+	timing.Report(w)
+}
+
+func writeHeapProfile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	runtime.GC() // Up-to-date allocation statistics
+	if err := pprof.WriteHeapProfile(f); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDiagnosticFlags(t *testing.T) {
+	dir := t.TempDir()
+	cpuPath := filepath.Join(dir, "cpu.pprof")
+	memPath := filepath.Join(dir, "mem.pprof")
+
+	root := NewRootCmd()
+	root.SetOut(io.Discard)
+	root.SetErr(io.Discard)
+	root.SetArgs([]string{"version", "--timings", "--profile-cpu", cpuPath, "--profile-mem", memPath})
+	if err := root.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	var report bytes.Buffer
+	stopDiagnostics(&report)
+
+	if !strings.HasPrefix(report.String(), "Timings:\n") || !strings.Contains(report.String(), "  total ") {
+		t.Errorf("expected a timings report, got %q", report.String())
+	}
+	for _, path := range []string{cpuPath, memPath} {
+		if info, err := os.Stat(path); err != nil || info.Size() == 0 {
+			t.Errorf("expected a non-empty profile at %s (err: %v)", path, err)
+		}
+	}
+
+	// Without the flags nothing is reported
+	root = NewRootCmd()
+	root.SetOut(io.Discard)
+	root.SetArgs([]string{"version"})
+	if err := root.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	report.Reset()
+	stopDiagnostics(&report)
+	if report.Len() != 0 {
+		t.Errorf("expected no report without --timings, got %q", report.String())
+	}
+}
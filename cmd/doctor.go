@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/ui"
+	"github.com/fgrehm/clotilde/internal/util"
+)
+
+func newDoctorCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check sessions for problems",
+		Long: `Run health checks on the project's sessions and report what needs attention.
+Exits with an error when a check finds problems, so it can run in scripts.
+
+Checks (all run when none is selected):
+  --hooks         Verify that every installed clotilde hook runs a binary that
+                  exists (by path, or through PATH for a bare "clotilde").
+                  Broken hooks fail silently in Claude Code.
+  --transcripts   Validate each session's transcript: unreadable JSONL lines,
+                  a truncated last line (interrupted write), or no assistant
+                  replies. Resuming into a damaged transcript can fail or lose
+                  history.`,
+		Example: `  clotilde doctor
+  clotilde doctor --hooks
+  clotilde doctor --transcripts`,
+		Annotations: readOnly(),
+		Args:        cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			hooks, _ := cmd.Flags().GetBool("hooks")
+			transcripts, _ := cmd.Flags().GetBool("transcripts")
+			all := !hooks && !transcripts
+
+			var problems []string
+			if hooks || all {
+				if failed := checkHooks(cmd); failed > 0 {
+					problems = append(problems, fmt.Sprintf("%d broken hook(s)", failed))
+				}
+			}
+
+			if transcripts || all {
+				clotildeRoot, err := findClotildeRoot()
+				switch {
+				case err != nil && projectRootOverride != "":
+					return err
+				case err != nil && transcripts:
+					return errNoSessions()
+				case err != nil:
+					// Nothing to check outside a project when running every check
+				default:
+					store := session.NewFileStore(clotildeRoot)
+					sessions, err := store.List()
+					if err != nil {
+						return fmt.Errorf("failed to list sessions: %w", err)
+					}
+					if failed := checkTranscripts(cmd, clotildeRoot, sessions); failed > 0 {
+						problems = append(problems, fmt.Sprintf("problems in %d session(s)", failed))
+					}
+				}
+			}
+
+			if len(problems) > 0 {
+				return fmt.Errorf("found %s", strings.Join(problems, " and "))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().Bool("hooks", false, "Check that installed hooks resolve to a clotilde binary")
+	cmd.Flags().Bool("transcripts", false, "Check transcript integrity")
+	return cmd
+}
+
+// checkTranscripts prints the transcript health of each session and returns
+// how many have problems.
+func checkTranscripts(cmd *cobra.Command, clotildeRoot string, sessions []*session.Session) int {
+	out := cmd.OutOrStdout()
+	_, _ = fmt.Fprintln(out, "Transcripts:")
+
+	homeDir, err := util.HomeDir()
+	if err != nil {
+		_, _ = fmt.Fprintln(out, ui.Warning(fmt.Sprintf("  cannot locate transcripts: %v", err)))
+		return len(sessions)
+	}
+
+	var failed int
+	for _, sess := range sessions {
+		health, err := transcriptHealth(sess, clotildeRoot, homeDir)
+		switch {
+		case err != nil:
+			failed++
+			_, _ = fmt.Fprintf(out, "  ✗ %s: %v\n", sess.Name, err)
+		case health.Missing:
+			_, _ = fmt.Fprintf(out, "  - %s: no transcript yet\n", sess.Name)
+		case len(health.Problems()) > 0:
+			failed++
+			_, _ = fmt.Fprintf(out, "  ✗ %s: %s\n", sess.Name, strings.Join(health.Problems(), ", "))
+		default:
+			_, _ = fmt.Fprintf(out, "  ✓ %s\n", sess.Name)
+		}
+	}
+
+	if failed == 0 {
+		_, _ = fmt.Fprintln(out, ui.Success(fmt.Sprintf("%d session(s) checked, no problems found", len(sessions))))
+	}
+	return failed
+}
+
+// checkHooks prints every clotilde hook that applies to the current directory
+// and returns how many run a binary that can't be found.
+func checkHooks(cmd *cobra.Command) int {
+	out := cmd.OutOrStdout()
+	_, _ = fmt.Fprintln(out, "Hooks:")
+
+	homeDir, err := util.HomeDir()
+	if err != nil {
+		_, _ = fmt.Fprintln(out, ui.Warning(fmt.Sprintf("  cannot locate settings: %v", err)))
+		return 1
+	}
+	projectRoot, err := config.FindProjectRoot()
+	if err != nil {
+		_, _ = fmt.Fprintln(out, ui.Warning(fmt.Sprintf("  cannot locate project: %v", err)))
+		return 1
+	}
+
+	var total, failed int
+	for _, file := range hookSettingsFiles(homeDir, projectRoot) {
+		hooks, err := findClotildeHooks(file.Path)
+		if err != nil {
+			failed++
+			_, _ = fmt.Fprintf(out, "  ✗ %s: %v\n", file.Scope, err)
+			continue
+		}
+		for _, h := range hooks {
+			total++
+			if h.Exists {
+				_, _ = fmt.Fprintf(out, "  ✓ %s %s: %s\n", file.Scope, h.Event, h.Command)
+				continue
+			}
+			failed++
+			_, _ = fmt.Fprintf(out, "  ✗ %s %s: %s (binary not found)\n", file.Scope, h.Event, h.Command)
+		}
+	}
+
+	switch {
+	case total == 0:
+		_, _ = fmt.Fprintln(out, "  - no clotilde hooks installed (run 'clotilde setup')")
+	case failed > 0:
+		_, _ = fmt.Fprintln(out, ui.Warning("  Run 'clotilde hooks status --repair' to point broken hooks at this binary"))
+	}
+	return failed
+}
@@ -0,0 +1,96 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/session"
+)
+
+var _ = Describe("Doctor Command", func() {
+	var (
+		tempDir    string
+		originalWd string
+		store      session.Store
+	)
+
+	BeforeEach(func() {
+		tempDir = GinkgoT().TempDir()
+		GinkgoT().Setenv("HOME", filepath.Join(tempDir, "home"))
+
+		var err error
+		originalWd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Chdir(tempDir)).To(Succeed())
+
+		Expect(config.EnsureClotildeStructure(tempDir)).To(Succeed())
+		store = session.NewFileStore(filepath.Join(tempDir, config.ClotildeDir))
+	})
+
+	AfterEach(func() {
+		_ = os.Chdir(originalWd)
+	})
+
+	withTranscript := func(name, content string) {
+		sess := session.NewSession(name, "uuid-"+name)
+		sess.Metadata.TranscriptPath = filepath.Join(tempDir, name+".jsonl")
+		Expect(os.WriteFile(sess.Metadata.TranscriptPath, []byte(content), 0o644)).To(Succeed())
+		Expect(store.Create(sess)).To(Succeed())
+	}
+
+	run := runClotilde
+
+	BeforeEach(func() {
+		withTranscript("healthy", `{"type":"user"}`+"\n"+`{"type":"assistant"}`+"\n")
+		Expect(store.Create(session.NewSession("unused", "uuid-unused"))).To(Succeed())
+	})
+
+	It("passes when every transcript is intact", func() {
+		out, err := run("doctor", "--transcripts")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(ContainSubstring("✓ healthy"))
+		Expect(out).To(ContainSubstring("- unused: no transcript yet"))
+		Expect(out).To(ContainSubstring("no problems found"))
+	})
+
+	It("reports damaged transcripts and fails", func() {
+		withTranscript("broken", `{"type":"assistant"}`+"\n"+`{"type":"us`)
+
+		out, err := run("doctor", "--transcripts")
+		Expect(err).To(MatchError("found problems in 1 session(s)"))
+		Expect(out).To(ContainSubstring("✗ broken: truncated last line"))
+	})
+
+	It("shows transcript health in list", func() {
+		withTranscript("broken", `{"type":"assistant"}`+"\n"+`{"type":"us`)
+
+		out, err := run("list")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(MatchRegexp(`healthy.*│ ok`))
+		Expect(out).To(MatchRegexp(`broken.*⚠ truncated last line`))
+	})
+
+	It("reports hooks whose binary is missing", func() {
+		settingsPath := filepath.Join(tempDir, "home", ".claude", "settings.json")
+		Expect(os.MkdirAll(filepath.Dir(settingsPath), 0o755)).To(Succeed())
+		Expect(os.WriteFile(settingsPath, []byte(`{"hooks": {"SessionStart": [{"hooks": [
+			{"type": "command", "command": "/gone/clotilde hook sessionstart"}
+		]}]}}`), 0o644)).To(Succeed())
+
+		out, err := run("doctor", "--hooks")
+		Expect(err).To(MatchError("found 1 broken hook(s)"))
+		Expect(out).To(ContainSubstring("✗ user SessionStart: /gone/clotilde hook sessionstart (binary not found)"))
+		Expect(out).NotTo(ContainSubstring("Transcripts:"))
+	})
+
+	It("runs every check without flags", func() {
+		out, err := run("doctor")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(ContainSubstring("no clotilde hooks installed"))
+		Expect(out).To(ContainSubstring("✓ healthy"))
+	})
+})
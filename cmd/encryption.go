@@ -0,0 +1,230 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/crypt"
+	"github.com/fgrehm/clotilde/internal/outputstyle"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/ui"
+	"github.com/fgrehm/clotilde/internal/util"
+)
+
+func newEncryptionCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "encryption",
+		Short: "Encrypt session contexts, custom output styles and backups at rest",
+		Long: `Opt-in encryption for session data that may hold sensitive business context.
+
+When enabled for a project, session contexts (in metadata.json), custom output
+styles (the session's system prompt) and 'backup create' archives are
+encrypted with AES-256-GCM. The key is kept in your config directory
+($XDG_CONFIG_HOME/clotilde/key), never in the project. Output styles are
+decrypted only while claude runs, and contexts are decrypted when read.
+
+Claude Code's own transcripts are not encrypted.`,
+	}
+
+	cmd.AddCommand(newEncryptionEnableCmd())
+	cmd.AddCommand(newEncryptionDisableCmd())
+	cmd.AddCommand(newEncryptionStatusCmd())
+
+	return cmd
+}
+
+func newEncryptionEnableCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "enable",
+		Short: "Turn on encryption for the project and encrypt existing sessions",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clotildeRoot, err := config.FindClotildeRoot()
+			if err != nil {
+				return errNoSessions()
+			}
+
+			key, created, err := crypt.EnsureKey()
+			if err != nil {
+				return err
+			}
+			if created {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Created encryption key at %s (back it up: without it encrypted data can't be read)\n", config.GlobalKeyPath())
+			}
+			if err := config.SaveProjectEncryption(clotildeRoot, true); err != nil {
+				return err
+			}
+
+			count, err := rewriteSessionData(clotildeRoot, func(path string) error { return crypt.SealFile(key, path) })
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), ui.Success(fmt.Sprintf("Encryption enabled; encrypted data of %d session(s)", count)))
+			return nil
+		},
+	}
+}
+
+func newEncryptionDisableCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "disable",
+		Short: "Turn off encryption for the project and decrypt existing sessions",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clotildeRoot, err := config.FindClotildeRoot()
+			if err != nil {
+				return errNoSessions()
+			}
+			if _, err := crypt.LoadKey(); err != nil {
+				return err
+			}
+			if err := config.SaveProjectEncryption(clotildeRoot, false); err != nil {
+				return err
+			}
+
+			count, err := rewriteSessionData(clotildeRoot, crypt.UnsealFile)
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), ui.Success(fmt.Sprintf("Encryption disabled; decrypted data of %d session(s)", count)))
+			return nil
+		},
+	}
+}
+
+func newEncryptionStatusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:         "status",
+		Short:       "Show whether encryption is enabled and what is encrypted",
+		Args:        cobra.NoArgs,
+		Annotations: readOnly(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clotildeRoot, err := findClotildeRoot()
+			if err != nil {
+				if projectRootOverride != "" {
+					return err
+				}
+				return errNoSessions()
+			}
+
+			enabled, err := config.EncryptionEnabled(clotildeRoot)
+			if err != nil {
+				return err
+			}
+			keyStatus := "present"
+			if _, err := crypt.LoadKey(); errors.Is(err, crypt.ErrNoKey) {
+				keyStatus = "missing"
+			} else if err != nil {
+				keyStatus = err.Error()
+			}
+
+			sessions, err := session.NewFileStore(clotildeRoot).List()
+			if err != nil {
+				return fmt.Errorf("failed to list sessions: %w", err)
+			}
+			var contexts, sealedContexts, styles, sealedStyles int
+			for _, sess := range sessions {
+				var metadata session.Metadata
+				if err := util.ReadJSON(filepath.Join(config.GetSessionDir(clotildeRoot, sess.Name), "metadata.json"), &metadata); err == nil && metadata.Context != "" {
+					contexts++
+					if crypt.IsSealedString(metadata.Context) {
+						sealedContexts++
+					}
+				}
+				if data, err := os.ReadFile(outputstyle.GetCustomStylePath(clotildeRoot, sess.Name)); err == nil {
+					styles++
+					if crypt.IsSealed(data) {
+						sealedStyles++
+					}
+				}
+			}
+
+			out := cmd.OutOrStdout()
+			_, _ = fmt.Fprintf(out, "Enabled: %t\n", enabled)
+			_, _ = fmt.Fprintf(out, "Key: %s (%s)\n", config.GlobalKeyPath(), keyStatus)
+			_, _ = fmt.Fprintf(out, "Encrypted contexts: %d of %d\n", sealedContexts, contexts)
+			_, _ = fmt.Fprintf(out, "Encrypted output styles: %d of %d\n", sealedStyles, styles)
+			if !enabled {
+				return nil
+			}
+			if leftovers := plainOutputStyles(clotildeRoot, sessions); len(leftovers) > 0 {
+				_, _ = fmt.Fprintln(out, ui.Warning(fmt.Sprintf("Output styles left decrypted by an interrupted run: %s; run 'clotilde maintain' to encrypt them", strings.Join(leftovers, ", "))))
+			} else if sealedContexts < contexts || sealedStyles < styles {
+				_, _ = fmt.Fprintln(out, ui.Warning("Some data is still in plain text; run 'clotilde encryption enable' to encrypt it"))
+			}
+			return nil
+		},
+	}
+}
+
+// rewriteSessionData rewrites every session's metadata, so contexts follow the
+// project's current encryption setting, and applies convertStyle to custom
+// output style files. It returns the number of sessions rewritten.
+func rewriteSessionData(clotildeRoot string, convertStyle func(path string) error) (int, error) {
+	store := session.NewFileStore(clotildeRoot)
+	sessions, err := store.List()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	for _, sess := range sessions {
+		if err := store.Update(sess); err != nil {
+			return 0, fmt.Errorf("failed to rewrite session '%s': %w", sess.Name, err)
+		}
+		stylePath := outputstyle.GetCustomStylePath(clotildeRoot, sess.Name)
+		if err := convertStyle(stylePath); err != nil && !os.IsNotExist(err) {
+			return 0, fmt.Errorf("failed to rewrite output style of '%s': %w", sess.Name, err)
+		}
+	}
+	return len(sessions), nil
+}
+
+// plainOutputStyles returns the sessions not running whose custom output style
+// is in plain text. With encryption enabled, these were decrypted for a run
+// that was killed before it could encrypt them again.
+func plainOutputStyles(clotildeRoot string, sessions []*session.Session) []string {
+	var names []string
+	for _, sess := range sessions {
+		if sess.Status() == session.StatusActive {
+			continue
+		}
+		data, err := os.ReadFile(outputstyle.GetCustomStylePath(clotildeRoot, sess.Name))
+		if err == nil && !crypt.IsSealed(data) {
+			names = append(names, sess.Name)
+		}
+	}
+	return names
+}
+
+// resealOutputStyles encrypts the output styles that plainOutputStyles finds
+// when encryption is enabled, summarizing which.
+func resealOutputStyles(clotildeRoot string, store session.Store) (string, error) {
+	key, err := crypt.ProjectKey(clotildeRoot)
+	if err != nil || key == nil {
+		return "", err
+	}
+	sessions, err := store.List()
+	if err != nil {
+		return "", fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	var sealed []string
+	var failures []error
+	for _, name := range plainOutputStyles(clotildeRoot, sessions) {
+		if err := crypt.SealFile(key, outputstyle.GetCustomStylePath(clotildeRoot, name)); err != nil {
+			failures = append(failures, fmt.Errorf("failed to encrypt the output style of '%s': %w", name, err))
+			continue
+		}
+		sealed = append(sealed, name)
+	}
+	var summary string
+	if len(sealed) > 0 {
+		summary = fmt.Sprintf("Encrypted the output style left decrypted by an interrupted run of: %s", strings.Join(sealed, ", "))
+	}
+	return summary, errors.Join(failures...)
+}
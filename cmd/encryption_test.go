@@ -0,0 +1,113 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/crypt"
+	"github.com/fgrehm/clotilde/internal/outputstyle"
+	"github.com/fgrehm/clotilde/internal/session"
+)
+
+var _ = Describe("encryption command", func() {
+	var (
+		clotildeRoot string
+		originalWd   string
+		store        session.Store
+	)
+
+	BeforeEach(func() {
+		tempDir := GinkgoT().TempDir()
+		GinkgoT().Setenv("XDG_CONFIG_HOME", filepath.Join(tempDir, "xdg"))
+
+		var err error
+		originalWd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.EnsureClotildeStructure(tempDir)).To(Succeed())
+		Expect(os.Chdir(tempDir)).To(Succeed())
+		clotildeRoot = filepath.Join(tempDir, config.ClotildeDir)
+
+		store = session.NewFileStore(clotildeRoot)
+		sess := session.NewSession("merger", "uuid-1")
+		sess.Metadata.Context = "acquisition of ACME"
+		Expect(store.Create(sess)).To(Succeed())
+		Expect(outputstyle.CreateCustomStyleFile(clotildeRoot, "merger", "Never mention ACME")).To(Succeed())
+	})
+
+	AfterEach(func() {
+		_ = os.Chdir(originalWd)
+	})
+
+	run := runClotilde
+
+	onDisk := func(path string) string {
+		data, err := os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		return string(data)
+	}
+
+	metadataPath := func() string {
+		return filepath.Join(config.GetSessionDir(clotildeRoot, "merger"), "metadata.json")
+	}
+
+	It("encrypts existing sessions and decrypts them again", func() {
+		out, err := run("encryption", "enable")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(ContainSubstring("Created encryption key"))
+		Expect(config.GlobalKeyPath()).To(BeAnExistingFile())
+		Expect(onDisk(metadataPath())).NotTo(ContainSubstring("ACME"))
+		Expect(crypt.IsSealed([]byte(onDisk(outputstyle.GetCustomStylePath(clotildeRoot, "merger"))))).To(BeTrue())
+
+		sess, err := session.NewFileStore(clotildeRoot).Get("merger")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sess.Metadata.Context).To(Equal("acquisition of ACME"))
+
+		out, err = run("encryption", "status")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(ContainSubstring("Enabled: true"))
+		Expect(out).To(ContainSubstring("Encrypted contexts: 1 of 1"))
+		Expect(out).To(ContainSubstring("Encrypted output styles: 1 of 1"))
+
+		_, err = run("encryption", "disable")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(onDisk(metadataPath())).To(ContainSubstring("acquisition of ACME"))
+		Expect(onDisk(outputstyle.GetCustomStylePath(clotildeRoot, "merger"))).To(ContainSubstring("Never mention ACME"))
+	})
+
+	It("encrypts output styles left decrypted by a killed run again", func() {
+		_, err := run("encryption", "enable")
+		Expect(err).NotTo(HaveOccurred())
+		stylePath := outputstyle.GetCustomStylePath(clotildeRoot, "merger")
+		Expect(crypt.UnsealFile(stylePath)).To(Succeed())
+
+		out, err := run("encryption", "status")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(ContainSubstring("Output styles left decrypted by an interrupted run: merger"))
+		// Housekeeping after the command seals it
+		Expect(crypt.IsSealed([]byte(onDisk(stylePath)))).To(BeTrue())
+	})
+
+	It("leaves the output style of a running session decrypted", func() {
+		_, err := run("encryption", "enable")
+		Expect(err).NotTo(HaveOccurred())
+		stylePath := outputstyle.GetCustomStylePath(clotildeRoot, "merger")
+		Expect(crypt.UnsealFile(stylePath)).To(Succeed())
+		sess, err := store.Get("merger")
+		Expect(err).NotTo(HaveOccurred())
+		sess.Metadata.Status = session.StatusActive
+		Expect(store.Update(sess)).To(Succeed())
+
+		_, err = run("list")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(onDisk(stylePath)).To(ContainSubstring("Never mention ACME"))
+	})
+
+	It("needs the key to disable encryption", func() {
+		_, err := run("encryption", "disable")
+		Expect(err).To(MatchError(crypt.ErrNoKey))
+	})
+})
@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/errs"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/ui"
+)
+
+func newEnvCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "env",
+		Short: "Manage environment variables set when a session launches",
+		Long: `Manage a session's env file (KEY=VALUE lines in the session folder). Its
+variables are set in Claude Code's environment on every start, resume and
+fork of the session, so secrets or config needed by MCP servers or project
+scripts travel with the session. Forks get a copy of their parent's env file.
+
+The file is readable only by you and can also be edited by hand: blank lines
+and # comments are skipped, and values may be quoted.`,
+	}
+
+	cmd.AddCommand(newEnvListCmd())
+	cmd.AddCommand(newEnvSetCmd())
+	cmd.AddCommand(newEnvUnsetCmd())
+
+	return cmd
+}
+
+func newEnvListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:               "list <name>",
+		Aliases:           []string{"ls"},
+		Short:             "Print a session's environment variables",
+		Example:           `  clotilde env list auth-feature`,
+		Annotations:       readOnly(),
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: firstArgSessionCompletion,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clotildeRoot, err := findClotildeRoot()
+			if err != nil {
+				if projectRootOverride != "" {
+					return err
+				}
+				return errNoSessions()
+			}
+
+			env, err := loadSessionEnv(session.NewFileStore(clotildeRoot), args[0])
+			if err != nil {
+				return err
+			}
+			_, _ = cmd.OutOrStdout().Write(session.FormatEnv(env))
+			return nil
+		},
+	}
+}
+
+func newEnvSetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "set <name> KEY=VALUE...",
+		Short: "Set environment variables for a session",
+		Example: `  clotilde env set auth-feature GITHUB_TOKEN=ghp_xxx
+  clotilde env set auth-feature API_URL=http://localhost:3000 DEBUG=1`,
+		Args:              cobra.MinimumNArgs(2),
+		ValidArgsFunction: firstArgSessionCompletion,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Validate all assignments before touching the file
+			assignments := make(map[string]string)
+			for _, arg := range args[1:] {
+				key, value, ok := strings.Cut(arg, "=")
+				if !ok {
+					return fmt.Errorf("expected KEY=VALUE, got '%s'", arg)
+				}
+				if err := session.ValidateEnvKey(key); err != nil {
+					return err
+				}
+				assignments[key] = value
+			}
+
+			clotildeRoot, err := config.FindClotildeRoot()
+			if err != nil {
+				return errNoSessions()
+			}
+			store := session.NewFileStore(clotildeRoot)
+			env, err := loadSessionEnv(store, args[0])
+			if err != nil {
+				return err
+			}
+
+			maps.Copy(env, assignments)
+			if err := store.SaveEnv(args[0], env); err != nil {
+				return fmt.Errorf("failed to save env: %w", err)
+			}
+
+			keys := slices.Sorted(maps.Keys(assignments))
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), ui.Success(fmt.Sprintf("Set %s for session '%s'", strings.Join(keys, ", "), args[0])))
+			return nil
+		},
+	}
+}
+
+func newEnvUnsetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:               "unset <name> KEY...",
+		Short:             "Remove environment variables from a session",
+		Example:           `  clotilde env unset auth-feature GITHUB_TOKEN`,
+		Args:              cobra.MinimumNArgs(2),
+		ValidArgsFunction: envKeyCompletion,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clotildeRoot, err := config.FindClotildeRoot()
+			if err != nil {
+				return errNoSessions()
+			}
+			store := session.NewFileStore(clotildeRoot)
+			env, err := loadSessionEnv(store, args[0])
+			if err != nil {
+				return err
+			}
+
+			for _, key := range args[1:] {
+				if _, ok := env[key]; !ok {
+					return errs.NotFound("variable '%s' is not set for session '%s'", key, args[0])
+				}
+				delete(env, key)
+			}
+			if err := store.SaveEnv(args[0], env); err != nil {
+				return fmt.Errorf("failed to save env: %w", err)
+			}
+
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), ui.Success(fmt.Sprintf("Unset %s for session '%s'", strings.Join(args[1:], ", "), args[0])))
+			return nil
+		},
+	}
+}
+
+// loadSessionEnv loads the env file of an existing session.
+func loadSessionEnv(store session.Store, name string) (map[string]string, error) {
+	if !store.Exists(name) {
+		return nil, errs.NotFound("session '%s' not found", name)
+	}
+	return store.LoadEnv(name)
+}
+
+// firstArgSessionCompletion completes session names for the first argument only.
+func firstArgSessionCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return completeSessionNames(args, toComplete, sessionCompletionOptions{firstArgOnly: true})
+}
+
+// envKeyCompletion completes the session name, then the variables it has set.
+func envKeyCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return sessionNameCompletion(cmd, args, toComplete)
+	}
+	clotildeRoot, err := findClotildeRoot()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	env, err := session.NewFileStore(clotildeRoot).LoadEnv(args[0])
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var keys []string
+	for _, key := range slices.Sorted(maps.Keys(env)) {
+		if !slices.Contains(args[1:], key) {
+			keys = append(keys, key)
+		}
+	}
+	return keys, cobra.ShellCompDirectiveNoFileComp
+}
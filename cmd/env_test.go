@@ -0,0 +1,135 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/errs"
+	"github.com/fgrehm/clotilde/internal/session"
+)
+
+var _ = Describe("Env Command", func() {
+	var (
+		tempDir      string
+		clotildeRoot string
+		originalWd   string
+		store        session.Store
+	)
+
+	BeforeEach(func() {
+		tempDir = GinkgoT().TempDir()
+		GinkgoT().Setenv("HOME", filepath.Join(tempDir, "home"))
+		GinkgoT().Setenv("XDG_CONFIG_HOME", filepath.Join(tempDir, "xdg"))
+		GinkgoT().Setenv("CLAUDE_CONFIG_DIR", "")
+
+		var err error
+		originalWd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Chdir(tempDir)).To(Succeed())
+
+		Expect(config.EnsureClotildeStructure(tempDir)).To(Succeed())
+		clotildeRoot = filepath.Join(tempDir, config.ClotildeDir)
+		store = session.NewFileStore(clotildeRoot)
+		Expect(store.Create(session.NewSession("my-session", "uuid-1"))).To(Succeed())
+	})
+
+	AfterEach(func() {
+		_ = os.Chdir(originalWd)
+	})
+
+	run := func(args ...string) (string, error) {
+		return runClotilde(append([]string{"env"}, args...)...)
+	}
+
+	It("sets, lists and unsets variables", func() {
+		_, err := run("set", "my-session", "API_URL=http://localhost:3000", "TOKEN=a=b")
+		Expect(err).NotTo(HaveOccurred())
+
+		out, err := run("list", "my-session")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(Equal("API_URL=http://localhost:3000\nTOKEN=a=b\n"))
+
+		_, err = run("unset", "my-session", "TOKEN")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(store.LoadEnv("my-session")).To(Equal(map[string]string{"API_URL": "http://localhost:3000"}))
+	})
+
+	It("keeps quotes and surrounding spaces in values", func() {
+		_, err := run("set", "my-session", `FOO="x"`, "BAR= padded ")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(store.LoadEnv("my-session")).To(Equal(map[string]string{"FOO": `"x"`, "BAR": " padded "}))
+		out, err := run("list", "my-session")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(Equal("BAR=' padded '\nFOO='\"x\"'\n"))
+	})
+
+	It("keeps the env file private to the user", func() {
+		_, err := run("set", "my-session", "TOKEN=secret")
+		Expect(err).NotTo(HaveOccurred())
+
+		info, err := os.Stat(filepath.Join(config.GetSessionDir(clotildeRoot, "my-session"), session.EnvFile))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0o600)))
+	})
+
+	It("removes the env file when the last variable is unset", func() {
+		_, err := run("set", "my-session", "TOKEN=secret")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = run("unset", "my-session", "TOKEN")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(filepath.Join(config.GetSessionDir(clotildeRoot, "my-session"), session.EnvFile)).NotTo(BeAnExistingFile())
+	})
+
+	It("rejects invalid assignments without changing the file", func() {
+		_, err := run("set", "my-session", "GOOD=1", "1BAD=2")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("invalid environment variable name"))
+
+		_, err = run("set", "my-session", "NOVALUE")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("expected KEY=VALUE"))
+
+		Expect(store.LoadEnv("my-session")).To(BeEmpty())
+	})
+
+	It("errors for unknown sessions and variables", func() {
+		_, err := run("list", "missing")
+		Expect(errs.ExitCode(err)).To(Equal(errs.ExitNotFound))
+
+		_, err = run("unset", "my-session", "NOPE")
+		Expect(errs.ExitCode(err)).To(Equal(errs.ExitNotFound))
+	})
+
+	It("passes the variables to claude, with clotilde's own taking precedence", func() {
+		fakeClaudeDir := filepath.Join(tempDir, "bin")
+		Expect(os.Mkdir(fakeClaudeDir, 0o755)).To(Succeed())
+		envDump := filepath.Join(tempDir, "claude-env.txt")
+		script := "#!/bin/bash\necho \"$API_URL $CLOTILDE_SESSION_NAME\" > " + envDump + "\n"
+		Expect(os.WriteFile(filepath.Join(fakeClaudeDir, "claude"), []byte(script), 0o755)).To(Succeed())
+
+		Expect(store.SaveEnv("my-session", map[string]string{
+			"API_URL":               "http://localhost:3000",
+			"CLOTILDE_SESSION_NAME": "other",
+		})).To(Succeed())
+
+		_, err := runClotilde("--claude-bin", filepath.Join(fakeClaudeDir, "claude"), "resume", "my-session")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(os.ReadFile(envDump)).To(Equal([]byte("http://localhost:3000 my-session\n")))
+	})
+
+	It("copies the parent's env file to forks", func() {
+		Expect(store.SaveEnv("my-session", map[string]string{"TOKEN": "secret"})).To(Succeed())
+
+		_, err := runClotilde("fork", "my-session", "my-fork", "--no-launch")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(store.LoadEnv("my-fork")).To(Equal(map[string]string{"TOKEN": "secret"}))
+	})
+})
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fgrehm/clotilde/internal/events"
+)
+
+// eventsPollInterval is how often 'events --follow' checks the log for new lines.
+const eventsPollInterval = 250 * time.Millisecond
+
+func newEventsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "events",
+		Short: "Show the project's event log as JSON lines",
+		Long: `Print clotilde's event log: one JSON object per line, for editor extensions,
+status bars and scripts that want to reflect clotilde's state without polling.
+
+Each event has a "time" and a "type":
+  session.created   a session was started (also incognito and restored ones)
+  session.forked    a fork was created ("parent" names the session forked from)
+  session.resumed   a session was resumed
+  session.deleted   a session was deleted (including by prune)
+  session.status    a session's lifecycle status changed ("status", "from")
+  session.settings  a session's settings.json changed
+  hook.fired        Claude Code ran a clotilde hook ("hook" and "source" say which)
+
+with "session" and "sessionId" when known. The log lives in
+.claude/clotilde/sessions/.events.jsonl and is rotated at 1 MB.`,
+		Example: `  clotilde events
+  clotilde events --follow
+  clotilde events -n 0 --follow | jq -r .type`,
+		Annotations: readOnly(),
+		Args:        cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			lines, _ := cmd.Flags().GetInt("lines")
+			follow, _ := cmd.Flags().GetBool("follow")
+			if lines < 0 {
+				return fmt.Errorf("--lines must not be negative")
+			}
+
+			clotildeRoot, err := findClotildeRoot()
+			if err != nil {
+				if projectRootOverride != "" {
+					return err
+				}
+				return errNoSessions()
+			}
+
+			path := events.LogPath(clotildeRoot)
+			var log strings.Builder
+			var offset int64
+			for _, p := range []string{path + ".1", path} {
+				data, err := os.ReadFile(p)
+				if errors.Is(err, fs.ErrNotExist) {
+					continue
+				}
+				if err != nil {
+					return fmt.Errorf("failed to read event log: %w", err)
+				}
+				log.Write(data)
+				if p == path {
+					offset = int64(len(data))
+				}
+			}
+
+			out := cmd.OutOrStdout()
+			switch {
+			case log.Len() == 0:
+			case lines == 0:
+				_, _ = fmt.Fprint(out, log.String())
+			default:
+				_, _ = fmt.Fprintln(out, lastLines(log.String(), lines))
+			}
+
+			if !follow {
+				return nil
+			}
+			return events.Follow(cmd.Context(), path, offset, out, eventsPollInterval)
+		},
+	}
+	cmd.Flags().IntP("lines", "n", 10, "Number of past events to show (0 for all)")
+	cmd.Flags().BoolP("follow", "f", false, "Keep printing new events as they happen")
+	return cmd
+}
@@ -0,0 +1,134 @@
+package cmd_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+
+	"github.com/fgrehm/clotilde/cmd"
+	"github.com/fgrehm/clotilde/internal/claude"
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/events"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/testutil"
+)
+
+var _ = Describe("Events Command", func() {
+	var (
+		tempDir      string
+		clotildeRoot string
+		originalWd   string
+		claudeBin    string
+	)
+
+	BeforeEach(func() {
+		tempDir = GinkgoT().TempDir()
+		GinkgoT().Setenv("XDG_CONFIG_HOME", filepath.Join(tempDir, "xdg"))
+
+		var err error
+		originalWd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Chdir(tempDir)).To(Succeed())
+
+		binDir := filepath.Join(tempDir, "bin")
+		Expect(os.Mkdir(binDir, 0o755)).To(Succeed())
+		claudeBin, _, err = testutil.CreateFakeClaude(binDir)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(config.EnsureClotildeStructure(tempDir)).To(Succeed())
+		clotildeRoot = filepath.Join(tempDir, config.ClotildeDir)
+
+		claude.SessionUsedFunc = func(_ string, _ *session.Session) bool { return true }
+	})
+
+	AfterEach(func() {
+		_ = os.Chdir(originalWd)
+	})
+
+	run := func(args ...string) (string, error) {
+		return runClotilde(append([]string{"--claude-bin", claudeBin}, args...)...)
+	}
+
+	eventTypes := func(out string) []string {
+		var types []string
+		for line := range strings.SplitSeq(strings.TrimSpace(out), "\n") {
+			var e events.Event
+			Expect(json.Unmarshal([]byte(line), &e)).To(Succeed())
+			types = append(types, e.Type+" "+e.Session)
+		}
+		return types
+	}
+
+	It("records session lifecycle events", func() {
+		for _, args := range [][]string{
+			{"start", "alpha"},
+			{"resume", "alpha"},
+			{"fork", "alpha", "beta"},
+			{"delete", "beta", "-f"},
+		} {
+			_, err := run(args...)
+			Expect(err).NotTo(HaveOccurred(), strings.Join(args, " "))
+		}
+
+		out, err := run("events", "-n", "0")
+		Expect(err).NotTo(HaveOccurred())
+		// Each run makes the session active, then idle again
+		Expect(eventTypes(out)).To(Equal([]string{
+			"session.created alpha",
+			"session.status alpha",
+			"session.status alpha",
+			"session.resumed alpha",
+			"session.status alpha",
+			"session.status alpha",
+			"session.forked beta",
+			"session.status beta",
+			"session.status beta",
+			"session.deleted beta",
+		}))
+
+		out, err = run("events", "-n", "1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(eventTypes(out)).To(Equal([]string{"session.deleted beta"}))
+	})
+
+	It("prints nothing when there are no events yet", func() {
+		out, err := run("events")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(BeEmpty())
+	})
+
+	It("follows new events until cancelled", func() {
+		events.Record(clotildeRoot, events.Event{Type: events.SessionCreated, Session: "old"})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		out := gbytes.NewBuffer()
+		done := make(chan error)
+		go func() {
+			rootCmd := cmd.NewRootCmd()
+			rootCmd.SetOut(out)
+			rootCmd.SetErr(io.Discard)
+			rootCmd.SetArgs([]string{"events", "--follow"})
+			done <- rootCmd.ExecuteContext(ctx)
+		}()
+
+		Eventually(out).Should(gbytes.Say(`"session":"old"`))
+		events.Record(clotildeRoot, events.Event{Type: events.SessionDeleted, Session: "new"})
+		Eventually(out, "2s").Should(gbytes.Say(`"type":"session.deleted","session":"new"`))
+
+		cancel()
+		Eventually(done, "2s").Should(Receive(BeNil()))
+	})
+
+	It("fails outside a project", func() {
+		Expect(os.Chdir(GinkgoT().TempDir())).To(Succeed())
+		_, err := run("events")
+		Expect(err).To(MatchError(ContainSubstring("no sessions found")))
+	})
+})
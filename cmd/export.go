@@ -3,35 +3,47 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 
+	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 
+	"github.com/fgrehm/clotilde/internal/claude"
 	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/errs"
 	"github.com/fgrehm/clotilde/internal/export"
 	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/ui"
 	"github.com/fgrehm/clotilde/internal/util"
 )
 
 func newExportCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:               "export <name>",
-		Short:             "Export session transcript as self-contained HTML",
-		Long:              `Render a Claude Code session transcript (JSONL) into a single, self-contained HTML file.`,
+		Use:   "export <name>",
+		Short: "Export session transcript as self-contained HTML",
+		Long: `Render a Claude Code session transcript (JSONL) into a single, self-contained HTML file.
+
+Matches of the config's "redact" patterns are replaced with [REDACTED].`,
+		Annotations:       readOnly(),
 		Args:              cobra.ExactArgs(1),
 		ValidArgsFunction: sessionNameCompletion,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			name := args[0]
 
-			clotildeRoot, err := config.FindClotildeRoot()
+			clotildeRoot, err := findClotildeRoot()
 			if err != nil {
-				return fmt.Errorf("no sessions found (create one with 'clotilde start <name>')")
+				if projectRootOverride != "" {
+					return err
+				}
+				return errNoSessions()
 			}
 
 			store := session.NewFileStore(clotildeRoot)
 			sess, err := store.Get(name)
 			if err != nil {
-				return fmt.Errorf("session '%s' not found", name)
+				return errs.NotFound("session '%s' not found", name)
 			}
 
 			// Collect entries from all transcripts (previous + current)
@@ -40,35 +52,62 @@ func newExportCmd() *cobra.Command {
 				return fmt.Errorf("could not determine home directory: %w", err)
 			}
 
-			paths := allTranscriptPaths(sess, clotildeRoot, homeDir)
+			// Progress goes to the terminal only when the HTML isn't written to stdout
+			toStdout, _ := cmd.Flags().GetBool("stdout")
+			progressOut := cmd.OutOrStdout()
+			if toStdout {
+				progressOut = io.Discard
+			}
+
+			paths := claude.SessionTranscriptPaths(homeDir, clotildeRoot, sess)
 			var allEntries []json.RawMessage
 			var readable int
-			for _, path := range paths {
-				f, err := os.Open(path)
-				if err != nil {
-					if os.IsNotExist(err) {
-						continue // previous transcript deleted or not yet written
+			interactive := !toStdout && isatty.IsTerminal(os.Stdout.Fd())
+			err = ui.RunWithProgress(progressOut, "Reading transcripts", interactive, func(progress ui.ProgressReporter) error {
+				for _, path := range paths {
+					f, err := os.Open(path)
+					if err != nil {
+						if os.IsNotExist(err) {
+							continue // previous transcript deleted or not yet written
+						}
+						return fmt.Errorf("opening transcript %s: %w", path, err)
 					}
-					return fmt.Errorf("opening transcript %s: %w", path, err)
-				}
-				entries, err := export.FilterTranscript(f)
-				_ = f.Close()
-				if err != nil {
-					return fmt.Errorf("reading transcript %s: %w", path, err)
+					item := filepath.Base(path)
+					progress.Start(item)
+					entries, err := export.FilterTranscript(f)
+					_ = f.Close()
+					if err != nil {
+						progress.Fail(item, err)
+						return fmt.Errorf("reading transcript %s: %w", path, err)
+					}
+					progress.Done(item, fmt.Sprintf("%d entries", len(entries)))
+					readable++
+					allEntries = append(allEntries, entries...)
 				}
-				readable++
-				allEntries = append(allEntries, entries...)
+				return nil
+			})
+			if err != nil {
+				return err
 			}
 			if readable == 0 {
 				return fmt.Errorf("no transcript found for session '%s'", name)
 			}
 
+			redactor, err := config.LoadRedactor(clotildeRoot)
+			if err != nil {
+				return err
+			}
+			if redactor != nil {
+				if allEntries, err = export.RedactEntries(allEntries, redactor.Redact); err != nil {
+					return err
+				}
+			}
+
 			html, err := export.BuildHTML(name, allEntries)
 			if err != nil {
 				return fmt.Errorf("building HTML: %w", err)
 			}
 
-			toStdout, _ := cmd.Flags().GetBool("stdout")
 			if toStdout {
 				_, err = fmt.Fprint(cmd.OutOrStdout(), html)
 				return err
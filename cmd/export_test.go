@@ -131,7 +131,7 @@ var _ = Describe("Export Command", func() {
 		Expect(err.Error()).To(ContainSubstring("not found"))
 	})
 
-	It("includes entries from previous transcripts when previousSessionIds is set", func() {
+	It("includes entries from previous transcripts when previousSessions is set", func() {
 		// Point HOME at the temp dir so transcript paths stay hermetic.
 		GinkgoT().Setenv("HOME", tempDir)
 		homeDir := tempDir
@@ -161,7 +161,7 @@ var _ = Describe("Export Command", func() {
 
 		sess := session.NewSession("multi-transcript-export", currentID)
 		sess.Metadata.TranscriptPath = currentPath
-		sess.Metadata.PreviousSessionIDs = []string{prevID}
+		sess.Metadata.PreviousSessions = []session.PreviousSession{{SessionID: prevID}}
 		err = store.Create(sess)
 		Expect(err).NotTo(HaveOccurred())
 
@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fgrehm/clotilde/internal/claude"
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/errs"
+	"github.com/fgrehm/clotilde/internal/export"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/util"
+)
+
+func newExportTranscriptCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export-transcript <name>",
+		Short: "Export a session's raw transcript (JSONL), optionally anonymized",
+		Long: `Write every entry of a session's Claude Code transcripts (including those from
+before a /clear) as JSONL, for attaching conversation logs to Claude Code or
+clotilde bug reports.
+
+Matches of the config's "redact" patterns and of --redact are replaced with
+[REDACTED]. --anonymize also strips what identifies you or your machine, using
+heuristics: API keys and tokens in well-known formats become [SECRET], email
+addresses [EMAIL], the project directory [PROJECT], your home directory ~ and
+other users' home directories /home/user. Heuristics miss things; read the
+file before sharing it, and add --redact patterns for what's left.`,
+		Example: `  clotilde export-transcript auth-bug --anonymize
+  clotilde export-transcript auth-bug --anonymize --redact 'acme-[a-z]+' --stdout > report.jsonl`,
+		Annotations:       readOnly(),
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: sessionNameCompletion,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+
+			clotildeRoot, err := findClotildeRoot()
+			if err != nil {
+				if projectRootOverride != "" {
+					return err
+				}
+				return errNoSessions()
+			}
+
+			store := session.NewFileStore(clotildeRoot)
+			sess, err := store.Get(name)
+			if err != nil {
+				return errs.NotFound("session '%s' not found", name)
+			}
+
+			homeDir, err := util.HomeDir()
+			if err != nil {
+				return fmt.Errorf("could not determine home directory: %w", err)
+			}
+
+			entries, err := readSessionTranscripts(homeDir, clotildeRoot, sess)
+			if err != nil {
+				return err
+			}
+
+			anonymize, _ := cmd.Flags().GetBool("anonymize")
+			patterns, _ := cmd.Flags().GetStringArray("redact")
+			redact, err := transcriptRedaction(clotildeRoot, homeDir, patterns, anonymize)
+			if err != nil {
+				return err
+			}
+			if redact != nil {
+				if entries, err = export.RedactEntries(entries, redact); err != nil {
+					return err
+				}
+			}
+
+			var out bytes.Buffer
+			for _, entry := range entries {
+				out.Write(entry)
+				out.WriteByte('\n')
+			}
+
+			if toStdout, _ := cmd.Flags().GetBool("stdout"); toStdout {
+				_, err = cmd.OutOrStdout().Write(out.Bytes())
+				return err
+			}
+
+			outputPath, _ := cmd.Flags().GetString("output")
+			if outputPath == "" {
+				outputPath = name + ".jsonl"
+			}
+			if err := os.WriteFile(outputPath, out.Bytes(), 0o644); err != nil {
+				return fmt.Errorf("writing output file: %w", err)
+			}
+
+			note := ""
+			if anonymize {
+				note = ", anonymized"
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Wrote %s (%d entries%s)\n", outputPath, len(entries), note)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP("output", "o", "", "Output file path (default: ./<name>.jsonl)")
+	cmd.Flags().Bool("stdout", false, "Write to stdout instead of file")
+	cmd.Flags().Bool("anonymize", false, "Strip API keys, emails and file paths")
+	cmd.Flags().StringArray("redact", nil, "Also replace matches of this regular expression (repeatable)")
+
+	return cmd
+}
+
+// readSessionTranscripts returns every entry of the session's transcripts,
+// oldest first. Transcripts that no longer exist are skipped.
+func readSessionTranscripts(homeDir, clotildeRoot string, sess *session.Session) ([]json.RawMessage, error) {
+	var entries []json.RawMessage
+	readable := 0
+	for _, path := range claude.SessionTranscriptPaths(homeDir, clotildeRoot, sess) {
+		f, err := os.Open(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("opening transcript %s: %w", path, err)
+		}
+		read, err := export.ReadTranscript(f)
+		_ = f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reading transcript %s: %w", path, err)
+		}
+		readable++
+		entries = append(entries, read...)
+	}
+	if readable == 0 {
+		return nil, fmt.Errorf("no transcript found for session '%s'", sess.Name)
+	}
+	return entries, nil
+}
+
+// transcriptRedaction combines the config's redact patterns, the given
+// patterns and, with anonymize, the anonymizer into one function, or returns
+// nil when there's nothing to replace. Patterns run first, on the original
+// text.
+func transcriptRedaction(clotildeRoot, homeDir string, patterns []string, anonymize bool) (func(string) string, error) {
+	configRedactor, err := config.LoadRedactor(clotildeRoot)
+	if err != nil {
+		return nil, err
+	}
+	flagRedactor, err := config.NewRedactor(patterns)
+	if err != nil {
+		return nil, err
+	}
+	if configRedactor == nil && flagRedactor == nil && !anonymize {
+		return nil, nil
+	}
+
+	var anonymizer *export.Anonymizer
+	if anonymize {
+		anonymizer = export.NewAnonymizer(config.ProjectRootOf(clotildeRoot), homeDir)
+	}
+	return func(text string) string {
+		text = flagRedactor.Redact(configRedactor.Redact(text))
+		if anonymizer != nil {
+			text = anonymizer.Anonymize(text)
+		}
+		return text
+	}, nil
+}
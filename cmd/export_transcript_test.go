@@ -0,0 +1,88 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/session"
+)
+
+var _ = Describe("Export-transcript Command", func() {
+	var (
+		tempDir    string
+		originalWd string
+		store      session.Store
+	)
+
+	BeforeEach(func() {
+		tempDir = GinkgoT().TempDir()
+		GinkgoT().Setenv("HOME", filepath.Join(tempDir, "home"))
+		GinkgoT().Setenv("XDG_CONFIG_HOME", filepath.Join(tempDir, "config"))
+
+		var err error
+		originalWd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Chdir(tempDir)).To(Succeed())
+
+		Expect(config.EnsureClotildeStructure(tempDir)).To(Succeed())
+		store = session.NewFileStore(filepath.Join(tempDir, config.ClotildeDir))
+
+		transcriptPath := filepath.Join(tempDir, "uuid-bug.jsonl")
+		transcript := `{"type":"summary","summary":"Bug hunt"}
+{"type":"user","cwd":"` + tempDir + `","message":{"content":"mail jane@example.com, key sk-ant-REDACTED, codename falcon"}}
+{"type":"assistant","message":{"content":[{"type":"tool_use","input":{"file_path":"` + tempDir + `/main.go"}}]}}
+`
+		Expect(os.WriteFile(transcriptPath, []byte(transcript), 0o644)).To(Succeed())
+		sess := session.NewSession("bug", "uuid-bug")
+		sess.Metadata.TranscriptPath = transcriptPath
+		Expect(store.Create(sess)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		_ = os.Chdir(originalWd)
+	})
+
+	exportTranscript := func(args ...string) (string, error) {
+		return runClotilde(append([]string{"export-transcript"}, args...)...)
+	}
+
+	It("writes every entry as JSONL", func() {
+		out, err := exportTranscript("bug")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(Equal("Wrote bug.jsonl (3 entries)\n"))
+
+		data, err := os.ReadFile(filepath.Join(tempDir, "bug.jsonl"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(strings.Count(string(data), "\n")).To(Equal(3))
+		Expect(string(data)).To(ContainSubstring(`"summary":"Bug hunt"`))
+		Expect(string(data)).To(ContainSubstring("jane@example.com"))
+	})
+
+	It("anonymizes and applies --redact patterns", func() {
+		out, err := exportTranscript("bug", "--anonymize", "--redact", "falcon", "--stdout")
+		Expect(err).NotTo(HaveOccurred())
+
+		lines := strings.Split(strings.TrimSpace(out), "\n")
+		Expect(lines).To(HaveLen(3))
+		Expect(lines[1]).To(MatchJSON(`{"type":"user","cwd":"[PROJECT]","message":{"content":"mail [EMAIL], key [SECRET], codename [REDACTED]"}}`))
+		Expect(lines[2]).To(ContainSubstring(`"file_path":"[PROJECT]/main.go"`))
+		Expect(out).NotTo(ContainSubstring(tempDir))
+	})
+
+	It("rejects invalid --redact patterns", func() {
+		_, err := exportTranscript("bug", "--redact", "(", "--stdout")
+		Expect(err).To(MatchError(ContainSubstring("invalid redact pattern")))
+	})
+
+	It("fails without a transcript", func() {
+		Expect(store.Create(session.NewSession("empty", "uuid-empty"))).To(Succeed())
+
+		_, err := exportTranscript("empty")
+		Expect(err).To(MatchError(ContainSubstring("no transcript found for session 'empty'")))
+	})
+})
@@ -1,17 +1,14 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
-	"os"
-	"path/filepath"
-	"strings"
 
 	"github.com/spf13/cobra"
 
+	"github.com/fgrehm/clotilde/internal/app"
 	"github.com/fgrehm/clotilde/internal/claude"
 	"github.com/fgrehm/clotilde/internal/config"
-	"github.com/fgrehm/clotilde/internal/outputstyle"
+	"github.com/fgrehm/clotilde/internal/errs"
 	"github.com/fgrehm/clotilde/internal/session"
 	"github.com/fgrehm/clotilde/internal/ui"
 	"github.com/fgrehm/clotilde/internal/util"
@@ -25,18 +22,42 @@ func newForkCmd() *cobra.Command {
 		Long: `Create a new session that branches from an existing one.
 The fork inherits settings and system prompt from the parent.
 
+The parent's context is inherited when --context isn't given, and the
+SessionStart hook also tells Claude which session the fork came from and the
+parent's current context, labeled as such. --no-parent-context skips both.
+
 If fork-name is not provided for incognito forks, a random name will be generated.
 
+Use --matrix to create one fork per combination of settings values (keys:
+model, effort). Forks are named <fork-name>-<value>..., using the parent
+name when fork-name is omitted. The first fork is launched unless
+--no-launch is given; the others start when resumed.
+
 Pass additional flags to Claude Code after '--':
   clotilde fork my-session experiment -- --debug api,hooks
-  clotilde fork my-session --incognito  # Random name like "happy-fox"`,
+  clotilde fork my-session --incognito  # Random name like "happy-fox"
+  clotilde fork my-session --matrix model=haiku,sonnet,opus --no-launch
+  clotilde fork my-session try --matrix model=sonnet,opus --matrix effort=low,high`,
 		Args:              rangePositionalArgs(1, 2),
-		ValidArgsFunction: sessionNameCompletion,
+		ValidArgsFunction: forkParentCompletion,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			parentName := args[0]
 
 			// Get incognito flag early to determine if we need a name
 			incognito, _ := cmd.Flags().GetBool("incognito")
+			noLaunch, _ := cmd.Flags().GetBool("no-launch")
+			if incognito && noLaunch {
+				return fmt.Errorf("cannot use --no-launch with --incognito (incognito forks are deleted when Claude exits)")
+			}
+
+			matrixSpecs, _ := cmd.Flags().GetStringArray("matrix")
+			matrix, err := parseForkMatrix(matrixSpecs)
+			if err != nil {
+				return err
+			}
+			if len(matrix) > 0 && incognito {
+				return fmt.Errorf("cannot use --matrix with --incognito")
+			}
 
 			// Find or create clotilde root
 			clotildeRoot, err := config.FindOrCreateClotildeRoot()
@@ -47,8 +68,13 @@ Pass additional flags to Claude Code after '--':
 			store := session.NewFileStore(clotildeRoot)
 
 			var forkName string
-			if len(args) >= 2 {
-				forkName = args[1]
+			if len(args) >= 2 && cmd.Flags().ArgsLenAtDash() != 1 {
+				if forkName, err = sessionNameArg(cmd, clotildeRoot, args[1]); err != nil {
+					return err
+				}
+			} else if len(matrix) > 0 {
+				// Matrix forks are named after the parent by default
+				forkName = parentName
 			} else {
 				// Only allow missing name for incognito forks
 				if !incognito {
@@ -68,178 +94,166 @@ Pass additional flags to Claude Code after '--':
 				forkName = util.GenerateUniqueRandomName(existingNames)
 			}
 
-			// Extract additional args after '--'
-			var additionalArgs []string
-			argsLenAtDash := cmd.Flags().ArgsLenAtDash()
-			if argsLenAtDash > 0 && len(args) > argsLenAtDash {
-				additionalArgs = args[argsLenAtDash:]
+			// Claude flags from --with presets and after '--'
+			additionalArgs, err := claudeArgs(cmd, args)
+			if err != nil {
+				return err
 			}
 
-			// Resolve shorthand flags
-			permMode, err := resolvePermissionMode(cmd)
+			expiresAt, err := expiresAtFlag(cmd)
 			if err != nil {
 				return err
 			}
-			if permMode != "" {
-				additionalArgs = append(additionalArgs, "--permission-mode", permMode)
-			}
 
-			fastEnabled, err := resolveFastMode(cmd)
+			// Resolve flags (model/effort are persisted to settings.json below,
+			// permission mode is passed to the claude CLI)
+			flags, err := flagSettingsLayer(cmd)
 			if err != nil {
 				return err
 			}
 
-			// Resolve model/effort from flags (persisted to settings.json below, not CLI args)
-			var forkModel, forkEffort string
-			if fastEnabled {
-				forkModel = "haiku"
-				forkEffort = "low"
-			} else {
-				if model, _ := cmd.Flags().GetString("model"); model != "" {
-					forkModel = normalizeModel(model)
-				}
-				effort, _ := cmd.Flags().GetString("effort")
-				forkEffort = effort
+			rules, err := session.LoadNameRules(clotildeRoot)
+			if err != nil {
+				return fmt.Errorf("invalid naming config: %w", err)
 			}
 
-			if err := session.ValidateName(forkName); err != nil {
-				return err
+			// Each fork is a name plus the flag layer it is created with
+			variants := []forkVariant{{Name: forkName, Flags: flags}}
+			if len(matrix) > 0 {
+				variants = expandForkMatrix(forkName, flags, matrix)
 			}
 
-			// Check if fork already exists
-			if store.Exists(forkName) {
-				return fmt.Errorf("session '%s' already exists", forkName)
+			// Validate every name up front so a matrix is created all or nothing
+			for _, v := range variants {
+				if err := rules.Validate(v.Name); err != nil {
+					return err
+				}
+				if store.Exists(v.Name) {
+					return errs.AlreadyExists("session '%s' already exists", v.Name)
+				}
 			}
 
 			// Load parent session
 			parentSess, err := store.Get(parentName)
 			if err != nil {
-				return fmt.Errorf("parent session '%s' not found", parentName)
+				return errs.NotFound("parent session '%s' not found", parentName)
 			}
 
-			// Prevent forking FROM incognito sessions
+			// Checked before any matrix fork is created (app.ForkSession refuses too)
 			if parentSess.Metadata.IsIncognito {
 				return fmt.Errorf("cannot fork from incognito session '%s' (it will auto-delete when you exit)", parentName)
 			}
 
-			// Create fork session with a pre-assigned UUID passed via --session-id
-			forkUUID := util.GenerateUUID()
-			var fork *session.Session
-			if incognito {
-				fork = session.NewIncognitoSession(forkName, forkUUID)
-			} else {
-				fork = session.NewSession(forkName, forkUUID)
-			}
-			fork.Metadata.IsForkedSession = true
-			fork.Metadata.ParentSession = parentName
-
-			// Set context: use --context flag if provided, otherwise inherit from parent
 			forkContext, _ := cmd.Flags().GetString("context")
-			if forkContext != "" {
-				fork.Metadata.Context = forkContext
-			} else if parentSess.Metadata.Context != "" {
-				fork.Metadata.Context = parentSess.Metadata.Context
-			}
+			noParentContext, _ := cmd.Flags().GetBool("no-parent-context")
+			opts := app.ForkOptions{Incognito: incognito, Context: forkContext, NoParentContext: noParentContext, ExpiresAt: expiresAt}
 
-			if err := store.Create(fork); err != nil {
-				return fmt.Errorf("failed to create fork: %w", err)
+			if len(matrix) > 0 {
+				return runForkMatrix(cmd, clotildeRoot, store, parentSess, variants, opts, additionalArgs, noLaunch)
 			}
 
-			forkDir := config.GetSessionDir(clotildeRoot, forkName)
-			parentDir := config.GetSessionDir(clotildeRoot, parentName)
-
-			// Copy settings.json and handle custom output style inheritance
-			parentSettingsPath := filepath.Join(parentDir, "settings.json")
-			if util.FileExists(parentSettingsPath) {
-				forkSettingsPath := filepath.Join(forkDir, "settings.json")
-				if err := util.CopyFile(parentSettingsPath, forkSettingsPath); err != nil {
-					return fmt.Errorf("failed to copy settings: %w", err)
-				}
-
-				// Check for custom output style that needs its own copy
-				parentSettingsData, err := os.ReadFile(parentSettingsPath)
-				if err == nil {
-					var parsedSettings session.Settings
-					if err := json.Unmarshal(parentSettingsData, &parsedSettings); err == nil {
-						if parsedSettings.OutputStyle != "" && strings.HasPrefix(parsedSettings.OutputStyle, "clotilde/") {
-							parentStyleName := strings.TrimPrefix(parsedSettings.OutputStyle, "clotilde/")
-							parentStylePath := outputstyle.GetCustomStylePath(clotildeRoot, parentStyleName)
-							if util.FileExists(parentStylePath) {
-								styleContent, err := os.ReadFile(parentStylePath)
-								if err == nil {
-									content := string(styleContent)
-									parts := strings.SplitN(content, "---", 3)
-									if len(parts) == 3 {
-										content = strings.TrimSpace(parts[2])
-									}
-
-									if err := outputstyle.CreateCustomStyleFile(clotildeRoot, forkName, content); err != nil {
-										return fmt.Errorf("failed to copy custom output style: %w", err)
-									}
-
-									// Update the already-copied settings to reference the fork's style
-									parsedSettings.OutputStyle = outputstyle.GetCustomStyleReference(forkName)
-									updatedData, err := json.MarshalIndent(parsedSettings, "", "  ")
-									if err != nil {
-										return fmt.Errorf("failed to marshal fork settings: %w", err)
-									}
-									if err := os.WriteFile(forkSettingsPath, updatedData, 0o644); err != nil {
-										return fmt.Errorf("failed to write fork settings: %w", err)
-									}
-
-									fork.Metadata.HasCustomOutputStyle = true
-									if err := store.Update(fork); err != nil {
-										return fmt.Errorf("failed to update fork metadata: %w", err)
-									}
-								}
-							}
-						}
-					}
-				}
+			// Deferred launches start from the parent once resumed
+			opts.Pending = noLaunch
+			created, err := createFork(clotildeRoot, store, parentSess, forkName, flags, opts)
+			if err != nil {
+				return err
 			}
+			fork, resolved := created.Session, created.Resolved
 
-			// Apply model/effort overrides to fork settings.json (sticky, not CLI args)
-			if forkModel != "" || forkEffort != "" {
-				settings, err := store.LoadSettings(forkName)
-				if err != nil {
-					return fmt.Errorf("failed to load fork settings: %w", err)
-				}
-				if settings == nil {
-					settings = &session.Settings{}
-				}
-				if forkModel != "" {
-					settings.Model = forkModel
-				}
-				if forkEffort != "" {
-					settings.EffortLevel = forkEffort
-				}
-				if err := store.SaveSettings(forkName, settings); err != nil {
-					return fmt.Errorf("failed to save fork settings: %w", err)
-				}
-			}
+			// Only the permission mode is passed per-run; model and effort are now in settings.json
+			perRun := resolvedSettings{PermissionMode: resolved.PermissionMode}
+			additionalArgs = append(additionalArgs, perRun.launchArgs()...)
 
+			// Print output (to stderr with --no-launch, where stdout is just the name)
+			out := cmd.OutOrStdout()
+			if noLaunch {
+				out = cmd.ErrOrStderr()
+			}
 			if incognito {
-				_, _ = fmt.Fprintln(cmd.OutOrStdout(), ui.Info(fmt.Sprintf("👻 Created incognito fork '%s' from '%s'", forkName, parentName)))
-				_, _ = fmt.Fprintln(cmd.OutOrStdout(), ui.Info("👻 This fork will auto-delete when you exit Claude"))
+				_, _ = fmt.Fprintln(out, ui.Info(fmt.Sprintf("👻 Created incognito fork '%s' from '%s'", forkName, parentName)))
+				_, _ = fmt.Fprintln(out, ui.Info("👻 This fork will auto-delete when you exit Claude"))
 			} else {
-				_, _ = fmt.Fprintln(cmd.OutOrStdout(), ui.Success(fmt.Sprintf("Created fork '%s' from '%s'", forkName, parentName)))
+				_, _ = fmt.Fprintln(out, ui.Success(fmt.Sprintf("Created fork '%s' from '%s'", forkName, parentName)))
+				printExpiry(out, fork)
+			}
+			if explain, _ := cmd.Flags().GetBool("explain"); explain {
+				_, _ = fmt.Fprintln(out)
+				printSettingsExplanation(out, resolved)
 			}
-			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "\nStarting Claude Code with fork...")
+			printSessionOverrides(out, resolved, created.Pinned)
 
-			// Build file paths for claude invocation
-			var settingsFile string
-			if util.FileExists(filepath.Join(forkDir, "settings.json")) {
-				settingsFile = filepath.Join(forkDir, "settings.json")
+			if noLaunch {
+				printNoLaunch(cmd, forkName)
+				return nil
 			}
+			_, _ = fmt.Fprintln(out, "\nStarting Claude Code with fork...")
+			warnMissingHooks(cmd, clotildeRoot)
+			warnActiveSessions(cmd, clotildeRoot, forkName)
 
 			// Invoke claude with fork (pass fork session for cleanup handling)
-			return claude.Fork(clotildeRoot, parentSess, forkName, settingsFile, additionalArgs, fork)
+			err = claude.Fork(clotildeRoot, parentSess, forkName, created.SettingsFile, additionalArgs, fork)
+			return handleLaunchFailure(cmd.ErrOrStderr(), clotildeRoot, err)
 		},
 	}
 	cmd.Flags().Bool("incognito", false, "Create fork as incognito session (auto-deletes on exit)")
 	cmd.Flags().String("context", "", "Session context (e.g. \"working on ticket GH-123\")")
+	cmd.Flags().Bool("no-parent-context", false, "Don't inherit or inject the parent session's context")
 	cmd.Flags().String("model", "", "Claude model to use (haiku, sonnet, opus); opus defaults to 1M context")
+	cmd.Flags().StringArray("matrix", nil, "Create one fork per value combination, e.g. model=haiku,sonnet (repeatable; keys: model, effort)")
+	registerNoLaunchFlag(cmd, "Create the fork(s) without starting Claude Code (prints their names)")
 	registerShorthandFlags(cmd)
+	registerWithFlag(cmd)
+	registerExplainFlag(cmd)
+	registerTitleFlag(cmd)
+	registerHookCheckFlag(cmd)
+	registerSlugifyFlag(cmd)
+	registerExpiresFlag(cmd)
 	_ = cmd.RegisterFlagCompletionFunc("model", modelCompletion)
 	return cmd
 }
+
+// createdFork is a fork that exists in the store and is ready to launch.
+type createdFork struct {
+	Session      *session.Session
+	Resolved     resolvedSettings
+	Pinned       settingsLayer
+	SettingsFile string
+}
+
+// createFork creates forkName from parentSess with app.ForkSession and persists
+// model/effort resolved from flags to the fork's settings.json.
+func createFork(clotildeRoot string, store session.Store, parentSess *session.Session, forkName string, flags settingsLayer, opts app.ForkOptions) (*createdFork, error) {
+	fork, err := app.ForkSession(clotildeRoot, store, parentSess, forkName, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Resolve effective settings: flag > parent's session settings > project default
+	resolved, pinned, err := resolveSessionSettings(clotildeRoot, store, forkName, flags)
+	if err != nil {
+		return nil, err
+	}
+
+	// Persist model/effort overrides to fork settings.json (sticky, not CLI args)
+	sticky := func(v resolvedValue) bool { return v.Source == sourceFlag || v.Source == sourceDefault }
+	if sticky(resolved.Model) || sticky(resolved.EffortLevel) {
+		settings, err := store.LoadSettings(forkName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load fork settings: %w", err)
+		}
+		if settings == nil {
+			settings = &session.Settings{}
+		}
+		if sticky(resolved.Model) {
+			settings.Model = resolved.Model.Value
+		}
+		if sticky(resolved.EffortLevel) {
+			settings.EffortLevel = resolved.EffortLevel.Value
+		}
+		if err := store.SaveSettings(forkName, settings); err != nil {
+			return nil, fmt.Errorf("failed to save fork settings: %w", err)
+		}
+	}
+
+	return &createdFork{Session: fork, Resolved: resolved, Pinned: pinned, SettingsFile: app.SettingsFile(clotildeRoot, forkName)}, nil
+}
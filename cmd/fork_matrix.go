@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/fgrehm/clotilde/internal/app"
+	"github.com/fgrehm/clotilde/internal/claude"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/ui"
+)
+
+// forkMatrixKeys are the settings a --matrix axis can vary, in naming order.
+var forkMatrixKeys = []string{"model", "effort"}
+
+// forkMatrixAxis is one --matrix entry, e.g. model=haiku,sonnet.
+type forkMatrixAxis struct {
+	Key    string
+	Values []string
+}
+
+// forkVariant is a fork to create: its name and the flag layer it gets.
+type forkVariant struct {
+	Name  string
+	Flags settingsLayer
+}
+
+// parseForkMatrix parses --matrix values ("key=v1,v2"). Axes are returned in
+// forkMatrixKeys order so fork names don't depend on flag order.
+func parseForkMatrix(specs []string) ([]forkMatrixAxis, error) {
+	var axes []forkMatrixAxis
+	for _, spec := range specs {
+		key, list, ok := strings.Cut(spec, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --matrix '%s': expected key=value1,value2", spec)
+		}
+		if !slices.Contains(forkMatrixKeys, key) {
+			return nil, fmt.Errorf("invalid --matrix key '%s' (valid: %s)", key, strings.Join(forkMatrixKeys, ", "))
+		}
+		if slices.ContainsFunc(axes, func(a forkMatrixAxis) bool { return a.Key == key }) {
+			return nil, fmt.Errorf("--matrix key '%s' given more than once", key)
+		}
+
+		var values []string
+		for _, v := range strings.Split(list, ",") {
+			v = strings.TrimSpace(v)
+			if v == "" {
+				continue
+			}
+			if slices.Contains(values, v) {
+				return nil, fmt.Errorf("--matrix %s lists '%s' more than once", key, v)
+			}
+			values = append(values, v)
+		}
+		if len(values) == 0 {
+			return nil, fmt.Errorf("--matrix %s needs at least one value", key)
+		}
+		axes = append(axes, forkMatrixAxis{Key: key, Values: values})
+	}
+
+	slices.SortStableFunc(axes, func(a, b forkMatrixAxis) int {
+		return slices.Index(forkMatrixKeys, a.Key) - slices.Index(forkMatrixKeys, b.Key)
+	})
+	return axes, nil
+}
+
+// expandForkMatrix returns one variant per value combination, named
+// <prefix>-<value>... Matrix values take precedence over --model/--effort.
+func expandForkMatrix(prefix string, flags settingsLayer, axes []forkMatrixAxis) []forkVariant {
+	variants := []forkVariant{{Name: prefix, Flags: flags}}
+	for _, axis := range axes {
+		var next []forkVariant
+		for _, v := range variants {
+			for _, value := range axis.Values {
+				layer := v.Flags
+				switch axis.Key {
+				case "model":
+					layer.Model = value
+				case "effort":
+					layer.EffortLevel = value
+				}
+				next = append(next, forkVariant{Name: v.Name + "-" + value, Flags: layer})
+			}
+		}
+		variants = next
+	}
+	return variants
+}
+
+// runForkMatrix creates every matrix fork, prints them as a table, and
+// launches the first one unless noLaunch is set. Forks that aren't launched
+// now start from the parent when resumed.
+func runForkMatrix(cmd *cobra.Command, clotildeRoot string, store session.Store, parentSess *session.Session, variants []forkVariant, opts app.ForkOptions, additionalArgs []string, noLaunch bool) error {
+	// With --no-launch stdout is just the fork names, for scripts
+	out := cmd.OutOrStdout()
+	if noLaunch {
+		out = cmd.ErrOrStderr()
+	}
+
+	opts.Pending = true
+	created := make([]*createdFork, 0, len(variants))
+	for _, v := range variants {
+		fork, err := createFork(clotildeRoot, store, parentSess, v.Name, v.Flags, opts)
+		if err != nil {
+			return fmt.Errorf("failed to create fork '%s': %w", v.Name, err)
+		}
+		created = append(created, fork)
+	}
+
+	_, _ = fmt.Fprintln(out, ui.Success(fmt.Sprintf("Created %d forks from '%s'", len(created), parentSess.Name)))
+	table := tablewriter.NewWriter(out)
+	table.Header("NAME", "MODEL", "EFFORT")
+	for _, fork := range created {
+		_ = table.Append(fork.Session.Name, valueOrDash(fork.Resolved.Model.Value), valueOrDash(fork.Resolved.EffortLevel.Value))
+	}
+	_ = table.Render()
+	if !opts.ExpiresAt.IsZero() {
+		printExpiry(out, created[0].Session)
+	}
+
+	if noLaunch {
+		names := make([]string, len(created))
+		for i, fork := range created {
+			names[i] = fork.Session.Name
+		}
+		printNoLaunch(cmd, names...)
+		return nil
+	}
+
+	first := created[0]
+	_, _ = fmt.Fprintf(out, "\nStarting Claude Code with '%s' (resume the others with 'clotilde resume <name>')...\n", first.Session.Name)
+	perRun := resolvedSettings{PermissionMode: first.Resolved.PermissionMode}
+	return claude.Resume(clotildeRoot, first.Session, first.SettingsFile, append(additionalArgs, perRun.launchArgs()...), "")
+}
+
+// valueOrDash shows unset table values as "-".
+func valueOrDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -1,6 +1,7 @@
 package cmd_test
 
 import (
+	"bytes"
 	"io"
 	"os"
 	"path/filepath"
@@ -234,4 +235,142 @@ var _ = Describe("Fork Command", func() {
 		// Verify incognito fork was auto-deleted after Claude exited
 		Expect(store.Exists("incognito-fork")).To(BeFalse())
 	})
+
+	Describe("parent context", func() {
+		BeforeEach(func() {
+			parent := session.NewSession("parent", "uuid-parent-123")
+			parent.Metadata.Context = "working on GH-123"
+			Expect(store.Create(parent)).To(Succeed())
+		})
+
+		fork := func(args ...string) *session.Session {
+			rootCmd := cmd.NewRootCmd()
+			rootCmd.SetOut(io.Discard)
+			rootCmd.SetErr(io.Discard)
+			rootCmd.SetArgs(append([]string{"fork", "parent", "child", "--no-launch"}, args...))
+			Expect(rootCmd.Execute()).To(Succeed())
+
+			child, err := store.Get("child")
+			Expect(err).NotTo(HaveOccurred())
+			return child
+		}
+
+		It("inherits the parent's context", func() {
+			child := fork()
+			Expect(child.Metadata.Context).To(Equal("working on GH-123"))
+			Expect(child.Metadata.NoParentContext).To(BeFalse())
+		})
+
+		It("skips it with --no-parent-context", func() {
+			child := fork("--no-parent-context")
+			Expect(child.Metadata.Context).To(BeEmpty())
+			Expect(child.Metadata.NoParentContext).To(BeTrue())
+		})
+	})
+
+	Describe("--matrix", func() {
+		var out, errOut bytes.Buffer
+
+		BeforeEach(func() {
+			out.Reset()
+			errOut.Reset()
+			Expect(store.Create(session.NewSession("parent", "uuid-parent-123"))).To(Succeed())
+		})
+
+		run := func(args ...string) error {
+			rootCmd := cmd.NewRootCmd()
+			rootCmd.SetOut(&out)
+			rootCmd.SetErr(&errOut)
+			rootCmd.SetArgs(append([]string{"--claude-bin", filepath.Join(fakeClaudeDir, "claude")}, args...))
+			return rootCmd.Execute()
+		}
+
+		It("creates one fork per value without launching Claude on --no-launch", func() {
+			Expect(run("fork", "parent", "--matrix", "model=haiku,sonnet", "--no-launch")).To(Succeed())
+
+			for _, model := range []string{"haiku", "sonnet"} {
+				fork, err := store.Get("parent-" + model)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fork.Metadata.ParentSession).To(Equal("parent"))
+				Expect(fork.Metadata.PendingLaunch).To(BeTrue())
+
+				settings, err := store.LoadSettings("parent-" + model)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(settings.Model).To(Equal(model))
+			}
+
+			// Names on stdout for scripts, the table on stderr
+			Expect(out.String()).To(Equal("parent-haiku\nparent-sonnet\n"))
+			Expect(errOut.String()).To(ContainSubstring("Created 2 forks from 'parent'"))
+			Expect(errOut.String()).To(MatchRegexp(`parent-haiku\s+│\s+haiku`))
+			Expect(util.FileExists(claudeArgsFile)).To(BeFalse())
+		})
+
+		It("combines axes and names forks after the given prefix", func() {
+			Expect(run("fork", "parent", "try", "--matrix", "effort=low,high", "--matrix", "model=opus", "--no-launch")).To(Succeed())
+
+			Expect(store.Exists("try-opus-low")).To(BeTrue())
+			Expect(store.Exists("try-opus-high")).To(BeTrue())
+
+			settings, err := store.LoadSettings("try-opus-high")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(settings.EffortLevel).To(Equal("high"))
+		})
+
+		It("launches the first fork and leaves the others pending", func() {
+			Expect(run("fork", "parent", "--matrix", "model=haiku,sonnet")).To(Succeed())
+
+			args, err := testutil.ReadClaudeArgs(claudeArgsFile)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(args).To(ContainSubstring("--resume uuid-parent-123 --fork-session"))
+			Expect(args).To(ContainSubstring("-n parent-haiku"))
+
+			first, err := store.Get("parent-haiku")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(first.Metadata.PendingLaunch).To(BeFalse())
+
+			second, err := store.Get("parent-sonnet")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(second.Metadata.PendingLaunch).To(BeTrue())
+		})
+
+		It("forks from the parent when a pending fork is resumed", func() {
+			Expect(run("fork", "parent", "--matrix", "model=haiku", "--no-launch")).To(Succeed())
+			fork, err := store.Get("parent-haiku")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(run("resume", "parent-haiku")).To(Succeed())
+
+			args, err := testutil.ReadClaudeArgs(claudeArgsFile)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(args).To(ContainSubstring("--resume uuid-parent-123 --fork-session --session-id " + fork.Metadata.SessionID))
+
+			fork, err = store.Get("parent-haiku")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fork.Metadata.PendingLaunch).To(BeFalse())
+		})
+
+		It("creates nothing when any fork name is taken", func() {
+			Expect(store.Create(session.NewSession("parent-sonnet", "uuid-taken"))).To(Succeed())
+
+			err := run("fork", "parent", "--matrix", "model=haiku,sonnet", "--no-launch")
+			Expect(err).To(MatchError(ContainSubstring("session 'parent-sonnet' already exists")))
+			Expect(store.Exists("parent-haiku")).To(BeFalse())
+		})
+
+		It("creates a single fork without launching Claude on --no-launch", func() {
+			Expect(run("fork", "parent", "later", "--no-launch")).To(Succeed())
+
+			Expect(out.String()).To(Equal("later\n"))
+			fork, err := store.Get("later")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fork.Metadata.PendingLaunch).To(BeTrue())
+			Expect(util.FileExists(claudeArgsFile)).To(BeFalse())
+		})
+
+		It("rejects unknown keys", func() {
+			err := run("fork", "parent", "--matrix", "temperature=1,2")
+			Expect(err).To(MatchError(ContainSubstring("invalid --matrix key 'temperature'")))
+		})
+	})
 })
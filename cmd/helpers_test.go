@@ -0,0 +1,28 @@
+package cmd_test
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/fgrehm/clotilde/cmd"
+)
+
+// runClotilde runs clotilde with args in a fresh root command and returns what
+// it printed on stdout; stderr is discarded.
+func runClotilde(args ...string) (string, error) {
+	out, _, err := runClotildeWithInput("", args...)
+	return out, err
+}
+
+// runClotildeWithInput runs clotilde with args, reading stdin from input, and
+// returns what it printed on stdout and stderr.
+func runClotildeWithInput(input string, args ...string) (string, string, error) {
+	var out, errOut bytes.Buffer
+	rootCmd := cmd.NewRootCmd()
+	rootCmd.SetIn(strings.NewReader(input))
+	rootCmd.SetOut(&out)
+	rootCmd.SetErr(&errOut)
+	rootCmd.SetArgs(args)
+	err := rootCmd.Execute()
+	return out.String(), errOut.String(), err
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/ui"
+	"github.com/fgrehm/clotilde/internal/util"
+)
+
+// sessionStartEvent is the Claude Code hook event clotilde relies on to track
+// transcripts and register forks.
+const sessionStartEvent = "SessionStart"
+
+// registerHookCheckFlag adds --no-hook-check to commands that launch claude.
+func registerHookCheckFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("no-hook-check", false, "Don't warn when clotilde's SessionStart hook isn't installed")
+}
+
+// hookCheckStatus is what warnMissingHooks found.
+type hookCheckStatus int
+
+const (
+	hookInstalled hookCheckStatus = iota
+	hookMissing                   // No settings file has a clotilde SessionStart hook
+	hookStale                     // Only hooks pointing at a missing binary
+)
+
+// warnMissingHooks warns on stderr, before claude is launched, when no
+// settings file Claude Code reads for the project has a working clotilde
+// SessionStart hook. Without it the session's transcript path, /clear
+// rotations and fork registration silently stop being tracked.
+func warnMissingHooks(cmd *cobra.Command, clotildeRoot string) {
+	if skip, _ := cmd.Flags().GetBool("no-hook-check"); skip {
+		return
+	}
+	homeDir, err := util.HomeDir()
+	if err != nil {
+		return
+	}
+	writeHookWarning(cmd.ErrOrStderr(), sessionStartHookStatus(homeDir, config.ProjectRootOf(clotildeRoot)))
+}
+
+// sessionStartHookStatus checks the settings files Claude Code reads for
+// projectRoot. Unreadable files count as having no hooks.
+func sessionStartHookStatus(homeDir, projectRoot string) hookCheckStatus {
+	status := hookMissing
+	for _, file := range hookSettingsFiles(homeDir, projectRoot) {
+		hooks, _ := findClotildeHooks(file.Path)
+		for _, h := range hooks {
+			if h.Event != sessionStartEvent {
+				continue
+			}
+			if h.Exists {
+				return hookInstalled
+			}
+			status = hookStale
+		}
+	}
+	return status
+}
+
+// writeHookWarning explains what a missing or stale hook breaks and how to
+// fix it.
+func writeHookWarning(w io.Writer, status hookCheckStatus) {
+	var problem, fix string
+	switch status {
+	case hookMissing:
+		problem = "clotilde's SessionStart hook is not installed"
+		fix = "clotilde init --hooks-only"
+	case hookStale:
+		problem = "clotilde's SessionStart hook points at a missing clotilde binary"
+		fix = "clotilde hooks status --repair"
+	default:
+		return
+	}
+	_, _ = fmt.Fprintln(w, ui.Warning(fmt.Sprintf("%s: transcripts, /clear and forks of this session won't be tracked.", problem)))
+	_, _ = fmt.Fprintf(w, "  Fix it with: %s   (or pass --no-hook-check to skip this check)\n\n", fix)
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fgrehm/clotilde/internal/claude"
+)
+
+func TestSessionStartHookStatus(t *testing.T) {
+	homeDir := t.TempDir()
+	projectRoot := t.TempDir()
+	t.Setenv(claude.ConfigDirEnv, filepath.Join(homeDir, ".claude"))
+
+	writeSettings := func(path, command string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		content := `{"hooks": {"SessionStart": [{"hooks": [{"type": "command", "command": "` + command + `"}]}]}}`
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := sessionStartHookStatus(homeDir, projectRoot); got != hookMissing {
+		t.Errorf("Without settings files got %v, want hookMissing", got)
+	}
+
+	local := filepath.Join(projectRoot, ".claude", "settings.local.json")
+	writeSettings(local, "/gone/clotilde hook sessionstart")
+	if got := sessionStartHookStatus(homeDir, projectRoot); got != hookStale {
+		t.Errorf("With a missing binary got %v, want hookStale", got)
+	}
+
+	binary, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	clotilde := filepath.Join(t.TempDir(), "clotilde")
+	if err := os.Symlink(binary, clotilde); err != nil {
+		t.Fatal(err)
+	}
+	writeSettings(filepath.Join(homeDir, ".claude", "settings.json"), clotilde+" hook sessionstart")
+	if got := sessionStartHookStatus(homeDir, projectRoot); got != hookInstalled {
+		t.Errorf("With a user hook got %v, want hookInstalled", got)
+	}
+}
+
+func TestWriteHookWarning(t *testing.T) {
+	var out bytes.Buffer
+	writeHookWarning(&out, hookInstalled)
+	if out.Len() != 0 {
+		t.Errorf("Installed hooks printed %q", out.String())
+	}
+
+	writeHookWarning(&out, hookMissing)
+	if !strings.Contains(out.String(), "not installed") || !strings.Contains(out.String(), "clotilde init --hooks-only") {
+		t.Errorf("Missing hooks printed %q", out.String())
+	}
+
+	out.Reset()
+	writeHookWarning(&out, hookStale)
+	if !strings.Contains(out.String(), "hooks status --repair") {
+		t.Errorf("Stale hooks printed %q", out.String())
+	}
+}
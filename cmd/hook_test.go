@@ -5,15 +5,19 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	"github.com/fgrehm/clotilde/cmd"
+	"github.com/fgrehm/clotilde/internal/claude"
 	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/events"
 	"github.com/fgrehm/clotilde/internal/notify"
 	"github.com/fgrehm/clotilde/internal/session"
 	"github.com/fgrehm/clotilde/internal/testutil"
+	"github.com/fgrehm/clotilde/internal/util"
 )
 
 // executeHookWithInput executes a hook command with JSON input via stdin
@@ -40,6 +44,28 @@ func executeHookWithInput(hookName string, input []byte) error { //nolint:unpara
 	return err
 }
 
+// executeHookCapturingStdout executes a hook like executeHookWithInput and
+// returns what it printed to stdout (the context Claude Code injects)
+func executeHookCapturingStdout(hookName string, input []byte) (string, error) {
+	oldStdout := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		return "", err
+	}
+	os.Stdout = w
+
+	output := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		output <- string(data)
+	}()
+
+	err = executeHookWithInput(hookName, input)
+	os.Stdout = oldStdout
+	_ = w.Close()
+	return <-output, err
+}
+
 var _ = Describe("Hook Commands", func() {
 	var (
 		tempDir        string
@@ -94,6 +120,23 @@ var _ = Describe("Hook Commands", func() {
 	})
 
 	Describe("hook sessionstart", func() {
+		Context("input from other Claude Code versions", func() {
+			It("tracks the session from renamed fields and handles unknown sources as a startup", func() {
+				Expect(store.Create(session.NewSession("compat", "compat-uuid"))).To(Succeed())
+				GinkgoT().Setenv("CLOTILDE_SESSION_NAME", "compat")
+				GinkgoT().Setenv("CLAUDE_ENV_FILE", "")
+
+				input := []byte(`{"sessionId": "compat-uuid", "transcriptPath": "/transcripts/compat-uuid.jsonl", "source": "handoff", "model": {"id": "opus"}}`)
+				out, err := executeHookCapturingStdout("sessionstart", input)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(out).To(ContainSubstring("Session name: compat"))
+
+				sess, err := store.Get("compat")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(sess.Metadata.TranscriptPath).To(Equal("/transcripts/compat-uuid.jsonl"))
+			})
+		})
+
 		Context("source: startup", func() {
 			It("should handle startup for new sessions without error", func() {
 				// Create hook input
@@ -148,7 +191,7 @@ var _ = Describe("Hook Commands", func() {
 				Expect(string(content)).To(ContainSubstring("startup"))
 			})
 
-			It("should not error when session has context set", func() {
+			It("prints the session name and context", func() {
 				// Create session with context
 				sess := session.NewSession("session-with-context", "test-uuid-ctx")
 				sess.Metadata.Context = "working on GH-123"
@@ -168,10 +211,10 @@ var _ = Describe("Hook Commands", func() {
 				Expect(err).NotTo(HaveOccurred())
 
 				// Execute hook sessionstart
-				err = executeHookWithInput("sessionstart", inputJSON)
+				out, err := executeHookCapturingStdout("sessionstart", inputJSON)
 				Expect(err).NotTo(HaveOccurred())
-				// Note: Output includes "Session name: session-with-context" and "Context: working on GH-123"
-				// but we can't easily capture stdout in tests
+				Expect(out).To(ContainSubstring("Session name: session-with-context"))
+				Expect(out).To(ContainSubstring("Context: working on GH-123"))
 			})
 
 			It("should save transcript path from hook input", func() {
@@ -202,6 +245,36 @@ var _ = Describe("Hook Commands", func() {
 				Expect(err).NotTo(HaveOccurred())
 				Expect(updatedSess.Metadata.TranscriptPath).To(Equal("/home/user/.claude/projects/test-project/test-uuid-123.jsonl"))
 			})
+
+			It("records a hook.fired event", func() {
+				Expect(store.Create(session.NewSession("evented", "uuid-evented"))).To(Succeed())
+				GinkgoT().Setenv("CLOTILDE_SESSION_NAME", "evented")
+
+				err := executeHookWithInput("sessionstart", []byte(`{"session_id":"uuid-evented","source":"startup"}`))
+				Expect(err).NotTo(HaveOccurred())
+
+				data, err := os.ReadFile(events.LogPath(clotildeRoot))
+				Expect(err).NotTo(HaveOccurred())
+				lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+				var e events.Event
+				Expect(json.Unmarshal([]byte(lines[len(lines)-1]), &e)).To(Succeed())
+				Expect(e.Type).To(Equal(events.HookFired))
+				Expect(e.Session).To(Equal("evented"))
+				Expect(e.SessionID).To(Equal("uuid-evented"))
+				Expect(e.Hook).To(Equal("SessionStart"))
+				Expect(e.Source).To(Equal("startup"))
+			})
+
+			It("invalidates the session's transcript cache", func() {
+				Expect(store.Create(session.NewSession("cached", "uuid-cached"))).To(Succeed())
+				GinkgoT().Setenv("CLOTILDE_SESSION_NAME", "cached")
+				cachePath := claude.StatsCachePath(clotildeRoot, "cached")
+				Expect(os.WriteFile(cachePath, []byte("{}"), 0o644)).To(Succeed())
+
+				err := executeHookWithInput("sessionstart", []byte(`{"session_id":"uuid-cached","source":"resume"}`))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cachePath).NotTo(BeAnExistingFile())
+			})
 		})
 
 		Context("source: resume", func() {
@@ -260,6 +333,186 @@ var _ = Describe("Hook Commands", func() {
 				Expect(updatedSess.Metadata.TranscriptPath).To(Equal("/home/user/.claude/projects/test-project/test-uuid-456.jsonl"))
 			})
 		})
+
+		Context("forks", func() {
+			BeforeEach(func() {
+				parent := session.NewSession("parent", "uuid-parent")
+				parent.Metadata.Context = "auth refactor"
+				Expect(store.Create(parent)).To(Succeed())
+				GinkgoT().Setenv("CLOTILDE_SESSION_NAME", "child")
+			})
+
+			createChild := func(context string, noParentContext bool) {
+				child := session.NewSession("child", "uuid-child")
+				child.Metadata.IsForkedSession = true
+				child.Metadata.ParentSession = "parent"
+				child.Metadata.Context = context
+				child.Metadata.NoParentContext = noParentContext
+				Expect(store.Create(child)).To(Succeed())
+			}
+
+			resumeOutput := func() string {
+				input, err := json.Marshal(map[string]string{"session_id": "uuid-child", "source": "resume"})
+				Expect(err).NotTo(HaveOccurred())
+				out, err := executeHookCapturingStdout("sessionstart", input)
+				Expect(err).NotTo(HaveOccurred())
+				return out
+			}
+
+			It("labels the parent and its context", func() {
+				createChild("trying another approach", false)
+
+				out := resumeOutput()
+				Expect(out).To(ContainSubstring("Session name: child"))
+				Expect(out).To(ContainSubstring("Context: trying another approach"))
+				Expect(out).To(ContainSubstring("Forked from session: parent"))
+				Expect(out).To(ContainSubstring("Parent session context: auth refactor"))
+			})
+
+			It("doesn't repeat a context inherited from the parent", func() {
+				createChild("auth refactor", false)
+
+				out := resumeOutput()
+				Expect(out).To(ContainSubstring("Forked from session: parent"))
+				Expect(out).NotTo(ContainSubstring("Parent session context"))
+			})
+
+			It("redacts contexts matching the config's redact patterns", func() {
+				createChild("token ghp_abc123 for the deploy", false)
+				Expect(util.WriteJSON(config.GetConfigPath(clotildeRoot), map[string]any{"redact": []string{`ghp_\w+`, "auth"}})).To(Succeed())
+
+				out := resumeOutput()
+				Expect(out).To(ContainSubstring("Context: token [REDACTED] for the deploy"))
+				Expect(out).To(ContainSubstring("Parent session context: [REDACTED] refactor"))
+				Expect(out).NotTo(ContainSubstring("ghp_abc123"))
+			})
+
+			It("omits the parent for forks created with --no-parent-context", func() {
+				createChild("", true)
+
+				out := resumeOutput()
+				Expect(out).To(ContainSubstring("Session name: child"))
+				Expect(out).NotTo(ContainSubstring("parent"))
+			})
+		})
+
+		Context("source: clear", func() {
+			var oldTranscript string
+
+			BeforeEach(func() {
+				GinkgoT().Setenv("XDG_CONFIG_HOME", filepath.Join(tempDir, "xdg"))
+				GinkgoT().Setenv("CLOTILDE_SESSION_NAME", "cleared")
+
+				oldTranscript = filepath.Join(tempDir, "uuid-old.jsonl")
+				Expect(os.WriteFile(oldTranscript, []byte(`{"type":"assistant"}`+"\n"), 0o644)).To(Succeed())
+				sess := session.NewSession("cleared", "uuid-old")
+				sess.Metadata.TranscriptPath = oldTranscript
+				Expect(store.Create(sess)).To(Succeed())
+			})
+
+			runClear := func() *session.Session {
+				input, err := json.Marshal(map[string]string{
+					"session_id":      "uuid-new",
+					"transcript_path": filepath.Join(tempDir, "uuid-new.jsonl"),
+					"source":          "clear",
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(executeHookWithInput("sessionstart", input)).To(Succeed())
+
+				sess, err := store.Get("cleared")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(sess.Metadata.SessionID).To(Equal("uuid-new"))
+				Expect(sess.Metadata.PreviousSessions).To(HaveLen(1))
+				Expect(sess.Metadata.PreviousSessions[0].SessionID).To(Equal("uuid-old"))
+				Expect(sess.Metadata.PreviousSessions[0].Reason).To(Equal(session.RotationClear))
+				return sess
+			}
+
+			It("leaves the old transcript in place by default", func() {
+				sess := runClear()
+				Expect(sess.Metadata.PreviousSessions[0].Detached).To(BeEmpty())
+				Expect(oldTranscript).To(BeAnExistingFile())
+			})
+
+			It("copies the old transcript into the session with clear.transcript=snapshot", func() {
+				Expect(os.WriteFile(config.GetConfigPath(clotildeRoot), []byte(`{"clear": {"transcript": "snapshot"}}`), 0o644)).To(Succeed())
+
+				sess := runClear()
+				Expect(sess.Metadata.PreviousSessions[0].Detached).To(Equal(session.TranscriptSnapshot))
+				Expect(oldTranscript).To(BeAnExistingFile())
+				snapshot, err := os.ReadFile(filepath.Join(config.GetSessionDir(clotildeRoot, "cleared"), "snapshots", "uuid-old.jsonl"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(snapshot)).To(ContainSubstring("assistant"))
+			})
+
+			It("deletes the old transcript with clear.transcript=prune", func() {
+				Expect(os.WriteFile(config.GetConfigPath(clotildeRoot), []byte(`{"clear": {"transcript": "prune"}}`), 0o644)).To(Succeed())
+
+				sess := runClear()
+				Expect(sess.Metadata.PreviousSessions[0].Detached).To(Equal(session.TranscriptPruned))
+				Expect(oldTranscript).NotTo(BeAnExistingFile())
+			})
+		})
+
+		Context("CLAUDE_ENV_FILE", func() {
+			var envFile string
+
+			BeforeEach(func() {
+				envFile = filepath.Join(tempDir, "claude-env")
+				Expect(os.WriteFile(envFile, []byte("export FOO=bar\n"), 0o644)).To(Succeed())
+				GinkgoT().Setenv("CLAUDE_ENV_FILE", envFile)
+				GinkgoT().Setenv("CLOTILDE_HOOK_EXECUTED", "")
+				GinkgoT().Setenv("CLOTILDE_SESSION_NAME", "")
+
+				Expect(store.Create(session.NewSession("env-session", "uuid-1"))).To(Succeed())
+			})
+
+			runHook := func(sessionID, source string) {
+				input, err := json.Marshal(map[string]string{"session_id": sessionID, "source": source})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(executeHookWithInput("sessionstart", input)).To(Succeed())
+			}
+
+			envLines := func() []string {
+				content, err := os.ReadFile(envFile)
+				Expect(err).NotTo(HaveOccurred())
+				return strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+			}
+
+			It("keeps one line per key across startup, compact, clear and resume", func() {
+				GinkgoT().Setenv("CLOTILDE_SESSION_NAME", "env-session")
+				runHook("uuid-1", "startup")
+
+				// Later hooks run without the launch env and fall back to the env file
+				GinkgoT().Setenv("CLOTILDE_SESSION_NAME", "")
+				runHook("uuid-1", "compact")
+				runHook("uuid-2", "clear")
+				runHook("uuid-2", "resume")
+
+				Expect(envLines()).To(Equal([]string{
+					"export FOO=bar",
+					"CLOTILDE_HOOK_EXECUTED=uuid-2:resume",
+					"CLOTILDE_SESSION=env-session",
+				}))
+
+				sess, err := store.Get("env-session")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(sess.Metadata.SessionID).To(Equal("uuid-2"))
+			})
+
+			It("collapses duplicate lines written by older versions", func() {
+				Expect(os.WriteFile(envFile, []byte("CLOTILDE_SESSION=old\nexport FOO=bar\nCLOTILDE_SESSION=old\n"), 0o644)).To(Succeed())
+				GinkgoT().Setenv("CLOTILDE_SESSION_NAME", "env-session")
+				runHook("uuid-1", "startup")
+
+				Expect(envLines()).To(Equal([]string{
+					"CLOTILDE_SESSION=env-session",
+					"export FOO=bar",
+					"CLOTILDE_HOOK_EXECUTED=uuid-1:startup",
+				}))
+				Expect(envFile + ".lock").NotTo(BeAnExistingFile())
+			})
+		})
 	})
 
 	Describe("hook notify", func() {
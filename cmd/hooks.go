@@ -0,0 +1,256 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fgrehm/clotilde/internal/claude"
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/ui"
+	"github.com/fgrehm/clotilde/internal/util"
+)
+
+// newHooksCmd creates the 'hooks' command group. Not to be confused with
+// 'hook', which holds the commands Claude Code itself invokes.
+func newHooksCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "hooks",
+		Short: "Inspect clotilde's Claude Code hooks",
+	}
+	cmd.AddCommand(newHooksStatusCmd())
+	return cmd
+}
+
+func newHooksStatusCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show which clotilde hooks are installed and where",
+		Long: `List the clotilde hooks found in every Claude Code settings file that applies
+to the current directory: user (~/.claude/settings.json and settings.local.json,
+or under $CLAUDE_CONFIG_DIR),
+project (.claude/settings.json) and local (.claude/settings.local.json).
+
+Each hook's exact command is shown, along with whether the clotilde binary it
+runs still exists. Hooks that point at a moved or deleted binary silently stop
+working; --repair rewrites them to use the running clotilde binary.`,
+		Example: `  clotilde hooks status
+  clotilde hooks status --repair`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repair, _ := cmd.Flags().GetBool("repair")
+
+			homeDir, err := util.HomeDir()
+			if err != nil {
+				return fmt.Errorf("failed to determine home directory: %w", err)
+			}
+			projectRoot, err := config.FindProjectRoot()
+			if err != nil {
+				return fmt.Errorf("failed to determine project root: %w", err)
+			}
+			currentBinary, err := os.Executable()
+			if err != nil {
+				return fmt.Errorf("failed to determine clotilde binary path: %w", err)
+			}
+
+			out := cmd.OutOrStdout()
+			var total, stale int
+			for _, file := range hookSettingsFiles(homeDir, projectRoot) {
+				hooks, err := findClotildeHooks(file.Path)
+				if err != nil {
+					return err
+				}
+				total += len(hooks)
+				stale += printHookFile(out, file, hooks, homeDir)
+
+				if repair && hasStaleHook(hooks) {
+					fixed, err := repairHookFile(file.Path, currentBinary)
+					if err != nil {
+						return err
+					}
+					_, _ = fmt.Fprintln(out, ui.Success(fmt.Sprintf("  Repaired %d hook(s) to use %s", fixed, currentBinary)))
+					stale -= fixed
+				}
+			}
+
+			_, _ = fmt.Fprintln(out)
+			switch {
+			case total == 0:
+				_, _ = fmt.Fprintln(out, ui.Warning("No clotilde hooks installed. Run 'clotilde setup' to install them."))
+			case stale > 0:
+				_, _ = fmt.Fprintln(out, ui.Warning(fmt.Sprintf("%d hook(s) point at a missing clotilde binary. Run 'clotilde hooks status --repair' to fix them.", stale)))
+			default:
+				_, _ = fmt.Fprintln(out, ui.Success("All clotilde hooks point at an existing binary."))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().Bool("repair", false, "Rewrite hooks whose binary is missing to use the running clotilde binary")
+	return cmd
+}
+
+// hookSettingsFile is a Claude Code settings file that may hold hooks.
+type hookSettingsFile struct {
+	Scope string // user, user (local), project, local
+	Path  string
+}
+
+// installedHook is a clotilde hook found in a settings file.
+type installedHook struct {
+	Event   string
+	Command string
+	Exists  bool // Whether the binary the command runs still exists
+}
+
+// hookSettingsFiles returns the settings files Claude Code reads hooks from,
+// from broadest to narrowest scope. Project files are skipped when the project
+// root is the home directory, where they are the user files.
+func hookSettingsFiles(homeDir, projectRoot string) []hookSettingsFile {
+	files := []hookSettingsFile{
+		{Scope: "user", Path: filepath.Join(claude.ConfigDir(homeDir), "settings.json")},
+		{Scope: "user (local)", Path: filepath.Join(claude.ConfigDir(homeDir), "settings.local.json")},
+	}
+	if projectRoot != homeDir {
+		files = append(files,
+			hookSettingsFile{Scope: "project", Path: filepath.Join(projectRoot, ".claude", "settings.json")},
+			hookSettingsFile{Scope: "local", Path: filepath.Join(projectRoot, ".claude", "settings.local.json")},
+		)
+	}
+	return files
+}
+
+// findClotildeHooks returns the clotilde hooks in a settings file, sorted by
+// event. A missing file has no hooks.
+func findClotildeHooks(settingsPath string) ([]installedHook, error) {
+	settings, err := loadHookSettings(settingsPath)
+	if err != nil || settings == nil {
+		return nil, err
+	}
+
+	var found []installedHook
+	forEachClotildeHook(settings, func(event string, hook map[string]any) {
+		command, _ := hook["command"].(string)
+		found = append(found, installedHook{Event: event, Command: command, Exists: binaryExists(strings.Fields(command)[0])})
+	})
+	return found, nil
+}
+
+// repairHookFile points every clotilde hook whose binary is missing at
+// currentBinary, keeping the rest of the command. Returns how many were fixed.
+func repairHookFile(settingsPath, currentBinary string) (int, error) {
+	settings, err := loadHookSettings(settingsPath)
+	if err != nil || settings == nil {
+		return 0, err
+	}
+
+	var fixed int
+	forEachClotildeHook(settings, func(_ string, hook map[string]any) {
+		command, _ := hook["command"].(string)
+		binary := strings.Fields(command)[0]
+		if binaryExists(binary) {
+			return
+		}
+		hook["command"] = currentBinary + strings.TrimPrefix(strings.TrimSpace(command), binary)
+		fixed++
+	})
+
+	if err := util.WriteJSON(settingsPath, settings); err != nil {
+		return 0, fmt.Errorf("failed to write %s: %w", settingsPath, err)
+	}
+	return fixed, nil
+}
+
+// loadHookSettings reads a Claude Code settings file, returning nil if it doesn't exist.
+func loadHookSettings(settingsPath string) (map[string]any, error) {
+	if !util.FileExists(settingsPath) {
+		return nil, nil
+	}
+	var settings map[string]any
+	if err := util.ReadJSON(settingsPath, &settings); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", settingsPath, err)
+	}
+	return settings, nil
+}
+
+// forEachClotildeHook calls fn for every clotilde hook in settings, ordered by
+// event name. fn may modify the hook in place.
+func forEachClotildeHook(settings map[string]any, fn func(event string, hook map[string]any)) {
+	hooks, _ := settings["hooks"].(map[string]any)
+	for _, event := range slices.Sorted(maps.Keys(hooks)) {
+		matchers, _ := hooks[event].([]any)
+		for _, item := range matchers {
+			matcher, _ := item.(map[string]any)
+			hookList, _ := matcher["hooks"].([]any)
+			for _, h := range hookList {
+				hook, ok := h.(map[string]any)
+				if !ok {
+					continue
+				}
+				command, _ := hook["command"].(string)
+				if parts := strings.Fields(command); len(parts) > 0 && filepath.Base(parts[0]) == "clotilde" {
+					fn(event, hook)
+				}
+			}
+		}
+	}
+}
+
+// binaryExists reports whether a hook's binary can be run: an existing file
+// for paths, or a PATH lookup for bare names.
+func binaryExists(binary string) bool {
+	if strings.ContainsRune(binary, filepath.Separator) {
+		info, err := os.Stat(binary)
+		return err == nil && !info.IsDir()
+	}
+	_, err := exec.LookPath(binary)
+	return err == nil
+}
+
+func hasStaleHook(hooks []installedHook) bool {
+	for _, h := range hooks {
+		if !h.Exists {
+			return true
+		}
+	}
+	return false
+}
+
+// tildePath shortens paths under the home directory to ~/... for display.
+func tildePath(homeDir, path string) string {
+	if rel, err := filepath.Rel(homeDir, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.Join("~", rel)
+	}
+	return path
+}
+
+// printHookFile prints one settings file's clotilde hooks and returns how many are stale.
+func printHookFile(out io.Writer, file hookSettingsFile, hooks []installedHook, homeDir string) int {
+	header := fmt.Sprintf("%s (%s)", ui.BoldStyle.Render(file.Scope), tildePath(homeDir, file.Path))
+	switch {
+	case !util.FileExists(file.Path):
+		_, _ = fmt.Fprintln(out, header+": "+ui.DimStyle.Render("no settings file"))
+		return 0
+	case len(hooks) == 0:
+		_, _ = fmt.Fprintln(out, header+": "+ui.DimStyle.Render("no clotilde hooks"))
+		return 0
+	}
+
+	_, _ = fmt.Fprintln(out, header+":")
+	var stale int
+	for _, h := range hooks {
+		if h.Exists {
+			_, _ = fmt.Fprintf(out, "  ✓ %s: %s\n", h.Event, h.Command)
+			continue
+		}
+		stale++
+		_, _ = fmt.Fprintf(out, "  ✗ %s: %s (binary not found)\n", h.Event, h.Command)
+	}
+	return stale
+}
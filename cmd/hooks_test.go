@@ -0,0 +1,90 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Hooks Status Command", func() {
+	var (
+		tempDir    string
+		originalWd string
+		fakeHome   string
+		installed  string
+	)
+
+	BeforeEach(func() {
+		tempDir = GinkgoT().TempDir()
+		fakeHome = filepath.Join(tempDir, "home")
+		GinkgoT().Setenv("HOME", fakeHome)
+
+		var err error
+		originalWd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+
+		projectDir := filepath.Join(tempDir, "project")
+		Expect(os.MkdirAll(filepath.Join(projectDir, ".claude"), 0o755)).To(Succeed())
+		Expect(os.Chdir(projectDir)).To(Succeed())
+
+		installed = filepath.Join(tempDir, "bin", "clotilde")
+		Expect(os.MkdirAll(filepath.Dir(installed), 0o755)).To(Succeed())
+		Expect(os.WriteFile(installed, []byte("#!/bin/sh\n"), 0o755)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		_ = os.Chdir(originalWd)
+	})
+
+	writeSettings := func(path, command string) {
+		Expect(os.MkdirAll(filepath.Dir(path), 0o755)).To(Succeed())
+		content := `{"model": "opus", "hooks": {"SessionStart": [{"hooks": [
+			{"type": "command", "command": "` + command + `"},
+			{"type": "command", "command": "echo other"}
+		]}]}}`
+		Expect(os.WriteFile(path, []byte(content), 0o644)).To(Succeed())
+	}
+
+	run := func(args ...string) string {
+		out, err := runClotilde(args...)
+		Expect(err).NotTo(HaveOccurred())
+		return out
+	}
+
+	It("suggests setup when no hooks are installed", func() {
+		out := run("hooks", "status")
+		Expect(out).To(ContainSubstring("no settings file"))
+		Expect(out).To(ContainSubstring("No clotilde hooks installed"))
+	})
+
+	It("lists hooks per settings file and flags missing binaries", func() {
+		writeSettings(filepath.Join(fakeHome, ".claude", "settings.json"), installed+" hook sessionstart")
+		writeSettings(filepath.Join(".claude", "settings.local.json"), "/gone/clotilde hook sessionstart")
+
+		out := run("hooks", "status")
+		Expect(out).To(ContainSubstring("user (~/.claude/settings.json):"))
+		Expect(out).To(ContainSubstring("✓ SessionStart: " + installed + " hook sessionstart"))
+		Expect(out).To(ContainSubstring("✗ SessionStart: /gone/clotilde hook sessionstart (binary not found)"))
+		Expect(out).NotTo(ContainSubstring("echo other"))
+		Expect(out).To(ContainSubstring("1 hook(s) point at a missing clotilde binary"))
+	})
+
+	It("rewrites stale hooks to the running binary with --repair", func() {
+		localSettings := filepath.Join(".claude", "settings.local.json")
+		writeSettings(localSettings, "/gone/clotilde hook sessionstart")
+
+		out := run("hooks", "status", "--repair")
+		Expect(out).To(ContainSubstring("Repaired 1 hook(s)"))
+		Expect(out).To(ContainSubstring("All clotilde hooks point at an existing binary"))
+
+		self, err := os.Executable()
+		Expect(err).NotTo(HaveOccurred())
+		data, err := os.ReadFile(localSettings)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(ContainSubstring(`"command": "` + self + ` hook sessionstart"`))
+		Expect(string(data)).To(ContainSubstring(`"echo other"`))
+		Expect(string(data)).To(ContainSubstring(`"model": "opus"`))
+	})
+})
@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"fmt"
+	"io"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -35,11 +37,10 @@ process crashes or is killed (SIGKILL), the session may persist. Use
 'clotilde delete <name>' to clean up manually if needed.`,
 		Args: maxPositionalArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			// Extract additional args after '--'
-			var additionalArgs []string
-			argsLenAtDash := cmd.Flags().ArgsLenAtDash()
-			if argsLenAtDash > 0 && len(args) > argsLenAtDash {
-				additionalArgs = args[argsLenAtDash:]
+			// Claude flags from --with presets and after '--'
+			additionalArgs, err := claudeArgs(cmd, args)
+			if err != nil {
+				return err
 			}
 
 			// Resolve shorthand flags
@@ -63,7 +64,13 @@ process crashes or is killed (SIGKILL), the session may persist. Use
 			// Generate or use provided name
 			var name string
 			if len(args) > 0 {
-				name = args[0]
+				clotildeRoot, err := config.FindOrCreateClotildeRoot()
+				if err != nil {
+					return fmt.Errorf("failed to initialize session storage: %w", err)
+				}
+				if name, err = sessionNameArg(cmd, clotildeRoot, args[0]); err != nil {
+					return err
+				}
 			} else {
 				// Generate a unique random name
 				clotildeRoot, err := config.FindOrCreateClotildeRoot()
@@ -88,6 +95,10 @@ process crashes or is killed (SIGKILL), the session may persist. Use
 			// Build params from flags (incognito doesn't have --incognito flag, so build manually)
 			params := buildIncognitoParams(cmd, name)
 
+			if err := confirmYoloDirs(cmd, params); err != nil {
+				return err
+			}
+
 			// Create the session
 			result, err := createSession(params)
 			if err != nil {
@@ -97,15 +108,22 @@ process crashes or is killed (SIGKILL), the session may persist. Use
 			// Print output
 			_, _ = fmt.Fprintln(cmd.OutOrStdout(), ui.Info(fmt.Sprintf("👻 Created incognito session '%s' (%s)", result.Session.Name, result.Session.Metadata.SessionID)))
 			_, _ = fmt.Fprintln(cmd.OutOrStdout(), ui.Info("👻 This session will auto-delete when you exit Claude"))
+			if explain, _ := cmd.Flags().GetBool("explain"); explain {
+				_, _ = fmt.Fprintln(cmd.OutOrStdout())
+				printSettingsExplanation(cmd.OutOrStdout(), result.Resolved)
+			}
 			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "\nStarting Claude Code...")
+			warnMissingHooks(cmd, result.ClotildeRoot)
+			warnActiveSessions(cmd, result.ClotildeRoot, result.Session.Name)
 
 			// Invoke claude
-			return claude.Start(result.ClotildeRoot, result.Session, result.SettingsFile, additionalArgs)
+			return claude.Start(result.ClotildeRoot, result.Session, result.SettingsFile, additionalArgs, "")
 		},
 	}
 	cmd.Flags().String("model", "", "Claude model to use (haiku, sonnet, opus); opus defaults to 1M context")
 	cmd.Flags().String("context", "", "Session context (e.g. \"working on ticket GH-123\")")
 	cmd.Flags().String("profile", "", "Named profile from config (model, permissions, output style)")
+	registerLauncherFlag(cmd)
 
 	// Permission flags
 	cmd.Flags().String("permission-mode", "", "Permission mode (acceptEdits, bypassPermissions, default, dontAsk, plan)")
@@ -119,6 +137,12 @@ process crashes or is killed (SIGKILL), the session may persist. Use
 
 	// Shorthand flags
 	registerShorthandFlags(cmd)
+	registerWithFlag(cmd)
+	registerExplainFlag(cmd)
+	registerTitleFlag(cmd)
+	registerHookCheckFlag(cmd)
+	registerIKnowFlag(cmd)
+	registerSlugifyFlag(cmd)
 
 	// Register flag completions
 	_ = cmd.RegisterFlagCompletionFunc("model", modelCompletion)
@@ -126,3 +150,55 @@ process crashes or is killed (SIGKILL), the session may persist. Use
 	_ = cmd.RegisterFlagCompletionFunc("output-style", outputStyleCompletion)
 	return cmd
 }
+
+func newQuickCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "q [question...] [-- <claude-flags>...]",
+		Short: "Ask a quick question in a throwaway incognito session",
+		Long: `Launch Claude Code right away in an incognito session with a generated
+name and the project's default settings, without asking anything. Words
+given as arguments are sent as the first message. The session deletes
+itself when you exit, as with 'clotilde incognito'.`,
+		Example: `  clotilde q
+  clotilde q how is the retry logic in the api client configured
+  clotilde q -- --model haiku`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			question := args
+			var claudeArgs []string
+			if dash := cmd.Flags().ArgsLenAtDash(); dash >= 0 {
+				question, claudeArgs = args[:dash], args[dash:]
+			}
+
+			clotildeRoot, err := config.FindOrCreateClotildeRoot()
+			if err != nil {
+				return fmt.Errorf("failed to initialize session storage: %w", err)
+			}
+			sessions, err := session.NewFileStore(clotildeRoot).List()
+			if err != nil {
+				return fmt.Errorf("failed to list sessions: %w", err)
+			}
+			return startQuickIncognito(cmd.OutOrStdout(), sessions, strings.Join(question, " "), claudeArgs)
+		},
+	}
+}
+
+// startQuickIncognito launches an incognito session with a generated name
+// and default settings, sending question (if any) as the first message. Used
+// by 'clotilde q' and the dashboard.
+func startQuickIncognito(out io.Writer, sessions []*session.Session, question string, claudeArgs []string) error {
+	existingNames := make([]string, len(sessions))
+	for i, sess := range sessions {
+		existingNames[i] = sess.Name
+	}
+
+	result, err := createSession(SessionCreateParams{Name: util.GenerateUniqueRandomName(existingNames), Incognito: true})
+	if err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintln(out, ui.Info(fmt.Sprintf("👻 Quick incognito session '%s' (deleted when you exit Claude)", result.Session.Name)))
+	if question = strings.TrimSpace(question); question != "" {
+		claudeArgs = append(claudeArgs, question)
+	}
+	return claude.Start(result.ClotildeRoot, result.Session, result.SettingsFile, claudeArgs, "")
+}
@@ -5,13 +5,16 @@ import (
 	"fmt"
 	"maps"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/spf13/cobra"
 
 	"github.com/fgrehm/clotilde/internal/claude"
 	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/registry"
 	"github.com/fgrehm/clotilde/internal/ui"
 	"github.com/fgrehm/clotilde/internal/util"
 )
@@ -23,9 +26,35 @@ var initCmd = &cobra.Command{
 	Long: `Initialize clotilde by creating the .claude/clotilde directory structure
 and setting up SessionStart hooks in .claude/settings.local.json (local to your machine).
 
-Use --global to install hooks in .claude/settings.json instead (shared with team).`,
+Use --global to install hooks in .claude/settings.json instead (shared with team).
+
+Re-running init leaves hooks that are already installed alone. --hooks-only
+(re)installs just the hooks, without creating folders or touching config,
+git ignore rules or imports. Hooks written by an older clotilde (e.g. one
+entry per SessionStart source) are reported but kept; --force rewrites every
+clotilde hook in the settings file in the current format.
+
+Hooks run the current clotilde binary by absolute path, so they keep working
+when Claude Code is started from a shell without clotilde on its PATH. Use
+--hook-path path to write a bare "clotilde" command resolved through PATH
+instead (e.g. when the binary is upgraded in place by a package manager).
+
+Session data is kept out of git by adding .claude/clotilde/sessions/ to
+.git/info/exclude (or to .gitignore with --global). Pass --gitignore=false to skip.
+
+With --storage data, sessions are kept under $XDG_DATA_HOME/clotilde instead
+of the project, and only a small .claude/clotilde-storage file pointing there
+is written to it. The default comes from the global config's "storage" setting.
+
+Claude Code conversations already in the project are listed after a fresh
+init, most recent first, to import as named sessions. Use --import all to
+import them without asking, or --import none to skip the check.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		global, _ := cmd.Flags().GetBool("global")
+		importMode, _ := cmd.Flags().GetString("import")
+		if !slices.Contains([]string{importAsk, importAll, importNone}, importMode) {
+			return fmt.Errorf("invalid --import %q (must be %q, %q or %q)", importMode, importAsk, importAll, importNone)
+		}
 		// Check if claude is installed
 		if err := claude.IsInstalled(); err != nil {
 			return err
@@ -37,22 +66,11 @@ Use --global to install hooks in .claude/settings.json instead (shared with team
 			return fmt.Errorf("failed to get current directory: %w", err)
 		}
 
-		// Check if already initialized
-		alreadyInitialized := config.IsInitialized()
-		if alreadyInitialized {
-			fmt.Println("Clotilde is already initialized. Updating hooks...")
-		} else {
-			// Create .claude/clotilde structure
-			fmt.Println("Creating .claude/clotilde structure...")
-			if err := config.EnsureClotildeStructure(cwd); err != nil {
-				return fmt.Errorf("failed to create clotilde structure: %w", err)
-			}
-		}
-
 		// Get path to clotilde binary (for hooks)
-		clotildeBinary, err := os.Executable()
+		hookPath, _ := cmd.Flags().GetString("hook-path")
+		clotildeBinary, err := resolveHookBinary(hookPath)
 		if err != nil {
-			return fmt.Errorf("failed to determine clotilde binary path: %w", err)
+			return err
 		}
 
 		// Setup hooks
@@ -60,6 +78,46 @@ Use --global to install hooks in .claude/settings.json instead (shared with team
 		if global {
 			settingsFile = "settings.json"
 		}
+		force, _ := cmd.Flags().GetBool("force")
+
+		// Only the hook wiring: no folders, config, git rules or imports
+		if hooksOnly, _ := cmd.Flags().GetBool("hooks-only"); hooksOnly {
+			if err := setupHooks(cwd, clotildeBinary, settingsFile, force); err != nil {
+				return fmt.Errorf("failed to setup hooks: %w", err)
+			}
+			return nil
+		}
+
+		// Check if already initialized
+		alreadyInitialized := config.IsInitialized()
+		if alreadyInitialized {
+			fmt.Println("Clotilde is already initialized. Updating hooks...")
+		} else {
+			storage, _ := cmd.Flags().GetString("storage")
+			if storage == "" {
+				globalCfg, err := config.LoadGlobalOrDefault()
+				if err != nil {
+					return fmt.Errorf("failed to load global config: %w", err)
+				}
+				storage = globalCfg.Storage
+			}
+
+			switch storage {
+			case "", config.StorageProject:
+				// Create .claude/clotilde structure
+				fmt.Println("Creating .claude/clotilde structure...")
+				if err := config.EnsureClotildeStructure(cwd); err != nil {
+					return fmt.Errorf("failed to create clotilde structure: %w", err)
+				}
+			case config.StorageData:
+				fmt.Printf("Creating session storage under %s...\n", config.GlobalDataDir())
+				if _, err := config.EnsureDataStorage(cwd); err != nil {
+					return err
+				}
+			default:
+				return fmt.Errorf("invalid storage '%s' (use %q or %q)", storage, config.StorageProject, config.StorageData)
+			}
+		}
 
 		if !alreadyInitialized {
 			if global {
@@ -68,11 +126,31 @@ Use --global to install hooks in .claude/settings.json instead (shared with team
 				fmt.Println("Setting up SessionStart hooks in .claude/settings.local.json (local to this machine)...")
 			}
 		}
-		if err := setupHooks(cwd, clotildeBinary, settingsFile); err != nil {
+		if err := setupHooks(cwd, clotildeBinary, settingsFile, force); err != nil {
 			return fmt.Errorf("failed to setup hooks: %w", err)
 		}
 
-		clotildeRoot := filepath.Join(cwd, config.ClotildeDir)
+		clotildeRoot, _, err := config.ProjectClotildeRoot(cwd)
+		if err != nil {
+			return err
+		}
+
+		// Record the project for 'clotilde projects' (best effort)
+		_ = registry.Register(cwd)
+
+		if manageGitignore, _ := cmd.Flags().GetBool("gitignore"); manageGitignore {
+			if err := reportGitignore(cmd, cwd, global); err != nil {
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), ui.Warning(fmt.Sprintf("Could not update git ignore rules: %v", err)))
+			}
+		}
+
+		// Re-running init only looks again when asked to
+		if !alreadyInitialized || cmd.Flags().Changed("import") {
+			if err := offerConversationImport(cmd.OutOrStdout(), clotildeRoot, importMode); err != nil {
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), ui.Warning(fmt.Sprintf("Could not import existing conversations: %v", err)))
+			}
+		}
+
 		if alreadyInitialized {
 			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "")
 			_, _ = fmt.Fprintln(cmd.OutOrStdout(), ui.Success("Hooks updated successfully!"))
@@ -88,15 +166,63 @@ Use --global to install hooks in .claude/settings.json instead (shared with team
 	},
 }
 
+// Hook path modes for --hook-path.
+const (
+	hookPathAbsolute = "absolute" // Embed the running binary's absolute path
+	hookPathLookup   = "path"     // Write a bare "clotilde", resolved through PATH
+)
+
+func registerHookPathFlag(cmd *cobra.Command) {
+	cmd.Flags().String("hook-path", hookPathAbsolute, `How hooks reference clotilde: "absolute" (this binary's path) or "path" (PATH lookup)`)
+}
+
+// resolveHookBinary returns the binary clotilde's hook commands should run.
+// The PATH mode fails when clotilde can't be found on PATH, since the hook
+// would then fail silently on every session start.
+func resolveHookBinary(mode string) (string, error) {
+	switch mode {
+	case hookPathAbsolute:
+		binary, err := os.Executable()
+		if err != nil {
+			return "", fmt.Errorf("failed to determine clotilde binary path: %w", err)
+		}
+		return binary, nil
+	case hookPathLookup:
+		if _, err := exec.LookPath("clotilde"); err != nil {
+			return "", fmt.Errorf("clotilde is not on PATH; use --hook-path absolute to embed this binary's path")
+		}
+		return "clotilde", nil
+	default:
+		return "", fmt.Errorf("invalid --hook-path %q (must be %q or %q)", mode, hookPathAbsolute, hookPathLookup)
+	}
+}
+
+// hookInstallState compares a settings file's clotilde hooks with the ones
+// GenerateHookConfig produces.
+type hookInstallState int
+
+const (
+	hooksCurrent  hookInstallState = iota // Exactly the generated hooks
+	hooksMissing                          // None, or only ones whose binary is gone
+	hooksOutdated                         // Working hooks in another shape (e.g. per-source matchers)
+)
+
+// managedHookEvents are the events whose clotilde hooks are replaced on
+// every install, whether clotilde still generates hooks for them or not.
+var managedHookEvents = []string{"SessionStart", "Stop", "Notification", "PreToolUse", "PostToolUse"}
+
 // mergeHooksIntoSettings reads a Claude settings file, merges clotilde's
-// hooks, and writes it back. Returns the merged hooks map for display purposes.
+// hooks, and writes it back. Returns the merged hooks map for display purposes
+// and the state the file was found in. Without force, files whose hooks are
+// current or outdated are left alone; force replaces clotilde hooks in every
+// event with the generated ones.
 // The caller is responsible for ensuring the parent directory exists.
-func mergeHooksIntoSettings(settingsPath, clotildeBinary string) (map[string]any, error) {
+func mergeHooksIntoSettings(settingsPath, clotildeBinary string, force bool) (map[string]any, hookInstallState, error) {
 	// Read existing settings if they exist
 	var settings map[string]any
 	if util.FileExists(settingsPath) {
 		if err := util.ReadJSON(settingsPath, &settings); err != nil {
-			return nil, fmt.Errorf("failed to read existing settings: %w", err)
+			return nil, hooksMissing, fmt.Errorf("failed to read existing settings: %w", err)
 		}
 	} else {
 		settings = make(map[string]any)
@@ -104,37 +230,93 @@ func mergeHooksIntoSettings(settingsPath, clotildeBinary string) (map[string]any
 
 	// Generate hook config
 	hookConfig := claude.GenerateHookConfig(clotildeBinary)
+	generated := map[string][]claude.HookMatcher{
+		"SessionStart": hookConfig.SessionStart,
+		"Stop":         hookConfig.Stop,
+		"Notification": hookConfig.Notification,
+		"PreToolUse":   hookConfig.PreToolUse,
+		"PostToolUse":  hookConfig.PostToolUse,
+	}
+
+	state := hookStateOf(settings, generated, clotildeBinary)
+	hooks, _ := settings["hooks"].(map[string]any)
+	if !force && state != hooksMissing {
+		return hooks, state, nil
+	}
 
 	// Merge hooks into settings, preserving non-clotilde hooks
-	var hooks map[string]any
-	if existingHooks, ok := settings["hooks"].(map[string]any); ok {
-		hooks = existingHooks
-	} else {
+	if hooks == nil {
 		hooks = make(map[string]any)
 	}
-
-	mergeHookType := func(key string, matchers []claude.HookMatcher) {
-		existing := hooks[key]
-		merged := stripAndAppendHooks(existing, matchers, clotildeBinary)
+	events := managedHookEvents
+	if force {
+		// Every event in the file, so hooks clotilde no longer installs go too
+		events = slices.Sorted(maps.Keys(hooks))
+		for _, key := range managedHookEvents {
+			if !slices.Contains(events, key) {
+				events = append(events, key)
+			}
+		}
+	}
+	for _, key := range events {
+		merged := stripAndAppendHooks(hooks[key], generated[key], clotildeBinary)
 		if len(merged) > 0 {
 			hooks[key] = merged
 		} else {
 			delete(hooks, key)
 		}
 	}
-
-	mergeHookType("SessionStart", hookConfig.SessionStart)
-	mergeHookType("Stop", hookConfig.Stop)
-	mergeHookType("Notification", hookConfig.Notification)
-	mergeHookType("PreToolUse", hookConfig.PreToolUse)
-	mergeHookType("PostToolUse", hookConfig.PostToolUse)
 	settings["hooks"] = hooks
 
 	if err := util.WriteJSON(settingsPath, settings); err != nil {
-		return nil, fmt.Errorf("failed to write settings: %w", err)
+		return nil, state, fmt.Errorf("failed to write settings: %w", err)
 	}
 
-	return hooks, nil
+	return hooks, state, nil
+}
+
+// hookStateOf compares the clotilde hooks in settings, by event, matcher and
+// command, with the generated ones.
+func hookStateOf(settings map[string]any, generated map[string][]claude.HookMatcher, clotildeBinary string) hookInstallState {
+	var want, have []string
+	for event, matchers := range generated {
+		for _, m := range matchers {
+			for _, h := range m.Hooks {
+				want = append(want, event+"\x00"+m.Matcher+"\x00"+h.Command)
+			}
+		}
+	}
+
+	working := false
+	hooks, _ := settings["hooks"].(map[string]any)
+	for event, value := range hooks {
+		matchers, _ := value.([]any)
+		for _, item := range matchers {
+			matcher, _ := item.(map[string]any)
+			pattern, _ := matcher["matcher"].(string)
+			hookList, _ := matcher["hooks"].([]any)
+			for _, h := range hookList {
+				hook, _ := h.(map[string]any)
+				command, _ := hook["command"].(string)
+				if command == "" || !isClotildeHookCmd(command, clotildeBinary+" ", clotildeBinary) {
+					continue
+				}
+				have = append(have, event+"\x00"+pattern+"\x00"+command)
+				working = working || binaryExists(strings.Fields(command)[0])
+			}
+		}
+	}
+
+	slices.Sort(want)
+	slices.Sort(have)
+	switch {
+	case slices.Equal(want, have):
+		return hooksCurrent
+	case working:
+		return hooksOutdated
+	default:
+		return hooksMissing
+	}
 }
 
 // stripAndAppendHooks removes clotilde hooks from existing matchers, then
@@ -206,7 +388,9 @@ func isClotildeHookCmd(cmd, currentPrefix, currentBinary string) bool {
 	return false
 }
 
-func setupHooks(projectRoot, clotildeBinary, settingsFile string) error {
+// setupHooks installs clotilde's hooks in the project's .claude/settingsFile
+// and reports what it did. See mergeHooksIntoSettings for force.
+func setupHooks(projectRoot, clotildeBinary, settingsFile string, force bool) error {
 	claudeDir := filepath.Join(projectRoot, ".claude")
 	settingsPath := filepath.Join(claudeDir, settingsFile)
 
@@ -215,14 +399,22 @@ func setupHooks(projectRoot, clotildeBinary, settingsFile string) error {
 		return fmt.Errorf("failed to create .claude directory: %w", err)
 	}
 
-	hooks, err := mergeHooksIntoSettings(settingsPath, clotildeBinary)
+	hooks, state, err := mergeHooksIntoSettings(settingsPath, clotildeBinary, force)
 	if err != nil {
 		return err
 	}
 
-	// Pretty print the hooks for user confirmation
-	hooksJSON, _ := json.MarshalIndent(hooks, "  ", "  ")
-	fmt.Printf("  Added hooks to .claude/%s:\n  %s\n", settingsFile, string(hooksJSON))
+	switch {
+	case force || state == hooksMissing:
+		// Pretty print the hooks for user confirmation
+		hooksJSON, _ := json.MarshalIndent(hooks, "  ", "  ")
+		fmt.Printf("  Added hooks to .claude/%s:\n  %s\n", settingsFile, string(hooksJSON))
+	case state == hooksCurrent:
+		fmt.Printf("  Hooks in .claude/%s are already up to date.\n", settingsFile)
+	default:
+		fmt.Println("  " + ui.Warning(fmt.Sprintf("Hooks in .claude/%s are from an older clotilde and were left as they are.", settingsFile)))
+		fmt.Println("  Run 'clotilde init --hooks-only --force' to rewrite them.")
+	}
 
 	return nil
 }
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/ui"
+	"github.com/fgrehm/clotilde/internal/util"
+)
+
+// sessionsPath is the session data directory relative to the project root.
+// Only sessions are ignored; config.json may hold profiles meant to be shared.
+var sessionsPath = filepath.Join(config.ClotildeDir, config.SessionsDir)
+
+// gitignoreResult describes what ensureSessionsIgnored found and changed.
+type gitignoreResult struct {
+	File    string   // File the ignore entry was appended to ("" if nothing was written)
+	Tracked []string // Session files already tracked by git
+}
+
+// ensureSessionsIgnored keeps session data out of git for the project at projectRoot.
+// When shared is true the entry goes into the project's .gitignore (committed, affects
+// the team); otherwise into .git/info/exclude (local only). Nothing is written when the
+// sessions folder is already ignored. Returns nil if projectRoot is not in a git repo
+// or git is unavailable.
+func ensureSessionsIgnored(projectRoot string, shared bool) (*gitignoreResult, error) {
+	git := func(args ...string) *exec.Cmd {
+		return exec.Command("git", append([]string{"-C", projectRoot}, args...)...)
+	}
+
+	topLevel, err := git("rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return nil, nil //nolint:nilnil // not a git repository, nothing to do
+	}
+
+	result := &gitignoreResult{}
+
+	tracked, err := git("ls-files", "--", sessionsPath).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tracked files: %w", err)
+	}
+	for line := range strings.SplitSeq(strings.TrimSpace(string(tracked)), "\n") {
+		if line != "" {
+			result.Tracked = append(result.Tracked, line)
+		}
+	}
+
+	// Exit status 0 means some existing rule already ignores the folder
+	if git("check-ignore", "--no-index", "-q", sessionsPath+"/").Run() == nil {
+		return result, nil
+	}
+
+	var file, pattern string
+	if shared {
+		file = filepath.Join(projectRoot, ".gitignore")
+		pattern = "/" + filepath.ToSlash(sessionsPath) + "/"
+	} else {
+		out, err := git("rev-parse", "--git-path", "info/exclude").Output()
+		if err != nil {
+			return nil, fmt.Errorf("failed to locate .git/info/exclude: %w", err)
+		}
+		file = strings.TrimSpace(string(out))
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(projectRoot, file)
+		}
+
+		// Exclude patterns are relative to the repository root, which git
+		// reports with symlinks resolved
+		resolved, err := filepath.EvalSymlinks(projectRoot)
+		if err != nil {
+			return nil, err
+		}
+		rel, err := filepath.Rel(strings.TrimSpace(string(topLevel)), resolved)
+		if err != nil {
+			return nil, err
+		}
+		pattern = "/" + filepath.ToSlash(filepath.Join(rel, sessionsPath)) + "/"
+	}
+
+	if err := appendIgnoreEntry(file, pattern); err != nil {
+		return nil, err
+	}
+	result.File = file
+	return result, nil
+}
+
+// appendIgnoreEntry appends a commented ignore pattern to file, creating it if needed.
+func appendIgnoreEntry(file, pattern string) error {
+	existing, err := os.ReadFile(file)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", file, err)
+	}
+
+	var b strings.Builder
+	b.Write(existing)
+	if len(existing) > 0 && !strings.HasSuffix(string(existing), "\n") {
+		b.WriteString("\n")
+	}
+	b.WriteString("# clotilde session data\n")
+	b.WriteString(pattern + "\n")
+
+	if err := util.WriteFile(file, []byte(b.String())); err != nil {
+		return fmt.Errorf("failed to update %s: %w", file, err)
+	}
+	return nil
+}
+
+// reportGitignore runs ensureSessionsIgnored and tells the user what changed,
+// including how to stop tracking session files that were committed earlier.
+func reportGitignore(cmd *cobra.Command, projectRoot string, shared bool) error {
+	result, err := ensureSessionsIgnored(projectRoot, shared)
+	if err != nil || result == nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	if result.File != "" {
+		display := result.File
+		if rel, err := filepath.Rel(projectRoot, result.File); err == nil && !strings.HasPrefix(rel, "..") {
+			display = rel
+		}
+		_, _ = fmt.Fprintf(out, "Added %s/ to %s\n", filepath.ToSlash(sessionsPath), display)
+	}
+
+	if len(result.Tracked) > 0 {
+		_, _ = fmt.Fprintln(out, ui.Warning(fmt.Sprintf("%d session file(s) are already tracked by git; ignore rules do not apply to them", len(result.Tracked))))
+		_, _ = fmt.Fprintln(out, "  To stop tracking them (files stay on disk):")
+		_, _ = fmt.Fprintf(out, "    git rm -r --cached %s\n", filepath.ToSlash(sessionsPath))
+		_, _ = fmt.Fprintln(out, "    git commit -m \"Stop tracking clotilde session data\"")
+	}
+	return nil
+}
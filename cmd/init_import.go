@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+
+	"github.com/fgrehm/clotilde/internal/claude"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/ui"
+	"github.com/fgrehm/clotilde/internal/util"
+)
+
+// Modes for init --import.
+const (
+	importAsk  = "ask"  // List the conversations and ask which to import
+	importAll  = "all"  // Import every listed conversation
+	importNone = "none" // Don't look for conversations
+)
+
+// importListLimit is how many of the most recent conversations init offers.
+const importListLimit = 10
+
+// importNameWords is how many words of a conversation's first prompt make up
+// the name of the session it's imported as.
+const importNameWords = 5
+
+// offerConversationImport lists the project's Claude Code conversations no
+// session knows about and imports the ones picked as named sessions, so work
+// done before clotilde isn't invisible to it.
+func offerConversationImport(out io.Writer, clotildeRoot, mode string) error {
+	if mode == importNone {
+		return nil
+	}
+
+	homeDir, err := util.HomeDir()
+	if err != nil {
+		return fmt.Errorf("could not determine home directory: %w", err)
+	}
+	store := session.NewFileStore(clotildeRoot)
+	sessions, err := store.List()
+	if err != nil {
+		return fmt.Errorf("failed to list sessions: %w", err)
+	}
+	known := map[string]bool{}
+	for _, sess := range sessions {
+		known[sess.Metadata.SessionID] = true
+		for _, prev := range sess.Metadata.PreviousSessions {
+			known[prev.SessionID] = true
+		}
+	}
+
+	conversations, err := claude.FindConversations(homeDir, clotildeRoot, known)
+	if err != nil || len(conversations) == 0 {
+		return err
+	}
+	more := len(conversations) - importListLimit
+	conversations = conversations[:min(len(conversations), importListLimit)]
+
+	_, _ = fmt.Fprintf(out, "\nFound %d Claude Code conversation(s) in this project that aren't sessions yet:\n", len(conversations))
+	for i, c := range conversations {
+		_, _ = fmt.Fprintf(out, "  %2d. %s  %s\n", i+1, c.LastActive.Local().Format("2006-01-02 15:04"), c.FirstPrompt)
+	}
+	if more > 0 {
+		_, _ = fmt.Fprintf(out, "  (%d older not shown)\n", more)
+	}
+
+	picked := conversations
+	if mode == importAsk {
+		if !isatty.IsTerminal(os.Stdin.Fd()) {
+			_, _ = fmt.Fprintln(out, ui.Info("Import them with 'clotilde init --import all'"))
+			return nil
+		}
+		_, _ = fmt.Fprint(out, "Import which as sessions? (e.g. 1,3 or 'all'; Enter for none): ")
+		response, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && response == "" {
+			return fmt.Errorf("failed to read input: %w", err)
+		}
+		if picked, err = pickConversations(conversations, response); err != nil {
+			return err
+		}
+	}
+
+	return importConversations(out, clotildeRoot, store, sessions, picked)
+}
+
+// pickConversations returns the conversations chosen by answer: "all", or
+// comma- or space-separated numbers from the list.
+func pickConversations(conversations []claude.Conversation, answer string) ([]claude.Conversation, error) {
+	answer = strings.TrimSpace(strings.ToLower(answer))
+	if answer == "all" {
+		return conversations, nil
+	}
+
+	var picked []claude.Conversation
+	for _, field := range strings.FieldsFunc(answer, func(r rune) bool { return r == ',' || r == ' ' }) {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 1 || n > len(conversations) {
+			return nil, fmt.Errorf("invalid choice %q (pick numbers from 1 to %d)", field, len(conversations))
+		}
+		if c := conversations[n-1]; !slices.Contains(picked, c) {
+			picked = append(picked, c)
+		}
+	}
+	return picked, nil
+}
+
+// importConversations creates a session for each conversation, named after
+// its first prompt.
+func importConversations(out io.Writer, clotildeRoot string, store session.Store, sessions []*session.Session, conversations []claude.Conversation) error {
+	rules, err := session.LoadNameRules(clotildeRoot)
+	if err != nil {
+		return fmt.Errorf("invalid naming config: %w", err)
+	}
+	existing := make([]string, len(sessions))
+	for i, sess := range sessions {
+		existing[i] = sess.Name
+	}
+
+	for _, c := range conversations {
+		name := importedSessionName(c, existing, rules)
+		existing = append(existing, name)
+
+		sess := session.NewSession(name, c.SessionID)
+		sess.Metadata.TranscriptPath = c.Path
+		if !c.Started.IsZero() {
+			sess.Metadata.Created = c.Started
+		}
+		sess.Metadata.LastAccessed = c.LastActive
+		if err := store.Create(sess); err != nil {
+			return fmt.Errorf("failed to import conversation %s: %w", c.SessionID, err)
+		}
+		_, _ = fmt.Fprintln(out, ui.Success(fmt.Sprintf("Imported '%s' (%s)", name, c.SessionID)))
+	}
+	if len(conversations) > 0 {
+		_, _ = fmt.Fprintln(out, ui.Info("Resume them with 'clotilde resume <name>'"))
+	}
+	return nil
+}
+
+// importedSessionName names a session after the first words of its first
+// prompt, or after its session ID when they don't make a valid name.
+func importedSessionName(c claude.Conversation, existing []string, rules session.NameRules) string {
+	words := strings.Fields(c.FirstPrompt)
+	base := util.SanitizeBranchName(strings.Join(words[:min(len(words), importNameWords)], "-"))
+	if len(base) > rules.MaxLength || rules.Validate(base) != nil {
+		base = "imported-" + c.SessionID[:min(len(c.SessionID), 8)]
+	}
+	if slices.Contains(existing, base) {
+		return util.NumberedName(base, existing, rules.MaxLength)
+	}
+	return base
+}
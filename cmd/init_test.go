@@ -1,17 +1,24 @@
 package cmd_test
 
 import (
+	"bytes"
 	"encoding/json"
 	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	"github.com/fgrehm/clotilde/cmd"
+	"github.com/fgrehm/clotilde/internal/claude"
 	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/session"
 	"github.com/fgrehm/clotilde/internal/testutil"
+	"github.com/fgrehm/clotilde/internal/util"
 )
 
 var _ = Describe("Init Command", func() {
@@ -330,4 +337,245 @@ var _ = Describe("Init Command", func() {
 		sessionStart := hooks["SessionStart"].([]any)
 		Expect(sessionStart).To(HaveLen(1))
 	})
+
+	Describe("reinstalling hooks", func() {
+		var settingsPath string
+
+		BeforeEach(func() {
+			settingsPath = filepath.Join(tempDir, ".claude", "settings.local.json")
+		})
+
+		run := func(args ...string) {
+			rootCmd := cmd.NewRootCmd()
+			rootCmd.SetOut(io.Discard)
+			rootCmd.SetErr(io.Discard)
+			rootCmd.SetArgs(append([]string{"init", "--import", "none"}, args...))
+			Expect(rootCmd.Execute()).To(Succeed())
+		}
+
+		// writeMatcherHooks writes hooks the way older clotilde versions did:
+		// one SessionStart entry per source, running an existing binary.
+		writeMatcherHooks := func() string {
+			binary := filepath.Join(tempDir, "old-bin", "clotilde")
+			Expect(os.MkdirAll(filepath.Dir(binary), 0o755)).To(Succeed())
+			Expect(os.WriteFile(binary, []byte("#!/bin/sh\n"), 0o755)).To(Succeed())
+			content := `{"hooks": {"SessionStart": [
+				{"matcher": "startup", "hooks": [{"type": "command", "command": "` + binary + ` hook sessionstart"}]},
+				{"matcher": "resume", "hooks": [{"type": "command", "command": "` + binary + ` hook sessionstart"}]}
+			]}}`
+			Expect(os.MkdirAll(filepath.Dir(settingsPath), 0o755)).To(Succeed())
+			Expect(os.WriteFile(settingsPath, []byte(content), 0o644)).To(Succeed())
+			return content
+		}
+
+		sessionStart := func() []any {
+			var settings map[string]any
+			Expect(util.ReadJSON(settingsPath, &settings)).To(Succeed())
+			return settings["hooks"].(map[string]any)["SessionStart"].([]any)
+		}
+
+		It("installs only the hooks with --hooks-only", func() {
+			run("--hooks-only")
+
+			Expect(sessionStart()).To(HaveLen(1))
+			Expect(filepath.Join(tempDir, config.ClotildeDir)).NotTo(BeADirectory())
+		})
+
+		It("leaves hooks that are already up to date untouched", func() {
+			run("--hooks-only")
+			// Reformatted by hand; an up-to-date file isn't rewritten
+			var settings map[string]any
+			Expect(util.ReadJSON(settingsPath, &settings)).To(Succeed())
+			compact, err := json.Marshal(settings)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(os.WriteFile(settingsPath, compact, 0o644)).To(Succeed())
+
+			run("--hooks-only")
+			content, err := os.ReadFile(settingsPath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(content).To(Equal(compact))
+		})
+
+		It("keeps hooks in an older format unless --force is given", func() {
+			original := writeMatcherHooks()
+
+			run("--hooks-only")
+			content, err := os.ReadFile(settingsPath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal(original))
+
+			run("--hooks-only", "--force")
+			entries := sessionStart()
+			Expect(entries).To(HaveLen(1))
+			entry := entries[0].(map[string]any)
+			Expect(entry).NotTo(HaveKey("matcher"))
+			self, err := os.Executable()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entry["hooks"].([]any)[0].(map[string]any)["command"]).To(Equal(self + " hook sessionstart"))
+		})
+
+		It("removes clotilde hooks from events it no longer uses with --force", func() {
+			content := `{"hooks": {"SessionEnd": [{"hooks": [
+				{"type": "command", "command": "/gone/clotilde hook sessionend"},
+				{"type": "command", "command": "/some/other/tool.sh"}
+			]}]}}`
+			Expect(os.MkdirAll(filepath.Dir(settingsPath), 0o755)).To(Succeed())
+			Expect(os.WriteFile(settingsPath, []byte(content), 0o644)).To(Succeed())
+
+			run("--hooks-only", "--force")
+			var settings map[string]any
+			Expect(util.ReadJSON(settingsPath, &settings)).To(Succeed())
+			sessionEnd := settings["hooks"].(map[string]any)["SessionEnd"].([]any)
+			Expect(sessionEnd[0].(map[string]any)["hooks"]).To(HaveLen(1))
+			Expect(sessionStart()).To(HaveLen(1))
+		})
+	})
+
+	Describe("git ignore management", func() {
+		git := func(args ...string) string {
+			c := exec.Command("git", append([]string{"-C", tempDir}, args...)...)
+			c.Env = append(os.Environ(),
+				"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+				"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+			out, err := c.CombinedOutput()
+			Expect(err).NotTo(HaveOccurred(), string(out))
+			return string(out)
+		}
+
+		runInit := func(args ...string) string {
+			var out bytes.Buffer
+			rootCmd := cmd.NewRootCmd()
+			rootCmd.SetOut(&out)
+			rootCmd.SetErr(io.Discard)
+			rootCmd.SetArgs(append([]string{"init"}, args...))
+			Expect(rootCmd.Execute()).To(Succeed())
+			return out.String()
+		}
+
+		BeforeEach(func() {
+			git("init", "-q")
+		})
+
+		It("adds session data to .git/info/exclude by default", func() {
+			out := runInit()
+			Expect(out).To(ContainSubstring("Added .claude/clotilde/sessions/ to .git/info/exclude"))
+
+			content, err := os.ReadFile(filepath.Join(tempDir, ".git", "info", "exclude"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(ContainSubstring("/.claude/clotilde/sessions/\n"))
+		})
+
+		It("adds session data to .gitignore with --global", func() {
+			Expect(os.WriteFile(filepath.Join(tempDir, ".gitignore"), []byte("node_modules"), 0o644)).To(Succeed())
+
+			runInit("--global")
+
+			content, err := os.ReadFile(filepath.Join(tempDir, ".gitignore"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal("node_modules\n# clotilde session data\n/.claude/clotilde/sessions/\n"))
+		})
+
+		It("does not add the entry twice", func() {
+			runInit()
+			out := runInit()
+			Expect(out).NotTo(ContainSubstring("Added"))
+
+			content, err := os.ReadFile(filepath.Join(tempDir, ".git", "info", "exclude"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(strings.Count(string(content), "/.claude/clotilde/sessions/")).To(Equal(1))
+		})
+
+		It("warns with remediation when session data is already tracked", func() {
+			sessionDir := filepath.Join(tempDir, config.ClotildeDir, config.SessionsDir, "tracked")
+			Expect(os.MkdirAll(sessionDir, 0o755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(sessionDir, "metadata.json"), []byte("{}"), 0o644)).To(Succeed())
+			git("add", ".claude/clotilde/sessions")
+			git("commit", "-q", "-m", "oops")
+
+			out := runInit()
+			Expect(out).To(ContainSubstring("1 session file(s) are already tracked by git"))
+			Expect(out).To(ContainSubstring("git rm -r --cached .claude/clotilde/sessions"))
+		})
+
+		It("leaves git alone with --gitignore=false", func() {
+			out := runInit("--gitignore=false")
+			Expect(out).NotTo(ContainSubstring("Added"))
+
+			content, err := os.ReadFile(filepath.Join(tempDir, ".git", "info", "exclude"))
+			if err == nil {
+				Expect(string(content)).NotTo(ContainSubstring("clotilde"))
+			}
+		})
+	})
+
+	Describe("importing existing conversations", func() {
+		var (
+			clotildeRoot string
+			dataDir      string
+		)
+
+		runInit := func(args ...string) string {
+			var out bytes.Buffer
+			rootCmd := cmd.NewRootCmd()
+			rootCmd.SetOut(&out)
+			rootCmd.SetErr(io.Discard)
+			rootCmd.SetArgs(append([]string{"init", "--gitignore=false"}, args...))
+			Expect(rootCmd.Execute()).To(Succeed())
+			return out.String()
+		}
+
+		BeforeEach(func() {
+			homeDir := GinkgoT().TempDir()
+			GinkgoT().Setenv("HOME", homeDir)
+			GinkgoT().Setenv("XDG_CONFIG_HOME", filepath.Join(homeDir, ".config"))
+			GinkgoT().Setenv("CLAUDE_CONFIG_DIR", "")
+
+			cwd, err := os.Getwd()
+			Expect(err).NotTo(HaveOccurred())
+			clotildeRoot = filepath.Join(cwd, config.ClotildeDir)
+			dataDir = claude.ProjectDataDir(homeDir, clotildeRoot)
+			Expect(os.MkdirAll(dataDir, 0o755)).To(Succeed())
+			transcript := `{"type":"user","timestamp":"2026-01-02T10:00:00Z","message":{"content":"Fix the login redirect bug please"}}` + "\n"
+			Expect(os.WriteFile(filepath.Join(dataDir, "conv-uuid.jsonl"), []byte(transcript), 0o644)).To(Succeed())
+		})
+
+		It("lists them and suggests --import all without a terminal", func() {
+			out := runInit()
+			Expect(out).To(ContainSubstring("Found 1 Claude Code conversation(s)"))
+			Expect(out).To(ContainSubstring("Fix the login redirect bug please"))
+			Expect(out).To(ContainSubstring("clotilde init --import all"))
+
+			sessions, err := session.NewFileStore(clotildeRoot).List()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sessions).To(BeEmpty())
+		})
+
+		It("imports them as sessions named after the first prompt with --import all", func() {
+			out := runInit("--import", "all")
+			Expect(out).To(ContainSubstring("Imported 'fix-the-login-redirect-bug'"))
+
+			sess, err := session.NewFileStore(clotildeRoot).Get("fix-the-login-redirect-bug")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sess.Metadata.SessionID).To(Equal("conv-uuid"))
+			Expect(sess.Metadata.TranscriptPath).To(Equal(filepath.Join(dataDir, "conv-uuid.jsonl")))
+			Expect(sess.Metadata.Created).To(Equal(time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)))
+
+			// Imported conversations aren't offered again
+			out = runInit("--import", "all")
+			Expect(out).NotTo(ContainSubstring("Found"))
+		})
+
+		It("skips the check with --import none", func() {
+			out := runInit("--import", "none")
+			Expect(out).NotTo(ContainSubstring("Found"))
+		})
+
+		It("rejects an unknown --import mode", func() {
+			rootCmd := cmd.NewRootCmd()
+			rootCmd.SetOut(io.Discard)
+			rootCmd.SetErr(io.Discard)
+			rootCmd.SetArgs([]string{"init", "--import", "some"})
+			Expect(rootCmd.Execute()).To(MatchError(ContainSubstring("invalid --import")))
+		})
+	})
 })
@@ -2,156 +2,301 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/fgrehm/clotilde/internal/claude"
 	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/crypt"
+	"github.com/fgrehm/clotilde/internal/errs"
+	"github.com/fgrehm/clotilde/internal/events"
 	"github.com/fgrehm/clotilde/internal/outputstyle"
 	"github.com/fgrehm/clotilde/internal/session"
 	"github.com/fgrehm/clotilde/internal/util"
 )
 
-var inspectCmd = &cobra.Command{
-	Use:     "inspect <name>",
-	Aliases: []string{"show", "info"},
-	Short:   "Show detailed information about a session",
-	Long: `Display detailed information about a session including metadata,
-files present, settings, context sources, and Claude Code data status.`,
-	Args:              cobra.ExactArgs(1),
-	ValidArgsFunction: sessionNameCompletion,
-	RunE: func(cmd *cobra.Command, args []string) error {
-		name := args[0]
-
-		// Find clotilde root
-		clotildeRoot, err := config.FindClotildeRoot()
-		if err != nil {
-			return fmt.Errorf("no sessions found (create one with 'clotilde start <name>')")
-		}
-
-		// Create store
-		store := session.NewFileStore(clotildeRoot)
+// inspectFiles are the raw session files 'inspect --cat' prints.
+var inspectFiles = []string{"settings", "prompt", "context", "metadata"}
 
-		// Load session
-		sess, err := store.Get(name)
-		if err != nil {
-			return fmt.Errorf("session '%s' not found", name)
-		}
+func newInspectCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "inspect <name>",
+		Aliases: []string{"show", "info"},
+		Short:   "Show detailed information about a session",
+		Long: `Display detailed information about a session including metadata,
+files present, settings, context sources, and Claude Code data status.
 
-		sessionDir := config.GetSessionDir(clotildeRoot, name)
+With --cat, print one of the session's files as-is instead, for piping to
+jq or diff: settings (settings.json), prompt (the custom output style),
+context, or metadata (metadata.json).
 
-		// Print metadata
-		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Session: %s\n", sess.Name)
-		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "UUID: %s\n", sess.Metadata.SessionID)
-		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Created: %s\n", sess.Metadata.Created.Format(time.RFC3339))
-		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Last Accessed: %s\n", sess.Metadata.LastAccessed.Format(time.RFC3339))
+With --porcelain, print "key<TAB>value" lines in a fixed order that stays
+stable between versions, for scripts (see docs/porcelain.md).`,
+		Example: `  clotilde inspect auth-feature
+  clotilde inspect auth-feature --cat settings | jq .permissions`,
+		Annotations:       readOnly(),
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: sessionNameCompletion,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
 
-		// Try to extract last model from transcript
-		if sess.Metadata.TranscriptPath != "" {
-			if lastModel := claude.ExtractLastModel(sess.Metadata.TranscriptPath); lastModel != "" {
-				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Last Model Used: %s\n", lastModel)
+			// Find clotilde root
+			clotildeRoot, err := findClotildeRoot()
+			if err != nil {
+				if projectRootOverride != "" {
+					return err
+				}
+				return errNoSessions()
 			}
-		}
 
-		if sess.Metadata.IsForkedSession {
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Forked from: %s\n", sess.Metadata.ParentSession)
-		}
+			// Create store
+			store := session.NewFileStore(clotildeRoot)
 
-		// Show previous session IDs (from /clear operations, and defensively from /compact)
-		if len(sess.Metadata.PreviousSessionIDs) > 0 {
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Previous UUIDs: %d\n", len(sess.Metadata.PreviousSessionIDs))
-			for i, prevID := range sess.Metadata.PreviousSessionIDs {
-				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  %d. %s\n", i+1, prevID)
+			// Load session
+			sess, err := store.Get(name)
+			if err != nil {
+				return errs.NotFound("session '%s' not found", name)
 			}
-		}
 
-		_, _ = fmt.Fprintln(cmd.OutOrStdout())
+			if file, _ := cmd.Flags().GetString("cat"); file != "" {
+				return catSessionFile(cmd.OutOrStdout(), clotildeRoot, sess, file)
+			}
+
+			sessionDir := config.GetSessionDir(clotildeRoot, name)
 
-		// Show files present
-		_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Files:")
-		files := []string{"metadata.json", "settings.json"}
-		for _, file := range files {
-			path := filepath.Join(sessionDir, file)
-			if util.FileExists(path) {
-				info, err := os.Stat(path)
+			// Use stored transcript path if available, otherwise compute it
+			transcriptPath := sess.Metadata.TranscriptPath
+			if transcriptPath == "" {
+				// Fall back to computing the path
+				homeDir, err := util.HomeDir()
 				if err == nil {
-					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  ✓ %s (%s)\n", file, util.FormatSize(info.Size()))
+					transcriptPath = claude.TranscriptPath(homeDir, clotildeRoot, sess.Metadata.SessionID)
 				}
-			} else {
-				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  - %s\n", file)
 			}
-		}
 
-		_, _ = fmt.Fprintln(cmd.OutOrStdout())
+			if isPorcelain(cmd) {
+				writePorcelainInspect(cmd.OutOrStdout(), clotildeRoot, sess, store, transcriptPath)
+				return nil
+			}
 
-		// Show settings summary
-		settings, err := store.LoadSettings(name)
-		if err == nil && settings != nil {
-			hasSettings := settings.Model != "" ||
-				settings.OutputStyle != "" ||
-				len(settings.Permissions.Allow) > 0 ||
-				len(settings.Permissions.Deny) > 0
+			// Print metadata
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Session: %s\n", sess.Name)
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "UUID: %s\n", sess.Metadata.SessionID)
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Status: %s\n", sess.Status())
+			if sess.Metadata.Starred {
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Starred: yes")
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Created: %s\n", sess.Metadata.Created.Format(time.RFC3339))
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Last Accessed: %s\n", sess.Metadata.LastAccessed.Format(time.RFC3339))
+			if !sess.Metadata.ExpiresAt.IsZero() {
+				expires := sess.Metadata.ExpiresAt.Format(time.RFC3339)
+				if sess.IsExpired(time.Now()) {
+					expires += " (expired)"
+				}
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Expires: %s\n", expires)
+			}
+			if exit := sess.Metadata.LastExit; exit != nil {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Last Exit: %s %s\n", exit.Summary(), util.FormatRelativeTime(exit.At))
+				if exit.Crashed() {
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  (see 'clotilde last-error %s')\n", sess.Name)
+				}
+			}
 
-			if hasSettings {
-				_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Settings:")
-				if settings.Model != "" {
-					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  Model: %s\n", settings.Model)
+			// Try to extract last model from transcript
+			if sess.Metadata.TranscriptPath != "" {
+				if lastModel, _ := claude.CachedModelAndLastTime(claude.StatsCachePath(clotildeRoot, sess.Name), sess.Metadata.TranscriptPath); lastModel != "" {
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Last Model Used: %s\n", lastModel)
 				}
-				if settings.OutputStyle != "" {
-					if outputstyle.IsBuiltIn(settings.OutputStyle) {
-						_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  Output Style: %s (built-in)\n", settings.OutputStyle)
-					} else {
-						_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  Output Style: %s (custom)\n", settings.OutputStyle)
+			}
+
+			if sess.Metadata.IsForkedSession {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Forked from: %s\n", sess.Metadata.ParentSession)
+			}
+			if len(sess.Metadata.Launcher) > 0 {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Launcher: %s\n", util.ShellJoin(sess.Metadata.Launcher))
+			}
+
+			if sessions, err := store.List(); err == nil {
+				if children := forkChildren(sessions, sess.Name); len(children) > 0 {
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Forks: %d\n", len(children))
+					for _, child := range children {
+						_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  - %s (last accessed %s)\n", child.Name, util.FormatRelativeTime(child.Metadata.LastAccessed))
 					}
 				}
-				if len(settings.Permissions.Allow) > 0 {
-					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  Allowed tools: %d\n", len(settings.Permissions.Allow))
+			}
+
+			// Show previous session IDs (from /clear operations, and defensively from /compact)
+			if len(sess.Metadata.PreviousSessions) > 0 {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Previous UUIDs: %d\n", len(sess.Metadata.PreviousSessions))
+				for i, prev := range sess.Metadata.PreviousSessions {
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  %d. %s%s\n", i+1, prev.SessionID, formatPreviousSession(prev))
+				}
+			}
+
+			_, _ = fmt.Fprintln(cmd.OutOrStdout())
+
+			// Show files present
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Files:")
+			files := []string{"metadata.json", "settings.json"}
+			for _, file := range files {
+				path := filepath.Join(sessionDir, file)
+				if util.FileExists(path) {
+					info, err := os.Stat(path)
+					if err == nil {
+						_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  ✓ %s (%s)\n", file, util.FormatSize(info.Size()))
+					}
+				} else {
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  - %s\n", file)
 				}
-				if len(settings.Permissions.Deny) > 0 {
-					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  Denied tools: %d\n", len(settings.Permissions.Deny))
+			}
+
+			_, _ = fmt.Fprintln(cmd.OutOrStdout())
+
+			// Show settings summary
+			settings, err := store.LoadSettings(name)
+			if err == nil && settings != nil {
+				hasSettings := settings.Model != "" ||
+					settings.OutputStyle != "" ||
+					len(settings.Permissions.Allow) > 0 ||
+					len(settings.Permissions.Deny) > 0
+
+				if hasSettings {
+					_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Settings:")
+					if settings.Model != "" {
+						_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  Model: %s\n", settings.Model)
+					}
+					if settings.OutputStyle != "" {
+						_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  Output Style: %s (%s)\n", settings.OutputStyle, describeStyleSource(settings.OutputStyle, sess.Metadata.OutputStyleSource))
+					}
+					if len(settings.Permissions.Allow) > 0 {
+						_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  Allowed tools: %d\n", len(settings.Permissions.Allow))
+					}
+					if len(settings.Permissions.Deny) > 0 {
+						_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  Denied tools: %d\n", len(settings.Permissions.Deny))
+					}
+					_, _ = fmt.Fprintln(cmd.OutOrStdout())
 				}
-				_, _ = fmt.Fprintln(cmd.OutOrStdout())
 			}
-		}
 
-		// Show context sources
-		_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Context:")
+			// Show context sources
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Context:")
 
-		if sess.Metadata.Context != "" {
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  %s\n", sess.Metadata.Context)
-		} else {
-			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "  not set")
-		}
+			if sess.Metadata.Context != "" {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  %s\n", sess.Metadata.Context)
+			} else {
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), "  not set")
+			}
 
-		_, _ = fmt.Fprintln(cmd.OutOrStdout())
+			_, _ = fmt.Fprintln(cmd.OutOrStdout())
 
-		// Show Claude Code data status
-		_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Claude Code Data:")
+			// Show Claude Code data status
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Claude Code Data:")
 
-		// Use stored transcript path if available, otherwise compute it
-		transcriptPath := sess.Metadata.TranscriptPath
-		if transcriptPath == "" {
-			// Fall back to computing the path
-			homeDir, err := util.HomeDir()
-			if err == nil {
-				projectDir := claude.ProjectDir(clotildeRoot)
-				claudeProjectDir := filepath.Join(homeDir, ".claude", "projects", projectDir)
-				transcriptPath = filepath.Join(claudeProjectDir, sess.Metadata.SessionID+".jsonl")
+			if transcriptPath != "" && util.FileExists(transcriptPath) {
+				info, err := os.Stat(transcriptPath)
+				if err == nil {
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  Transcript: %s\n", util.FormatSize(info.Size()))
+				}
+			} else {
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), "  Transcript: not found")
 			}
-		}
 
-		if transcriptPath != "" && util.FileExists(transcriptPath) {
-			info, err := os.Stat(transcriptPath)
-			if err == nil {
-				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  Transcript: %s\n", util.FormatSize(info.Size()))
+			// Show what happened to the session, oldest first
+			sessions, _ := store.List()
+			log, _ := events.Read(clotildeRoot)
+			_, _ = fmt.Fprintln(cmd.OutOrStdout())
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "History:")
+			for _, entry := range sessionHistory(sess, sessions, log) {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  %s  %s\n", entry.Time.Local().Format(historyTimeFormat), entry.Text)
 			}
-		} else {
-			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "  Transcript: not found")
+
+			return nil
+		},
+	}
+	cmd.Flags().String("cat", "", "Print a raw session file: "+strings.Join(inspectFiles, ", "))
+	_ = cmd.RegisterFlagCompletionFunc("cat", cobra.FixedCompletions(inspectFiles, cobra.ShellCompDirectiveNoFileComp))
+	addPorcelainFlag(cmd)
+	cmd.MarkFlagsMutuallyExclusive("cat", "porcelain")
+	return cmd
+}
+
+// describeStyleSource says where a session's output style came from. Sessions
+// created before provenance was recorded only tell built-in styles apart.
+func describeStyleSource(style string, source *session.OutputStyleSource) string {
+	switch {
+	case source == nil && outputstyle.IsBuiltIn(style):
+		return "built-in"
+	case source == nil:
+		return "custom"
+	case source.Kind == session.OutputStyleBuiltin:
+		return "built-in"
+	case source.Kind == session.OutputStyleShared && source.Path == "":
+		return "shared, file not found"
+	case source.Kind == session.OutputStyleShared:
+		return "shared, " + source.Path
+	case source.Path != "":
+		return "custom, from " + source.Path
+	default:
+		return "custom, inline"
+	}
+}
+
+// catSessionFile writes one of the session's files to out unchanged. The
+// custom output style is decrypted when encryption is on; the context comes
+// from metadata and is empty when unset.
+func catSessionFile(out io.Writer, clotildeRoot string, sess *session.Session, file string) error {
+	sessionDir := config.GetSessionDir(clotildeRoot, sess.Name)
+
+	var data []byte
+	var err error
+	switch file {
+	case "metadata":
+		data, err = os.ReadFile(filepath.Join(sessionDir, "metadata.json"))
+	case "settings":
+		data, err = os.ReadFile(filepath.Join(sessionDir, "settings.json"))
+		if os.IsNotExist(err) {
+			return errs.NotFound("session '%s' has no settings.json", sess.Name)
+		}
+	case "prompt":
+		data, err = crypt.ReadFile(outputstyle.GetCustomStylePath(clotildeRoot, sess.Name))
+		if os.IsNotExist(err) {
+			return errs.NotFound("session '%s' has no custom output style", sess.Name)
 		}
+	case "context":
+		if sess.Metadata.Context != "" {
+			data = []byte(sess.Metadata.Context + "\n")
+		}
+	default:
+		return fmt.Errorf("invalid --cat '%s' (use %s)", file, strings.Join(inspectFiles, ", "))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	_, err = out.Write(data)
+	return err
+}
 
-		return nil
-	},
+// formatPreviousSession describes why and when a session ID was superseded,
+// e.g. " (clear, 2 hours ago, snapshot)". Empty for entries migrated from the
+// legacy ID list, which carry no details.
+func formatPreviousSession(prev session.PreviousSession) string {
+	var details []string
+	if prev.Reason != "" {
+		details = append(details, prev.Reason)
+	}
+	if !prev.SupersededAt.IsZero() {
+		details = append(details, util.FormatRelativeTime(prev.SupersededAt))
+	}
+	if prev.Detached != "" {
+		details = append(details, prev.Detached)
+	}
+	if len(details) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(details, ", ") + ")"
 }
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/fgrehm/clotilde/internal/events"
+	"github.com/fgrehm/clotilde/internal/session"
+)
+
+// historyEntry is one line of inspect's History section.
+type historyEntry struct {
+	Time  time.Time
+	Text  string
+	count int // Resumes collapsed into this entry, 0 for other entries
+}
+
+// historyTimeFormat is how inspect's History section shows times.
+const historyTimeFormat = "2006-01-02 15:04"
+
+// rotationEventWindow is how close a SessionStart hook firing must be to a
+// recorded /clear or /compact to be taken as the same event.
+const rotationEventWindow = time.Minute
+
+// sessionHistory reconstructs the lifecycle of sess, oldest first, from its
+// metadata, the forks among sessions, and the event log. The log is rotated,
+// so a long-lived session's older resumes and settings changes may be
+// missing. Consecutive resumes are collapsed into one entry.
+func sessionHistory(sess *session.Session, sessions []*session.Session, log []events.Event) []historyEntry {
+	var entries []historyEntry
+	add := func(at time.Time, format string, args ...any) {
+		entries = append(entries, historyEntry{Time: at, Text: fmt.Sprintf(format, args...)})
+	}
+
+	if sess.Metadata.IsForkedSession {
+		add(sess.Metadata.Created, "forked from %s", sess.Metadata.ParentSession)
+	} else {
+		add(sess.Metadata.Created, "created")
+	}
+
+	for _, prev := range sess.Metadata.PreviousSessions {
+		if !prev.SupersededAt.IsZero() {
+			add(prev.SupersededAt, "%s", rotationText(prev.Reason))
+		}
+	}
+
+	forks := map[string]bool{}
+	for _, s := range sessions {
+		if s.Metadata.IsForkedSession && s.Metadata.ParentSession == sess.Name {
+			forks[s.Name] = true
+			add(s.Metadata.Created, "forked to %s", s.Name)
+		}
+	}
+
+	if exit := sess.Metadata.LastExit; exit != nil && exit.Crashed() {
+		add(exit.At, "claude %s", exit.Summary())
+	}
+
+	for _, e := range log {
+		// Events from before a deleted session of the same name was recreated
+		if e.Time.Before(sess.Metadata.Created) {
+			continue
+		}
+		switch {
+		case e.Type == events.SessionForked && e.Parent == sess.Name && !forks[e.Session]:
+			forks[e.Session] = true
+			add(e.Time, "forked to %s (deleted since)", e.Session)
+		case e.Session != sess.Name:
+		case e.Type == events.SessionResumed:
+			entries = append(entries, historyEntry{Time: e.Time, Text: "resumed", count: 1})
+		case e.Type == events.SessionSettings:
+			add(e.Time, "settings changed")
+		case e.Type == events.SessionStatus && !routineStatusChange(e.From, e.Status):
+			add(e.Time, "status %s → %s", e.From, e.Status)
+		case e.Type == events.HookFired && (e.Source == session.RotationClear || e.Source == session.RotationCompact) && !recordedRotation(sess, e):
+			add(e.Time, "%s", rotationText(e.Source))
+		}
+	}
+
+	slices.SortStableFunc(entries, func(a, b historyEntry) int { return a.Time.Compare(b.Time) })
+	return collapseResumes(entries)
+}
+
+// rotationText describes a move to a new Claude Code session ID.
+func rotationText(reason string) string {
+	switch reason {
+	case session.RotationClear:
+		return "cleared (/clear)"
+	case session.RotationCompact:
+		return "compacted (/compact)"
+	default:
+		return "moved to a new Claude Code session"
+	}
+}
+
+// routineStatusChange reports whether a status change only reflects claude
+// starting or exiting, which every resume does.
+func routineStatusChange(from, to string) bool {
+	routine := []string{session.StatusActive, session.StatusIdle}
+	return slices.Contains(routine, from) && slices.Contains(routine, to)
+}
+
+// recordedRotation reports whether the hook event e is a /clear or /compact
+// already in the session's previous UUIDs.
+func recordedRotation(sess *session.Session, e events.Event) bool {
+	for _, prev := range sess.Metadata.PreviousSessions {
+		if prev.Reason == e.Source && prev.SupersededAt.Sub(e.Time).Abs() < rotationEventWindow {
+			return true
+		}
+	}
+	return false
+}
+
+// collapseResumes merges runs of resumes into one entry, at the first of them.
+func collapseResumes(entries []historyEntry) []historyEntry {
+	var collapsed []historyEntry
+	for _, entry := range entries {
+		if last := len(collapsed) - 1; entry.count > 0 && last >= 0 && collapsed[last].count > 0 {
+			collapsed[last].count++
+			collapsed[last].Text = fmt.Sprintf("resumed %d times (until %s)", collapsed[last].count, entry.Time.Local().Format(historyTimeFormat))
+			continue
+		}
+		collapsed = append(collapsed, entry)
+	}
+	return collapsed
+}
@@ -1,15 +1,20 @@
 package cmd_test
 
 import (
+	"bytes"
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	"github.com/fgrehm/clotilde/cmd"
 	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/events"
+	"github.com/fgrehm/clotilde/internal/outputstyle"
 	"github.com/fgrehm/clotilde/internal/session"
 	"github.com/fgrehm/clotilde/internal/testutil"
 )
@@ -104,6 +109,62 @@ var _ = Describe("Inspect Command", func() {
 		Expect(err).NotTo(HaveOccurred())
 	})
 
+	It("shows where the output style came from", func() {
+		inspect := func(name string) string {
+			var out bytes.Buffer
+			rootCmd := cmd.NewRootCmd()
+			rootCmd.SetOut(&out)
+			rootCmd.SetErr(io.Discard)
+			rootCmd.SetArgs([]string{"inspect", name})
+			Expect(rootCmd.Execute()).To(Succeed())
+			return out.String()
+		}
+		create := func(name, style string, source *session.OutputStyleSource) {
+			sess := session.NewSession(name, "uuid-"+name)
+			sess.Metadata.OutputStyleSource = source
+			Expect(store.Create(sess)).To(Succeed())
+			Expect(store.SaveSettings(name, &session.Settings{OutputStyle: style})).To(Succeed())
+		}
+
+		create("shared", "reviewer", &session.OutputStyleSource{Kind: session.OutputStyleShared, Name: "reviewer", Path: "/styles/reviewer.md"})
+		create("inline", "clotilde/inline", &session.OutputStyleSource{Kind: session.OutputStyleCustom})
+		create("from-file", "clotilde/from-file", &session.OutputStyleSource{Kind: session.OutputStyleCustom, Path: "/tmp/terse.md"})
+		create("legacy", "reviewer", nil)
+
+		Expect(inspect("shared")).To(ContainSubstring("Output Style: reviewer (shared, /styles/reviewer.md)"))
+		Expect(inspect("inline")).To(ContainSubstring("Output Style: clotilde/inline (custom, inline)"))
+		Expect(inspect("from-file")).To(ContainSubstring("Output Style: clotilde/from-file (custom, from /tmp/terse.md)"))
+		Expect(inspect("legacy")).To(ContainSubstring("Output Style: reviewer (custom)"))
+	})
+
+	It("shows the session's history from metadata and the event log", func() {
+		sess := session.NewSession("long-lived", "uuid-2")
+		sess.Metadata.Created = time.Now().Add(-3 * time.Hour)
+		sess.Metadata.PreviousSessions = []session.PreviousSession{
+			{SessionID: "uuid-1", Reason: session.RotationClear, SupersededAt: time.Now().Add(-time.Hour)},
+		}
+		Expect(store.Create(sess)).To(Succeed())
+		Expect(store.SaveSettings("long-lived", &session.Settings{Model: "sonnet"})).To(Succeed())
+		Expect(store.SaveSettings("long-lived", &session.Settings{Model: "opus"})).To(Succeed())
+		fork := session.NewSession("spin-off", "uuid-fork")
+		fork.Metadata.IsForkedSession = true
+		fork.Metadata.ParentSession = "long-lived"
+		Expect(store.Create(fork)).To(Succeed())
+		for range 3 {
+			events.Record(clotildeRoot, events.Event{Type: events.SessionResumed, Session: "long-lived"})
+		}
+
+		var out bytes.Buffer
+		rootCmd := cmd.NewRootCmd()
+		rootCmd.SetOut(&out)
+		rootCmd.SetErr(io.Discard)
+		rootCmd.SetArgs([]string{"inspect", "long-lived"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		history := out.String()[strings.Index(out.String(), "History:"):]
+		Expect(history).To(MatchRegexp(`(?s)created.*cleared \(/clear\).*settings changed.*forked to spin-off.*resumed 3 times`))
+	})
+
 	It("should show fork information", func() {
 		// Create parent
 		parent := session.NewSession("parent", "uuid-parent")
@@ -127,6 +188,27 @@ var _ = Describe("Inspect Command", func() {
 		Expect(err).NotTo(HaveOccurred())
 	})
 
+	It("should list forks of the session, most recently accessed first", func() {
+		parent := session.NewSession("parent", "uuid-parent")
+		Expect(store.Create(parent)).To(Succeed())
+		for name, age := range map[string]time.Duration{"old-fork": 3 * time.Hour, "new-fork": 0} {
+			fork := session.NewSession(name, "uuid-"+name)
+			fork.Metadata.IsForkedSession = true
+			fork.Metadata.ParentSession = "parent"
+			fork.Metadata.LastAccessed = time.Now().Add(-age)
+			Expect(store.Create(fork)).To(Succeed())
+		}
+
+		var out bytes.Buffer
+		rootCmd := cmd.NewRootCmd()
+		rootCmd.SetOut(&out)
+		rootCmd.SetErr(io.Discard)
+		rootCmd.SetArgs([]string{"inspect", "parent"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		Expect(out.String()).To(ContainSubstring("Forks: 2\n  - new-fork (last accessed just now)\n  - old-fork (last accessed 3 hours ago)\n"))
+	})
+
 	It("should show context information", func() {
 		// Create session with context
 		sess := session.NewSession("ctx-session", "uuid-ctx")
@@ -170,4 +252,51 @@ var _ = Describe("Inspect Command", func() {
 		err = rootCmd.Execute()
 		Expect(err).NotTo(HaveOccurred())
 	})
+
+	Describe("--cat", func() {
+		cat := func(name, file string) (string, error) {
+			var out bytes.Buffer
+			rootCmd := cmd.NewRootCmd()
+			rootCmd.SetOut(&out)
+			rootCmd.SetErr(io.Discard)
+			rootCmd.SetArgs([]string{"inspect", name, "--cat", file})
+			err := rootCmd.Execute()
+			return out.String(), err
+		}
+
+		BeforeEach(func() {
+			sess := session.NewSession("raw", "uuid-raw")
+			sess.Metadata.Context = "a context longer than the summary shows"
+			Expect(store.Create(sess)).To(Succeed())
+		})
+
+		It("prints raw session files", func() {
+			Expect(store.SaveSettings("raw", &session.Settings{Model: "sonnet"})).To(Succeed())
+			Expect(outputstyle.CreateCustomStyleFile(clotildeRoot, "raw", "Be terse.")).To(Succeed())
+
+			for _, file := range []string{"metadata.json", "settings.json"} {
+				want, err := os.ReadFile(filepath.Join(config.GetSessionDir(clotildeRoot, "raw"), file))
+				Expect(err).NotTo(HaveOccurred())
+				out, err := cat("raw", strings.TrimSuffix(file, ".json"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(out).To(Equal(string(want)))
+			}
+
+			out, err := cat("raw", "context")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(out).To(Equal("a context longer than the summary shows\n"))
+
+			out, err = cat("raw", "prompt")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(out).To(ContainSubstring("Be terse."))
+		})
+
+		It("reports missing and unknown files", func() {
+			_, err := cat("raw", "prompt")
+			Expect(err).To(MatchError("session 'raw' has no custom output style"))
+
+			_, err = cat("raw", "transcript")
+			Expect(err).To(MatchError(ContainSubstring("invalid --cat 'transcript'")))
+		})
+	})
 })
@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/ui"
+	"github.com/fgrehm/clotilde/internal/util"
+)
+
+// vscodeTaskPrefix marks the tasks clotilde generates. Tasks with other labels
+// are never touched.
+const vscodeTaskPrefix = "clotilde: "
+
+// newIntegrateCmd creates the 'integrate' command group, which generates
+// editor configuration for launching sessions.
+func newIntegrateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "integrate",
+		Short: "Generate editor integrations for clotilde sessions",
+	}
+	cmd.AddCommand(newIntegrateVSCodeCmd())
+	return cmd
+}
+
+func newIntegrateVSCodeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "vscode",
+		Short: "Add clotilde tasks to .vscode/tasks.json",
+		Long: `Write VS Code tasks that launch clotilde in the integrated terminal, so
+sessions can be started from the command palette (Tasks: Run Task):
+
+  clotilde: dashboard            the interactive dashboard
+  clotilde: start                a new session with a random name
+  clotilde: start (<profile>)    a new session with each configured profile
+  clotilde: resume <name>        each existing session (incognito ones are skipped)
+
+Tasks labelled "clotilde: ..." are replaced on every run; other tasks and
+settings in the file are kept. Run it again after adding sessions or profiles.
+Files with comments can't be merged: use --stdout and copy the tasks by hand.`,
+		Example: `  clotilde integrate vscode
+  clotilde integrate vscode --stdout`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			toStdout, _ := cmd.Flags().GetBool("stdout")
+
+			projectRoot, err := config.FindProjectRoot()
+			if err != nil {
+				return fmt.Errorf("failed to determine project root: %w", err)
+			}
+
+			var sessions []*session.Session
+			var profiles map[string]config.Profile
+			if clotildeRoot, err := config.FindClotildeRoot(); err == nil {
+				if sessions, err = session.NewFileStore(clotildeRoot).List(); err != nil {
+					return fmt.Errorf("failed to list sessions: %w", err)
+				}
+				// Encrypted contexts stay out of tasks.json
+				if encrypted, _ := config.EncryptionEnabled(clotildeRoot); encrypted {
+					for _, sess := range sessions {
+						sess.Metadata.Context = ""
+					}
+				}
+				if profiles, err = config.MergedProfiles(clotildeRoot); err != nil {
+					return err
+				}
+			} else {
+				globalCfg, err := config.LoadGlobalOrDefault()
+				if err != nil {
+					return fmt.Errorf("failed to load global config: %w", err)
+				}
+				profiles = globalCfg.Profiles
+			}
+			tasks := vscodeTasks(sessions, slices.Sorted(maps.Keys(profiles)))
+
+			if toStdout {
+				data, err := json.MarshalIndent(map[string]any{"version": "2.0.0", "tasks": tasks}, "", "  ")
+				if err != nil {
+					return err
+				}
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), string(data))
+				return nil
+			}
+
+			tasksPath := filepath.Join(projectRoot, ".vscode", "tasks.json")
+			if err := mergeVSCodeTasks(tasksPath, tasks); err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), ui.Success(fmt.Sprintf("Wrote %d clotilde task(s) to %s", len(tasks), tasksPath)))
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Run them with 'Tasks: Run Task' in the command palette.")
+			return nil
+		},
+	}
+	cmd.Flags().Bool("stdout", false, "Print the tasks instead of writing .vscode/tasks.json")
+	return cmd
+}
+
+// vscodeTask is an entry of tasks.json.
+type vscodeTask struct {
+	Label          string             `json:"label"`
+	Detail         string             `json:"detail,omitempty"`
+	Type           string             `json:"type"`
+	Command        string             `json:"command"`
+	Args           []string           `json:"args,omitempty"`
+	Presentation   vscodePresentation `json:"presentation"`
+	ProblemMatcher []string           `json:"problemMatcher"`
+}
+
+type vscodePresentation struct {
+	Reveal string `json:"reveal"`
+	Panel  string `json:"panel"`
+	Focus  bool   `json:"focus"`
+}
+
+// vscodeTasks builds the tasks for the dashboard, a new session, one per
+// profile and one per session. Each runs in its own terminal.
+func vscodeTasks(sessions []*session.Session, profiles []string) []vscodeTask {
+	task := func(label, detail string, args ...string) vscodeTask {
+		return vscodeTask{
+			Label:          vscodeTaskPrefix + label,
+			Detail:         detail,
+			Type:           "shell",
+			Command:        "clotilde",
+			Args:           args,
+			Presentation:   vscodePresentation{Reveal: "always", Panel: "new", Focus: true},
+			ProblemMatcher: []string{},
+		}
+	}
+
+	tasks := []vscodeTask{
+		task("dashboard", "Open the clotilde dashboard"),
+		task("start", "Start a new session with a random name", "start"),
+	}
+	for _, profile := range profiles {
+		tasks = append(tasks, task(fmt.Sprintf("start (%s)", profile), "Start a new session with the '"+profile+"' profile", "start", "--profile", profile))
+	}
+	for _, sess := range sessions {
+		if sess.Metadata.IsIncognito {
+			continue
+		}
+		tasks = append(tasks, task("resume "+sess.Name, sess.Metadata.Context, "resume", sess.Name))
+	}
+	return tasks
+}
+
+// mergeVSCodeTasks replaces the clotilde tasks in tasksPath with tasks,
+// keeping every other task and setting. The file is created if missing.
+func mergeVSCodeTasks(tasksPath string, tasks []vscodeTask) error {
+	file := map[string]any{"version": "2.0.0"}
+	if util.FileExists(tasksPath) {
+		if err := util.ReadJSON(tasksPath, &file); err != nil {
+			return fmt.Errorf("failed to parse %s (comments are not supported, use --stdout and merge by hand): %w", tasksPath, err)
+		}
+	}
+
+	existing, _ := file["tasks"].([]any)
+	merged := make([]any, 0, len(existing)+len(tasks))
+	for _, t := range existing {
+		if entry, ok := t.(map[string]any); ok {
+			if label, _ := entry["label"].(string); strings.HasPrefix(label, vscodeTaskPrefix) {
+				continue
+			}
+		}
+		merged = append(merged, t)
+	}
+	for _, t := range tasks {
+		merged = append(merged, t)
+	}
+	file["tasks"] = merged
+
+	if err := util.WriteJSON(tasksPath, file); err != nil {
+		return fmt.Errorf("failed to write %s: %w", tasksPath, err)
+	}
+	return nil
+}
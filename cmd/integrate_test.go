@@ -0,0 +1,126 @@
+package cmd_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/util"
+)
+
+var _ = Describe("Integrate Command", func() {
+	var (
+		tempDir    string
+		originalWd string
+		tasksPath  string
+		store      session.Store
+	)
+
+	BeforeEach(func() {
+		tempDir = GinkgoT().TempDir()
+		GinkgoT().Setenv("XDG_CONFIG_HOME", filepath.Join(tempDir, "xdg"))
+
+		var err error
+		originalWd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Chdir(tempDir)).To(Succeed())
+
+		Expect(config.EnsureClotildeStructure(tempDir)).To(Succeed())
+		clotildeRoot := filepath.Join(tempDir, config.ClotildeDir)
+		store = session.NewFileStore(clotildeRoot)
+		tasksPath = filepath.Join(tempDir, ".vscode", "tasks.json")
+
+		cfg := config.NewConfig()
+		cfg.Profiles["quick"] = config.Profile{Model: "haiku"}
+		Expect(util.WriteJSON(config.GetConfigPath(clotildeRoot), cfg)).To(Succeed())
+
+		sess := session.NewSession("auth", "uuid-auth")
+		sess.Metadata.Context = "working on GH-42"
+		Expect(store.Create(sess)).To(Succeed())
+		ghost := session.NewSession("ghost", "uuid-ghost")
+		ghost.Metadata.IsIncognito = true
+		Expect(store.Create(ghost)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		_ = os.Chdir(originalWd)
+	})
+
+	run := func(args ...string) (string, error) {
+		return runClotilde(append([]string{"integrate", "vscode"}, args...)...)
+	}
+
+	type task struct {
+		Label   string   `json:"label"`
+		Detail  string   `json:"detail"`
+		Command string   `json:"command"`
+		Args    []string `json:"args"`
+	}
+
+	readTasks := func() (map[string]json.RawMessage, []task) {
+		var file map[string]json.RawMessage
+		Expect(util.ReadJSON(tasksPath, &file)).To(Succeed())
+		var tasks []task
+		Expect(json.Unmarshal(file["tasks"], &tasks)).To(Succeed())
+		return file, tasks
+	}
+
+	It("writes tasks for the dashboard, profiles and sessions", func() {
+		_, err := run()
+		Expect(err).NotTo(HaveOccurred())
+
+		_, tasks := readTasks()
+		Expect(tasks).To(Equal([]task{
+			{Label: "clotilde: dashboard", Detail: "Open the clotilde dashboard", Command: "clotilde"},
+			{Label: "clotilde: start", Detail: "Start a new session with a random name", Command: "clotilde", Args: []string{"start"}},
+			{Label: "clotilde: start (quick)", Detail: "Start a new session with the 'quick' profile", Command: "clotilde", Args: []string{"start", "--profile", "quick"}},
+			{Label: "clotilde: resume auth", Detail: "working on GH-42", Command: "clotilde", Args: []string{"resume", "auth"}},
+		}))
+	})
+
+	It("keeps other tasks and settings and replaces old clotilde tasks", func() {
+		existing := `{
+  "version": "2.0.0",
+  "inputs": [],
+  "tasks": [
+    {"label": "build", "type": "shell", "command": "make"},
+    {"label": "clotilde: resume deleted-session", "type": "shell", "command": "clotilde"}
+  ]
+}`
+		Expect(os.MkdirAll(filepath.Dir(tasksPath), 0o755)).To(Succeed())
+		Expect(os.WriteFile(tasksPath, []byte(existing), 0o644)).To(Succeed())
+
+		_, err := run()
+		Expect(err).NotTo(HaveOccurred())
+
+		file, tasks := readTasks()
+		Expect(file).To(HaveKey("inputs"))
+		Expect(tasks[0].Label).To(Equal("build"))
+		Expect(tasks).To(HaveLen(5))
+		for _, t := range tasks {
+			Expect(t.Label).NotTo(Equal("clotilde: resume deleted-session"))
+		}
+	})
+
+	It("refuses to rewrite a file it can't parse", func() {
+		Expect(os.MkdirAll(filepath.Dir(tasksPath), 0o755)).To(Succeed())
+		Expect(os.WriteFile(tasksPath, []byte("// my tasks\n{}"), 0o644)).To(Succeed())
+
+		_, err := run()
+		Expect(err).To(MatchError(ContainSubstring("use --stdout")))
+		data, _ := os.ReadFile(tasksPath)
+		Expect(string(data)).To(Equal("// my tasks\n{}"))
+	})
+
+	It("prints the tasks with --stdout without writing", func() {
+		out, err := run("--stdout")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(ContainSubstring(`"label": "clotilde: resume auth"`))
+		Expect(tasksPath).NotTo(BeAnExistingFile())
+	})
+})
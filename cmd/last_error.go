@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fgrehm/clotilde/internal/claude"
+	"github.com/fgrehm/clotilde/internal/errs"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/util"
+)
+
+func newLastErrorCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "last-error <name>",
+		Short: "Show Claude Code's error output from a session's last crash",
+		Long: `Show how a session's last Claude Code run ended and, if it crashed, the
+tail of the error output Claude Code printed before exiting.
+
+Clotilde keeps the last 64 KB of Claude Code's stderr for each run and saves
+it when the run exits with an error (interrupting with Ctrl+C doesn't count).`,
+		Example: `  clotilde last-error auth-feature
+  clotilde last-error auth-feature -n 100`,
+		Annotations:       readOnly(),
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: sessionNameCompletion,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			lines, _ := cmd.Flags().GetInt("lines")
+			if lines < 1 {
+				return fmt.Errorf("--lines must be at least 1")
+			}
+
+			clotildeRoot, err := findClotildeRoot()
+			if err != nil {
+				if projectRootOverride != "" {
+					return err
+				}
+				return errNoSessions()
+			}
+
+			store := session.NewFileStore(clotildeRoot)
+			sess, err := store.Get(name)
+			if err != nil {
+				return errs.NotFound("session '%s' not found", name)
+			}
+
+			out := cmd.OutOrStdout()
+			if exit := sess.Metadata.LastExit; exit != nil {
+				_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Last exit: %s %s\n", exit.Summary(), util.FormatRelativeTime(exit.At))
+			}
+
+			data, err := os.ReadFile(claude.LastErrorPath(clotildeRoot, name))
+			if errors.Is(err, fs.ErrNotExist) {
+				_, _ = fmt.Fprintf(out, "No error output captured for '%s'.\n", name)
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read error output: %w", err)
+			}
+
+			tail := lastLines(string(data), lines)
+			if tail == "" {
+				_, _ = fmt.Fprintln(out, "Claude Code printed nothing to stderr before exiting.")
+				return nil
+			}
+			_, _ = fmt.Fprintln(out, tail)
+			return nil
+		},
+	}
+	cmd.Flags().IntP("lines", "n", 40, "Number of lines to show")
+	return cmd
+}
+
+// lastLines returns the last n lines of s, without the trailing newline.
+func lastLines(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,80 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/claude"
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/errs"
+	"github.com/fgrehm/clotilde/internal/session"
+)
+
+var _ = Describe("Last Error Command", func() {
+	var (
+		tempDir      string
+		clotildeRoot string
+		originalWd   string
+		claudeBin    string
+		store        session.Store
+	)
+
+	BeforeEach(func() {
+		tempDir = GinkgoT().TempDir()
+
+		var err error
+		originalWd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Chdir(tempDir)).To(Succeed())
+
+		// A claude that complains on stderr and exits with an error
+		claudeBin = filepath.Join(tempDir, "claude")
+		script := "#!/bin/bash\necho 'starting up' >&2\necho 'API error: overloaded' >&2\nexit 3\n"
+		Expect(os.WriteFile(claudeBin, []byte(script), 0o755)).To(Succeed())
+
+		Expect(config.EnsureClotildeStructure(tempDir)).To(Succeed())
+		clotildeRoot = filepath.Join(tempDir, config.ClotildeDir)
+		store = session.NewFileStore(clotildeRoot)
+
+		claude.SessionUsedFunc = func(_ string, _ *session.Session) bool { return true }
+	})
+
+	AfterEach(func() {
+		_ = os.Chdir(originalWd)
+	})
+
+	run := func(args ...string) (string, error) {
+		return runClotilde(append([]string{"--claude-bin", claudeBin}, args...)...)
+	}
+
+	It("records a crash and shows claude's error output", func() {
+		_, err := run("start", "flaky")
+		Expect(errs.ExitCode(err)).To(Equal(errs.ExitClaudeFailed))
+
+		sess, err := store.Get("flaky")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sess.Metadata.LastExit).NotTo(BeNil())
+		Expect(sess.Metadata.LastExit.Code).To(Equal(3))
+		Expect(sess.Metadata.LastExit.Summary()).To(Equal("crashed (3)"))
+		Expect(sess.Status()).To(Equal(session.StatusBroken))
+
+		out, err := run("last-error", "flaky", "-n", "1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(Equal("API error: overloaded\n"))
+
+		out, err = run("inspect", "flaky")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(ContainSubstring("Last Exit: crashed (3) just now"))
+	})
+
+	It("reports when nothing was captured", func() {
+		Expect(store.Create(session.NewSession("calm", "uuid-calm"))).To(Succeed())
+
+		out, err := run("last-error", "calm")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(ContainSubstring("No error output captured for 'calm'"))
+	})
+})
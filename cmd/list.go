@@ -2,81 +2,203 @@ package cmd
 
 import (
 	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 
 	"github.com/fgrehm/clotilde/internal/claude"
-	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/i18n"
 	"github.com/fgrehm/clotilde/internal/session"
 	"github.com/fgrehm/clotilde/internal/ui"
+	"github.com/fgrehm/clotilde/internal/uistate"
 	"github.com/fgrehm/clotilde/internal/util"
 )
 
-var listCmd = &cobra.Command{
-	Use:     "list",
-	Aliases: []string{"ls"},
-	Short:   "List all sessions",
-	Long:    `List all clotilde sessions in the current project, sorted by last used.`,
-	RunE: func(cmd *cobra.Command, args []string) error {
-		// Find clotilde root
-		clotildeRoot, err := config.FindClotildeRoot()
-		if err != nil {
-			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "No sessions found.")
-			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "\nCreate a session with:")
-			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "  clotilde start <session-name>")
-			return nil
-		}
+// newListCmd creates a fresh list command instance (avoids flag pollution in tests)
+func newListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List all sessions",
+		Long: `List all clotilde sessions in the current project, starred sessions first
+(see 'clotilde star'), then sorted by last used.
 
-		// Load all sessions
-		store := session.NewFileStore(clotildeRoot)
-		sessions, err := store.List()
-		if err != nil {
-			return fmt.Errorf("failed to list sessions: %w", err)
-		}
+Each session has a lifecycle status: active (Claude Code is running), idle,
+archived, expired (kept because auto-prune is off) or broken (the last run
+crashed). --status shows only sessions with the given statuses, and
+--incognito / --no-incognito only or no incognito sessions (which exist only
+while Claude Code runs them).
+
+With --team, list the sessions teammates share through the directory set in
+the "team" config instead. Your own session metadata (names, contexts and
+timestamps; never transcripts) is shared there first.
+
+With --porcelain, print one tab-separated line per session in a fixed field
+order that stays stable between versions, for scripts (see docs/porcelain.md).`,
+		Annotations: withRemote(readOnly()),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if remoteTarget != "" {
+				return listRemote(cmd)
+			}
+
+			// Find clotilde root
+			clotildeRoot, err := findClotildeRoot()
+			if err != nil {
+				if projectRootOverride != "" {
+					return err
+				}
+				if isPorcelain(cmd) {
+					return nil
+				}
+				printNoSessions(cmd.OutOrStdout())
+				return nil
+			}
+
+			// Load all sessions
+			store := session.NewFileStore(clotildeRoot)
+			sessions, err := store.List()
+			if err != nil {
+				return fmt.Errorf("failed to list sessions: %w", err)
+			}
+
+			if teamFlag, _ := cmd.Flags().GetBool("team"); teamFlag {
+				return listTeam(cmd, clotildeRoot, sessions)
+			}
+
+			statuses, _ := cmd.Flags().GetStringSlice("status")
+			if len(statuses) > 0 {
+				if sessions, err = filterByStatus(sessions, statuses); err != nil {
+					return err
+				}
+			}
+
+			if onlyIncognito, _ := cmd.Flags().GetBool("incognito"); onlyIncognito {
+				sessions = filterIncognito(sessions, true)
+			} else if noIncognito, _ := cmd.Flags().GetBool("no-incognito"); noIncognito {
+				sessions = filterIncognito(sessions, false)
+			}
 
-		if len(sessions) == 0 {
-			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "No sessions found.")
-			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "\nCreate a session with:")
-			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "  clotilde start <session-name>")
-			return nil
+			session.SortStarredFirst(sessions)
+
+			if isPorcelain(cmd) {
+				writePorcelainList(cmd.OutOrStdout(), sessions, func(sess *session.Session) (string, time.Time) {
+					return extractModelAndLastUsed(clotildeRoot, sess, store)
+				})
+				return nil
+			}
+
+			if len(sessions) == 0 {
+				printNoSessions(cmd.OutOrStdout())
+				return nil
+			}
+
+			// Always use static table - dashboard has interactive list
+			return showStaticTable(cmd, clotildeRoot, sessions, store)
+		},
+	}
+	cmd.Flags().Bool("team", false, "List session metadata shared by teammates (see the \"team\" config)")
+	cmd.Flags().StringSlice("status", nil, "Only list sessions with these statuses (active, idle, archived, expired, broken)")
+	cmd.Flags().Bool("incognito", false, "Only list incognito sessions")
+	cmd.Flags().Bool("no-incognito", false, "Leave incognito sessions out")
+	addPorcelainFlag(cmd)
+	cmd.MarkFlagsMutuallyExclusive("incognito", "no-incognito")
+	cmd.MarkFlagsMutuallyExclusive("team", "porcelain")
+	_ = cmd.RegisterFlagCompletionFunc("status", cobra.FixedCompletions(session.Statuses, cobra.ShellCompDirectiveNoFileComp))
+	return cmd
+}
+
+// printNoSessions tells the user there are no sessions and how to create one.
+func printNoSessions(w io.Writer) {
+	_, _ = fmt.Fprintln(w, i18n.T(i18n.NoSessionsFound))
+	_, _ = fmt.Fprintln(w, "\n"+i18n.T(i18n.CreateSessionHint))
+	_, _ = fmt.Fprintln(w, "  clotilde start <session-name>")
+}
+
+// filterIncognito keeps the incognito sessions when incognito is true, and
+// the others when it's false.
+func filterIncognito(sessions []*session.Session, incognito bool) []*session.Session {
+	var filtered []*session.Session
+	for _, sess := range sessions {
+		if sess.Metadata.IsIncognito == incognito {
+			filtered = append(filtered, sess)
 		}
+	}
+	return filtered
+}
 
-		// Always use static table - dashboard has interactive list
-		return showStaticTable(cmd, sessions, store)
-	},
+// filterByStatus keeps the sessions whose lifecycle status is in statuses.
+func filterByStatus(sessions []*session.Session, statuses []string) ([]*session.Session, error) {
+	for _, status := range statuses {
+		if !slices.Contains(session.Statuses, status) {
+			return nil, fmt.Errorf("invalid status '%s' (valid: %s)", status, strings.Join(session.Statuses, ", "))
+		}
+	}
+	var filtered []*session.Session
+	for _, sess := range sessions {
+		if slices.Contains(statuses, sess.Status()) {
+			filtered = append(filtered, sess)
+		}
+	}
+	return filtered, nil
 }
 
 // showInteractiveTable displays sessions in an interactive TUI table with sorting
 // If a session is selected, it returns the session. Otherwise returns nil.
-func showInteractiveTable(sessions []*session.Session, store session.Store) (*session.Session, error) {
+// The sort and filter are restored from the UI state file and saved back
+// when they change.
+func showInteractiveTable(clotildeRoot string, sessions []*session.Session, store session.Store) (*session.Session, error) {
 	// Build headers
-	headers := []string{"Name", "Model", "Type", "Last Used"}
+	headers := []string{"Name", "Model", "Type", "Status", "Last Used", "Health"}
+
+	// Health is best effort; without a home dir transcripts can't be located
+	homeDir, _ := util.HomeDir()
 
 	// Build rows (rows will be in same order as sessions array initially)
 	var rows [][]string
 	for _, sess := range sessions {
-		model, lastUsed := extractModelAndLastUsed(sess, store)
+		model, lastUsed := extractModelAndLastUsed(clotildeRoot, sess, store)
 		typeStr := formatSessionType(sess)
-		rows = append(rows, []string{sess.Name, model, typeStr, util.FormatRelativeTime(lastUsed)})
+		if isYolo(store, sess.Name) {
+			typeStr += " " + ui.ErrorStyle.Render(yoloMarker)
+		}
+		health := sessionHealthCell(sess, clotildeRoot, homeDir)
+		rows = append(rows, []string{sess.Name, model, typeStr, ui.RenderStatus(sess.Status()), util.FormatRelativeTime(lastUsed), health})
 	}
 
 	// Create and run interactive table
-	fmt.Printf("Sessions (%d total)\n\n", len(sessions))
-	table := ui.NewTable(headers, rows).WithSorting()
-	selectedRow, err := ui.RunTable(table)
+	fmt.Printf("%s\n\n", i18n.T(i18n.ListHeader, len(sessions)))
+	state, err := uistate.Load()
+	if err != nil {
+		// A broken state file shouldn't block listing sessions
+		_, _ = fmt.Fprintln(os.Stderr, ui.Warning(fmt.Sprintf("Ignoring saved table view: %v", err)))
+		state = &uistate.State{}
+	}
+	table := ui.NewTable(headers, rows).WithSorting().WithView(state.TableView(uistate.SessionsTable))
+	initial := table.TableView()
+
+	final, err := ui.RunTableModel(table)
 	if err != nil {
 		return nil, err
 	}
+	if view := final.TableView(); view != initial {
+		state.SetTableView(uistate.SessionsTable, view)
+		if err := state.Save(); err != nil {
+			_, _ = fmt.Fprintln(os.Stderr, ui.Warning(fmt.Sprintf("Failed to save table view: %v", err)))
+		}
+	}
 
 	// If cancelled or no selection, return nil
-	if len(selectedRow) == 0 {
+	if final.Cancelled || len(final.SelectedRow) == 0 {
 		return nil, nil
 	}
 
 	// Map the selected row back to the session by name (first column)
-	selectedName := selectedRow[0]
+	selectedName := final.SelectedRow[0]
 	for _, sess := range sessions {
 		if sess.Name == selectedName {
 			return sess, nil
@@ -87,31 +209,38 @@ func showInteractiveTable(sessions []*session.Session, store session.Store) (*se
 }
 
 // showStaticTable displays sessions in a static text table (for scripts/pipes)
-func showStaticTable(cmd *cobra.Command, sessions []*session.Session, store session.Store) error {
-	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Sessions (%d total):\n", len(sessions))
+func showStaticTable(cmd *cobra.Command, clotildeRoot string, sessions []*session.Session, store session.Store) error {
+	_, _ = fmt.Fprintln(cmd.OutOrStdout(), i18n.T(i18n.ListHeader, len(sessions)))
 
 	table := tablewriter.NewWriter(cmd.OutOrStdout())
-	table.Header("NAME", "MODEL", "TYPE", "LAST USED")
+	table.Header("NAME", "MODEL", "TYPE", "STATUS", "LAST USED", "HEALTH")
+
+	// Health is best effort; without a home dir transcripts can't be located
+	homeDir, _ := util.HomeDir()
 
 	for _, sess := range sessions {
-		model, lastUsed := extractModelAndLastUsed(sess, store)
+		model, lastUsed := extractModelAndLastUsed(clotildeRoot, sess, store)
 		typeStr := formatSessionType(sess)
-		_ = table.Append(sess.Name, model, typeStr, util.FormatRelativeTime(lastUsed))
+		if isYolo(store, sess.Name) {
+			typeStr += " " + ui.ErrorStyle.Render(yoloMarker)
+		}
+		health := sessionHealthCell(sess, clotildeRoot, homeDir)
+		_ = table.Append(sess.Name, model, typeStr, ui.RenderStatus(sess.Status()), util.FormatRelativeTime(lastUsed), health)
 	}
 
 	_ = table.Render()
 	return nil
 }
 
-// extractModelAndLastUsed reads the transcript tail once, returning both the model
-// family and the best "last used" time. More efficient than separate ExtractLastModel
-// and LastTranscriptTime calls, which would each open and seek the file.
-func extractModelAndLastUsed(sess *session.Session, store session.Store) (string, time.Time) {
+// extractModelAndLastUsed returns the model family and the best "last used"
+// time, from the session's transcript cache or a single read of the
+// transcript tail when the transcript changed since it was cached.
+func extractModelAndLastUsed(clotildeRoot string, sess *session.Session, store session.Store) (string, time.Time) {
 	lastUsed := sess.Metadata.LastAccessed
 	model := "-"
 
 	if sess.Metadata.TranscriptPath != "" {
-		m, ts := claude.ExtractModelAndLastTime(sess.Metadata.TranscriptPath)
+		m, ts := claude.CachedModelAndLastTime(claude.StatsCachePath(clotildeRoot, sess.Name), sess.Metadata.TranscriptPath)
 		if m != "" {
 			model = m
 		}
@@ -131,7 +260,7 @@ func extractModelAndLastUsed(sess *session.Session, store session.Store) (string
 	return model, lastUsed
 }
 
-// formatSessionType formats the session type string (regular, fork, incognito)
+// formatSessionType formats the session type string (regular, fork, incognito, not started)
 func formatSessionType(sess *session.Session) string {
 	typeStr := "session"
 	if sess.Metadata.IsForkedSession {
@@ -140,5 +269,11 @@ func formatSessionType(sess *session.Session) string {
 	if sess.Metadata.IsIncognito {
 		typeStr += " 👻"
 	}
+	if sess.Metadata.Starred {
+		typeStr += " ★"
+	}
+	if sess.Metadata.PendingLaunch {
+		typeStr += " (not started)"
+	}
 	return typeStr
 }
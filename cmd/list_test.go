@@ -1,9 +1,11 @@
 package cmd_test
 
 import (
+	"bytes"
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -110,4 +112,55 @@ var _ = Describe("List Command", func() {
 		err = rootCmd.Execute()
 		Expect(err).NotTo(HaveOccurred())
 	})
+	It("flags sessions whose settings use bypassPermissions", func() {
+		Expect(store.Create(session.NewSession("wild", "uuid-wild"))).To(Succeed())
+		Expect(store.SaveSettings("wild", &session.Settings{Permissions: session.Permissions{DefaultMode: "bypassPermissions"}})).To(Succeed())
+		Expect(store.Create(session.NewSession("tame", "uuid-tame"))).To(Succeed())
+
+		var out bytes.Buffer
+		rootCmd := cmd.NewRootCmd()
+		rootCmd.SetOut(&out)
+		rootCmd.SetErr(io.Discard)
+		rootCmd.SetArgs([]string{"list"})
+		Expect(rootCmd.Execute()).To(Succeed())
+		Expect(out.String()).To(ContainSubstring("⚠ yolo"))
+
+		for line := range strings.SplitSeq(out.String(), "\n") {
+			switch {
+			case strings.Contains(line, "wild"):
+				Expect(line).To(ContainSubstring("⚠ yolo"))
+			case strings.Contains(line, "tame"):
+				Expect(line).NotTo(ContainSubstring("yolo"))
+			}
+		}
+	})
+
+	It("filters incognito sessions with --incognito and --no-incognito", func() {
+		Expect(store.Create(session.NewIncognitoSession("ghost", "uuid-ghost"))).To(Succeed())
+		Expect(store.Create(session.NewSession("regular", "uuid-regular"))).To(Succeed())
+
+		list := func(args ...string) string {
+			var out bytes.Buffer
+			rootCmd := cmd.NewRootCmd()
+			rootCmd.SetOut(&out)
+			rootCmd.SetErr(io.Discard)
+			rootCmd.SetArgs(append([]string{"list"}, args...))
+			Expect(rootCmd.Execute()).To(Succeed())
+			return out.String()
+		}
+
+		out := list("--incognito")
+		Expect(out).To(ContainSubstring("ghost"))
+		Expect(out).NotTo(ContainSubstring("regular"))
+
+		out = list("--no-incognito")
+		Expect(out).To(ContainSubstring("regular"))
+		Expect(out).NotTo(ContainSubstring("ghost"))
+
+		rootCmd := cmd.NewRootCmd()
+		rootCmd.SetOut(io.Discard)
+		rootCmd.SetErr(io.Discard)
+		rootCmd.SetArgs([]string{"list", "--incognito", "--no-incognito"})
+		Expect(rootCmd.Execute()).To(HaveOccurred())
+	})
 })
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fgrehm/clotilde/internal/claude"
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/errs"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/ui"
+)
+
+func newLogsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "logs <name>",
+		Short: "Show Claude Code's captured stderr for a session",
+		Long: `Show the session's claude.log: everything Claude Code printed to stderr
+(hook errors, API failures, warnings) across its runs, each run starting with
+a "=== <time> claude <args> ===" line.
+
+Capture is off by default. Enable it in the project or global config:
+  "logging": {"captureStderr": true, "maxSizeKB": 1024}
+
+The log is rotated to claude.log.1 once it grows past maxSizeKB (default
+1024), so at most two files are kept per session.`,
+		Example: `  clotilde logs auth-feature
+  clotilde logs auth-feature -n 0   # whole log`,
+		Annotations:       readOnly(),
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: sessionNameCompletion,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			lines, _ := cmd.Flags().GetInt("lines")
+			if lines < 0 {
+				return fmt.Errorf("--lines must not be negative")
+			}
+
+			clotildeRoot, err := findClotildeRoot()
+			if err != nil {
+				if projectRootOverride != "" {
+					return err
+				}
+				return errNoSessions()
+			}
+
+			store := session.NewFileStore(clotildeRoot)
+			if !store.Exists(name) {
+				return errs.NotFound("session '%s' not found", name)
+			}
+
+			var log strings.Builder
+			for _, path := range claude.SessionLogPaths(clotildeRoot, name) {
+				data, err := os.ReadFile(path)
+				if errors.Is(err, fs.ErrNotExist) {
+					continue
+				}
+				if err != nil {
+					return fmt.Errorf("failed to read session log: %w", err)
+				}
+				log.Write(data)
+			}
+
+			out := cmd.OutOrStdout()
+			if log.Len() == 0 {
+				_, _ = fmt.Fprintf(out, "No log for '%s'.\n", name)
+				if logging, err := config.MergedLogging(clotildeRoot); err == nil && !*logging.CaptureStderr {
+					_, _ = fmt.Fprintln(cmd.ErrOrStderr(), ui.Info(`Capture is disabled; set "logging": {"captureStderr": true} in the config to record Claude Code's stderr`))
+				}
+				return nil
+			}
+
+			if lines == 0 {
+				_, _ = fmt.Fprint(out, log.String())
+				return nil
+			}
+			_, _ = fmt.Fprintln(out, lastLines(log.String(), lines))
+			return nil
+		},
+	}
+	cmd.Flags().IntP("lines", "n", 100, "Number of lines to show (0 for the whole log)")
+	return cmd
+}
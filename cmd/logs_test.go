@@ -0,0 +1,104 @@
+package cmd_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/claude"
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/session"
+)
+
+var _ = Describe("Logs Command", func() {
+	var (
+		tempDir      string
+		clotildeRoot string
+		originalWd   string
+		claudeBin    string
+	)
+
+	BeforeEach(func() {
+		tempDir = GinkgoT().TempDir()
+		GinkgoT().Setenv("XDG_CONFIG_HOME", filepath.Join(tempDir, "xdg"))
+
+		var err error
+		originalWd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Chdir(tempDir)).To(Succeed())
+
+		claudeBin = filepath.Join(tempDir, "claude")
+		script := "#!/bin/bash\necho 'hook failed: exit 1' >&2\n"
+		Expect(os.WriteFile(claudeBin, []byte(script), 0o755)).To(Succeed())
+
+		Expect(config.EnsureClotildeStructure(tempDir)).To(Succeed())
+		clotildeRoot = filepath.Join(tempDir, config.ClotildeDir)
+
+		claude.SessionUsedFunc = func(_ string, _ *session.Session) bool { return true }
+	})
+
+	AfterEach(func() {
+		_ = os.Chdir(originalWd)
+	})
+
+	run := func(args ...string) (string, error) {
+		return runClotilde(append([]string{"--claude-bin", claudeBin}, args...)...)
+	}
+
+	enableCapture := func(maxSizeKB int) {
+		cfg := fmt.Sprintf(`{"logging": {"captureStderr": true, "maxSizeKB": %d}}`, maxSizeKB)
+		Expect(os.WriteFile(config.GetConfigPath(clotildeRoot), []byte(cfg), 0o644)).To(Succeed())
+	}
+
+	It("does not write a log unless capture is enabled", func() {
+		_, err := run("start", "quiet")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(claude.SessionLogPaths(clotildeRoot, "quiet")[1]).NotTo(BeAnExistingFile())
+		out, err := run("logs", "quiet")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(ContainSubstring("No log for 'quiet'"))
+	})
+
+	It("captures stderr from every run", func() {
+		enableCapture(1)
+
+		_, err := run("start", "noisy")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = run("resume", "noisy")
+		Expect(err).NotTo(HaveOccurred())
+
+		out, err := run("logs", "noisy", "-n", "0")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(strings.Count(out, "hook failed: exit 1")).To(Equal(2))
+		Expect(out).To(MatchRegexp(`=== \S+ claude --session-id \S+ -n noisy`))
+		Expect(out).To(MatchRegexp(`=== \S+ claude --resume \S+ -n noisy`))
+
+		out, err = run("logs", "noisy", "-n", "1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(Equal("hook failed: exit 1\n"))
+	})
+
+	It("rotates the log once it grows past maxSizeKB", func() {
+		enableCapture(1)
+		paths := claude.SessionLogPaths(clotildeRoot, "noisy")
+
+		_, err := run("start", "noisy")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.WriteFile(paths[1], bytes.Repeat([]byte("x"), 1020), 0o644)).To(Succeed())
+
+		_, err = run("resume", "noisy")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(paths[0]).To(BeAnExistingFile())
+		current, err := os.ReadFile(paths[1])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(len(current)).To(BeNumerically("<=", 1024))
+		Expect(string(current)).To(ContainSubstring("hook failed"))
+	})
+})
@@ -0,0 +1,279 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fgrehm/clotilde/internal/app"
+	"github.com/fgrehm/clotilde/internal/claude"
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/scheduler"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/ui"
+)
+
+func newMaintainCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "maintain",
+		Short: "Apply the auto-archive and expiry policies and run housekeeping now",
+		Long: `Archive sessions left unused for longer than "autoArchiveAfter" (e.g. "30d")
+and clean up expired sessions: deleted with "expiry.autoPrune", marked expired
+otherwise. Then run the housekeeping tasks: refresh the transcript stats cache
+of every session, remove incognito sessions left behind by a clotilde that
+didn't get to clean up, and rotate session logs larger than
+"logging.maxSizeKB".
+
+Every command applies the policies after it finishes, and with
+"maintenance": {"background": true} also runs the housekeeping tasks that are
+due (once per "maintenance.interval", default 6h), for at most a couple of
+seconds. maintain does all of it on its own and reports what
+changed, e.g. for a cron job. Starred and running sessions are never
+auto-archived, and 'clotilde unarchive' brings sessions back (counting as a
+use, so they aren't archived again right away).`,
+		Example: `  clotilde maintain`,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clotildeRoot, err := config.FindClotildeRoot()
+			if err != nil {
+				return errNoSessions()
+			}
+			out := cmd.OutOrStdout()
+
+			after, _, err := config.AutoArchiveAfter(clotildeRoot)
+			if err != nil {
+				return err
+			}
+			if after == 0 {
+				_, _ = fmt.Fprintln(out, ui.Info(`Auto-archive is off; set "autoArchiveAfter" (e.g. "30d") in the config to enable it`))
+			}
+
+			tasks := append(policyTasks(clotildeRoot), maintenanceTasks(clotildeRoot)...)
+			results, err := scheduler.New(config.GetSessionsDir(clotildeRoot), 0).RunAll(cmd.Context(), tasks)
+			if err != nil {
+				return err
+			}
+			if !reportMaintenance(out, results) {
+				_, _ = fmt.Fprintln(out, "Nothing to do.")
+			}
+			return nil
+		},
+	}
+}
+
+// applyAutoArchive archives the sessions staleSessions picks when
+// "autoArchiveAfter" is set, summarizing them with the command that undoes it.
+func applyAutoArchive(ctx context.Context, clotildeRoot string, store session.Store) (string, error) {
+	after, setting, err := config.AutoArchiveAfter(clotildeRoot)
+	if err != nil || after == 0 {
+		return "", err
+	}
+	sessions, err := store.List()
+	if err != nil {
+		return "", fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	var archived []string
+	var failures []error
+	for _, sess := range staleSessions(sessions, after, time.Now()) {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		sess.Metadata.Status = session.StatusArchived
+		if err := store.Update(sess); err != nil {
+			failures = append(failures, fmt.Errorf("failed to archive session '%s': %w", sess.Name, err))
+			continue
+		}
+		archived = append(archived, sess.Name)
+	}
+
+	var summary string
+	if len(archived) > 0 {
+		noun := "sessions"
+		if len(archived) == 1 {
+			noun = "session"
+		}
+		summary = fmt.Sprintf("Archived %d %s unused for %s: %s (undo with 'clotilde unarchive %s')",
+			len(archived), noun, setting, strings.Join(archived, ", "), strings.Join(archived, " "))
+	}
+	return summary, errors.Join(failures...)
+}
+
+// staleSessions returns the sessions auto-archive puts away: idle or broken,
+// not starred, and last used longer than after before now.
+func staleSessions(sessions []*session.Session, after time.Duration, now time.Time) []*session.Session {
+	var stale []*session.Session
+	for _, sess := range sessions {
+		if status := sess.Status(); status != session.StatusIdle && status != session.StatusBroken {
+			continue
+		}
+		if sess.Metadata.Starred || sess.Metadata.LastAccessed.IsZero() || now.Sub(sess.Metadata.LastAccessed) <= after {
+			continue
+		}
+		stale = append(stale, sess)
+	}
+	return stale
+}
+
+// backgroundMaintenanceBudget bounds how long the housekeeping after a command
+// may delay its exit. Tasks it cuts short run after a later command.
+const backgroundMaintenanceBudget = 2 * time.Second
+
+// incognitoLeftoverAge is how long an incognito session that isn't running
+// is kept before housekeeping removes it, leaving time for a session that is
+// about to start.
+const incognitoLeftoverAge = time.Hour
+
+// runBackgroundMaintenance applies the auto-archive and expiry policies after
+// a command finishes and, when "maintenance.background" is enabled, runs the
+// housekeeping tasks that are due. Running after the command leaves it the
+// sessions it was asked about. Failures never fail the command.
+func runBackgroundMaintenance(cmd *cobra.Command) {
+	if projectRootOverride != "" || remoteTarget != "" || skipsAutoPrune(cmd) {
+		return
+	}
+
+	clotildeRoot, err := config.FindClotildeRoot()
+	if err != nil {
+		return
+	}
+	// Report on stderr so scripted output (list, export) stays clean
+	start := time.Now()
+	dir := config.GetSessionsDir(clotildeRoot)
+	results, _ := scheduler.New(dir, 0).RunDue(cmd.Context(), backgroundMaintenanceBudget, policyTasks(clotildeRoot))
+	reportMaintenance(cmd.ErrOrStderr(), results)
+
+	enabled, interval, err := config.MergedMaintenance(clotildeRoot)
+	budget := backgroundMaintenanceBudget - time.Since(start)
+	if err != nil || !enabled || budget <= 0 {
+		return
+	}
+	results, _ = scheduler.New(dir, interval).RunDue(cmd.Context(), budget, maintenanceTasks(clotildeRoot))
+	reportMaintenance(cmd.ErrOrStderr(), results)
+}
+
+// reportMaintenance prints what the housekeeping tasks changed and why any
+// failed, reporting whether anything changed.
+func reportMaintenance(out io.Writer, results []scheduler.Result) bool {
+	changed := false
+	for _, result := range results {
+		if result.Err != nil {
+			_, _ = fmt.Fprintln(out, ui.Warning(fmt.Sprintf("Maintenance task %s failed: %v", result.Task, result.Err)))
+		}
+		if result.Summary != "" {
+			_, _ = fmt.Fprintln(out, ui.Info(result.Summary))
+			changed = true
+		}
+	}
+	return changed
+}
+
+// policyTasks apply the expiry and auto-archive policies and encrypt output
+// styles left decrypted. Unlike the housekeeping tasks they run after every
+// command, not once per interval.
+func policyTasks(clotildeRoot string) []scheduler.Task {
+	store := session.NewFileStore(clotildeRoot)
+	return []scheduler.Task{
+		{Name: "expiry", Run: func(ctx context.Context) (string, error) { return applyExpiryPolicy(ctx, clotildeRoot, store) }},
+		{Name: "auto-archive", Run: func(ctx context.Context) (string, error) { return applyAutoArchive(ctx, clotildeRoot, store) }},
+		{Name: "reseal", Run: func(context.Context) (string, error) { return resealOutputStyles(clotildeRoot, store) }},
+	}
+}
+
+// maintenanceTasks are the housekeeping tasks, cheapest first so a tight
+// budget still gets through most of them. There is no session ID index to
+// rebuild: hooks find sessions by scanning their metadata, which stays fast
+// at the number of sessions a project has and can't go stale after a rename
+// or a /clear.
+func maintenanceTasks(clotildeRoot string) []scheduler.Task {
+	store := session.NewFileStore(clotildeRoot)
+	return []scheduler.Task{
+		{Name: "logs", Run: func(ctx context.Context) (string, error) { return rotateSessionLogs(ctx, clotildeRoot, store) }},
+		{Name: "incognito", Run: func(ctx context.Context) (string, error) { return removeIncognitoLeftovers(ctx, clotildeRoot, store) }},
+		{Name: "stats-cache", Run: func(ctx context.Context) (string, error) { return refreshStatsCaches(ctx, clotildeRoot, store) }},
+	}
+}
+
+// rotateSessionLogs rotates the claude.log of sessions that aren't running
+// once it's larger than "logging.maxSizeKB".
+func rotateSessionLogs(ctx context.Context, clotildeRoot string, store session.Store) (string, error) {
+	sessions, err := store.List()
+	if err != nil {
+		return "", fmt.Errorf("failed to list sessions: %w", err)
+	}
+	var rotated []string
+	for _, sess := range sessions {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		if sess.Status() == session.StatusActive {
+			continue
+		}
+		ok, err := claude.RotateSessionLog(clotildeRoot, sess.Name)
+		if err != nil {
+			return "", err
+		}
+		if ok {
+			rotated = append(rotated, sess.Name)
+		}
+	}
+	if len(rotated) == 0 {
+		return "", nil
+	}
+	return fmt.Sprintf("Rotated the session log of %s", strings.Join(rotated, ", ")), nil
+}
+
+// removeIncognitoLeftovers deletes incognito sessions that aren't running and
+// haven't been used for incognitoLeftoverAge: they should have been deleted
+// when Claude Code exited, but clotilde was interrupted first.
+func removeIncognitoLeftovers(ctx context.Context, clotildeRoot string, store session.Store) (string, error) {
+	sessions, err := store.List()
+	if err != nil {
+		return "", fmt.Errorf("failed to list sessions: %w", err)
+	}
+	var removed []string
+	var failures []error
+	for _, sess := range sessions {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		if !sess.Metadata.IsIncognito || sess.Metadata.PendingLaunch || sess.Status() == session.StatusActive ||
+			time.Since(sess.Metadata.LastAccessed) < incognitoLeftoverAge {
+			continue
+		}
+		if _, err := app.DeleteSession(clotildeRoot, store, sess); err != nil {
+			failures = append(failures, fmt.Errorf("failed to delete incognito session '%s': %w", sess.Name, err))
+			continue
+		}
+		removed = append(removed, sess.Name)
+	}
+	var summary string
+	if len(removed) > 0 {
+		summary = fmt.Sprintf("👻 Deleted incognito session(s) left behind: %s", strings.Join(removed, ", "))
+	}
+	return summary, errors.Join(failures...)
+}
+
+// refreshStatsCaches brings every session's transcript stats cache up to
+// date, so listing sessions doesn't have to read changed transcripts.
+// Nothing user-visible changes, so it never has a summary.
+func refreshStatsCaches(ctx context.Context, clotildeRoot string, store session.Store) (string, error) {
+	sessions, err := store.List()
+	if err != nil {
+		return "", fmt.Errorf("failed to list sessions: %w", err)
+	}
+	for _, sess := range sessions {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		if sess.Metadata.TranscriptPath != "" {
+			claude.CachedModelAndLastTime(claude.StatsCachePath(clotildeRoot, sess.Name), sess.Metadata.TranscriptPath)
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,211 @@
+package cmd_test
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/scheduler"
+	"github.com/fgrehm/clotilde/internal/session"
+)
+
+var _ = Describe("Auto-archive", func() {
+	var (
+		tempDir      string
+		clotildeRoot string
+		originalWd   string
+		store        session.Store
+	)
+
+	BeforeEach(func() {
+		tempDir = GinkgoT().TempDir()
+		GinkgoT().Setenv("HOME", filepath.Join(tempDir, "home"))
+		GinkgoT().Setenv("XDG_CONFIG_HOME", filepath.Join(tempDir, "xdg"))
+
+		var err error
+		originalWd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Chdir(tempDir)).To(Succeed())
+
+		Expect(config.EnsureClotildeStructure(tempDir)).To(Succeed())
+		clotildeRoot = filepath.Join(tempDir, config.ClotildeDir)
+		store = session.NewFileStore(clotildeRoot)
+
+		create := func(name string, unused time.Duration, starred bool) {
+			sess := session.NewSession(name, "uuid-"+name)
+			sess.Metadata.LastAccessed = time.Now().Add(-unused)
+			sess.Metadata.Starred = starred
+			Expect(store.Create(sess)).To(Succeed())
+		}
+		create("stale", 45*24*time.Hour, false)
+		create("favorite", 90*24*time.Hour, true)
+		create("fresh", time.Hour, false)
+	})
+
+	AfterEach(func() {
+		_ = os.Chdir(originalWd)
+	})
+
+	run := func(args ...string) (string, string, error) { return runClotildeWithInput("", args...) }
+
+	status := func(name string) string {
+		sess, err := store.Get(name)
+		Expect(err).NotTo(HaveOccurred())
+		return sess.Status()
+	}
+
+	enable := func(after string) {
+		Expect(os.WriteFile(config.GetConfigPath(clotildeRoot), []byte(`{"autoArchiveAfter": "`+after+`"}`), 0o644)).To(Succeed())
+	}
+
+	It("does nothing unless autoArchiveAfter is set", func() {
+		_, _, err := run("list")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status("stale")).To(Equal(session.StatusIdle))
+	})
+
+	It("archives unused sessions after any command, leaving starred ones alone", func() {
+		enable("30d")
+
+		out, errOut, err := run("list", "--porcelain")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(errOut).To(ContainSubstring("Archived 1 session unused for 30d: stale (undo with 'clotilde unarchive stale')"))
+		// The command itself still sees the session as it was
+		Expect(out).To(MatchRegexp(`stale\tuuid-stale\tidle`))
+
+		Expect(status("stale")).To(Equal(session.StatusArchived))
+		Expect(status("favorite")).To(Equal(session.StatusIdle))
+		Expect(status("fresh")).To(Equal(session.StatusIdle))
+	})
+
+	It("doesn't archive sessions again right after they're unarchived", func() {
+		enable("30d")
+		_, _, err := run("list")
+		Expect(err).NotTo(HaveOccurred())
+
+		_, _, err = run("unarchive", "stale")
+		Expect(err).NotTo(HaveOccurred())
+		_, errOut, err := run("list")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(errOut).NotTo(ContainSubstring("Archived"))
+		Expect(status("stale")).To(Equal(session.StatusIdle))
+	})
+
+	Describe("maintain", func() {
+		It("reports what it archived", func() {
+			enable("30d")
+
+			out, errOut, err := run("maintain")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errOut).To(BeEmpty())
+			Expect(out).To(ContainSubstring("Archived 1 session unused for 30d: stale"))
+			Expect(status("stale")).To(Equal(session.StatusArchived))
+
+			out, _, err = run("maintain")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(out).To(ContainSubstring("Nothing to do."))
+		})
+
+		It("says when auto-archive is off", func() {
+			out, _, err := run("maintain")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(out).To(ContainSubstring("Auto-archive is off"))
+			Expect(status("stale")).To(Equal(session.StatusIdle))
+		})
+
+		It("rejects an invalid period", func() {
+			enable("a month")
+
+			_, _, err := run("maintain")
+			Expect(err).To(MatchError(ContainSubstring("invalid autoArchiveAfter")))
+		})
+	})
+})
+
+var _ = Describe("Housekeeping", func() {
+	var (
+		tempDir      string
+		clotildeRoot string
+		originalWd   string
+		store        session.Store
+	)
+
+	BeforeEach(func() {
+		tempDir = GinkgoT().TempDir()
+		GinkgoT().Setenv("HOME", filepath.Join(tempDir, "home"))
+		GinkgoT().Setenv("XDG_CONFIG_HOME", filepath.Join(tempDir, "xdg"))
+
+		var err error
+		originalWd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Chdir(tempDir)).To(Succeed())
+
+		Expect(config.EnsureClotildeStructure(tempDir)).To(Succeed())
+		clotildeRoot = filepath.Join(tempDir, config.ClotildeDir)
+		store = session.NewFileStore(clotildeRoot)
+
+		incognito := func(name string, unused time.Duration, status string) {
+			sess := session.NewIncognitoSession(name, "uuid-"+name)
+			sess.Metadata.LastAccessed = time.Now().Add(-unused)
+			sess.Metadata.Status = status
+			Expect(store.Create(sess)).To(Succeed())
+		}
+		incognito("leftover", 2*time.Hour, session.StatusIdle)
+		incognito("running", 2*time.Hour, session.StatusActive)
+		incognito("killed", 2*time.Hour, session.StatusActive)
+		killed, err := store.Get("killed")
+		Expect(err).NotTo(HaveOccurred())
+		exited := exec.Command(os.Args[0], "-test.run=^$")
+		Expect(exited.Run()).To(Succeed())
+		killed.Metadata.Process = &session.Process{PID: exited.Process.Pid, Host: session.CurrentProcess().Host}
+		Expect(store.Update(killed)).To(Succeed())
+		incognito("starting", time.Minute, session.StatusIdle)
+	})
+
+	AfterEach(func() {
+		_ = os.Chdir(originalWd)
+	})
+
+	run := func(args ...string) (string, string, error) { return runClotildeWithInput("", args...) }
+
+	exists := func(name string) bool {
+		_, err := store.Get(name)
+		return err == nil
+	}
+
+	It("removes incognito leftovers and rotates oversized logs on maintain", func() {
+		Expect(os.WriteFile(config.GetConfigPath(clotildeRoot), []byte(`{"logging": {"maxSizeKB": 1}}`), 0o644)).To(Succeed())
+		logPath := filepath.Join(config.GetSessionDir(clotildeRoot, "starting"), "claude.log")
+		Expect(os.WriteFile(logPath, bytes.Repeat([]byte("x"), 2048), 0o644)).To(Succeed())
+
+		out, _, err := run("maintain")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(MatchRegexp(`Deleted incognito session\(s\) left behind: (leftover, killed|killed, leftover)`))
+		Expect(out).To(ContainSubstring("Rotated the session log of starting"))
+		Expect(exists("leftover")).To(BeFalse())
+		Expect(exists("killed")).To(BeFalse())
+		Expect(exists("running")).To(BeTrue())
+		Expect(exists("starting")).To(BeTrue())
+		Expect(logPath + ".1").To(BeAnExistingFile())
+		Expect(logPath).NotTo(BeAnExistingFile())
+	})
+
+	It("runs due tasks after commands only when enabled", func() {
+		_, _, err := run("list")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(exists("leftover")).To(BeTrue())
+
+		Expect(os.WriteFile(config.GetConfigPath(clotildeRoot), []byte(`{"maintenance": {"background": true}}`), 0o644)).To(Succeed())
+		_, errOut, err := run("list")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(errOut).To(ContainSubstring("Deleted incognito session(s) left behind:"))
+		Expect(exists("leftover")).To(BeFalse())
+		Expect(filepath.Join(config.GetSessionsDir(clotildeRoot), scheduler.StateFile)).To(BeAnExistingFile())
+	})
+})
@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fgrehm/clotilde/internal/claude"
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/registry"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/ui"
+	"github.com/fgrehm/clotilde/internal/util"
+)
+
+func newMigratePathCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate-path --from <old-path>",
+		Short: "Move Claude transcripts over after the project directory moved",
+		Long: `Claude Code keeps transcripts in a folder named after the project's path
+(~/.claude/projects/<encoded-path>), so moving or renaming a repository
+orphans them and sessions can no longer be resumed.
+
+Run this from the project's new location with the path it used to have. The
+contents of the old transcript folder are moved into the new one (files that
+already exist there are left alone and reported), and the transcript paths
+stored in session metadata are updated.`,
+		Example: `  cd ~/code/new-name && clotilde migrate-path --from ~/code/old-name
+  clotilde migrate-path --from /old/checkout --dry-run`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clotildeRoot, err := config.FindClotildeRoot()
+			if err != nil {
+				return errNoSessions()
+			}
+			projectRoot, err := config.FindProjectRoot()
+			if err != nil {
+				return err
+			}
+			fromFlag, _ := cmd.Flags().GetString("from")
+			from, err := filepath.Abs(fromFlag)
+			if err != nil {
+				return fmt.Errorf("invalid --from: %w", err)
+			}
+			if from == projectRoot {
+				return fmt.Errorf("--from is the project's current path (%s)", projectRoot)
+			}
+			homeDir, err := util.HomeDir()
+			if err != nil {
+				return err
+			}
+
+			oldDir := filepath.Join(claude.ConfigDir(homeDir), "projects", claude.EncodeProjectPath(from))
+			newDir := filepath.Join(claude.ConfigDir(homeDir), "projects", claude.EncodeProjectPath(projectRoot))
+			out := cmd.OutOrStdout()
+
+			if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+				entries, err := os.ReadDir(oldDir)
+				if err != nil && !os.IsNotExist(err) {
+					return fmt.Errorf("failed to read %s: %w", oldDir, err)
+				}
+				_, _ = fmt.Fprintf(out, "Would move %d item(s) from %s to %s\n", len(entries), oldDir, newDir)
+				return nil
+			}
+
+			// Storage kept outside the project records where the project is
+			if err := config.RelinkDataStorage(clotildeRoot, projectRoot); err != nil {
+				return fmt.Errorf("failed to update session storage: %w", err)
+			}
+
+			moved, conflicts, err := moveProjectData(oldDir, newDir)
+			if err != nil {
+				return err
+			}
+			updated, err := rewriteTranscriptPaths(session.NewFileStore(clotildeRoot), oldDir, newDir)
+			if err != nil {
+				return err
+			}
+
+			// Best effort: 'clotilde projects' should list the new location
+			if reg, err := registry.Load(); err == nil {
+				reg.Remove(from)
+				reg.Touch(projectRoot)
+				_ = reg.Save()
+			}
+
+			_, _ = fmt.Fprintln(out, ui.Success(fmt.Sprintf("Moved %d item(s) from %s to %s", moved, oldDir, newDir)))
+			_, _ = fmt.Fprintf(out, "Updated transcript paths of %d session(s)\n", updated)
+			for _, name := range conflicts {
+				_, _ = fmt.Fprintln(out, ui.Warning(fmt.Sprintf("Left %s in %s: it already exists in the new folder", name, oldDir)))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().String("from", "", "The project's previous path")
+	cmd.Flags().Bool("dry-run", false, "Show what would be moved without changing anything")
+	_ = cmd.MarkFlagRequired("from")
+	_ = cmd.MarkFlagDirname("from")
+	return cmd
+}
+
+// moveProjectData moves everything in oldDir into newDir, renaming the whole
+// folder when newDir doesn't exist yet. Entries already present in newDir are
+// skipped and returned as conflicts. A missing oldDir moves nothing.
+func moveProjectData(oldDir, newDir string) (moved int, conflicts []string, err error) {
+	entries, err := os.ReadDir(oldDir)
+	if os.IsNotExist(err) {
+		return 0, nil, nil
+	}
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to read %s: %w", oldDir, err)
+	}
+
+	if !util.DirExists(newDir) {
+		if err := util.EnsureDir(filepath.Dir(newDir)); err != nil {
+			return 0, nil, err
+		}
+		if err := os.Rename(oldDir, newDir); err != nil {
+			return 0, nil, fmt.Errorf("failed to move transcripts: %w", err)
+		}
+		return len(entries), nil, nil
+	}
+
+	for _, entry := range entries {
+		src := filepath.Join(oldDir, entry.Name())
+		dst := filepath.Join(newDir, entry.Name())
+		if _, err := os.Lstat(dst); err == nil {
+			conflicts = append(conflicts, entry.Name())
+			continue
+		}
+		if err := os.Rename(src, dst); err != nil {
+			return moved, conflicts, fmt.Errorf("failed to move %s: %w", entry.Name(), err)
+		}
+		moved++
+	}
+	// Only succeeds once nothing was left behind
+	_ = os.Remove(oldDir)
+	return moved, conflicts, nil
+}
+
+// rewriteTranscriptPaths points transcript paths stored in session metadata
+// (current and superseded) at newDir instead of oldDir. It returns the number
+// of sessions changed.
+func rewriteTranscriptPaths(store session.Store, oldDir, newDir string) (int, error) {
+	sessions, err := store.List()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	rewrite := func(path *string) bool {
+		rest, ok := strings.CutPrefix(*path, oldDir+string(filepath.Separator))
+		if !ok {
+			return false
+		}
+		*path = filepath.Join(newDir, rest)
+		return true
+	}
+
+	var updated int
+	for _, sess := range sessions {
+		changed := rewrite(&sess.Metadata.TranscriptPath)
+		for i := range sess.Metadata.PreviousSessions {
+			if rewrite(&sess.Metadata.PreviousSessions[i].TranscriptPath) {
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+		if err := store.Update(sess); err != nil {
+			return updated, fmt.Errorf("failed to update session '%s': %w", sess.Name, err)
+		}
+		updated++
+	}
+	return updated, nil
+}
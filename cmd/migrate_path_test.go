@@ -0,0 +1,118 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/claude"
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/session"
+)
+
+var _ = Describe("migrate-path", func() {
+	var (
+		tempDir     string
+		projectRoot string
+		claudeDir   string
+		originalWd  string
+		store       session.Store
+	)
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = filepath.EvalSymlinks(GinkgoT().TempDir())
+		Expect(err).NotTo(HaveOccurred())
+		claudeDir = filepath.Join(tempDir, "claude")
+		GinkgoT().Setenv("CLAUDE_CONFIG_DIR", claudeDir)
+		GinkgoT().Setenv("XDG_CONFIG_HOME", filepath.Join(tempDir, "xdg-config"))
+		GinkgoT().Setenv("XDG_DATA_HOME", filepath.Join(tempDir, "xdg-data"))
+
+		projectRoot = filepath.Join(tempDir, "new-name")
+		Expect(config.EnsureClotildeStructure(projectRoot)).To(Succeed())
+		originalWd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Chdir(projectRoot)).To(Succeed())
+
+		store = session.NewFileStore(filepath.Join(projectRoot, config.ClotildeDir))
+	})
+
+	AfterEach(func() {
+		_ = os.Chdir(originalWd)
+	})
+
+	projectsDir := func(path string) string {
+		return filepath.Join(claudeDir, "projects", claude.EncodeProjectPath(path))
+	}
+
+	run := func(args ...string) (string, error) {
+		return runClotilde(append([]string{"migrate-path"}, args...)...)
+	}
+
+	It("moves transcripts and updates stored transcript paths", func() {
+		oldRoot := filepath.Join(tempDir, "old-name")
+		oldDir := projectsDir(oldRoot)
+		Expect(os.MkdirAll(filepath.Join(oldDir, "uuid-1", "subagents"), 0o755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(oldDir, "uuid-1.jsonl"), []byte("{}\n"), 0o644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(oldDir, "uuid-0.jsonl"), []byte("{}\n"), 0o644)).To(Succeed())
+
+		sess := session.NewSession("moved", "uuid-1")
+		sess.Metadata.TranscriptPath = filepath.Join(oldDir, "uuid-1.jsonl")
+		sess.Metadata.PreviousSessions = []session.PreviousSession{{SessionID: "uuid-0", TranscriptPath: filepath.Join(oldDir, "uuid-0.jsonl")}}
+		Expect(store.Create(sess)).To(Succeed())
+
+		out, err := run("--from", oldRoot)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(ContainSubstring("Moved 3 item(s)"))
+		Expect(out).To(ContainSubstring("Updated transcript paths of 1 session(s)"))
+
+		newDir := projectsDir(projectRoot)
+		Expect(filepath.Join(newDir, "uuid-1.jsonl")).To(BeAnExistingFile())
+		Expect(filepath.Join(newDir, "uuid-1", "subagents")).To(BeADirectory())
+		Expect(oldDir).NotTo(BeADirectory())
+
+		migrated, err := store.Get("moved")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(migrated.Metadata.TranscriptPath).To(Equal(filepath.Join(newDir, "uuid-1.jsonl")))
+		Expect(migrated.Metadata.PreviousSessions[0].TranscriptPath).To(Equal(filepath.Join(newDir, "uuid-0.jsonl")))
+	})
+
+	It("leaves files that already exist in the new folder", func() {
+		oldRoot := filepath.Join(tempDir, "old-name")
+		oldDir, newDir := projectsDir(oldRoot), projectsDir(projectRoot)
+		Expect(os.MkdirAll(oldDir, 0o755)).To(Succeed())
+		Expect(os.MkdirAll(newDir, 0o755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(oldDir, "a.jsonl"), []byte("old\n"), 0o644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(oldDir, "b.jsonl"), []byte("old\n"), 0o644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(newDir, "a.jsonl"), []byte("new\n"), 0o644)).To(Succeed())
+
+		out, err := run("--from", oldRoot)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(ContainSubstring("Moved 1 item(s)"))
+		Expect(out).To(ContainSubstring("Left a.jsonl"))
+
+		data, err := os.ReadFile(filepath.Join(newDir, "a.jsonl"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(Equal("new\n"))
+		Expect(filepath.Join(newDir, "b.jsonl")).To(BeAnExistingFile())
+		Expect(filepath.Join(oldDir, "a.jsonl")).To(BeAnExistingFile())
+	})
+
+	It("changes nothing with --dry-run", func() {
+		oldRoot := filepath.Join(tempDir, "old-name")
+		Expect(os.MkdirAll(projectsDir(oldRoot), 0o755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(projectsDir(oldRoot), "a.jsonl"), nil, 0o644)).To(Succeed())
+
+		out, err := run("--from", oldRoot, "--dry-run")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(ContainSubstring("Would move 1 item(s)"))
+		Expect(projectsDir(projectRoot)).NotTo(BeADirectory())
+	})
+
+	It("rejects the current path", func() {
+		_, err := run("--from", projectRoot)
+		Expect(err).To(MatchError(ContainSubstring("--from is the project's current path")))
+	})
+})
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/ui"
+)
+
+// pickSession shows the session picker with a preview pane, restoring the
+// preview layout saved in the global config and saving it again if the user
+// toggled or resized the pane. Yolo sessions are flagged from their settings,
+// and stars toggled in the picker are saved. Returns nil when the picker is
+// cancelled.
+func pickSession(clotildeRoot string, store session.Store, sessions []*session.Session, title string) (*session.Session, error) {
+	saved, err := config.GlobalPicker()
+	if err != nil {
+		// A broken config shouldn't block picking a session
+		_, _ = fmt.Fprintln(os.Stderr, ui.Warning(fmt.Sprintf("Ignoring picker layout: %v", err)))
+	}
+
+	picker := ui.NewPicker(sessions, title).WithPreview().
+		WithPreviewLayout(!saved.HidePreview, saved.PreviewWidth).
+		WithYolo(yoloSessions(store, sessions)).
+		WithStarring()
+	initial := picker
+
+	final, err := ui.RunPickerModel(picker)
+	if err != nil {
+		return nil, err
+	}
+
+	if final.ShowPreview != initial.ShowPreview || final.PreviewPercent != initial.PreviewPercent {
+		layout := config.Picker{HidePreview: !final.ShowPreview, PreviewWidth: final.PreviewPercent}
+		if err := config.SaveGlobalPicker(layout); err != nil {
+			_, _ = fmt.Fprintln(os.Stderr, ui.Warning(fmt.Sprintf("Failed to save picker layout: %v", err)))
+		}
+	}
+
+	saveStars(clotildeRoot, store, final.Starred)
+
+	if final.Cancelled {
+		return nil, nil
+	}
+	return final.Selected, nil
+}
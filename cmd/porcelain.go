@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fgrehm/clotilde/internal/claude"
+	"github.com/fgrehm/clotilde/internal/session"
+)
+
+// Porcelain output (--porcelain on list, inspect and stats, and the summary
+// 'clotilde' prints without a terminal) is a contract with scripts, unlike
+// the human format, which is free to change:
+//
+//   - Every line is tab-separated fields. list prints one line per session
+//     with the fields of porcelainListFields; inspect, stats and the summary
+//     print "key<TAB>value..." lines in the order of their *Keys, a key
+//     repeated once per item for lists (and absent when the list is empty).
+//   - Fields and keys are never reordered, renamed or removed between
+//     versions; new ones are only ever appended at the end.
+//   - Unset values are "-". Times are RFC 3339 in UTC, durations whole
+//     seconds, sizes bytes, and booleans "true" or "false".
+//   - Tabs, newlines and backslashes in values are escaped as \t, \n and \\.
+//   - There are no headers, colors or explanatory messages; no sessions is
+//     no output.
+//
+// docs/porcelain.md documents these lists; update it alongside them.
+
+// porcelainListFields are the fields of a 'list --porcelain' line.
+var porcelainListFields = []string{"name", "uuid", "status", "type", "parent", "incognito", "model", "last-used", "created", "starred"}
+
+// porcelainInspectKeys are the keys of 'inspect --porcelain', in order.
+var porcelainInspectKeys = []string{
+	"name", "uuid", "status", "type", "parent", "incognito", "created", "last-accessed", "expires",
+	"last-exit-code", "last-exit-signal", "last-exit-at", "last-model", "model", "output-style",
+	"context", "transcript", "transcript-bytes", "fork", "previous-uuid", "starred",
+}
+
+// porcelainStatsKeys are the keys of 'stats --porcelain', in order.
+var porcelainStatsKeys = []string{"name", "turns", "active-seconds", "last-activity", "approximate", "model", "model-span"}
+
+// porcelainSummaryKeys are the keys of the summary 'clotilde' prints before
+// its help when stdout isn't a terminal, in order.
+var porcelainSummaryKeys = []string{"project", "sessions", "last-session", "last-used"}
+
+// addPorcelainFlag adds --porcelain to cmd.
+func addPorcelainFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("porcelain", false, "Stable tab-separated output for scripts (see docs/porcelain.md)")
+}
+
+// isPorcelain reports whether --porcelain was given.
+func isPorcelain(cmd *cobra.Command) bool {
+	porcelain, _ := cmd.Flags().GetBool("porcelain")
+	return porcelain
+}
+
+var porcelainEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+// porcelainValue formats a value for porcelain output.
+func porcelainValue(v any) string {
+	var s string
+	switch v := v.(type) {
+	case string:
+		s = v
+	case time.Time:
+		if !v.IsZero() {
+			s = v.UTC().Format(time.RFC3339)
+		}
+	case time.Duration:
+		s = strconv.FormatInt(int64(v.Seconds()), 10)
+	default:
+		s = fmt.Sprint(v)
+	}
+	if s == "" {
+		return "-"
+	}
+	return porcelainEscaper.Replace(s)
+}
+
+// writePorcelainLine writes values as one tab-separated porcelain line.
+func writePorcelainLine(w io.Writer, values ...any) {
+	fields := make([]string, len(values))
+	for i, v := range values {
+		fields[i] = porcelainValue(v)
+	}
+	_, _ = fmt.Fprintln(w, strings.Join(fields, "\t"))
+}
+
+// porcelainType is a session's type for porcelain output.
+func porcelainType(sess *session.Session) string {
+	if sess.Metadata.IsForkedSession {
+		return "fork"
+	}
+	return "session"
+}
+
+// writePorcelainList writes a 'list --porcelain' line per session.
+// modelAndLastUsed returns the model and last use of a session.
+func writePorcelainList(w io.Writer, sessions []*session.Session, modelAndLastUsed func(*session.Session) (string, time.Time)) {
+	for _, sess := range sessions {
+		model, lastUsed := modelAndLastUsed(sess)
+		if model == "-" {
+			model = ""
+		}
+		values := map[string]any{
+			"name": sess.Name, "uuid": sess.Metadata.SessionID, "status": sess.Status(), "type": porcelainType(sess),
+			"parent": sess.Metadata.ParentSession, "incognito": sess.Metadata.IsIncognito,
+			"model": model, "last-used": lastUsed, "created": sess.Metadata.Created, "starred": sess.Metadata.Starred,
+		}
+		line := make([]any, len(porcelainListFields))
+		for i, field := range porcelainListFields {
+			line[i] = values[field]
+		}
+		writePorcelainLine(w, line...)
+	}
+}
+
+// writePorcelainInspect writes 'inspect --porcelain' for sess.
+func writePorcelainInspect(w io.Writer, clotildeRoot string, sess *session.Session, store session.Store, transcriptPath string) {
+	var exitCode, exitSignal any = "", ""
+	var exitAt time.Time
+	if exit := sess.Metadata.LastExit; exit != nil {
+		exitCode, exitSignal, exitAt = exit.Code, exit.Signal, exit.At
+	}
+	var lastModel string
+	if sess.Metadata.TranscriptPath != "" {
+		lastModel, _ = claude.CachedModelAndLastTime(claude.StatsCachePath(clotildeRoot, sess.Name), sess.Metadata.TranscriptPath)
+	}
+	var model, outputStyle string
+	if settings, err := store.LoadSettings(sess.Name); err == nil && settings != nil {
+		model, outputStyle = settings.Model, settings.OutputStyle
+	}
+	var transcriptBytes any = ""
+	if info, err := os.Stat(transcriptPath); transcriptPath != "" && err == nil && info.Mode().IsRegular() {
+		transcriptBytes = info.Size()
+	} else {
+		transcriptPath = ""
+	}
+
+	values := map[string]any{
+		"name": sess.Name, "uuid": sess.Metadata.SessionID, "status": sess.Status(), "type": porcelainType(sess),
+		"parent": sess.Metadata.ParentSession, "incognito": sess.Metadata.IsIncognito,
+		"created": sess.Metadata.Created, "last-accessed": sess.Metadata.LastAccessed, "expires": sess.Metadata.ExpiresAt,
+		"last-exit-code": exitCode, "last-exit-signal": exitSignal, "last-exit-at": exitAt, "last-model": lastModel,
+		"model": model, "output-style": outputStyle, "context": sess.Metadata.Context,
+		"transcript": transcriptPath, "transcript-bytes": transcriptBytes, "starred": sess.Metadata.Starred,
+	}
+	lists := map[string][]string{"fork": nil, "previous-uuid": nil}
+	if sessions, err := store.List(); err == nil {
+		for _, child := range forkChildren(sessions, sess.Name) {
+			lists["fork"] = append(lists["fork"], child.Name)
+		}
+	}
+	for _, prev := range sess.Metadata.PreviousSessions {
+		lists["previous-uuid"] = append(lists["previous-uuid"], prev.SessionID)
+	}
+
+	for _, key := range porcelainInspectKeys {
+		if items, ok := lists[key]; ok {
+			for _, item := range items {
+				writePorcelainLine(w, key, item)
+			}
+			continue
+		}
+		writePorcelainLine(w, key, values[key])
+	}
+}
+
+// writePorcelainStats writes 'stats --porcelain' for a session's stats:
+// "model" lines are "model<TAB>family<TAB>turns", most used first, and
+// "model-span" lines "model-span<TAB>family<TAB>start<TAB>end<TAB>turns",
+// oldest first.
+func writePorcelainStats(w io.Writer, name string, stats claude.TranscriptStats) {
+	models := modelsByTurns(stats)
+	for _, key := range porcelainStatsKeys {
+		switch key {
+		case "name":
+			writePorcelainLine(w, key, name)
+		case "turns":
+			writePorcelainLine(w, key, stats.Messages)
+		case "active-seconds":
+			writePorcelainLine(w, key, stats.ActiveTime)
+		case "last-activity":
+			writePorcelainLine(w, key, stats.LastActivity)
+		case "approximate":
+			writePorcelainLine(w, key, stats.Approximate)
+		case "model":
+			for _, model := range models {
+				writePorcelainLine(w, key, model, stats.Models[model])
+			}
+		case "model-span":
+			for _, span := range stats.ModelSpans {
+				writePorcelainLine(w, key, span.Model, span.Start, span.End, span.Turns)
+			}
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/claude"
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/session"
+)
+
+var _ = Describe("Porcelain output", func() {
+	var (
+		tempDir      string
+		homeDir      string
+		clotildeRoot string
+		originalWd   string
+		store        session.Store
+	)
+
+	created := time.Date(2026, 3, 1, 14, 2, 11, 0, time.FixedZone("BRT", -3*3600))
+
+	BeforeEach(func() {
+		tempDir = GinkgoT().TempDir()
+		homeDir = filepath.Join(tempDir, "home")
+		GinkgoT().Setenv("HOME", homeDir)
+		GinkgoT().Setenv("XDG_CONFIG_HOME", filepath.Join(tempDir, "xdg"))
+		GinkgoT().Setenv("CLAUDE_CONFIG_DIR", "")
+
+		var err error
+		originalWd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Chdir(tempDir)).To(Succeed())
+
+		Expect(config.EnsureClotildeStructure(tempDir)).To(Succeed())
+		clotildeRoot = filepath.Join(tempDir, config.ClotildeDir)
+		store = session.NewFileStore(clotildeRoot)
+
+		parent := session.NewSession("parent", "uuid-parent")
+		parent.Metadata.Created = created
+		parent.Metadata.LastAccessed = created.Add(time.Hour)
+		parent.Metadata.Context = "line one\nline\ttwo"
+		parent.Metadata.PreviousSessions = []session.PreviousSession{{SessionID: "uuid-old", Reason: session.RotationClear}}
+		Expect(store.Create(parent)).To(Succeed())
+
+		fork := session.NewSession("child", "uuid-child")
+		fork.Metadata.Created = created
+		fork.Metadata.LastAccessed = created
+		fork.Metadata.IsForkedSession = true
+		fork.Metadata.ParentSession = "parent"
+		fork.Metadata.Starred = true
+		Expect(store.Create(fork)).To(Succeed())
+		Expect(store.SaveSettings("child", &session.Settings{Model: "haiku"})).To(Succeed())
+	})
+
+	AfterEach(func() {
+		_ = os.Chdir(originalWd)
+	})
+
+	run := func(args ...string) string {
+		out, err := runClotilde(args...)
+		Expect(err).NotTo(HaveOccurred())
+		return out
+	}
+
+	It("lists sessions as tab-separated lines in a fixed field order, starred first", func() {
+		Expect(run("list", "--porcelain")).To(Equal(
+			"child\tuuid-child\tidle\tfork\tparent\tfalse\thaiku\t2026-03-01T17:02:11Z\t2026-03-01T17:02:11Z\ttrue\n" +
+				"parent\tuuid-parent\tidle\tsession\t-\tfalse\t-\t2026-03-01T18:02:11Z\t2026-03-01T17:02:11Z\tfalse\n"))
+	})
+
+	It("prints nothing when there are no sessions to list", func() {
+		Expect(run("list", "--porcelain", "--status", "archived")).To(BeEmpty())
+	})
+
+	It("prints a session's keys in a fixed order, escaping values", func() {
+		Expect(run("inspect", "parent", "--porcelain")).To(Equal(
+			"name\tparent\n" +
+				"uuid\tuuid-parent\n" +
+				"status\tidle\n" +
+				"type\tsession\n" +
+				"parent\t-\n" +
+				"incognito\tfalse\n" +
+				"created\t2026-03-01T17:02:11Z\n" +
+				"last-accessed\t2026-03-01T18:02:11Z\n" +
+				"expires\t-\n" +
+				"last-exit-code\t-\n" +
+				"last-exit-signal\t-\n" +
+				"last-exit-at\t-\n" +
+				"last-model\t-\n" +
+				"model\t-\n" +
+				"output-style\t-\n" +
+				"context\tline one\\nline\\ttwo\n" +
+				"transcript\t-\n" +
+				"transcript-bytes\t-\n" +
+				"fork\tchild\n" +
+				"previous-uuid\tuuid-old\n" +
+				"starred\tfalse\n"))
+	})
+
+	It("prints a session's stats as key-value lines", func() {
+		path := claude.TranscriptPath(homeDir, clotildeRoot, "uuid-child")
+		Expect(os.MkdirAll(filepath.Dir(path), 0o755)).To(Succeed())
+		Expect(os.WriteFile(path, []byte(
+			`{"type":"assistant","timestamp":"2026-01-05T10:00:00Z","message":{"model":"claude-opus-4-20250514"}}`+"\n"+
+				`{"type":"assistant","timestamp":"2026-01-05T10:01:00Z","message":{"model":"claude-opus-4-20250514"}}`+"\n"), 0o644)).To(Succeed())
+
+		Expect(run("stats", "child", "--porcelain")).To(Equal(
+			"name\tchild\n" +
+				"turns\t2\n" +
+				"active-seconds\t60\n" +
+				"last-activity\t2026-01-05T10:01:00Z\n" +
+				"approximate\tfalse\n" +
+				"model\topus\t2\n" +
+				"model-span\topus\t2026-01-05T10:00:00Z\t2026-01-05T10:01:00Z\t2\n"))
+	})
+})
@@ -0,0 +1,16 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+
+	"github.com/fgrehm/clotilde/internal/ui"
+)
+
+// runWithProgress runs a long operation with per-item progress on the command's
+// output: a live spinner in a terminal, plain lines for scripts and pipes.
+func runWithProgress(cmd *cobra.Command, title string, work func(ui.ProgressReporter) error) error {
+	return ui.RunWithProgress(cmd.OutOrStdout(), title, isatty.IsTerminal(os.Stdout.Fd()), work)
+}
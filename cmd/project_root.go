@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/errs"
+	"github.com/fgrehm/clotilde/internal/i18n"
+)
+
+// readOnlyAnnotation marks commands that only read session data and therefore
+// accept --root/-C to operate on another project.
+const readOnlyAnnotation = "clotilde.readOnly"
+
+// projectRootOverride is set via the --root/-C flag
+var projectRootOverride string
+
+// readOnly returns the annotations map for commands that support --root/-C.
+func readOnly() map[string]string {
+	return map[string]string{readOnlyAnnotation: "true"}
+}
+
+// checkProjectRootOverride rejects --root/-C for commands that modify sessions.
+func checkProjectRootOverride(cmd *cobra.Command, _ []string) error {
+	if projectRootOverride == "" || cmd.Annotations[readOnlyAnnotation] == "true" {
+		return nil
+	}
+	return fmt.Errorf("--root/-C is only supported by read-only commands (%s)", strings.Join(readOnlyCommands(cmd.Root()), ", "))
+}
+
+// readOnlyCommands lists the commands under parent that accept --root/-C, as
+// typed after "clotilde".
+func readOnlyCommands(parent *cobra.Command) []string {
+	var names []string
+	for _, sub := range parent.Commands() {
+		if sub.Annotations[readOnlyAnnotation] == "true" {
+			names = append(names, strings.TrimPrefix(sub.CommandPath(), sub.Root().Name()+" "))
+		}
+		names = append(names, readOnlyCommands(sub)...)
+	}
+	return names
+}
+
+// findClotildeRoot locates the clotilde root for read commands. With --root/-C
+// the search starts at the given path (a project directory, any directory
+// inside it, or the .claude/clotilde directory itself) instead of the cwd.
+func findClotildeRoot() (string, error) {
+	if projectRootOverride == "" {
+		return config.FindClotildeRoot()
+	}
+
+	if _, err := os.Stat(projectRootOverride); err != nil {
+		return "", fmt.Errorf("invalid --root path: %w", err)
+	}
+	clotildeRoot, err := config.ClotildeRootFromPath(projectRootOverride)
+	if err != nil {
+		return "", errs.NotInProject("no clotilde sessions found under %s", projectRootOverride)
+	}
+	return clotildeRoot, nil
+}
+
+// errNoSessions is returned by commands that need an existing project when no
+// .claude/clotilde is found.
+func errNoSessions() error {
+	return errs.NotInProject("%s", i18n.T(i18n.ErrNoSessions))
+}
@@ -0,0 +1,84 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/errs"
+	"github.com/fgrehm/clotilde/internal/session"
+)
+
+var _ = Describe("--root flag", func() {
+	var (
+		tempDir    string
+		otherDir   string
+		originalWd string
+	)
+
+	BeforeEach(func() {
+		tempDir = GinkgoT().TempDir()
+
+		var err error
+		originalWd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+
+		// cwd has no clotilde root; the sessions live in another project
+		cwd := filepath.Join(tempDir, "elsewhere")
+		Expect(os.Mkdir(cwd, 0o755)).To(Succeed())
+		Expect(os.Chdir(cwd)).To(Succeed())
+
+		otherDir = filepath.Join(tempDir, "other-project")
+		Expect(config.EnsureClotildeStructure(otherDir)).To(Succeed())
+		store := session.NewFileStore(filepath.Join(otherDir, config.ClotildeDir))
+		Expect(store.Create(session.NewSession("remote-session", "uuid-remote"))).To(Succeed())
+	})
+
+	AfterEach(func() {
+		_ = os.Chdir(originalWd)
+	})
+
+	run := runClotilde
+
+	It("lists sessions of another project", func() {
+		out, err := run("list", "--root", otherDir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(ContainSubstring("remote-session"))
+	})
+
+	It("inspects a session of another project with -C", func() {
+		out, err := run("-C", otherDir, "inspect", "remote-session")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(ContainSubstring("UUID: uuid-remote"))
+	})
+
+	It("accepts the .claude/clotilde directory itself", func() {
+		out, err := run("list", "--root", filepath.Join(otherDir, config.ClotildeDir))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(ContainSubstring("remote-session"))
+	})
+
+	It("fails when the path has no clotilde sessions", func() {
+		_, err := run("list", "--root", tempDir)
+		Expect(err).To(MatchError(ContainSubstring("no clotilde sessions found")))
+		Expect(errs.ExitCode(err)).To(Equal(errs.ExitNotInProject))
+	})
+
+	It("fails when the path does not exist", func() {
+		_, err := run("list", "--root", filepath.Join(tempDir, "missing"))
+		Expect(err).To(MatchError(ContainSubstring("invalid --root path")))
+	})
+
+	It("is rejected by commands that modify sessions", func() {
+		_, err := run("delete", "remote-session", "--force", "--root", otherDir)
+		Expect(err).To(MatchError(ContainSubstring("only supported by read-only commands")))
+		Expect(err).To(MatchError(ContainSubstring("backup create, context preview, doctor,")))
+		Expect(err.Error()).NotTo(ContainSubstring("delete"))
+
+		store := session.NewFileStore(filepath.Join(otherDir, config.ClotildeDir))
+		Expect(store.Exists("remote-session")).To(BeTrue())
+	})
+})
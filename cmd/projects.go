@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-isatty"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/fgrehm/clotilde/internal/claude"
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/registry"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/ui"
+	"github.com/fgrehm/clotilde/internal/util"
+)
+
+func newProjectsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "projects",
+		Short: "Show a summary of sessions across all projects",
+		Long: `List every project where clotilde has been used, with its session count,
+most recent session, and total disk usage (session folders plus Claude Code
+transcripts).
+
+Projects are recorded in a global registry ($XDG_DATA_HOME/clotilde/projects.json)
+whenever 'clotilde init' or 'clotilde start' runs. In a terminal, select a
+project to open its dashboard.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			reg, err := registry.Load()
+			if err != nil {
+				return err
+			}
+
+			prune, _ := cmd.Flags().GetBool("prune")
+			if prune {
+				// Collected first: Remove shifts the projects after the one removed
+				var missing []string
+				for _, p := range reg.Projects {
+					if _, found, _ := config.ProjectClotildeRoot(p.Path); !found {
+						missing = append(missing, p.Path)
+					}
+				}
+				for _, path := range missing {
+					reg.Remove(path)
+				}
+				if err := reg.Save(); err != nil {
+					return err
+				}
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), ui.Success(fmt.Sprintf("Removed %d missing project(s) from the registry", len(missing))))
+			}
+
+			if len(reg.Projects) == 0 {
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), "No projects registered yet.")
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), "\nProjects are registered when you run:")
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), "  clotilde start <session-name>")
+				return nil
+			}
+
+			homeDir, _ := util.HomeDir()
+			summaries := make([]projectSummary, len(reg.Projects))
+			for i, p := range reg.Projects {
+				summaries[i] = summarizeProject(p.Path, homeDir)
+			}
+
+			interactive, _ := cmd.Flags().GetBool("interactive")
+			if !interactive || !isatty.IsTerminal(os.Stdout.Fd()) {
+				showProjectsTable(cmd, summaries, homeDir)
+				return nil
+			}
+
+			selected, err := pickProject(summaries, homeDir)
+			if err != nil || selected == "" {
+				return err
+			}
+			if err := os.Chdir(selected); err != nil {
+				return fmt.Errorf("failed to open project: %w", err)
+			}
+			runDashboard(cmd, nil)
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("interactive", true, "Pick a project to open its dashboard (TTY only)")
+	cmd.Flags().Bool("prune", false, "Remove projects that no longer exist from the registry")
+
+	return cmd
+}
+
+// projectSummary is the cross-project overview for a single registered project.
+type projectSummary struct {
+	Path       string
+	Missing    bool // .claude/clotilde no longer exists
+	Sessions   int
+	Recent     string // Name of the most recently used session
+	RecentTime time.Time
+	DiskUsage  int64 // Session folders plus Claude Code transcripts
+}
+
+// summarizeProject collects session count, most recent session, and disk usage for a project.
+func summarizeProject(projectRoot, homeDir string) projectSummary {
+	summary := projectSummary{Path: projectRoot}
+
+	clotildeRoot, found, _ := config.ProjectClotildeRoot(projectRoot)
+	if !found {
+		summary.Missing = true
+		return summary
+	}
+
+	store := session.NewFileStore(clotildeRoot)
+	sessions, err := store.List()
+	if err == nil {
+		summary.Sessions = len(sessions)
+		for _, sess := range sessions {
+			_, lastUsed := extractModelAndLastUsed(clotildeRoot, sess, store)
+			if lastUsed.After(summary.RecentTime) {
+				summary.Recent = sess.Name
+				summary.RecentTime = lastUsed
+			}
+		}
+	}
+
+	// Errors only lead to an underestimate; the overview is informational
+	size, _ := util.DirSize(clotildeRoot)
+	if homeDir != "" {
+		transcripts, _ := util.DirSize(claude.ProjectDataDir(homeDir, clotildeRoot))
+		size += transcripts
+	}
+	summary.DiskUsage = size
+
+	return summary
+}
+
+// projectRow formats a summary as table columns: project, sessions, most recent, disk usage.
+func projectRow(s projectSummary, homeDir string) []string {
+	path := s.Path
+	if homeDir != "" && strings.HasPrefix(path, homeDir+string(filepath.Separator)) {
+		path = "~" + strings.TrimPrefix(path, homeDir)
+	}
+	if s.Missing {
+		return []string{path, "(missing)", "-", "-"}
+	}
+
+	recent := "-"
+	if s.Recent != "" {
+		recent = fmt.Sprintf("%s (%s)", s.Recent, util.FormatRelativeTime(s.RecentTime))
+	}
+	return []string{path, strconv.Itoa(s.Sessions), recent, util.FormatSize(s.DiskUsage)}
+}
+
+// showProjectsTable displays the project overview as a static table (for scripts/pipes)
+func showProjectsTable(cmd *cobra.Command, summaries []projectSummary, homeDir string) {
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Projects (%d total):\n", len(summaries))
+
+	table := tablewriter.NewWriter(cmd.OutOrStdout())
+	table.Header("PROJECT", "SESSIONS", "MOST RECENT", "DISK USAGE")
+	for _, s := range summaries {
+		_ = table.Append(projectRow(s, homeDir))
+	}
+	_ = table.Render()
+}
+
+// pickProject shows the project overview in an interactive table and returns
+// the selected project root, or "" if cancelled.
+func pickProject(summaries []projectSummary, homeDir string) (string, error) {
+	headers := []string{"Project", "Sessions", "Most Recent", "Disk Usage"}
+	var rows [][]string
+	paths := make(map[string]string) // displayed path -> project root
+	for _, s := range summaries {
+		if s.Missing {
+			continue // Nothing to open
+		}
+		row := projectRow(s, homeDir)
+		rows = append(rows, row)
+		paths[row[0]] = s.Path
+	}
+	if len(rows) == 0 {
+		fmt.Println("No existing projects to open.")
+		return "", nil
+	}
+
+	fmt.Printf("Projects (%d total)\n\n", len(rows))
+	selectedRow, err := ui.RunTable(ui.NewTable(headers, rows).WithSorting())
+	if err != nil {
+		return "", err
+	}
+	if len(selectedRow) == 0 {
+		return "", nil
+	}
+	return paths[selectedRow[0]], nil
+}
@@ -0,0 +1,100 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/claude"
+	"github.com/fgrehm/clotilde/internal/registry"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/testutil"
+)
+
+var _ = Describe("Projects Command", func() {
+	var (
+		tempDir    string
+		originalWd string
+		fakeClaude string
+	)
+
+	BeforeEach(func() {
+		tempDir = GinkgoT().TempDir()
+		GinkgoT().Setenv("HOME", filepath.Join(tempDir, "home"))
+
+		var err error
+		originalWd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+
+		fakeClaudeDir := filepath.Join(tempDir, "bin")
+		Expect(os.Mkdir(fakeClaudeDir, 0o755)).To(Succeed())
+		fakeClaude, _, err = testutil.CreateFakeClaude(fakeClaudeDir)
+		Expect(err).NotTo(HaveOccurred())
+
+		// Fake claude sends no messages; keep sessions instead of cleaning them up
+		claude.SessionUsedFunc = func(_ string, _ *session.Session) bool { return true }
+	})
+
+	AfterEach(func() {
+		_ = os.Chdir(originalWd)
+	})
+
+	run := func(dir string, args ...string) (string, error) {
+		Expect(os.Chdir(dir)).To(Succeed())
+		return runClotilde(append([]string{"--claude-bin", fakeClaude}, args...)...)
+	}
+
+	newProject := func(name string) string {
+		dir := filepath.Join(tempDir, name)
+		Expect(os.MkdirAll(filepath.Join(dir, ".claude"), 0o755)).To(Succeed())
+		return dir
+	}
+
+	It("shows a hint when no projects are registered", func() {
+		out, err := run(tempDir, "projects")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(ContainSubstring("No projects registered yet."))
+	})
+
+	It("registers projects on start and summarizes them", func() {
+		alpha := newProject("alpha")
+		beta := newProject("beta")
+
+		_, err := run(alpha, "start", "first")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = run(alpha, "start", "second")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = run(beta, "start", "only")
+		Expect(err).NotTo(HaveOccurred())
+
+		out, err := run(tempDir, "projects")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(ContainSubstring("Projects (2 total)"))
+		Expect(out).To(MatchRegexp(`alpha\s+│\s+2\s+│\s+second`))
+		Expect(out).To(MatchRegexp(`beta\s+│\s+1\s+│\s+only`))
+	})
+
+	It("marks and prunes projects that no longer exist", func() {
+		for _, name := range []string{"gone", "also-gone"} {
+			gone := newProject(name)
+			_, err := run(gone, "start", "doomed")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(os.Chdir(tempDir)).To(Succeed())
+			Expect(os.RemoveAll(gone)).To(Succeed())
+		}
+
+		out, err := run(tempDir, "projects")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(ContainSubstring("(missing)"))
+
+		out, err = run(tempDir, "projects", "--prune")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(ContainSubstring("Removed 2 missing project(s)"))
+
+		reg, err := registry.Load()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reg.Projects).To(BeEmpty())
+	})
+})
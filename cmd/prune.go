@@ -0,0 +1,287 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+
+	"github.com/fgrehm/clotilde/internal/app"
+	"github.com/fgrehm/clotilde/internal/claude"
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/ui"
+)
+
+func newPruneCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Delete expired or empty sessions",
+		Long: `Delete sessions whose expiry (set with 'start --expires' or 'fork --expires')
+has passed (--expired), or that have no conversation (--empty), together with
+their Claude Code transcripts and logs.
+
+Empty sessions were launched but Claude Code never wrote a transcript for
+them, or were created with --no-launch and never started. 'clotilde list'
+flags them in the health column.
+
+To delete expired sessions automatically whenever clotilde runs, set
+"expiry": {"autoPrune": true} in the project or global config. Sessions
+Claude Code exits from without a conversation are removed right away unless
+"emptySessions": {"autoRemove": false} is set.`,
+		Example: `  clotilde prune --expired
+  clotilde prune --empty --dry-run
+  clotilde prune --expired --empty --force`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			expired, _ := cmd.Flags().GetBool("expired")
+			empty, _ := cmd.Flags().GetBool("empty")
+			if !expired && !empty {
+				return fmt.Errorf("nothing to prune: pass --expired and/or --empty to choose which sessions to delete")
+			}
+
+			clotildeRoot, err := config.FindClotildeRoot()
+			if err != nil {
+				return errNoSessions()
+			}
+
+			store := session.NewFileStore(clotildeRoot)
+			now := time.Now()
+			candidates, err := pruneCandidates(clotildeRoot, store, now, expired, empty)
+			if err != nil {
+				return err
+			}
+
+			kind := "expired"
+			switch {
+			case expired && empty:
+				kind = "expired or empty"
+			case empty:
+				kind = "empty"
+			}
+			describe := func(sess *session.Session) string {
+				return describePrunable(clotildeRoot, sess, now)
+			}
+
+			out := cmd.OutOrStdout()
+			if len(candidates) == 0 {
+				_, _ = fmt.Fprintf(out, "No %s sessions.\n", kind)
+				return nil
+			}
+
+			if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+				_, _ = fmt.Fprintf(out, "Would delete %d %s session(s):\n", len(candidates), kind)
+				for _, sess := range candidates {
+					_, _ = fmt.Fprintf(out, "  %s\n", describe(sess))
+				}
+				return nil
+			}
+
+			if force, _ := cmd.Flags().GetBool("force"); !force {
+				confirmed, err := confirmPrune(cmd, clotildeRoot, store, kind, candidates, describe)
+				if err != nil {
+					return err
+				}
+				if !confirmed {
+					_, _ = fmt.Fprintln(out, "Cancelled.")
+					return nil
+				}
+			}
+
+			return runWithProgress(cmd, fmt.Sprintf("Deleting %s sessions", kind), func(progress ui.ProgressReporter) error {
+				var failed int
+				for _, sess := range candidates {
+					progress.Start(sess.Name)
+					// Per-file output would garble the progress display
+					if err := deleteSession(io.Discard, clotildeRoot, sess, store); err != nil {
+						progress.Fail(sess.Name, err)
+						failed++
+						continue
+					}
+					progress.Done(sess.Name, "")
+				}
+				if failed > 0 {
+					return fmt.Errorf("failed to delete %d of %d %s session(s)", failed, len(candidates), kind)
+				}
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().Bool("expired", false, "Delete sessions whose expiry has passed")
+	cmd.Flags().Bool("empty", false, "Delete sessions without a conversation (no transcript)")
+	cmd.Flags().Bool("dry-run", false, "Show what would be deleted without deleting anything")
+	cmd.Flags().BoolP("force", "f", false, "Skip confirmation prompt")
+
+	return cmd
+}
+
+// expiredSessions returns the sessions in store that have expired as of now.
+// Sessions Claude Code is running in are left out: they expire once it exits.
+func expiredSessions(store session.Store, now time.Time) ([]*session.Session, error) {
+	sessions, err := store.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	var expired []*session.Session
+	for _, sess := range sessions {
+		if sess.IsExpired(now) && sess.Status() != session.StatusActive {
+			expired = append(expired, sess)
+		}
+	}
+	return expired, nil
+}
+
+// pruneCandidates returns the sessions in store that are expired as of now
+// (with expired) or empty (with empty), each once. Active sessions are never
+// candidates.
+func pruneCandidates(clotildeRoot string, store session.Store, now time.Time, expired, empty bool) ([]*session.Session, error) {
+	sessions, err := store.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	var candidates []*session.Session
+	for _, sess := range sessions {
+		if sess.Status() == session.StatusActive {
+			continue
+		}
+		if (expired && sess.IsExpired(now)) || (empty && claude.EmptySession(clotildeRoot, sess)) {
+			candidates = append(candidates, sess)
+		}
+	}
+	return candidates, nil
+}
+
+// describePrunable formats a session name with why it can be pruned.
+func describePrunable(clotildeRoot string, sess *session.Session, now time.Time) string {
+	switch {
+	case sess.IsExpired(now):
+		return fmt.Sprintf("%s (expired %s)", sess.Name, sess.Metadata.ExpiresAt.Format("2006-01-02 15:04"))
+	case sess.Metadata.PendingLaunch:
+		return fmt.Sprintf("%s (never launched)", sess.Name)
+	default:
+		return fmt.Sprintf("%s (empty)", sess.Name)
+	}
+}
+
+// confirmPrune asks before deleting sessions, using a TUI dialog in a
+// terminal and a prompt otherwise. The "confirm" config applies as to single
+// deletions: when every session is below confirm.skipBelowTurns nothing is
+// asked, and when any requires typing, "prune" must be typed.
+func confirmPrune(cmd *cobra.Command, clotildeRoot string, store session.Store, kind string, candidates []*session.Session, describe func(*session.Session) string) (bool, error) {
+	title := fmt.Sprintf("Delete %d %s session(s)?", len(candidates), kind)
+
+	level := confirmSkip
+	details := make([]string, len(candidates))
+	var warnings []string
+	for i, sess := range candidates {
+		details[i] = describe(sess)
+		sessLevel, reasons := deletionConfirmation(clotildeRoot, sess, store)
+		level = max(level, sessLevel)
+		for _, reason := range reasons {
+			warnings = append(warnings, fmt.Sprintf("Warning: %s: %s", sess.Name, reason))
+		}
+	}
+	if level == confirmSkip {
+		return true, nil
+	}
+	phrase := ""
+	if level == confirmTyped {
+		phrase = "prune"
+	}
+
+	if isatty.IsTerminal(os.Stdout.Fd()) {
+		confirmModel := ui.NewConfirm(title, "This will permanently delete these sessions and their Claude Code data:").
+			WithDetails(append(details, warnings...)).WithDestructive()
+		if phrase != "" {
+			confirmModel = confirmModel.WithTypedConfirmation(phrase)
+		}
+		confirmed, err := ui.RunConfirm(confirmModel)
+		if err != nil {
+			return false, fmt.Errorf("confirmation dialog failed: %w", err)
+		}
+		return confirmed, nil
+	}
+
+	for _, line := range append(details, warnings...) {
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  %s\n", line)
+	}
+	return promptConfirm(cmd.OutOrStdout(), title, phrase)
+}
+
+// applyExpiryPolicy deletes expired sessions when "expiry.autoPrune" is
+// enabled and otherwise marks them expired, summarizing what it did.
+func applyExpiryPolicy(ctx context.Context, clotildeRoot string, store session.Store) (string, error) {
+	enabled, err := config.AutoPruneExpired(clotildeRoot)
+	if err != nil {
+		return "", err
+	}
+	candidates, err := expiredSessions(store, time.Now())
+	if err != nil {
+		return "", err
+	}
+	if !enabled {
+		marked := markExpired(store, candidates)
+		if len(marked) == 0 {
+			return "", nil
+		}
+		return fmt.Sprintf("Marked session(s) expired: %s (delete with 'clotilde prune --expired')", strings.Join(marked, ", ")), nil
+	}
+
+	var deleted []string
+	var failures []error
+	for _, sess := range candidates {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		if _, err := app.DeleteSession(clotildeRoot, store, sess); err != nil {
+			failures = append(failures, fmt.Errorf("failed to delete expired session '%s': %w", sess.Name, err))
+			continue
+		}
+		deleted = append(deleted, sess.Name)
+	}
+	var summary string
+	if len(deleted) > 0 {
+		summary = fmt.Sprintf("Deleted expired session(s): %s", strings.Join(deleted, ", "))
+	}
+	return summary, errors.Join(failures...)
+}
+
+// markExpired moves expired sessions that are kept to the expired status,
+// returning the names of those it moved. Running and archived sessions keep
+// theirs.
+func markExpired(store session.Store, sessions []*session.Session) []string {
+	var marked []string
+	for _, sess := range sessions {
+		if status := sess.Status(); status != session.StatusIdle && status != session.StatusBroken {
+			continue
+		}
+		sess.Metadata.Status = session.StatusExpired
+		if store.Update(sess) == nil {
+			marked = append(marked, sess.Name)
+		}
+	}
+	return marked
+}
+
+// skipsAutoPrune reports whether cmd must not trigger automatic pruning,
+// archiving or background maintenance: hooks run inside Claude Code,
+// completion runs on every tab press, maintain applies the policies itself,
+// and prune and version have nothing to gain from it.
+func skipsAutoPrune(cmd *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		switch c.Name() {
+		case "hook", "completion", "prune", "maintain", "version", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,156 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/claude"
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/session"
+)
+
+var _ = Describe("Prune Command", func() {
+	var (
+		tempDir      string
+		clotildeRoot string
+		originalWd   string
+		store        session.Store
+	)
+
+	BeforeEach(func() {
+		tempDir = GinkgoT().TempDir()
+		GinkgoT().Setenv("HOME", filepath.Join(tempDir, "home"))
+		GinkgoT().Setenv("XDG_CONFIG_HOME", filepath.Join(tempDir, "xdg"))
+
+		var err error
+		originalWd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Chdir(tempDir)).To(Succeed())
+
+		Expect(config.EnsureClotildeStructure(tempDir)).To(Succeed())
+		clotildeRoot = filepath.Join(tempDir, config.ClotildeDir)
+		store = session.NewFileStore(clotildeRoot)
+
+		expired := session.NewSession("old-spike", "uuid-expired")
+		expired.Metadata.ExpiresAt = time.Now().Add(-time.Hour)
+		Expect(store.Create(expired)).To(Succeed())
+
+		pending := session.NewSession("fresh-spike", "uuid-pending")
+		pending.Metadata.ExpiresAt = time.Now().Add(time.Hour)
+		Expect(store.Create(pending)).To(Succeed())
+
+		Expect(store.Create(session.NewSession("keeper", "uuid-keeper"))).To(Succeed())
+	})
+
+	AfterEach(func() {
+		_ = os.Chdir(originalWd)
+	})
+
+	run := runClotilde
+
+	It("requires --expired", func() {
+		_, err := run("prune")
+		Expect(err).To(MatchError(ContainSubstring("pass --expired")))
+	})
+
+	It("deletes only expired sessions", func() {
+		out, err := run("prune", "--expired", "--force")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(ContainSubstring("✓ old-spike"))
+		Expect(out).To(ContainSubstring("1 done"))
+
+		Expect(store.Exists("old-spike")).To(BeFalse())
+		Expect(store.Exists("fresh-spike")).To(BeTrue())
+		Expect(store.Exists("keeper")).To(BeTrue())
+	})
+
+	It("lists expired sessions without deleting them on --dry-run", func() {
+		out, err := run("prune", "--expired", "--dry-run")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(ContainSubstring("Would delete 1 expired session(s)"))
+		Expect(out).To(ContainSubstring("old-spike"))
+		Expect(store.Exists("old-spike")).To(BeTrue())
+	})
+
+	It("marks expired sessions after any command when autoPrune is off", func() {
+		_, err := run("list")
+		Expect(err).NotTo(HaveOccurred())
+		out, err := run("list")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(MatchRegexp(`old-spike.*expired`))
+		Expect(out).To(MatchRegexp(`fresh-spike.*idle`))
+
+		sess, err := store.Get("old-spike")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sess.Status()).To(Equal(session.StatusExpired))
+	})
+
+	It("deletes expired sessions after any command when autoPrune is enabled", func() {
+		Expect(os.WriteFile(config.GetConfigPath(clotildeRoot), []byte(`{"expiry": {"autoPrune": true}}`), 0o644)).To(Succeed())
+
+		_, err := run("list")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(store.Exists("old-spike")).To(BeFalse())
+		Expect(store.Exists("fresh-spike")).To(BeTrue())
+	})
+
+	It("leaves expired sessions claude is running in", func() {
+		running := session.NewSession("running-spike", "uuid-running")
+		running.Metadata.ExpiresAt = time.Now().Add(-time.Hour)
+		running.Metadata.Status = session.StatusActive
+		Expect(store.Create(running)).To(Succeed())
+		Expect(os.WriteFile(config.GetConfigPath(clotildeRoot), []byte(`{"expiry": {"autoPrune": true}}`), 0o644)).To(Succeed())
+
+		_, err := run("list")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(store.Exists("running-spike")).To(BeTrue())
+
+		out, err := run("prune", "--expired", "--dry-run")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).NotTo(ContainSubstring("running-spike"))
+	})
+
+	Describe("--empty", func() {
+		BeforeEach(func() {
+			// Only keeper has a conversation
+			claude.SessionUsedFunc = func(_ string, sess *session.Session) bool { return sess.Name == "keeper" }
+			notLaunched := session.NewSession("queued", "uuid-queued")
+			notLaunched.Metadata.PendingLaunch = true
+			Expect(store.Create(notLaunched)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			claude.SessionUsedFunc = func(_ string, _ *session.Session) bool { return true }
+		})
+
+		It("lists sessions without a conversation on --dry-run", func() {
+			out, err := run("prune", "--empty", "--dry-run")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(out).To(ContainSubstring("Would delete 3 empty session(s)"))
+			Expect(out).To(ContainSubstring("queued (never launched)"))
+			Expect(out).To(ContainSubstring("fresh-spike (empty)"))
+			Expect(out).NotTo(ContainSubstring("keeper"))
+		})
+
+		It("deletes them, together with expired ones when asked", func() {
+			out, err := run("prune", "--empty", "--expired", "--force")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(out).To(ContainSubstring("3 done"))
+			Expect(store.Exists("keeper")).To(BeTrue())
+			Expect(store.Exists("queued")).To(BeFalse())
+			Expect(store.Exists("old-spike")).To(BeFalse())
+		})
+
+		It("flags them in list", func() {
+			out, err := run("list")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(out).To(MatchRegexp(`queued.*not launched`))
+			Expect(out).To(MatchRegexp(`fresh-spike.*empty`))
+			Expect(out).NotTo(MatchRegexp(`keeper.*empty`))
+		})
+	})
+})
@@ -0,0 +1,67 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/testutil"
+)
+
+var _ = Describe("Q Command", func() {
+	var (
+		tempDir    string
+		originalWd string
+		claudeBin  string
+		argsFile   string
+	)
+
+	BeforeEach(func() {
+		tempDir = GinkgoT().TempDir()
+		GinkgoT().Setenv("XDG_CONFIG_HOME", filepath.Join(tempDir, "xdg"))
+
+		var err error
+		originalWd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Chdir(tempDir)).To(Succeed())
+		Expect(config.EnsureClotildeStructure(tempDir)).To(Succeed())
+
+		claudeBin, argsFile, err = testutil.CreateFakeClaude(tempDir)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		_ = os.Chdir(originalWd)
+	})
+
+	run := func(args ...string) error {
+		_, err := runClotilde(append([]string{"--claude-bin", claudeBin, "q"}, args...)...)
+		return err
+	}
+
+	It("launches a throwaway incognito session with the question as first message", func() {
+		Expect(run("where", "are", "retries", "configured", "--", "--debug")).To(Succeed())
+
+		args, err := testutil.ReadClaudeArgs(argsFile)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(args).To(ContainSubstring("--session-id"))
+		Expect(strings.TrimSpace(args)).To(HaveSuffix("--debug where are retries configured"))
+
+		sessions, err := session.NewFileStore(filepath.Join(tempDir, config.ClotildeDir)).List()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sessions).To(BeEmpty())
+	})
+
+	It("launches without a question", func() {
+		Expect(run()).To(Succeed())
+
+		args, err := testutil.ReadClaudeArgs(argsFile)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(args).To(MatchRegexp(`-n [a-z0-9-]+`))
+	})
+})
@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/errs"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/ui"
+	"github.com/fgrehm/clotilde/internal/util"
+)
+
+// remoteAnnotation marks commands that accept --remote.
+const remoteAnnotation = "clotilde.remote"
+
+// remoteTarget is set via the --remote flag, as <remote>:<project-path>
+var remoteTarget string
+
+// withRemote adds the annotation for commands that support --remote.
+func withRemote(annotations map[string]string) map[string]string {
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[remoteAnnotation] = "true"
+	return annotations
+}
+
+// checkRemote rejects --remote for commands that don't support it.
+func checkRemote(cmd *cobra.Command, _ []string) error {
+	if remoteTarget == "" {
+		return nil
+	}
+	if cmd.Annotations[remoteAnnotation] != "true" {
+		return fmt.Errorf("--remote is only supported by list and resume")
+	}
+	if projectRootOverride != "" {
+		return fmt.Errorf("--remote can't be combined with --root/-C")
+	}
+	return nil
+}
+
+// openRemote parses --remote and returns the remote, the project path on it,
+// and a store for its sessions.
+func openRemote() (config.Remote, string, session.Store, error) {
+	name, projectPath, ok := strings.Cut(remoteTarget, ":")
+	if !ok || name == "" || projectPath == "" || strings.HasPrefix(name, "-") {
+		return config.Remote{}, "", nil, fmt.Errorf("invalid --remote '%s' (expected <remote>:<project-path>)", remoteTarget)
+	}
+	remote, err := config.LookupRemote(name)
+	if err != nil {
+		return config.Remote{}, "", nil, err
+	}
+	return remote, projectPath, session.NewSSHStore(remote, projectPath), nil
+}
+
+// listRemote prints the sessions of the --remote project. Transcripts live on
+// the remote host, so models and health aren't shown.
+func listRemote(cmd *cobra.Command) error {
+	if teamFlag, _ := cmd.Flags().GetBool("team"); teamFlag {
+		return fmt.Errorf("--team can't be combined with --remote")
+	}
+	_, _, store, err := openRemote()
+	if err != nil {
+		return err
+	}
+	sessions, err := store.List()
+	if err != nil {
+		return err
+	}
+	if statuses, _ := cmd.Flags().GetStringSlice("status"); len(statuses) > 0 {
+		if sessions, err = filterByStatus(sessions, statuses); err != nil {
+			return err
+		}
+	}
+
+	out := cmd.OutOrStdout()
+	if isPorcelain(cmd) {
+		// Transcripts aren't read remotely, so the model is unknown
+		writePorcelainList(out, sessions, func(sess *session.Session) (string, time.Time) {
+			return "", sess.Metadata.LastAccessed
+		})
+		return nil
+	}
+	if len(sessions) == 0 {
+		_, _ = fmt.Fprintf(out, "No sessions found on %s.\n", remoteTarget)
+		return nil
+	}
+
+	_, _ = fmt.Fprintf(out, "Sessions on %s (%d total):\n", remoteTarget, len(sessions))
+	table := tablewriter.NewWriter(out)
+	table.Header("NAME", "TYPE", "STATUS", "LAST USED")
+	for _, sess := range sessions {
+		_ = table.Append(sess.Name, formatSessionType(sess), ui.RenderStatus(sess.Status()), util.FormatRelativeTime(sess.Metadata.LastAccessed))
+	}
+	_ = table.Render()
+	return nil
+}
+
+// resumeRemote resumes a session of the --remote project by running
+// 'clotilde resume' there over ssh -t, forwarding resume's flags and any
+// arguments for claude. The command runs under sh -c like the store's
+// scripts, so the remote login shell only has to understand single quotes
+// (fish and csh do too).
+func resumeRemote(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("session name required with --remote")
+	}
+	name := args[0]
+
+	remote, projectPath, store, err := openRemote()
+	if err != nil {
+		return err
+	}
+	sess, err := store.Get(name)
+	if err != nil {
+		if errs.ExitCode(err) == errs.ExitNotFound {
+			return errs.NotFound("session '%s' not found on %s", name, remoteTarget)
+		}
+		return err
+	}
+
+	resume := []string{"resume"}
+	local := cmd.LocalFlags()
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		if local.Lookup(f.Name) == nil {
+			return
+		}
+		// Slices print as "[a,b]"; send each element as its own flag
+		if slice, ok := f.Value.(pflag.SliceValue); ok {
+			for _, v := range slice.GetSlice() {
+				resume = append(resume, "--"+f.Name+"="+v)
+			}
+			return
+		}
+		resume = append(resume, "--"+f.Name+"="+f.Value.String())
+	})
+	resume = append(resume, name)
+	if dash := cmd.Flags().ArgsLenAtDash(); dash > 0 && len(args) > dash {
+		resume = append(resume, "--")
+		resume = append(resume, args[dash:]...)
+	}
+	script := "cd " + remoteShellPath(projectPath) + " && " + remoteShellPath(remote.Clotilde) + " " + util.ShellJoin(resume)
+
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Resuming session '%s' on %s (%s)\n\n", name, remote.Host, sess.Metadata.SessionID)
+
+	ssh := session.SSHCommand(remote, true, "sh -c "+util.ShellQuote(script))
+	ssh.Stdin = os.Stdin
+	ssh.Stdout = cmd.OutOrStdout()
+	ssh.Stderr = cmd.ErrOrStderr()
+	if err := ssh.Run(); err != nil {
+		return errs.ClaudeFailed("remote resume failed: %w", err)
+	}
+	return nil
+}
+
+// remoteShellPath quotes path for sh on the remote host, keeping a leading ~/
+// pointing at the remote home directory, which quoting alone would prevent.
+func remoteShellPath(path string) string {
+	if path == "~" {
+		return `"$HOME"`
+	}
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		return `"$HOME"/` + util.ShellQuote(rest)
+	}
+	return util.ShellQuote(path)
+}
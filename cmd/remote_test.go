@@ -0,0 +1,101 @@
+package cmd_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/errs"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/util"
+)
+
+var _ = Describe("--remote flag", func() {
+	var (
+		remoteHome string
+		sshLog     string
+	)
+
+	BeforeEach(func() {
+		tempDir := GinkgoT().TempDir()
+		GinkgoT().Setenv("XDG_CONFIG_HOME", filepath.Join(tempDir, "xdg"))
+
+		// The fake ssh logs its arguments and runs commands in a local "remote
+		// home"; interactive (-t) sessions are only logged
+		remoteHome = filepath.Join(tempDir, "remote-home")
+		sshLog = filepath.Join(tempDir, "ssh.log")
+		binDir := filepath.Join(tempDir, "bin")
+		Expect(os.MkdirAll(binDir, 0o755)).To(Succeed())
+		script := fmt.Sprintf(`#!/bin/sh
+printf '%%s\n' "$@" >> %s
+for last; do :; done
+case " $* " in *" -t "*) exit 0;; esac
+cd %s && exec sh -c "$last"
+`, util.ShellQuote(sshLog), util.ShellQuote(remoteHome))
+		Expect(os.WriteFile(filepath.Join(binDir, "ssh"), []byte(script), 0o755)).To(Succeed())
+		GinkgoT().Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+		clotildeRoot := filepath.Join(remoteHome, "src", "app", config.ClotildeDir)
+		Expect(os.MkdirAll(config.GetSessionsDir(clotildeRoot), 0o755)).To(Succeed())
+		store := session.NewFileStore(clotildeRoot)
+		Expect(store.Create(session.NewSession("on-devbox", "uuid-remote"))).To(Succeed())
+	})
+
+	run := runClotilde
+
+	sshArgs := func() string {
+		data, err := os.ReadFile(sshLog)
+		Expect(err).NotTo(HaveOccurred())
+		return string(data)
+	}
+
+	It("lists sessions on the remote host", func() {
+		out, err := run("--remote", "devbox:~/src/app", "list")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(ContainSubstring("Sessions on devbox:~/src/app (1 total)"))
+		Expect(out).To(ContainSubstring("on-devbox"))
+		Expect(sshArgs()).To(HavePrefix("devbox\n"))
+	})
+
+	It("uses configured remotes", func() {
+		Expect(util.WriteJSON(config.GlobalConfigPath(), map[string]any{
+			"remotes": map[string]any{
+				"dev": map[string]any{"host": "me@devbox", "sshArgs": []string{"-p", "2222"}, "clotilde": "bin/clotilde"},
+			},
+		})).To(Succeed())
+
+		_, err := run("--remote", "dev:src/app", "resume", "on-devbox", "--model", "opus", "--", "--debug")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sshArgs()).To(HaveSuffix("-p\n2222\n-t\nme@devbox\nsh -c 'cd src/app && bin/clotilde resume '\\''--model=opus'\\'' on-devbox -- --debug'\n"))
+	})
+
+	It("sends each element of a list flag on its own and keeps ~ paths in the remote home", func() {
+		Expect(util.WriteJSON(config.GlobalConfigPath(), map[string]any{
+			"remotes": map[string]any{"dev": map[string]any{"host": "devbox", "clotilde": "~/bin/clotilde"}},
+		})).To(Succeed())
+
+		_, err := run("--remote", "dev:~/src/app", "resume", "on-devbox", "--snippet", "review,style notes")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sshArgs()).To(HaveSuffix(`sh -c 'cd "$HOME"/src/app && "$HOME"/bin/clotilde resume '\''--snippet=review'\'' '\''--snippet=style notes'\'' on-devbox'` + "\n"))
+	})
+
+	It("fails for sessions missing on the remote host", func() {
+		_, err := run("--remote", "devbox:src/app", "resume", "missing")
+		Expect(err).To(MatchError(ContainSubstring("session 'missing' not found on devbox:src/app")))
+		Expect(errs.ExitCode(err)).To(Equal(errs.ExitNotFound))
+	})
+
+	It("rejects targets without a project path", func() {
+		_, err := run("--remote", "devbox", "list")
+		Expect(err).To(MatchError(ContainSubstring("expected <remote>:<project-path>")))
+	})
+
+	It("is rejected by other commands", func() {
+		_, err := run("--remote", "devbox:src/app", "delete", "on-devbox")
+		Expect(err).To(MatchError(ContainSubstring("--remote is only supported by list and resume")))
+	})
+})
@@ -0,0 +1,262 @@
+package cmd
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fgrehm/clotilde/internal/claude"
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/events"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/util"
+)
+
+// reportFormats are the formats 'clotilde report' can write.
+var reportFormats = []string{"md", "text"}
+
+// reportDateFormat is how dates are shown in reports.
+const reportDateFormat = "Jan 2, 2006"
+
+// report summarizes a project's sessions over a period.
+type report struct {
+	Project  string
+	Since    time.Time
+	Until    time.Time
+	Sessions []reportSession // Busiest first
+	Total    claude.TranscriptStats
+	Forks    []reportFork // Oldest first
+}
+
+// reportSession is a session's activity over the report's period.
+type reportSession struct {
+	Name  string
+	Stats claude.TranscriptStats
+}
+
+// reportFork is a fork created during the report's period.
+type reportFork struct {
+	Name    string
+	Parent  string
+	Created time.Time
+	Deleted bool
+}
+
+func newReportCmd() *cobra.Command {
+	var since, format string
+
+	cmd := &cobra.Command{
+		Use:   "report [--since 7d] [--format md|text]",
+		Short: "Summarize recent work for a status update",
+		Long: `Summarize the project's sessions over a period, by default the last 7 days:
+the sessions worked on with their active time, turns and models, the models
+used overall, and the forks created. The Markdown output is meant to be
+pasted into a weekly status update.
+
+Activity is read from the sessions' transcripts (including those from before
+a /clear), like 'clotilde stats'; forks come from the event log and the
+sessions' metadata, so forks deleted since are listed too. Clotilde doesn't
+track merging a fork's work back, so the report only counts forks created.`,
+		Example: `  clotilde report
+  clotilde report --since 2w --format text
+  clotilde report | pbcopy`,
+		Annotations: readOnly(),
+		Args:        cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			period, err := util.ParseDuration(since)
+			if err != nil {
+				return fmt.Errorf("invalid --since: %w", err)
+			}
+			if !slices.Contains(reportFormats, format) {
+				return fmt.Errorf("invalid --format %q (expected %s)", format, strings.Join(reportFormats, " or "))
+			}
+
+			clotildeRoot, err := findClotildeRoot()
+			if err != nil {
+				if projectRootOverride != "" {
+					return err
+				}
+				return errNoSessions()
+			}
+
+			homeDir, err := util.HomeDir()
+			if err != nil {
+				return fmt.Errorf("failed to determine home directory: %w", err)
+			}
+
+			now := time.Now()
+			r, err := buildReport(clotildeRoot, homeDir, now.Add(-period), now)
+			if err != nil {
+				return err
+			}
+
+			if format == "text" {
+				writeTextReport(cmd.OutOrStdout(), r)
+				return nil
+			}
+			writeMarkdownReport(cmd.OutOrStdout(), r)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "7d", "How far back to report (e.g. 7d, 2w, 36h)")
+	cmd.Flags().StringVar(&format, "format", "md", "Output format: md or text")
+	_ = cmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions(reportFormats, cobra.ShellCompDirectiveNoFileComp))
+	return cmd
+}
+
+// buildReport reads the activity of the project's sessions between since
+// and until. Sessions without activity in the period are left out.
+func buildReport(clotildeRoot, homeDir string, since, until time.Time) (report, error) {
+	r := report{Project: filepath.Base(config.ProjectRootOf(clotildeRoot)), Since: since, Until: until}
+
+	store := session.NewFileStore(clotildeRoot)
+	sessions, err := store.List()
+	if err != nil {
+		return r, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	forks := make(map[string]*reportFork)
+	for _, sess := range sessions {
+		if sess.Metadata.IsForkedSession && !sess.Metadata.Created.Before(since) {
+			forks[sess.Name] = &reportFork{Name: sess.Name, Parent: sess.Metadata.ParentSession, Created: sess.Metadata.Created}
+		}
+
+		var total claude.TranscriptStats
+		for _, path := range claude.SessionTranscriptPaths(homeDir, clotildeRoot, sess) {
+			stats, err := claude.ReadTranscriptStats(path, since)
+			if err != nil {
+				return r, fmt.Errorf("failed to read transcripts of '%s': %w", sess.Name, err)
+			}
+			total.Add(stats)
+		}
+		if total.Messages > 0 {
+			r.Sessions = append(r.Sessions, reportSession{Name: sess.Name, Stats: total})
+			r.Total.Add(total)
+		}
+	}
+	slices.SortStableFunc(r.Sessions, func(a, b reportSession) int {
+		if c := cmp.Compare(b.Stats.ActiveTime, a.Stats.ActiveTime); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.Stats.Messages, a.Stats.Messages)
+	})
+
+	// The event log also remembers forks deleted since
+	recorded, err := events.Read(clotildeRoot)
+	if err != nil {
+		return r, fmt.Errorf("failed to read event log: %w", err)
+	}
+	for _, e := range recorded {
+		if e.Time.Before(since) {
+			continue
+		}
+		switch e.Type {
+		case events.SessionForked:
+			if forks[e.Session] == nil {
+				forks[e.Session] = &reportFork{Name: e.Session, Parent: e.Parent, Created: e.Time, Deleted: !slices.ContainsFunc(sessions, func(s *session.Session) bool { return s.Name == e.Session })}
+			}
+		case events.SessionDeleted:
+			if fork := forks[e.Session]; fork != nil && e.Time.After(fork.Created) {
+				fork.Deleted = true
+			}
+		}
+	}
+	for _, fork := range forks {
+		r.Forks = append(r.Forks, *fork)
+	}
+	slices.SortFunc(r.Forks, func(a, b reportFork) int { return a.Created.Compare(b.Created) })
+
+	return r, nil
+}
+
+// reportModels describes the model families of stats, most used first, with
+// their share of turns, e.g. "sonnet 80%, opus 20%".
+func reportModels(stats claude.TranscriptStats) string {
+	var parts []string
+	for _, model := range modelsByTurns(stats) {
+		parts = append(parts, fmt.Sprintf("%s %d%%", model, stats.Models[model]*100/stats.Messages))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// reportPeriod describes the report's period, e.g. "Oct 11, 2026 – Oct 18, 2026".
+func reportPeriod(r report) string {
+	return r.Since.Format(reportDateFormat) + " – " + r.Until.Format(reportDateFormat)
+}
+
+// writeMarkdownReport writes r as a Markdown document.
+func writeMarkdownReport(out io.Writer, r report) {
+	_, _ = fmt.Fprintf(out, "# %s: %s\n\n", r.Project, reportPeriod(r))
+	_, _ = fmt.Fprintf(out, "- Sessions worked on: %d\n", len(r.Sessions))
+	_, _ = fmt.Fprintf(out, "- Active time: %s\n", util.FormatDuration(r.Total.ActiveTime))
+	_, _ = fmt.Fprintf(out, "- Assistant turns: %d\n", r.Total.Messages)
+	_, _ = fmt.Fprintf(out, "- Forks created: %d\n", len(r.Forks))
+
+	if len(r.Sessions) > 0 {
+		_, _ = fmt.Fprint(out, "\n## Sessions\n\n| Session | Active time | Turns | Models |\n| --- | --- | ---: | --- |\n")
+		for _, s := range r.Sessions {
+			_, _ = fmt.Fprintf(out, "| %s | %s | %d | %s |\n", markdownCell(s.Name), util.FormatDuration(s.Stats.ActiveTime), s.Stats.Messages, reportModels(s.Stats))
+		}
+
+		_, _ = fmt.Fprint(out, "\n## Models\n\n| Model | Turns | Share |\n| --- | ---: | ---: |\n")
+		for _, model := range modelsByTurns(r.Total) {
+			n := r.Total.Models[model]
+			_, _ = fmt.Fprintf(out, "| %s | %d | %d%% |\n", markdownCell(model), n, n*100/r.Total.Messages)
+		}
+	}
+
+	if len(r.Forks) > 0 {
+		_, _ = fmt.Fprint(out, "\n## Forks\n\n")
+		for _, f := range r.Forks {
+			_, _ = fmt.Fprintf(out, "- `%s` from `%s` (%s%s)\n", f.Name, f.Parent, f.Created.Format(reportDateFormat), forkDeletedNote(f))
+		}
+	}
+}
+
+// writeTextReport writes r as plain, aligned text.
+func writeTextReport(out io.Writer, r report) {
+	_, _ = fmt.Fprintf(out, "%s: %s\n\n", r.Project, reportPeriod(r))
+	_, _ = fmt.Fprintf(out, "Sessions worked on: %d\n", len(r.Sessions))
+	_, _ = fmt.Fprintf(out, "Active time: %s\n", util.FormatDuration(r.Total.ActiveTime))
+	_, _ = fmt.Fprintf(out, "Assistant turns: %d\n", r.Total.Messages)
+	_, _ = fmt.Fprintf(out, "Forks created: %d\n", len(r.Forks))
+
+	if len(r.Sessions) > 0 {
+		width := 0
+		for _, s := range r.Sessions {
+			width = max(width, len(s.Name))
+		}
+		_, _ = fmt.Fprintln(out, "\nSessions:")
+		for _, s := range r.Sessions {
+			_, _ = fmt.Fprintf(out, "  %-*s  %8s  %4d turn(s)  %s\n", width, s.Name, util.FormatDuration(s.Stats.ActiveTime), s.Stats.Messages, reportModels(s.Stats))
+		}
+		_, _ = fmt.Fprintf(out, "\nModels: %s\n", reportModels(r.Total))
+	}
+
+	if len(r.Forks) > 0 {
+		_, _ = fmt.Fprintln(out, "\nForks:")
+		for _, f := range r.Forks {
+			_, _ = fmt.Fprintf(out, "  %s from %s (%s%s)\n", f.Name, f.Parent, f.Created.Format(reportDateFormat), forkDeletedNote(f))
+		}
+	}
+}
+
+// forkDeletedNote marks forks that no longer exist.
+func forkDeletedNote(f reportFork) string {
+	if f.Deleted {
+		return ", deleted since"
+	}
+	return ""
+}
+
+// markdownCell escapes the pipes that would split a table cell.
+func markdownCell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
@@ -0,0 +1,113 @@
+package cmd_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/session"
+)
+
+var _ = Describe("Report Command", func() {
+	var (
+		tempDir    string
+		originalWd string
+		store      session.Store
+	)
+
+	BeforeEach(func() {
+		tempDir = GinkgoT().TempDir()
+		GinkgoT().Setenv("HOME", filepath.Join(tempDir, "home"))
+		GinkgoT().Setenv("XDG_CONFIG_HOME", filepath.Join(tempDir, "xdg"))
+		GinkgoT().Setenv("CLAUDE_CONFIG_DIR", "")
+
+		var err error
+		originalWd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Chdir(tempDir)).To(Succeed())
+
+		Expect(config.EnsureClotildeStructure(tempDir)).To(Succeed())
+		store = session.NewFileStore(filepath.Join(tempDir, config.ClotildeDir))
+	})
+
+	AfterEach(func() {
+		_ = os.Chdir(originalWd)
+	})
+
+	run := func(args ...string) (string, error) {
+		return runClotilde(append([]string{"report"}, args...)...)
+	}
+
+	// createSession creates a session whose transcript has one assistant turn
+	// a minute, ending ago, from each of models in turn.
+	createSession := func(sess *session.Session, ago time.Duration, models ...string) {
+		start := time.Now().Add(-ago).Add(-time.Duration(len(models)) * time.Minute)
+		var lines []string
+		for i, model := range models {
+			ts := start.Add(time.Duration(i+1) * time.Minute).UTC().Format(time.RFC3339)
+			lines = append(lines, fmt.Sprintf(`{"type":"assistant","timestamp":%q,"message":{"model":%q}}`, ts, model))
+		}
+		sess.Metadata.TranscriptPath = filepath.Join(tempDir, sess.Name+".jsonl")
+		Expect(os.WriteFile(sess.Metadata.TranscriptPath, []byte(strings.Join(lines, "\n")+"\n"), 0o644)).To(Succeed())
+		Expect(store.Create(sess)).To(Succeed())
+	}
+
+	It("summarizes the sessions worked on and the models used in Markdown", func() {
+		createSession(session.NewSession("auth", "uuid-auth"), time.Hour,
+			"claude-sonnet-4-5-20250929", "claude-sonnet-4-5-20250929", "claude-sonnet-4-5-20250929", "claude-opus-4-1-20250805")
+		createSession(session.NewSession("docs", "uuid-docs"), 2*time.Hour, "claude-sonnet-4-5-20250929")
+		createSession(session.NewSession("old", "uuid-old"), 30*24*time.Hour, "claude-sonnet-4-5-20250929")
+
+		output, err := run()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(HavePrefix("# " + filepath.Base(tempDir) + ": "))
+		Expect(output).To(ContainSubstring("- Sessions worked on: 2\n"))
+		Expect(output).To(ContainSubstring("- Assistant turns: 5\n"))
+		Expect(output).To(ContainSubstring("| auth | 3m | 4 | sonnet 75%, opus 25% |\n| docs | <1m | 1 | sonnet 100% |\n"))
+		Expect(output).To(ContainSubstring("| sonnet | 4 | 80% |"))
+		Expect(output).NotTo(ContainSubstring("old"))
+	})
+
+	It("lists the forks created, including deleted ones", func() {
+		createSession(session.NewSession("auth", "uuid-auth"), time.Hour, "claude-sonnet-4-5-20250929")
+		for _, name := range []string{"auth-try", "auth-scrap"} {
+			fork := session.NewSession(name, "uuid-"+name)
+			fork.Metadata.IsForkedSession = true
+			fork.Metadata.ParentSession = "auth"
+			Expect(store.Create(fork)).To(Succeed())
+		}
+		Expect(store.Delete("auth-scrap")).To(Succeed())
+
+		output, err := run("--format", "text")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(ContainSubstring("Forks created: 2\n"))
+		Expect(output).To(MatchRegexp(`auth-try from auth \([^)]+\)\n`))
+		Expect(output).To(MatchRegexp(`auth-scrap from auth \([^)]+, deleted since\)\n`))
+	})
+
+	It("limits the report to --since", func() {
+		createSession(session.NewSession("auth", "uuid-auth"), 3*24*time.Hour, "claude-sonnet-4-5-20250929")
+
+		output, err := run("--since", "2d")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(ContainSubstring("- Sessions worked on: 0\n"))
+
+		output, err = run("--since", "1w")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(ContainSubstring("- Sessions worked on: 1\n"))
+	})
+
+	It("rejects unknown formats and periods", func() {
+		_, err := run("--format", "html")
+		Expect(err).To(MatchError(ContainSubstring("invalid --format")))
+
+		_, err = run("--since", "soon")
+		Expect(err).To(MatchError(ContainSubstring("invalid --since")))
+	})
+})
@@ -2,16 +2,18 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
-	"path/filepath"
 
 	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 
+	"github.com/fgrehm/clotilde/internal/app"
 	"github.com/fgrehm/clotilde/internal/claude"
 	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/errs"
 	"github.com/fgrehm/clotilde/internal/session"
-	"github.com/fgrehm/clotilde/internal/ui"
+	"github.com/fgrehm/clotilde/internal/snippet"
 	"github.com/fgrehm/clotilde/internal/util"
 )
 
@@ -26,14 +28,25 @@ If no session name is provided, an interactive picker will be shown
 (in TTY environments).
 
 Pass additional flags to Claude Code after '--':
-  clotilde resume my-session -- --debug api,hooks`,
+  clotilde resume my-session -- --debug api,hooks
+
+Add stored snippets to the context injected into this launch only:
+  clotilde resume my-session --snippet deploy-checklist
+
+With --remote, the session is resumed on another host over ssh:
+  clotilde --remote dev:src/myproject resume my-session`,
 		Args:              maxPositionalArgs(1),
-		ValidArgsFunction: sessionNameCompletion,
+		ValidArgsFunction: resumeCompletion,
+		Annotations:       withRemote(nil),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if remoteTarget != "" {
+				return resumeRemote(cmd, args)
+			}
+
 			// Find clotilde root
 			clotildeRoot, err := config.FindClotildeRoot()
 			if err != nil {
-				return fmt.Errorf("no sessions found (create one with 'clotilde start <name>')")
+				return errNoSessions()
 			}
 
 			// Create store
@@ -55,15 +68,14 @@ Pass additional flags to Claude Code after '--':
 				}
 
 				if len(sessions) == 0 {
-					return fmt.Errorf("no sessions available")
+					return errs.NotFound("no sessions available")
 				}
 
-				// Sort by last accessed (most recent first)
-				sortSessionsByLastAccessed(sessions)
+				// Starred first, then by last accessed (most recent first)
+				session.SortStarredFirst(sessions)
 
 				// Show picker with preview pane
-				picker := ui.NewPicker(sessions, "Select session to resume").WithPreview()
-				selected, err := ui.RunPicker(picker)
+				selected, err := pickSession(clotildeRoot, store, sessions, "Select session to resume")
 				if err != nil {
 					return fmt.Errorf("picker failed: %w", err)
 				}
@@ -79,75 +91,107 @@ Pass additional flags to Claude Code after '--':
 				name = args[0]
 			}
 
-			// Extract additional args after '--'
-			var additionalArgs []string
-			argsLenAtDash := cmd.Flags().ArgsLenAtDash()
-			if argsLenAtDash > 0 && len(args) > argsLenAtDash {
-				additionalArgs = args[argsLenAtDash:]
-			}
-
-			// Resolve shorthand flags (resume doesn't create sessions, pass to claude CLI)
-			permMode, err := resolvePermissionMode(cmd)
+			// Claude flags from --with presets and after '--'
+			additionalArgs, err := claudeArgs(cmd, args)
 			if err != nil {
 				return err
 			}
-			if permMode != "" {
-				additionalArgs = append(additionalArgs, "--permission-mode", permMode)
-			}
 
-			fastEnabled, err := resolveFastMode(cmd)
+			// Resolve flags (resume doesn't create sessions, pass to claude CLI)
+			flags, err := flagSettingsLayer(cmd)
 			if err != nil {
 				return err
 			}
-			if fastEnabled {
-				additionalArgs = append(additionalArgs, "--model", "haiku", "--effort", "low")
-			} else {
-				if model, _ := cmd.Flags().GetString("model"); model != "" {
-					additionalArgs = append(additionalArgs, "--model", normalizeModel(model))
-				}
-				additionalArgs = collectEffortFlag(cmd, additionalArgs)
-			}
 
 			// Load session
 			sess, err := store.Get(name)
 			if err != nil {
-				return fmt.Errorf("session '%s' not found", name)
+				return errs.NotFound("session '%s' not found", name)
 			}
 
-			// Update context if --context flag provided
-			contextFlag, _ := cmd.Flags().GetString("context")
-			if contextFlag != "" {
-				sess.Metadata.Context = contextFlag
+			snippetNames, _ := cmd.Flags().GetStringSlice("snippet")
+			snippets, err := snippet.Resolve(clotildeRoot, snippetNames)
+			if err != nil {
+				return err
 			}
 
-			// Update lastAccessed timestamp
-			sess.UpdateLastAccessed()
-			if err := store.Update(sess); err != nil {
-				return fmt.Errorf("failed to update session: %w", err)
+			// Resolve effective settings: flag > session settings > project default
+			resolved, pinned, err := resolveSessionSettings(clotildeRoot, store, name, flags)
+			if err != nil {
+				return err
 			}
+			additionalArgs = append(additionalArgs, resolved.launchArgs()...)
 
-			sessionDir := config.GetSessionDir(clotildeRoot, name)
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Resuming session '%s' (%s)\n\n", name, sess.Metadata.SessionID)
+			if explain, _ := cmd.Flags().GetBool("explain"); explain {
+				printSettingsExplanation(cmd.OutOrStdout(), resolved)
+			}
+			printSessionOverrides(cmd.OutOrStdout(), resolved, pinned)
 
-			// Check for settings file
-			var settingsFile string
-			settingsPath := filepath.Join(sessionDir, "settings.json")
-			if util.FileExists(settingsPath) {
-				settingsFile = settingsPath
+			recap, err := wantsRecap(cmd, clotildeRoot)
+			if err != nil {
+				return err
+			}
+			if recap {
+				printRecap(cmd.OutOrStdout(), sess)
 			}
 
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Resuming session '%s' (%s)\n\n", name, sess.Metadata.SessionID)
+			warnMissingHooks(cmd, clotildeRoot)
+			warnActiveSessions(cmd, clotildeRoot, name)
 
-			// Invoke claude
-			return claude.Resume(clotildeRoot, sess, settingsFile, additionalArgs)
+			// Record the access and invoke claude
+			contextFlag, _ := cmd.Flags().GetString("context")
+			return app.ResumeSession(clotildeRoot, store, sess, app.ResumeOptions{Context: contextFlag, Args: additionalArgs, Snippets: snippets})
 		},
 	}
 	cmd.Flags().String("model", "", "Claude model to use (haiku, sonnet, opus); opus defaults to 1M context")
 	cmd.Flags().String("context", "", "Session context (e.g. \"working on ticket GH-123\")")
+	cmd.Flags().StringSlice("snippet", nil, "Add these snippets to the context injected into this launch (see 'clotilde snippets')")
+	cmd.Flags().Bool("recap", false, "Print the last prompt and reply before resuming (default from resume.recap config)")
 	registerShorthandFlags(cmd)
+	registerWithFlag(cmd)
+	registerExplainFlag(cmd)
+	registerTitleFlag(cmd)
+	registerHookCheckFlag(cmd)
 	_ = cmd.RegisterFlagCompletionFunc("model", modelCompletion)
+	_ = cmd.RegisterFlagCompletionFunc("snippet", snippetNameCompletion)
 	return cmd
 }
 
+// wantsRecap reports whether to print a recap: --recap when given, otherwise
+// the resume.recap config.
+func wantsRecap(cmd *cobra.Command, clotildeRoot string) (bool, error) {
+	if cmd.Flags().Changed("recap") {
+		return cmd.Flags().GetBool("recap")
+	}
+	return config.ResumeRecap(clotildeRoot)
+}
+
+// printRecap prints where the session's conversation left off. Nothing is
+// printed when the transcript can't be read or has no prompt yet.
+func printRecap(out io.Writer, sess *session.Session) {
+	if sess.Metadata.TranscriptPath == "" {
+		return
+	}
+	recap, err := claude.ReadRecap(sess.Metadata.TranscriptPath)
+	if err != nil || recap.Prompt == "" {
+		return
+	}
+
+	if recap.At.IsZero() {
+		_, _ = fmt.Fprintln(out, "Last time:")
+	} else {
+		_, _ = fmt.Fprintf(out, "Last time (%s):\n", util.FormatRelativeTime(recap.At))
+	}
+	_, _ = fmt.Fprintf(out, "  You: %s\n", recap.Prompt)
+	if recap.Reply != "" {
+		_, _ = fmt.Fprintf(out, "  Claude: %s\n", recap.Reply)
+	} else {
+		_, _ = fmt.Fprintln(out, "  (no reply)")
+	}
+	_, _ = fmt.Fprintln(out)
+}
+
 // sortSessionsByLastAccessed sorts sessions by last accessed time (most recent first)
 func sortSessionsByLastAccessed(sessions []*session.Session) {
 	// Simple bubble sort - good enough for typical session counts
@@ -1,6 +1,7 @@
 package cmd_test
 
 import (
+	"bytes"
 	"io"
 	"os"
 	"path/filepath"
@@ -9,8 +10,10 @@ import (
 	. "github.com/onsi/gomega"
 
 	"github.com/fgrehm/clotilde/cmd"
+	"github.com/fgrehm/clotilde/internal/claude"
 	"github.com/fgrehm/clotilde/internal/config"
 	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/snippet"
 	"github.com/fgrehm/clotilde/internal/testutil"
 )
 
@@ -44,6 +47,8 @@ var _ = Describe("Resume Command", func() {
 
 		_, claudeArgsFile, err = testutil.CreateFakeClaude(fakeClaudeDir)
 		Expect(err).NotTo(HaveOccurred())
+		// The fake claude writes no transcript; resuming an empty session would remove it
+		claude.SessionUsedFunc = func(_ string, _ *session.Session) bool { return true }
 
 		Expect(err).NotTo(HaveOccurred())
 
@@ -151,4 +156,268 @@ var _ = Describe("Resume Command", func() {
 		Expect(err).To(HaveOccurred())
 		Expect(err.Error()).To(ContainSubstring("not found"))
 	})
+
+	Describe("--with", func() {
+		BeforeEach(func() {
+			GinkgoT().Setenv("XDG_CONFIG_HOME", filepath.Join(tempDir, "xdg"))
+			Expect(store.Create(session.NewSession("preset", "uuid-preset"))).To(Succeed())
+			Expect(os.WriteFile(config.GetConfigPath(clotildeRoot), []byte(`{"argPresets": {"debug": ["--debug", "api,hooks"], "quiet": ["--verbose=false"]}}`), 0o644)).To(Succeed())
+		})
+
+		resume := func(args ...string) error {
+			rootCmd := cmd.NewRootCmd()
+			rootCmd.SetOut(io.Discard)
+			rootCmd.SetErr(io.Discard)
+			rootCmd.SetArgs(append([]string{"--claude-bin", filepath.Join(fakeClaudeDir, "claude"), "resume", "preset"}, args...))
+			return rootCmd.Execute()
+		}
+
+		It("expands presets ahead of the flags after '--'", func() {
+			Expect(resume("--with", "debug,quiet", "--", "--mcp-debug")).To(Succeed())
+
+			args, err := testutil.ReadClaudeArgs(claudeArgsFile)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(args).To(ContainSubstring("--debug api,hooks --verbose=false --mcp-debug"))
+		})
+
+		It("rejects unknown presets", func() {
+			err := resume("--with", "nope")
+			Expect(err).To(MatchError(ContainSubstring("arg preset 'nope' not found")))
+		})
+	})
+
+	Describe("empty sessions", func() {
+		BeforeEach(func() {
+			GinkgoT().Setenv("XDG_CONFIG_HOME", filepath.Join(tempDir, "xdg"))
+			claude.SessionUsedFunc = func(_ string, _ *session.Session) bool { return false }
+			Expect(store.Create(session.NewSession("abandoned", "uuid-abandoned"))).To(Succeed())
+		})
+
+		resume := func() {
+			rootCmd := cmd.NewRootCmd()
+			rootCmd.SetOut(io.Discard)
+			rootCmd.SetErr(io.Discard)
+			rootCmd.SetArgs([]string{"--claude-bin", filepath.Join(fakeClaudeDir, "claude"), "resume", "abandoned"})
+			Expect(rootCmd.Execute()).To(Succeed())
+		}
+
+		It("removes sessions still without a conversation when claude exits", func() {
+			resume()
+			Expect(store.Exists("abandoned")).To(BeFalse())
+		})
+
+		It("keeps them when emptySessions.autoRemove is off", func() {
+			Expect(os.WriteFile(config.GetConfigPath(clotildeRoot), []byte(`{"emptySessions": {"autoRemove": false}}`), 0o644)).To(Succeed())
+			resume()
+			Expect(store.Exists("abandoned")).To(BeTrue())
+		})
+	})
+
+	Describe("recap", func() {
+		BeforeEach(func() {
+			sess := session.NewSession("recapped", "uuid-recapped")
+			sess.Metadata.TranscriptPath = filepath.Join(tempDir, "recapped.jsonl")
+			Expect(store.Create(sess)).To(Succeed())
+			transcript := `{"type":"user","timestamp":"2026-01-05T10:00:00Z","message":{"role":"user","content":"fix the flaky login test"}}
+{"type":"assistant","timestamp":"2026-01-05T10:01:00Z","message":{"model":"claude-sonnet-4-5-20250929","content":[{"type":"text","text":"Fixed the race in the login test."}]}}
+`
+			Expect(os.WriteFile(sess.Metadata.TranscriptPath, []byte(transcript), 0o644)).To(Succeed())
+		})
+
+		resume := func(extraArgs ...string) string {
+			var out bytes.Buffer
+			rootCmd := cmd.NewRootCmd()
+			rootCmd.SetOut(&out)
+			rootCmd.SetErr(io.Discard)
+			rootCmd.SetArgs(append([]string{"--claude-bin", filepath.Join(fakeClaudeDir, "claude"), "resume", "recapped"}, extraArgs...))
+			Expect(rootCmd.Execute()).To(Succeed())
+			return out.String()
+		}
+
+		It("isn't printed by default", func() {
+			Expect(resume()).NotTo(ContainSubstring("Last time"))
+		})
+
+		It("prints the last prompt and reply with --recap", func() {
+			output := resume("--recap")
+			Expect(output).To(ContainSubstring("Last time"))
+			Expect(output).To(ContainSubstring("You: fix the flaky login test"))
+			Expect(output).To(ContainSubstring("Claude: Fixed the race in the login test."))
+		})
+
+		It("follows the resume.recap config unless --recap is given", func() {
+			Expect(os.WriteFile(config.GetConfigPath(clotildeRoot), []byte(`{"resume": {"recap": true}}`), 0o644)).To(Succeed())
+
+			Expect(resume()).To(ContainSubstring("You: fix the flaky login test"))
+			Expect(resume("--recap=false")).NotTo(ContainSubstring("Last time"))
+		})
+	})
+
+	Describe("prepare and teardown scripts", func() {
+		var runLog string
+
+		BeforeEach(func() {
+			GinkgoT().Setenv("XDG_CONFIG_HOME", filepath.Join(tempDir, "xdg"))
+			runLog = filepath.Join(tempDir, "run.log")
+			Expect(store.Create(session.NewSession("scripted", "scripted-uuid"))).To(Succeed())
+		})
+
+		writeScript := func(kind, body string, mode os.FileMode) {
+			path := filepath.Join(config.GetSessionDir(clotildeRoot, "scripted"), kind)
+			Expect(os.WriteFile(path, []byte(body), mode)).To(Succeed())
+		}
+
+		resume := func() error {
+			rootCmd := cmd.NewRootCmd()
+			rootCmd.SetOut(io.Discard)
+			rootCmd.SetErr(io.Discard)
+			rootCmd.SetArgs([]string{"--claude-bin", filepath.Join(fakeClaudeDir, "claude"), "resume", "scripted"})
+			return rootCmd.Execute()
+		}
+
+		It("runs the session's scripts around claude, in the project root", func() {
+			writeScript(claude.PrepareScript, "#!/bin/sh\necho \"prepare $CLOTILDE_SESSION_NAME $(pwd)\" >> "+runLog+"\n", 0o755)
+			writeScript(claude.TeardownScript, "test -f "+claudeArgsFile+" && echo teardown >> "+runLog+"\n", 0o644)
+
+			Expect(resume()).To(Succeed())
+			projectRoot, err := filepath.EvalSymlinks(tempDir)
+			Expect(err).NotTo(HaveOccurred())
+			content, err := os.ReadFile(runLog)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal("prepare scripted " + projectRoot + "\nteardown\n"))
+		})
+
+		It("doesn't launch claude when the prepare script fails", func() {
+			writeScript(claude.PrepareScript, "echo 'compose: no such service'\nexit 3\n", 0o644)
+			writeScript(claude.TeardownScript, "echo teardown >> "+runLog+"\n", 0o644)
+
+			err := resume()
+			Expect(err).To(MatchError(ContainSubstring("prepare script failed")))
+			Expect(err).To(MatchError(ContainSubstring("compose: no such service")))
+			Expect(claudeArgsFile).NotTo(BeAnExistingFile())
+			Expect(runLog).NotTo(BeAnExistingFile())
+		})
+
+		It("runs the teardown of an incognito session before deleting it", func() {
+			sess, err := store.Get("scripted")
+			Expect(err).NotTo(HaveOccurred())
+			sess.Metadata.IsIncognito = true
+			Expect(store.Update(sess)).To(Succeed())
+			Expect(store.SaveEnv("scripted", map[string]string{"STACK": "dev"})).To(Succeed())
+			writeScript(claude.TeardownScript, "echo \"teardown $STACK\" >> "+runLog+"\n", 0o644)
+
+			Expect(resume()).To(Succeed())
+			content, err := os.ReadFile(runLog)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal("teardown dev\n"))
+			Expect(store.Exists("scripted")).To(BeFalse())
+		})
+
+		It("falls back to the resume.prepare and resume.teardown config", func() {
+			Expect(os.WriteFile(config.GetConfigPath(clotildeRoot), []byte(`{"resume": {"prepare": "echo configured >> `+runLog+`", "teardown": "echo down >> `+runLog+`"}}`), 0o644)).To(Succeed())
+			writeScript(claude.TeardownScript, "echo own >> "+runLog+"\n", 0o644)
+
+			Expect(resume()).To(Succeed())
+			content, err := os.ReadFile(runLog)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal("configured\nown\n"))
+		})
+	})
+
+	Describe("hook check", func() {
+		BeforeEach(func() {
+			GinkgoT().Setenv(claude.ConfigDirEnv, filepath.Join(tempDir, "claude-config"))
+			Expect(store.Create(session.NewSession("hookless", "hookless-uuid"))).To(Succeed())
+		})
+
+		resume := func(extra ...string) string {
+			var stderr bytes.Buffer
+			rootCmd := cmd.NewRootCmd()
+			rootCmd.SetOut(io.Discard)
+			rootCmd.SetErr(&stderr)
+			rootCmd.SetArgs(append([]string{"--claude-bin", filepath.Join(fakeClaudeDir, "claude"), "resume", "hookless"}, extra...))
+			Expect(rootCmd.Execute()).To(Succeed())
+			return stderr.String()
+		}
+
+		It("warns when the SessionStart hook isn't installed", func() {
+			Expect(resume()).To(ContainSubstring("SessionStart hook is not installed"))
+			Expect(claudeArgsFile).To(BeAnExistingFile())
+		})
+
+		It("skips the check with --no-hook-check", func() {
+			Expect(resume("--no-hook-check")).NotTo(ContainSubstring("SessionStart hook"))
+		})
+	})
+
+	Describe("active sessions", func() {
+		BeforeEach(func() {
+			GinkgoT().Setenv("XDG_CONFIG_HOME", filepath.Join(tempDir, "xdg"))
+			running := session.NewSession("running", "running-uuid")
+			running.Metadata.Status = session.StatusActive
+			Expect(store.Create(running)).To(Succeed())
+			Expect(store.Create(session.NewSession("next", "next-uuid"))).To(Succeed())
+		})
+
+		resume := func() string {
+			var stderr bytes.Buffer
+			rootCmd := cmd.NewRootCmd()
+			rootCmd.SetOut(io.Discard)
+			rootCmd.SetErr(&stderr)
+			rootCmd.SetArgs([]string{"--claude-bin", filepath.Join(fakeClaudeDir, "claude"), "resume", "next", "--no-hook-check"})
+			Expect(rootCmd.Execute()).To(Succeed())
+			return stderr.String()
+		}
+
+		It("doesn't warn without a soft limit", func() {
+			Expect(resume()).NotTo(ContainSubstring("already active"))
+		})
+
+		It("lists the active sessions once the soft limit is reached", func() {
+			Expect(os.WriteFile(config.GetConfigPath(clotildeRoot), []byte(`{"activeSessions": {"softLimit": 1}}`), 0o644)).To(Succeed())
+
+			stderr := resume()
+			Expect(stderr).To(ContainSubstring("1 session(s) already active (soft limit: 1)"))
+			Expect(stderr).To(ContainSubstring("running  last used just now"))
+			Expect(claudeArgsFile).To(BeAnExistingFile())
+		})
+	})
+
+	Describe("--snippet", func() {
+		BeforeEach(func() {
+			GinkgoT().Setenv("XDG_CONFIG_HOME", filepath.Join(tempDir, "xdg"))
+			Expect(store.Create(session.NewSession("snippy", "snippy-uuid"))).To(Succeed())
+			Expect(snippet.Save(snippet.ProjectDir(clotildeRoot), "deploy-checklist", "Run the migrations first")).To(Succeed())
+			Expect(snippet.Save(snippet.GlobalDir(), "review", "Keep reviews short")).To(Succeed())
+		})
+
+		resume := func(extra ...string) error {
+			rootCmd := cmd.NewRootCmd()
+			rootCmd.SetOut(io.Discard)
+			rootCmd.SetErr(io.Discard)
+			rootCmd.SetArgs(append([]string{"--claude-bin", filepath.Join(fakeClaudeDir, "claude"), "resume", "snippy"}, extra...))
+			return rootCmd.Execute()
+		}
+
+		It("injects the snippets into this launch only", func() {
+			sessionDir := config.GetSessionDir(clotildeRoot, "snippy")
+			injected := filepath.Join(tempDir, "injected.md")
+			// The teardown script runs while claude's launch is still in progress
+			pending := filepath.Join(sessionDir, snippet.PendingFile)
+			Expect(os.WriteFile(filepath.Join(sessionDir, claude.TeardownScript), []byte("cp "+pending+" "+injected+"\n"), 0o644)).To(Succeed())
+
+			Expect(resume("--snippet", "review,deploy-checklist")).To(Succeed())
+			Expect(claudeArgsFile).To(BeAnExistingFile())
+			content, err := os.ReadFile(injected)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal("Snippet review:\nKeep reviews short\nSnippet deploy-checklist:\nRun the migrations first\n"))
+			Expect(filepath.Join(sessionDir, snippet.PendingFile)).NotTo(BeAnExistingFile())
+		})
+
+		It("fails before launching claude for unknown snippets", func() {
+			err := resume("--snippet", "missing")
+			Expect(err).To(MatchError(ContainSubstring("snippet 'missing' not found")))
+			Expect(claudeArgsFile).NotTo(BeAnExistingFile())
+		})
+	})
 })
@@ -2,15 +2,18 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
-	"path/filepath"
+	"strings"
 
 	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 
+	"github.com/fgrehm/clotilde/internal/app"
 	"github.com/fgrehm/clotilde/internal/claude"
 	"github.com/fgrehm/clotilde/internal/config"
-	"github.com/fgrehm/clotilde/internal/outputstyle"
+	"github.com/fgrehm/clotilde/internal/errs"
+	"github.com/fgrehm/clotilde/internal/i18n"
 	"github.com/fgrehm/clotilde/internal/session"
 	"github.com/fgrehm/clotilde/internal/ui"
 	"github.com/fgrehm/clotilde/internal/util"
@@ -29,7 +32,9 @@ func runDashboard(cmd *cobra.Command, args []string) {
 	// Check if in TTY (interactive terminal)
 	isTTY := isatty.IsTerminal(os.Stdout.Fd())
 	if !isTTY {
-		// Non-interactive mode: show help
+		// Non-interactive mode (CI logs, scripts): summarize, then show help
+		writeProjectSummary(cmd.OutOrStdout())
+		_, _ = fmt.Fprintln(cmd.OutOrStdout())
 		_ = cmd.Help()
 		return
 	}
@@ -49,8 +54,8 @@ func runDashboard(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	// Sort by last accessed (most recent first)
-	sortSessionsByLastAccessed(sessions)
+	// Best effort; a broken config already shows up in other commands
+	showIncognito, _ := config.DashboardShowsIncognito(clotildeRoot)
 
 	// Dashboard loop - keep showing dashboard until quit or session launched
 	for {
@@ -60,10 +65,13 @@ func runDashboard(cmd *cobra.Command, args []string) {
 			_, _ = fmt.Fprintf(os.Stderr, "Failed to load sessions: %v\n", err)
 			os.Exit(1)
 		}
-		sortSessionsByLastAccessed(sessions)
+		session.SortStarredFirst(sessions)
 
 		// Show dashboard
-		dashboard := ui.NewDashboard(sessions)
+		dashboard := ui.NewDashboard(sessions).
+			WithActivityStats(activityLoader(clotildeRoot, sessions)).
+			WithIncognitoInRecent(showIncognito).
+			WithYolo(yoloSessions(store, sessions))
 		selectedAction, err := ui.RunDashboard(dashboard)
 		if err != nil {
 			_, _ = fmt.Fprintf(os.Stderr, "Dashboard error: %v\n", err)
@@ -84,6 +92,26 @@ func runDashboard(cmd *cobra.Command, args []string) {
 	}
 }
 
+// writeProjectSummary prints porcelain "key<TAB>value" lines (see
+// porcelainSummaryKeys) saying whether a project was found, how many sessions
+// it has and which was used last. Never creates the clotilde root.
+func writeProjectSummary(w io.Writer) {
+	values := map[string]any{"project": "", "sessions": 0, "last-session": "", "last-used": ""}
+	if clotildeRoot, err := findClotildeRoot(); err == nil {
+		values["project"] = config.ProjectRootOf(clotildeRoot)
+		if sessions, err := session.NewFileStore(clotildeRoot).List(); err == nil {
+			values["sessions"] = len(sessions)
+			sortSessionsByLastAccessed(sessions)
+			if len(sessions) > 0 {
+				values["last-session"], values["last-used"] = sessions[0].Name, sessions[0].Metadata.LastAccessed
+			}
+		}
+	}
+	for _, key := range porcelainSummaryKeys {
+		writePorcelainLine(w, key, values[key])
+	}
+}
+
 // handleDashboardAction handles a dashboard action and returns true if we should exit
 func handleDashboardAction(selectedAction string, sessions []*session.Session, clotildeRoot string, store session.Store) bool {
 	switch selectedAction {
@@ -104,7 +132,15 @@ func handleDashboardAction(selectedAction string, sessions []*session.Session, c
 		fmt.Println(ui.Success(fmt.Sprintf("Created session '%s' (%s)", result.Session.Name, result.Session.Metadata.SessionID)))
 		fmt.Println("\nStarting Claude Code...")
 
-		if err := claude.Start(result.ClotildeRoot, result.Session, result.SettingsFile, nil); err != nil {
+		err = claude.Start(result.ClotildeRoot, result.Session, result.SettingsFile, nil, "")
+		if err := handleLaunchFailure(os.Stderr, result.ClotildeRoot, err); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Failed to start session: %v\n", err)
+			os.Exit(1)
+		}
+		return true
+
+	case "quick":
+		if err := startQuickIncognito(os.Stdout, sessions, "", nil); err != nil {
 			_, _ = fmt.Fprintf(os.Stderr, "Failed to start session: %v\n", err)
 			os.Exit(1)
 		}
@@ -117,8 +153,7 @@ func handleDashboardAction(selectedAction string, sessions []*session.Session, c
 			return false // Stay in dashboard
 		}
 
-		picker := ui.NewPicker(sessions, "Select session to resume").WithPreview()
-		selected, err := ui.RunPicker(picker)
+		selected, err := pickSession(clotildeRoot, store, sessions, "Select session to resume")
 		if err != nil {
 			_, _ = fmt.Fprintf(os.Stderr, "Picker failed: %v\n", err)
 			os.Exit(1)
@@ -129,15 +164,8 @@ func handleDashboardAction(selectedAction string, sessions []*session.Session, c
 			return false
 		}
 
-		// Update last accessed
-		selected.UpdateLastAccessed()
-		if err := store.Update(selected); err != nil {
-			_, _ = fmt.Fprintf(os.Stderr, "Failed to update session: %v\n", err)
-			os.Exit(1)
-		}
-
-		// Resume the session (reuse logic from resume command)
-		if err := resumeSession(clotildeRoot, selected, store); err != nil {
+		// Resume the session (shares app.ResumeSession with the resume command)
+		if err := resumeSession(os.Stdout, clotildeRoot, selected, store); err != nil {
 			_, _ = fmt.Fprintf(os.Stderr, "Failed to resume session: %v\n", err)
 			os.Exit(1)
 		}
@@ -163,8 +191,7 @@ func handleDashboardAction(selectedAction string, sessions []*session.Session, c
 			return false
 		}
 
-		picker := ui.NewPicker(forkable, "Select session to fork").WithPreview()
-		parent, err := ui.RunPicker(picker)
+		parent, err := pickSession(clotildeRoot, store, forkable, "Select session to fork")
 		if err != nil {
 			_, _ = fmt.Fprintf(os.Stderr, "Picker failed: %v\n", err)
 			os.Exit(1)
@@ -173,7 +200,7 @@ func handleDashboardAction(selectedAction string, sessions []*session.Session, c
 			return false
 		}
 
-		if err := forkFromDashboard(clotildeRoot, parent, sessions, store); err != nil {
+		if err := forkFromDashboard(os.Stdout, clotildeRoot, parent, sessions, store); err != nil {
 			_, _ = fmt.Fprintf(os.Stderr, "Failed to fork session: %v\n", err)
 			os.Exit(1)
 		}
@@ -181,7 +208,7 @@ func handleDashboardAction(selectedAction string, sessions []*session.Session, c
 
 	case "list":
 		// Show interactive table
-		selected, err := showInteractiveTable(sessions, store)
+		selected, err := showInteractiveTable(clotildeRoot, sessions, store)
 		if err != nil {
 			_, _ = fmt.Fprintf(os.Stderr, "Failed to show table: %v\n", err)
 			os.Exit(1)
@@ -192,15 +219,8 @@ func handleDashboardAction(selectedAction string, sessions []*session.Session, c
 			return false
 		}
 
-		// Update last accessed
-		selected.UpdateLastAccessed()
-		if err := store.Update(selected); err != nil {
-			_, _ = fmt.Fprintf(os.Stderr, "Failed to update session: %v\n", err)
-			os.Exit(1)
-		}
-
 		// Resume the selected session
-		if err := resumeSession(clotildeRoot, selected, store); err != nil {
+		if err := resumeSession(os.Stdout, clotildeRoot, selected, store); err != nil {
 			_, _ = fmt.Fprintf(os.Stderr, "Failed to resume session: %v\n", err)
 			os.Exit(1)
 		}
@@ -215,8 +235,7 @@ func handleDashboardAction(selectedAction string, sessions []*session.Session, c
 			return false // Stay in dashboard
 		}
 
-		picker := ui.NewPicker(sessions, "Select session to delete").WithPreview()
-		selected, err := ui.RunPicker(picker)
+		selected, err := pickSession(clotildeRoot, store, sessions, "Select session to delete")
 		if err != nil {
 			_, _ = fmt.Fprintf(os.Stderr, "Picker failed: %v\n", err)
 			os.Exit(1)
@@ -227,26 +246,22 @@ func handleDashboardAction(selectedAction string, sessions []*session.Session, c
 			return false
 		}
 
-		// Show confirmation with details
-		details := buildDeletionDetails(clotildeRoot, selected)
-		confirmModel := ui.NewConfirm(
-			fmt.Sprintf("Delete session '%s'?", selected.Name),
-			"This will permanently delete:",
-		).WithDetails(details).WithDestructive()
-
-		confirmed, err := ui.RunConfirm(confirmModel)
-		if err != nil {
-			_, _ = fmt.Fprintf(os.Stderr, "Confirmation dialog failed: %v\n", err)
-			os.Exit(1)
-		}
+		// Show confirmation with details, unless the "confirm" config skips it
+		if level, reasons := deletionConfirmation(clotildeRoot, selected, store); level != confirmSkip {
+			confirmed, err := ui.RunConfirm(newDeleteConfirm(clotildeRoot, selected, store, reasons))
+			if err != nil {
+				_, _ = fmt.Fprintf(os.Stderr, "Confirmation dialog failed: %v\n", err)
+				os.Exit(1)
+			}
 
-		if !confirmed {
-			// Cancelled - go back to dashboard
-			return false
+			if !confirmed {
+				// Cancelled - go back to dashboard
+				return false
+			}
 		}
 
-		// Delete the session (reuse logic from delete command)
-		if err := deleteSession(clotildeRoot, selected, store); err != nil {
+		// Delete the session (shares app.DeleteSession with the delete command)
+		if err := deleteSession(os.Stdout, clotildeRoot, selected, store); err != nil {
 			_, _ = fmt.Fprintf(os.Stderr, "Failed to delete session: %v\n", err)
 			os.Exit(1)
 		}
@@ -306,26 +321,93 @@ func registerSubcommands(root *cobra.Command) {
 		RunE:  initCmd.RunE,
 	}
 	freshInitCmd.Flags().Bool("global", false, "Install hooks in .claude/settings.json (project-wide) instead of settings.local.json (local)")
+	freshInitCmd.Flags().Bool("gitignore", true, "Keep session data out of git (.git/info/exclude, or .gitignore with --global)")
+	registerHookPathFlag(freshInitCmd)
+	freshInitCmd.Flags().String("storage", "", `Where to keep sessions: "project" (.claude/clotilde) or "data" ($XDG_DATA_HOME/clotilde); default from the global config`)
+	freshInitCmd.Flags().Bool("hooks-only", false, "Only (re)install the hooks, without creating folders or touching config")
+	freshInitCmd.Flags().Bool("force", false, "Rewrite clotilde's hooks even when they're already installed in an older format")
+	freshInitCmd.Flags().String("import", importAsk, `Existing Claude Code conversations to import as sessions: "ask", "all" or "none"`)
 
 	root.AddCommand(freshInitCmd)
 	root.AddCommand(newSetupCmd())
 	root.AddCommand(newStartCmd())
+	root.AddCommand(newSyncCmd())
 	root.AddCommand(newIncognitoCmd())
+	root.AddCommand(newQuickCmd())
 	root.AddCommand(newResumeCmd())
-	root.AddCommand(listCmd)
-	root.AddCommand(inspectCmd)
+	root.AddCommand(newSwitchCmd())
+	root.AddCommand(newListCmd())
+	root.AddCommand(newInspectCmd())
+	root.AddCommand(newSimilarCmd())
+	root.AddCommand(newContextCmd())
+	root.AddCommand(newStylesCmd())
+	root.AddCommand(newStatsCmd())
+	root.AddCommand(newTimelineCmd())
+	root.AddCommand(newReportCmd())
+	root.AddCommand(newLastErrorCmd())
+	root.AddCommand(newLogsCmd())
+	root.AddCommand(newEventsCmd())
+	root.AddCommand(newEnvCmd())
+	root.AddCommand(newSnippetsCmd())
+	root.AddCommand(newEncryptionCmd())
 	root.AddCommand(newForkCmd())
-	root.AddCommand(deleteCmd)
+	root.AddCommand(newDeleteCmd())
+	root.AddCommand(newArchiveCmd())
+	root.AddCommand(newUnarchiveCmd())
+	root.AddCommand(newStarCmd())
 	root.AddCommand(newExportCmd())
+	root.AddCommand(newExportTranscriptCmd())
+	root.AddCommand(newBackupCmd())
+	root.AddCommand(newProjectsCmd())
+	root.AddCommand(newMigratePathCmd())
+	root.AddCommand(newServeCmd())
+	root.AddCommand(newDaemonCmd())
+	root.AddCommand(newPruneCmd())
+	root.AddCommand(newMaintainCmd())
+	root.AddCommand(newDoctorCmd())
 	root.AddCommand(hookCmd)
+	root.AddCommand(newHooksCmd())
+	root.AddCommand(newWhyCmd())
+	root.AddCommand(newIntegrateCmd())
 	root.AddCommand(versionCmd)
 	root.AddCommand(newCompletionCmd())
+	root.AddCommand(newShellInitCmd())
 
 	root.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Enable verbose output")
+	root.PersistentFlags().StringVarP(&projectRootOverride, "root", "C", "", "Read sessions from another project (read-only commands)")
+	root.PersistentFlags().StringVar(&remoteTarget, "remote", "", "Use sessions of a project on another host over ssh, as <remote>:<project-path> (list, resume)")
+	registerDiagnosticFlags(root)
+	root.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if err := startDiagnostics(); err != nil {
+			return err
+		}
+		setLanguage()
+		readTitleFlag(cmd)
+		if err := checkProjectRootOverride(cmd, args); err != nil {
+			return err
+		}
+		if err := checkRemote(cmd, args); err != nil {
+			return err
+		}
+		return nil
+	}
+	root.PersistentPostRun = func(cmd *cobra.Command, args []string) {
+		runBackgroundMaintenance(cmd)
+	}
 	root.PersistentFlags().StringVar(&claudeBinaryPath, "claude-bin", "", "Path to claude binary (hidden, for testing)")
 	_ = root.PersistentFlags().MarkHidden("claude-bin")
 }
 
+// setLanguage selects the language of user-facing messages from the global
+// config or the locale. An unreadable config just means the locale decides.
+func setLanguage() {
+	var configured string
+	if cfg, err := config.LoadGlobalOrDefault(); err == nil {
+		configured = cfg.Language
+	}
+	i18n.SetLanguage(i18n.Detect(configured))
+}
+
 // GetClaudeBinaryPath returns the path to the claude binary.
 // If --claude-bin flag is set, returns that path. Otherwise returns "claude".
 func GetClaudeBinaryPath() string {
@@ -341,86 +423,62 @@ func IsVerbose() bool {
 }
 
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
+	err := rootCmd.Execute()
+	stopDiagnostics(os.Stderr)
+	if err != nil {
+		os.Exit(errs.ExitCode(err))
 	}
 }
 
-// resumeSession resumes a session (extracted from resume command)
-func resumeSession(clotildeRoot string, sess *session.Session, _ session.Store) error {
-	sessionDir := config.GetSessionDir(clotildeRoot, sess.Name)
-
-	// Check for settings file
-	var settingsFile string
-	settingsPath := filepath.Join(sessionDir, "settings.json")
-	if util.FileExists(settingsPath) {
-		settingsFile = settingsPath
+// resumeSession resumes a session picked from the dashboard or switcher: no
+// flags, so session settings > project default.
+func resumeSession(out io.Writer, clotildeRoot string, sess *session.Session, store session.Store) error {
+	resolved, pinned, err := resolveSessionSettings(clotildeRoot, store, sess.Name, settingsLayer{Source: sourceFlag})
+	if err != nil {
+		return err
 	}
 
-	fmt.Printf("Resuming session '%s' (%s)\n\n", sess.Name, sess.Metadata.SessionID)
+	_, _ = fmt.Fprintf(out, "Resuming session '%s' (%s)\n\n", sess.Name, sess.Metadata.SessionID)
+	printSessionOverrides(out, resolved, pinned)
+	if recap, err := config.ResumeRecap(clotildeRoot); err == nil && recap {
+		printRecap(out, sess)
+	}
 
-	// Invoke claude
-	return claude.Resume(clotildeRoot, sess, settingsFile, nil)
+	return app.ResumeSession(clotildeRoot, store, sess, app.ResumeOptions{Args: resolved.launchArgs()})
 }
 
-// deleteSession deletes a session (extracted from delete command)
-func deleteSession(clotildeRoot string, sess *session.Session, store session.Store) error {
-	// Track all deleted files for verbose output
-	allDeletedFiles := &claude.DeletedFiles{
-		Transcript: []string{},
-		AgentLogs:  []string{},
+// deleteSession deletes a session with app.DeleteSession, reporting what was
+// removed to out.
+func deleteSession(out io.Writer, clotildeRoot string, sess *session.Session, store session.Store) error {
+	result, err := app.DeleteSession(clotildeRoot, store, sess)
+	for _, warning := range result.Warnings {
+		_, _ = fmt.Fprintln(out, ui.Warning(warning))
 	}
-
-	// Delete Claude data for current session (transcript and agent logs)
-	deleted, err := claude.DeleteSessionData(clotildeRoot, sess.Metadata.SessionID, sess.Metadata.TranscriptPath)
 	if err != nil {
-		fmt.Println(ui.Warning(fmt.Sprintf("Failed to delete Claude data for current session: %v", err)))
-	} else {
-		allDeletedFiles.Transcript = append(allDeletedFiles.Transcript, deleted.Transcript...)
-		allDeletedFiles.AgentLogs = append(allDeletedFiles.AgentLogs, deleted.AgentLogs...)
+		return err
 	}
 
-	// Delete Claude data for previous sessions (from /clear operations, and defensively from /compact)
-	for _, prevSessionID := range sess.Metadata.PreviousSessionIDs {
-		deleted, err := claude.DeleteSessionData(clotildeRoot, prevSessionID, "")
-		if err != nil {
-			fmt.Println(ui.Warning(fmt.Sprintf("Failed to delete Claude data for previous session %s: %v", prevSessionID, err)))
-		} else {
-			allDeletedFiles.Transcript = append(allDeletedFiles.Transcript, deleted.Transcript...)
-			allDeletedFiles.AgentLogs = append(allDeletedFiles.AgentLogs, deleted.AgentLogs...)
-		}
+	_, _ = fmt.Fprintln(out, ui.Success(fmt.Sprintf("Deleted session '%s'", sess.Name)))
+	_, _ = fmt.Fprintf(out, "  Session folder, %d transcript(s), %d agent log(s)\n", len(result.Transcripts), len(result.AgentLogs))
+	if len(result.Detached) > 0 {
+		_, _ = fmt.Fprintf(out, "  Detached %d fork(s): %s\n", len(result.Detached), strings.Join(result.Detached, ", "))
 	}
-
-	// Delete session folder
-	if err := store.Delete(sess.Name); err != nil {
-		return fmt.Errorf("failed to delete session: %w", err)
+	if len(result.StyleUsers) > 0 {
+		_, _ = fmt.Fprintf(out, "  Kept output style, still used by: %s\n", strings.Join(result.StyleUsers, ", "))
 	}
 
-	// Delete custom output style if it exists
-	if sess.Metadata.HasCustomOutputStyle {
-		if err := outputstyle.DeleteCustomStyleFile(clotildeRoot, sess.Name); err != nil {
-			fmt.Println(ui.Warning(fmt.Sprintf("Failed to delete output style file: %v", err)))
-		}
-	}
-
-	// Show summary of what was deleted
-	transcriptCount := len(allDeletedFiles.Transcript)
-	agentLogCount := len(allDeletedFiles.AgentLogs)
-	fmt.Println(ui.Success(fmt.Sprintf("Deleted session '%s'", sess.Name)))
-	fmt.Printf("  Session folder, %d transcript(s), %d agent log(s)\n", transcriptCount, agentLogCount)
-
 	// Show detailed file paths in verbose mode
 	if verbose {
-		if transcriptCount > 0 {
-			fmt.Println("\n  Deleted transcripts:")
-			for _, path := range allDeletedFiles.Transcript {
-				fmt.Printf("    %s\n", path)
+		if len(result.Transcripts) > 0 {
+			_, _ = fmt.Fprintln(out, "\n  Deleted transcripts:")
+			for _, path := range result.Transcripts {
+				_, _ = fmt.Fprintf(out, "    %s\n", path)
 			}
 		}
-		if agentLogCount > 0 {
-			fmt.Println("\n  Deleted agent logs:")
-			for _, path := range allDeletedFiles.AgentLogs {
-				fmt.Printf("    %s\n", path)
+		if len(result.AgentLogs) > 0 {
+			_, _ = fmt.Fprintln(out, "\n  Deleted agent logs:")
+			for _, path := range result.AgentLogs {
+				_, _ = fmt.Fprintf(out, "    %s\n", path)
 			}
 		}
 	}
@@ -428,42 +486,26 @@ func deleteSession(clotildeRoot string, sess *session.Session, store session.Sto
 	return nil
 }
 
-// forkFromDashboard creates a fork with an auto-generated name and launches Claude
-func forkFromDashboard(clotildeRoot string, parent *session.Session, sessions []*session.Session, store session.Store) error {
+// forkFromDashboard creates a fork with an auto-generated name, the same way
+// the fork command does, and launches Claude
+func forkFromDashboard(out io.Writer, clotildeRoot string, parent *session.Session, sessions []*session.Session, store session.Store) error {
 	existingNames := make([]string, len(sessions))
 	for i, s := range sessions {
 		existingNames[i] = s.Name
 	}
 	forkName := util.GenerateUniqueRandomName(existingNames)
 
-	// Create fork session with empty sessionId (filled by hook)
-	fork := session.NewSession(forkName, "")
-	fork.Metadata.IsForkedSession = true
-	fork.Metadata.ParentSession = parent.Name
-	fork.Metadata.Context = parent.Metadata.Context
-
-	if err := store.Create(fork); err != nil {
-		return fmt.Errorf("failed to create fork: %w", err)
-	}
-
-	forkDir := config.GetSessionDir(clotildeRoot, forkName)
-	parentDir := config.GetSessionDir(clotildeRoot, parent.Name)
-
-	// Copy settings.json if exists
-	parentSettings := filepath.Join(parentDir, "settings.json")
-	if util.FileExists(parentSettings) {
-		if err := util.CopyFile(parentSettings, filepath.Join(forkDir, "settings.json")); err != nil {
-			return fmt.Errorf("failed to copy settings: %w", err)
-		}
+	created, err := createFork(clotildeRoot, store, parent, forkName, settingsLayer{Source: sourceFlag}, app.ForkOptions{})
+	if err != nil {
+		return err
 	}
 
-	fmt.Println(ui.Success(fmt.Sprintf("Created fork '%s' from '%s'", forkName, parent.Name)))
-	fmt.Println("\nStarting Claude Code with fork...")
-
-	var settingsFile string
-	if util.FileExists(filepath.Join(forkDir, "settings.json")) {
-		settingsFile = filepath.Join(forkDir, "settings.json")
-	}
+	_, _ = fmt.Fprintln(out, ui.Success(fmt.Sprintf("Created fork '%s' from '%s'", forkName, parent.Name)))
+	printSessionOverrides(out, created.Resolved, created.Pinned)
+	_, _ = fmt.Fprintln(out, "\nStarting Claude Code with fork...")
 
-	return claude.Fork(clotildeRoot, parent, forkName, settingsFile, nil, fork)
+	// Model and effort are in the fork's settings.json; only the permission mode is per-run
+	perRun := resolvedSettings{PermissionMode: created.Resolved.PermissionMode}
+	err = claude.Fork(clotildeRoot, parent, forkName, created.SettingsFile, perRun.launchArgs(), created.Session)
+	return handleLaunchFailure(out, clotildeRoot, err)
 }
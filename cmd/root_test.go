@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/session"
+)
+
+func TestWriteProjectSummary(t *testing.T) {
+	t.Chdir(t.TempDir())
+	var out bytes.Buffer
+	writeProjectSummary(&out)
+	if want := "project\t-\nsessions\t0\nlast-session\t-\nlast-used\t-\n"; out.String() != want {
+		t.Errorf("Outside a project got %q, want %q", out.String(), want)
+	}
+
+	projectRoot, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := config.EnsureClotildeStructure(projectRoot); err != nil {
+		t.Fatal(err)
+	}
+	store := session.NewFileStore(filepath.Join(projectRoot, config.ClotildeDir))
+	used := time.Date(2026, 3, 1, 14, 0, 0, 0, time.UTC)
+	for i, name := range []string{"older", "newer"} {
+		sess := session.NewSession(name, "uuid-"+name)
+		sess.Metadata.LastAccessed = used.Add(time.Duration(i) * time.Hour)
+		if err := store.Create(sess); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Chdir(projectRoot)
+
+	out.Reset()
+	writeProjectSummary(&out)
+	want := "project\t" + projectRoot + "\nsessions\t2\nlast-session\tnewer\nlast-used\t2026-03-01T15:00:00Z\n"
+	if out.String() != want {
+		t.Errorf("In a project got %q, want %q", out.String(), want)
+	}
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/ui"
+	"github.com/fgrehm/clotilde/internal/web"
+)
+
+func newServeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve [--port <n>] [--host <addr>]",
+		Short: "Serve a web dashboard of the project's sessions",
+		Long: `Serve a minimal web interface to the project's sessions: list and inspect
+them, read their transcripts, archive or delete them, and copy the command
+that resumes one. Handy on a remote dev box reached through port forwarding, e.g.
+'ssh -L 8787:localhost:8787 devbox'.
+
+It listens on localhost only unless --host says otherwise. There is no login,
+so anyone who can reach the port can read and delete sessions. Browsers only
+get the dashboard through localhost, an IP address or the --host name, and
+forms posted from other sites are refused.`,
+		Example: `  clotilde serve
+  clotilde serve --port 9000`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clotildeRoot, err := config.FindClotildeRoot()
+			if err != nil {
+				return errNoSessions()
+			}
+			server, err := web.New(clotildeRoot)
+			if err != nil {
+				return err
+			}
+
+			host, _ := cmd.Flags().GetString("host")
+			port, _ := cmd.Flags().GetInt("port")
+			listener, err := net.Listen("tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+			if err != nil {
+				return fmt.Errorf("failed to listen: %w", err)
+			}
+
+			out := cmd.OutOrStdout()
+			_, _ = fmt.Fprintf(out, "Serving sessions at http://%s (Ctrl-C to stop)\n", listener.Addr())
+			if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+				_, _ = fmt.Fprintln(out, ui.Warning(fmt.Sprintf("Listening on %s: anyone who can reach it can read and delete sessions", host)))
+			}
+
+			httpServer := &http.Server{Handler: server.Handler(host), ReadHeaderTimeout: 10 * time.Second}
+			go func() {
+				<-cmd.Context().Done()
+				_ = httpServer.Close()
+			}()
+			if err := httpServer.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+				return fmt.Errorf("server failed: %w", err)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Int("port", 8787, "Port to listen on (0 picks a free one)")
+	cmd.Flags().String("host", "127.0.0.1", "Address to listen on")
+	return cmd
+}
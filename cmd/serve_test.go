@@ -0,0 +1,72 @@
+package cmd_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+
+	"github.com/fgrehm/clotilde/cmd"
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/session"
+)
+
+var _ = Describe("Serve Command", func() {
+	var originalWd string
+
+	BeforeEach(func() {
+		tempDir := GinkgoT().TempDir()
+		GinkgoT().Setenv("HOME", filepath.Join(tempDir, "home"))
+
+		var err error
+		originalWd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Chdir(tempDir)).To(Succeed())
+
+		Expect(config.EnsureClotildeStructure(tempDir)).To(Succeed())
+		store := session.NewFileStore(filepath.Join(tempDir, config.ClotildeDir))
+		Expect(store.Create(session.NewSession("work", "uuid-work"))).To(Succeed())
+	})
+
+	AfterEach(func() {
+		_ = os.Chdir(originalWd)
+	})
+
+	It("serves the dashboard until cancelled", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		out := gbytes.NewBuffer()
+		done := make(chan error)
+		go func() {
+			rootCmd := cmd.NewRootCmd()
+			rootCmd.SetOut(out)
+			rootCmd.SetErr(io.Discard)
+			rootCmd.SetArgs([]string{"serve", "--port", "0"})
+			done <- rootCmd.ExecuteContext(ctx)
+		}()
+
+		Eventually(out).Should(gbytes.Say(`Serving sessions at http://127\.0\.0\.1:\d+`))
+		url := regexp.MustCompile(`http://\S+`).FindString(string(out.Contents()))
+
+		resp, err := http.Get(url + "/")
+		Expect(err).NotTo(HaveOccurred())
+		body, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(body)).To(ContainSubstring("clotilde resume work"))
+
+		cancel()
+		Eventually(done, "2s").Should(Receive(BeNil()))
+	})
+
+	It("fails outside a project", func() {
+		Expect(os.Chdir(GinkgoT().TempDir())).To(Succeed())
+		_, err := runClotilde("serve", "--port", "0")
+		Expect(err).To(MatchError(ContainSubstring("no sessions found")))
+	})
+})
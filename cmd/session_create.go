@@ -1,14 +1,24 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
+	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 
+	"github.com/fgrehm/clotilde/internal/claude"
 	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/errs"
 	"github.com/fgrehm/clotilde/internal/outputstyle"
+	"github.com/fgrehm/clotilde/internal/registry"
 	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/ui"
 	"github.com/fgrehm/clotilde/internal/util"
 )
 
@@ -17,6 +27,16 @@ import (
 func buildSessionCreateParams(cmd *cobra.Command, name string) (SessionCreateParams, error) {
 	params := buildCommonParams(cmd, name)
 	params.Incognito, _ = cmd.Flags().GetBool("incognito")
+	params.Pending, _ = cmd.Flags().GetBool("no-launch")
+	if params.Incognito && params.Pending {
+		return SessionCreateParams{}, fmt.Errorf("cannot use --no-launch with --incognito (incognito sessions are deleted when Claude exits)")
+	}
+
+	expiresAt, err := expiresAtFlag(cmd)
+	if err != nil {
+		return SessionCreateParams{}, err
+	}
+	params.ExpiresAt = expiresAt
 
 	// Validate output style flags
 	if params.OutputStyle != "" && params.OutputStyleFile != "" {
@@ -46,6 +66,7 @@ func buildCommonParams(cmd *cobra.Command, name string) SessionCreateParams {
 	outputStyleFile, _ := cmd.Flags().GetString("output-style-file")
 	context, _ := cmd.Flags().GetString("context")
 	effort, _ := cmd.Flags().GetString("effort")
+	launcher, _ := cmd.Flags().GetString("launcher")
 
 	return SessionCreateParams{
 		Name:            name,
@@ -59,7 +80,81 @@ func buildCommonParams(cmd *cobra.Command, name string) SessionCreateParams {
 		OutputStyleFile: outputStyleFile,
 		Context:         context,
 		EffortLevel:     effort,
+		Launcher:        launcher,
+	}
+}
+
+// registerNoLaunchFlag adds the --no-launch flag to commands that create sessions.
+func registerNoLaunchFlag(cmd *cobra.Command, usage string) {
+	cmd.Flags().Bool("no-launch", false, usage)
+}
+
+// printNoLaunch finishes a --no-launch run. Session names go to stdout, one per
+// line, so scripts can capture them; the hint for humans goes to stderr.
+func printNoLaunch(cmd *cobra.Command, names ...string) {
+	for _, name := range names {
+		_, _ = fmt.Fprintln(cmd.OutOrStdout(), name)
+	}
+	hint := "Start them with 'clotilde resume <name>' or pick one from 'clotilde resume'"
+	if len(names) == 1 {
+		hint = fmt.Sprintf("Start it with 'clotilde resume %s'", names[0])
+	}
+	_, _ = fmt.Fprintln(cmd.ErrOrStderr(), ui.Info(hint))
+}
+
+// registerSlugifyFlag adds the --slugify flag to commands that take a new session name.
+func registerSlugifyFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("slugify", false, "Convert the name into a valid session name (e.g. \"My Feature!\" -> my-feature)")
+}
+
+// sessionNameArg returns the session name to use for a user-provided name.
+// With --slugify, arbitrary text is converted into a valid name first.
+func sessionNameArg(cmd *cobra.Command, clotildeRoot, name string) (string, error) {
+	if slugify, _ := cmd.Flags().GetBool("slugify"); !slugify {
+		return name, nil
+	}
+
+	rules, err := session.LoadNameRules(clotildeRoot)
+	if err != nil {
+		return "", fmt.Errorf("invalid naming config: %w", err)
+	}
+	slug := rules.Slugify(name)
+	if slug == "" {
+		return "", fmt.Errorf("cannot derive a session name from %q", name)
 	}
+	return slug, nil
+}
+
+// registerLauncherFlag adds the --launcher flag to commands that create sessions.
+func registerLauncherFlag(cmd *cobra.Command) {
+	cmd.Flags().String("launcher", "", "Run the session through this command instead of claude (placeholders: {claude}, {args}, {sessionId}, {name}, {settings}, {prompt})")
+}
+
+// registerExpiresFlag adds the --expires flag to commands that create sessions.
+func registerExpiresFlag(cmd *cobra.Command) {
+	cmd.Flags().String("expires", "", "Mark the session as expired after this long (e.g. 12h, 7d, 2w)")
+}
+
+// expiresAtFlag returns the expiry timestamp requested via --expires, or the
+// zero time when the flag is not set.
+func expiresAtFlag(cmd *cobra.Command) (time.Time, error) {
+	value, _ := cmd.Flags().GetString("expires")
+	if value == "" {
+		return time.Time{}, nil
+	}
+	d, err := util.ParseDuration(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --expires: %w", err)
+	}
+	return time.Now().Add(d), nil
+}
+
+// printExpiry tells the user when a newly created session expires.
+func printExpiry(out io.Writer, sess *session.Session) {
+	if sess.Metadata.ExpiresAt.IsZero() {
+		return
+	}
+	_, _ = fmt.Fprintf(out, "  Expires %s (clean up with 'clotilde prune --expired')\n", sess.Metadata.ExpiresAt.Format("2006-01-02 15:04"))
 }
 
 // SessionCreateParams holds parameters for creating a new session.
@@ -71,11 +166,15 @@ type SessionCreateParams struct {
 	AllowedTools    []string
 	DisallowedTools []string
 	AdditionalDirs  []string
-	OutputStyle     string // built-in style, custom style name, or inline content
-	OutputStyleFile string // path to custom style file
-	Context         string // session context (e.g. "working on ticket GH-123")
-	EffortLevel     string // effort level (low, medium, high, max)
+	OutputStyle     string    // built-in style, custom style name, or inline content
+	OutputStyleFile string    // path to custom style file
+	Context         string    // session context (e.g. "working on ticket GH-123")
+	EffortLevel     string    // effort level (low, medium, high, max)
+	ExpiresAt       time.Time // zero means the session never expires
 	Incognito       bool
+	Pending         bool   // created with --no-launch; Claude Code starts on first resume
+	Prompt          string // first message; kept for the first resume of pending sessions
+	Launcher        string // command template run instead of claude, split like a shell would
 }
 
 // SessionCreateResult holds the created session and file paths.
@@ -83,6 +182,7 @@ type SessionCreateResult struct {
 	ClotildeRoot string
 	Session      *session.Session
 	SettingsFile string
+	Resolved     resolvedSettings // effective model/effort/permission mode and their sources
 }
 
 // createSession handles common session creation logic.
@@ -94,8 +194,15 @@ func createSession(params SessionCreateParams) (*SessionCreateResult, error) {
 		return nil, fmt.Errorf("failed to initialize session storage: %w", err)
 	}
 
-	// Validate session name
-	if err := session.ValidateName(params.Name); err != nil {
+	// Record the project for 'clotilde projects' (best effort, never blocks a start)
+	_ = registry.Register(config.ProjectRootOf(clotildeRoot))
+
+	// Validate session name against the configured naming rules
+	rules, err := session.LoadNameRules(clotildeRoot)
+	if err != nil {
+		return nil, fmt.Errorf("invalid naming config: %w", err)
+	}
+	if err := rules.Validate(params.Name); err != nil {
 		return nil, err
 	}
 
@@ -104,7 +211,7 @@ func createSession(params SessionCreateParams) (*SessionCreateResult, error) {
 
 	// Check if session already exists
 	if store.Exists(params.Name) {
-		return nil, fmt.Errorf("session '%s' already exists", params.Name)
+		return nil, errs.AlreadyExists("session '%s' already exists", params.Name)
 	}
 
 	// Generate UUID for the session
@@ -122,11 +229,24 @@ func createSession(params SessionCreateParams) (*SessionCreateResult, error) {
 	if params.Context != "" {
 		sess.Metadata.Context = params.Context
 	}
+	sess.Metadata.ExpiresAt = params.ExpiresAt
+	sess.Metadata.PendingLaunch = params.Pending
+	if params.Pending {
+		sess.Metadata.InitialPrompt = params.Prompt
+	}
 
 	if err := store.Create(sess); err != nil {
 		return nil, fmt.Errorf("failed to create session: %w", err)
 	}
 
+	// A failure from here on must not leave a half-created session behind
+	created := false
+	defer func() {
+		if !created {
+			rollbackSession(clotildeRoot, store, params.Name)
+		}
+	}()
+
 	sessionDir := config.GetSessionDir(clotildeRoot, params.Name)
 
 	// Load merged profiles (global + project, project takes precedence)
@@ -139,82 +259,103 @@ func createSession(params SessionCreateParams) (*SessionCreateResult, error) {
 	settings := &session.Settings{}
 
 	// Apply profile if specified
+	profileLayer := settingsLayer{Source: sourceProfile}
+	var launcher []string
 	if params.Profile != "" {
 		profile, ok := profiles[params.Profile]
 		if !ok {
-			return nil, fmt.Errorf("profile '%s' not found in config", params.Profile)
+			return nil, errs.NotFound("profile '%s' not found in config", params.Profile)
 		}
 
 		// Apply profile as baseline
-		if profile.Model != "" {
-			settings.Model = profile.Model
-		}
-		if profile.PermissionMode != "" {
-			settings.Permissions.DefaultMode = profile.PermissionMode
-		}
 		if profile.Permissions != nil {
 			settings.Permissions = session.Permissions{
 				Allow:                        profile.Permissions.Allow,
 				Ask:                          profile.Permissions.Ask,
 				Deny:                         profile.Permissions.Deny,
 				AdditionalDirectories:        profile.Permissions.AdditionalDirectories,
-				DefaultMode:                  profile.Permissions.DefaultMode,
 				DisableBypassPermissionsMode: profile.Permissions.DisableBypassPermissionsMode,
 			}
 		}
 		if profile.OutputStyle != "" {
 			settings.OutputStyle = profile.OutputStyle
 		}
+		profileLayer = profileSettingsLayer(profile)
+		launcher = profile.Launcher
 	}
 
-	// CLI flags override profile values
-	if params.Model != "" {
-		settings.Model = params.Model
+	// --launcher overrides the profile's; "defaults.launcher" applies at launch
+	if params.Launcher != "" {
+		if launcher, err = util.ShellSplit(params.Launcher); err != nil {
+			return nil, fmt.Errorf("invalid --launcher: %w", err)
+		}
+	}
+	if len(launcher) > 0 {
+		if err := claude.ValidateLauncher(launcher); err != nil {
+			return nil, err
+		}
+		sess.Metadata.Launcher = launcher
+	} else if _, err := claude.SessionLauncher(clotildeRoot, sess); err != nil {
+		return nil, err
 	}
 
-	// Normalize model shorthand (e.g. "opus" -> "opus[1m]")
-	settings.Model = normalizeModel(settings.Model)
-
-	if params.EffortLevel != "" {
-		settings.EffortLevel = params.EffortLevel
+	defaultsLayer, err := projectDefaultsLayer(clotildeRoot)
+	if err != nil {
+		return nil, err
 	}
 
+	// Resolve model, effort, and permission mode: flag > profile > project default.
+	// normalizeModel is applied by the resolver (e.g. "opus" -> "opus[1m]").
+	resolved := resolveSettings(
+		settingsLayer{
+			Source:         sourceFlag,
+			Model:          params.Model,
+			EffortLevel:    params.EffortLevel,
+			PermissionMode: params.PermissionMode,
+		},
+		profileLayer,
+		defaultsLayer,
+	)
+	settings.Model = resolved.Model.Value
+	settings.EffortLevel = resolved.EffortLevel.Value
+	settings.Permissions.DefaultMode = resolved.PermissionMode.Value
+
 	// Handle output style (CLI flags override profile)
-	var hasCustomStyle bool
+	var styleSource *session.OutputStyleSource
 	if params.OutputStyleFile != "" {
 		// Create custom style from file (validates/injects frontmatter)
 		if err := outputstyle.CreateCustomStyleFileFromFile(clotildeRoot, params.Name, params.OutputStyleFile); err != nil {
 			return nil, fmt.Errorf("failed to create custom style: %w", err)
 		}
 		settings.OutputStyle = outputstyle.GetCustomStyleReference(params.Name)
-		hasCustomStyle = true
+		sourcePath, err := filepath.Abs(params.OutputStyleFile)
+		if err != nil {
+			sourcePath = params.OutputStyleFile
+		}
+		styleSource = &session.OutputStyleSource{Kind: session.OutputStyleCustom, Path: sourcePath}
 	} else if params.OutputStyle != "" {
-		switch {
-		case outputstyle.IsBuiltIn(params.OutputStyle):
-			// Use built-in style directly
-			settings.OutputStyle = params.OutputStyle
-			hasCustomStyle = false
-		case outputstyle.StyleExists(clotildeRoot, params.OutputStyle):
-			// Reference existing style by name (don't create new file)
+		if outputstyle.IsBuiltIn(params.OutputStyle) || outputstyle.StyleExists(clotildeRoot, params.OutputStyle) {
+			// Reference built-in or existing style by name (don't create new file)
 			settings.OutputStyle = params.OutputStyle
-			hasCustomStyle = false
-		default:
+			styleSource = outputstyle.ReferenceSource(clotildeRoot, params.OutputStyle)
+		} else {
 			// Treat as custom inline content - create new session-specific style
 			if err := outputstyle.CreateCustomStyleFile(clotildeRoot, params.Name, params.OutputStyle); err != nil {
 				return nil, fmt.Errorf("failed to create custom style: %w", err)
 			}
 			settings.OutputStyle = outputstyle.GetCustomStyleReference(params.Name)
-			hasCustomStyle = true
+			styleSource = &session.OutputStyleSource{Kind: session.OutputStyleCustom}
 		}
+	} else if settings.OutputStyle != "" {
+		// From the profile, which only references styles by name
+		styleSource = outputstyle.ReferenceSource(clotildeRoot, settings.OutputStyle)
 	}
 
 	// Update metadata
-	sess.Metadata.HasCustomOutputStyle = hasCustomStyle
+	sess.Metadata.OutputStyleSource = styleSource
+	sess.Metadata.HasCustomOutputStyle = sess.Metadata.OwnsOutputStyle()
 
 	// CLI permission flags override profile values (replace, don't merge)
-	if params.PermissionMode != "" {
-		settings.Permissions.DefaultMode = params.PermissionMode
-	}
 	if len(params.AllowedTools) > 0 {
 		settings.Permissions.Allow = params.AllowedTools
 	}
@@ -234,12 +375,71 @@ func createSession(params SessionCreateParams) (*SessionCreateResult, error) {
 		return nil, fmt.Errorf("failed to update session: %w", err)
 	}
 
+	created = true
+
 	// Build result
 	result := &SessionCreateResult{
 		ClotildeRoot: clotildeRoot,
 		Session:      sess,
 		SettingsFile: filepath.Join(sessionDir, "settings.json"),
+		Resolved:     resolved,
 	}
 
 	return result, nil
 }
+
+// rollbackSession removes a session that was created but never used, along
+// with its custom output style.
+func rollbackSession(clotildeRoot string, store session.Store, name string) {
+	_ = store.Delete(name)
+	_ = outputstyle.DeleteCustomStyleFile(clotildeRoot, name)
+}
+
+// launchStderrLines bounds how much of claude's error output is repeated
+// after a failed launch
+const launchStderrLines = 10
+
+// handleLaunchFailure deals with a new session whose claude failed to launch
+// (see claude.LaunchError): it repeats the error and the end of claude's
+// error output, then keeps the session for 'clotilde resume' when the user
+// asks to in a terminal, or rolls it back. Other errors are returned as is.
+func handleLaunchFailure(out io.Writer, clotildeRoot string, err error) error {
+	var launchErr *claude.LaunchError
+	if !errors.As(err, &launchErr) {
+		return err
+	}
+	name := launchErr.Session
+
+	_, _ = fmt.Fprintln(out, ui.Warning(fmt.Sprintf("Claude Code failed to launch for session '%s': %v", name, launchErr.Err)))
+	lines := strings.Split(strings.TrimRight(string(launchErr.Stderr), "\n"), "\n")
+	if len(launchErr.Stderr) == 0 {
+		lines = nil
+	}
+	for _, line := range lines[max(0, len(lines)-launchStderrLines):] {
+		_, _ = fmt.Fprintf(out, "  %s\n", line)
+	}
+
+	store := session.NewFileStore(clotildeRoot)
+	keep := false
+	if isatty.IsTerminal(os.Stdin.Fd()) && isatty.IsTerminal(os.Stdout.Fd()) {
+		confirm := ui.NewConfirm("Keep session?", fmt.Sprintf("Keep '%s' to retry with 'clotilde resume %s'? Otherwise it is removed.", name, name))
+		if keep, err = ui.RunConfirm(confirm); err != nil {
+			keep = false
+		}
+	}
+
+	if keep {
+		// Nothing to resume yet: the next resume starts it with its ID
+		if sess, err := store.Get(name); err == nil {
+			sess.Metadata.PendingLaunch = true
+			if err := store.Update(sess); err == nil {
+				_, _ = fmt.Fprintln(out, ui.Info(fmt.Sprintf("Kept session '%s'; retry with 'clotilde resume %s'", name, name)))
+				return launchErr
+			}
+		}
+	}
+
+	rollbackSession(clotildeRoot, store, name)
+	_, _ = fmt.Fprintln(out, ui.Info(fmt.Sprintf("Removed session '%s'", name)))
+	return launchErr
+}
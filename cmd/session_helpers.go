@@ -1,79 +1,61 @@
 package cmd
 
 import (
-	"fmt"
-	"os"
 	"slices"
+	"strings"
 
 	"github.com/fgrehm/clotilde/internal/claude"
 	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/ui"
 )
 
-// allTranscriptPaths returns paths for all transcripts associated with a session,
-// in chronological order: previous UUIDs first (oldest to newest), then the current one.
-// The current path comes from metadata when available; otherwise it is computed from the UUID.
-// Callers should skip paths that do not exist on disk (missing transcripts are not an error).
-func allTranscriptPaths(sess *session.Session, clotildeRoot, homeDir string) []string {
-	var paths []string
-
-	for _, prevID := range sess.Metadata.PreviousSessionIDs {
-		if prevID == "" {
-			continue
+// forkChildren returns the sessions forked from the named session, most
+// recently accessed first.
+func forkChildren(sessions []*session.Session, name string) []*session.Session {
+	var children []*session.Session
+	for _, s := range sessions {
+		if s.Metadata.IsForkedSession && s.Metadata.ParentSession == name {
+			children = append(children, s)
 		}
-		paths = append(paths, claude.TranscriptPath(homeDir, clotildeRoot, prevID))
-	}
-
-	current := sess.Metadata.TranscriptPath
-	if current == "" && sess.Metadata.SessionID != "" {
-		current = claude.TranscriptPath(homeDir, clotildeRoot, sess.Metadata.SessionID)
-	}
-	if current != "" {
-		paths = append(paths, current)
 	}
-
-	return paths
+	slices.SortFunc(children, func(a, b *session.Session) int {
+		return b.Metadata.LastAccessed.Compare(a.Metadata.LastAccessed)
+	})
+	return children
 }
 
-// resolveSessionName resolves the session name using a multi-level fallback strategy.
-// Priority 1: CLOTILDE_SESSION_NAME env var (always checked).
-// When fullFallback is true, also tries:
-// Priority 2: Read from CLAUDE_ENV_FILE (persisted by previous hook).
-// Priority 3: Reverse UUID lookup in session store.
-func resolveSessionName(hookData hookInput, store session.Store, fullFallback bool) (string, error) {
-	if name := os.Getenv("CLOTILDE_SESSION_NAME"); name != "" {
-		return name, nil
-	}
-
-	if !fullFallback {
-		return "", nil
-	}
-
-	if name := readLastEnvFileValue("CLOTILDE_SESSION"); name != "" {
-		return name, nil
+// transcriptHealth checks the integrity of sess's current transcript. A
+// session without a UUID is reported as missing a transcript.
+func transcriptHealth(sess *session.Session, clotildeRoot, homeDir string) (claude.TranscriptHealth, error) {
+	path := claude.CurrentTranscriptPath(homeDir, clotildeRoot, sess)
+	if path == "" {
+		return claude.TranscriptHealth{Missing: true}, nil
 	}
-
-	return findSessionByUUID(store, hookData.SessionID)
+	return claude.CheckTranscriptHealth(path)
 }
 
-// findSessionByUUID searches for a session with the given UUID.
-// Checks both current sessionId and previousSessionIds.
-func findSessionByUUID(store session.Store, uuid string) (string, error) {
-	sessions, err := store.List()
-	if err != nil {
-		return "", fmt.Errorf("failed to list sessions: %w", err)
-	}
-
-	for _, sess := range sessions {
-		if sess.Metadata.SessionID == uuid {
-			return sess.Name, nil
+// sessionHealthCell renders the list's health column: the transcript check,
+// or whether a session without a conversation is empty or not launched yet.
+func sessionHealthCell(sess *session.Session, clotildeRoot, homeDir string) string {
+	if claude.EmptySession(clotildeRoot, sess) {
+		if sess.Metadata.PendingLaunch {
+			return "not launched"
 		}
+		return ui.WarningStyle.Render("empty")
 	}
+	return formatHealth(transcriptHealth(sess, clotildeRoot, homeDir))
+}
 
-	for _, sess := range sessions {
-		if slices.Contains(sess.Metadata.PreviousSessionIDs, uuid) {
-			return sess.Name, nil
-		}
+// formatHealth renders a transcript health check as a short table cell.
+func formatHealth(health claude.TranscriptHealth, err error) string {
+	switch {
+	case err != nil:
+		return "? unreadable"
+	case health.Missing:
+		return "-"
 	}
-
-	return "", fmt.Errorf("no session found with UUID %s", uuid)
+	if problems := health.Problems(); len(problems) > 0 {
+		return "⚠ " + strings.Join(problems, ", ")
+	}
+	return "ok"
 }
@@ -1,71 +1,163 @@
 package cmd
 
 import (
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/fgrehm/clotilde/internal/claude"
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/events"
 	"github.com/fgrehm/clotilde/internal/session"
 )
 
-func TestAllTranscriptPaths(t *testing.T) {
-	tests := []struct {
-		name           string
-		sessionID      string
-		transcriptPath string
-		previousIDs    []string
-		wantCount      int
-	}{
-		{
-			name:      "empty fork — hook hasn't filled in UUID yet",
-			wantCount: 0,
-		},
-		{
-			name:      "session with UUID only — path computed from UUID",
-			sessionID: "abc-123",
-			wantCount: 1,
-		},
-		{
-			name:           "explicit TranscriptPath takes precedence over UUID",
-			transcriptPath: "/home/user/.claude/projects/foo/abc.jsonl",
-			wantCount:      1,
-		},
-		{
-			name:        "previous IDs included before current",
-			sessionID:   "current-uuid",
-			previousIDs: []string{"old-uuid-1", "old-uuid-2"},
-			wantCount:   3, // 2 previous + 1 current
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			sess := &session.Session{}
-			sess.Metadata.SessionID = tt.sessionID
-			sess.Metadata.TranscriptPath = tt.transcriptPath
-			sess.Metadata.PreviousSessionIDs = tt.previousIDs
-
-			paths := allTranscriptPaths(sess, "/tmp/.claude/clotilde", "/home/user")
-
-			if len(paths) != tt.wantCount {
-				t.Errorf("got %d paths %v, want %d", len(paths), paths, tt.wantCount)
-			}
-			// Verify no path is empty or has a bare ".jsonl" basename — either
-			// would indicate an empty UUID slipped through TranscriptPath().
-			for _, p := range paths {
-				if p == "" {
-					t.Errorf("paths contains an empty entry: %v", paths)
-				}
-				if filepath.Base(p) == ".jsonl" {
-					t.Errorf("paths contains a bare .jsonl entry (empty UUID): %v", paths)
-				}
-			}
-			// Explicit path should be preserved verbatim.
-			if tt.transcriptPath != "" && len(paths) > 0 {
-				last := paths[len(paths)-1]
-				if last != tt.transcriptPath {
-					t.Errorf("last path = %q, want %q", last, tt.transcriptPath)
-				}
-			}
-		})
+func TestDeletionConfirmation(t *testing.T) {
+	homeDir := t.TempDir()
+	t.Setenv("HOME", homeDir)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	clotildeRoot := filepath.Join(t.TempDir(), ".claude", "clotilde")
+	store := session.NewFileStore(clotildeRoot)
+
+	parent := session.NewSession("parent", "uuid-parent")
+	if err := store.Create(parent); err != nil {
+		t.Fatal(err)
+	}
+	small := session.NewSession("small", "uuid-small")
+	if err := store.Create(small); err != nil {
+		t.Fatal(err)
+	}
+	fork := session.NewSession("child", "uuid-child")
+	fork.Metadata.IsForkedSession = true
+	fork.Metadata.ParentSession = "parent"
+	if err := store.Create(fork); err != nil {
+		t.Fatal(err)
+	}
+
+	if level, reasons := deletionConfirmation(clotildeRoot, small, store); level != confirmAsk || len(reasons) != 0 {
+		t.Errorf("expected a plain confirmation for a small leaf session, got %v %v", level, reasons)
+	}
+
+	level, reasons := deletionConfirmation(clotildeRoot, parent, store)
+	if level != confirmTyped || len(reasons) != 1 || reasons[0] != "parent of 1 fork(s): child" {
+		t.Errorf("expected fork parent reason, got %v %v", level, reasons)
+	}
+
+	// Grow the transcript past the default threshold
+	transcript := claude.TranscriptPath(homeDir, clotildeRoot, "uuid-small")
+	if err := os.MkdirAll(filepath.Dir(transcript), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(transcript, make([]byte, config.DefaultTypedConfirmAboveKB*1024+1), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	level, reasons = deletionConfirmation(clotildeRoot, small, store)
+	if level != confirmTyped || len(reasons) != 1 || !strings.HasPrefix(reasons[0], "transcripts total") {
+		t.Errorf("expected transcript size reason, got %v %v", level, reasons)
+	}
+
+	// Small sessions are skipped, and the size threshold follows the config
+	if err := os.WriteFile(config.GetConfigPath(clotildeRoot), []byte(`{"confirm": {"skipBelowTurns": 5, "typedAboveKB": 100000}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if level, reasons := deletionConfirmation(clotildeRoot, small, store); level != confirmSkip || len(reasons) != 0 {
+		t.Errorf("expected no confirmation for a session without turns, got %v %v", level, reasons)
+	}
+}
+
+func TestBuildDeletionDetailsCountsClaudeFiles(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	clotildeRoot := filepath.Join(t.TempDir(), ".claude", "clotilde")
+	store := session.NewFileStore(clotildeRoot)
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	sess := session.NewSession("cleared", "uuid-new")
+	sess.Metadata.TranscriptPath = write("uuid-new.jsonl", "{}\n")
+	sess.Metadata.PreviousSessions = []session.PreviousSession{
+		{SessionID: "uuid-old", TranscriptPath: write("uuid-old.jsonl", "{}\n")},
+	}
+	write("agent-1.jsonl", `{"sessionId":"uuid-old"}`+"\n")
+	write("agent-2.jsonl", `{"sessionId":"uuid-new"}`+"\n")
+	write("agent-3.jsonl", `{"sessionId":"uuid-other"}`+"\n")
+
+	details := strings.Join(buildDeletionDetails(clotildeRoot, sess, store), "\n")
+	for _, want := range []string{"2 Claude transcript(s) (6 B)", "2 agent log(s) (50 B)"} {
+		if !strings.Contains(details, want) {
+			t.Errorf("expected %q in details:\n%s", want, details)
+		}
+	}
+}
+
+func TestCollectActivity(t *testing.T) {
+	dir := t.TempDir()
+	writeTranscript := func(name string, lines ...string) string {
+		path := filepath.Join(dir, name+".jsonl")
+		if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	busy := session.NewSession("busy", "uuid-busy")
+	busy.Metadata.TranscriptPath = writeTranscript("busy",
+		`{"type":"user","timestamp":"2026-01-05T10:00:00Z"}`,
+		`{"type":"assistant","timestamp":"2026-01-05T10:04:00Z","message":{"model":"claude-opus-4-20250514"}}`,
+	)
+	quiet := session.NewSession("quiet", "uuid-quiet")
+	quiet.Metadata.TranscriptPath = writeTranscript("quiet",
+		`{"type":"user","timestamp":"2026-01-05T10:00:00Z"}`,
+		`{"type":"assistant","timestamp":"2026-01-05T10:01:00Z","message":{"model":"claude-sonnet-4-5-20250929"}}`,
+	)
+	idle := session.NewSession("idle", "uuid-idle")
+	idle.Metadata.TranscriptPath = filepath.Join(dir, "missing.jsonl")
+
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	activity := collectActivity(dir, dir, []*session.Session{quiet, idle, busy}, since)
+
+	if activity.ActiveTime != 5*time.Minute {
+		t.Errorf("Expected 5m total, got %s", activity.ActiveTime)
+	}
+	if len(activity.Busiest) != 2 || activity.Busiest[0].Name != "busy" || activity.Busiest[1].Name != "quiet" {
+		t.Errorf("Expected busy then quiet (idle sessions omitted), got %+v", activity.Busiest)
+	}
+	if activity.Models["opus"] != 1 || activity.Models["sonnet"] != 1 {
+		t.Errorf("Unexpected model breakdown: %v", activity.Models)
+	}
+}
+
+func TestSessionHistory(t *testing.T) {
+	created := time.Date(2026, 1, 5, 10, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return created.Add(time.Duration(minutes) * time.Minute) }
+
+	sess := session.NewSession("work", "uuid-2")
+	sess.Metadata.Created = created
+	sess.Metadata.PreviousSessions = []session.PreviousSession{
+		{SessionID: "uuid-1", Reason: session.RotationCompact, SupersededAt: at(30)},
+	}
+	log := []events.Event{
+		{Time: at(-60), Type: events.SessionResumed, Session: "work"}, // An earlier session of the same name
+		{Time: at(10), Type: events.SessionResumed, Session: "work"},
+		{Time: at(11), Type: events.SessionStatus, Session: "work", From: session.StatusIdle, Status: session.StatusActive},
+		{Time: at(20), Type: events.SessionForked, Session: "gone", Parent: "work"},
+		{Time: at(30), Type: events.HookFired, Session: "work", Source: session.RotationCompact},
+		{Time: at(40), Type: events.SessionResumed, Session: "work"},
+		{Time: at(50), Type: events.SessionStatus, Session: "work", From: session.StatusIdle, Status: session.StatusArchived},
+	}
+
+	var got []string
+	for _, entry := range sessionHistory(sess, []*session.Session{sess}, log) {
+		got = append(got, entry.Text)
+	}
+	want := []string{"created", "resumed", "forked to gone (deleted since)", "compacted (/compact)", "resumed", "status idle → archived"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("expected %q, got %q", want, got)
 	}
 }
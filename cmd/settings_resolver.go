@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/ui"
+)
+
+// settingSource identifies the precedence layer an effective setting came from.
+type settingSource string
+
+const (
+	sourceFlag    settingSource = "flag"
+	sourceProfile settingSource = "profile"
+	sourceSession settingSource = "session settings"
+	sourceDefault settingSource = "project default"
+	sourceUnset   settingSource = "unset (Claude Code default)"
+)
+
+// registerExplainFlag adds the --explain flag to commands that launch Claude.
+func registerExplainFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("explain", false, "Print where each effective setting (model, effort, permission mode) came from")
+}
+
+// settingsLayer holds the values contributed by a single precedence layer.
+// Empty fields mean the layer has no opinion about that setting.
+type settingsLayer struct {
+	Source         settingSource
+	Model          string
+	EffortLevel    string
+	PermissionMode string
+}
+
+// resolvedValue is an effective setting value along with where it came from.
+type resolvedValue struct {
+	Value  string
+	Source settingSource
+}
+
+// resolvedSettings holds the effective model, effort, and permission mode.
+type resolvedSettings struct {
+	Model          resolvedValue
+	EffortLevel    resolvedValue
+	PermissionMode resolvedValue
+}
+
+// resolveSettings picks, for each setting, the value from the first layer that sets it.
+// Layers must be ordered from highest to lowest precedence:
+// flag > profile > session settings > project default.
+func resolveSettings(layers ...settingsLayer) resolvedSettings {
+	pick := func(get func(settingsLayer) string) resolvedValue {
+		for _, layer := range layers {
+			if v := get(layer); v != "" {
+				return resolvedValue{Value: v, Source: layer.Source}
+			}
+		}
+		return resolvedValue{Source: sourceUnset}
+	}
+
+	return resolvedSettings{
+		Model:          pick(func(l settingsLayer) string { return normalizeModel(l.Model) }),
+		EffortLevel:    pick(func(l settingsLayer) string { return l.EffortLevel }),
+		PermissionMode: pick(func(l settingsLayer) string { return l.PermissionMode }),
+	}
+}
+
+// flagSettingsLayer builds the flag layer from --model, --effort, --fast and the
+// permission mode shortcuts, validating conflicts between them.
+func flagSettingsLayer(cmd *cobra.Command) (settingsLayer, error) {
+	layer := settingsLayer{Source: sourceFlag}
+
+	permMode, err := resolvePermissionMode(cmd)
+	if err != nil {
+		return layer, err
+	}
+	layer.PermissionMode = permMode
+
+	fastEnabled, err := resolveFastMode(cmd)
+	if err != nil {
+		return layer, err
+	}
+	if fastEnabled {
+		layer.Model = "haiku"
+		layer.EffortLevel = "low"
+		return layer, nil
+	}
+
+	if cmd.Flags().Lookup("model") != nil {
+		layer.Model, _ = cmd.Flags().GetString("model")
+	}
+	layer.EffortLevel, _ = cmd.Flags().GetString("effort")
+	return layer, nil
+}
+
+// profileSettingsLayer builds the layer contributed by a named profile.
+// A profile's permissions block replaces its permissionMode, matching how
+// profiles are applied to settings.json.
+func profileSettingsLayer(profile config.Profile) settingsLayer {
+	permMode := profile.PermissionMode
+	if profile.Permissions != nil {
+		permMode = profile.Permissions.DefaultMode
+	}
+	return settingsLayer{
+		Source:         sourceProfile,
+		Model:          profile.Model,
+		PermissionMode: permMode,
+	}
+}
+
+// sessionSettingsLayer builds the layer contributed by a session's settings.json.
+// A nil settings value yields an empty layer.
+func sessionSettingsLayer(settings *session.Settings) settingsLayer {
+	layer := settingsLayer{Source: sourceSession}
+	if settings == nil {
+		return layer
+	}
+	layer.Model = settings.Model
+	layer.EffortLevel = settings.EffortLevel
+	layer.PermissionMode = settings.Permissions.DefaultMode
+	return layer
+}
+
+// projectDefaultsLayer builds the lowest precedence layer from the "defaults"
+// block of the global and project configs.
+func projectDefaultsLayer(clotildeRoot string) (settingsLayer, error) {
+	defaults, err := config.MergedDefaults(clotildeRoot)
+	if err != nil {
+		return settingsLayer{}, fmt.Errorf("failed to load config: %w", err)
+	}
+	layer := profileSettingsLayer(defaults)
+	layer.Source = sourceDefault
+	return layer, nil
+}
+
+// launchArgs returns the claude CLI flags for settings that are not already
+// stored in the session's settings.json (i.e. those coming from flags or
+// project defaults). Used by commands that reuse an existing settings file.
+func (r resolvedSettings) launchArgs() []string {
+	var args []string
+	passed := func(v resolvedValue) bool {
+		return v.Value != "" && (v.Source == sourceFlag || v.Source == sourceDefault)
+	}
+	if passed(r.PermissionMode) {
+		args = append(args, "--permission-mode", r.PermissionMode.Value)
+	}
+	if passed(r.Model) {
+		args = append(args, "--model", r.Model.Value)
+	}
+	if passed(r.EffortLevel) {
+		args = append(args, "--effort", r.EffortLevel.Value)
+	}
+	return args
+}
+
+// printSettingsExplanation writes each effective setting and its source.
+func printSettingsExplanation(w io.Writer, r resolvedSettings) {
+	_, _ = fmt.Fprintln(w, "Effective settings (flag > profile > session settings > project default):")
+	rows := []struct {
+		label string
+		value resolvedValue
+	}{
+		{"model", r.Model},
+		{"effort", r.EffortLevel},
+		{"permission mode", r.PermissionMode},
+	}
+	for _, row := range rows {
+		value := row.value.Value
+		if value == "" {
+			value = "-"
+		}
+		_, _ = fmt.Fprintf(w, "  %-16s %-20s (%s)\n", row.label+":", value, row.value.Source)
+	}
+	_, _ = fmt.Fprintln(w)
+}
+
+// printSessionOverrides reports settings where a flag overrides a different
+// value pinned in the session's settings.json, so one-off overrides are visible.
+func printSessionOverrides(w io.Writer, r resolvedSettings, pinned settingsLayer) {
+	check := func(label string, effective resolvedValue, pinnedValue string) {
+		pinnedValue = normalizeModel(pinnedValue)
+		if effective.Source != sourceFlag || pinnedValue == "" || pinnedValue == effective.Value {
+			return
+		}
+		_, _ = fmt.Fprintln(w, ui.Info(fmt.Sprintf("Overriding session %s '%s' with '%s' for this run", label, pinnedValue, effective.Value)))
+	}
+	check("model", r.Model, pinned.Model)
+	check("effort", r.EffortLevel, pinned.EffortLevel)
+	check("permission mode", r.PermissionMode, pinned.PermissionMode)
+}
+
+// resolveSessionSettings resolves the effective settings for launching an
+// existing session (flag > session settings > project default). Also returns
+// the session settings layer so callers can report overridden values.
+func resolveSessionSettings(clotildeRoot string, store session.Store, name string, flags settingsLayer) (resolvedSettings, settingsLayer, error) {
+	settings, err := store.LoadSettings(name)
+	if err != nil {
+		return resolvedSettings{}, settingsLayer{}, fmt.Errorf("failed to load session settings: %w", err)
+	}
+	pinned := sessionSettingsLayer(settings)
+
+	defaults, err := projectDefaultsLayer(clotildeRoot)
+	if err != nil {
+		return resolvedSettings{}, settingsLayer{}, err
+	}
+
+	return resolveSettings(flags, pinned, defaults), pinned, nil
+}
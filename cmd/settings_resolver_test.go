@@ -0,0 +1,147 @@
+package cmd_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/claude"
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/testutil"
+)
+
+var _ = Describe("Settings precedence", func() {
+	var (
+		tempDir        string
+		clotildeRoot   string
+		originalWd     string
+		claudeArgsFile string
+		fakeClaudeDir  string
+		store          session.Store
+	)
+
+	BeforeEach(func() {
+		tempDir = GinkgoT().TempDir()
+
+		var err error
+		originalWd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Chdir(tempDir)).To(Succeed())
+
+		// Isolate from the user's global config
+		GinkgoT().Setenv("XDG_CONFIG_HOME", filepath.Join(tempDir, "xdg"))
+
+		fakeClaudeDir = filepath.Join(tempDir, "bin")
+		Expect(os.Mkdir(fakeClaudeDir, 0o755)).To(Succeed())
+		_, claudeArgsFile, err = testutil.CreateFakeClaude(fakeClaudeDir)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(config.EnsureClotildeStructure(tempDir)).To(Succeed())
+		clotildeRoot = filepath.Join(tempDir, config.ClotildeDir)
+		store = session.NewFileStore(clotildeRoot)
+
+		claude.SessionUsedFunc = func(_ string, _ *session.Session) bool { return true }
+	})
+
+	AfterEach(func() {
+		_ = os.Chdir(originalWd)
+	})
+
+	writeProjectConfig := func(cfg *config.Config) {
+		data, err := json.Marshal(cfg)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.WriteFile(config.GetConfigPath(clotildeRoot), data, 0o644)).To(Succeed())
+	}
+
+	run := func(args ...string) (string, error) {
+		return runClotilde(append([]string{"--claude-bin", filepath.Join(fakeClaudeDir, "claude")}, args...)...)
+	}
+
+	It("reports when --fast on resume overrides a pinned model", func() {
+		Expect(store.Create(session.NewSession("pinned", "uuid-pinned"))).To(Succeed())
+		Expect(store.SaveSettings("pinned", &session.Settings{Model: "opus[1m]"})).To(Succeed())
+
+		out, err := run("resume", "pinned", "--fast", "--explain")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(ContainSubstring("Overriding session model 'opus[1m]' with 'haiku'"))
+		Expect(out).To(MatchRegexp(`model:\s+haiku\s+\(flag\)`))
+		Expect(out).To(MatchRegexp(`effort:\s+low\s+\(flag\)`))
+
+		args, err := testutil.ReadClaudeArgs(claudeArgsFile)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(args).To(ContainSubstring("--model haiku"))
+	})
+
+	It("does not pass session-pinned values as CLI args on resume", func() {
+		Expect(store.Create(session.NewSession("pinned", "uuid-pinned"))).To(Succeed())
+		Expect(store.SaveSettings("pinned", &session.Settings{Model: "sonnet"})).To(Succeed())
+
+		out, err := run("resume", "pinned", "--explain")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(MatchRegexp(`model:\s+sonnet\s+\(session settings\)`))
+
+		args, err := testutil.ReadClaudeArgs(claudeArgsFile)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(args).NotTo(ContainSubstring("--model"))
+	})
+
+	It("applies project defaults on resume when the session does not pin a value", func() {
+		writeProjectConfig(&config.Config{Defaults: &config.Profile{Model: "sonnet"}})
+		Expect(store.Create(session.NewSession("unpinned", "uuid-unpinned"))).To(Succeed())
+
+		out, err := run("resume", "unpinned", "--explain")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(MatchRegexp(`model:\s+sonnet\s+\(project default\)`))
+
+		args, err := testutil.ReadClaudeArgs(claudeArgsFile)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(args).To(ContainSubstring("--model sonnet"))
+	})
+
+	It("prefers profile values over project defaults on start", func() {
+		writeProjectConfig(&config.Config{
+			Profiles: map[string]config.Profile{"quick": {Model: "haiku"}},
+			Defaults: &config.Profile{Model: "sonnet", PermissionMode: "plan"},
+		})
+
+		out, err := run("start", "with-profile", "--profile", "quick", "--explain")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(MatchRegexp(`model:\s+haiku\s+\(profile\)`))
+		Expect(out).To(MatchRegexp(`permission mode:\s+plan\s+\(project default\)`))
+
+		settings, err := store.LoadSettings("with-profile")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(settings.Model).To(Equal("haiku"))
+		Expect(settings.Permissions.DefaultMode).To(Equal("plan"))
+	})
+
+	It("prefers flags over profile values on start", func() {
+		writeProjectConfig(&config.Config{
+			Profiles: map[string]config.Profile{"quick": {Model: "haiku"}},
+		})
+
+		out, err := run("start", "flag-wins", "--profile", "quick", "--model", "sonnet", "--explain")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(MatchRegexp(`model:\s+sonnet\s+\(flag\)`))
+		Expect(out).To(MatchRegexp(`effort:\s+-\s+\(unset`))
+	})
+
+	It("explains inherited parent settings on fork", func() {
+		Expect(store.Create(session.NewSession("parent", "uuid-parent"))).To(Succeed())
+		Expect(store.SaveSettings("parent", &session.Settings{Model: "sonnet", EffortLevel: "high"})).To(Succeed())
+
+		out, err := run("fork", "parent", "child", "--effort", "low", "--explain")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(MatchRegexp(`model:\s+sonnet\s+\(session settings\)`))
+		Expect(out).To(MatchRegexp(`effort:\s+low\s+\(flag\)`))
+
+		settings, err := store.LoadSettings("child")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(settings.Model).To(Equal("sonnet"))
+		Expect(settings.EffortLevel).To(Equal("low"))
+	})
+})
@@ -3,7 +3,6 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
-	"os"
 	"path/filepath"
 
 	"github.com/spf13/cobra"
@@ -20,7 +19,12 @@ func newSetupCmd() *cobra.Command {
 		Long: `Register SessionStart hooks in ~/.claude/settings.json so clotilde
 works automatically in all projects. Run this once after installing clotilde.
 
-Use --local to install hooks in ~/.claude/settings.local.json instead.`,
+Use --local to install hooks in ~/.claude/settings.local.json instead.
+Honors CLAUDE_CONFIG_DIR when Claude Code's config directory is relocated.
+
+Hooks run this clotilde binary by absolute path, so they work even when
+Claude Code's shell doesn't have clotilde on its PATH. Use --hook-path path
+to write a bare "clotilde" command resolved through PATH instead.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			local, _ := cmd.Flags().GetBool("local")
 
@@ -28,9 +32,10 @@ Use --local to install hooks in ~/.claude/settings.local.json instead.`,
 				return err
 			}
 
-			clotildeBinary, err := os.Executable()
+			hookPath, _ := cmd.Flags().GetString("hook-path")
+			clotildeBinary, err := resolveHookBinary(hookPath)
 			if err != nil {
-				return fmt.Errorf("failed to determine clotilde binary path: %w", err)
+				return err
 			}
 
 			homeDir, err := util.HomeDir()
@@ -43,21 +48,21 @@ Use --local to install hooks in ~/.claude/settings.local.json instead.`,
 				settingsFile = "settings.local.json"
 			}
 
-			claudeDir := filepath.Join(homeDir, ".claude")
+			// ~/.claude unless relocated with CLAUDE_CONFIG_DIR
+			claudeDir := claude.ConfigDir(homeDir)
 			settingsPath := filepath.Join(claudeDir, settingsFile)
 
-			// Ensure ~/.claude directory exists
 			if err := util.EnsureDir(claudeDir); err != nil {
-				return fmt.Errorf("failed to create ~/.claude directory: %w", err)
+				return fmt.Errorf("failed to create %s: %w", claudeDir, err)
 			}
 
-			hooks, err := mergeHooksIntoSettings(settingsPath, clotildeBinary)
+			hooks, _, err := mergeHooksIntoSettings(settingsPath, clotildeBinary, true)
 			if err != nil {
 				return err
 			}
 
 			hooksJSON, _ := json.MarshalIndent(hooks, "  ", "  ")
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Added hooks to ~/%s:\n  %s\n", filepath.Join(".claude", settingsFile), string(hooksJSON))
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Added hooks to %s:\n  %s\n", tildePath(homeDir, settingsPath), string(hooksJSON))
 			_, _ = fmt.Fprintln(cmd.OutOrStdout())
 			_, _ = fmt.Fprintln(cmd.OutOrStdout(), ui.Success("Clotilde setup complete!"))
 			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "  Sessions will be created automatically when you run:")
@@ -68,6 +73,7 @@ Use --local to install hooks in ~/.claude/settings.local.json instead.`,
 	}
 
 	cmd.Flags().Bool("local", false, "Install hooks in ~/.claude/settings.local.json instead of settings.json")
+	registerHookPathFlag(cmd)
 
 	return cmd
 }
@@ -216,4 +216,50 @@ var _ = Describe("Setup Command", func() {
 		Expect(hooks).NotTo(HaveKey("PreToolUse"))
 		Expect(hooks).NotTo(HaveKey("PostToolUse"))
 	})
+
+	Describe("--hook-path", func() {
+		sessionStartCommand := func() string {
+			content, err := os.ReadFile(filepath.Join(fakeHome, ".claude", "settings.json"))
+			Expect(err).NotTo(HaveOccurred())
+			var settings map[string]any
+			Expect(json.Unmarshal(content, &settings)).To(Succeed())
+			matcher := settings["hooks"].(map[string]any)["SessionStart"].([]any)[0].(map[string]any)
+			return matcher["hooks"].([]any)[0].(map[string]any)["command"].(string)
+		}
+
+		It("embeds the running binary's absolute path by default", func() {
+			rootCmd := cmd.NewRootCmd()
+			rootCmd.SetArgs([]string{"setup"})
+			Expect(rootCmd.Execute()).To(Succeed())
+
+			self, err := os.Executable()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sessionStartCommand()).To(Equal(self + " hook sessionstart"))
+		})
+
+		It("writes a bare command with path when clotilde is on PATH", func() {
+			fakeClotilde := filepath.Join(tempDir, "bin", "clotilde")
+			Expect(os.WriteFile(fakeClotilde, []byte("#!/bin/sh\n"), 0o755)).To(Succeed())
+
+			rootCmd := cmd.NewRootCmd()
+			rootCmd.SetArgs([]string{"setup", "--hook-path", "path"})
+			Expect(rootCmd.Execute()).To(Succeed())
+
+			Expect(sessionStartCommand()).To(Equal("clotilde hook sessionstart"))
+		})
+
+		It("refuses path when clotilde is not on PATH", func() {
+			_ = os.Setenv("PATH", filepath.Join(tempDir, "bin"))
+
+			rootCmd := cmd.NewRootCmd()
+			rootCmd.SetArgs([]string{"setup", "--hook-path", "path"})
+			Expect(rootCmd.Execute()).To(MatchError(ContainSubstring("clotilde is not on PATH")))
+		})
+
+		It("rejects unknown modes", func() {
+			rootCmd := cmd.NewRootCmd()
+			rootCmd.SetArgs([]string{"setup", "--hook-path", "relative"})
+			Expect(rootCmd.Execute()).To(MatchError(ContainSubstring(`invalid --hook-path "relative"`)))
+		})
+	})
 })
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fgrehm/clotilde/internal/app"
+)
+
+// shellWrappers are the 'clotilde' functions printed by shell-init, by shell.
+// The binary writes the directory to change to into the file named by
+// app.ShellCDFileEnv; a child process can't change its parent's directory.
+var shellWrappers = map[string]string{
+	"bash": posixShellWrapper,
+	"zsh":  posixShellWrapper,
+	"fish": `function clotilde --wraps clotilde --description 'clotilde, changing to the session directory after resuming'
+    set -l cd_file (mktemp -t clotilde-cd.XXXXXX); or begin
+        command clotilde $argv
+        return
+    end
+    set -lx __CD_FILE_ENV__ $cd_file
+    command clotilde $argv
+    set -l ret $status
+    if test -s $cd_file
+        set -l dir (cat $cd_file)
+        if test -d "$dir"; and test "$dir" != "$PWD"
+            cd $dir
+        end
+    end
+    rm -f $cd_file
+    return $ret
+end
+`,
+}
+
+const posixShellWrapper = `clotilde() {
+  local cd_file dir ret
+  cd_file="$(mktemp -t clotilde-cd.XXXXXX)" || { command clotilde "$@"; return; }
+  __CD_FILE_ENV__="$cd_file" command clotilde "$@"
+  ret=$?
+  if [ -s "$cd_file" ]; then
+    dir="$(cat "$cd_file")"
+    if [ -d "$dir" ] && [ "$dir" != "$PWD" ]; then
+      cd "$dir" || true
+    fi
+  fi
+  rm -f "$cd_file"
+  return $ret
+}
+`
+
+func newShellInitCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "shell-init bash|zsh|fish",
+		Short: "Print a shell function that follows resumed sessions to their directory",
+		Long: `Print a 'clotilde' shell function that wraps the binary. After Claude Code
+exits from a resumed session (resume, switch, or the dashboard), the wrapper
+changes the shell's directory to the session's directory, which the clotilde
+process can't do on its own. A session's directory is its project root, so
+resuming from a subdirectory or through 'clotilde projects' lands you there.
+
+Add it to your shell's startup file:
+
+  eval "$(clotilde shell-init bash)"    # ~/.bashrc
+  eval "$(clotilde shell-init zsh)"     # ~/.zshrc
+  clotilde shell-init fish | source     # ~/.config/fish/config.fish`,
+		Annotations: readOnly(),
+		Args:        cobra.ExactArgs(1),
+		ValidArgs:   []string{"bash", "zsh", "fish"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			wrapper, ok := shellWrappers[args[0]]
+			if !ok {
+				return fmt.Errorf("unsupported shell '%s' (use bash, zsh, or fish)", args[0])
+			}
+			_, _ = fmt.Fprint(cmd.OutOrStdout(), strings.ReplaceAll(wrapper, "__CD_FILE_ENV__", app.ShellCDFileEnv))
+			return nil
+		},
+	}
+}
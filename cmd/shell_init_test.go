@@ -0,0 +1,42 @@
+package cmd_test
+
+import (
+	"os/exec"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/app"
+)
+
+var _ = Describe("Shell-init Command", func() {
+	shellInit := func(shell string) (string, error) {
+		return runClotilde("shell-init", shell)
+	}
+
+	It("prints a bash function passing the cd file to the binary", func() {
+		out, err := shellInit("bash")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(HavePrefix("clotilde() {"))
+		Expect(out).To(ContainSubstring(app.ShellCDFileEnv + `="$cd_file" command clotilde "$@"`))
+
+		if bash, err := exec.LookPath("bash"); err == nil {
+			check := exec.Command(bash, "-n")
+			check.Stdin = strings.NewReader(out)
+			Expect(check.Run()).To(Succeed())
+		}
+	})
+
+	It("prints a fish function", func() {
+		out, err := shellInit("fish")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(HavePrefix("function clotilde --wraps clotilde"))
+		Expect(out).To(ContainSubstring("set -lx " + app.ShellCDFileEnv + " $cd_file"))
+	})
+
+	It("rejects other shells", func() {
+		_, err := shellInit("powershell")
+		Expect(err).To(MatchError(ContainSubstring("unsupported shell 'powershell'")))
+	})
+})
@@ -102,10 +102,10 @@ func resolvePermissionMode(cmd *cobra.Command) (string, error) {
 // (if the command has it). Returns true if --fast was set.
 //
 // When true, the caller should:
-//   - For session-creating commands: set model to "haiku" via cmd.Flags().Set()
-//     and append ["--effort", "low"] to additionalArgs
-//   - For non-creating commands: append ["--model", "haiku", "--effort", "low"]
-//     to additionalArgs
+//   - For session-creating commands: set model to "haiku" and effort to "low"
+//     via cmd.Flags().Set() so they are persisted to settings.json
+//   - For other commands: use flagSettingsLayer, which maps --fast to the
+//     flag precedence layer
 func resolveFastMode(cmd *cobra.Command) (bool, error) {
 	fast, _ := cmd.Flags().GetBool("fast")
 	if !fast {
@@ -129,14 +129,3 @@ func normalizeModel(model string) string {
 	}
 	return model
 }
-
-// collectEffortFlag appends --effort to additionalArgs if the flag is set.
-// Called after resolveFastMode; when --fast is enabled, the caller appends
-// ["--effort", "low"] directly instead of calling this function.
-func collectEffortFlag(cmd *cobra.Command, additionalArgs []string) []string {
-	effort, _ := cmd.Flags().GetString("effort")
-	if effort != "" {
-		additionalArgs = append(additionalArgs, "--effort", effort)
-	}
-	return additionalArgs
-}
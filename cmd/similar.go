@@ -0,0 +1,237 @@
+package cmd
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fgrehm/clotilde/internal/crypt"
+	"github.com/fgrehm/clotilde/internal/errs"
+	"github.com/fgrehm/clotilde/internal/outputstyle"
+	"github.com/fgrehm/clotilde/internal/session"
+)
+
+func newSimilarCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "similar <name>",
+		Short: "Find sessions similar to a session, to consolidate them",
+		Long: `Compare a session's settings, custom output style (prompt) and context with
+every other session's and list the closest, as candidates to merge or delete.
+
+Each part scores the share of words (or settings) both sessions have, out of
+all the words either has, and the parts set in either session are averaged.
+Parts neither session sets are left out, and sessions with nothing set are
+never listed.`,
+		Example: `  clotilde similar auth-bug
+  clotilde similar auth-bug --min 80`,
+		Annotations:       readOnly(),
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: sessionNameCompletion,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			minScore, _ := cmd.Flags().GetInt("min")
+			limit, _ := cmd.Flags().GetInt("limit")
+			if minScore < 0 || minScore > 100 {
+				return fmt.Errorf("--min must be between 0 and 100")
+			}
+
+			clotildeRoot, err := findClotildeRoot()
+			if err != nil {
+				if projectRootOverride != "" {
+					return err
+				}
+				return errNoSessions()
+			}
+			store := session.NewFileStore(clotildeRoot)
+
+			sess, err := store.Get(name)
+			if err != nil {
+				return errs.NotFound("session '%s' not found", name)
+			}
+			sessions, err := store.List()
+			if err != nil {
+				return fmt.Errorf("failed to list sessions: %w", err)
+			}
+
+			target := loadSimilarityProfile(clotildeRoot, store, sess)
+			var matches []similarSession
+			for _, other := range sessions {
+				if other.Name == sess.Name {
+					continue
+				}
+				match := compareSessions(target, loadSimilarityProfile(clotildeRoot, store, other))
+				match.Name = other.Name
+				if len(match.Parts) > 0 && match.Score*100 >= float64(minScore) {
+					matches = append(matches, match)
+				}
+			}
+			slices.SortStableFunc(matches, func(a, b similarSession) int {
+				if c := cmp.Compare(b.Score, a.Score); c != 0 {
+					return c
+				}
+				return strings.Compare(a.Name, b.Name)
+			})
+			if limit > 0 && len(matches) > limit {
+				matches = matches[:limit]
+			}
+
+			writeSimilarSessions(cmd.OutOrStdout(), name, minScore, matches)
+			return nil
+		},
+	}
+
+	cmd.Flags().Int("min", 50, "Only list sessions at least this similar (percent)")
+	cmd.Flags().Int("limit", 10, "List at most this many sessions (0 for all)")
+	return cmd
+}
+
+// similarityProfile holds the words and settings of a session that
+// 'clotilde similar' compares.
+type similarityProfile struct {
+	Settings []string // "key=value" pairs
+	Prompt   []string // Words of the custom output style
+	Context  []string // Words of the context
+}
+
+// similarPart is one compared part of two sessions and its score (0 to 1).
+type similarPart struct {
+	Name  string
+	Score float64
+}
+
+// similarSession is a session compared with the one given to 'similar'.
+type similarSession struct {
+	Name  string
+	Score float64 // Average of Parts
+	Parts []similarPart
+}
+
+// loadSimilarityProfile reads what sess is compared on. Unreadable files
+// count as unset.
+func loadSimilarityProfile(clotildeRoot string, store session.Store, sess *session.Session) similarityProfile {
+	profile := similarityProfile{Context: words(sess.Metadata.Context)}
+
+	if settings, err := store.LoadSettings(sess.Name); err == nil && settings != nil {
+		profile.Settings = settingsPairs(settings)
+	}
+	if sess.Metadata.OwnsOutputStyle() {
+		// The frontmatter names the session, so only the instructions count
+		if content, err := crypt.ReadFile(outputstyle.GetCustomStylePath(clotildeRoot, sess.Name)); err == nil {
+			if style, err := outputstyle.ParseStyleFile(string(content)); err == nil {
+				profile.Prompt = words(style.Body)
+			}
+		}
+	}
+	return profile
+}
+
+// settingsPairs flattens settings into comparable "key=value" pairs. A
+// session's custom output style is named after the session, so it's
+// compared by content (the prompt) instead.
+func settingsPairs(settings *session.Settings) []string {
+	var pairs []string
+	add := func(key string, values ...string) {
+		for _, value := range values {
+			if value != "" {
+				pairs = append(pairs, key+"="+value)
+			}
+		}
+	}
+	add("model", settings.Model)
+	add("effort", settings.EffortLevel)
+	if !strings.HasPrefix(settings.OutputStyle, "clotilde/") {
+		add("outputStyle", settings.OutputStyle)
+	}
+	add("allow", settings.Permissions.Allow...)
+	add("ask", settings.Permissions.Ask...)
+	add("deny", settings.Permissions.Deny...)
+	add("additionalDirectory", settings.Permissions.AdditionalDirectories...)
+	add("defaultMode", settings.Permissions.DefaultMode)
+	return pairs
+}
+
+// words returns the distinct lowercase words of text.
+func words(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	slices.Sort(fields)
+	return slices.Compact(fields)
+}
+
+// compareSessions scores the parts set in either profile.
+func compareSessions(a, b similarityProfile) similarSession {
+	var match similarSession
+	for _, part := range []struct {
+		name string
+		a, b []string
+	}{
+		{"settings", a.Settings, b.Settings},
+		{"prompt", a.Prompt, b.Prompt},
+		{"context", a.Context, b.Context},
+	} {
+		if score, ok := jaccard(part.a, part.b); ok {
+			match.Parts = append(match.Parts, similarPart{Name: part.name, Score: score})
+			match.Score += score
+		}
+	}
+	if len(match.Parts) > 0 {
+		match.Score /= float64(len(match.Parts))
+	}
+	return match
+}
+
+// jaccard returns the share of items in both sets out of those in either,
+// or false when both are empty.
+func jaccard(a, b []string) (float64, bool) {
+	inA := make(map[string]bool, len(a))
+	for _, item := range a {
+		inA[item] = true
+	}
+	inB := make(map[string]bool, len(b))
+	shared := 0
+	for _, item := range b {
+		if !inB[item] && inA[item] {
+			shared++
+		}
+		inB[item] = true
+	}
+	union := len(inA) + len(inB) - shared
+	if union == 0 {
+		return 0, false
+	}
+	return float64(shared) / float64(union), true
+}
+
+// writeSimilarSessions prints the matches, most similar first.
+func writeSimilarSessions(w io.Writer, name string, minScore int, matches []similarSession) {
+	if len(matches) == 0 {
+		_, _ = fmt.Fprintf(w, "No sessions at least %d%% similar to '%s'.\n", minScore, name)
+		return
+	}
+
+	width := 0
+	for _, match := range matches {
+		width = max(width, len(match.Name))
+	}
+	_, _ = fmt.Fprintf(w, "Sessions similar to '%s':\n", name)
+	for _, match := range matches {
+		parts := make([]string, len(match.Parts))
+		for i, part := range match.Parts {
+			parts[i] = fmt.Sprintf("%s %d%%", part.Name, percent(part.Score))
+		}
+		_, _ = fmt.Fprintf(w, "  %3d%%  %-*s  %s\n", percent(match.Score), width, match.Name, strings.Join(parts, ", "))
+	}
+	_, _ = fmt.Fprintln(w)
+	_, _ = fmt.Fprintln(w, "Compare with 'clotilde inspect <name>', and remove duplicates with 'clotilde delete <name>'.")
+}
+
+// percent rounds a 0 to 1 score to a percentage.
+func percent(score float64) int {
+	return int(score*100 + 0.5)
+}
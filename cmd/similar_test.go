@@ -0,0 +1,84 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/outputstyle"
+	"github.com/fgrehm/clotilde/internal/session"
+)
+
+var _ = Describe("Similar Command", func() {
+	var (
+		tempDir      string
+		clotildeRoot string
+		originalWd   string
+		store        session.Store
+	)
+
+	BeforeEach(func() {
+		tempDir = GinkgoT().TempDir()
+		GinkgoT().Setenv("HOME", filepath.Join(tempDir, "home"))
+		GinkgoT().Setenv("XDG_CONFIG_HOME", filepath.Join(tempDir, "config"))
+
+		var err error
+		originalWd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Chdir(tempDir)).To(Succeed())
+
+		Expect(config.EnsureClotildeStructure(tempDir)).To(Succeed())
+		clotildeRoot = filepath.Join(tempDir, config.ClotildeDir)
+		store = session.NewFileStore(clotildeRoot)
+	})
+
+	AfterEach(func() {
+		_ = os.Chdir(originalWd)
+	})
+
+	create := func(name, context, model, prompt string) {
+		sess := session.NewSession(name, "uuid-"+name)
+		sess.Metadata.Context = context
+		settings := &session.Settings{Model: model}
+		if prompt != "" {
+			sess.Metadata.HasCustomOutputStyle = true
+			Expect(outputstyle.CreateCustomStyleFile(clotildeRoot, name, prompt)).To(Succeed())
+			settings.OutputStyle = outputstyle.GetCustomStyleReference(name)
+		}
+		Expect(store.Create(sess)).To(Succeed())
+		Expect(store.SaveSettings(name, settings)).To(Succeed())
+	}
+
+	similar := func(args ...string) (string, error) {
+		return runClotilde(append([]string{"similar"}, args...)...)
+	}
+
+	It("lists the closest sessions first with a score per part", func() {
+		create("auth-bug", "GH-123 login fails after token refresh", "sonnet", "Be terse and cite files.")
+		create("auth-bug-2", "GH-123 login fails after token refresh", "sonnet", "Be terse and cite files.")
+		create("auth-retry", "GH-123 login fails on refresh", "opus", "")
+		create("docs", "Rewrite the README", "haiku", "")
+
+		out, err := similar("auth-bug", "--min", "20")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(MatchRegexp(`(?s)100%\s+auth-bug-2\s+settings 100%, prompt 100%, context 100%.*auth-retry`))
+		Expect(out).NotTo(ContainSubstring("docs"))
+	})
+
+	It("says so when nothing is similar enough", func() {
+		create("auth-bug", "GH-123 login fails", "sonnet", "")
+		create("docs", "Rewrite the README", "haiku", "")
+
+		out, err := similar("auth-bug")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(ContainSubstring("No sessions at least 50% similar to 'auth-bug'."))
+	})
+
+	It("fails for an unknown session", func() {
+		_, err := similar("missing")
+		Expect(err).To(MatchError(ContainSubstring("not found")))
+	})
+})
@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fgrehm/clotilde/internal/errs"
+	"github.com/fgrehm/clotilde/internal/snippet"
+	"github.com/fgrehm/clotilde/internal/ui"
+)
+
+// snippetPreviewLength caps the first line shown for each snippet by
+// 'snippets list'.
+const snippetPreviewLength = 60
+
+func newSnippetsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "snippets",
+		Aliases: []string{"snippet"},
+		Short:   "Manage reusable prompt snippets",
+		Long: `Manage short texts (checklists, conventions, reminders) that can be added to
+the context injected when a session is resumed:
+
+  clotilde resume my-session --snippet deploy-checklist
+
+Project snippets live in .claude/clotilde/snippets/ and are shared by the
+project's sessions. Global snippets (--global) live next to the global config
+(~/.config/clotilde/snippets/) and are available everywhere; a project snippet
+hides a global one with the same name. Snippets are plain Markdown files and
+can also be edited by hand.`,
+	}
+
+	cmd.AddCommand(newSnippetsListCmd())
+	cmd.AddCommand(newSnippetsShowCmd())
+	cmd.AddCommand(newSnippetsAddCmd())
+	cmd.AddCommand(newSnippetsDeleteCmd())
+
+	return cmd
+}
+
+func newSnippetsListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:         "list",
+		Aliases:     []string{"ls"},
+		Short:       "List the project's and global snippets",
+		Annotations: readOnly(),
+		Args:        cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Outside a project only the global snippets are listed
+			clotildeRoot, _ := findClotildeRoot()
+			snippets, err := snippet.List(clotildeRoot)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if len(snippets) == 0 {
+				_, _ = fmt.Fprintln(out, "No snippets yet. Add one with:")
+				_, _ = fmt.Fprintln(out, "  clotilde snippets add <name> <text>")
+				return nil
+			}
+			writeSnippetList(out, snippets)
+			return nil
+		},
+	}
+}
+
+// writeSnippetList prints one line per snippet: its name, scope and the
+// start of its first line.
+func writeSnippetList(w io.Writer, snippets []snippet.Snippet) {
+	width := 0
+	for _, s := range snippets {
+		width = max(width, len(s.Name))
+	}
+	for _, s := range snippets {
+		preview, _, _ := strings.Cut(s.Text, "\n")
+		if runes := []rune(preview); len(runes) > snippetPreviewLength {
+			preview = string(runes[:snippetPreviewLength-1]) + "…"
+		}
+		_, _ = fmt.Fprintf(w, "%-*s  %-7s  %s\n", width, s.Name, s.Scope, preview)
+	}
+}
+
+func newSnippetsShowCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:               "show <name>",
+		Short:             "Print a snippet",
+		Example:           `  clotilde snippets show deploy-checklist`,
+		Annotations:       readOnly(),
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: snippetArgCompletion,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clotildeRoot, _ := findClotildeRoot()
+			s, err := snippet.Get(clotildeRoot, args[0])
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), s.Text)
+			return nil
+		},
+	}
+}
+
+func newSnippetsAddCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add <name> [text]",
+		Short: "Add or replace a snippet",
+		Long: `Add a snippet, replacing any snippet with the same name in the same scope.
+The text is read from stdin when it isn't given as an argument.`,
+		Example: `  clotilde snippets add deploy-checklist "Before deploying: run migrations, check the error budget"
+  clotilde snippets add --global review-style < review.md`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, scope, err := snippetScopeDir(cmd)
+			if err != nil {
+				return err
+			}
+
+			var text string
+			if len(args) == 2 {
+				text = args[1]
+			} else {
+				data, err := io.ReadAll(cmd.InOrStdin())
+				if err != nil {
+					return fmt.Errorf("failed to read snippet text: %w", err)
+				}
+				text = string(data)
+			}
+
+			if err := snippet.Save(dir, args[0], text); err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), ui.Success(fmt.Sprintf("Saved %s snippet '%s'", scope, args[0])))
+			return nil
+		},
+	}
+	cmd.Flags().Bool("global", false, "Store the snippet globally instead of in the project")
+	return cmd
+}
+
+func newSnippetsDeleteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "delete <name>",
+		Aliases:           []string{"rm"},
+		Short:             "Delete a snippet",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: snippetArgCompletion,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, scope, err := snippetScopeDir(cmd)
+			if err != nil {
+				return err
+			}
+			if err := snippet.Delete(dir, args[0]); err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), ui.Success(fmt.Sprintf("Deleted %s snippet '%s'", scope, args[0])))
+			return nil
+		},
+	}
+	cmd.Flags().Bool("global", false, "Delete the global snippet instead of the project's")
+	return cmd
+}
+
+// snippetScopeDir returns the directory and scope a write goes to: the global
+// snippets with --global, otherwise the project's.
+func snippetScopeDir(cmd *cobra.Command) (string, string, error) {
+	if global, _ := cmd.Flags().GetBool("global"); global {
+		return snippet.GlobalDir(), snippet.ScopeGlobal, nil
+	}
+	clotildeRoot, err := findClotildeRoot()
+	if err != nil {
+		return "", "", errs.NotInProject("not in a clotilde project (use --global for global snippets)")
+	}
+	return snippet.ProjectDir(clotildeRoot), snippet.ScopeProject, nil
+}
+
+// snippetNameCompletion completes snippet names, e.g. for resume --snippet.
+func snippetNameCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	clotildeRoot, _ := findClotildeRoot()
+	snippets, err := snippet.List(clotildeRoot)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	names := make([]string, 0, len(snippets))
+	for _, s := range snippets {
+		names = append(names, s.Name)
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// snippetArgCompletion completes the snippet name argument.
+func snippetArgCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return snippetNameCompletion(cmd, args, toComplete)
+}
@@ -0,0 +1,78 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/snippet"
+)
+
+var _ = Describe("Snippets Command", func() {
+	var (
+		tempDir      string
+		clotildeRoot string
+		originalWd   string
+	)
+
+	BeforeEach(func() {
+		tempDir = GinkgoT().TempDir()
+		GinkgoT().Setenv("XDG_CONFIG_HOME", filepath.Join(tempDir, "config"))
+
+		var err error
+		originalWd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Chdir(tempDir)).To(Succeed())
+
+		Expect(config.EnsureClotildeStructure(tempDir)).To(Succeed())
+		clotildeRoot = filepath.Join(tempDir, config.ClotildeDir)
+	})
+
+	AfterEach(func() {
+		_ = os.Chdir(originalWd)
+	})
+
+	run := func(stdin string, args ...string) (string, error) {
+		out, _, err := runClotildeWithInput(stdin, append([]string{"snippets"}, args...)...)
+		return out, err
+	}
+
+	It("adds project and global snippets and lists them", func() {
+		_, err := run("", "add", "deploy-checklist", "Run the migrations first\nThen deploy")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = run("Keep reviews short\n", "add", "--global", "review")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(filepath.Join(snippet.ProjectDir(clotildeRoot), "deploy-checklist.md")).To(BeAnExistingFile())
+		Expect(filepath.Join(snippet.GlobalDir(), "review.md")).To(BeAnExistingFile())
+
+		out, err := run("", "list")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(Equal("deploy-checklist  project  Run the migrations first\n" +
+			"review            global   Keep reviews short\n"))
+	})
+
+	It("shows and deletes snippets", func() {
+		_, err := run("", "add", "deploy", "Run the migrations first")
+		Expect(err).NotTo(HaveOccurred())
+
+		out, err := run("", "show", "deploy")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(Equal("Run the migrations first\n"))
+
+		_, err = run("", "delete", "deploy")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = run("", "show", "deploy")
+		Expect(err).To(MatchError(ContainSubstring("snippet 'deploy' not found")))
+	})
+
+	It("rejects invalid names and empty snippets", func() {
+		_, err := run("", "add", "../escape", "text")
+		Expect(err).To(MatchError(ContainSubstring("invalid snippet name")))
+		_, err = run("  \n", "add", "empty")
+		Expect(err).To(MatchError(ContainSubstring("snippet 'empty' is empty")))
+	})
+})
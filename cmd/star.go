@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/errs"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/ui"
+)
+
+func newStarCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "star <name>",
+		Short: "Star or unstar a session",
+		Long: `Toggle a session's star. Starred sessions are listed first by list, the
+session picker and the dashboard, whenever they were last used. The picker
+toggles stars with f too.`,
+		Example:           `  clotilde star auth-bug`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: sessionNameCompletion,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clotildeRoot, err := config.FindClotildeRoot()
+			if err != nil {
+				return errNoSessions()
+			}
+			store := session.NewFileStore(clotildeRoot)
+
+			name := args[0]
+			if _, err := store.Get(name); err != nil {
+				return errs.NotFound("session '%s' not found", name)
+			}
+			var starred bool
+			err = session.UpdateLocked(clotildeRoot, store, name, func(sess *session.Session) bool {
+				sess.Metadata.Starred = !sess.Metadata.Starred
+				starred = sess.Metadata.Starred
+				return true
+			})
+			if err != nil {
+				return err
+			}
+
+			if starred {
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), ui.Success(fmt.Sprintf("Starred session '%s'", name)))
+			} else {
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), ui.Success(fmt.Sprintf("Unstarred session '%s'", name)))
+			}
+			return nil
+		},
+	}
+}
+
+// saveStars saves the stars toggled in the picker. Failures are warnings:
+// the pick itself still counts.
+func saveStars(clotildeRoot string, store session.Store, starred map[string]bool) {
+	for name, star := range starred {
+		err := session.UpdateLocked(clotildeRoot, store, name, func(sess *session.Session) bool {
+			if sess.Metadata.Starred == star {
+				return false
+			}
+			sess.Metadata.Starred = star
+			return true
+		})
+		if err != nil {
+			_, _ = fmt.Fprintln(os.Stderr, ui.Warning(fmt.Sprintf("Failed to save star of '%s': %v", name, err)))
+		}
+	}
+}
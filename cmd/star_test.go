@@ -0,0 +1,84 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/errs"
+	"github.com/fgrehm/clotilde/internal/session"
+)
+
+var _ = Describe("Star command", func() {
+	var (
+		tempDir    string
+		originalWd string
+		store      session.Store
+	)
+
+	BeforeEach(func() {
+		tempDir = GinkgoT().TempDir()
+		GinkgoT().Setenv("XDG_CONFIG_HOME", filepath.Join(tempDir, "xdg"))
+
+		var err error
+		originalWd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Chdir(tempDir)).To(Succeed())
+
+		Expect(config.EnsureClotildeStructure(tempDir)).To(Succeed())
+		store = session.NewFileStore(filepath.Join(tempDir, config.ClotildeDir))
+		for i, name := range []string{"recent", "favorite"} {
+			sess := session.NewSession(name, "uuid-"+name)
+			sess.Metadata.LastAccessed = time.Now().Add(-time.Duration(i) * 24 * time.Hour)
+			Expect(store.Create(sess)).To(Succeed())
+		}
+	})
+
+	AfterEach(func() {
+		_ = os.Chdir(originalWd)
+	})
+
+	run := runClotilde
+
+	starred := func(name string) bool {
+		sess, err := store.Get(name)
+		Expect(err).NotTo(HaveOccurred())
+		return sess.Metadata.Starred
+	}
+
+	It("toggles a session's star", func() {
+		out, err := run("star", "favorite")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(ContainSubstring("Starred session 'favorite'"))
+		Expect(starred("favorite")).To(BeTrue())
+
+		out, err = run("star", "favorite")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(ContainSubstring("Unstarred session 'favorite'"))
+		Expect(starred("favorite")).To(BeFalse())
+	})
+
+	It("lists starred sessions first", func() {
+		out, err := run("list")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(strings.Index(out, "recent")).To(BeNumerically("<", strings.Index(out, "favorite")))
+
+		_, err = run("star", "favorite")
+		Expect(err).NotTo(HaveOccurred())
+
+		out, err = run("list")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(strings.Index(out, "favorite")).To(BeNumerically("<", strings.Index(out, "recent")))
+		Expect(out).To(MatchRegexp(`favorite.*★`))
+	})
+
+	It("fails for unknown sessions", func() {
+		_, err := run("star", "missing")
+		Expect(errs.ExitCode(err)).To(Equal(errs.ExitNotFound))
+	})
+})
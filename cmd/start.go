@@ -5,22 +5,26 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 
 	"github.com/fgrehm/clotilde/internal/claude"
 	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/errs"
 	"github.com/fgrehm/clotilde/internal/session"
 	"github.com/fgrehm/clotilde/internal/ui"
 	"github.com/fgrehm/clotilde/internal/util"
 )
 
 func init() {
-	// Wire up the claude binary path function and verbose flag
+	// Wire up the claude binary path function, verbose and title flags
 	claude.ClaudeBinaryPathFunc = GetClaudeBinaryPath
 	claude.VerboseFunc = IsVerbose
+	claude.TerminalTitleFunc = wantsTerminalTitle
 }
 
 // newStartCmd creates a fresh start command instance (avoids flag pollution in tests)
@@ -32,17 +36,48 @@ func newStartCmd() *cobra.Command {
 If no name is provided, one is generated automatically (e.g. "2026-03-08-happy-fox").
 Optionally specify a model, profile, and context.
 
+With --no-launch the session is created without starting Claude Code and
+its name is printed, so scripts can provision sessions to resume later.
+
+With --file (-f) the session is described by a JSON or YAML spec instead of
+flags, read from stdin with '-'. Flags still override the spec. Fields:
+name, model, effort, profile, permissionMode, permissions (allow, deny,
+additionalDirectories), outputStyle, outputStyleFile, context, expires,
+incognito, and prompt (the first message sent to Claude Code).
+
 Pass additional flags to Claude Code after '--':
   clotilde start my-session -- --debug api,hooks
   clotilde start test --model haiku -- --verbose
-  clotilde start                       # auto-generated name`,
+  clotilde start                       # auto-generated name
+  clotilde start --no-launch --context "GH-123"
+  clotilde start -f session.yaml       # name, model, prompt, ... from a spec
+  echo '{"name":"triage","prompt":"Summarize open TODOs"}' | clotilde start -f -`,
 		Args: maxPositionalArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			// Extract additional args after '--'
-			var additionalArgs []string
-			argsLenAtDash := cmd.Flags().ArgsLenAtDash()
-			if argsLenAtDash > 0 && len(args) > argsLenAtDash {
-				additionalArgs = args[argsLenAtDash:]
+			// Claude flags from --with presets and after '--'
+			additionalArgs, err := claudeArgs(cmd, args)
+			if err != nil {
+				return err
+			}
+
+			noLaunch, _ := cmd.Flags().GetBool("no-launch")
+			if noLaunch && len(additionalArgs) > 0 {
+				return fmt.Errorf("cannot pass Claude flags with --no-launch (pass them to 'clotilde resume' instead)")
+			}
+
+			// Fill unset flags from a session spec; a name argument wins over the spec's
+			var spec *SessionSpec
+			if specFile, _ := cmd.Flags().GetString("file"); specFile != "" {
+				var err error
+				if spec, err = readSessionSpec(cmd, specFile); err != nil {
+					return err
+				}
+				if err := applySessionSpec(cmd, spec); err != nil {
+					return err
+				}
+				if spec.Name != "" && (len(args) == 0 || cmd.Flags().ArgsLenAtDash() == 0) {
+					args = append([]string{spec.Name}, args...)
+				}
 			}
 
 			// Generate or use provided name
@@ -53,10 +88,35 @@ Pass additional flags to Claude Code after '--':
 				// Check if session already exists - offer to resume instead
 				// (only for explicitly provided names)
 				if clotildeRoot, err := config.FindOrCreateClotildeRoot(); err == nil {
+					if name, err = sessionNameArg(cmd, clotildeRoot, name); err != nil {
+						return err
+					}
+					requested := name
 					store := session.NewFileStore(clotildeRoot)
-					if store.Exists(name) {
+					suffixed, applied, err := applyNameSuffix(cmd, clotildeRoot, store, name)
+					if err != nil {
+						return err
+					}
+					if applied {
+						name = suffixed
+					} else if store.Exists(name) && (noLaunch || spec != nil) {
+						return errs.AlreadyExists("session '%s' already exists", name)
+					} else if store.Exists(name) {
 						return handleExistingSession(cmd, name, clotildeRoot, store, additionalArgs)
 					}
+
+					// New sessions named in the project's clotilde.yaml start from their spec
+					if spec == nil {
+						if spec, err = projectSessionSpec(clotildeRoot, requested); err != nil {
+							return err
+						}
+						if spec != nil {
+							if err := applySessionSpec(cmd, spec); err != nil {
+								return err
+							}
+							_, _ = fmt.Fprintln(cmd.ErrOrStderr(), ui.Info(fmt.Sprintf("Using '%s' from %s", requested, config.SessionsFileName)))
+						}
+					}
 				}
 			} else {
 				// Generate a unique random name
@@ -101,6 +161,13 @@ Pass additional flags to Claude Code after '--':
 			if err != nil {
 				return err
 			}
+			if spec != nil {
+				params.Prompt = strings.TrimSpace(spec.Prompt)
+			}
+
+			if err := confirmYoloDirs(cmd, params); err != nil {
+				return err
+			}
 
 			// Create the session
 			result, err := createSession(params)
@@ -108,23 +175,42 @@ Pass additional flags to Claude Code after '--':
 				return err
 			}
 
-			// Print output
+			// Print output (to stderr with --no-launch, where stdout is just the name)
+			out := cmd.OutOrStdout()
+			if noLaunch {
+				out = cmd.ErrOrStderr()
+			}
 			if params.Incognito {
-				_, _ = fmt.Fprintln(cmd.OutOrStdout(), ui.Info(fmt.Sprintf("👻 Created incognito session '%s' (%s)", result.Session.Name, result.Session.Metadata.SessionID)))
-				_, _ = fmt.Fprintln(cmd.OutOrStdout(), ui.Info("👻 This session will auto-delete when you exit Claude"))
+				_, _ = fmt.Fprintln(out, ui.Info(fmt.Sprintf("👻 Created incognito session '%s' (%s)", result.Session.Name, result.Session.Metadata.SessionID)))
+				_, _ = fmt.Fprintln(out, ui.Info("👻 This session will auto-delete when you exit Claude"))
 			} else {
-				_, _ = fmt.Fprintln(cmd.OutOrStdout(), ui.Success(fmt.Sprintf("Created session '%s' (%s)", result.Session.Name, result.Session.Metadata.SessionID)))
+				_, _ = fmt.Fprintln(out, ui.Success(fmt.Sprintf("Created session '%s' (%s)", result.Session.Name, result.Session.Metadata.SessionID)))
+				printExpiry(out, result.Session)
+			}
+			if explain, _ := cmd.Flags().GetBool("explain"); explain {
+				_, _ = fmt.Fprintln(out)
+				printSettingsExplanation(out, result.Resolved)
+			}
+
+			if noLaunch {
+				printNoLaunch(cmd, result.Session.Name)
+				return nil
 			}
-			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "\nStarting Claude Code...")
+			_, _ = fmt.Fprintln(out, "\nStarting Claude Code...")
+			warnMissingHooks(cmd, result.ClotildeRoot)
+			warnActiveSessions(cmd, result.ClotildeRoot, result.Session.Name)
 
-			// Invoke claude
-			return claude.Start(result.ClotildeRoot, result.Session, result.SettingsFile, additionalArgs)
+			// Invoke claude, sending the spec's prompt as the first message
+			err = claude.Start(result.ClotildeRoot, result.Session, result.SettingsFile, additionalArgs, params.Prompt)
+			return handleLaunchFailure(cmd.ErrOrStderr(), result.ClotildeRoot, err)
 		},
 	}
 	cmd.Flags().String("model", "", "Claude model to use (haiku, sonnet, opus); opus defaults to 1M context")
 	cmd.Flags().Bool("incognito", false, "Create incognito session (auto-deletes on exit)")
 	cmd.Flags().String("context", "", "Session context (e.g. \"working on ticket GH-123\")")
 	cmd.Flags().String("profile", "", "Named profile from config (model, permissions, output style)")
+	registerLauncherFlag(cmd)
+	cmd.Flags().StringP("file", "f", "", "Read the session spec (JSON or YAML) from a file, or stdin with '-'")
 
 	// Permission flags
 	cmd.Flags().String("permission-mode", "", "Permission mode (acceptEdits, bypassPermissions, default, dontAsk, plan)")
@@ -138,6 +224,17 @@ Pass additional flags to Claude Code after '--':
 
 	// Shorthand flags
 	registerShorthandFlags(cmd)
+	registerWithFlag(cmd)
+	registerExplainFlag(cmd)
+	registerTitleFlag(cmd)
+	registerHookCheckFlag(cmd)
+	registerIKnowFlag(cmd)
+	registerSlugifyFlag(cmd)
+	registerExpiresFlag(cmd)
+	registerNoLaunchFlag(cmd, "Create the session without starting Claude Code (prints its name)")
+	cmd.Flags().Bool("auto-suffix", false, "If the name is taken, append the first free numeric suffix (-2, -3, ...)")
+	cmd.Flags().Bool("suffix-date", false, "Append today's date to the name (e.g. -jan15), adding a number if still taken")
+	cmd.MarkFlagsMutuallyExclusive("auto-suffix", "suffix-date")
 
 	// Register flag completions
 	_ = cmd.RegisterFlagCompletionFunc("model", modelCompletion)
@@ -150,7 +247,11 @@ Pass additional flags to Claude Code after '--':
 // creating a duplicate. In non-TTY mode, returns an error suggesting the resume command.
 func handleExistingSession(cmd *cobra.Command, name, clotildeRoot string, store *session.FileStore, additionalArgs []string) error {
 	if !isatty.IsTerminal(os.Stdout.Fd()) {
-		return fmt.Errorf("session '%s' already exists, use 'clotilde resume %s' to resume it", name, name)
+		msg := fmt.Sprintf("session '%s' already exists, use 'clotilde resume %s' to resume it", name, name)
+		if suggestions := suggestSessionNames(clotildeRoot, store, name); len(suggestions) > 0 {
+			msg += fmt.Sprintf("\navailable names: %s (or pass --auto-suffix / --suffix-date)", strings.Join(suggestions, ", "))
+		}
+		return errs.AlreadyExists("%s", msg)
 	}
 
 	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s\n", ui.Warning(fmt.Sprintf("Session '%s' already exists.", name)))
@@ -167,24 +268,11 @@ func handleExistingSession(cmd *cobra.Command, name, clotildeRoot string, store
 		return nil
 	}
 
-	// Resolve shorthand flags for resume (pass as additional args, not baked into settings)
-	permMode, err := resolvePermissionMode(cmd)
+	// Resolve flags for resume (pass as additional args, not baked into settings)
+	flags, err := flagSettingsLayer(cmd)
 	if err != nil {
 		return err
 	}
-	if permMode != "" {
-		additionalArgs = append(additionalArgs, "--permission-mode", permMode)
-	}
-
-	fastEnabled, err := resolveFastMode(cmd)
-	if err != nil {
-		return err
-	}
-	if fastEnabled {
-		additionalArgs = append(additionalArgs, "--model", "haiku", "--effort", "low")
-	} else {
-		additionalArgs = collectEffortFlag(cmd, additionalArgs)
-	}
 
 	// Load and resume session
 	sess, err := store.Get(name)
@@ -192,6 +280,12 @@ func handleExistingSession(cmd *cobra.Command, name, clotildeRoot string, store
 		return fmt.Errorf("failed to load session: %w", err)
 	}
 
+	resolved, pinned, err := resolveSessionSettings(clotildeRoot, store, name, flags)
+	if err != nil {
+		return err
+	}
+	additionalArgs = append(additionalArgs, resolved.launchArgs()...)
+
 	sess.UpdateLastAccessed()
 	if err := store.Update(sess); err != nil {
 		return fmt.Errorf("failed to update session: %w", err)
@@ -205,5 +299,73 @@ func handleExistingSession(cmd *cobra.Command, name, clotildeRoot string, store
 	}
 
 	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "\nResuming session '%s' (%s)\n\n", sess.Name, sess.Metadata.SessionID)
-	return claude.Resume(clotildeRoot, sess, settingsFile, additionalArgs)
+	if explain, _ := cmd.Flags().GetBool("explain"); explain {
+		printSettingsExplanation(cmd.OutOrStdout(), resolved)
+	}
+	printSessionOverrides(cmd.OutOrStdout(), resolved, pinned)
+	return claude.Resume(clotildeRoot, sess, settingsFile, additionalArgs, "")
+}
+
+// sessionNames returns the names of all sessions in the store.
+func sessionNames(store session.Store) ([]string, error) {
+	sessions, err := store.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	names := make([]string, len(sessions))
+	for i, sess := range sessions {
+		names[i] = sess.Name
+	}
+	return names, nil
+}
+
+// applyNameSuffix handles --suffix-date (always appends today's date) and
+// --auto-suffix (appends -2, -3, ... only when the name is taken), so scripts
+// can create sessions without handling collisions. Returns applied=false when
+// neither flag is set.
+func applyNameSuffix(cmd *cobra.Command, clotildeRoot string, store session.Store, name string) (string, bool, error) {
+	autoSuffix, _ := cmd.Flags().GetBool("auto-suffix")
+	suffixDate, _ := cmd.Flags().GetBool("suffix-date")
+	if !autoSuffix && !suffixDate {
+		return name, false, nil
+	}
+
+	rules, err := session.LoadNameRules(clotildeRoot)
+	if err != nil {
+		return "", false, fmt.Errorf("invalid naming config: %w", err)
+	}
+	existing, err := sessionNames(store)
+	if err != nil {
+		return "", false, err
+	}
+
+	switch {
+	case suffixDate:
+		return util.DatedName(name, existing, rules.MaxLength, time.Now()), true, nil
+	case slices.Contains(existing, name):
+		return util.NumberedName(name, existing, rules.MaxLength), true, nil
+	default:
+		return name, true, nil
+	}
+}
+
+// suggestSessionNames returns free near-names for a taken session name that
+// satisfy the configured naming rules. Errors yield no suggestions.
+func suggestSessionNames(clotildeRoot string, store session.Store, name string) []string {
+	rules, err := session.LoadNameRules(clotildeRoot)
+	if err != nil {
+		return nil
+	}
+	existing, err := sessionNames(store)
+	if err != nil {
+		return nil
+	}
+
+	var valid []string
+	for _, candidate := range util.SuggestNames(name, existing, rules.MaxLength, time.Now()) {
+		if rules.Validate(candidate) == nil {
+			valid = append(valid, candidate)
+		}
+	}
+	return valid
 }
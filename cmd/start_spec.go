@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/util"
+)
+
+// SessionSpec is the declarative form of 'clotilde start' flags, read from a
+// JSON or YAML file (or stdin) with --file or from the project's clotilde.yaml.
+// Field names follow the profile config; flags given on the command line
+// override the spec.
+type SessionSpec struct {
+	Name            string          `yaml:"name"`
+	Model           string          `yaml:"model"`
+	Effort          string          `yaml:"effort"`
+	Profile         string          `yaml:"profile"`
+	PermissionMode  string          `yaml:"permissionMode"`
+	Permissions     SpecPermissions `yaml:"permissions"`
+	OutputStyle     string          `yaml:"outputStyle"`
+	OutputStyleFile string          `yaml:"outputStyleFile"`
+	Context         string          `yaml:"context"`
+	Expires         string          `yaml:"expires"` // duration, as with --expires
+	Incognito       bool            `yaml:"incognito"`
+	Prompt          string          `yaml:"prompt"` // first message sent to Claude Code
+}
+
+// SpecPermissions holds the tool permissions of a SessionSpec.
+type SpecPermissions struct {
+	Allow                 []string `yaml:"allow"`
+	Deny                  []string `yaml:"deny"`
+	AdditionalDirectories []string `yaml:"additionalDirectories"`
+}
+
+// readSessionSpec parses the spec at path, or stdin when path is "-". YAML is
+// a superset of JSON, so both are accepted; unknown fields are rejected so
+// typos don't go unnoticed.
+func readSessionSpec(cmd *cobra.Command, path string) (*SessionSpec, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(cmd.InOrStdin())
+		path = "stdin"
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session spec: %w", err)
+	}
+
+	spec := &SessionSpec{}
+	if err := decodeStrict(data, spec); err != nil {
+		return nil, fmt.Errorf("invalid session spec in %s: %w", path, err)
+	}
+	return spec, nil
+}
+
+// decodeStrict decodes YAML (or JSON) into v, rejecting unknown fields.
+// Empty input leaves v untouched.
+func decodeStrict(data []byte, v any) error {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(v); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
+
+// sessionsFile is the layout of a project's clotilde.yaml.
+type sessionsFile struct {
+	Sessions map[string]*SessionSpec `yaml:"sessions"`
+}
+
+// loadSessionsFile returns the specs declared in the project's clotilde.yaml,
+// keyed by session name, or nil when the project has none. Relative output
+// style files are resolved against the project root.
+func loadSessionsFile(clotildeRoot string) (map[string]*SessionSpec, error) {
+	path := config.SessionsFilePath(clotildeRoot)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", config.SessionsFileName, err)
+	}
+
+	var file sessionsFile
+	if err := decodeStrict(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", config.SessionsFileName, err)
+	}
+	for name, spec := range file.Sessions {
+		if spec == nil {
+			spec = &SessionSpec{}
+			file.Sessions[name] = spec
+		}
+		if spec.Name != "" && spec.Name != name {
+			return nil, fmt.Errorf("invalid %s: session '%s' sets a different name ('%s')", config.SessionsFileName, name, spec.Name)
+		}
+		spec.Name = name
+		if spec.OutputStyleFile != "" && !filepath.IsAbs(spec.OutputStyleFile) {
+			spec.OutputStyleFile = filepath.Join(config.ProjectRootOf(clotildeRoot), spec.OutputStyleFile)
+		}
+	}
+	return file.Sessions, nil
+}
+
+// projectSessionSpec returns the clotilde.yaml spec for name, or nil when
+// there's none.
+func projectSessionSpec(clotildeRoot, name string) (*SessionSpec, error) {
+	specs, err := loadSessionsFile(clotildeRoot)
+	if err != nil {
+		return nil, err
+	}
+	return specs[name], nil
+}
+
+// pendingParams returns the parameters 'clotilde sync' creates spec's
+// session with: the same as 'start -f' with --no-launch, so the prompt is
+// sent on the first resume.
+func (spec *SessionSpec) pendingParams() (SessionCreateParams, error) {
+	if spec.OutputStyle != "" && spec.OutputStyleFile != "" {
+		return SessionCreateParams{}, fmt.Errorf("session '%s': cannot specify both outputStyle and outputStyleFile", spec.Name)
+	}
+	var expiresAt time.Time
+	if spec.Expires != "" {
+		d, err := util.ParseDuration(spec.Expires)
+		if err != nil {
+			return SessionCreateParams{}, fmt.Errorf("session '%s': invalid expires: %w", spec.Name, err)
+		}
+		expiresAt = time.Now().Add(d)
+	}
+
+	return SessionCreateParams{
+		Name:            spec.Name,
+		Model:           spec.Model,
+		Profile:         spec.Profile,
+		PermissionMode:  spec.PermissionMode,
+		AllowedTools:    spec.Permissions.Allow,
+		DisallowedTools: spec.Permissions.Deny,
+		AdditionalDirs:  spec.Permissions.AdditionalDirectories,
+		OutputStyle:     spec.OutputStyle,
+		OutputStyleFile: spec.OutputStyleFile,
+		Context:         spec.Context,
+		EffortLevel:     spec.Effort,
+		ExpiresAt:       expiresAt,
+		Pending:         true,
+		Prompt:          strings.TrimSpace(spec.Prompt),
+	}, nil
+}
+
+// applySessionSpec copies spec values into the flags the user didn't set, so
+// the rest of 'start' only has to deal with flags.
+func applySessionSpec(cmd *cobra.Command, spec *SessionSpec) error {
+	flags := cmd.Flags()
+	changed := func(names ...string) bool {
+		for _, name := range names {
+			if flags.Changed(name) {
+				return true
+			}
+		}
+		return false
+	}
+
+	values := []struct {
+		flag, value string
+		skip        bool
+	}{
+		{"model", spec.Model, changed("model", "fast")},
+		{"effort", spec.Effort, changed("effort", "fast")},
+		{"profile", spec.Profile, changed("profile")},
+		{"permission-mode", spec.PermissionMode, changed("permission-mode", "accept-edits", "yolo", "plan", "dont-ask")},
+		{"output-style", spec.OutputStyle, changed("output-style", "output-style-file")},
+		{"output-style-file", spec.OutputStyleFile, changed("output-style", "output-style-file")},
+		{"context", spec.Context, changed("context")},
+		{"expires", spec.Expires, changed("expires")},
+	}
+	for _, v := range values {
+		if v.skip || v.value == "" {
+			continue
+		}
+		if err := flags.Set(v.flag, v.value); err != nil {
+			return fmt.Errorf("invalid %s in session spec: %w", v.flag, err)
+		}
+	}
+	if spec.Incognito && !changed("incognito") {
+		_ = flags.Set("incognito", "true")
+	}
+
+	lists := []struct {
+		flag   string
+		values []string
+	}{
+		{"allowed-tools", spec.Permissions.Allow},
+		{"disallowed-tools", spec.Permissions.Deny},
+		{"add-dir", spec.Permissions.AdditionalDirectories},
+	}
+	for _, l := range lists {
+		if len(l.values) == 0 || changed(l.flag) {
+			continue
+		}
+		// Replace rather than Set, which would split entries on commas
+		if err := flags.Lookup(l.flag).Value.(pflag.SliceValue).Replace(l.values); err != nil {
+			return fmt.Errorf("invalid %s in session spec: %w", l.flag, err)
+		}
+	}
+	return nil
+}
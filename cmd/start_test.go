@@ -1,9 +1,12 @@
 package cmd_test
 
 import (
+	"bytes"
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -11,8 +14,10 @@ import (
 	"github.com/fgrehm/clotilde/cmd"
 	"github.com/fgrehm/clotilde/internal/claude"
 	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/errs"
 	"github.com/fgrehm/clotilde/internal/session"
 	"github.com/fgrehm/clotilde/internal/testutil"
+	"github.com/fgrehm/clotilde/internal/util"
 )
 
 var _ = Describe("Start Command", func() {
@@ -156,6 +161,7 @@ var _ = Describe("Start Command", func() {
 		Expect(err).To(HaveOccurred())
 		Expect(err.Error()).To(ContainSubstring("already exists"))
 		Expect(err.Error()).To(ContainSubstring("clotilde resume duplicate"))
+		Expect(errs.ExitCode(err)).To(Equal(errs.ExitAlreadyExists))
 	})
 
 	It("should cleanup session when no messages were sent", func() {
@@ -175,6 +181,36 @@ var _ = Describe("Start Command", func() {
 		Expect(store.Exists("empty-session")).To(BeFalse())
 	})
 
+	It("rolls back sessions whose claude fails to launch", func() {
+		claude.SessionUsedFunc = func(_ string, _ *session.Session) bool { return false }
+		failingClaude := filepath.Join(tempDir, "failing-claude")
+		Expect(os.WriteFile(failingClaude, []byte("#!/bin/sh\necho \"error: unknown option '--bogus'\" >&2\nexit 1\n"), 0o755)).To(Succeed())
+
+		var stderr bytes.Buffer
+		rootCmd := cmd.NewRootCmd()
+		rootCmd.SetOut(io.Discard)
+		rootCmd.SetErr(&stderr)
+		rootCmd.SetArgs([]string{"--claude-bin", failingClaude, "start", "bad-flag", "--", "--bogus"})
+
+		err := rootCmd.Execute()
+		Expect(errs.ExitCode(err)).To(Equal(errs.ExitClaudeFailed))
+		Expect(stderr.String()).To(ContainSubstring("Claude Code failed to launch for session 'bad-flag'"))
+		Expect(stderr.String()).To(ContainSubstring("  error: unknown option '--bogus'"))
+		Expect(stderr.String()).To(ContainSubstring("Removed session 'bad-flag'"))
+		Expect(session.NewFileStore(clotildeRoot).Exists("bad-flag")).To(BeFalse())
+	})
+
+	It("rolls back sessions when setup fails after creating them", func() {
+		rootCmd := cmd.NewRootCmd()
+		rootCmd.SetOut(io.Discard)
+		rootCmd.SetErr(io.Discard)
+		rootCmd.SetArgs([]string{"--claude-bin", filepath.Join(fakeClaudeDir, "claude"), "start", "no-profile", "--profile", "missing"})
+
+		err := rootCmd.Execute()
+		Expect(errs.ExitCode(err)).To(Equal(errs.ExitNotFound))
+		Expect(session.NewFileStore(clotildeRoot).Exists("no-profile")).To(BeFalse())
+	})
+
 	It("should keep session when messages were sent", func() {
 		// Simulate Claude Code creating a transcript (user sent messages)
 		claude.SessionUsedFunc = func(_ string, _ *session.Session) bool { return true }
@@ -211,4 +247,356 @@ var _ = Describe("Start Command", func() {
 		name := sessions[0].Name
 		Expect(name).To(MatchRegexp(`^\d{4}-\d{2}-\d{2}-[a-z]+-[a-z]+$`))
 	})
+
+	Describe("session naming", func() {
+		start := func(args ...string) error {
+			rootCmd := cmd.NewRootCmd()
+			rootCmd.SetOut(io.Discard)
+			rootCmd.SetErr(io.Discard)
+			rootCmd.SetArgs(append([]string{"--claude-bin", filepath.Join(fakeClaudeDir, "claude"), "start"}, args...))
+			return rootCmd.Execute()
+		}
+
+		writeNaming := func(naming string) {
+			Expect(os.WriteFile(config.GetConfigPath(clotildeRoot), []byte(`{"naming": `+naming+`}`), 0o644)).To(Succeed())
+		}
+
+		It("converts arbitrary text with --slugify", func() {
+			Expect(start("My Feature!", "--slugify")).To(Succeed())
+
+			store := session.NewFileStore(clotildeRoot)
+			Expect(store.Exists("my-feature")).To(BeTrue())
+		})
+
+		It("rejects invalid names without --slugify", func() {
+			err := start("My Feature!")
+			Expect(err).To(MatchError(session.ErrInvalidName))
+		})
+
+		It("accepts names allowed by the naming config", func() {
+			writeNaming(`{"case": "any", "extraChars": "_"}`)
+
+			Expect(start("Feature_X")).To(Succeed())
+
+			store := session.NewFileStore(clotildeRoot)
+			Expect(store.Exists("Feature_X")).To(BeTrue())
+		})
+
+		It("enforces a configured max length", func() {
+			writeNaming(`{"maxLength": 10}`)
+
+			Expect(start("much-too-long-name")).To(MatchError(ContainSubstring("at most 10 characters")))
+		})
+
+		It("reports invalid naming config", func() {
+			writeNaming(`{"extraChars": "/"}`)
+
+			Expect(start("valid-name")).To(MatchError(ContainSubstring("invalid naming config")))
+		})
+	})
+
+	It("records an expiry with --expires", func() {
+		rootCmd := cmd.NewRootCmd()
+		rootCmd.SetOut(io.Discard)
+		rootCmd.SetErr(io.Discard)
+		rootCmd.SetArgs([]string{"--claude-bin", filepath.Join(fakeClaudeDir, "claude"), "start", "spike", "--expires", "7d"})
+		Expect(rootCmd.Execute()).To(Succeed())
+
+		store := session.NewFileStore(clotildeRoot)
+		sess, err := store.Get("spike")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sess.Metadata.ExpiresAt).To(BeTemporally("~", time.Now().Add(7*24*time.Hour), time.Minute))
+	})
+
+	It("rejects an invalid --expires value", func() {
+		rootCmd := cmd.NewRootCmd()
+		rootCmd.SetOut(io.Discard)
+		rootCmd.SetErr(io.Discard)
+		rootCmd.SetArgs([]string{"--claude-bin", filepath.Join(fakeClaudeDir, "claude"), "start", "spike", "--expires", "soon"})
+		Expect(rootCmd.Execute()).To(MatchError(ContainSubstring("invalid --expires")))
+	})
+
+	Describe("name collisions", func() {
+		start := func(args ...string) error {
+			rootCmd := cmd.NewRootCmd()
+			rootCmd.SetOut(io.Discard)
+			rootCmd.SetErr(io.Discard)
+			rootCmd.SetArgs(append([]string{"--claude-bin", filepath.Join(fakeClaudeDir, "claude"), "start"}, args...))
+			return rootCmd.Execute()
+		}
+
+		BeforeEach(func() {
+			Expect(start("auth")).To(Succeed())
+		})
+
+		It("suggests available names when the name is taken (non-TTY)", func() {
+			err := start("auth")
+			Expect(err).To(HaveOccurred())
+			// The date suffix is matched loosely, so the test passes across midnight
+			Expect(err.Error()).To(MatchRegexp(`available names: auth-2, auth-3, auth-[a-z]{3}\d{1,2}\b`))
+			Expect(err.Error()).To(ContainSubstring("--auto-suffix"))
+		})
+
+		It("appends a numeric suffix with --auto-suffix", func() {
+			Expect(start("auth", "--auto-suffix")).To(Succeed())
+			Expect(start("auth", "--auto-suffix")).To(Succeed())
+
+			store := session.NewFileStore(clotildeRoot)
+			Expect(store.Exists("auth-2")).To(BeTrue())
+			Expect(store.Exists("auth-3")).To(BeTrue())
+		})
+
+		It("keeps the name as-is with --auto-suffix when it is free", func() {
+			Expect(start("billing", "--auto-suffix")).To(Succeed())
+
+			store := session.NewFileStore(clotildeRoot)
+			Expect(store.Exists("billing")).To(BeTrue())
+		})
+
+		It("always appends the date with --suffix-date", func() {
+			Expect(start("billing", "--suffix-date")).To(Succeed())
+
+			sessions, err := session.NewFileStore(clotildeRoot).List()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sessions).To(ContainElement(HaveField("Name", MatchRegexp(`^billing-[a-z]{3}\d{1,2}$`))))
+		})
+
+		It("rejects --auto-suffix together with --suffix-date", func() {
+			Expect(start("auth", "--auto-suffix", "--suffix-date")).To(HaveOccurred())
+		})
+	})
+
+	Describe("--no-launch", func() {
+		start := func(args ...string) (string, error) {
+			var out bytes.Buffer
+			rootCmd := cmd.NewRootCmd()
+			rootCmd.SetOut(&out)
+			rootCmd.SetErr(io.Discard)
+			rootCmd.SetArgs(append([]string{"--claude-bin", filepath.Join(fakeClaudeDir, "claude"), "start"}, args...))
+			err := rootCmd.Execute()
+			return out.String(), err
+		}
+
+		It("creates the session without launching Claude and prints its name", func() {
+			out, err := start("later", "--no-launch", "--model", "haiku")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(out).To(Equal("later\n"))
+			Expect(claudeArgsFile).NotTo(BeAnExistingFile())
+
+			store := session.NewFileStore(clotildeRoot)
+			sess, err := store.Get("later")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sess.Metadata.PendingLaunch).To(BeTrue())
+		})
+
+		It("starts the session with its pre-assigned ID on first resume", func() {
+			_, err := start("later", "--no-launch")
+			Expect(err).NotTo(HaveOccurred())
+
+			rootCmd := cmd.NewRootCmd()
+			rootCmd.SetOut(io.Discard)
+			rootCmd.SetErr(io.Discard)
+			rootCmd.SetArgs([]string{"--claude-bin", filepath.Join(fakeClaudeDir, "claude"), "resume", "later"})
+			Expect(rootCmd.Execute()).To(Succeed())
+
+			store := session.NewFileStore(clotildeRoot)
+			sess, err := store.Get("later")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sess.Metadata.PendingLaunch).To(BeFalse())
+
+			args, err := testutil.ReadClaudeArgs(claudeArgsFile)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(args).To(ContainSubstring("--session-id " + sess.Metadata.SessionID))
+			Expect(args).NotTo(ContainSubstring("--resume"))
+		})
+
+		It("prints a generated name when none is given", func() {
+			out, err := start("--no-launch")
+			Expect(err).NotTo(HaveOccurred())
+
+			name := strings.TrimSpace(out)
+			Expect(session.NewFileStore(clotildeRoot).Exists(name)).To(BeTrue())
+		})
+
+		It("errors instead of offering to resume an existing session", func() {
+			_, err := start("later", "--no-launch")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = start("later", "--no-launch")
+			Expect(err).To(MatchError("session 'later' already exists"))
+		})
+
+		It("rejects --incognito", func() {
+			_, err := start("later", "--no-launch", "--incognito")
+			Expect(err).To(MatchError(ContainSubstring("cannot use --no-launch with --incognito")))
+		})
+	})
+
+	Describe("output style provenance", func() {
+		start := func(args ...string) *session.Session {
+			rootCmd := cmd.NewRootCmd()
+			rootCmd.SetOut(io.Discard)
+			rootCmd.SetErr(io.Discard)
+			rootCmd.SetArgs(append([]string{"start", "styled", "--no-launch"}, args...))
+			Expect(rootCmd.Execute()).To(Succeed())
+
+			sess, err := session.NewFileStore(clotildeRoot).Get("styled")
+			Expect(err).NotTo(HaveOccurred())
+			return sess
+		}
+
+		It("records built-in styles", func() {
+			sess := start("--output-style", "Learning")
+			Expect(sess.Metadata.OutputStyleSource).To(Equal(&session.OutputStyleSource{Kind: session.OutputStyleBuiltin, Name: "Learning"}))
+			Expect(sess.Metadata.HasCustomOutputStyle).To(BeFalse())
+		})
+
+		It("records the file of shared styles", func() {
+			stylePath := filepath.Join(tempDir, ".claude", "output-styles", "reviewer.md")
+			Expect(os.MkdirAll(filepath.Dir(stylePath), 0o755)).To(Succeed())
+			Expect(os.WriteFile(stylePath, []byte("Review."), 0o644)).To(Succeed())
+
+			sess := start("--output-style", "reviewer")
+			Expect(sess.Metadata.OutputStyleSource).To(Equal(&session.OutputStyleSource{Kind: session.OutputStyleShared, Name: "reviewer", Path: stylePath}))
+			Expect(sess.Metadata.HasCustomOutputStyle).To(BeFalse())
+		})
+
+		It("records inline styles as custom", func() {
+			sess := start("--output-style", "Be terse.")
+			Expect(sess.Metadata.OutputStyleSource).To(Equal(&session.OutputStyleSource{Kind: session.OutputStyleCustom}))
+			Expect(sess.Metadata.HasCustomOutputStyle).To(BeTrue())
+		})
+
+		It("records where --output-style-file was read from", func() {
+			Expect(os.WriteFile(filepath.Join(tempDir, "terse.md"), []byte("Be terse."), 0o644)).To(Succeed())
+
+			sess := start("--output-style-file", "terse.md")
+			Expect(sess.Metadata.OutputStyleSource).To(Equal(&session.OutputStyleSource{Kind: session.OutputStyleCustom, Path: filepath.Join(tempDir, "terse.md")}))
+			Expect(sess.Metadata.HasCustomOutputStyle).To(BeTrue())
+		})
+	})
+
+	Describe("--file", func() {
+		start := func(stdin string, args ...string) (string, error) {
+			var out bytes.Buffer
+			rootCmd := cmd.NewRootCmd()
+			rootCmd.SetOut(&out)
+			rootCmd.SetErr(io.Discard)
+			rootCmd.SetIn(strings.NewReader(stdin))
+			rootCmd.SetArgs(append([]string{"--claude-bin", filepath.Join(fakeClaudeDir, "claude"), "start"}, args...))
+			err := rootCmd.Execute()
+			return out.String(), err
+		}
+
+		It("creates the session from a YAML spec on stdin", func() {
+			spec := `name: from-spec
+model: haiku
+context: GH-42
+permissions:
+  allow: ["Bash(npm run test,lint:*)", Read]
+prompt: Summarize the open TODOs
+`
+			_, err := start(spec, "-f", "-")
+			Expect(err).NotTo(HaveOccurred())
+
+			store := session.NewFileStore(clotildeRoot)
+			sess, err := store.Get("from-spec")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sess.Metadata.Context).To(Equal("GH-42"))
+
+			settings, err := store.LoadSettings("from-spec")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(settings.Model).To(Equal("haiku"))
+			Expect(settings.Permissions.Allow).To(Equal([]string{"Bash(npm run test,lint:*)", "Read"}))
+
+			args, err := testutil.ReadClaudeArgs(claudeArgsFile)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(args).To(HaveSuffix("Summarize the open TODOs\n"))
+		})
+
+		It("reads JSON specs from a file and lets flags and the name argument win", func() {
+			specFile := filepath.Join(tempDir, "spec.json")
+			Expect(os.WriteFile(specFile, []byte(`{"name": "ignored", "model": "opus", "effort": "high"}`), 0o644)).To(Succeed())
+
+			out, err := start("", "chosen", "--file", specFile, "--model", "haiku", "--no-launch")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(out).To(Equal("chosen\n"))
+
+			settings, err := session.NewFileStore(clotildeRoot).LoadSettings("chosen")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(settings.Model).To(Equal("haiku"))
+			Expect(settings.EffortLevel).To(Equal("high"))
+		})
+
+		It("rejects unknown fields", func() {
+			_, err := start(`{"name": "typo", "modle": "haiku"}`, "-f", "-")
+			Expect(err).To(MatchError(ContainSubstring("invalid session spec in stdin")))
+			Expect(err).To(MatchError(ContainSubstring("modle")))
+		})
+
+		It("errors instead of offering to resume an existing session", func() {
+			_, err := start("name: twice", "-f", "-")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = start("name: twice", "-f", "-")
+			Expect(err).To(MatchError("session 'twice' already exists"))
+		})
+
+		It("sends the prompt of --no-launch sessions on their first resume", func() {
+			_, err := start("{name: later, prompt: hello}", "-f", "-", "--no-launch")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(claudeArgsFile).NotTo(BeAnExistingFile())
+
+			rootCmd := cmd.NewRootCmd()
+			rootCmd.SetOut(io.Discard)
+			rootCmd.SetErr(io.Discard)
+			rootCmd.SetArgs([]string{"--claude-bin", filepath.Join(fakeClaudeDir, "claude"), "resume", "later"})
+			Expect(rootCmd.Execute()).To(Succeed())
+
+			args, err := testutil.ReadClaudeArgs(claudeArgsFile)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(args).To(HaveSuffix(" hello\n"))
+
+			sess, err := session.NewFileStore(clotildeRoot).Get("later")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sess.Metadata.InitialPrompt).To(BeEmpty())
+		})
+	})
+	Describe("yolo guard rails", func() {
+		start := func(args ...string) error {
+			rootCmd := cmd.NewRootCmd()
+			rootCmd.SetOut(io.Discard)
+			rootCmd.SetErr(io.Discard)
+			rootCmd.SetArgs(append([]string{"--claude-bin", filepath.Join(fakeClaudeDir, "claude"), "start", "--no-launch"}, args...))
+			return rootCmd.Execute()
+		}
+		outside := filepath.Join(os.TempDir(), "elsewhere")
+
+		It("refuses --yolo with --add-dir outside the project (non-TTY)", func() {
+			err := start("risky", "--yolo", "--add-dir", outside)
+			Expect(err).To(MatchError(ContainSubstring("--i-know")))
+			Expect(session.NewFileStore(clotildeRoot).Exists("risky")).To(BeFalse())
+		})
+
+		It("proceeds with --i-know", func() {
+			Expect(start("risky", "--yolo", "--add-dir", outside, "--i-know")).To(Succeed())
+			Expect(session.NewFileStore(clotildeRoot).Exists("risky")).To(BeTrue())
+		})
+
+		It("allows directories inside the project", func() {
+			Expect(start("inside", "--yolo", "--add-dir", "sub/dir")).To(Succeed())
+		})
+
+		It("allows outside directories without bypassPermissions", func() {
+			Expect(start("careful", "--accept-edits", "--add-dir", outside)).To(Succeed())
+		})
+
+		It("applies to bypassPermissions from a profile", func() {
+			cfg := config.NewConfig()
+			cfg.Profiles = map[string]config.Profile{"wild": {PermissionMode: "bypassPermissions"}}
+			Expect(util.WriteJSON(config.GetConfigPath(clotildeRoot), cfg)).To(Succeed())
+
+			Expect(start("risky", "--profile", "wild", "--add-dir", outside)).To(MatchError(ContainSubstring("--i-know")))
+		})
+	})
 })
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fgrehm/clotilde/internal/claude"
+	"github.com/fgrehm/clotilde/internal/errs"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/util"
+)
+
+// statsTimeFormat is how model history timestamps are shown.
+const statsTimeFormat = "2006-01-02 15:04"
+
+// statsSampleBytes is the size over which 'stats --approx' samples a
+// transcript, and roughly how much of it is read.
+const statsSampleBytes = 32 << 20
+
+func newStatsCmd() *cobra.Command {
+	var approx bool
+
+	cmd := &cobra.Command{
+		Use:   "stats <name>",
+		Short: "Show a session's activity and model usage",
+		Long: `Summarize a session's transcripts (including those from before a /clear):
+assistant turns, active time, the share of turns answered by each model
+family, and the history of model switches.
+
+Results are cached per transcript in the session folder and only recomputed
+for transcripts that changed, so large sessions stay fast after the first run.
+With --approx, changed transcripts over 32MB are sampled instead of read
+whole, and the (uncached) figures are estimates.
+
+With --porcelain, print "key<TAB>value" lines in a fixed order that stays
+stable between versions, for scripts (see docs/porcelain.md).`,
+		Example: `  clotilde stats auth-feature
+  clotilde stats auth-feature --approx`,
+		Annotations:       readOnly(),
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: sessionNameCompletion,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+
+			clotildeRoot, err := findClotildeRoot()
+			if err != nil {
+				if projectRootOverride != "" {
+					return err
+				}
+				return errNoSessions()
+			}
+
+			store := session.NewFileStore(clotildeRoot)
+			sess, err := store.Get(name)
+			if err != nil {
+				return errs.NotFound("session '%s' not found", name)
+			}
+
+			homeDir, err := util.HomeDir()
+			if err != nil {
+				return fmt.Errorf("failed to determine home directory: %w", err)
+			}
+
+			perTranscript, err := claude.CachedTranscriptStats(claude.StatsCachePath(clotildeRoot, name), claude.SessionTranscriptPaths(homeDir, clotildeRoot, sess), sampleOver(approx))
+			if err != nil {
+				return fmt.Errorf("failed to read transcripts: %w", err)
+			}
+			var total claude.TranscriptStats
+			for _, stats := range perTranscript {
+				total.Add(stats)
+			}
+
+			if isPorcelain(cmd) {
+				writePorcelainStats(cmd.OutOrStdout(), sess.Name, total)
+				return nil
+			}
+			printSessionStats(cmd.OutOrStdout(), sess.Name, total)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&approx, "approx", false, "Estimate from a sample of very large transcripts instead of reading them whole")
+	addPorcelainFlag(cmd)
+	return cmd
+}
+
+// sampleOver is the transcript size over which stats are sampled: none
+// unless --approx was given.
+func sampleOver(approx bool) int64 {
+	if !approx {
+		return 0
+	}
+	return statsSampleBytes
+}
+
+// printSessionStats prints the summary, the model breakdown by turns (most
+// used first) and the model history.
+func printSessionStats(out io.Writer, name string, stats claude.TranscriptStats) {
+	_, _ = fmt.Fprintf(out, "Session: %s\n", name)
+	if stats.Messages == 0 {
+		_, _ = fmt.Fprintln(out, "No assistant turns yet.")
+		return
+	}
+	approx := ""
+	if stats.Approximate {
+		approx = "~"
+	}
+	_, _ = fmt.Fprintf(out, "Assistant turns: %s%d\n", approx, stats.Messages)
+	_, _ = fmt.Fprintf(out, "Active time: %s%s\n", approx, util.FormatDuration(stats.ActiveTime))
+	_, _ = fmt.Fprintf(out, "Last activity: %s\n", util.FormatRelativeTime(stats.LastActivity))
+
+	models := modelsByTurns(stats)
+	width := 0
+	for _, model := range models {
+		width = max(width, len(model))
+	}
+
+	_, _ = fmt.Fprintln(out, "\nModels (by turns):")
+	for _, model := range models {
+		n := stats.Models[model]
+		_, _ = fmt.Fprintf(out, "  %-*s  %3d%%  %d\n", width, model, n*100/stats.Messages, n)
+	}
+
+	_, _ = fmt.Fprintln(out, "\nModel history:")
+	for _, span := range stats.ModelSpans {
+		_, _ = fmt.Fprintf(out, "  %-*s  %s → %s  %d turn(s)\n", width, span.Model,
+			span.Start.Local().Format(statsTimeFormat), span.End.Local().Format(statsTimeFormat), span.Turns)
+	}
+}
+
+// modelsByTurns returns the model families of stats, most used first.
+func modelsByTurns(stats claude.TranscriptStats) []string {
+	return slices.SortedFunc(maps.Keys(stats.Models), func(a, b string) int {
+		if c := cmp.Compare(stats.Models[b], stats.Models[a]); c != 0 {
+			return c
+		}
+		return cmp.Compare(a, b)
+	})
+}
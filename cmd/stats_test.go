@@ -0,0 +1,87 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/claude"
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/session"
+)
+
+var _ = Describe("Stats Command", func() {
+	var (
+		tempDir      string
+		homeDir      string
+		clotildeRoot string
+		originalWd   string
+		store        session.Store
+	)
+
+	BeforeEach(func() {
+		tempDir = GinkgoT().TempDir()
+		homeDir = filepath.Join(tempDir, "home")
+		GinkgoT().Setenv("HOME", homeDir)
+		GinkgoT().Setenv("XDG_CONFIG_HOME", filepath.Join(tempDir, "xdg"))
+		GinkgoT().Setenv("CLAUDE_CONFIG_DIR", "")
+
+		var err error
+		originalWd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Chdir(tempDir)).To(Succeed())
+
+		Expect(config.EnsureClotildeStructure(tempDir)).To(Succeed())
+		clotildeRoot = filepath.Join(tempDir, config.ClotildeDir)
+		store = session.NewFileStore(clotildeRoot)
+	})
+
+	AfterEach(func() {
+		_ = os.Chdir(originalWd)
+	})
+
+	run := func(args ...string) (string, error) {
+		return runClotilde(append([]string{"stats"}, args...)...)
+	}
+
+	writeTranscript := func(uuid string, models ...string) {
+		var lines []string
+		for _, model := range models {
+			lines = append(lines, `{"type":"assistant","timestamp":"2026-01-05T10:00:00Z","message":{"model":"`+model+`"}}`)
+		}
+		path := claude.TranscriptPath(homeDir, clotildeRoot, uuid)
+		Expect(os.MkdirAll(filepath.Dir(path), 0o755)).To(Succeed())
+		Expect(os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o644)).To(Succeed())
+	}
+
+	It("breaks down turns by model across the session's transcripts", func() {
+		sess := session.NewSession("auth", "uuid-new")
+		sess.Metadata.PreviousSessions = []session.PreviousSession{{SessionID: "uuid-old", Reason: session.RotationClear}}
+		Expect(store.Create(sess)).To(Succeed())
+		writeTranscript("uuid-old", "claude-sonnet-4-5-20250929", "claude-sonnet-4-5-20250929", "claude-sonnet-4-5-20250929")
+		writeTranscript("uuid-new", "claude-opus-4-20250514", "claude-opus-4-20250514")
+
+		out, err := run("auth")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(ContainSubstring("Assistant turns: 5\n"))
+		Expect(out).To(ContainSubstring("Models (by turns):\n  sonnet   60%  3\n  opus     40%  2\n"))
+		Expect(out).To(MatchRegexp(`Model history:\n  sonnet  \S+ \S+ → \S+ \S+  3 turn\(s\)\n  opus    \S+ \S+ → \S+ \S+  2 turn\(s\)\n`))
+		Expect(claude.StatsCachePath(clotildeRoot, "auth")).To(BeAnExistingFile())
+	})
+
+	It("says so when the session has no turns", func() {
+		Expect(store.Create(session.NewSession("empty", "uuid-empty"))).To(Succeed())
+
+		out, err := run("empty")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(Equal("Session: empty\nNo assistant turns yet.\n"))
+	})
+
+	It("fails for a missing session", func() {
+		_, err := run("nope")
+		Expect(err).To(MatchError("session 'nope' not found"))
+	})
+})
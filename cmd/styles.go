@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+
+	"github.com/fgrehm/clotilde/internal/crypt"
+	"github.com/fgrehm/clotilde/internal/errs"
+	"github.com/fgrehm/clotilde/internal/outputstyle"
+	"github.com/fgrehm/clotilde/internal/ui"
+	"github.com/fgrehm/clotilde/internal/util"
+)
+
+func newStylesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "styles",
+		Short: "Work with output styles",
+	}
+	cmd.AddCommand(newStylesPreviewCmd())
+	return cmd
+}
+
+func newStylesPreviewCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "preview <name|file>",
+		Short: "Render an output style and check its frontmatter",
+		Long: `Render an output style's frontmatter and instructions as Claude Code would
+get them, and check the frontmatter: 'name' and 'description' are required,
+'keep-coding-instructions' must be true or false, and other fields are ignored.
+Exits non-zero when the style has problems, so it can run in CI.
+
+The argument is a file path, a session's custom style (clotilde/<session>), or
+the name of a shared style in .claude/output-styles/ or ~/.claude/output-styles/.`,
+		Example: `  clotilde styles preview ./reviewer.md
+  clotilde styles preview reviewer
+  clotilde styles preview clotilde/bugfix-auth`,
+		Annotations: readOnly(),
+		Args:        cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := resolveStyleFile(args[0])
+			if err != nil {
+				return err
+			}
+			content, err := crypt.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("failed to read output style: %w", err)
+			}
+			style, err := outputstyle.ParseStyleFile(string(content))
+			if err != nil {
+				return err
+			}
+			return writeStylePreview(cmd.OutOrStdout(), path, style, isatty.IsTerminal(os.Stdout.Fd()))
+		},
+	}
+}
+
+// resolveStyleFile returns the file to preview for arg: a path, a session's
+// custom style, or a shared style by name.
+func resolveStyleFile(arg string) (string, error) {
+	if util.FileExists(arg) {
+		return arg, nil
+	}
+	if outputstyle.IsBuiltIn(arg) {
+		return "", fmt.Errorf("'%s' is built into Claude Code, there's no file to preview", arg)
+	}
+
+	// Outside a project, only user-level styles can be found by name
+	clotildeRoot, _ := findClotildeRoot()
+	if name, ok := strings.CutPrefix(arg, "clotilde/"); ok && clotildeRoot != "" {
+		if path := outputstyle.GetCustomStylePath(clotildeRoot, name); util.FileExists(path) {
+			return path, nil
+		}
+	}
+	if path := outputstyle.StylePath(clotildeRoot, arg); path != "" {
+		return path, nil
+	}
+	return "", errs.NotFound("output style '%s' not found (not a file, nor in .claude/output-styles/ or ~/.claude/output-styles/)", arg)
+}
+
+// writeStylePreview prints the style's frontmatter, its rendered body and the
+// problems found, which it returns as an error.
+func writeStylePreview(w io.Writer, path string, style *outputstyle.StyleFile, styled bool) error {
+	name := style.Field("name")
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	_, _ = fmt.Fprintf(w, "Output style: %s\n", name)
+	_, _ = fmt.Fprintf(w, "  File: %s\n", path)
+	if description := style.Field("description"); description != "" {
+		_, _ = fmt.Fprintf(w, "  Description: %s\n", description)
+	}
+	if keep := style.Field("keep-coding-instructions"); keep != "" {
+		_, _ = fmt.Fprintf(w, "  Keep coding instructions: %s\n", keep)
+	}
+
+	if style.Body != "" {
+		rendered, err := ui.RenderMarkdown(style.Body, styled)
+		if err != nil {
+			return fmt.Errorf("failed to render output style: %w", err)
+		}
+		_, _ = fmt.Fprint(w, rendered)
+	} else {
+		_, _ = fmt.Fprintln(w)
+	}
+
+	problems := style.Problems()
+	if len(problems) == 0 {
+		_, _ = fmt.Fprintln(w, ui.Success("No problems found"))
+		return nil
+	}
+	for _, problem := range problems {
+		_, _ = fmt.Fprintln(w, ui.Warning(problem))
+	}
+	return fmt.Errorf("output style has %d problem(s)", len(problems))
+}
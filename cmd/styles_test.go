@@ -0,0 +1,111 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/outputstyle"
+)
+
+var _ = Describe("Styles Command", func() {
+	var (
+		tempDir      string
+		clotildeRoot string
+		originalWd   string
+	)
+
+	BeforeEach(func() {
+		tempDir = GinkgoT().TempDir()
+		GinkgoT().Setenv("HOME", filepath.Join(tempDir, "home"))
+		GinkgoT().Setenv("XDG_CONFIG_HOME", filepath.Join(tempDir, "config"))
+
+		var err error
+		originalWd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Chdir(tempDir)).To(Succeed())
+
+		Expect(config.EnsureClotildeStructure(tempDir)).To(Succeed())
+		clotildeRoot = filepath.Join(tempDir, config.ClotildeDir)
+	})
+
+	AfterEach(func() {
+		_ = os.Chdir(originalWd)
+	})
+
+	preview := func(arg string) (string, error) {
+		return runClotilde("styles", "preview", arg)
+	}
+
+	writeStyle := func(path, content string) {
+		Expect(os.MkdirAll(filepath.Dir(path), 0o755)).To(Succeed())
+		Expect(os.WriteFile(path, []byte(content), 0o644)).To(Succeed())
+	}
+
+	It("renders a style file's frontmatter and body", func() {
+		writeStyle(filepath.Join(tempDir, "reviewer.md"), "---\nname: reviewer\ndescription: Reviews code\nkeep-coding-instructions: true\n---\n\n# Reviewer\n\nBe strict.\n")
+
+		out, err := preview("reviewer.md")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(HavePrefix("Output style: reviewer\n  File: reviewer.md\n  Description: Reviews code\n  Keep coding instructions: true\n"))
+		Expect(out).To(ContainSubstring("# Reviewer"))
+		Expect(out).To(ContainSubstring("Be strict."))
+		Expect(out).To(HaveSuffix("No problems found\n"))
+	})
+
+	It("finds shared styles and session styles by name", func() {
+		sharedPath := filepath.Join(tempDir, ".claude", "output-styles", "reviewer.md")
+		writeStyle(sharedPath, "---\nname: reviewer\ndescription: Reviews code\n---\nBe strict.\n")
+		Expect(outputstyle.CreateCustomStyleFile(clotildeRoot, "bugfix", "Be terse.")).To(Succeed())
+
+		out, err := preview("reviewer")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(ContainSubstring("  File: " + sharedPath + "\n"))
+
+		out, err = preview("clotilde/bugfix")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(ContainSubstring("Output style: clotilde/bugfix\n"))
+		Expect(out).To(ContainSubstring("Be terse."))
+	})
+
+	It("finds user-level styles outside a project", func() {
+		userPath := filepath.Join(tempDir, "home", ".claude", "output-styles", "mine.md")
+		writeStyle(userPath, "---\nname: mine\ndescription: Mine\n---\nHi.\n")
+		Expect(os.Chdir(filepath.Join(tempDir, "home"))).To(Succeed())
+
+		out, err := preview("mine")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(ContainSubstring("  File: " + userPath + "\n"))
+	})
+
+	It("lists problems and fails", func() {
+		writeStyle(filepath.Join(tempDir, "broken.md"), "---\nname: broken\nkeep-coding-instructions: yes\ncolor: red\n---\n")
+
+		out, err := preview("broken.md")
+		Expect(err).To(MatchError("output style has 4 problem(s)"))
+		Expect(out).To(ContainSubstring("frontmatter missing required field 'description'"))
+		Expect(out).To(ContainSubstring("'keep-coding-instructions' must be true or false, got 'yes'"))
+		Expect(out).To(ContainSubstring("unknown frontmatter field 'color' (ignored)"))
+		Expect(out).To(ContainSubstring("no instructions after the frontmatter"))
+	})
+
+	It("rejects malformed frontmatter", func() {
+		writeStyle(filepath.Join(tempDir, "unclosed.md"), "---\nname: x\n")
+
+		_, err := preview("unclosed.md")
+		Expect(err).To(MatchError(ContainSubstring("missing closing ---")))
+	})
+
+	It("has nothing to preview for built-in styles", func() {
+		_, err := preview("Explanatory")
+		Expect(err).To(MatchError(ContainSubstring("built into Claude Code")))
+	})
+
+	It("fails for unknown styles", func() {
+		_, err := preview("missing")
+		Expect(err).To(MatchError(ContainSubstring("output style 'missing' not found")))
+	})
+})
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/errs"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/ui"
+)
+
+func newSwitchCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "switch",
+		Short: "Quickly resume one of the most recent sessions",
+		Long: `Show a compact chooser with the most recently used sessions and resume the
+one you pick. Number keys resume a session instantly; arrows/tab move the
+highlight and Enter resumes it.
+
+Designed to be bound to a shell alias or hotkey for fast context switching.`,
+		Example: `  clotilde switch
+  clotilde switch -n 3
+
+  # Bind to Ctrl-O in bash
+  bind -x '"\C-o": clotilde switch'`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			count, _ := cmd.Flags().GetInt("count")
+			if count < 1 || count > ui.MaxSwitcherSessions {
+				return fmt.Errorf("--count must be between 1 and %d", ui.MaxSwitcherSessions)
+			}
+
+			if !isatty.IsTerminal(os.Stdout.Fd()) {
+				return fmt.Errorf("switch needs an interactive terminal (use 'clotilde resume <name>' in scripts)")
+			}
+
+			clotildeRoot, err := config.FindClotildeRoot()
+			if err != nil {
+				return errNoSessions()
+			}
+
+			store := session.NewFileStore(clotildeRoot)
+			sessions, err := store.List()
+			if err != nil {
+				return fmt.Errorf("failed to list sessions: %w", err)
+			}
+			if len(sessions) == 0 {
+				return errs.NotFound("no sessions available")
+			}
+			sortSessionsByLastAccessed(sessions)
+			sessions = sessions[:min(count, len(sessions))]
+
+			selected, err := ui.RunSwitcher(ui.NewSwitcher(sessions))
+			if err != nil {
+				return err
+			}
+			if selected == nil {
+				return nil
+			}
+
+			return resumeSession(cmd.OutOrStdout(), clotildeRoot, selected, store)
+		},
+	}
+
+	cmd.Flags().IntP("count", "n", 5, fmt.Sprintf("How many recent sessions to offer (1-%d)", ui.MaxSwitcherSessions))
+
+	return cmd
+}
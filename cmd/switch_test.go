@@ -0,0 +1,22 @@
+package cmd_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Switch Command", func() {
+	run := func(args ...string) error {
+		_, err := runClotilde(append([]string{"switch"}, args...)...)
+		return err
+	}
+
+	It("rejects counts that don't map to number keys", func() {
+		Expect(run("-n", "0")).To(MatchError(ContainSubstring("between 1 and 9")))
+		Expect(run("--count", "10")).To(MatchError(ContainSubstring("between 1 and 9")))
+	})
+
+	It("requires an interactive terminal", func() {
+		Expect(run()).To(MatchError(ContainSubstring("use 'clotilde resume <name>'")))
+	})
+})
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/errs"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/ui"
+)
+
+func newSyncCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Create the sessions declared in clotilde.yaml that don't exist yet",
+		Long: `Create the standard sessions a project declares in a checked-in
+clotilde.yaml at its root, so everyone on a team gets the same setups.
+
+Sessions that already exist are left alone. New ones are created without
+launching Claude Code, as with 'start --no-launch'; their prompt is sent on
+the first 'clotilde resume'. Incognito sessions are skipped, since they only
+exist while Claude Code runs ('clotilde start <name>' uses their spec).
+
+  sessions:
+    review:
+      model: sonnet
+      permissionMode: plan
+      prompt: Review the changes on this branch against main
+    docs:
+      model: haiku
+      context: keeping docs/ in sync with the code
+
+Each session takes the fields of a 'clotilde start -f' spec.`,
+		Example: `  clotilde sync
+  clotilde sync --dry-run`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clotildeRoot, err := config.FindOrCreateClotildeRoot()
+			if err != nil {
+				return fmt.Errorf("failed to initialize session storage: %w", err)
+			}
+			specs, err := loadSessionsFile(clotildeRoot)
+			if err != nil {
+				return err
+			}
+			if specs == nil {
+				return errs.NotFound("no %s found at %s", config.SessionsFileName, config.SessionsFilePath(clotildeRoot))
+			}
+
+			rules, err := session.LoadNameRules(clotildeRoot)
+			if err != nil {
+				return fmt.Errorf("invalid naming config: %w", err)
+			}
+			store := session.NewFileStore(clotildeRoot)
+			out := cmd.OutOrStdout()
+
+			// Check every spec before creating anything
+			var missing []SessionCreateParams
+			names := make([]string, 0, len(specs))
+			for name := range specs {
+				names = append(names, name)
+			}
+			slices.Sort(names)
+			for _, name := range names {
+				spec := specs[name]
+				if store.Exists(name) {
+					_, _ = fmt.Fprintf(out, "  %s: exists\n", name)
+					continue
+				}
+				if spec.Incognito {
+					_, _ = fmt.Fprintf(out, "  %s: skipped (incognito; run 'clotilde start %s')\n", name, name)
+					continue
+				}
+				if err := rules.Validate(name); err != nil {
+					return fmt.Errorf("invalid session in %s: %w", config.SessionsFileName, err)
+				}
+				params, err := spec.pendingParams()
+				if err != nil {
+					return fmt.Errorf("invalid %s: %w", config.SessionsFileName, err)
+				}
+				if err := confirmYoloDirs(cmd, params); err != nil {
+					return err
+				}
+				missing = append(missing, params)
+			}
+
+			if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+				for _, params := range missing {
+					_, _ = fmt.Fprintf(out, "  %s: would be created\n", params.Name)
+				}
+				return nil
+			}
+
+			for _, params := range missing {
+				if _, err := createSession(params); err != nil {
+					return err
+				}
+				_, _ = fmt.Fprintln(out, ui.Success(fmt.Sprintf("Created session '%s'", params.Name)))
+			}
+			if len(missing) > 0 {
+				_, _ = fmt.Fprintln(out, ui.Info("Start them with 'clotilde resume <name>'"))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().Bool("dry-run", false, "Show which sessions would be created without creating them")
+	registerIKnowFlag(cmd)
+	return cmd
+}
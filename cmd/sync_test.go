@@ -0,0 +1,137 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/claude"
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/errs"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/testutil"
+)
+
+var _ = Describe("Sync Command", func() {
+	var (
+		tempDir        string
+		originalWd     string
+		claudeBin      string
+		claudeArgsFile string
+		store          session.Store
+	)
+
+	BeforeEach(func() {
+		tempDir = GinkgoT().TempDir()
+		GinkgoT().Setenv("XDG_CONFIG_HOME", filepath.Join(tempDir, "xdg"))
+
+		var err error
+		originalWd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Chdir(tempDir)).To(Succeed())
+
+		binDir := filepath.Join(tempDir, "bin")
+		Expect(os.Mkdir(binDir, 0o755)).To(Succeed())
+		claudeBin, claudeArgsFile, err = testutil.CreateFakeClaude(binDir)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(config.EnsureClotildeStructure(tempDir)).To(Succeed())
+		store = session.NewFileStore(filepath.Join(tempDir, config.ClotildeDir))
+		claude.SessionUsedFunc = func(_ string, _ *session.Session) bool { return true }
+
+		Expect(os.WriteFile(filepath.Join(tempDir, "clotilde.yaml"), []byte(`sessions:
+  review:
+    model: sonnet
+    permissionMode: plan
+    prompt: Review this branch
+  docs:
+    context: keeping docs in sync
+  scratch:
+    incognito: true
+`), 0o644)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		_ = os.Chdir(originalWd)
+	})
+
+	run := func(args ...string) (string, error) {
+		return runClotilde(append([]string{"--claude-bin", claudeBin}, args...)...)
+	}
+
+	It("creates missing sessions without launching claude", func() {
+		Expect(store.Create(session.NewSession("docs", "uuid-docs"))).To(Succeed())
+
+		out, err := run("sync")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(ContainSubstring("docs: exists"))
+		Expect(out).To(ContainSubstring("scratch: skipped"))
+		Expect(out).To(ContainSubstring("Created session 'review'"))
+		Expect(claudeArgsFile).NotTo(BeAnExistingFile())
+
+		sess, err := store.Get("review")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sess.Metadata.PendingLaunch).To(BeTrue())
+		Expect(sess.Metadata.InitialPrompt).To(Equal("Review this branch"))
+		settings, err := store.LoadSettings("review")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(settings.Model).To(Equal("sonnet"))
+		Expect(settings.Permissions.DefaultMode).To(Equal("plan"))
+		Expect(store.Exists("scratch")).To(BeFalse())
+
+		out, err = run("sync")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(ContainSubstring("review: exists"))
+		Expect(out).NotTo(ContainSubstring("Created"))
+	})
+
+	It("only reports what it would create with --dry-run", func() {
+		out, err := run("sync", "--dry-run")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(ContainSubstring("docs: would be created"))
+		Expect(out).To(ContainSubstring("review: would be created"))
+		Expect(store.Exists("review")).To(BeFalse())
+	})
+
+	It("uses the spec when starting a declared session that doesn't exist", func() {
+		_, err := run("start", "review")
+		Expect(err).NotTo(HaveOccurred())
+
+		settings, err := store.LoadSettings("review")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(settings.Model).To(Equal("sonnet"))
+
+		args, err := testutil.ReadClaudeArgs(claudeArgsFile)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(args).To(HaveSuffix(" Review this branch\n"))
+	})
+
+	It("lets flags override the spec on start", func() {
+		_, err := run("start", "review", "--model", "haiku", "--no-launch")
+		Expect(err).NotTo(HaveOccurred())
+
+		settings, err := store.LoadSettings("review")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(settings.Model).To(Equal("haiku"))
+		Expect(settings.Permissions.DefaultMode).To(Equal("plan"))
+	})
+
+	It("rejects invalid files before creating anything", func() {
+		Expect(os.WriteFile(filepath.Join(tempDir, "clotilde.yaml"), []byte("sessions:\n  a-ok: {}\n  Bad Name: {}\n"), 0o644)).To(Succeed())
+		_, err := run("sync")
+		Expect(err).To(MatchError(ContainSubstring("invalid session in clotilde.yaml")))
+		Expect(store.Exists("a-ok")).To(BeFalse())
+
+		Expect(os.WriteFile(filepath.Join(tempDir, "clotilde.yaml"), []byte("sessions:\n  review:\n    modle: haiku\n"), 0o644)).To(Succeed())
+		_, err = run("sync")
+		Expect(err).To(MatchError(ContainSubstring("invalid clotilde.yaml")))
+	})
+
+	It("reports a missing clotilde.yaml", func() {
+		Expect(os.Remove(filepath.Join(tempDir, "clotilde.yaml"))).To(Succeed())
+		_, err := run("sync")
+		Expect(errs.ExitCode(err)).To(Equal(errs.ExitNotFound))
+	})
+})
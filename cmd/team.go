@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/team"
+	"github.com/fgrehm/clotilde/internal/util"
+)
+
+// listTeam shares the project's session metadata and prints what every
+// teammate has shared for the project, for 'list --team'.
+func listTeam(cmd *cobra.Command, clotildeRoot string, sessions []*session.Session) error {
+	share, err := team.Open(clotildeRoot)
+	if err != nil {
+		return err
+	}
+	if err := share.Publish(sessions, time.Now()); err != nil {
+		return err
+	}
+	snapshots, err := share.Load()
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	_, _ = fmt.Fprintf(out, "Team sessions for %s (metadata only, transcripts are not shared):\n", share.Project)
+
+	table := tablewriter.NewWriter(out)
+	table.Header("USER", "NAME", "CONTEXT", "LAST USED")
+	for _, snapshot := range snapshots {
+		user := snapshot.User
+		if user == share.User {
+			user += " (you)"
+		}
+		for _, entry := range snapshot.Sessions {
+			name := entry.Name
+			if entry.Parent != "" {
+				name += " (fork of " + entry.Parent + ")"
+			}
+			context := entry.Context
+			if context == "" {
+				context = "-"
+			}
+			_ = table.Append(user, name, context, util.FormatRelativeTime(entry.LastAccessed))
+		}
+	}
+	_ = table.Render()
+	return nil
+}
@@ -0,0 +1,74 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/team"
+	"github.com/fgrehm/clotilde/internal/util"
+)
+
+var _ = Describe("list --team", func() {
+	var (
+		projectDir   string
+		clotildeRoot string
+		sharedDir    string
+		originalWd   string
+	)
+
+	BeforeEach(func() {
+		tempDir := GinkgoT().TempDir()
+		GinkgoT().Setenv("XDG_CONFIG_HOME", filepath.Join(tempDir, "xdg"))
+		sharedDir = filepath.Join(tempDir, "shared")
+
+		var err error
+		originalWd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+
+		projectDir = filepath.Join(tempDir, "api")
+		Expect(config.EnsureClotildeStructure(projectDir)).To(Succeed())
+		Expect(os.Chdir(projectDir)).To(Succeed())
+		clotildeRoot = filepath.Join(projectDir, config.ClotildeDir)
+
+		store := session.NewFileStore(clotildeRoot)
+		mine := session.NewSession("auth-feature", "uuid-1")
+		mine.Metadata.Context = "GH-123"
+		Expect(store.Create(mine)).To(Succeed())
+		Expect(store.Create(session.NewIncognitoSession("private", "uuid-2"))).To(Succeed())
+	})
+
+	AfterEach(func() {
+		_ = os.Chdir(originalWd)
+	})
+
+	run := runClotilde
+
+	It("fails when sharing isn't configured", func() {
+		_, err := run("list", "--team")
+		Expect(err).To(MatchError(team.ErrNotConfigured))
+	})
+
+	It("shares your metadata and lists teammates' sessions", func() {
+		Expect(util.WriteJSON(config.GetConfigPath(clotildeRoot), map[string]any{
+			"team": map[string]string{"dir": sharedDir, "user": "alice"},
+		})).To(Succeed())
+		bob := team.Share{Dir: sharedDir, Project: "api", User: "bob"}
+		Expect(bob.Publish([]*session.Session{session.NewSession("bob-refactor", "uuid-3")}, time.Now())).To(Succeed())
+
+		out, err := run("list", "--team")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(ContainSubstring("metadata only, transcripts are not shared"))
+		Expect(out).To(ContainSubstring("alice (you)"))
+		Expect(out).To(ContainSubstring("auth-feature"))
+		Expect(out).To(ContainSubstring("GH-123"))
+		Expect(out).To(ContainSubstring("bob-refactor"))
+		Expect(out).NotTo(ContainSubstring("private"))
+		Expect(filepath.Join(sharedDir, "api", "alice.json")).To(BeAnExistingFile())
+	})
+})
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/fgrehm/clotilde/internal/config"
+)
+
+// titleFlag is --title on the command being run, nil when it wasn't given.
+var titleFlag *bool
+
+// registerTitleFlag adds --title to commands that launch claude.
+func registerTitleFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("title", false, `Set the terminal title to "claude: <session>" while claude runs (default from terminalTitle config)`)
+}
+
+// readTitleFlag records --title for wantsTerminalTitle. Runs before every
+// command, so a previous command's flag never carries over.
+func readTitleFlag(cmd *cobra.Command) {
+	titleFlag = nil
+	if f := cmd.Flags().Lookup("title"); f != nil && f.Changed {
+		title, _ := cmd.Flags().GetBool("title")
+		titleFlag = &title
+	}
+}
+
+// wantsTerminalTitle reports whether claude's launch sets the terminal title:
+// --title when given, otherwise the terminalTitle config.
+func wantsTerminalTitle(clotildeRoot string) bool {
+	if titleFlag != nil {
+		return *titleFlag
+	}
+	enabled, _ := config.TerminalTitle(clotildeRoot)
+	return enabled
+}
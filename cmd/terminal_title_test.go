@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fgrehm/clotilde/internal/config"
+)
+
+func TestWantsTerminalTitle(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(t.TempDir(), "xdg"))
+	clotildeRoot := t.TempDir()
+	t.Cleanup(func() { titleFlag = nil })
+
+	parse := func(args ...string) {
+		t.Helper()
+		cmd := &cobra.Command{Use: "resume"}
+		registerTitleFlag(cmd)
+		if err := cmd.ParseFlags(args); err != nil {
+			t.Fatal(err)
+		}
+		readTitleFlag(cmd)
+	}
+
+	parse()
+	if wantsTerminalTitle(clotildeRoot) {
+		t.Error("expected no title by default")
+	}
+	parse("--title")
+	if !wantsTerminalTitle(clotildeRoot) {
+		t.Error("expected --title to set the title")
+	}
+
+	if err := os.WriteFile(config.GetConfigPath(clotildeRoot), []byte(`{"terminalTitle": true}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	parse()
+	if !wantsTerminalTitle(clotildeRoot) {
+		t.Error("expected the terminalTitle config to set the title")
+	}
+	parse("--title=false")
+	if wantsTerminalTitle(clotildeRoot) {
+		t.Error("expected --title=false to override the config")
+	}
+}
@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fgrehm/clotilde/internal/claude"
+	"github.com/fgrehm/clotilde/internal/errs"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/util"
+)
+
+// timelineLevels are the sparkline characters for a day's turns, from none to
+// the busiest day shown.
+var timelineLevels = []rune(" ▁▂▃▄▅▆▇█")
+
+// timelineRow is one session's line in the timeline.
+type timelineRow struct {
+	name  string
+	turns []int // Per day, oldest first
+	total int
+}
+
+func newTimelineCmd() *cobra.Command {
+	var all bool
+	var days int
+
+	cmd := &cobra.Command{
+		Use:   "timeline [name] [--all]",
+		Short: "Chart turns per day for one or all sessions",
+		Long: `Show a day-by-day chart of assistant turns, one row per session, read from
+the sessions' transcripts (including those from before a /clear). Each
+column is a day, oldest on the left; the busiest day shown gets a full
+block. Sessions without turns in the period are left out.
+
+Results come from the same per-transcript cache as 'clotilde stats'.`,
+		Example: `  clotilde timeline auth-feature
+  clotilde timeline --all --days 14`,
+		Annotations:       readOnly(),
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: sessionNameCompletion,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if all == (len(args) == 1) {
+				return fmt.Errorf("specify a session name or --all")
+			}
+			if days < 1 {
+				return fmt.Errorf("--days must be at least 1")
+			}
+
+			clotildeRoot, err := findClotildeRoot()
+			if err != nil {
+				if projectRootOverride != "" {
+					return err
+				}
+				return errNoSessions()
+			}
+
+			store := session.NewFileStore(clotildeRoot)
+			var sessions []*session.Session
+			if all {
+				sessions, err = store.List()
+				if err != nil {
+					return fmt.Errorf("failed to list sessions: %w", err)
+				}
+			} else {
+				sess, err := store.Get(args[0])
+				if err != nil {
+					return errs.NotFound("session '%s' not found", args[0])
+				}
+				sessions = []*session.Session{sess}
+			}
+
+			homeDir, err := util.HomeDir()
+			if err != nil {
+				return fmt.Errorf("failed to determine home directory: %w", err)
+			}
+
+			start := timelineStart(time.Now(), days)
+			var rows []timelineRow
+			for _, sess := range sessions {
+				perTranscript, err := claude.CachedTranscriptStats(claude.StatsCachePath(clotildeRoot, sess.Name), claude.SessionTranscriptPaths(homeDir, clotildeRoot, sess), 0)
+				if err != nil {
+					return fmt.Errorf("failed to read transcripts of '%s': %w", sess.Name, err)
+				}
+				var total claude.TranscriptStats
+				for _, stats := range perTranscript {
+					total.Add(stats)
+				}
+				if row := newTimelineRow(sess.Name, total.Days, start, days); row.total > 0 {
+					rows = append(rows, row)
+				}
+			}
+
+			printTimeline(cmd.OutOrStdout(), rows, start, days)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&all, "all", false, "Chart every session in the project")
+	cmd.Flags().IntVar(&days, "days", 28, "Number of days to show, ending today")
+	return cmd
+}
+
+// timelineStart returns the local midnight that starts the first of the last
+// days days, today included.
+func timelineStart(now time.Time, days int) time.Time {
+	y, m, d := now.Date()
+	return time.Date(y, m, d-days+1, 0, 0, 0, 0, now.Location())
+}
+
+// newTimelineRow picks the days shown out of a session's turns per day.
+func newTimelineRow(name string, perDay map[string]int, start time.Time, days int) timelineRow {
+	row := timelineRow{name: name, turns: make([]int, days)}
+	for i := range days {
+		n := perDay[start.AddDate(0, 0, i).Format(claude.DayFormat)]
+		row.turns[i] = n
+		row.total += n
+	}
+	return row
+}
+
+// printTimeline prints the rows busiest first, scaled to the busiest day
+// across all of them, with the first and last dates under the chart.
+func printTimeline(out io.Writer, rows []timelineRow, start time.Time, days int) {
+	if len(rows) == 0 {
+		_, _ = fmt.Fprintf(out, "No activity in the last %d days.\n", days)
+		return
+	}
+
+	slices.SortStableFunc(rows, func(a, b timelineRow) int {
+		return cmp.Compare(b.total, a.total)
+	})
+	width, peak := 0, 0
+	for _, row := range rows {
+		width = max(width, len(row.name))
+		peak = max(peak, slices.Max(row.turns))
+	}
+
+	for _, row := range rows {
+		var chart strings.Builder
+		for _, n := range row.turns {
+			chart.WriteRune(timelineLevel(n, peak))
+		}
+		_, _ = fmt.Fprintf(out, "%-*s  %s  %d\n", width, row.name, chart.String(), row.total)
+	}
+
+	first := start.Format("Jan 2")
+	last := start.AddDate(0, 0, days-1).Format("Jan 2")
+	axis := first
+	if gap := days - len(first) - len(last); gap > 0 {
+		axis += strings.Repeat(" ", gap) + last
+	}
+	_, _ = fmt.Fprintf(out, "%-*s  %s\n", width, "", axis)
+	_, _ = fmt.Fprintf(out, "\nEach column is a day; %c = %d turns.\n", timelineLevels[len(timelineLevels)-1], peak)
+}
+
+// timelineLevel maps a day's turns to a sparkline character. Any activity
+// shows at least the lowest block.
+func timelineLevel(turns, peak int) rune {
+	if turns <= 0 || peak <= 0 {
+		return timelineLevels[0]
+	}
+	top := len(timelineLevels) - 1
+	level := (turns*top + peak - 1) / peak
+	return timelineLevels[min(max(level, 1), top)]
+}
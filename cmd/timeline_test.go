@@ -0,0 +1,104 @@
+package cmd_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/session"
+)
+
+var _ = Describe("Timeline Command", func() {
+	var (
+		tempDir    string
+		originalWd string
+		store      session.Store
+	)
+
+	BeforeEach(func() {
+		tempDir = GinkgoT().TempDir()
+		GinkgoT().Setenv("HOME", filepath.Join(tempDir, "home"))
+		GinkgoT().Setenv("XDG_CONFIG_HOME", filepath.Join(tempDir, "xdg"))
+		GinkgoT().Setenv("CLAUDE_CONFIG_DIR", "")
+
+		var err error
+		originalWd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Chdir(tempDir)).To(Succeed())
+
+		Expect(config.EnsureClotildeStructure(tempDir)).To(Succeed())
+		store = session.NewFileStore(filepath.Join(tempDir, config.ClotildeDir))
+	})
+
+	AfterEach(func() {
+		_ = os.Chdir(originalWd)
+	})
+
+	run := func(args ...string) (string, error) {
+		return runClotilde(append([]string{"timeline"}, args...)...)
+	}
+
+	// createSession creates a session whose transcript has turnsPerDay[i]
+	// assistant turns i days ago.
+	createSession := func(name string, turnsPerDay ...int) {
+		y, m, d := time.Now().Date()
+		noon := time.Date(y, m, d, 12, 0, 0, 0, time.Local)
+		var lines []string
+		for daysAgo := len(turnsPerDay) - 1; daysAgo >= 0; daysAgo-- {
+			for range turnsPerDay[daysAgo] {
+				ts := noon.AddDate(0, 0, -daysAgo).UTC().Format(time.RFC3339)
+				lines = append(lines, fmt.Sprintf(`{"type":"assistant","timestamp":%q,"message":{"model":"claude-sonnet-4-5-20250929"}}`, ts))
+			}
+		}
+		sess := session.NewSession(name, "uuid-"+name)
+		sess.Metadata.TranscriptPath = filepath.Join(tempDir, name+".jsonl")
+		Expect(os.WriteFile(sess.Metadata.TranscriptPath, []byte(strings.Join(lines, "\n")+"\n"), 0o644)).To(Succeed())
+		Expect(store.Create(sess)).To(Succeed())
+	}
+
+	It("charts one session's turns per day, today in the last column", func() {
+		createSession("busy", 4, 0, 2)
+
+		output, err := run("busy", "--days", "7")
+		Expect(err).NotTo(HaveOccurred())
+		lines := strings.Split(output, "\n")
+		Expect(lines[0]).To(Equal("busy      ▄ █  6"))
+		Expect(output).To(ContainSubstring("█ = 4 turns"))
+	})
+
+	It("charts every session with --all, busiest first", func() {
+		createSession("quiet", 1)
+		createSession("busy", 3, 3)
+		createSession("idle")
+
+		output, err := run("--all", "--days", "3")
+		Expect(err).NotTo(HaveOccurred())
+		lines := strings.Split(output, "\n")
+		Expect(lines[0]).To(HavePrefix("busy "))
+		Expect(lines[1]).To(HavePrefix("quiet "))
+		Expect(output).NotTo(ContainSubstring("idle"))
+	})
+
+	It("says so when there was no activity", func() {
+		createSession("idle")
+
+		output, err := run("idle")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(ContainSubstring("No activity in the last 28 days."))
+	})
+
+	It("requires a session name or --all", func() {
+		_, err := run()
+		Expect(err).To(MatchError(ContainSubstring("session name or --all")))
+
+		createSession("busy", 1)
+		_, err = run("busy", "--all")
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fgrehm/clotilde/internal/hooks"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/ui"
+)
+
+func newWhyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "why --session-id <uuid>",
+		Short: "Explain which session the hooks resolve a Claude Code session ID to",
+		Long: `Run the same resolution the SessionStart hook uses for /compact and /clear
+and print each step and why it matched or not:
+
+  1. CLOTILDE_SESSION_NAME, set when clotilde launches claude
+  2. CLOTILDE_SESSION in CLAUDE_ENV_FILE, written by an earlier hook
+  3. The session whose current or earlier UUID is the given one
+
+The first two read this shell's environment, so run it from Claude Code
+(e.g. with '!') to see what the hooks see.`,
+		Example:     `  clotilde why --session-id 3f2a9c1e-8b7d-4e6f-a5c4-1d2e3f4a5b6c`,
+		Annotations: readOnly(),
+		Args:        cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sessionID, _ := cmd.Flags().GetString("session-id")
+			if sessionID == "" {
+				return fmt.Errorf("--session-id is required")
+			}
+
+			clotildeRoot, err := findClotildeRoot()
+			if err != nil {
+				if projectRootOverride != "" {
+					return err
+				}
+				return errNoSessions()
+			}
+			store := session.NewFileStore(clotildeRoot)
+
+			resolution, resolveErr := hooks.ExplainSessionName(os.Getenv, sessionID, store)
+
+			out := cmd.OutOrStdout()
+			for i, step := range resolution.Steps {
+				mark := ui.DimStyle.Render("✗")
+				if step.Matched {
+					mark = ui.SuccessStyle.Render("✓")
+				}
+				_, _ = fmt.Fprintf(out, "%s %d. %s: %s\n", mark, i+1, step.Source, step.Result)
+			}
+			_, _ = fmt.Fprintln(out)
+
+			if resolveErr != nil {
+				return resolveErr
+			}
+			_, _ = fmt.Fprintf(out, "Resolved to '%s' (step %d).\n", resolution.Name, len(resolution.Steps))
+			if !store.Exists(resolution.Name) {
+				_, _ = fmt.Fprintln(out, ui.Warning(fmt.Sprintf("Session '%s' doesn't exist in this project, so the hooks can't update it.", resolution.Name)))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().String("session-id", "", "Claude Code session UUID to resolve (required)")
+	return cmd
+}
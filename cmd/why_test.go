@@ -0,0 +1,88 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/errs"
+	"github.com/fgrehm/clotilde/internal/session"
+)
+
+var _ = Describe("Why Command", func() {
+	var (
+		tempDir    string
+		originalWd string
+		store      session.Store
+	)
+
+	BeforeEach(func() {
+		tempDir = GinkgoT().TempDir()
+		GinkgoT().Setenv("CLOTILDE_SESSION_NAME", "")
+		GinkgoT().Setenv("CLAUDE_ENV_FILE", "")
+
+		var err error
+		originalWd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Chdir(tempDir)).To(Succeed())
+
+		Expect(config.EnsureClotildeStructure(tempDir)).To(Succeed())
+		store = session.NewFileStore(filepath.Join(tempDir, config.ClotildeDir))
+
+		sess := session.NewSession("auth-feature", "uuid-current")
+		sess.Metadata.PreviousSessions = []session.PreviousSession{{SessionID: "uuid-before-clear"}}
+		Expect(store.Create(sess)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		_ = os.Chdir(originalWd)
+	})
+
+	run := func(args ...string) (string, error) {
+		return runClotilde(append([]string{"why"}, args...)...)
+	}
+
+	It("stops at CLOTILDE_SESSION_NAME when set", func() {
+		GinkgoT().Setenv("CLOTILDE_SESSION_NAME", "auth-feature")
+
+		out, err := run("--session-id", "uuid-other")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(ContainSubstring("✓ 1. CLOTILDE_SESSION_NAME env var: set to 'auth-feature'"))
+		Expect(out).NotTo(ContainSubstring("2."))
+		Expect(out).To(ContainSubstring("Resolved to 'auth-feature' (step 1)."))
+	})
+
+	It("falls back to the env file", func() {
+		envFile := filepath.Join(tempDir, "claude.env")
+		Expect(os.WriteFile(envFile, []byte("CLOTILDE_SESSION=gone\n"), 0o644)).To(Succeed())
+		GinkgoT().Setenv("CLAUDE_ENV_FILE", envFile)
+
+		out, err := run("--session-id", "uuid-other")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(ContainSubstring("✗ 1. CLOTILDE_SESSION_NAME env var: not set"))
+		Expect(out).To(ContainSubstring("✓ 2. CLOTILDE_SESSION in CLAUDE_ENV_FILE: 'gone'"))
+		Expect(out).To(ContainSubstring("Session 'gone' doesn't exist in this project"))
+	})
+
+	It("looks up earlier UUIDs in the store", func() {
+		out, err := run("--session-id", "uuid-before-clear")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(ContainSubstring("✗ 2. CLOTILDE_SESSION in CLAUDE_ENV_FILE: CLAUDE_ENV_FILE not set"))
+		Expect(out).To(ContainSubstring("✓ 3. UUID lookup in the session store: uuid-before-clear is an earlier UUID of 'auth-feature'"))
+		Expect(out).To(ContainSubstring("Resolved to 'auth-feature' (step 3)."))
+	})
+
+	It("fails when nothing matches", func() {
+		out, err := run("--session-id", "uuid-unknown")
+		Expect(errs.ExitCode(err)).To(Equal(errs.ExitNotFound))
+		Expect(out).To(ContainSubstring("✗ 3. UUID lookup in the session store: no session found with UUID uuid-unknown"))
+	})
+
+	It("requires --session-id", func() {
+		_, err := run()
+		Expect(err).To(MatchError(ContainSubstring("--session-id is required")))
+	})
+})
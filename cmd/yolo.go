@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/ui"
+)
+
+// yoloMode is the permission mode --yolo stands for.
+const yoloMode = "bypassPermissions"
+
+// yoloMarker flags yolo sessions in plain-text output such as 'list'.
+const yoloMarker = "⚠ yolo"
+
+// registerIKnowFlag adds --i-know to commands that can create yolo sessions.
+func registerIKnowFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("i-know", false, "Skip the confirmation for bypassPermissions with --add-dir outside the project")
+}
+
+// isYolo reports whether a session's settings.json runs it with
+// bypassPermissions. Read on every listing, so edits to the file show up.
+func isYolo(store session.Store, name string) bool {
+	settings, err := store.LoadSettings(name)
+	return err == nil && settings != nil && settings.Permissions.DefaultMode == yoloMode
+}
+
+// yoloSessions returns the names of the yolo sessions among sessions.
+func yoloSessions(store session.Store, sessions []*session.Session) map[string]bool {
+	yolo := make(map[string]bool)
+	for _, sess := range sessions {
+		if isYolo(store, sess.Name) {
+			yolo[sess.Name] = true
+		}
+	}
+	return yolo
+}
+
+// confirmYoloDirs guards creating a session that would run with
+// bypassPermissions and access to directories outside the project: it asks
+// for confirmation in a terminal and refuses otherwise, unless --i-know was
+// given. The permission mode and directories are resolved the same way
+// createSession does (flags over profile over project defaults).
+func confirmYoloDirs(cmd *cobra.Command, params SessionCreateParams) error {
+	if iKnow, _ := cmd.Flags().GetBool("i-know"); iKnow {
+		return nil
+	}
+
+	clotildeRoot, err := config.FindOrCreateClotildeRoot()
+	if err != nil {
+		return fmt.Errorf("failed to initialize session storage: %w", err)
+	}
+
+	profileLayer := settingsLayer{Source: sourceProfile}
+	dirs := params.AdditionalDirs
+	if params.Profile != "" {
+		profiles, err := config.MergedProfiles(clotildeRoot)
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		// An unknown profile is reported by createSession
+		if profile, ok := profiles[params.Profile]; ok {
+			profileLayer = profileSettingsLayer(profile)
+			if len(dirs) == 0 && profile.Permissions != nil {
+				dirs = profile.Permissions.AdditionalDirectories
+			}
+		}
+	}
+	defaultsLayer, err := projectDefaultsLayer(clotildeRoot)
+	if err != nil {
+		return err
+	}
+	resolved := resolveSettings(settingsLayer{Source: sourceFlag, PermissionMode: params.PermissionMode}, profileLayer, defaultsLayer)
+	if resolved.PermissionMode.Value != yoloMode {
+		return nil
+	}
+
+	outside := dirsOutside(config.ProjectRootOf(clotildeRoot), dirs)
+	if len(outside) == 0 {
+		return nil
+	}
+
+	if !isatty.IsTerminal(os.Stdout.Fd()) {
+		return fmt.Errorf("refusing to give a bypassPermissions session access outside the project (%s); pass --i-know to proceed", strings.Join(outside, ", "))
+	}
+	confirmModel := ui.NewConfirm(
+		"Run without permission prompts outside the project?",
+		"This session will run with bypassPermissions and access to:",
+	).WithDetails(outside).WithDestructive()
+	confirmed, err := ui.RunConfirm(confirmModel)
+	if err != nil {
+		return fmt.Errorf("confirmation dialog failed: %w", err)
+	}
+	if !confirmed {
+		return fmt.Errorf("cancelled")
+	}
+	return nil
+}
+
+// dirsOutside returns the directories (relative ones taken from the working
+// directory) that aren't projectRoot or inside it.
+func dirsOutside(projectRoot string, dirs []string) []string {
+	var outside []string
+	for _, dir := range dirs {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			outside = append(outside, dir)
+			continue
+		}
+		rel, err := filepath.Rel(projectRoot, abs)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			outside = append(outside, dir)
+		}
+	}
+	return outside
+}
@@ -0,0 +1,21 @@
+// Package app implements the session operations shared by the CLI commands
+// and the dashboard (resume, delete, fork), so every entry point behaves the
+// same. Callers resolve settings and print; this package changes the store
+// and launches Claude Code.
+package app
+
+import (
+	"path/filepath"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/util"
+)
+
+// SettingsFile returns the session's settings.json path, or "" when it has none.
+func SettingsFile(clotildeRoot, name string) string {
+	path := filepath.Join(config.GetSessionDir(clotildeRoot, name), "settings.json")
+	if !util.FileExists(path) {
+		return ""
+	}
+	return path
+}
@@ -0,0 +1,285 @@
+package app_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/app"
+	"github.com/fgrehm/clotilde/internal/claude"
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/outputstyle"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/testutil"
+)
+
+var _ = Describe("Session operations", func() {
+	var (
+		tempDir      string
+		clotildeRoot string
+		store        session.Store
+	)
+
+	BeforeEach(func() {
+		tempDir = GinkgoT().TempDir()
+		GinkgoT().Setenv("HOME", filepath.Join(tempDir, "home"))
+		Expect(config.EnsureClotildeStructure(tempDir)).To(Succeed())
+		clotildeRoot = filepath.Join(tempDir, config.ClotildeDir)
+		store = session.NewFileStore(clotildeRoot)
+	})
+
+	Describe("ResumeSession", func() {
+		var argsFile string
+
+		BeforeEach(func() {
+			var binary string
+			var err error
+			binary, argsFile, err = testutil.CreateFakeClaude(tempDir)
+			Expect(err).NotTo(HaveOccurred())
+			claude.ClaudeBinaryPathFunc = func() string { return binary }
+			// The fake claude writes no transcript; resuming an empty session would remove it
+			claude.SessionUsedFunc = func(_ string, _ *session.Session) bool { return true }
+		})
+
+		AfterEach(func() {
+			claude.ClaudeBinaryPathFunc = func() string { return "claude" }
+			claude.SessionUsedFunc = claude.DefaultSessionUsed
+		})
+
+		It("records the access and new context, then launches with settings", func() {
+			sess := session.NewSession("work", "uuid-work")
+			Expect(store.Create(sess)).To(Succeed())
+			Expect(store.SaveSettings("work", &session.Settings{Model: "sonnet"})).To(Succeed())
+			before := sess.Metadata.LastAccessed
+
+			err := app.ResumeSession(clotildeRoot, store, sess, app.ResumeOptions{Context: "GH-123", Args: []string{"--debug"}})
+			Expect(err).NotTo(HaveOccurred())
+
+			stored, err := store.Get("work")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stored.Metadata.Context).To(Equal("GH-123"))
+			Expect(stored.Metadata.LastAccessed).To(BeTemporally(">=", before))
+
+			args, err := testutil.ReadClaudeArgs(argsFile)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(args).To(ContainSubstring("--resume uuid-work"))
+			Expect(args).To(ContainSubstring("--settings " + app.SettingsFile(clotildeRoot, "work")))
+			Expect(strings.TrimSpace(args)).To(HaveSuffix("--debug"))
+		})
+
+		It("writes the session's directory for the shell-init wrapper", func() {
+			sess := session.NewSession("work", "uuid-work")
+			Expect(store.Create(sess)).To(Succeed())
+			cdFile := filepath.Join(tempDir, "cd-file")
+			GinkgoT().Setenv(app.ShellCDFileEnv, cdFile)
+
+			Expect(app.ResumeSession(clotildeRoot, store, sess, app.ResumeOptions{})).To(Succeed())
+
+			dir, err := os.ReadFile(cdFile)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(strings.TrimSpace(string(dir))).To(Equal(tempDir))
+			_, set := os.LookupEnv(app.ShellCDFileEnv)
+			Expect(set).To(BeFalse())
+		})
+	})
+
+	Describe("DeleteSession", func() {
+		It("removes transcripts and detaches forks", func() {
+			transcript := filepath.Join(tempDir, "uuid-parent.jsonl")
+			Expect(os.WriteFile(transcript, []byte("{}\n"), 0o644)).To(Succeed())
+			parent := session.NewSession("parent", "uuid-parent")
+			parent.Metadata.TranscriptPath = transcript
+			Expect(store.Create(parent)).To(Succeed())
+
+			fork := session.NewSession("child", "uuid-child")
+			fork.Metadata.IsForkedSession = true
+			fork.Metadata.ParentSession = "parent"
+			Expect(store.Create(fork)).To(Succeed())
+
+			result, err := app.DeleteSession(clotildeRoot, store, parent)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Transcripts).To(ConsistOf(transcript))
+			Expect(result.Detached).To(ConsistOf("child"))
+			Expect(result.Warnings).To(BeEmpty())
+
+			Expect(store.Exists("parent")).To(BeFalse())
+			Expect(transcript).NotTo(BeAnExistingFile())
+			child, err := store.Get("child")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(child.Metadata.IsForkedSession).To(BeFalse())
+			Expect(child.Metadata.ParentSession).To(BeEmpty())
+		})
+
+		It("removes the transcripts of previous sessions too", func() {
+			var transcripts []string
+			sess := session.NewSession("cleared", "uuid-3")
+			for _, id := range []string{"uuid-1", "uuid-2", "uuid-3"} {
+				transcript := filepath.Join(tempDir, id+".jsonl")
+				Expect(os.WriteFile(transcript, []byte("{}\n"), 0o644)).To(Succeed())
+				transcripts = append(transcripts, transcript)
+				if id != "uuid-3" {
+					sess.Metadata.PreviousSessions = append(sess.Metadata.PreviousSessions, session.PreviousSession{SessionID: id, TranscriptPath: transcript})
+				}
+			}
+			sess.Metadata.TranscriptPath = transcripts[2]
+			Expect(store.Create(sess)).To(Succeed())
+
+			result, err := app.DeleteSession(clotildeRoot, store, sess)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Transcripts).To(ConsistOf(transcripts))
+			Expect(store.Exists("cleared")).To(BeFalse())
+		})
+
+		It("keeps the session and reports every file it couldn't remove", func() {
+			removable := filepath.Join(tempDir, "uuid-1.jsonl")
+			Expect(os.WriteFile(removable, []byte("{}\n"), 0o644)).To(Succeed())
+			// A non-empty directory can't be removed, even as root
+			stuck := filepath.Join(tempDir, "uuid-2.jsonl")
+			Expect(os.MkdirAll(filepath.Join(stuck, "child"), 0o755)).To(Succeed())
+
+			sess := session.NewSession("stuck", "uuid-2")
+			sess.Metadata.TranscriptPath = stuck
+			sess.Metadata.PreviousSessions = []session.PreviousSession{{SessionID: "uuid-1", TranscriptPath: removable}}
+			Expect(store.Create(sess)).To(Succeed())
+
+			result, err := app.DeleteSession(clotildeRoot, store, sess)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("the session was kept"))
+			Expect(err.Error()).To(ContainSubstring("failed to delete 1 file(s)"))
+			Expect(err.Error()).To(ContainSubstring(stuck))
+			Expect(result.Transcripts).To(ConsistOf(removable))
+			Expect(store.Exists("stuck")).To(BeTrue())
+		})
+
+		It("keeps a custom output style other sessions still use", func() {
+			owner := session.NewSession("owner", "uuid-owner")
+			owner.Metadata.HasCustomOutputStyle = true
+			Expect(store.Create(owner)).To(Succeed())
+			Expect(outputstyle.CreateCustomStyleFile(clotildeRoot, "owner", "Be terse.")).To(Succeed())
+			Expect(store.SaveSettings("owner", &session.Settings{OutputStyle: outputstyle.GetCustomStyleReference("owner")})).To(Succeed())
+			Expect(store.Create(session.NewSession("renamed", "uuid-renamed"))).To(Succeed())
+			Expect(store.SaveSettings("renamed", &session.Settings{OutputStyle: outputstyle.GetCustomStyleReference("owner")})).To(Succeed())
+
+			result, err := app.DeleteSession(clotildeRoot, store, owner)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.StyleUsers).To(ConsistOf("renamed"))
+			Expect(outputstyle.GetCustomStylePath(clotildeRoot, "owner")).To(BeAnExistingFile())
+
+			renamed, err := store.Get("renamed")
+			Expect(err).NotTo(HaveOccurred())
+			result, err = app.DeleteSession(clotildeRoot, store, renamed)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.StyleUsers).To(BeEmpty())
+		})
+
+		It("deletes a custom output style no other session uses", func() {
+			owner := session.NewSession("owner", "uuid-owner")
+			owner.Metadata.HasCustomOutputStyle = true
+			Expect(store.Create(owner)).To(Succeed())
+			Expect(outputstyle.CreateCustomStyleFile(clotildeRoot, "owner", "Be terse.")).To(Succeed())
+			Expect(store.SaveSettings("owner", &session.Settings{OutputStyle: outputstyle.GetCustomStyleReference("owner")})).To(Succeed())
+			Expect(store.Create(session.NewSession("other", "uuid-other"))).To(Succeed())
+
+			result, err := app.DeleteSession(clotildeRoot, store, owner)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.StyleUsers).To(BeEmpty())
+			Expect(outputstyle.GetCustomStylePath(clotildeRoot, "owner")).NotTo(BeAnExistingFile())
+		})
+	})
+
+	Describe("ForkSession", func() {
+		var parent *session.Session
+
+		BeforeEach(func() {
+			parent = session.NewSession("parent", "uuid-parent")
+			parent.Metadata.Context = "GH-123"
+			Expect(store.Create(parent)).To(Succeed())
+		})
+
+		It("copies the parent's settings, context and env", func() {
+			Expect(store.SaveSettings("parent", &session.Settings{Model: "opus"})).To(Succeed())
+			Expect(store.SaveEnv("parent", map[string]string{"TOKEN": "secret"})).To(Succeed())
+
+			fork, err := app.ForkSession(clotildeRoot, store, parent, "child", app.ForkOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fork.Metadata.SessionID).NotTo(BeEmpty())
+			Expect(fork.Metadata.IsForkedSession).To(BeTrue())
+			Expect(fork.Metadata.ParentSession).To(Equal("parent"))
+			Expect(fork.Metadata.Context).To(Equal("GH-123"))
+
+			settings, err := store.LoadSettings("child")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(settings.Model).To(Equal("opus"))
+			env, err := store.LoadEnv("child")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env).To(Equal(map[string]string{"TOKEN": "secret"}))
+		})
+
+		It("gives the fork its own copy of a custom output style", func() {
+			Expect(outputstyle.CreateCustomStyleFile(clotildeRoot, "parent", "Be terse.")).To(Succeed())
+			Expect(store.SaveSettings("parent", &session.Settings{OutputStyle: outputstyle.GetCustomStyleReference("parent")})).To(Succeed())
+
+			fork, err := app.ForkSession(clotildeRoot, store, parent, "child", app.ForkOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fork.Metadata.HasCustomOutputStyle).To(BeTrue())
+
+			settings, err := store.LoadSettings("child")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(settings.OutputStyle).To(Equal(outputstyle.GetCustomStyleReference("child")))
+			content, err := os.ReadFile(outputstyle.GetCustomStylePath(clotildeRoot, "child"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(ContainSubstring("Be terse."))
+		})
+
+		It("records the parent's style provenance on the fork", func() {
+			parent.Metadata.OutputStyleSource = &session.OutputStyleSource{Kind: session.OutputStyleCustom, Path: "/tmp/terse.md"}
+			parent.Metadata.HasCustomOutputStyle = true
+			Expect(store.Update(parent)).To(Succeed())
+			Expect(outputstyle.CreateCustomStyleFile(clotildeRoot, "parent", "Be terse.")).To(Succeed())
+			Expect(store.SaveSettings("parent", &session.Settings{OutputStyle: outputstyle.GetCustomStyleReference("parent")})).To(Succeed())
+
+			fork, err := app.ForkSession(clotildeRoot, store, parent, "child", app.ForkOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fork.Metadata.OutputStyleSource).To(Equal(parent.Metadata.OutputStyleSource))
+			Expect(fork.Metadata.OutputStyleSource).NotTo(BeIdenticalTo(parent.Metadata.OutputStyleSource))
+			Expect(outputstyle.GetCustomStylePath(clotildeRoot, "child")).To(BeAnExistingFile())
+		})
+
+		It("only references a shared output style", func() {
+			parent.Metadata.OutputStyleSource = &session.OutputStyleSource{Kind: session.OutputStyleShared, Name: "reviewer", Path: "/tmp/reviewer.md"}
+			Expect(store.Update(parent)).To(Succeed())
+			Expect(store.SaveSettings("parent", &session.Settings{OutputStyle: "reviewer"})).To(Succeed())
+
+			fork, err := app.ForkSession(clotildeRoot, store, parent, "child", app.ForkOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fork.Metadata.HasCustomOutputStyle).To(BeFalse())
+			Expect(fork.Metadata.OutputStyleSource.Kind).To(Equal(session.OutputStyleShared))
+			Expect(outputstyle.GetCustomStylePath(clotildeRoot, "child")).NotTo(BeAnExistingFile())
+
+			settings, err := store.LoadSettings("child")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(settings.OutputStyle).To(Equal("reviewer"))
+			stored, err := store.Get("child")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stored.Metadata.OutputStyleSource.Path).To(Equal("/tmp/reviewer.md"))
+		})
+
+		It("leaves the context out with NoParentContext", func() {
+			fork, err := app.ForkSession(clotildeRoot, store, parent, "child", app.ForkOptions{NoParentContext: true})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fork.Metadata.Context).To(BeEmpty())
+		})
+
+		It("refuses to fork an incognito session", func() {
+			ghost := session.NewIncognitoSession("ghost", "uuid-ghost")
+			Expect(store.Create(ghost)).To(Succeed())
+
+			_, err := app.ForkSession(clotildeRoot, store, ghost, "child", app.ForkOptions{})
+			Expect(err).To(MatchError(ContainSubstring("cannot fork from incognito session")))
+			Expect(store.Exists("child")).To(BeFalse())
+		})
+	})
+})
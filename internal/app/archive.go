@@ -0,0 +1,37 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/fgrehm/clotilde/internal/session"
+)
+
+// CheckArchive reports why sess can't be archived (or, with archived false,
+// unarchived): Claude Code is running in it, or it isn't archived.
+func CheckArchive(sess *session.Session, archived bool) error {
+	if sess.Status() == session.StatusActive {
+		return fmt.Errorf("session '%s' is active; wait for claude to exit", sess.Name)
+	}
+	if !archived && sess.Status() != session.StatusArchived {
+		return fmt.Errorf("session '%s' isn't archived (status: %s)", sess.Name, sess.Status())
+	}
+	return nil
+}
+
+// ArchiveSession moves sess to the archived status, or with archived false
+// back to idle. Unarchiving counts as a use, so auto-archive doesn't put the
+// session back right away.
+func ArchiveSession(store session.Store, sess *session.Session, archived bool) error {
+	if err := CheckArchive(sess, archived); err != nil {
+		return err
+	}
+	sess.Metadata.Status = session.StatusArchived
+	if !archived {
+		sess.Metadata.Status = session.StatusIdle
+		sess.UpdateLastAccessed()
+	}
+	if err := store.Update(sess); err != nil {
+		return fmt.Errorf("failed to update session '%s': %w", sess.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/fgrehm/clotilde/internal/errs"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/util"
+)
+
+// CreateOptions configure a session created without launching Claude Code.
+type CreateOptions struct {
+	Context   string            // e.g. "working on ticket GH-123"
+	ExpiresAt time.Time         // zero means the session never expires
+	Settings  *session.Settings // written to the session's settings.json when set
+}
+
+// CreateSession creates name without launching Claude Code, like
+// 'clotilde start --no-launch': it starts on its first resume. The name must
+// follow the project's naming rules.
+func CreateSession(clotildeRoot string, store session.Store, name string, opts CreateOptions) (*session.Session, error) {
+	rules, err := session.LoadNameRules(clotildeRoot)
+	if err != nil {
+		return nil, fmt.Errorf("invalid naming config: %w", err)
+	}
+	if err := rules.Validate(name); err != nil {
+		return nil, err
+	}
+	if store.Exists(name) {
+		return nil, errs.AlreadyExists("session '%s' already exists", name)
+	}
+
+	sess := session.NewSession(name, util.GenerateUUID())
+	sess.Metadata.Context = opts.Context
+	sess.Metadata.ExpiresAt = opts.ExpiresAt
+	sess.Metadata.PendingLaunch = true
+	if err := store.Create(sess); err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	if opts.Settings != nil {
+		if err := store.SaveSettings(name, opts.Settings); err != nil {
+			return nil, fmt.Errorf("failed to save settings: %w", err)
+		}
+	}
+	return sess, nil
+}
@@ -0,0 +1,98 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/fgrehm/clotilde/internal/claude"
+	"github.com/fgrehm/clotilde/internal/outputstyle"
+	"github.com/fgrehm/clotilde/internal/session"
+)
+
+// DeleteResult reports what DeleteSession removed.
+type DeleteResult struct {
+	Transcripts []string // Transcript file paths that were deleted
+	AgentLogs   []string // Agent log file paths that were deleted
+	Detached    []string // Forks turned into regular sessions
+	StyleUsers  []string // Other sessions using its custom output style, which was kept
+	Warnings    []string // Cleanup that failed without stopping the deletion
+}
+
+// DeleteSession deletes a session folder, the Claude Code data of its current
+// and previous (from /clear) UUIDs, and its custom output style unless other
+// sessions use it too. Its forks are detached so none is left pointing at a
+// parent that no longer exists. When any Claude Code file can't be removed,
+// the session is kept and the error lists every failure.
+func DeleteSession(clotildeRoot string, store session.Store, sess *session.Session) (*DeleteResult, error) {
+	result := &DeleteResult{}
+
+	// Previous sessions come from /clear operations, and defensively from /compact
+	deleted, err := claude.DeleteAllSessionData(clotildeRoot, claude.SessionDataOf(sess))
+	result.add(deleted)
+	if err != nil {
+		// Keep the session so deleting it again retries what's left, instead
+		// of leaving Claude Code files nothing points at
+		return result, fmt.Errorf("failed to delete Claude data of '%s', the session was kept: %w", sess.Name, err)
+	}
+
+	if err := store.Delete(sess.Name); err != nil {
+		return result, fmt.Errorf("failed to delete session: %w", err)
+	}
+
+	if sess.Metadata.OwnsOutputStyle() {
+		result.deleteStyle(clotildeRoot, store, sess.Name)
+	}
+
+	result.detachForks(store, sess.Name)
+	return result, nil
+}
+
+func (r *DeleteResult) add(deleted *claude.DeletedFiles) {
+	r.Transcripts = append(r.Transcripts, deleted.Transcript...)
+	r.AgentLogs = append(r.AgentLogs, deleted.AgentLogs...)
+}
+
+// deleteStyle deletes the custom output style of name unless other sessions
+// reference it. When they can't be checked, the file is kept.
+func (r *DeleteResult) deleteStyle(clotildeRoot string, store session.Store, name string) {
+	users, err := outputstyle.CustomStyleUsers(store, name)
+	if err != nil {
+		r.Warnings = append(r.Warnings, fmt.Sprintf("Kept output style file, failed to check other sessions using it: %v", err))
+		return
+	}
+	if len(users) > 0 {
+		r.StyleUsers = users
+		return
+	}
+	if err := outputstyle.DeleteCustomStyleFile(clotildeRoot, name); err != nil {
+		r.Warnings = append(r.Warnings, fmt.Sprintf("Failed to delete output style file: %v", err))
+	}
+}
+
+// detachForks turns the forks of parent into regular sessions.
+func (r *DeleteResult) detachForks(store session.Store, parent string) {
+	sessions, err := store.List()
+	if err != nil {
+		r.Warnings = append(r.Warnings, fmt.Sprintf("Failed to detach forks of '%s': %v", parent, err))
+		return
+	}
+	var forks []*session.Session
+	for _, s := range sessions {
+		if s.Metadata.IsForkedSession && s.Metadata.ParentSession == parent {
+			forks = append(forks, s)
+		}
+	}
+	// Most recently used first, as elsewhere fork lists are shown
+	slices.SortFunc(forks, func(a, b *session.Session) int {
+		return b.Metadata.LastAccessed.Compare(a.Metadata.LastAccessed)
+	})
+	for _, fork := range forks {
+		fork.Metadata.IsForkedSession = false
+		fork.Metadata.ParentSession = ""
+		if err := store.Update(fork); err != nil {
+			r.Warnings = append(r.Warnings, fmt.Sprintf("Failed to detach fork '%s': %v", fork.Name, err))
+			continue
+		}
+		r.Detached = append(r.Detached, fork.Name)
+	}
+}
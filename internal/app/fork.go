@@ -0,0 +1,141 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/crypt"
+	"github.com/fgrehm/clotilde/internal/outputstyle"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/util"
+)
+
+// ForkOptions holds the metadata of a new fork.
+type ForkOptions struct {
+	Incognito       bool
+	Context         string // Defaults to the parent's context unless NoParentContext
+	NoParentContext bool
+	ExpiresAt       time.Time
+	Pending         bool // Claude Code is not launched now; the fork starts on resume
+}
+
+// ForkSession creates forkName from parent with a pre-assigned UUID (passed
+// to claude via --session-id) and copies the parent's settings.json, custom
+// output style and env file. The fork is not launched.
+func ForkSession(clotildeRoot string, store session.Store, parent *session.Session, forkName string, opts ForkOptions) (*session.Session, error) {
+	if parent.Metadata.IsIncognito {
+		return nil, fmt.Errorf("cannot fork from incognito session '%s' (it will auto-delete when you exit)", parent.Name)
+	}
+
+	forkUUID := util.GenerateUUID()
+	var fork *session.Session
+	if opts.Incognito {
+		fork = session.NewIncognitoSession(forkName, forkUUID)
+	} else {
+		fork = session.NewSession(forkName, forkUUID)
+	}
+	fork.Metadata.IsForkedSession = true
+	fork.Metadata.ParentSession = parent.Name
+	fork.Metadata.ExpiresAt = opts.ExpiresAt
+	fork.Metadata.PendingLaunch = opts.Pending
+	fork.Metadata.NoParentContext = opts.NoParentContext
+	fork.Metadata.Launcher = parent.Metadata.Launcher
+
+	if opts.Context != "" {
+		fork.Metadata.Context = opts.Context
+	} else if !opts.NoParentContext {
+		fork.Metadata.Context = parent.Metadata.Context
+	}
+
+	if err := store.Create(fork); err != nil {
+		return nil, fmt.Errorf("failed to create fork: %w", err)
+	}
+
+	if err := copyForkSettings(clotildeRoot, store, parent, fork); err != nil {
+		return nil, err
+	}
+
+	// The parent's env file travels with the fork
+	parentEnv, err := store.LoadEnv(parent.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load parent env: %w", err)
+	}
+	if err := store.SaveEnv(forkName, parentEnv); err != nil {
+		return nil, fmt.Errorf("failed to copy env: %w", err)
+	}
+
+	return fork, nil
+}
+
+// copyForkSettings copies the parent's settings.json to the fork. A custom
+// output style gets its own copy, so editing one session's style doesn't
+// change the other's; built-in and shared styles are only referenced. The
+// fork records the parent's style provenance.
+func copyForkSettings(clotildeRoot string, store session.Store, parent *session.Session, fork *session.Session) error {
+	parentSettingsPath := filepath.Join(config.GetSessionDir(clotildeRoot, parent.Name), "settings.json")
+	if !util.FileExists(parentSettingsPath) {
+		return nil
+	}
+	forkSettingsPath := filepath.Join(config.GetSessionDir(clotildeRoot, fork.Name), "settings.json")
+	if err := util.CopyFile(parentSettingsPath, forkSettingsPath); err != nil {
+		return fmt.Errorf("failed to copy settings: %w", err)
+	}
+
+	if source := parent.Metadata.OutputStyleSource; source != nil {
+		copied := *source
+		fork.Metadata.OutputStyleSource = &copied
+		if !parent.Metadata.OwnsOutputStyle() {
+			if err := store.Update(fork); err != nil {
+				return fmt.Errorf("failed to update fork metadata: %w", err)
+			}
+			return nil
+		}
+	}
+
+	data, err := os.ReadFile(parentSettingsPath)
+	if err != nil {
+		return nil
+	}
+	var settings session.Settings
+	if err := json.Unmarshal(data, &settings); err != nil || !strings.HasPrefix(settings.OutputStyle, "clotilde/") {
+		return nil
+	}
+	parentStylePath := outputstyle.GetCustomStylePath(clotildeRoot, strings.TrimPrefix(settings.OutputStyle, "clotilde/"))
+	styleContent, err := crypt.ReadFile(parentStylePath)
+	if err != nil {
+		return nil
+	}
+
+	// Drop the parent's frontmatter; the fork's file gets its own
+	content := string(styleContent)
+	if parts := strings.SplitN(content, "---", 3); len(parts) == 3 {
+		content = strings.TrimSpace(parts[2])
+	}
+	if err := outputstyle.CreateCustomStyleFile(clotildeRoot, fork.Name, content); err != nil {
+		return fmt.Errorf("failed to copy custom output style: %w", err)
+	}
+
+	// Point the already-copied settings at the fork's style
+	settings.OutputStyle = outputstyle.GetCustomStyleReference(fork.Name)
+	updatedData, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal fork settings: %w", err)
+	}
+	if err := os.WriteFile(forkSettingsPath, updatedData, 0o644); err != nil {
+		return fmt.Errorf("failed to write fork settings: %w", err)
+	}
+
+	fork.Metadata.HasCustomOutputStyle = true
+	if fork.Metadata.OutputStyleSource == nil {
+		fork.Metadata.OutputStyleSource = &session.OutputStyleSource{Kind: session.OutputStyleCustom}
+	}
+	if err := store.Update(fork); err != nil {
+		return fmt.Errorf("failed to update fork metadata: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package app
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fgrehm/clotilde/internal/claude"
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/snippet"
+)
+
+// ResumeOptions adjust how a session is resumed.
+type ResumeOptions struct {
+	Context  string   // Replaces the session's context when set
+	Args     []string // Extra claude CLI args, e.g. resolved settings
+	Snippets string   // Rendered snippets (snippet.Resolve) injected into this launch only
+	Claude   string   // The claude executable; claude.ClaudeBinaryPathFunc() when empty
+}
+
+// ResumeSession records the access (and new context) and launches Claude Code
+// on the session with its settings.json, if any.
+func ResumeSession(clotildeRoot string, store session.Store, sess *session.Session, opts ResumeOptions) error {
+	if opts.Context != "" {
+		sess.Metadata.Context = opts.Context
+	}
+	sess.UpdateLastAccessed()
+	if err := store.Update(sess); err != nil {
+		return fmt.Errorf("failed to update session: %w", err)
+	}
+
+	// Taken out of the environment so clotilde runs inside Claude Code don't
+	// write to the wrapper's file
+	cdFile := os.Getenv(ShellCDFileEnv)
+	_ = os.Unsetenv(ShellCDFileEnv)
+
+	if opts.Snippets != "" {
+		sessionDir := config.GetSessionDir(clotildeRoot, sess.Name)
+		if err := snippet.WritePending(sessionDir, opts.Snippets); err != nil {
+			return err
+		}
+		defer snippet.ClearPending(sessionDir)
+	}
+
+	err := claude.Resume(clotildeRoot, sess, SettingsFile(clotildeRoot, sess.Name), opts.Args, opts.Claude)
+	if cdFile != "" {
+		// Best effort: the worst outcome is the shell staying where it was
+		_ = os.WriteFile(cdFile, []byte(config.ProjectRootOf(clotildeRoot)+"\n"), 0o600)
+	}
+	return err
+}
+
+// ShellCDFileEnv names a file the 'clotilde shell-init' wrapper creates for
+// each run. After Claude Code exits, ResumeSession writes the session's
+// directory there so the wrapper can cd the calling shell into it.
+const ShellCDFileEnv = "CLOTILDE_CD_FILE"
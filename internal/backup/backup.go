@@ -0,0 +1,805 @@
+// Package backup creates and restores full backups of a project's clotilde
+// sessions, including the Claude Code transcripts they reference.
+package backup
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fgrehm/clotilde/internal/claude"
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/crypt"
+	"github.com/fgrehm/clotilde/internal/outputstyle"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/util"
+)
+
+const (
+	// ManifestFile is the name of the manifest at the top of every backup.
+	ManifestFile = "manifest.json"
+
+	formatVersion     = 1
+	clotildeSubdir    = "clotilde"
+	transcriptsSubdir = "transcripts"
+	stylesSubdir      = "output-styles"
+)
+
+// Manifest describes the contents of a backup.
+type Manifest struct {
+	Version     int       `json:"version"`
+	Created     time.Time `json:"created"`
+	ProjectRoot string    `json:"projectRoot"`
+	Sessions    []string  `json:"sessions"`
+	Transcripts int       `json:"transcripts"`
+}
+
+// RestoredSession records where a session from the backup ended up.
+type RestoredSession struct {
+	From string // Name in the backup
+	Name string // Name in the target project (differs when renamed on conflict)
+}
+
+// Renamed reports whether the session was renamed to avoid a conflict.
+func (r RestoredSession) Renamed() bool {
+	return r.From != r.Name
+}
+
+// RestoreResult summarizes a restore.
+type RestoreResult struct {
+	Restored       []RestoredSession
+	Merged         []RestoredSession // Sessions merged into the existing copy named Name
+	Overwritten    []string          // Target sessions replaced by the backup's
+	Skipped        []string          // Conflicting sessions left as they were
+	Transcripts    int               // Transcript files copied into Claude's project directory
+	ConfigRestored bool              // Whether config.json was copied (only when the target had none)
+}
+
+// Progress receives per-session updates from Create and Restore. Matches
+// ui.ProgressReporter without tying this package to the UI.
+type Progress interface {
+	Start(item string)
+	Done(item, detail string)
+	Fail(item string, err error)
+}
+
+// noProgress discards updates when the caller passes a nil Progress
+type noProgress struct{}
+
+func (noProgress) Start(string)        {}
+func (noProgress) Done(string, string) {}
+func (noProgress) Fail(string, error)  {}
+
+// IsArchivePath reports whether path names a tarball (.tar, .tar.gz, .tgz)
+// rather than a directory.
+func IsArchivePath(path string) bool {
+	return strings.HasSuffix(path, ".tar") || strings.HasSuffix(path, ".tar.gz") || strings.HasSuffix(path, ".tgz")
+}
+
+// Create writes a backup of the clotilde root, the transcripts referenced by its
+// sessions, and their custom output styles to dest. dest is written as a tarball
+// when IsArchivePath(dest) is true, otherwise as a directory. dest must not exist.
+// progress (may be nil) is notified as each session is backed up.
+func Create(clotildeRoot, homeDir, dest string, progress Progress) (*Manifest, error) {
+	if progress == nil {
+		progress = noProgress{}
+	}
+	if _, err := os.Stat(dest); err == nil {
+		return nil, fmt.Errorf("%s already exists", dest)
+	}
+	key, err := crypt.ProjectKey(clotildeRoot)
+	if err != nil {
+		return nil, err
+	}
+	if key != nil && !IsArchivePath(dest) {
+		return nil, fmt.Errorf("encryption is enabled, so backups must be written as an archive (.tar.gz, .tgz or .tar)")
+	}
+
+	stage := dest
+	if IsArchivePath(dest) {
+		tmp, err := os.MkdirTemp("", "clotilde-backup-")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create staging directory: %w", err)
+		}
+		defer func() { _ = os.RemoveAll(tmp) }()
+		stage = tmp
+	}
+
+	if err := copyTree(clotildeRoot, filepath.Join(stage, clotildeSubdir)); err != nil {
+		return nil, fmt.Errorf("failed to copy clotilde root: %w", err)
+	}
+
+	sessions, err := session.NewFileStore(clotildeRoot).List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	manifest := &Manifest{
+		Version:     formatVersion,
+		Created:     time.Now(),
+		ProjectRoot: config.ProjectRootOf(clotildeRoot),
+		Sessions:    []string{},
+	}
+
+	for _, sess := range sessions {
+		manifest.Sessions = append(manifest.Sessions, sess.Name)
+
+		progress.Start(sess.Name)
+		copied, err := backupSession(stage, clotildeRoot, homeDir, sess)
+		if err != nil {
+			progress.Fail(sess.Name, err)
+			return nil, err
+		}
+		manifest.Transcripts += copied
+		progress.Done(sess.Name, fmt.Sprintf("%d transcript(s)", copied))
+	}
+
+	if err := util.WriteJSON(filepath.Join(stage, ManifestFile), manifest); err != nil {
+		return nil, fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	if stage != dest {
+		if err := writeArchive(stage, dest); err != nil {
+			_ = os.Remove(dest)
+			return nil, fmt.Errorf("failed to write archive: %w", err)
+		}
+		if key != nil {
+			if err := crypt.SealFile(key, dest); err != nil {
+				_ = os.Remove(dest)
+				return nil, fmt.Errorf("failed to encrypt archive: %w", err)
+			}
+		}
+	}
+
+	return manifest, nil
+}
+
+// backupSession copies a session's transcripts and custom output style into the
+// staging directory (the session folder itself is part of the copied root).
+// Returns the number of transcripts copied.
+func backupSession(stage, clotildeRoot, homeDir string, sess *session.Session) (int, error) {
+	var copied int
+	for _, src := range transcriptPaths(sess, clotildeRoot, homeDir) {
+		if !util.FileExists(src) {
+			continue // Not written yet or already cleaned up
+		}
+		if err := util.CopyFile(src, filepath.Join(stage, transcriptsSubdir, filepath.Base(src))); err != nil {
+			return copied, fmt.Errorf("failed to copy transcript %s: %w", src, err)
+		}
+		copied++
+	}
+
+	if sess.Metadata.OwnsOutputStyle() {
+		stylePath := outputstyle.GetCustomStylePath(clotildeRoot, sess.Name)
+		if util.FileExists(stylePath) {
+			if err := util.CopyFile(stylePath, backupStylePath(stage, sess.Name)); err != nil {
+				return copied, fmt.Errorf("failed to copy output style for '%s': %w", sess.Name, err)
+			}
+		}
+	}
+	return copied, nil
+}
+
+// Strategy is what Restore does with a session from the backup whose name or
+// UUID is already used in the target project.
+type Strategy string
+
+const (
+	// StrategySkip leaves the existing session alone and doesn't restore.
+	StrategySkip Strategy = "skip"
+	// StrategyRename restores under a free name ("<name>-restored", ...),
+	// keeping both. A copy of a session already here also gets new session
+	// IDs, so the two never share a transcript.
+	StrategyRename Strategy = "rename"
+	// StrategyOverwrite deletes the existing session and restores over it,
+	// replacing its transcripts.
+	StrategyOverwrite Strategy = "overwrite"
+	// StrategyMerge keeps the existing copy of the same session (same UUID)
+	// and fills in what it lacks from the backup: context, earlier
+	// transcripts, settings and env. Conflicts with a different session are
+	// renamed instead.
+	StrategyMerge Strategy = "merge"
+)
+
+// Strategies lists the valid strategies, in the order they're offered.
+var Strategies = []Strategy{StrategySkip, StrategyRename, StrategyOverwrite, StrategyMerge}
+
+// ParseStrategy validates a strategy name.
+func ParseStrategy(s string) (Strategy, error) {
+	if slices.Contains(Strategies, Strategy(s)) {
+		return Strategy(s), nil
+	}
+	names := make([]string, len(Strategies))
+	for i, st := range Strategies {
+		names[i] = string(st)
+	}
+	return "", fmt.Errorf("invalid strategy '%s' (valid: %s)", s, strings.Join(names, ", "))
+}
+
+// Conflict is a session in the backup whose name or UUID is already used by a
+// session in the target project.
+type Conflict struct {
+	Session  *session.Session // From the backup
+	Existing *session.Session // In the target, with the same name or UUID
+}
+
+// SameSession reports whether both are copies of the same Claude Code session.
+func (c Conflict) SameSession() bool {
+	id := c.Session.Metadata.SessionID
+	return id != "" && id == c.Existing.Metadata.SessionID
+}
+
+// DefaultStrategy is used for conflicts without an explicit strategy: skip
+// copies of the same session, rename different sessions.
+func DefaultStrategy(c Conflict) Strategy {
+	if c.SameSession() {
+		return StrategySkip
+	}
+	return StrategyRename
+}
+
+// Archive is a backup opened for restoring. Tarballs are extracted to a
+// temporary directory until Close.
+type Archive struct {
+	Manifest Manifest
+	stage    string
+	tmp      string
+	sessions []*session.Session
+}
+
+// Open reads the backup at src (directory or tarball).
+func Open(src string) (*Archive, error) {
+	a := &Archive{stage: src}
+	if !util.DirExists(src) {
+		tmp, err := os.MkdirTemp("", "clotilde-restore-")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create staging directory: %w", err)
+		}
+		a.stage, a.tmp = tmp, tmp
+		if err := extractArchive(src, tmp); err != nil {
+			_ = a.Close()
+			return nil, fmt.Errorf("failed to read archive %s: %w", src, err)
+		}
+	}
+
+	if err := util.ReadJSON(filepath.Join(a.stage, ManifestFile), &a.Manifest); err != nil {
+		_ = a.Close()
+		return nil, fmt.Errorf("%s is not a clotilde backup: %w", src, err)
+	}
+	if a.Manifest.Version > formatVersion {
+		_ = a.Close()
+		return nil, fmt.Errorf("backup format version %d is newer than supported (%d)", a.Manifest.Version, formatVersion)
+	}
+
+	sessions, err := session.NewFileStore(a.backupRoot()).List()
+	if err != nil {
+		_ = a.Close()
+		return nil, fmt.Errorf("failed to list sessions in backup: %w", err)
+	}
+	a.sessions = sessions
+	return a, nil
+}
+
+// Close removes the extracted copy of a tarball.
+func (a *Archive) Close() error {
+	if a.tmp == "" {
+		return nil
+	}
+	return os.RemoveAll(a.tmp)
+}
+
+func (a *Archive) backupRoot() string {
+	return filepath.Join(a.stage, clotildeSubdir)
+}
+
+// Conflicts returns the sessions in the backup whose name, or failing that
+// whose UUID, is already used in clotildeRoot.
+func (a *Archive) Conflicts(clotildeRoot string) ([]Conflict, error) {
+	target := session.NewFileStore(clotildeRoot)
+	existing, err := target.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	var conflicts []Conflict
+	for _, sess := range a.sessions {
+		for _, other := range existing {
+			sameID := sess.Metadata.SessionID != "" && other.Metadata.SessionID == sess.Metadata.SessionID
+			if other.Name == sess.Name || sameID {
+				conflicts = append(conflicts, Conflict{Session: sess, Existing: other})
+				break
+			}
+		}
+	}
+	// Prefer reporting the name conflict when both exist
+	for i, c := range conflicts {
+		if c.Existing.Name != c.Session.Name {
+			if byName, err := target.Get(c.Session.Name); err == nil {
+				conflicts[i].Existing = byName
+			}
+		}
+	}
+	return conflicts, nil
+}
+
+// Restore merges the backup into clotildeRoot. Conflicting sessions (see
+// Conflicts) are handled with the strategy in strategies under the backup's
+// session name, or DefaultStrategy. Transcripts are copied into Claude's
+// project directory for clotildeRoot, without overwriting existing files
+// unless the session is overwritten. progress (may be nil) is notified as
+// each session is restored.
+func (a *Archive) Restore(clotildeRoot, homeDir string, strategies map[string]Strategy, progress Progress) (*RestoreResult, error) {
+	if progress == nil {
+		progress = noProgress{}
+	}
+
+	conflicts, err := a.Conflicts(clotildeRoot)
+	if err != nil {
+		return nil, err
+	}
+	conflictOf := make(map[string]Conflict, len(conflicts))
+	for _, c := range conflicts {
+		conflictOf[c.Session.Name] = c
+	}
+
+	target := session.NewFileStore(clotildeRoot)
+	existing, err := target.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	result := &RestoreResult{}
+
+	for _, sess := range a.sessions {
+		progress.Start(sess.Name)
+
+		name, overwrite, newIDs := sess.Name, false, false
+		if c, ok := conflictOf[sess.Name]; ok {
+			strategy, ok := strategies[sess.Name]
+			if !ok {
+				strategy = DefaultStrategy(c)
+			}
+			if strategy == StrategyMerge && !c.SameSession() {
+				strategy = StrategyRename
+			}
+
+			switch strategy {
+			case StrategySkip:
+				result.Skipped = append(result.Skipped, sess.Name)
+				progress.Done(sess.Name, "already present, skipped")
+				continue
+			case StrategyMerge:
+				copied, err := a.mergeSession(clotildeRoot, homeDir, sess, c.Existing)
+				if err != nil {
+					progress.Fail(sess.Name, err)
+					return result, err
+				}
+				result.Transcripts += copied
+				result.Merged = append(result.Merged, RestoredSession{From: sess.Name, Name: c.Existing.Name})
+				progress.Done(sess.Name, fmt.Sprintf("merged into '%s', %d transcript(s)", c.Existing.Name, copied))
+				continue
+			case StrategyOverwrite:
+				if err := deleteSession(clotildeRoot, target, c.Existing); err != nil {
+					progress.Fail(sess.Name, err)
+					return result, err
+				}
+				overwrite = true
+				result.Overwritten = append(result.Overwritten, c.Existing.Name)
+			default:
+				name = uniqueName(target, sess.Name)
+				newIDs = slices.ContainsFunc(existing, func(other *session.Session) bool { return sharesSessionID(sess, other) })
+			}
+		}
+
+		restored, copied, err := restoreSession(a.stage, a.backupRoot(), clotildeRoot, homeDir, sess, name, overwrite, newIDs)
+		if err != nil {
+			progress.Fail(sess.Name, err)
+			return result, err
+		}
+		existing = append(existing, restored)
+		result.Transcripts += copied
+		result.Restored = append(result.Restored, RestoredSession{From: sess.Name, Name: name})
+
+		detail := fmt.Sprintf("%d transcript(s)", copied)
+		switch {
+		case overwrite:
+			detail = "overwritten, " + detail
+		case newIDs:
+			detail = fmt.Sprintf("restored as '%s' with a new session ID, %s", name, detail)
+		case name != sess.Name:
+			detail = fmt.Sprintf("restored as '%s', %s", name, detail)
+		}
+		progress.Done(sess.Name, detail)
+	}
+
+	srcConfig := config.GetConfigPath(a.backupRoot())
+	dstConfig := config.GetConfigPath(clotildeRoot)
+	if util.FileExists(srcConfig) && !util.FileExists(dstConfig) {
+		if err := util.CopyFile(srcConfig, dstConfig); err != nil {
+			return result, fmt.Errorf("failed to restore config: %w", err)
+		}
+		result.ConfigRestored = true
+	}
+
+	return result, nil
+}
+
+// deleteSession removes a session being overwritten, with its custom output
+// style unless other sessions use it. Its transcripts are left for the
+// restored ones to replace.
+func deleteSession(clotildeRoot string, store session.Store, sess *session.Session) error {
+	if sess.Metadata.OwnsOutputStyle() {
+		users, err := outputstyle.CustomStyleUsers(store, sess.Name)
+		if err != nil {
+			return fmt.Errorf("failed to check output style users of '%s': %w", sess.Name, err)
+		}
+		if len(users) == 0 {
+			if err := outputstyle.DeleteCustomStyleFile(clotildeRoot, sess.Name); err != nil {
+				return fmt.Errorf("failed to delete output style of '%s': %w", sess.Name, err)
+			}
+		}
+	}
+	if err := store.Delete(sess.Name); err != nil {
+		return fmt.Errorf("failed to delete session '%s': %w", sess.Name, err)
+	}
+	return nil
+}
+
+// mergeSession fills in what the existing copy of a session lacks from the
+// backup: context, earlier transcripts (from before a /clear), settings.json
+// and env. Returns the number of transcripts copied.
+func (a *Archive) mergeSession(clotildeRoot, homeDir string, sess, existing *session.Session) (int, error) {
+	projectDir := claude.ProjectDataDir(homeDir, clotildeRoot)
+	copied, err := restoreTranscripts(a.stage, a.backupRoot(), projectDir, homeDir, sess, false, nil)
+	if err != nil {
+		return copied, err
+	}
+
+	merged := existing.Metadata
+	if merged.Context == "" {
+		merged.Context = sess.Metadata.Context
+	}
+	if sess.Metadata.LastAccessed.After(merged.LastAccessed) {
+		merged.LastAccessed = sess.Metadata.LastAccessed
+	}
+	merged.PreviousSessions = slices.Clone(merged.PreviousSessions)
+	for _, prev := range sess.Metadata.PreviousSessions {
+		known := slices.ContainsFunc(merged.PreviousSessions, func(p session.PreviousSession) bool {
+			return p.SessionID == prev.SessionID
+		})
+		if known {
+			continue
+		}
+		if prev.TranscriptPath != "" {
+			prev.TranscriptPath = filepath.Join(projectDir, filepath.Base(prev.TranscriptPath))
+		}
+		merged.PreviousSessions = append(merged.PreviousSessions, prev)
+	}
+
+	srcDir := config.GetSessionDir(a.backupRoot(), sess.Name)
+	dstDir := config.GetSessionDir(clotildeRoot, existing.Name)
+	for _, file := range []string{"settings.json", session.EnvFile} {
+		src, dst := filepath.Join(srcDir, file), filepath.Join(dstDir, file)
+		if util.FileExists(src) && !util.FileExists(dst) {
+			if err := util.CopyFile(src, dst); err != nil {
+				return copied, fmt.Errorf("failed to merge %s of '%s': %w", file, existing.Name, err)
+			}
+		}
+	}
+
+	store := session.NewFileStore(clotildeRoot)
+	if err := store.Update(&session.Session{Name: existing.Name, Metadata: merged}); err != nil {
+		return copied, fmt.Errorf("failed to update merged session '%s': %w", existing.Name, err)
+	}
+	return copied, nil
+}
+
+// restoreSession copies a single session from the backup into the target root
+// under the given name. With newIDs, its current and previous session IDs are
+// replaced by fresh ones and its transcripts copied under them. Returns the
+// restored session and the number of transcripts copied.
+func restoreSession(stage, backupRoot, clotildeRoot, homeDir string, sess *session.Session, name string, overwrite, newIDs bool) (*session.Session, int, error) {
+	if err := copyTree(config.GetSessionDir(backupRoot, sess.Name), config.GetSessionDir(clotildeRoot, name)); err != nil {
+		return nil, 0, fmt.Errorf("failed to restore session '%s': %w", sess.Name, err)
+	}
+
+	var ids map[string]string
+	if newIDs {
+		ids = make(map[string]string)
+		for _, id := range sessionIDs(sess) {
+			ids[id] = util.GenerateUUID()
+		}
+	}
+
+	// Transcripts live under a directory derived from the project path, which
+	// differs when restoring into another project or machine.
+	projectDir := claude.ProjectDataDir(homeDir, clotildeRoot)
+	copied, err := restoreTranscripts(stage, backupRoot, projectDir, homeDir, sess, overwrite, ids)
+	if err != nil {
+		return nil, copied, err
+	}
+
+	restored := &session.Session{Name: name, Metadata: sess.Metadata}
+	restored.Metadata.Name = name
+	if newID, ok := ids[sess.Metadata.SessionID]; ok {
+		restored.Metadata.SessionID = newID
+	}
+	if restored.Metadata.TranscriptPath != "" {
+		restored.Metadata.TranscriptPath = filepath.Join(projectDir, renamedTranscript(filepath.Base(restored.Metadata.TranscriptPath), ids))
+	}
+	restored.Metadata.PreviousSessions = slices.Clone(sess.Metadata.PreviousSessions)
+	for i, prev := range restored.Metadata.PreviousSessions {
+		if newID, ok := ids[prev.SessionID]; ok {
+			restored.Metadata.PreviousSessions[i].SessionID = newID
+		}
+		if prev.TranscriptPath != "" {
+			restored.Metadata.PreviousSessions[i].TranscriptPath = filepath.Join(projectDir, renamedTranscript(filepath.Base(prev.TranscriptPath), ids))
+		}
+	}
+
+	store := session.NewFileStore(clotildeRoot)
+	if restored.Metadata.OwnsOutputStyle() {
+		if err := restoreOutputStyle(stage, clotildeRoot, store, sess.Name, name); err != nil {
+			return nil, copied, err
+		}
+	}
+
+	if err := store.Update(restored); err != nil {
+		return nil, copied, fmt.Errorf("failed to update restored session '%s': %w", name, err)
+	}
+	return restored, copied, nil
+}
+
+// restoreTranscripts copies a session's transcripts from the backup into
+// projectDir, leaving existing files alone unless overwrite is set. ids maps
+// session IDs to the new ones to copy their transcripts under; the entries
+// of those transcripts are rewritten to the new ID too.
+func restoreTranscripts(stage, backupRoot, projectDir, homeDir string, sess *session.Session, overwrite bool, ids map[string]string) (int, error) {
+	copied := 0
+	for _, original := range transcriptPaths(sess, backupRoot, homeDir) {
+		base := filepath.Base(original)
+		backupPath := filepath.Join(stage, transcriptsSubdir, base)
+		dst := filepath.Join(projectDir, renamedTranscript(base, ids))
+		if !util.FileExists(backupPath) || util.FileExists(dst) && !overwrite {
+			continue
+		}
+		oldID := strings.TrimSuffix(base, ".jsonl")
+		if newID, ok := ids[oldID]; ok {
+			data, err := os.ReadFile(backupPath)
+			if err != nil {
+				return copied, fmt.Errorf("failed to read transcript %s: %w", base, err)
+			}
+			if err := util.WriteFile(dst, bytes.ReplaceAll(data, []byte(oldID), []byte(newID))); err != nil {
+				return copied, fmt.Errorf("failed to restore transcript %s: %w", base, err)
+			}
+		} else if err := util.CopyFile(backupPath, dst); err != nil {
+			return copied, fmt.Errorf("failed to restore transcript %s: %w", base, err)
+		}
+		copied++
+	}
+	return copied, nil
+}
+
+// renamedTranscript returns the file name of a transcript restored under the
+// new session IDs in ids, or base when its ID isn't replaced.
+func renamedTranscript(base string, ids map[string]string) string {
+	if newID, ok := ids[strings.TrimSuffix(base, ".jsonl")]; ok {
+		return newID + ".jsonl"
+	}
+	return base
+}
+
+// sessionIDs returns a session's previous and current session IDs.
+func sessionIDs(sess *session.Session) []string {
+	var ids []string
+	for _, prev := range sess.Metadata.PreviousSessions {
+		if prev.SessionID != "" {
+			ids = append(ids, prev.SessionID)
+		}
+	}
+	if sess.Metadata.SessionID != "" {
+		ids = append(ids, sess.Metadata.SessionID)
+	}
+	return ids
+}
+
+// sharesSessionID reports whether two sessions have a current or previous
+// session ID in common, which would make deleting one delete the other's
+// transcripts.
+func sharesSessionID(a, b *session.Session) bool {
+	theirs := sessionIDs(b)
+	return slices.ContainsFunc(sessionIDs(a), func(id string) bool { return slices.Contains(theirs, id) })
+}
+
+// restoreOutputStyle restores a session's custom output style, rewriting its
+// frontmatter and the settings.json reference when the session was renamed.
+func restoreOutputStyle(stage, clotildeRoot string, store session.Store, from, name string) error {
+	src := backupStylePath(stage, from)
+	if !util.FileExists(src) {
+		return nil
+	}
+
+	if from == name {
+		dst := outputstyle.GetCustomStylePath(clotildeRoot, name)
+		if util.FileExists(dst) {
+			return nil
+		}
+		return util.CopyFile(src, dst)
+	}
+
+	if err := outputstyle.CreateCustomStyleFileFromFile(clotildeRoot, name, src); err != nil {
+		return fmt.Errorf("failed to restore output style for '%s': %w", name, err)
+	}
+
+	settings, err := store.LoadSettings(name)
+	if err != nil || settings == nil {
+		return err
+	}
+	settings.OutputStyle = outputstyle.GetCustomStyleReference(name)
+	return store.SaveSettings(name, settings)
+}
+
+// uniqueName returns name if it is free in the store, otherwise the first free
+// "<name>-restored[-N]" variant, trimmed to fit session.MaxNameLength.
+func uniqueName(store session.Store, name string) string {
+	if !store.Exists(name) {
+		return name
+	}
+	for i := 1; ; i++ {
+		suffix := "-restored"
+		if i > 1 {
+			suffix = fmt.Sprintf("-restored-%d", i)
+		}
+		base := name
+		if len(base)+len(suffix) > session.MaxNameLength {
+			base = strings.TrimRight(base[:session.MaxNameLength-len(suffix)], "-")
+		}
+		if candidate := base + suffix; !store.Exists(candidate) {
+			return candidate
+		}
+	}
+}
+
+// transcriptPaths returns every transcript path associated with a session:
+// previous UUIDs first, then the current one.
+func transcriptPaths(sess *session.Session, clotildeRoot, homeDir string) []string {
+	var paths []string
+	for _, prev := range sess.Metadata.PreviousSessions {
+		if prev.SessionID != "" {
+			paths = append(paths, claude.PreviousTranscriptPath(homeDir, clotildeRoot, prev))
+		}
+	}
+	switch {
+	case sess.Metadata.TranscriptPath != "":
+		paths = append(paths, sess.Metadata.TranscriptPath)
+	case sess.Metadata.SessionID != "":
+		paths = append(paths, claude.TranscriptPath(homeDir, clotildeRoot, sess.Metadata.SessionID))
+	}
+	return paths
+}
+
+func backupStylePath(stage, sessionName string) string {
+	return filepath.Join(stage, stylesSubdir, "clotilde", sessionName+".md")
+}
+
+// copyTree copies all regular files under src into dst, preserving layout.
+func copyTree(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if d.IsDir() {
+			return util.EnsureDir(target)
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		return util.CopyFile(path, target)
+	})
+}
+
+// writeArchive writes the contents of dir to a tarball at dest, gzip-compressed
+// unless dest ends in plain ".tar".
+func writeArchive(dir, dest string) (err error) {
+	f, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	var w io.Writer = f
+	if !strings.HasSuffix(dest, ".tar") {
+		gz := gzip.NewWriter(f)
+		defer func() {
+			if cerr := gz.Close(); err == nil {
+				err = cerr
+			}
+		}()
+		w = gz
+	}
+
+	tw := tar.NewWriter(w)
+	defer func() {
+		if cerr := tw.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	return tw.AddFS(os.DirFS(dir))
+}
+
+// extractArchive unpacks a (optionally gzip-compressed, optionally encrypted)
+// tarball into dir, rejecting entries that would escape it.
+func extractArchive(src, dir string) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	br := bufio.NewReader(f)
+	var r io.Reader = br
+	if head, _ := br.Peek(64); crypt.IsSealed(head) {
+		data, err := crypt.ReadFile(src)
+		if err != nil {
+			return err
+		}
+		r = bytes.NewReader(data)
+	}
+	if !strings.HasSuffix(src, ".tar") {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = gz.Close() }()
+		r = gz
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !filepath.IsLocal(hdr.Name) {
+			return fmt.Errorf("archive entry %q escapes the backup directory", hdr.Name)
+		}
+		target := filepath.Join(dir, hdr.Name)
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := util.EnsureDir(target); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			data, err := io.ReadAll(tr)
+			if err != nil {
+				return err
+			}
+			if err := util.WriteFile(target, data); err != nil {
+				return err
+			}
+		}
+	}
+}
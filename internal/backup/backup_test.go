@@ -0,0 +1,316 @@
+package backup_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/backup"
+	"github.com/fgrehm/clotilde/internal/claude"
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/crypt"
+	"github.com/fgrehm/clotilde/internal/outputstyle"
+	"github.com/fgrehm/clotilde/internal/session"
+)
+
+var _ = Describe("Backup", func() {
+	var (
+		tempDir    string
+		homeDir    string
+		sourceRoot string
+		targetRoot string
+		source     *session.FileStore
+		target     *session.FileStore
+	)
+
+	BeforeEach(func() {
+		tempDir = GinkgoT().TempDir()
+		homeDir = filepath.Join(tempDir, "home")
+
+		sourceProject := filepath.Join(tempDir, "source")
+		Expect(config.EnsureClotildeStructure(sourceProject)).To(Succeed())
+		sourceRoot = filepath.Join(sourceProject, config.ClotildeDir)
+		source = session.NewFileStore(sourceRoot)
+
+		targetProject := filepath.Join(tempDir, "target")
+		Expect(config.EnsureClotildeStructure(targetProject)).To(Succeed())
+		targetRoot = filepath.Join(targetProject, config.ClotildeDir)
+		target = session.NewFileStore(targetRoot)
+	})
+
+	writeTranscript := func(clotildeRoot, uuid string) string {
+		path := claude.TranscriptPath(homeDir, clotildeRoot, uuid)
+		Expect(os.MkdirAll(filepath.Dir(path), 0o755)).To(Succeed())
+		Expect(os.WriteFile(path, []byte(`{"type":"user","sessionId":"`+uuid+`"}`+"\n"), 0o644)).To(Succeed())
+		return path
+	}
+
+	restore := func(src string, strategies map[string]backup.Strategy) (*backup.RestoreResult, error) {
+		archive, err := backup.Open(src)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = archive.Close() }()
+		return archive.Restore(targetRoot, homeDir, strategies, nil)
+	}
+
+	createSession := func(name, uuid string, previous ...string) {
+		sess := session.NewSession(name, uuid)
+		for _, id := range previous {
+			sess.Metadata.PreviousSessions = append(sess.Metadata.PreviousSessions, session.PreviousSession{SessionID: id})
+		}
+		sess.Metadata.TranscriptPath = writeTranscript(sourceRoot, uuid)
+		for _, id := range previous {
+			writeTranscript(sourceRoot, id)
+		}
+		Expect(source.Create(sess)).To(Succeed())
+		Expect(source.SaveSettings(name, &session.Settings{Model: "sonnet"})).To(Succeed())
+	}
+
+	for _, dest := range []string{"backup-dir", "backup.tar.gz", "backup.tar"} {
+		It("round-trips sessions and transcripts through "+dest, func() {
+			createSession("alpha", "uuid-alpha", "uuid-alpha-old")
+			createSession("beta", "uuid-beta")
+
+			out := filepath.Join(tempDir, dest)
+			manifest, err := backup.Create(sourceRoot, homeDir, out, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(manifest.Sessions).To(ConsistOf("alpha", "beta"))
+			Expect(manifest.Transcripts).To(Equal(3))
+
+			result, err := restore(out, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Restored).To(HaveLen(2))
+			Expect(result.Transcripts).To(Equal(3))
+
+			restored, err := target.Get("alpha")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(restored.Metadata.TranscriptPath).To(Equal(claude.TranscriptPath(homeDir, targetRoot, "uuid-alpha")))
+			Expect(restored.Metadata.TranscriptPath).To(BeAnExistingFile())
+			Expect(claude.TranscriptPath(homeDir, targetRoot, "uuid-alpha-old")).To(BeAnExistingFile())
+
+			settings, err := target.LoadSettings("alpha")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(settings.Model).To(Equal("sonnet"))
+		})
+	}
+
+	Describe("with encryption enabled", func() {
+		BeforeEach(func() {
+			GinkgoT().Setenv("XDG_CONFIG_HOME", filepath.Join(tempDir, "xdg"))
+			_, _, err := crypt.EnsureKey()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.SaveProjectEncryption(sourceRoot, true)).To(Succeed())
+		})
+
+		It("encrypts archives and restores them", func() {
+			createSession("alpha", "uuid-alpha")
+
+			out := filepath.Join(tempDir, "backup.tar.gz")
+			_, err := backup.Create(sourceRoot, homeDir, out, nil)
+			Expect(err).NotTo(HaveOccurred())
+			data, err := os.ReadFile(out)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(crypt.IsSealed(data)).To(BeTrue())
+
+			result, err := restore(out, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Restored).To(HaveLen(1))
+		})
+
+		It("refuses directory backups", func() {
+			_, err := backup.Create(sourceRoot, homeDir, filepath.Join(tempDir, "backup-dir"), nil)
+			Expect(err).To(MatchError(ContainSubstring("must be written as an archive")))
+		})
+	})
+
+	It("refuses to overwrite an existing destination", func() {
+		out := filepath.Join(tempDir, "existing")
+		Expect(os.Mkdir(out, 0o755)).To(Succeed())
+
+		_, err := backup.Create(sourceRoot, homeDir, out, nil)
+		Expect(err).To(MatchError(ContainSubstring("already exists")))
+	})
+
+	It("renames sessions that conflict with a different session", func() {
+		createSession("alpha", "uuid-alpha")
+		Expect(target.Create(session.NewSession("alpha", "uuid-other"))).To(Succeed())
+
+		out := filepath.Join(tempDir, "backup-dir")
+		_, err := backup.Create(sourceRoot, homeDir, out, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		result, err := restore(out, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Restored).To(ConsistOf(backup.RestoredSession{From: "alpha", Name: "alpha-restored"}))
+
+		renamed, err := target.Get("alpha-restored")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(renamed.Metadata.Name).To(Equal("alpha-restored"))
+		Expect(renamed.Metadata.SessionID).To(Equal("uuid-alpha"))
+
+		original, err := target.Get("alpha")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(original.Metadata.SessionID).To(Equal("uuid-other"))
+	})
+
+	It("skips sessions already present with the same UUID", func() {
+		createSession("alpha", "uuid-alpha")
+		Expect(target.Create(session.NewSession("alpha", "uuid-alpha"))).To(Succeed())
+
+		out := filepath.Join(tempDir, "backup-dir")
+		_, err := backup.Create(sourceRoot, homeDir, out, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		result, err := restore(out, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Restored).To(BeEmpty())
+		Expect(result.Skipped).To(ConsistOf("alpha"))
+	})
+
+	It("rewrites custom output styles for renamed sessions", func() {
+		createSession("styled", "uuid-styled")
+		sess, err := source.Get("styled")
+		Expect(err).NotTo(HaveOccurred())
+		sess.Metadata.HasCustomOutputStyle = true
+		Expect(source.Update(sess)).To(Succeed())
+		Expect(outputstyle.CreateCustomStyleFile(sourceRoot, "styled", "Be terse.")).To(Succeed())
+		Expect(source.SaveSettings("styled", &session.Settings{OutputStyle: "clotilde/styled"})).To(Succeed())
+		Expect(target.Create(session.NewSession("styled", "uuid-other"))).To(Succeed())
+
+		out := filepath.Join(tempDir, "backup-dir")
+		_, err = backup.Create(sourceRoot, homeDir, out, nil)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = restore(out, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		content, err := os.ReadFile(outputstyle.GetCustomStylePath(targetRoot, "styled-restored"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(ContainSubstring("name: clotilde/styled-restored"))
+		Expect(string(content)).To(ContainSubstring("Be terse."))
+
+		settings, err := target.LoadSettings("styled-restored")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(settings.OutputStyle).To(Equal("clotilde/styled-restored"))
+	})
+
+	Describe("conflict strategies", func() {
+		var out string
+
+		BeforeEach(func() {
+			createSession("alpha", "uuid-alpha", "uuid-alpha-old")
+			sess, err := source.Get("alpha")
+			Expect(err).NotTo(HaveOccurred())
+			sess.Metadata.Context = "from the backup"
+			Expect(source.Update(sess)).To(Succeed())
+
+			out = filepath.Join(tempDir, "backup-dir")
+			_, err = backup.Create(sourceRoot, homeDir, out, nil)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("reports name and UUID conflicts", func() {
+			Expect(target.Create(session.NewSession("other", "uuid-alpha"))).To(Succeed())
+
+			archive, err := backup.Open(out)
+			Expect(err).NotTo(HaveOccurred())
+			defer func() { _ = archive.Close() }()
+
+			conflicts, err := archive.Conflicts(targetRoot)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(conflicts).To(HaveLen(1))
+			Expect(conflicts[0].Existing.Name).To(Equal("other"))
+			Expect(conflicts[0].SameSession()).To(BeTrue())
+		})
+
+		It("skips conflicting sessions", func() {
+			Expect(target.Create(session.NewSession("alpha", "uuid-other"))).To(Succeed())
+
+			result, err := restore(out, map[string]backup.Strategy{"alpha": backup.StrategySkip})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Skipped).To(ConsistOf("alpha"))
+			Expect(target.Exists("alpha-restored")).To(BeFalse())
+		})
+
+		It("overwrites the existing session and its transcripts", func() {
+			existing := session.NewSession("alpha", "uuid-alpha")
+			Expect(target.Create(existing)).To(Succeed())
+			transcript := claude.TranscriptPath(homeDir, targetRoot, "uuid-alpha")
+			Expect(os.MkdirAll(filepath.Dir(transcript), 0o755)).To(Succeed())
+			Expect(os.WriteFile(transcript, []byte("stale\n"), 0o644)).To(Succeed())
+
+			result, err := restore(out, map[string]backup.Strategy{"alpha": backup.StrategyOverwrite})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Overwritten).To(ConsistOf("alpha"))
+			Expect(result.Restored).To(ConsistOf(backup.RestoredSession{From: "alpha", Name: "alpha"}))
+
+			restored, err := target.Get("alpha")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(restored.Metadata.Context).To(Equal("from the backup"))
+			Expect(os.ReadFile(transcript)).To(ContainSubstring(`"type":"user"`))
+		})
+
+		It("merges metadata into the existing copy of the same session", func() {
+			Expect(target.Create(session.NewSession("alpha", "uuid-alpha"))).To(Succeed())
+
+			result, err := restore(out, map[string]backup.Strategy{"alpha": backup.StrategyMerge})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Merged).To(ConsistOf(backup.RestoredSession{From: "alpha", Name: "alpha"}))
+			Expect(result.Transcripts).To(Equal(2))
+
+			merged, err := target.Get("alpha")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(merged.Metadata.Context).To(Equal("from the backup"))
+			Expect(merged.Metadata.PreviousSessions).To(HaveLen(1))
+			Expect(claude.TranscriptPath(homeDir, targetRoot, "uuid-alpha-old")).To(BeAnExistingFile())
+
+			settings, err := target.LoadSettings("alpha")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(settings.Model).To(Equal("sonnet"))
+		})
+
+		It("gives a renamed copy of the same session new session IDs", func() {
+			Expect(target.Create(session.NewSession("alpha", "uuid-alpha"))).To(Succeed())
+
+			result, err := restore(out, map[string]backup.Strategy{"alpha": backup.StrategyRename})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Restored).To(ConsistOf(backup.RestoredSession{From: "alpha", Name: "alpha-restored"}))
+			Expect(result.Transcripts).To(Equal(2))
+
+			renamed, err := target.Get("alpha-restored")
+			Expect(err).NotTo(HaveOccurred())
+			newID := renamed.Metadata.SessionID
+			Expect(newID).NotTo(BeElementOf("uuid-alpha", "uuid-alpha-old", ""))
+			Expect(renamed.Metadata.PreviousSessions).To(HaveLen(1))
+			Expect(renamed.Metadata.PreviousSessions[0].SessionID).NotTo(BeElementOf("uuid-alpha", "uuid-alpha-old", newID))
+			Expect(renamed.Metadata.TranscriptPath).To(Equal(claude.TranscriptPath(homeDir, targetRoot, newID)))
+
+			transcript, err := os.ReadFile(claude.TranscriptPath(homeDir, targetRoot, newID))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(transcript)).To(ContainSubstring(`"sessionId":"` + newID + `"`))
+			Expect(claude.TranscriptPath(homeDir, targetRoot, renamed.Metadata.PreviousSessions[0].SessionID)).To(BeAnExistingFile())
+		})
+
+		It("renames instead of merging different sessions", func() {
+			Expect(target.Create(session.NewSession("alpha", "uuid-other"))).To(Succeed())
+
+			result, err := restore(out, map[string]backup.Strategy{"alpha": backup.StrategyMerge})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Merged).To(BeEmpty())
+			Expect(result.Restored).To(ConsistOf(backup.RestoredSession{From: "alpha", Name: "alpha-restored"}))
+		})
+	})
+
+	It("validates strategy names", func() {
+		Expect(backup.ParseStrategy("merge")).To(Equal(backup.StrategyMerge))
+		_, err := backup.ParseStrategy("clobber")
+		Expect(err).To(MatchError(ContainSubstring("valid: skip, rename, overwrite, merge")))
+	})
+
+	It("rejects paths that are not backups", func() {
+		_, err := restore(filepath.Join(tempDir, "source"), nil)
+		Expect(err).To(MatchError(ContainSubstring("not a clotilde backup")))
+	})
+})
@@ -2,11 +2,18 @@ package claude
 
 import (
 	"bufio"
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
 
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/ui"
 	"github.com/fgrehm/clotilde/internal/util"
 )
 
@@ -16,109 +23,235 @@ type DeletedFiles struct {
 	AgentLogs  []string // Agent log file paths that were deleted
 }
 
+// SessionData identifies the Claude Code data of one session UUID.
+type SessionData struct {
+	SessionID      string
+	TranscriptPath string // Computed from the project when empty
+}
+
+// DeleteError lists the files DeleteAllSessionData couldn't remove.
+type DeleteError struct {
+	Failures []error
+}
+
+func (e *DeleteError) Error() string {
+	lines := make([]string, len(e.Failures))
+	for i, err := range e.Failures {
+		lines[i] = "  - " + err.Error()
+	}
+	return fmt.Sprintf("failed to delete %d file(s):\n%s", len(e.Failures), strings.Join(lines, "\n"))
+}
+
+func (e *DeleteError) Unwrap() []error {
+	return e.Failures
+}
+
+// deleteWorkers bounds how many files are checked or removed at once.
+const deleteWorkers = 8
+
 // DeleteSessionData removes Claude Code transcript and agent logs for a session.
 // If transcriptPath is provided, uses it directly. Otherwise computes it from clotildeRoot.
 // Returns DeletedFiles with info about what was deleted.
 func DeleteSessionData(clotildeRoot, sessionID, transcriptPath string) (*DeletedFiles, error) {
+	return DeleteAllSessionData(clotildeRoot, []SessionData{{SessionID: sessionID, TranscriptPath: transcriptPath}})
+}
+
+// DeleteAllSessionData removes the transcripts of several session UUIDs (a
+// session's current and previous ones) and the agent logs referencing any of
+// them. Agent logs are read once for all UUIDs, and files are checked and
+// removed concurrently. A failure doesn't stop the others: every file that
+// couldn't be removed is reported in a *DeleteError, and DeletedFiles lists
+// what was removed. Agent logs that can't be read are skipped with a warning. Files already gone are skipped, so a
+// failed deletion can be retried.
+func DeleteAllSessionData(clotildeRoot string, sessions []SessionData) (*DeletedFiles, error) {
 	deleted := &DeletedFiles{
 		Transcript: []string{},
 		AgentLogs:  []string{},
 	}
 
-	var claudeProjectDir string
+	files, err := FindSessionData(clotildeRoot, sessions)
+	if err != nil {
+		return deleted, err
+	}
 
-	// If transcript path is provided, use it directly
-	if transcriptPath != "" {
-		// Delete transcript file
-		if util.FileExists(transcriptPath) {
-			if err := os.Remove(transcriptPath); err != nil {
-				return deleted, fmt.Errorf("failed to delete transcript: %w", err)
-			}
-			deleted.Transcript = append(deleted.Transcript, transcriptPath)
-		}
-		// Get project directory from transcript path
-		claudeProjectDir = filepath.Dir(transcriptPath)
-	} else {
-		// Fall back to computing the path
-		projectDir := ProjectDir(clotildeRoot)
-		homeDir, err := util.HomeDir()
-		if err != nil {
-			return deleted, fmt.Errorf("failed to get home directory: %w", err)
-		}
+	// An unreadable agent log may well belong to another session: it's only
+	// worth a warning, never a reason to keep this one
+	for _, err := range files.Unchecked {
+		fmt.Fprintln(os.Stderr, ui.Warning(err.Error()))
+	}
 
-		claudeProjectDir = filepath.Join(homeDir, ".claude", "projects", projectDir)
+	var failures, errs []error
+	deleted.Transcript, failures = removeFiles(files.Transcripts)
+	deleted.AgentLogs, errs = removeFiles(files.AgentLogs)
+	failures = append(failures, errs...)
 
-		// Delete transcript file
-		transcriptPath := filepath.Join(claudeProjectDir, sessionID+".jsonl")
-		if util.FileExists(transcriptPath) {
-			if err := os.Remove(transcriptPath); err != nil {
-				return deleted, fmt.Errorf("failed to delete transcript: %w", err)
-			}
-			deleted.Transcript = append(deleted.Transcript, transcriptPath)
-		}
+	if len(failures) > 0 {
+		return deleted, &DeleteError{Failures: failures}
 	}
+	return deleted, nil
+}
 
-	// Delete agent logs that reference this session
-	agentLogs, err := deleteAgentLogs(claudeProjectDir, sessionID)
-	if err != nil {
-		return deleted, err
-	}
-	deleted.AgentLogs = agentLogs
+// SessionFiles are the Claude Code files of a set of session UUIDs.
+type SessionFiles struct {
+	Transcripts []string // Existing transcript file paths
+	AgentLogs   []string // Agent log file paths referencing any of the UUIDs
+	Unchecked   []error  // Agent logs that couldn't be read to tell
+}
 
-	return deleted, nil
+// SessionDataOf returns the UUIDs of sess, previous ones (from /clear) first.
+func SessionDataOf(sess *session.Session) []SessionData {
+	var data []SessionData
+	for _, prev := range sess.Metadata.PreviousSessions {
+		data = append(data, SessionData{SessionID: prev.SessionID, TranscriptPath: prev.TranscriptPath})
+	}
+	return append(data, SessionData{SessionID: sess.Metadata.SessionID, TranscriptPath: sess.Metadata.TranscriptPath})
 }
 
-// deleteAgentLogs finds and deletes agent log files that reference the given sessionId.
-// Returns a list of deleted agent log file paths.
-func deleteAgentLogs(claudeProjectDir, sessionID string) ([]string, error) {
-	deletedLogs := []string{}
+// FindSessionData lists the files DeleteAllSessionData would remove for
+// sessions, so they can be shown before deleting.
+func FindSessionData(clotildeRoot string, sessions []SessionData) (*SessionFiles, error) {
+	files := &SessionFiles{}
 
-	// Check if project directory exists
-	if !util.DirExists(claudeProjectDir) {
-		return deletedLogs, nil // Nothing to delete
+	var projectDirs, sessionIDs []string
+	for _, s := range sessions {
+		if s.SessionID == "" && s.TranscriptPath == "" {
+			continue
+		}
+		path := s.TranscriptPath
+		if path == "" {
+			homeDir, err := util.HomeDir()
+			if err != nil {
+				return files, fmt.Errorf("failed to get home directory: %w", err)
+			}
+			path = filepath.Join(ProjectDataDir(homeDir, clotildeRoot), s.SessionID+".jsonl")
+		}
+		// Anything but a missing file is listed, so removing it reports why it failed
+		if _, err := os.Lstat(path); !os.IsNotExist(err) {
+			files.Transcripts = append(files.Transcripts, path)
+		}
+		if dir := filepath.Dir(path); !slices.Contains(projectDirs, dir) {
+			projectDirs = append(projectDirs, dir)
+		}
+		if s.SessionID != "" {
+			sessionIDs = append(sessionIDs, s.SessionID)
+		}
 	}
 
-	// Find all agent-*.jsonl files
-	pattern := filepath.Join(claudeProjectDir, "agent-*.jsonl")
-	matches, err := filepath.Glob(pattern)
-	if err != nil {
-		return deletedLogs, fmt.Errorf("failed to find agent logs: %w", err)
+	files.AgentLogs, files.Unchecked = findAgentLogs(projectDirs, sessionIDs)
+	return files, nil
+}
+
+// findAgentLogs returns the agent-*.jsonl files in dirs that reference any of
+// sessionIDs, reading each file once.
+func findAgentLogs(dirs, sessionIDs []string) ([]string, []error) {
+	if len(sessionIDs) == 0 {
+		return nil, nil
 	}
 
-	// Check each file for references to sessionId
-	for _, logPath := range matches {
-		containsSession, err := fileContainsSessionID(logPath, sessionID)
+	var candidates []string
+	var failures []error
+	for _, dir := range dirs {
+		if !util.DirExists(dir) {
+			continue
+		}
+		matches, err := filepath.Glob(filepath.Join(dir, "agent-*.jsonl"))
 		if err != nil {
-			// Log warning but continue with other files
-			fmt.Fprintf(os.Stderr, "Warning: failed to check %s: %v\n", logPath, err)
+			failures = append(failures, fmt.Errorf("failed to find agent logs in %s: %w", dir, err))
 			continue
 		}
+		candidates = append(candidates, matches...)
+	}
+
+	references := make([]bool, len(candidates))
+	errs := forEachConcurrently(len(candidates), func(i int) error {
+		found, err := fileContainsSessionID(candidates[i], sessionIDs)
+		if err != nil {
+			return fmt.Errorf("failed to check %s: %w", candidates[i], err)
+		}
+		references[i] = found
+		return nil
+	})
+	failures = append(failures, errs...)
 
-		if containsSession {
-			if err := os.Remove(logPath); err != nil {
-				return deletedLogs, fmt.Errorf("failed to delete agent log %s: %w", logPath, err)
+	var logs []string
+	for i, path := range candidates {
+		if references[i] {
+			logs = append(logs, path)
+		}
+	}
+	return logs, failures
+}
+
+// removeFiles removes paths concurrently, skipping those that don't exist, and
+// returns the ones removed in their original order.
+func removeFiles(paths []string) ([]string, []error) {
+	removed := make([]bool, len(paths))
+	failures := forEachConcurrently(len(paths), func(i int) error {
+		if err := os.Remove(paths[i]); err != nil {
+			if os.IsNotExist(err) {
+				return nil
 			}
-			deletedLogs = append(deletedLogs, logPath)
+			return fmt.Errorf("failed to delete %s: %w", paths[i], err)
+		}
+		removed[i] = true
+		return nil
+	})
+
+	result := []string{}
+	for i, path := range paths {
+		if removed[i] {
+			result = append(result, path)
 		}
 	}
+	return result, failures
+}
+
+// forEachConcurrently calls fn for 0..n-1 with at most deleteWorkers calls at
+// a time and returns the errors in index order.
+func forEachConcurrently(n int, fn func(i int) error) []error {
+	results := make([]error, n)
+	sem := make(chan struct{}, deleteWorkers)
+	var wg sync.WaitGroup
+	for i := range n {
+		sem <- struct{}{}
+		wg.Go(func() {
+			defer func() { <-sem }()
+			results[i] = fn(i)
+		})
+	}
+	wg.Wait()
 
-	return deletedLogs, nil
+	var failures []error
+	for _, err := range results {
+		if err != nil {
+			failures = append(failures, err)
+		}
+	}
+	return failures
 }
 
-// fileContainsSessionID checks if a file contains a reference to the given sessionId.
-func fileContainsSessionID(path, sessionID string) (bool, error) {
+// fileContainsSessionID checks if a file references any of the given session IDs.
+func fileContainsSessionID(path string, sessionIDs []string) (bool, error) {
 	file, err := os.Open(path)
 	if err != nil {
 		return false, err
 	}
 	defer func() { _ = file.Close() }()
 
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		if strings.Contains(scanner.Text(), sessionID) {
-			return true, nil
+	// ReadBytes copes with arbitrarily long lines (large tool results)
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		for _, id := range sessionIDs {
+			if bytes.Contains(line, []byte(id)) {
+				return true, nil
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
 		}
 	}
-
-	return false, scanner.Err()
 }
@@ -1,8 +1,10 @@
 package claude_test
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -101,4 +103,71 @@ var _ = Describe("Cleanup", func() {
 			Expect(deleted).NotTo(BeNil())
 		})
 	})
+
+	Describe("DeleteAllSessionData", func() {
+		It("removes every transcript and the agent logs referencing any of them", func() {
+			var sessions []claude.SessionData
+			var transcripts []string
+			for _, id := range []string{"uuid-a", "uuid-b", "uuid-c"} {
+				path := filepath.Join(projectDir, id+".jsonl")
+				Expect(os.WriteFile(path, []byte("{}\n"), 0o644)).To(Succeed())
+				sessions = append(sessions, claude.SessionData{SessionID: id, TranscriptPath: path})
+				transcripts = append(transcripts, path)
+			}
+			logA := filepath.Join(projectDir, "agent-a.jsonl")
+			Expect(os.WriteFile(logA, []byte(`{"sessionId":"uuid-a"}`+"\n"), 0o644)).To(Succeed())
+			logC := filepath.Join(projectDir, "agent-c.jsonl")
+			Expect(os.WriteFile(logC, []byte(`{"sessionId":"uuid-c"}`+"\n"), 0o644)).To(Succeed())
+			other := filepath.Join(projectDir, "agent-other.jsonl")
+			Expect(os.WriteFile(other, []byte(`{"sessionId":"uuid-other"}`+"\n"), 0o644)).To(Succeed())
+
+			deleted, err := claude.DeleteAllSessionData(clotildeRoot, sessions)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleted.Transcript).To(Equal(transcripts))
+			Expect(deleted.AgentLogs).To(ConsistOf(logA, logC))
+			Expect(other).To(BeAnExistingFile())
+		})
+
+		It("finds references in agent log lines longer than 64KB", func() {
+			agentLog := filepath.Join(projectDir, "agent-long.jsonl")
+			long := `{"output":"` + strings.Repeat("x", 200*1024) + `"}` + "\n" + `{"sessionId":"uuid-a"}` + "\n"
+			Expect(os.WriteFile(agentLog, []byte(long), 0o644)).To(Succeed())
+
+			deleted, err := claude.DeleteAllSessionData(clotildeRoot, []claude.SessionData{{SessionID: "uuid-a", TranscriptPath: filepath.Join(projectDir, "uuid-a.jsonl")}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleted.AgentLogs).To(ConsistOf(agentLog))
+		})
+
+		It("removes what it can and reports each failure", func() {
+			removable := filepath.Join(projectDir, "uuid-a.jsonl")
+			Expect(os.WriteFile(removable, []byte("{}\n"), 0o644)).To(Succeed())
+			// A non-empty directory can't be removed, even as root
+			stuck := filepath.Join(projectDir, "uuid-b.jsonl")
+			Expect(os.MkdirAll(filepath.Join(stuck, "child"), 0o755)).To(Succeed())
+			unreadable := filepath.Join(projectDir, "agent-dir.jsonl")
+			Expect(os.Mkdir(unreadable, 0o755)).To(Succeed())
+
+			deleted, err := claude.DeleteAllSessionData(clotildeRoot, []claude.SessionData{
+				{SessionID: "uuid-b", TranscriptPath: stuck},
+				{SessionID: "uuid-a", TranscriptPath: removable},
+			})
+			Expect(deleted.Transcript).To(ConsistOf(removable))
+
+			var deleteErr *claude.DeleteError
+			Expect(errors.As(err, &deleteErr)).To(BeTrue())
+			// An agent log that can't be read only warns: it may not be this session's
+			Expect(deleteErr.Failures).To(HaveLen(1))
+			Expect(err.Error()).NotTo(ContainSubstring(unreadable))
+			Expect(err.Error()).To(ContainSubstring("failed to delete " + stuck))
+		})
+
+		It("ignores agent logs when no session ID is known", func() {
+			agentLog := filepath.Join(projectDir, "agent-any.jsonl")
+			Expect(os.WriteFile(agentLog, []byte(`{"sessionId":"uuid-a"}`+"\n"), 0o644)).To(Succeed())
+
+			_, err := claude.DeleteAllSessionData(clotildeRoot, []claude.SessionData{{TranscriptPath: filepath.Join(projectDir, "none.jsonl")}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(agentLog).To(BeAnExistingFile())
+		})
+	})
 })
@@ -0,0 +1,94 @@
+package claude
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Conversation is a Claude Code transcript in a project's data directory.
+type Conversation struct {
+	SessionID   string
+	Path        string
+	Started     time.Time // Timestamp of the first entry
+	LastActive  time.Time // When the transcript was last written
+	FirstPrompt string    // First thing the user typed, on one line and shortened
+}
+
+// FindConversations returns the transcripts in the Claude Code data directory
+// of the project at clotildeRoot whose session IDs aren't in known, most
+// recently active first. Agent logs and transcripts without a prompt are left
+// out. A missing data directory yields none.
+func FindConversations(homeDir, clotildeRoot string, known map[string]bool) ([]Conversation, error) {
+	paths, err := filepath.Glob(filepath.Join(ProjectDataDir(homeDir, clotildeRoot), "*.jsonl"))
+	if err != nil {
+		return nil, err
+	}
+
+	var conversations []Conversation
+	for _, path := range paths {
+		sessionID := strings.TrimSuffix(filepath.Base(path), ".jsonl")
+		if known[sessionID] || strings.HasPrefix(sessionID, "agent-") {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		conversation := Conversation{SessionID: sessionID, Path: path, LastActive: info.ModTime()}
+		if err := readConversationStart(path, &conversation); err != nil || conversation.FirstPrompt == "" {
+			continue
+		}
+		conversations = append(conversations, conversation)
+	}
+
+	slices.SortFunc(conversations, func(a, b Conversation) int {
+		return b.LastActive.Compare(a.LastActive)
+	})
+	return conversations, nil
+}
+
+// readConversationStart sets the start time and first prompt of c, reading
+// the transcript only up to that prompt.
+func readConversationStart(path string, c *Conversation) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+
+	// ReadBytes copes with arbitrarily long lines (large tool results)
+	reader := bufio.NewReader(file)
+	for {
+		line, readErr := reader.ReadBytes('\n')
+		var e struct {
+			Type      string    `json:"type"`
+			Timestamp time.Time `json:"timestamp"`
+			IsMeta    bool      `json:"isMeta"`
+			Message   struct {
+				Content json.RawMessage `json:"content"`
+			} `json:"message"`
+		}
+		if json.Unmarshal(line, &e) == nil {
+			if c.Started.IsZero() {
+				c.Started = e.Timestamp
+			}
+			if text := messageText(e.Message.Content); e.Type == "user" && !e.IsMeta && text != "" && !strings.HasPrefix(text, "<") {
+				c.FirstPrompt = shortenRecapText(text)
+				return nil
+			}
+		}
+		if errors.Is(readErr, io.EOF) {
+			return nil
+		}
+		if readErr != nil {
+			return readErr
+		}
+	}
+}
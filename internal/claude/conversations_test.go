@@ -0,0 +1,68 @@
+package claude_test
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/claude"
+)
+
+var _ = Describe("FindConversations", func() {
+	var (
+		homeDir      string
+		clotildeRoot string
+		dataDir      string
+	)
+
+	BeforeEach(func() {
+		homeDir = GinkgoT().TempDir()
+		GinkgoT().Setenv("CLAUDE_CONFIG_DIR", "")
+		clotildeRoot = filepath.Join(GinkgoT().TempDir(), ".claude", "clotilde")
+		dataDir = claude.ProjectDataDir(homeDir, clotildeRoot)
+		Expect(os.MkdirAll(dataDir, 0o755)).To(Succeed())
+	})
+
+	writeTranscript := func(name, content string, modTime time.Time) string {
+		path := filepath.Join(dataDir, name)
+		Expect(os.WriteFile(path, []byte(content), 0o644)).To(Succeed())
+		Expect(os.Chtimes(path, modTime, modTime)).To(Succeed())
+		return path
+	}
+
+	It("lists unknown transcripts with a prompt, most recent first", func() {
+		now := time.Now().Truncate(time.Second)
+		oldPath := writeTranscript("old-uuid.jsonl",
+			`{"type":"user","timestamp":"2026-01-02T10:00:00Z","message":{"content":"<command-name>/clear</command-name>"}}`+"\n"+
+				`{"type":"user","timestamp":"2026-01-02T10:01:00Z","message":{"content":"Fix the login\nredirect bug"}}`+"\n",
+			now.Add(-time.Hour))
+		writeTranscript("new-uuid.jsonl",
+			`{"type":"user","timestamp":"2026-01-03T10:00:00Z","message":{"content":[{"type":"text","text":"Add dark mode"}]}}`+"\n",
+			now)
+		writeTranscript("known-uuid.jsonl", `{"type":"user","message":{"content":"known"}}`+"\n", now)
+		writeTranscript("agent-1234.jsonl", `{"type":"user","message":{"content":"agent"}}`+"\n", now)
+		writeTranscript("empty-uuid.jsonl", `{"type":"summary","summary":"nothing"}`+"\n", now)
+
+		conversations, err := claude.FindConversations(homeDir, clotildeRoot, map[string]bool{"known-uuid": true})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(conversations).To(HaveLen(2))
+
+		Expect(conversations[0].SessionID).To(Equal("new-uuid"))
+		Expect(conversations[0].FirstPrompt).To(Equal("Add dark mode"))
+
+		Expect(conversations[1].SessionID).To(Equal("old-uuid"))
+		Expect(conversations[1].Path).To(Equal(oldPath))
+		Expect(conversations[1].FirstPrompt).To(Equal("Fix the login redirect bug"))
+		Expect(conversations[1].Started).To(Equal(time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)))
+		Expect(conversations[1].LastActive).To(BeTemporally("~", now.Add(-time.Hour), time.Second))
+	})
+
+	It("returns none when the project has no data directory", func() {
+		conversations, err := claude.FindConversations(homeDir, filepath.Join(homeDir, "other", ".claude", "clotilde"), nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(conversations).To(BeEmpty())
+	})
+})
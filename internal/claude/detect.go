@@ -1,8 +1,9 @@
 package claude
 
 import (
-	"fmt"
 	"os/exec"
+
+	"github.com/fgrehm/clotilde/internal/errs"
 )
 
 // IsInstalled checks if the claude CLI is available in PATH.
@@ -10,7 +11,7 @@ import (
 func IsInstalled() error {
 	_, err := exec.LookPath("claude")
 	if err != nil {
-		return fmt.Errorf("claude CLI not found in PATH\n\n" +
+		return errs.ClaudeFailed("claude CLI not found in PATH\n\n" +
 			"Please install Claude Code first:\n" +
 			"  Visit: https://code.claude.com/\n" +
 			"  Or run: npm install -g @anthropic-ai/claude-code")
@@ -0,0 +1,121 @@
+package claude
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/ui"
+)
+
+// LastErrorFile holds the stderr tail of a session's last failed claude run.
+const LastErrorFile = "last-error.log"
+
+// stderrTailSize bounds how much of claude's stderr is kept per run
+const stderrTailSize = 64 * 1024
+
+// LastErrorPath returns where the stderr tail of a session's last failed run is saved.
+func LastErrorPath(clotildeRoot, name string) string {
+	return filepath.Join(config.GetSessionDir(clotildeRoot, name), LastErrorFile)
+}
+
+// tailBuffer is an io.Writer that keeps only the last max bytes written to it.
+type tailBuffer struct {
+	max int
+	buf []byte
+}
+
+func (t *tailBuffer) Write(p []byte) (int, error) {
+	t.buf = append(t.buf, p...)
+	if over := len(t.buf) - t.max; over > 0 {
+		t.buf = t.buf[over:]
+	}
+	return len(p), nil
+}
+
+// Bytes returns the retained tail.
+func (t *tailBuffer) Bytes() []byte {
+	return t.buf
+}
+
+// exitStatusFromError converts the result of running claude into an exit
+// status. Returns nil when claude didn't run at all (e.g. binary not found).
+// Deaths by signal are reported shell-style as 128+signal.
+func exitStatusFromError(err error, at time.Time) *session.ExitStatus {
+	if err == nil {
+		return &session.ExitStatus{Code: 0, At: at}
+	}
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return nil
+	}
+
+	status := &session.ExitStatus{Code: exitErr.ExitCode(), At: at}
+	if ws, ok := exitErr.Sys().(syscall.WaitStatus); ok && ws.Signaled() {
+		status.Code = 128 + int(ws.Signal())
+		status.Signal = ws.Signal().String()
+	}
+	return status
+}
+
+// markActive sets the session's status to active before claude runs, with
+// this process so a kill that skips recordExit doesn't leave it active, and
+// returns the status it had, restored by recordExit when claude doesn't run.
+func markActive(clotildeRoot string, sess *session.Session) string {
+	store := session.NewFileStore(clotildeRoot)
+	current, err := store.Get(sess.Name)
+	if err != nil {
+		return session.StatusIdle
+	}
+	previous := current.Status()
+	current.Metadata.Status = session.StatusActive
+	current.Metadata.Process = session.CurrentProcess()
+	if err := store.Update(current); err != nil {
+		fmt.Fprintln(os.Stderr, ui.Warning(fmt.Sprintf("Failed to update session status: %v", err)))
+	}
+	return previous
+}
+
+// recordExit stores how the run ended in the session metadata, moving the
+// session to broken after a crash and to idle otherwise, and, for crashes,
+// saves the stderr tail to LastErrorFile. When claude didn't run at all the
+// session gets previousStatus back. Failures only warn: the run itself
+// already finished.
+func recordExit(clotildeRoot string, sess *session.Session, runErr error, stderrTail []byte, previousStatus string) {
+	status := exitStatusFromError(runErr, time.Now())
+
+	// Reload session from disk (hook may have updated metadata)
+	store := session.NewFileStore(clotildeRoot)
+	current, err := store.Get(sess.Name)
+	if err != nil {
+		return
+	}
+	current.Metadata.Process = nil
+	switch {
+	case status == nil:
+		current.Metadata.Status = previousStatus
+	case status.Crashed():
+		current.Metadata.LastExit = status
+		current.Metadata.Status = session.StatusBroken
+	default:
+		current.Metadata.LastExit = status
+		current.Metadata.Status = session.StatusIdle
+	}
+	if err := store.Update(current); err != nil {
+		fmt.Fprintln(os.Stderr, ui.Warning(fmt.Sprintf("Failed to record exit status: %v", err)))
+		return
+	}
+
+	if status != nil && status.Crashed() {
+		if err := os.WriteFile(LastErrorPath(clotildeRoot, sess.Name), stderrTail, 0o644); err != nil {
+			fmt.Fprintln(os.Stderr, ui.Warning(fmt.Sprintf("Failed to save claude's error output: %v", err)))
+		}
+	}
+}
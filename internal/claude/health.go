@@ -0,0 +1,89 @@
+package claude
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// TranscriptHealth summarizes integrity problems found in a transcript.
+type TranscriptHealth struct {
+	Missing        bool // No transcript on disk (e.g. the session was never used)
+	Lines          int
+	ParseErrors    int  // Lines that aren't valid JSON, not counting a truncated last line
+	Truncated      bool // The last line is cut off (no newline and not valid JSON)
+	AssistantTurns int
+}
+
+// Problems describes what is wrong with the transcript, or nil when it looks
+// intact. A missing transcript is not a problem.
+func (h TranscriptHealth) Problems() []string {
+	var problems []string
+	if h.Truncated {
+		problems = append(problems, "truncated last line")
+	}
+	if h.ParseErrors == 1 {
+		problems = append(problems, "1 unreadable line")
+	} else if h.ParseErrors > 1 {
+		problems = append(problems, fmt.Sprintf("%d unreadable lines", h.ParseErrors))
+	}
+	if !h.Missing && h.AssistantTurns == 0 {
+		problems = append(problems, "no assistant replies")
+	}
+	return problems
+}
+
+// CheckTranscriptHealth reads a whole transcript and validates every line.
+// Only the JSON syntax and entry type are checked, so it stays cheap enough to
+// run for every session in 'list'.
+func CheckTranscriptHealth(transcriptPath string) (TranscriptHealth, error) {
+	var health TranscriptHealth
+
+	file, err := os.Open(transcriptPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			health.Missing = true
+			return health, nil
+		}
+		return health, err
+	}
+	defer func() { _ = file.Close() }()
+
+	var entry struct {
+		Type string `json:"type"`
+	}
+
+	// ReadBytes copes with arbitrarily long lines (large tool results)
+	reader := bufio.NewReader(file)
+	for {
+		line, readErr := reader.ReadBytes('\n')
+		if trimmed := bytes.TrimSpace(line); len(trimmed) > 0 {
+			health.Lines++
+			entry.Type = ""
+			switch {
+			case json.Unmarshal(trimmed, &entry) == nil:
+				if entry.Type == "assistant" {
+					health.AssistantTurns++
+				}
+			case errors.Is(readErr, io.EOF):
+				// Claude Code always ends entries with a newline; a partial
+				// last entry means a write was interrupted
+				health.Truncated = true
+			default:
+				health.ParseErrors++
+			}
+		}
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
+		if readErr != nil {
+			return health, readErr
+		}
+	}
+
+	return health, nil
+}
@@ -0,0 +1,63 @@
+package claude_test
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/fgrehm/clotilde/internal/claude"
+)
+
+func TestCheckTranscriptHealth(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		problems []string
+	}{
+		{
+			name: "intact",
+			path: write("ok.jsonl", `{"type":"user"}`+"\n"+`{"type":"assistant"}`+"\n"),
+		},
+		{
+			name:     "truncated last line",
+			path:     write("truncated.jsonl", `{"type":"user"}`+"\n"+`{"type":"assistant"}`+"\n"+`{"type":"assi`),
+			problems: []string{"truncated last line"},
+		},
+		{
+			name:     "unreadable lines",
+			path:     write("garbled.jsonl", "{oops\n"+`{"type":"assistant"}`+"\n"+"nope\n"),
+			problems: []string{"2 unreadable lines"},
+		},
+		{
+			name:     "no assistant replies",
+			path:     write("silent.jsonl", `{"type":"user"}`+"\n"),
+			problems: []string{"no assistant replies"},
+		},
+		{
+			name: "missing",
+			path: filepath.Join(dir, "missing.jsonl"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			health, err := claude.CheckTranscriptHealth(tt.path)
+			if err != nil {
+				t.Fatalf("CheckTranscriptHealth() error = %v", err)
+			}
+			if got := health.Problems(); !slices.Equal(got, tt.problems) {
+				t.Errorf("Problems() = %q, want %q", got, tt.problems)
+			}
+		})
+	}
+}
@@ -2,11 +2,22 @@ package claude
 
 import (
 	"fmt"
+	"maps"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/mattn/go-isatty"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/crypt"
+	"github.com/fgrehm/clotilde/internal/errs"
+	"github.com/fgrehm/clotilde/internal/events"
 	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/timing"
 	"github.com/fgrehm/clotilde/internal/ui"
 	"github.com/fgrehm/clotilde/internal/util"
 )
@@ -19,69 +30,111 @@ var VerboseFunc func() bool = func() bool { return false }
 // Can be overridden in tests where the fake claude binary doesn't create transcripts.
 var SessionUsedFunc = DefaultSessionUsed
 
-// appendCommonArgs adds settings flags to the arg list.
-func appendCommonArgs(args []string, settingsFile string) []string {
+// newLaunch builds a run of claude for sess: the given flags, the settings
+// file (when it exists) and additionalArgs.
+func newLaunch(sess *session.Session, args []string, settingsFile string, additionalArgs []string) Launch {
+	l := Launch{SessionID: sess.Metadata.SessionID, Name: sess.Name}
 	if settingsFile != "" && util.FileExists(settingsFile) {
+		l.Settings = settingsFile
 		args = append(args, "--settings", settingsFile)
 	}
-
-	return args
+	l.Args = append(args, additionalArgs...)
+	return l
 }
 
-// Start invokes claude CLI to start a new session.
-func Start(clotildeRoot string, sess *session.Session, settingsFile string, additionalArgs []string) error {
-	args := []string{"--session-id", sess.Metadata.SessionID, "-n", sess.Name}
-	args = appendCommonArgs(args, settingsFile)
-	args = append(args, additionalArgs...)
-
-	env := map[string]string{
-		"CLOTILDE_SESSION_NAME": sess.Name,
-	}
+// Start invokes claude CLI to start a new session, sending prompt (if any)
+// as the first message.
+func Start(clotildeRoot string, sess *session.Session, settingsFile string, additionalArgs []string, prompt string) error {
+	l := newLaunch(sess, []string{"--session-id", sess.Metadata.SessionID, "-n", sess.Name}, settingsFile, additionalArgs)
+	l.Prompt = prompt
 
 	if sess.Metadata.IsIncognito {
-		return invokeWithCleanup(clotildeRoot, sess, args, env)
+		return invokeWithCleanup(clotildeRoot, sess, l, nil)
 	}
 
-	err := invokeInteractive(args, env)
-	cleanupEmptySession(clotildeRoot, sess)
-	return err
+	return finishNewSession(clotildeRoot, sess, invokeInteractive(clotildeRoot, sess, l))
 }
 
-// Resume invokes claude CLI to resume an existing session.
-func Resume(clotildeRoot string, sess *session.Session, settingsFile string, additionalArgs []string) error {
-	args := []string{"--resume", sess.Metadata.SessionID, "-n", sess.Name}
-	args = appendCommonArgs(args, settingsFile)
-	args = append(args, additionalArgs...)
+// Resume invokes claude CLI to resume an existing session. Sessions created
+// without launching Claude Code have no transcript to resume yet, so they are
+// started (or forked from their parent) with their pre-assigned ID instead.
+// The session's prepare and teardown scripts run around claude, which is
+// claudeBin, or ClaudeBinaryPathFunc() when empty.
+func Resume(clotildeRoot string, sess *session.Session, settingsFile string, additionalArgs []string, claudeBin string) error {
+	teardown, err := prepareSession(clotildeRoot, sess)
+	if err != nil {
+		return err
+	}
 
-	env := map[string]string{
-		"CLOTILDE_SESSION_NAME": sess.Name,
+	if sess.Metadata.PendingLaunch {
+		defer teardown()
+		return launchPending(clotildeRoot, sess, settingsFile, additionalArgs, claudeBin)
 	}
 
+	l := newLaunch(sess, []string{"--resume", sess.Metadata.SessionID, "-n", sess.Name}, settingsFile, additionalArgs)
+	l.Binary = claudeBin
+
+	events.Record(clotildeRoot, events.Event{Type: events.SessionResumed, Session: sess.Name, SessionID: sess.Metadata.SessionID})
+
 	if sess.Metadata.IsIncognito {
-		return invokeWithCleanup(clotildeRoot, sess, args, env)
+		return invokeWithCleanup(clotildeRoot, sess, l, teardown)
 	}
-
-	return invokeInteractive(args, env)
+	defer teardown()
+
+	// Only sessions that were already empty are cleaned up afterwards, so a
+	// transcript clotilde can't locate never costs a used session
+	wasEmpty := !SessionUsedFunc(clotildeRoot, sess)
+	err = invokeInteractive(clotildeRoot, sess, l)
+	if wasEmpty {
+		if status := exitStatusFromError(err, time.Now()); err == nil || (status != nil && !status.Crashed()) {
+			cleanupEmptySession(clotildeRoot, sess)
+		}
+	}
+	return err
 }
 
 // Fork invokes claude CLI to fork an existing session.
 // The parent session will be resumed with --fork-session flag.
 // For ephemeral forks, cleanup will happen when Claude exits.
 func Fork(clotildeRoot string, parentSess *session.Session, forkName string, settingsFile string, additionalArgs []string, forkSession *session.Session) error {
-	args := []string{"--resume", parentSess.Metadata.SessionID, "--fork-session", "--session-id", forkSession.Metadata.SessionID, "-n", forkName}
-	args = appendCommonArgs(args, settingsFile)
-	args = append(args, additionalArgs...)
+	l := newLaunch(forkSession, []string{"--resume", parentSess.Metadata.SessionID, "--fork-session", "--session-id", forkSession.Metadata.SessionID, "-n", forkName}, settingsFile, additionalArgs)
 
-	env := map[string]string{
-		"CLOTILDE_SESSION_NAME": forkName,
+	if forkSession.Metadata.IsIncognito {
+		return invokeWithCleanup(clotildeRoot, forkSession, l, nil)
 	}
 
-	if forkSession.Metadata.IsIncognito {
-		return invokeWithCleanup(clotildeRoot, forkSession, args, env)
+	return finishNewSession(clotildeRoot, forkSession, invokeInteractive(clotildeRoot, forkSession, l))
+}
+
+// launchPending starts a session created with --no-launch. The pending flag is
+// cleared once Claude Code has written a transcript; an unused session stays
+// pending (rather than being removed) so it can still be launched later. A
+// prompt stored with the session is sent as the first message.
+func launchPending(clotildeRoot string, sess *session.Session, settingsFile string, additionalArgs []string, claudeBin string) error {
+	store := session.NewFileStore(clotildeRoot)
+
+	args := []string{"--session-id", sess.Metadata.SessionID, "-n", sess.Name}
+	if sess.Metadata.IsForkedSession {
+		parent, err := store.Get(sess.Metadata.ParentSession)
+		if err != nil {
+			return errs.NotFound("cannot start fork '%s': parent session '%s' not found", sess.Name, sess.Metadata.ParentSession)
+		}
+		args = append([]string{"--resume", parent.Metadata.SessionID, "--fork-session"}, args...)
 	}
+	l := newLaunch(sess, args, settingsFile, additionalArgs)
+	l.Binary = claudeBin
+	l.Prompt = sess.Metadata.InitialPrompt
 
-	err := invokeInteractive(args, env)
-	cleanupEmptySession(clotildeRoot, forkSession)
+	err := invokeInteractive(clotildeRoot, sess, l)
+
+	// Reload session from disk (hook may have updated metadata)
+	if current, getErr := store.Get(sess.Name); getErr == nil && SessionUsedFunc(clotildeRoot, current) {
+		current.Metadata.PendingLaunch = false
+		current.Metadata.InitialPrompt = ""
+		if updateErr := store.Update(current); updateErr != nil {
+			fmt.Fprintln(os.Stderr, ui.Warning(fmt.Sprintf("Failed to update session metadata: %v", updateErr)))
+		}
+	}
 	return err
 }
 
@@ -90,51 +143,129 @@ func Fork(clotildeRoot string, parentSess *session.Session, forkName string, set
 var ClaudeBinaryPathFunc func() string = func() string { return "claude" }
 
 // displayCommand prints the command being executed (always shown) and verbose debug info (if verbose mode).
-func displayCommand(claudeBin string, args []string, env map[string]string) {
-	// Always display the command being executed
-	cmdStr := claudeBin + " " + strings.Join(args, " ")
-	fmt.Fprintf(os.Stderr, "→ %s\n", cmdStr)
+// Only the names of the session's env file variables are shown, since they may hold secrets.
+func displayCommand(name string, args []string, env, sessionEnv map[string]string) {
+	// Always display the command being executed, quoted so it can be pasted
+	// into a shell to reproduce the run
+	fmt.Fprintln(os.Stderr, ui.Command(util.ShellJoin(append([]string{name}, args...))))
 
 	// Show additional debug info in verbose mode
 	if VerboseFunc() {
 		if len(env) > 0 {
 			fmt.Fprintln(os.Stderr, "[DEBUG] Environment variables:")
 			for k, v := range env {
-				fmt.Fprintf(os.Stderr, "  %s=%s\n", k, v)
+				fmt.Fprintf(os.Stderr, "  %s=%s\n", k, util.ShellQuote(v))
 			}
 		}
+		if len(sessionEnv) > 0 {
+			keys := slices.Sorted(maps.Keys(sessionEnv))
+			fmt.Fprintf(os.Stderr, "[DEBUG] Session env file: %s\n", strings.Join(keys, ", "))
+		}
 	}
 }
 
-// invokeInteractive executes the claude CLI command interactively.
-// Stdin, stdout, and stderr are connected to the current process.
-func invokeInteractive(args []string, env map[string]string) error {
-	claudeBin := ClaudeBinaryPathFunc()
+// invokeInteractive executes the claude CLI command interactively, through
+// the session's launcher when it has one (see SessionLauncher).
+// Stdin, stdout, and stderr are connected to the current process; the tail of
+// stderr is also kept so a failed run can be inspected with 'last-error', and
+// all of it goes to the session's claude.log when capture is enabled.
+func invokeInteractive(clotildeRoot string, sess *session.Session, l Launch) error {
+	launcher, err := SessionLauncher(clotildeRoot, sess)
+	if err != nil {
+		return err
+	}
+	claudeBin := l.Binary
+	if claudeBin == "" {
+		claudeBin = ClaudeBinaryPathFunc()
+	}
+	name, args := l.Command(launcher, claudeBin)
+	env := map[string]string{
+		"CLOTILDE_SESSION_NAME": sess.Name,
+	}
+
+	if os.Getenv(ConfigDirEnv) == "" {
+		// Make claude use the config dir relocated in clotilde's config
+		if homeDir, err := util.HomeDir(); err == nil {
+			if dir := configDirOverride(homeDir); dir != "" {
+				env[ConfigDirEnv] = dir
+			}
+		}
+	}
+
+	// Variables from the session's env file; clotilde's own take precedence
+	sessionEnv, err := session.NewFileStore(clotildeRoot).LoadEnv(sess.Name)
+	if err != nil {
+		return fmt.Errorf("failed to load session env: %w", err)
+	}
 
 	// Display the command being executed
-	displayCommand(claudeBin, args, env)
+	displayCommand(name, args, env, sessionEnv)
 
-	cmd := exec.Command(claudeBin, args...)
+	cmd := exec.Command(name, args...)
 
 	// Set up stdio
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	stderrTail := &tailBuffer{max: stderrTailSize}
+	sessionLog := openSessionLog(clotildeRoot, sess.Name, args)
+	if sessionLog != nil {
+		defer func() { _ = sessionLog.Close() }()
+	}
+	cmd.Stderr = stderrWriters(stderrTail, sessionLog)
 
 	// Set environment variables
 	cmd.Env = os.Environ()
+	for key, value := range sessionEnv {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", key, value))
+	}
 	for key, value := range env {
 		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", key, value))
 	}
 
-	return cmd.Run()
+	// Only a terminal understands the title escapes
+	if TerminalTitleFunc(clotildeRoot) && isatty.IsTerminal(os.Stdout.Fd()) && os.Getenv("TERM") != "dumb" {
+		cmd.Env = append(cmd.Env, disableTitleEnv+"=1")
+		defer SetTerminalTitle(os.Stdout, sess.Name)()
+	}
+
+	previousStatus := markActive(clotildeRoot, sess)
+
+	// Claude Code reads the session's output style itself, so an encrypted one
+	// is decrypted while it runs. Only once the session is active, so that the
+	// housekeeping of other clotilde commands leaves it alone; if this process
+	// is killed, that housekeeping encrypts it again.
+	stylePath := filepath.Join(config.ProjectClaudeDir(clotildeRoot), "output-styles", "clotilde", sess.Name+".md")
+	reseal, err := crypt.Unsealed(stylePath)
+	if err != nil {
+		recordExit(clotildeRoot, sess, err, nil, previousStatus)
+		return fmt.Errorf("failed to decrypt output style: %w", err)
+	}
+	defer func() {
+		if err := reseal(); err != nil {
+			fmt.Fprintln(os.Stderr, ui.Warning(fmt.Sprintf("Failed to re-encrypt output style: %v", err)))
+		}
+	}()
+
+	stopTiming := timing.Track("claude")
+	err = cmd.Run()
+	stopTiming()
+	recordExit(clotildeRoot, sess, err, stderrTail.Bytes(), previousStatus)
+	if err != nil {
+		return errs.ClaudeFailed("claude failed: %w", err)
+	}
+	return nil
 }
 
 // invokeWithCleanup runs claude and cleans up incognito session on exit.
 // Uses defer to ensure cleanup runs even on panic or interrupt (Ctrl+C).
-func invokeWithCleanup(clotildeRoot string, sess *session.Session, args []string, env map[string]string) error {
+// teardown (if not nil) runs first, while the session's folder, scripts and
+// env file still exist.
+func invokeWithCleanup(clotildeRoot string, sess *session.Session, l Launch, teardown func()) error {
 	// Setup cleanup to run after claude exits (even on panic/Ctrl+C)
 	defer func() {
+		if teardown != nil {
+			teardown()
+		}
 		deleted, err := cleanupIncognitoSession(clotildeRoot, sess)
 		if err != nil {
 			fmt.Fprintln(os.Stderr, ui.Warning(fmt.Sprintf("Failed to cleanup incognito session: %v", err)))
@@ -164,7 +295,7 @@ func invokeWithCleanup(clotildeRoot string, sess *session.Session, args []string
 	}()
 
 	// Run claude (blocks until exit)
-	return invokeInteractive(args, env)
+	return invokeInteractive(clotildeRoot, sess, l)
 }
 
 // cleanupIncognitoSession deletes session folder and Claude data.
@@ -216,10 +347,62 @@ func DefaultSessionUsed(clotildeRoot string, sess *session.Session) bool {
 	return util.FileExists(transcriptPath)
 }
 
+// EmptySession reports whether sess was launched but never used: Claude Code
+// wrote no transcript for it. Sessions created with --no-launch count as
+// empty until their first launch; incognito sessions (which clean up after
+// themselves) and sessions with Claude Code running never do.
+func EmptySession(clotildeRoot string, sess *session.Session) bool {
+	if sess.Metadata.IsIncognito || sess.Status() == session.StatusActive {
+		return false
+	}
+	return !SessionUsedFunc(clotildeRoot, sess)
+}
+
+// LaunchError reports that claude failed before a session it was starting
+// was used: it couldn't be run (e.g. binary missing) or exited with an error
+// before writing a transcript (e.g. a bad flag). The session is left in place
+// for the caller to keep for a retry or roll back.
+type LaunchError struct {
+	Session string
+	Err     error  // The error from running claude
+	Stderr  []byte // Tail of claude's error output, when it ran
+}
+
+func (e *LaunchError) Error() string { return e.Err.Error() }
+
+func (e *LaunchError) Unwrap() error { return e.Err }
+
+// finishNewSession handles a new session once claude exits after runErr: a
+// failed launch becomes a LaunchError, and a session left unused after a
+// normal exit is removed.
+func finishNewSession(clotildeRoot string, sess *session.Session, runErr error) error {
+	status := exitStatusFromError(runErr, time.Now())
+	if runErr == nil || (status != nil && !status.Crashed()) {
+		cleanupEmptySession(clotildeRoot, sess)
+		return runErr
+	}
+
+	// Reload session from disk (hook may have updated metadata)
+	current, err := session.NewFileStore(clotildeRoot).Get(sess.Name)
+	if err != nil || SessionUsedFunc(clotildeRoot, current) {
+		return runErr
+	}
+	launchErr := &LaunchError{Session: sess.Name, Err: runErr}
+	if status != nil {
+		launchErr.Stderr, _ = os.ReadFile(LastErrorPath(clotildeRoot, sess.Name))
+	}
+	return launchErr
+}
+
 // cleanupEmptySession removes a session if Claude Code never created a transcript.
-// This handles the case where the user starts a session but exits without sending
-// any messages, leaving a ghost session in clotilde's store.
+// This handles the case where the user starts (or resumes an empty) session but
+// exits without sending any messages, leaving a ghost session in clotilde's
+// store. Turned off with "emptySessions": {"autoRemove": false}.
 func cleanupEmptySession(clotildeRoot string, sess *session.Session) {
+	if enabled, err := config.AutoRemoveEmptySessions(clotildeRoot); err != nil || !enabled {
+		return
+	}
+
 	// Reload session from disk (hook may have updated metadata)
 	store := session.NewFileStore(clotildeRoot)
 	current, err := store.Get(sess.Name)
@@ -0,0 +1,119 @@
+package claude
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/session"
+)
+
+// Placeholders of a launcher template, a command clotilde runs instead of
+// claude (e.g. a wrapper script or a Claude-compatible CLI). An argument that
+// is only a placeholder is dropped when the placeholder is empty.
+const (
+	LauncherClaude    = "{claude}"    // The claude binary
+	LauncherArgs      = "{args}"      // Whole argument: the arguments clotilde builds for claude
+	LauncherSessionID = "{sessionId}" // Claude Code's session ID
+	LauncherName      = "{name}"      // The session name
+	LauncherSettings  = "{settings}"  // The session's settings file, if it has one
+	LauncherPrompt    = "{prompt}"    // The first message, if clotilde sends one
+)
+
+var launcherPlaceholders = []string{LauncherClaude, LauncherArgs, LauncherSessionID, LauncherName, LauncherSettings, LauncherPrompt}
+
+var placeholderRe = regexp.MustCompile(`\{[A-Za-z]+\}`)
+
+// ValidateLauncher checks a launcher template: it needs a command, only known
+// placeholders, and {args} as a whole argument.
+func ValidateLauncher(launcher []string) error {
+	if len(launcher) == 0 || strings.TrimSpace(launcher[0]) == "" {
+		return fmt.Errorf("launcher has no command")
+	}
+	for _, arg := range launcher {
+		for _, p := range placeholderRe.FindAllString(arg, -1) {
+			if !slices.Contains(launcherPlaceholders, p) {
+				return fmt.Errorf("unknown launcher placeholder %s (expected one of %s)", p, strings.Join(launcherPlaceholders, ", "))
+			}
+		}
+		if arg != LauncherArgs && strings.Contains(arg, LauncherArgs) {
+			return fmt.Errorf("launcher placeholder %s must be a whole argument", LauncherArgs)
+		}
+	}
+	return nil
+}
+
+// Launch is one run of claude for a session.
+type Launch struct {
+	Binary    string // The claude executable; ClaudeBinaryPathFunc() when empty
+	SessionID string
+	Name      string
+	Settings  string   // Passed with --settings (already in Args); empty when none
+	Args      []string // Clotilde's flags and the additional arguments
+	Prompt    string   // First message, passed after Args; empty when none
+}
+
+// Command returns the command running l: claudeBin with l's arguments, or,
+// with a launcher template, the template with its placeholders filled in.
+// Without {args} in the template, the arguments are appended to it; the
+// prompt goes with them unless the template places {prompt} itself.
+func (l Launch) Command(launcher []string, claudeBin string) (string, []string) {
+	args := slices.Clone(l.Args)
+	if len(launcher) == 0 {
+		if l.Prompt != "" {
+			args = append(args, l.Prompt)
+		}
+		return claudeBin, args
+	}
+
+	usesPrompt := slices.ContainsFunc(launcher, func(arg string) bool { return strings.Contains(arg, LauncherPrompt) })
+	if !usesPrompt && l.Prompt != "" {
+		args = append(args, l.Prompt)
+	}
+
+	replacer := strings.NewReplacer(
+		LauncherClaude, claudeBin,
+		LauncherSessionID, l.SessionID,
+		LauncherName, l.Name,
+		LauncherSettings, l.Settings,
+		LauncherPrompt, l.Prompt,
+	)
+	var command []string
+	for _, arg := range launcher {
+		if arg == LauncherArgs {
+			command = append(command, args...)
+			continue
+		}
+		expanded := replacer.Replace(arg)
+		if expanded == "" && slices.Contains(launcherPlaceholders, arg) {
+			continue
+		}
+		command = append(command, expanded)
+	}
+	if !slices.Contains(launcher, LauncherArgs) {
+		command = append(command, args...)
+	}
+	if len(command) == 0 {
+		return claudeBin, args
+	}
+	return command[0], command[1:]
+}
+
+// SessionLauncher returns the launcher template sess runs through: its own,
+// else the "defaults.launcher" config, which is validated since no command
+// checked it. Nil means claude is run directly.
+func SessionLauncher(clotildeRoot string, sess *session.Session) ([]string, error) {
+	if len(sess.Metadata.Launcher) > 0 {
+		return sess.Metadata.Launcher, nil
+	}
+	defaults, err := config.MergedDefaults(clotildeRoot)
+	if err != nil || len(defaults.Launcher) == 0 {
+		return nil, nil
+	}
+	if err := ValidateLauncher(defaults.Launcher); err != nil {
+		return nil, fmt.Errorf("invalid \"defaults.launcher\" config: %w", err)
+	}
+	return defaults.Launcher, nil
+}
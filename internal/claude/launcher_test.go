@@ -0,0 +1,57 @@
+package claude_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/claude"
+)
+
+var _ = Describe("Launch", func() {
+	launch := claude.Launch{
+		SessionID: "uuid-1",
+		Name:      "auth",
+		Args:      []string{"--resume", "uuid-1", "-n", "auth"},
+		Prompt:    "fix the tests",
+	}
+
+	It("runs claude directly without a launcher", func() {
+		name, args := launch.Command(nil, "/bin/claude")
+		Expect(name).To(Equal("/bin/claude"))
+		Expect(args).To(Equal([]string{"--resume", "uuid-1", "-n", "auth", "fix the tests"}))
+	})
+
+	It("fills in the placeholders of a launcher", func() {
+		name, args := launch.Command([]string{"wrap", "--id={sessionId}", "--", "{claude}", "{args}", "--title", "{name}"}, "claude")
+		Expect(name).To(Equal("wrap"))
+		Expect(args).To(Equal([]string{"--id=uuid-1", "--", "claude", "--resume", "uuid-1", "-n", "auth", "fix the tests", "--title", "auth"}))
+	})
+
+	It("appends the arguments when the launcher doesn't place them", func() {
+		_, args := launch.Command([]string{"wrap", "{claude}"}, "claude")
+		Expect(args).To(Equal([]string{"claude", "--resume", "uuid-1", "-n", "auth", "fix the tests"}))
+	})
+
+	It("keeps the prompt out of the arguments when the launcher places it", func() {
+		_, args := launch.Command([]string{"alt-cli", "{args}", "--message", "{prompt}"}, "claude")
+		Expect(args).To(Equal([]string{"--resume", "uuid-1", "-n", "auth", "--message", "fix the tests"}))
+	})
+
+	It("drops arguments whose placeholder is empty", func() {
+		_, args := launch.Command([]string{"wrap", "{settings}", "{args}"}, "claude")
+		Expect(args).NotTo(ContainElement(""))
+		Expect(args[0]).To(Equal("--resume"))
+	})
+
+	Describe("ValidateLauncher", func() {
+		It("accepts known placeholders", func() {
+			Expect(claude.ValidateLauncher([]string{"wrap", "--session={sessionId}", "{claude}", "{args}"})).To(Succeed())
+		})
+
+		It("rejects an empty command, unknown placeholders and {args} inside an argument", func() {
+			Expect(claude.ValidateLauncher(nil)).To(MatchError(ContainSubstring("no command")))
+			Expect(claude.ValidateLauncher([]string{"wrap", "{session}"})).To(MatchError(ContainSubstring("unknown launcher placeholder {session}")))
+			Expect(claude.ValidateLauncher([]string{"wrap", "--args={args}"})).To(MatchError(ContainSubstring("whole argument")))
+		})
+	})
+})
@@ -1,31 +1,120 @@
 package claude
 
 import (
+	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/session"
 )
 
+// ConfigDirEnv is the environment variable Claude Code reads its config
+// directory (settings, transcripts, output styles) from.
+const ConfigDirEnv = "CLAUDE_CONFIG_DIR"
+
+// ConfigDir returns Claude Code's config directory: $CLAUDE_CONFIG_DIR when
+// set, then "claudeConfigDir" from the global clotilde config, else ~/.claude.
+func ConfigDir(homeDir string) string {
+	if dir := os.Getenv(ConfigDirEnv); dir != "" {
+		return dir
+	}
+	if dir := configDirOverride(homeDir); dir != "" {
+		return dir
+	}
+	return filepath.Join(homeDir, ".claude")
+}
+
+// configDirOverride returns the "claudeConfigDir" set in the global clotilde
+// config with a leading ~ expanded, or "" when unset.
+func configDirOverride(homeDir string) string {
+	cfg, err := config.LoadGlobalOrDefault()
+	if err != nil || cfg.ClaudeConfigDir == "" {
+		return ""
+	}
+	if rest, ok := strings.CutPrefix(cfg.ClaudeConfigDir, "~/"); ok {
+		return filepath.Join(homeDir, rest)
+	}
+	return cfg.ClaudeConfigDir
+}
+
 // ProjectDir converts a clotilde root path to Claude Code's project directory format.
-// Claude Code stores project data in ~/.claude/projects/<encoded-path>/
+// Claude Code stores project data in <config dir>/projects/<encoded-path>/
 // where the path is encoded by replacing / and . with -
 //
 // Example:
 //
 //	/home/user/project/.claude/clotilde -> ~/.claude/projects/-home-user-project
 func ProjectDir(clotildeRoot string) string {
-	// Get the project root (parent of .claude/clotilde)
-	projectRoot := filepath.Dir(filepath.Dir(clotildeRoot))
+	return EncodeProjectPath(config.ProjectRootOf(clotildeRoot))
+}
 
-	// Convert path: replace / and . with -
+// EncodeProjectPath converts a project root into the name of its folder in
+// Claude Code's projects directory by replacing / and . with -.
+func EncodeProjectPath(projectRoot string) string {
 	encoded := strings.ReplaceAll(projectRoot, "/", "-")
-	encoded = strings.ReplaceAll(encoded, ".", "-")
+	return strings.ReplaceAll(encoded, ".", "-")
+}
+
+// ProjectDataDir returns the directory holding a project's transcripts and
+// agent logs in Claude's storage.
+// Format: <config dir>/projects/<project-dir>
+func ProjectDataDir(homeDir, clotildeRoot string) string {
+	return filepath.Join(ConfigDir(homeDir), "projects", ProjectDir(clotildeRoot))
+}
+
+// SnapshotPath returns where a superseded transcript is copied to in the
+// session folder when "clear.transcript" is "snapshot".
+func SnapshotPath(clotildeRoot, name, sessionID string) string {
+	return filepath.Join(config.GetSessionDir(clotildeRoot, name), "snapshots", sessionID+".jsonl")
+}
+
+// PreviousTranscriptPath returns the transcript of a superseded session ID:
+// the path recorded when it was superseded, or one computed from the UUID for
+// entries migrated from the legacy ID list.
+func PreviousTranscriptPath(homeDir, clotildeRoot string, prev session.PreviousSession) string {
+	if prev.TranscriptPath != "" {
+		return prev.TranscriptPath
+	}
+	return TranscriptPath(homeDir, clotildeRoot, prev.SessionID)
+}
+
+// SessionTranscriptPaths returns paths for all transcripts associated with a
+// session, in chronological order: previous UUIDs first (oldest to newest),
+// then the current one. Callers should skip paths that do not exist on disk
+// (missing transcripts are not an error).
+func SessionTranscriptPaths(homeDir, clotildeRoot string, sess *session.Session) []string {
+	var paths []string
+
+	for _, prev := range sess.Metadata.PreviousSessions {
+		if prev.SessionID == "" {
+			continue
+		}
+		paths = append(paths, PreviousTranscriptPath(homeDir, clotildeRoot, prev))
+	}
+
+	if current := CurrentTranscriptPath(homeDir, clotildeRoot, sess); current != "" {
+		paths = append(paths, current)
+	}
+
+	return paths
+}
 
-	return encoded
+// CurrentTranscriptPath returns the transcript that resuming sess continues:
+// the path saved in metadata, or one computed from the UUID. Empty when the
+// session has no UUID.
+func CurrentTranscriptPath(homeDir, clotildeRoot string, sess *session.Session) string {
+	if sess.Metadata.TranscriptPath != "" {
+		return sess.Metadata.TranscriptPath
+	}
+	if sess.Metadata.SessionID == "" {
+		return ""
+	}
+	return TranscriptPath(homeDir, clotildeRoot, sess.Metadata.SessionID)
 }
 
 // TranscriptPath returns the path to a session's transcript file in Claude's storage.
-// Format: ~/.claude/projects/<project-dir>/<session-id>.jsonl
+// Format: <config dir>/projects/<project-dir>/<session-id>.jsonl
 func TranscriptPath(homeDir, clotildeRoot, sessionID string) string {
-	projectDir := ProjectDir(clotildeRoot)
-	return filepath.Join(homeDir, ".claude", "projects", projectDir, sessionID+".jsonl")
+	return filepath.Join(ProjectDataDir(homeDir, clotildeRoot), sessionID+".jsonl")
 }
@@ -1,15 +1,22 @@
 package claude_test
 
 import (
+	"os"
 	"path/filepath"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	"github.com/fgrehm/clotilde/internal/claude"
+	"github.com/fgrehm/clotilde/internal/session"
 )
 
 var _ = Describe("Paths", func() {
+	BeforeEach(func() {
+		GinkgoT().Setenv("CLAUDE_CONFIG_DIR", "")
+		GinkgoT().Setenv("XDG_CONFIG_HOME", GinkgoT().TempDir())
+	})
+
 	Describe("ProjectDir", func() {
 		It("should encode project path correctly", func() {
 			clotildeRoot := "/home/user/project/.claude/clotilde"
@@ -41,4 +48,62 @@ var _ = Describe("Paths", func() {
 			Expect(path).To(Equal(expected))
 		})
 	})
+
+	Describe("SessionTranscriptPaths", func() {
+		homeDir := "/home/user"
+		clotildeRoot := "/tmp/.claude/clotilde"
+
+		It("is empty for a fork whose UUID the hook hasn't filled in yet", func() {
+			Expect(claude.SessionTranscriptPaths(homeDir, clotildeRoot, &session.Session{})).To(BeEmpty())
+		})
+
+		It("computes the current path from the UUID", func() {
+			sess := session.NewSession("work", "abc-123")
+			Expect(claude.SessionTranscriptPaths(homeDir, clotildeRoot, sess)).To(Equal([]string{
+				claude.TranscriptPath(homeDir, clotildeRoot, "abc-123"),
+			}))
+		})
+
+		It("prefers the TranscriptPath saved in metadata", func() {
+			sess := session.NewSession("work", "abc-123")
+			sess.Metadata.TranscriptPath = "/home/user/.claude/projects/foo/abc.jsonl"
+			Expect(claude.SessionTranscriptPaths(homeDir, clotildeRoot, sess)).To(Equal([]string{sess.Metadata.TranscriptPath}))
+		})
+
+		It("lists previous UUIDs before the current one", func() {
+			sess := session.NewSession("work", "current-uuid")
+			sess.Metadata.PreviousSessions = []session.PreviousSession{{SessionID: "old-uuid-1"}, {SessionID: "old-uuid-2"}}
+			Expect(claude.SessionTranscriptPaths(homeDir, clotildeRoot, sess)).To(Equal([]string{
+				claude.TranscriptPath(homeDir, clotildeRoot, "old-uuid-1"),
+				claude.TranscriptPath(homeDir, clotildeRoot, "old-uuid-2"),
+				claude.TranscriptPath(homeDir, clotildeRoot, "current-uuid"),
+			}))
+		})
+	})
+
+	Describe("ConfigDir", func() {
+		writeGlobalConfig := func(content string) {
+			path := filepath.Join(os.Getenv("XDG_CONFIG_HOME"), "clotilde", "config.json")
+			Expect(os.MkdirAll(filepath.Dir(path), 0o755)).To(Succeed())
+			Expect(os.WriteFile(path, []byte(content), 0o644)).To(Succeed())
+		}
+
+		It("defaults to ~/.claude", func() {
+			Expect(claude.ConfigDir("/home/user")).To(Equal("/home/user/.claude"))
+		})
+
+		It("uses claudeConfigDir from the global config", func() {
+			writeGlobalConfig(`{"claudeConfigDir": "~/.config/claude"}`)
+			Expect(claude.ConfigDir("/home/user")).To(Equal("/home/user/.config/claude"))
+		})
+
+		It("prefers CLAUDE_CONFIG_DIR over the config", func() {
+			writeGlobalConfig(`{"claudeConfigDir": "/opt/claude"}`)
+			GinkgoT().Setenv("CLAUDE_CONFIG_DIR", "/srv/claude")
+			Expect(claude.ConfigDir("/home/user")).To(Equal("/srv/claude"))
+
+			path := claude.TranscriptPath("/home/user", "/home/user/project/.claude/clotilde", "abc")
+			Expect(path).To(Equal("/srv/claude/projects/-home-user-project/abc.jsonl"))
+		})
+	})
 })
@@ -0,0 +1,93 @@
+package claude
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// recapMaxLen is how many characters of a prompt or reply a recap keeps.
+const recapMaxLen = 120
+
+// Recap is where a conversation left off: the last prompt and the reply to it.
+type Recap struct {
+	Prompt string    // Last thing the user typed
+	Reply  string    // Last text Claude answered with; empty if it didn't get to reply
+	At     time.Time // Timestamp of the last entry
+}
+
+// ReadRecap returns the last prompt and reply from the transcript, each
+// flattened to one line and shortened. Tool calls, tool results, thinking and
+// slash command output are skipped. The transcript is read backwards, so only
+// the tail of a long conversation is read. Returns a zero Recap when there's
+// no prompt.
+func ReadRecap(transcriptPath string) (Recap, error) {
+	var recap Recap
+	err := forEachLineReverse(transcriptPath, func(line []byte) bool {
+		var e struct {
+			Type      string    `json:"type"`
+			Timestamp time.Time `json:"timestamp"`
+			IsMeta    bool      `json:"isMeta"`
+			Message   struct {
+				Content json.RawMessage `json:"content"`
+			} `json:"message"`
+		}
+		if err := json.Unmarshal(line, &e); err != nil {
+			return true
+		}
+		if recap.At.IsZero() {
+			recap.At = e.Timestamp
+		}
+		if e.IsMeta {
+			return true
+		}
+
+		text := messageText(e.Message.Content)
+		switch {
+		case text == "":
+		case e.Type == "assistant" && recap.Reply == "":
+			recap.Reply = shortenRecapText(text)
+		case e.Type == "user" && !strings.HasPrefix(text, "<"):
+			recap.Prompt = shortenRecapText(text)
+			return false
+		}
+		return true
+	})
+	if err != nil || recap.Prompt == "" {
+		return Recap{}, err
+	}
+	return recap, nil
+}
+
+// messageText returns the text of a message's content: either a plain string
+// or the "text" blocks of a list of content blocks.
+func messageText(content json.RawMessage) string {
+	var s string
+	if json.Unmarshal(content, &s) == nil {
+		return strings.TrimSpace(s)
+	}
+
+	var blocks []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	if json.Unmarshal(content, &blocks) != nil {
+		return ""
+	}
+	var parts []string
+	for _, b := range blocks {
+		if b.Type == "text" && strings.TrimSpace(b.Text) != "" {
+			parts = append(parts, strings.TrimSpace(b.Text))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// shortenRecapText collapses whitespace and cuts text to recapMaxLen runes.
+func shortenRecapText(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if runes := []rune(text); len(runes) > recapMaxLen {
+		return strings.TrimSpace(string(runes[:recapMaxLen-1])) + "…"
+	}
+	return text
+}
@@ -0,0 +1,89 @@
+package claude_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fgrehm/clotilde/internal/claude"
+)
+
+func TestReadRecap(t *testing.T) {
+	tests := []struct {
+		name       string
+		transcript []string
+		prompt     string
+		reply      string
+	}{
+		{
+			name: "last prompt and reply",
+			transcript: []string{
+				`{"type":"user","message":{"content":"first question"}}`,
+				`{"type":"assistant","message":{"content":[{"type":"text","text":"first answer"}]}}`,
+				`{"type":"user","message":{"content":[{"type":"text","text":"run   the\ntests"}]}}`,
+				`{"type":"assistant","message":{"content":[{"type":"thinking","thinking":"hmm"},{"type":"tool_use","name":"Bash"}]}}`,
+				`{"type":"user","message":{"content":[{"type":"tool_result","content":"FAIL"}]}}`,
+				`{"type":"assistant","message":{"content":[{"type":"text","text":"Two tests fail on the auth mock."}]}}`,
+			},
+			prompt: "run the tests",
+			reply:  "Two tests fail on the auth mock.",
+		},
+		{
+			name: "unanswered prompt",
+			transcript: []string{
+				`{"type":"assistant","message":{"content":[{"type":"text","text":"done"}]}}`,
+				`{"type":"user","message":{"content":"now deploy it"}}`,
+			},
+			prompt: "now deploy it",
+		},
+		{
+			name: "skips meta entries and slash command output",
+			transcript: []string{
+				`{"type":"user","message":{"content":"refactor the parser"}}`,
+				`{"type":"assistant","message":{"content":[{"type":"text","text":"Refactored."}]}}`,
+				`{"type":"user","isMeta":true,"message":{"content":"Caveat: generated by a command"}}`,
+				`{"type":"user","message":{"content":"<command-name>/model</command-name>"}}`,
+			},
+			prompt: "refactor the parser",
+			reply:  "Refactored.",
+		},
+		{
+			name:       "no prompt",
+			transcript: []string{`{"type":"summary","summary":"x"}`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "transcript.jsonl")
+			if err := os.WriteFile(path, []byte(strings.Join(tt.transcript, "\n")+"\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			recap, err := claude.ReadRecap(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if recap.Prompt != tt.prompt || recap.Reply != tt.reply {
+				t.Errorf("got prompt %q, reply %q; want %q, %q", recap.Prompt, recap.Reply, tt.prompt, tt.reply)
+			}
+		})
+	}
+}
+
+func TestReadRecap_Shortens(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "transcript.jsonl")
+	line := `{"type":"user","message":{"content":"` + strings.Repeat("word ", 100) + `"}}`
+	if err := os.WriteFile(path, []byte(line), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	recap, err := claude.ReadRecap(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len([]rune(recap.Prompt)); n != 120 || !strings.HasSuffix(recap.Prompt, "…") {
+		t.Errorf("Expected a 120-character prompt ending in an ellipsis, got %d: %q", n, recap.Prompt)
+	}
+}
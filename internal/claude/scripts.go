@@ -0,0 +1,114 @@
+package claude
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/ui"
+	"github.com/fgrehm/clotilde/internal/util"
+)
+
+// Scripts in a session folder run around resuming the session: prepare before
+// claude starts (e.g. to start docker compose), teardown after it exits. They
+// take precedence over "resume.prepare" and "resume.teardown" in the config.
+const (
+	PrepareScript  = "prepare"
+	TeardownScript = "teardown"
+)
+
+// sessionScript returns the command that runs the session's script of kind:
+// the file in the session folder (run directly when executable, with sh
+// otherwise), else the configured shell command. Returns nil when there is
+// neither.
+func sessionScript(clotildeRoot, name, kind string) ([]string, error) {
+	path := filepath.Join(config.GetSessionDir(clotildeRoot, name), kind)
+	if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+		if info.Mode().Perm()&0o111 != 0 {
+			return []string{path}, nil
+		}
+		return []string{"sh", path}, nil
+	}
+
+	prepare, teardown, err := config.ResumeScripts(clotildeRoot)
+	if err != nil {
+		return nil, err
+	}
+	command := prepare
+	if kind == TeardownScript {
+		command = teardown
+	}
+	if command == "" {
+		return nil, nil
+	}
+	return []string{"sh", "-c", command}, nil
+}
+
+// runScript runs a session script in the project root, with the session's
+// env file and name in its environment, and returns its combined output.
+func runScript(clotildeRoot string, sess *session.Session, kind string, command []string) ([]byte, error) {
+	fmt.Fprintln(os.Stderr, ui.Command(kind+": "+util.ShellJoin(command)))
+
+	sessionEnv, err := session.NewFileStore(clotildeRoot).LoadEnv(sess.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load session env: %w", err)
+	}
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Dir = config.ProjectRootOf(clotildeRoot)
+	cmd.Env = os.Environ()
+	for key, value := range sessionEnv {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", key, value))
+	}
+	cmd.Env = append(cmd.Env, "CLOTILDE_SESSION_NAME="+sess.Name, "CLOTILDE_SESSION_ID="+sess.Metadata.SessionID)
+
+	var output bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &output, &output
+	err = cmd.Run()
+	if VerboseFunc() && output.Len() > 0 {
+		_, _ = os.Stderr.Write(output.Bytes())
+	}
+	return output.Bytes(), err
+}
+
+// prepareSession runs the session's prepare script, if any, and returns the
+// teardown to run once claude exits (a no-op when there's no teardown
+// script). A failing prepare script aborts the launch with its output.
+func prepareSession(clotildeRoot string, sess *session.Session) (func(), error) {
+	teardown, err := sessionScript(clotildeRoot, sess.Name, TeardownScript)
+	if err != nil {
+		return nil, err
+	}
+	prepare, err := sessionScript(clotildeRoot, sess.Name, PrepareScript)
+	if err != nil {
+		return nil, err
+	}
+
+	if prepare != nil {
+		if output, err := runScript(clotildeRoot, sess, PrepareScript, prepare); err != nil {
+			return nil, fmt.Errorf("prepare script failed, not launching claude: %w%s", err, scriptOutput(output))
+		}
+	}
+
+	return func() {
+		if teardown == nil {
+			return
+		}
+		if output, err := runScript(clotildeRoot, sess, TeardownScript, teardown); err != nil {
+			fmt.Fprintln(os.Stderr, ui.Warning(fmt.Sprintf("Teardown script failed: %v%s", err, scriptOutput(output))))
+		}
+	}, nil
+}
+
+// scriptOutput formats a script's output to follow an error message.
+func scriptOutput(output []byte) string {
+	text := strings.TrimRight(string(output), "\n")
+	if text == "" {
+		return ""
+	}
+	return "\n" + text
+}
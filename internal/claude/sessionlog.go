@@ -0,0 +1,123 @@
+package claude
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/ui"
+	"github.com/fgrehm/clotilde/internal/util"
+)
+
+// SessionLogFile is the per-session log that claude's stderr is copied to
+// when "logging.captureStderr" is enabled. It is rotated to SessionLogFile+".1".
+const SessionLogFile = "claude.log"
+
+// SessionLogPaths returns a session's log files, oldest first: the rotated
+// log and the current one.
+func SessionLogPaths(clotildeRoot, name string) []string {
+	current := filepath.Join(config.GetSessionDir(clotildeRoot, name), SessionLogFile)
+	return []string{current + ".1", current}
+}
+
+// RotateSessionLog moves a session's claude.log aside when it's larger than
+// the configured "logging.maxSizeKB", as writing to it would, so that a
+// lowered limit applies before the session runs again. Reports whether it
+// rotated the log.
+func RotateSessionLog(clotildeRoot, name string) (bool, error) {
+	logging, err := config.MergedLogging(clotildeRoot)
+	if err != nil {
+		return false, err
+	}
+	current := SessionLogPaths(clotildeRoot, name)[1]
+	info, err := os.Stat(current)
+	if err != nil || info.Size() <= int64(logging.MaxSizeKB)*1024 {
+		return false, nil
+	}
+	if err := os.Rename(current, current+".1"); err != nil {
+		return false, fmt.Errorf("failed to rotate %s: %w", current, err)
+	}
+	return true, nil
+}
+
+// rotatingLog appends to a file and moves it aside once it would grow past
+// max bytes, so at most two files of about max bytes each are kept. Write
+// never fails: a broken log must not interrupt claude's stderr.
+type rotatingLog struct {
+	path string
+	max  int64
+	file *os.File
+	size int64
+}
+
+// openSessionLog opens the session's claude.log for a new run and writes a
+// header marking where the run starts. Returns nil when capture is disabled or
+// the log can't be opened.
+func openSessionLog(clotildeRoot, name string, args []string) *rotatingLog {
+	logging, err := config.MergedLogging(clotildeRoot)
+	if err != nil || !*logging.CaptureStderr {
+		return nil
+	}
+
+	paths := SessionLogPaths(clotildeRoot, name)
+	l := &rotatingLog{path: paths[1], max: int64(logging.MaxSizeKB) * 1024}
+	if err := l.open(); err != nil {
+		fmt.Fprintln(os.Stderr, ui.Warning(fmt.Sprintf("Failed to open session log: %v", err)))
+		return nil
+	}
+	_, _ = fmt.Fprintf(l, "=== %s claude %s ===\n", time.Now().Format(time.RFC3339), util.ShellJoin(args))
+	return l
+}
+
+func (l *rotatingLog) open() error {
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	l.file, l.size = f, info.Size()
+	return nil
+}
+
+func (l *rotatingLog) Write(p []byte) (int, error) {
+	if l.file == nil {
+		return len(p), nil
+	}
+	if l.size > 0 && l.size+int64(len(p)) > l.max {
+		_ = l.file.Close()
+		l.file = nil
+		if err := os.Rename(l.path, l.path+".1"); err != nil {
+			return len(p), nil
+		}
+		if err := l.open(); err != nil {
+			return len(p), nil
+		}
+	}
+	n, _ := l.file.Write(p)
+	l.size += int64(n)
+	return len(p), nil
+}
+
+// Close closes the underlying file.
+func (l *rotatingLog) Close() error {
+	if l.file == nil {
+		return nil
+	}
+	return l.file.Close()
+}
+
+// stderrWriters tees claude's stderr to the terminal, the crash tail and, when
+// capture is enabled (log != nil), the session log.
+func stderrWriters(tail *tailBuffer, log *rotatingLog) io.Writer {
+	if log == nil {
+		return io.MultiWriter(os.Stderr, tail)
+	}
+	return io.MultiWriter(os.Stderr, tail, log)
+}
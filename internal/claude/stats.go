@@ -0,0 +1,232 @@
+package claude
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"os"
+	"time"
+)
+
+// ActiveGapLimit is the longest pause between transcript entries that still
+// counts as time spent in the session. Longer gaps are treated as idle.
+const ActiveGapLimit = 5 * time.Minute
+
+// TranscriptStats summarizes the activity recorded in a transcript.
+type TranscriptStats struct {
+	ActiveTime   time.Duration  `json:"activeTime"`            // Sum of gaps between entries, up to ActiveGapLimit each
+	Messages     int            `json:"messages"`              // Assistant messages
+	Models       map[string]int `json:"models"`                // Assistant messages per model family
+	ModelSpans   []ModelSpan    `json:"modelSpans"`            // Runs of consecutive messages from the same model family, oldest first
+	LastActivity time.Time      `json:"lastActivity"`          // Timestamp of the last entry
+	Approximate  bool           `json:"approximate,omitempty"` // Counts were extrapolated from a sample
+	Days         map[string]int `json:"days"`                  // Assistant messages per local day (DayFormat)
+}
+
+// DayFormat keys TranscriptStats.Days.
+const DayFormat = "2006-01-02"
+
+// ModelSpan is a run of consecutive assistant messages from one model family.
+type ModelSpan struct {
+	Model string    `json:"model"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	Turns int       `json:"turns"`
+}
+
+// Add merges other into s (e.g. to combine a session's transcripts). other's
+// model spans are taken to follow s's, as with transcripts added oldest first.
+func (s *TranscriptStats) Add(other TranscriptStats) {
+	s.ActiveTime += other.ActiveTime
+	s.Messages += other.Messages
+	for model, n := range other.Models {
+		if s.Models == nil {
+			s.Models = make(map[string]int)
+		}
+		s.Models[model] += n
+	}
+	for day, n := range other.Days {
+		if s.Days == nil {
+			s.Days = make(map[string]int)
+		}
+		s.Days[day] += n
+	}
+	for _, span := range other.ModelSpans {
+		s.addModelTurns(span.Model, span.Start, span.End, span.Turns)
+	}
+	if other.LastActivity.After(s.LastActivity) {
+		s.LastActivity = other.LastActivity
+	}
+	s.Approximate = s.Approximate || other.Approximate
+}
+
+// addModelTurns extends the last model span when the model is unchanged, or
+// starts a new one.
+func (s *TranscriptStats) addModelTurns(model string, start, end time.Time, turns int) {
+	if n := len(s.ModelSpans); n > 0 && s.ModelSpans[n-1].Model == model {
+		s.ModelSpans[n-1].End = end
+		s.ModelSpans[n-1].Turns += turns
+		return
+	}
+	s.ModelSpans = append(s.ModelSpans, ModelSpan{Model: model, Start: start, End: end, Turns: turns})
+}
+
+// ReadTranscriptStats scans the whole transcript and summarizes entries
+// timestamped at or after since. Transcripts last modified before since are
+// skipped without being read. A missing transcript yields empty stats.
+func ReadTranscriptStats(transcriptPath string, since time.Time) (TranscriptStats, error) {
+	stats := TranscriptStats{Models: make(map[string]int), Days: make(map[string]int)}
+
+	file, err := os.Open(transcriptPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return stats, nil
+		}
+		return stats, err
+	}
+	defer func() { _ = file.Close() }()
+
+	info, err := file.Stat()
+	if err != nil {
+		return stats, err
+	}
+	if info.ModTime().Before(since) {
+		return stats, nil
+	}
+
+	scanner := statsScanner{since: since, stats: stats}
+	// ReadBytes copes with arbitrarily long lines (large tool results)
+	reader := bufio.NewReader(file)
+	for {
+		line, readErr := reader.ReadBytes('\n')
+		scanner.add(line)
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
+		if readErr != nil {
+			return scanner.stats, readErr
+		}
+	}
+
+	return scanner.stats, nil
+}
+
+// statsSampleWindows is how many evenly spaced parts of a transcript
+// SampleTranscriptStats reads.
+const statsSampleWindows = 16
+
+// SampleTranscriptStats approximates ReadTranscriptStats (with a zero since)
+// for transcripts larger than budget bytes: it reads statsSampleWindows evenly
+// spaced windows totalling about budget bytes, always including the end, and
+// scales turns, models and active time by the share of the transcript they
+// cover. The result is marked Approximate. Smaller transcripts are read whole.
+func SampleTranscriptStats(transcriptPath string, budget int64) (TranscriptStats, error) {
+	info, err := os.Stat(transcriptPath)
+	if err != nil || info.Size() <= budget {
+		return ReadTranscriptStats(transcriptPath, time.Time{})
+	}
+
+	file, err := os.Open(transcriptPath)
+	if err != nil {
+		return TranscriptStats{}, err
+	}
+	defer func() { _ = file.Close() }()
+
+	size := info.Size()
+	window := budget / statsSampleWindows
+	buf := make([]byte, window)
+	scanner := statsScanner{stats: TranscriptStats{Models: make(map[string]int), Days: make(map[string]int)}}
+	var sampled int64
+	for i := range int64(statsSampleWindows) {
+		offset := i * (size - window) / (statsSampleWindows - 1)
+		if _, err := file.ReadAt(buf, offset); err != nil && !errors.Is(err, io.EOF) {
+			return TranscriptStats{}, err
+		}
+
+		// Only whole lines count: drop a line cut by either edge of the window
+		data := buf
+		if offset > 0 {
+			prev := make([]byte, 1)
+			if _, err := file.ReadAt(prev, offset-1); err != nil || prev[0] != '\n' {
+				cut := bytes.IndexByte(data, '\n')
+				if cut < 0 {
+					continue
+				}
+				data = data[cut+1:]
+			}
+		}
+		if offset+window < size {
+			data = data[:bytes.LastIndexByte(data, '\n')+1]
+		}
+
+		// Gaps between windows aren't known
+		scanner.previous = time.Time{}
+		for line := range bytes.Lines(data) {
+			scanner.add(line)
+		}
+		sampled += int64(len(data))
+	}
+
+	stats := scanner.stats
+	stats.Approximate = true
+	if sampled == 0 {
+		return stats, nil
+	}
+	ratio := float64(size) / float64(sampled)
+	scale := func(n int) int { return max(1, int(math.Round(float64(n)*ratio))) }
+	stats.ActiveTime = time.Duration(float64(stats.ActiveTime) * ratio)
+	stats.Messages = 0
+	for model, n := range stats.Models {
+		stats.Models[model] = scale(n)
+		stats.Messages += stats.Models[model]
+	}
+	for day, n := range stats.Days {
+		stats.Days[day] = scale(n)
+	}
+	for i := range stats.ModelSpans {
+		stats.ModelSpans[i].Turns = scale(stats.ModelSpans[i].Turns)
+	}
+	return stats, nil
+}
+
+// statsScanner accumulates TranscriptStats one transcript line at a time.
+type statsScanner struct {
+	since    time.Time
+	stats    TranscriptStats
+	previous time.Time // Latest timestamp since the last break in the lines
+}
+
+func (sc *statsScanner) add(line []byte) {
+	var e struct {
+		Type      string    `json:"type"`
+		Timestamp time.Time `json:"timestamp"`
+		Message   struct {
+			Model string `json:"model"`
+		} `json:"message"`
+	}
+	if len(line) == 0 || json.Unmarshal(line, &e) != nil || e.Timestamp.Before(sc.since) {
+		return
+	}
+
+	if !sc.previous.IsZero() {
+		if gap := e.Timestamp.Sub(sc.previous); gap > 0 && gap <= ActiveGapLimit {
+			sc.stats.ActiveTime += gap
+		}
+	}
+	if e.Timestamp.After(sc.previous) {
+		sc.previous = e.Timestamp
+	}
+	if e.Timestamp.After(sc.stats.LastActivity) {
+		sc.stats.LastActivity = e.Timestamp
+	}
+	if e.Type == "assistant" && e.Message.Model != "" {
+		model := FormatModelFamily(e.Message.Model)
+		sc.stats.Messages++
+		sc.stats.Models[model]++
+		sc.stats.Days[e.Timestamp.Local().Format(DayFormat)]++
+		sc.stats.addModelTurns(model, e.Timestamp, e.Timestamp, 1)
+	}
+}
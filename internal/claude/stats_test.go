@@ -0,0 +1,199 @@
+package claude_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fgrehm/clotilde/internal/claude"
+)
+
+func TestReadTranscriptStats(t *testing.T) {
+	transcript := strings.Join([]string{
+		`{"type":"user","timestamp":"2026-01-01T09:00:00Z"}`,
+		`{"type":"assistant","timestamp":"2026-01-05T10:00:00Z","message":{"model":"claude-opus-4-20250514"}}`,
+		`{"type":"user","timestamp":"2026-01-05T10:02:00Z"}`,
+		`{"type":"assistant","timestamp":"2026-01-05T10:03:00Z","message":{"model":"claude-sonnet-4-5-20250929"}}`,
+		`not json`,
+		// An hour-long pause is idle time, not activity
+		`{"type":"user","timestamp":"2026-01-05T11:03:00Z"}`,
+		`{"type":"assistant","timestamp":"2026-01-05T11:04:00Z","message":{"model":"claude-sonnet-4-5-20250929"}}`,
+	}, "\n")
+	path := filepath.Join(t.TempDir(), "transcript.jsonl")
+	if err := os.WriteFile(path, []byte(transcript), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	since := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	stats, err := claude.ReadTranscriptStats(path, since)
+	if err != nil {
+		t.Fatalf("ReadTranscriptStats failed: %v", err)
+	}
+
+	if stats.ActiveTime != 4*time.Minute {
+		t.Errorf("Expected 4m active, got %s", stats.ActiveTime)
+	}
+	if stats.Messages != 3 || stats.Models["opus"] != 1 || stats.Models["sonnet"] != 2 {
+		t.Errorf("Unexpected model breakdown: %d messages, %v", stats.Messages, stats.Models)
+	}
+	if want := time.Date(2026, 1, 5, 11, 4, 0, 0, time.UTC); !stats.LastActivity.Equal(want) {
+		t.Errorf("Expected last activity %s, got %s", want, stats.LastActivity)
+	}
+	if len(stats.ModelSpans) != 2 || stats.ModelSpans[0].Model != "opus" || stats.ModelSpans[1].Model != "sonnet" ||
+		stats.ModelSpans[1].Turns != 2 || !stats.ModelSpans[1].End.Equal(time.Date(2026, 1, 5, 11, 4, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected model spans: %+v", stats.ModelSpans)
+	}
+}
+
+func TestReadTranscriptStats_SkipsStaleAndMissingFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "transcript.jsonl")
+	stats, err := claude.ReadTranscriptStats(path, time.Time{})
+	if err != nil || stats.Messages != 0 {
+		t.Errorf("Expected empty stats for a missing transcript, got %+v, %v", stats, err)
+	}
+
+	line := `{"type":"assistant","timestamp":"2026-01-05T10:00:00Z","message":{"model":"claude-opus-4-20250514"}}`
+	if err := os.WriteFile(path, []byte(line), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-30 * 24 * time.Hour)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	// Not modified since the cutoff, so the file isn't read at all
+	stats, err = claude.ReadTranscriptStats(path, time.Now().Add(-7*24*time.Hour))
+	if err != nil || stats.Messages != 0 {
+		t.Errorf("Expected stale transcript to be skipped, got %+v, %v", stats, err)
+	}
+}
+
+func TestTranscriptStatsAdd(t *testing.T) {
+	var total claude.TranscriptStats
+	total.Add(claude.TranscriptStats{ActiveTime: time.Minute, Messages: 1, Models: map[string]int{"opus": 1}})
+	total.Add(claude.TranscriptStats{ActiveTime: time.Minute, Messages: 2, Models: map[string]int{"opus": 1, "sonnet": 1}})
+
+	if total.ActiveTime != 2*time.Minute || total.Messages != 3 || total.Models["opus"] != 2 || total.Models["sonnet"] != 1 {
+		t.Errorf("Unexpected totals: %+v", total)
+	}
+	spans := []claude.ModelSpan{{Model: "opus", Turns: 1}, {Model: "opus", Turns: 2}, {Model: "sonnet", Turns: 1}}
+	var merged claude.TranscriptStats
+	merged.Add(claude.TranscriptStats{ModelSpans: spans[:1]})
+	merged.Add(claude.TranscriptStats{ModelSpans: spans[1:]})
+	// The second transcript continues with the same model, so its span is merged
+	if len(merged.ModelSpans) != 2 || merged.ModelSpans[0].Turns != 3 || merged.ModelSpans[1].Model != "sonnet" {
+		t.Errorf("Unexpected merged spans: %+v", merged.ModelSpans)
+	}
+}
+
+func TestCachedTranscriptStats(t *testing.T) {
+	dir := t.TempDir()
+	cachePath := filepath.Join(dir, claude.StatsCacheFile)
+	path := filepath.Join(dir, "transcript.jsonl")
+	line := `{"type":"assistant","timestamp":"2026-01-05T10:00:00Z","message":{"model":"claude-opus-4-20250514"}}` + "\n"
+	if err := os.WriteFile(path, []byte(line), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	all, err := claude.CachedTranscriptStats(cachePath, []string{path, filepath.Join(dir, "missing.jsonl")}, 0)
+	if err != nil || len(all) != 1 || all[0].Models["opus"] != 1 {
+		t.Fatalf("Unexpected stats: %+v, %v", all, err)
+	}
+
+	// An unchanged transcript is served from the cache
+	cache, err := os.ReadFile(cachePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(cachePath, []byte(strings.Replace(string(cache), `"opus": 1`, `"opus": 7`, 1)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	all, err = claude.CachedTranscriptStats(cachePath, []string{path}, 0)
+	if err != nil || all[0].Models["opus"] != 7 {
+		t.Errorf("Expected cached stats, got %+v, %v", all, err)
+	}
+
+	// A modified transcript is rescanned
+	if err := os.WriteFile(path, []byte(line+line), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	all, err = claude.CachedTranscriptStats(cachePath, []string{path}, 0)
+	if err != nil || all[0].Models["opus"] != 2 {
+		t.Errorf("Expected rescanned stats, got %+v, %v", all, err)
+	}
+}
+
+func TestCachedModelAndLastTime(t *testing.T) {
+	dir := t.TempDir()
+	cachePath := filepath.Join(dir, claude.StatsCacheFile)
+	path := filepath.Join(dir, "transcript.jsonl")
+	line := `{"type":"assistant","timestamp":"2026-01-05T10:00:00Z","message":{"model":"claude-opus-4-20250514"}}` + "\n"
+	if err := os.WriteFile(path, []byte(line), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	model, lastTime := claude.CachedModelAndLastTime(cachePath, path)
+	if model != "opus" || lastTime.IsZero() {
+		t.Fatalf("Unexpected result: %q, %v", model, lastTime)
+	}
+
+	// An unchanged transcript is served from the cache
+	cache, err := os.ReadFile(cachePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(cachePath, []byte(strings.Replace(string(cache), `"lastModel": "opus"`, `"lastModel": "haiku"`, 1)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if model, _ := claude.CachedModelAndLastTime(cachePath, path); model != "haiku" {
+		t.Errorf("Expected cached model, got %q", model)
+	}
+
+	// An entry without stats is filled in by a full scan
+	all, err := claude.CachedTranscriptStats(cachePath, []string{path}, 0)
+	if err != nil || len(all) != 1 || all[0].Models["opus"] != 1 {
+		t.Errorf("Unexpected stats: %+v, %v", all, err)
+	}
+	if model, _ := claude.CachedModelAndLastTime(cachePath, path); model != "opus" {
+		t.Errorf("Expected model from the scan, got %q", model)
+	}
+
+	if model, lastTime := claude.CachedModelAndLastTime(cachePath, filepath.Join(dir, "missing.jsonl")); model != "" || !lastTime.IsZero() {
+		t.Errorf("Expected nothing for a missing transcript, got %q, %v", model, lastTime)
+	}
+}
+
+func TestSampleTranscriptStats(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "transcript.jsonl")
+	start := time.Date(2026, 1, 5, 10, 0, 0, 0, time.UTC)
+	var b strings.Builder
+	for i := range 20000 {
+		fmt.Fprintf(&b, `{"type":"assistant","timestamp":%q,"message":{"model":"claude-opus-4-20250514"}}`+"\n", start.Add(time.Duration(i)*time.Minute).Format(time.RFC3339))
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := claude.SampleTranscriptStats(path, 256*1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !stats.Approximate {
+		t.Error("Expected sampled stats to be approximate")
+	}
+	if stats.Messages < 19000 || stats.Messages > 21000 {
+		t.Errorf("Expected about 20000 turns, got %d", stats.Messages)
+	}
+	if want := start.Add(19999 * time.Minute); !stats.LastActivity.Equal(want) {
+		t.Errorf("Expected last activity %v, got %v", want, stats.LastActivity)
+	}
+
+	// Transcripts within the budget are read whole
+	stats, err = claude.SampleTranscriptStats(path, int64(b.Len()))
+	if err != nil || stats.Approximate || stats.Messages != 20000 {
+		t.Errorf("Expected exact stats, got %+v, %v", stats, err)
+	}
+}
@@ -0,0 +1,129 @@
+package claude
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/util"
+)
+
+// StatsCacheFile caches data derived from a session's transcripts in the
+// session folder, so 'list', 'inspect' and 'stats' only reread transcripts
+// that changed. The SessionStart hook removes it.
+const StatsCacheFile = "stats.json"
+
+// StatsCachePath returns the session's transcript cache.
+func StatsCachePath(clotildeRoot, name string) string {
+	return filepath.Join(config.GetSessionDir(clotildeRoot, name), StatsCacheFile)
+}
+
+// InvalidateStatsCache removes the session's transcript cache.
+func InvalidateStatsCache(clotildeRoot, name string) error {
+	err := os.Remove(StatsCachePath(clotildeRoot, name))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// statsCacheEntry is what's known about one transcript, valid while the
+// transcript's size and modification time are unchanged. Stats is only set
+// once the whole transcript has been scanned.
+type statsCacheEntry struct {
+	ModTime   time.Time        `json:"modTime"`
+	Size      int64            `json:"size"`
+	LastModel string           `json:"lastModel,omitempty"`
+	LastTime  time.Time        `json:"lastTime"`
+	Stats     *TranscriptStats `json:"stats,omitempty"`
+}
+
+func (e statsCacheEntry) matches(info os.FileInfo) bool {
+	return e.ModTime.Equal(info.ModTime()) && e.Size == info.Size()
+}
+
+// readStatsCache loads the cache, keyed by transcript path. An unreadable
+// cache is treated as empty.
+func readStatsCache(cachePath string) map[string]statsCacheEntry {
+	cache := make(map[string]statsCacheEntry)
+	if util.FileExists(cachePath) {
+		if err := util.ReadJSON(cachePath, &cache); err != nil {
+			return make(map[string]statsCacheEntry)
+		}
+	}
+	return cache
+}
+
+// CachedModelAndLastTime is ExtractModelAndLastTime, served from cachePath
+// while the transcript is unchanged.
+func CachedModelAndLastTime(cachePath, transcriptPath string) (string, time.Time) {
+	info, err := os.Stat(transcriptPath)
+	if err != nil {
+		return "", time.Time{}
+	}
+
+	cache := readStatsCache(cachePath)
+	if entry, ok := cache[transcriptPath]; ok && entry.matches(info) {
+		return entry.LastModel, entry.LastTime
+	}
+
+	model, lastTime := ExtractModelAndLastTime(transcriptPath)
+	cache[transcriptPath] = statsCacheEntry{ModTime: info.ModTime(), Size: info.Size(), LastModel: model, LastTime: lastTime}
+	_ = util.WriteJSON(cachePath, cache)
+	return model, lastTime
+}
+
+// CachedTranscriptStats returns the stats of each transcript (all of it, as
+// ReadTranscriptStats with a zero since), rescanning only those modified since
+// they were cached in cachePath. Missing transcripts are left out. The cache
+// is best effort: when it can't be read or written the transcripts are scanned.
+//
+// When sampleOver is positive, transcripts larger than that many bytes that
+// need a rescan are sampled with SampleTranscriptStats instead, and the
+// approximate result isn't cached.
+func CachedTranscriptStats(cachePath string, transcriptPaths []string, sampleOver int64) ([]TranscriptStats, error) {
+	cache := readStatsCache(cachePath)
+
+	var all []TranscriptStats
+	changed := false
+	for _, path := range transcriptPaths {
+		info, err := os.Stat(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		entry, ok := cache[path]
+		// Stats cached before days were tracked have turns but no days
+		stale := entry.Stats == nil || entry.Stats.Messages > 0 && entry.Stats.Days == nil
+		if !ok || !entry.matches(info) || stale {
+			if sampleOver > 0 && info.Size() > sampleOver {
+				stats, err := SampleTranscriptStats(path, sampleOver)
+				if err != nil {
+					return nil, err
+				}
+				all = append(all, stats)
+				continue
+			}
+			stats, err := ReadTranscriptStats(path, time.Time{})
+			if err != nil {
+				return nil, err
+			}
+			entry = statsCacheEntry{ModTime: info.ModTime(), Size: info.Size(), LastTime: stats.LastActivity, Stats: &stats}
+			if n := len(stats.ModelSpans); n > 0 {
+				entry.LastModel = stats.ModelSpans[n-1].Model
+			}
+			cache[path] = entry
+			changed = true
+		}
+		all = append(all, *entry.Stats)
+	}
+
+	if changed {
+		_ = util.WriteJSON(cachePath, cache)
+	}
+	return all, nil
+}
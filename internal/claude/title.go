@@ -0,0 +1,40 @@
+package claude
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// TerminalTitleFunc reports whether to set the terminal title to the session
+// name while claude runs. Set by the cmd package (--title, falling back to
+// the "terminalTitle" config).
+var TerminalTitleFunc = func(clotildeRoot string) bool { return false }
+
+// disableTitleEnv stops Claude Code from replacing the title with its own.
+const disableTitleEnv = "CLAUDE_CODE_DISABLE_TERMINAL_TITLE"
+
+// Escape sequences: xterm's title stack (push and pop, supported by most
+// terminals and tmux) and OSC 0, which sets the window and tab title.
+const (
+	pushTitleSeq = "\x1b[22;0t"
+	popTitleSeq  = "\x1b[23;0t"
+	setTitleSeq  = "\x1b]0;%s\x07"
+)
+
+// SetTerminalTitle writes the escapes that save the terminal's title and set
+// it to "claude: <name>", and returns a func that restores the saved one.
+// Terminals without a title stack keep the new title until something else
+// (usually the shell prompt) sets it.
+func SetTerminalTitle(w io.Writer, name string) (restore func()) {
+	// Control characters would end the sequence early
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name)
+	_, _ = fmt.Fprintf(w, pushTitleSeq+setTitleSeq, "claude: "+name)
+	return func() { _, _ = io.WriteString(w, popTitleSeq) }
+}
@@ -0,0 +1,30 @@
+package claude_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/claude"
+)
+
+var _ = Describe("SetTerminalTitle", func() {
+	It("saves the title, sets it to the session name and restores it", func() {
+		var out bytes.Buffer
+
+		restore := claude.SetTerminalTitle(&out, "auth-bug")
+		Expect(out.String()).To(Equal("\x1b[22;0t\x1b]0;claude: auth-bug\x07"))
+
+		out.Reset()
+		restore()
+		Expect(out.String()).To(Equal("\x1b[23;0t"))
+	})
+
+	It("drops control characters that would end the sequence early", func() {
+		var out bytes.Buffer
+
+		claude.SetTerminalTitle(&out, "a\x07b\x1bc")
+		Expect(out.String()).To(HaveSuffix("claude: abc\x07"))
+	})
+})
@@ -1,7 +1,6 @@
 package claude
 
 import (
-	"bufio"
 	"bytes"
 	"encoding/json"
 	"errors"
@@ -11,22 +10,18 @@ import (
 	"time"
 )
 
-// transcriptEntry represents a single line in the Claude Code transcript JSONL.
-type transcriptEntry struct {
-	Type    string `json:"type"`
-	Message struct {
-		Model string `json:"model"`
-	} `json:"message"`
-}
-
 var modelFamilyRegex = regexp.MustCompile(`claude-(?:\d+-)*(\w+)-\d+`)
 
-// forEachTailLine opens a transcript file, seeks to the last tailSize bytes,
-// and calls fn for each complete JSONL line in the tail. Uses bufio.Reader with
-// ReadSlice so that oversized lines are drained and skipped rather than halting
-// the scan (unlike bufio.Scanner which stops permanently on ErrTooLong).
-// Returns a non-nil error only for unexpected I/O failures.
-func forEachTailLine(transcriptPath string, tailSize int, fn func(line []byte)) error {
+// reverseChunkSize is how much of a transcript is read at a time when
+// scanning it backwards.
+const reverseChunkSize = 64 * 1024
+
+// forEachLineReverse calls fn for each JSONL line of a transcript, last line
+// first, until fn returns false. The file is read backwards in chunks, so
+// finding something near the end of a multi-hundred-MB transcript only reads
+// its tail. Lines spanning several chunks are reassembled without re-copying
+// earlier parts. Returns a non-nil error only for I/O failures.
+func forEachLineReverse(transcriptPath string, fn func(line []byte) bool) error {
 	if transcriptPath == "" {
 		return nil
 	}
@@ -42,86 +37,56 @@ func forEachTailLine(transcriptPath string, tailSize int, fn func(line []byte))
 		return err
 	}
 
-	skipFirstLine := false
-	if info.Size() > int64(tailSize) {
-		if _, err := file.Seek(info.Size()-int64(tailSize), io.SeekStart); err != nil {
-			return err
-		}
-		check := make([]byte, 1)
-		if _, err := file.ReadAt(check, info.Size()-int64(tailSize)-1); err == nil {
-			skipFirstLine = check[0] != '\n'
-		} else {
-			skipFirstLine = true
-		}
+	// emit hands fn a complete line; reports whether to keep going
+	emit := func(line []byte) bool {
+		line = bytes.TrimRight(line, "\r")
+		return len(line) == 0 || fn(line)
 	}
 
-	reader := bufio.NewReaderSize(file, tailSize)
+	chunk := make([]byte, reverseChunkSize)
+	// pieces holds the already-read end of the current line, latest part first
+	var pieces [][]byte
+	pos := info.Size()
+	for pos > 0 {
+		n := int(min(int64(reverseChunkSize), pos))
+		pos -= int64(n)
+		if _, err := file.ReadAt(chunk[:n], pos); err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
 
-	if skipFirstLine {
-		// Drain partial first line (may span multiple ReadSlice calls).
-		var drainErr error
+		end := n
 		for {
-			_, drainErr = reader.ReadSlice('\n')
-			if !errors.Is(drainErr, bufio.ErrBufferFull) {
+			i := bytes.LastIndexByte(chunk[:end], '\n')
+			if i < 0 {
+				pieces = append(pieces, bytes.Clone(chunk[:end]))
 				break
 			}
-		}
-		if drainErr == io.EOF {
-			return nil
-		}
-		if drainErr != nil {
-			return drainErr
-		}
-	}
-
-	for {
-		line, readErr := reader.ReadSlice('\n')
-		if errors.Is(readErr, bufio.ErrBufferFull) {
-			for errors.Is(readErr, bufio.ErrBufferFull) {
-				_, readErr = reader.ReadSlice('\n')
-			}
-			if readErr == io.EOF {
+			if !emit(joinLinePieces(chunk[i+1:end], pieces)) {
 				return nil
 			}
-			if readErr != nil {
-				return readErr
-			}
-			continue
-		}
-		line = bytes.TrimRight(line, "\r\n")
-		if len(line) > 0 {
-			fn(line)
-		}
-		if readErr == io.EOF {
-			return nil
-		}
-		if readErr != nil {
-			return readErr
+			pieces = nil
+			end = i
 		}
 	}
+	emit(joinLinePieces(nil, pieces))
+	return nil
 }
 
-// ExtractLastModel reads the transcript and returns the last model used.
-// Returns the model family name (e.g. "sonnet", "opus", "haiku") or empty string if not found.
-//
-// For large transcripts, only the last 128KB is read. Assistant entries that
-// record message.model are typically small, so the most recent one will almost
-// always be within the tail. A single assistant response larger than 128KB would
-// be missed, but that is an accepted tradeoff for the performance benefit.
-func ExtractLastModel(transcriptPath string) string {
-	var lastModel string
-	err := forEachTailLine(transcriptPath, 128*1024, func(line []byte) {
-		var entry transcriptEntry
-		if err := json.Unmarshal(line, &entry); err == nil {
-			if entry.Type == "assistant" && entry.Message.Model != "" {
-				lastModel = entry.Message.Model
-			}
-		}
-	})
-	if err != nil {
-		return ""
+// joinLinePieces returns head followed by pieces in reverse order.
+func joinLinePieces(head []byte, pieces [][]byte) []byte {
+	if len(pieces) == 0 {
+		return head
+	}
+	size := len(head)
+	for _, p := range pieces {
+		size += len(p)
 	}
-	return FormatModelFamily(lastModel)
+	line := make([]byte, 0, size)
+	line = append(line, head...)
+	for i := len(pieces) - 1; i >= 0; i-- {
+		line = append(line, pieces[i]...)
+	}
+	return line
 }
 
 // FormatModelFamily extracts the model family name from the full model ID.
@@ -140,10 +105,13 @@ func FormatModelFamily(fullModel string) string {
 	return fullModel
 }
 
-// ExtractModelAndLastTime reads the transcript tail once and returns both the
-// last model family name and the timestamp of the last entry. More efficient
-// than calling ExtractLastModel and LastTranscriptTime separately.
-// Returns empty string and zero time if the transcript is missing or unreadable.
+// ExtractModelAndLastTime returns both the last model family name (e.g.
+// "sonnet", "opus", "haiku") and the timestamp of the last entry. Returns empty
+// string and zero time if the transcript is missing or unreadable.
+//
+// The transcript is read backwards and the scan stops at the last assistant
+// entry that records message.model, so only the tail is read even for very
+// large transcripts.
 func ExtractModelAndLastTime(transcriptPath string) (string, time.Time) {
 	type entry struct {
 		Type      string    `json:"type"`
@@ -154,16 +122,19 @@ func ExtractModelAndLastTime(transcriptPath string) (string, time.Time) {
 	}
 	var lastModel string
 	var lastTime time.Time
-	err := forEachTailLine(transcriptPath, 128*1024, func(line []byte) {
+	err := forEachLineReverse(transcriptPath, func(line []byte) bool {
 		var e entry
-		if err := json.Unmarshal(line, &e); err == nil {
-			if !e.Timestamp.IsZero() {
-				lastTime = e.Timestamp
-			}
-			if e.Type == "assistant" && e.Message.Model != "" {
-				lastModel = e.Message.Model
-			}
+		if err := json.Unmarshal(line, &e); err != nil {
+			return true
+		}
+		if lastTime.IsZero() {
+			lastTime = e.Timestamp
+		}
+		if e.Type == "assistant" && e.Message.Model != "" {
+			lastModel = e.Message.Model
+			return false
 		}
+		return true
 	})
 	if err != nil {
 		return "", time.Time{}
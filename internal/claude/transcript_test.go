@@ -4,12 +4,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/fgrehm/clotilde/internal/claude"
 )
 
-func TestExtractLastModel(t *testing.T) {
+func TestExtractModelAndLastTime_Models(t *testing.T) {
 	tests := []struct {
 		name           string
 		transcriptData string
@@ -70,7 +72,7 @@ func TestExtractLastModel(t *testing.T) {
 				t.Fatalf("Failed to write test transcript: %v", err)
 			}
 
-			result := claude.ExtractLastModel(transcriptPath)
+			result, _ := claude.ExtractModelAndLastTime(transcriptPath)
 			if result != tt.expectedModel {
 				t.Errorf("Expected model %q, got %q", tt.expectedModel, result)
 			}
@@ -78,7 +80,7 @@ func TestExtractLastModel(t *testing.T) {
 	}
 }
 
-func TestExtractLastModel_LargeFile(t *testing.T) {
+func TestExtractModelAndLastTime_LargeFile(t *testing.T) {
 	// Verify that the tail-read optimization finds the last model in a file > 128KB.
 	// An early "opus" message is buried before the 128KB tail;
 	// a later "sonnet" message sits in the tail.
@@ -111,23 +113,33 @@ func TestExtractLastModel_LargeFile(t *testing.T) {
 		t.Fatalf("close: %v", err)
 	}
 
-	result := claude.ExtractLastModel(transcriptPath)
+	result, _ := claude.ExtractModelAndLastTime(transcriptPath)
 	if result != "sonnet" {
 		t.Errorf("got %q, want %q", result, "sonnet")
 	}
 }
 
-func TestExtractLastModel_NonExistentFile(t *testing.T) {
-	result := claude.ExtractLastModel("/non/existent/path")
-	if result != "" {
-		t.Errorf("Expected empty string for non-existent file, got %q", result)
+func TestExtractModelAndLastTime_ReverseScan(t *testing.T) {
+	// The last assistant entry is itself longer than a read chunk, and is
+	// followed by far more than a chunk of other entries.
+	path := filepath.Join(t.TempDir(), "huge.jsonl")
+	var b strings.Builder
+	b.WriteString(`{"type":"assistant","message":{"model":"claude-opus-4-20250514"}}` + "\n")
+	b.WriteString(`{"type":"assistant","message":{"model":"claude-sonnet-4-5-20250929","content":"` + strings.Repeat("x", 200*1024) + `"}}` + "\n")
+	for range 5000 {
+		b.WriteString(`{"type":"user","timestamp":"2025-01-01T10:00:00Z","message":{"content":"padding"}}` + "\n")
+	}
+	b.WriteString(`{"type":"user","timestamp":"2025-01-01T11:00:00Z","message":{"content":"last"}}`)
+	if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+		t.Fatal(err)
 	}
-}
 
-func TestExtractLastModel_EmptyPath(t *testing.T) {
-	result := claude.ExtractLastModel("")
-	if result != "" {
-		t.Errorf("Expected empty string for empty path, got %q", result)
+	model, ts := claude.ExtractModelAndLastTime(path)
+	if model != "sonnet" {
+		t.Errorf("model: got %q, want %q", model, "sonnet")
+	}
+	if want := time.Date(2025, 1, 1, 11, 0, 0, 0, time.UTC); !ts.Equal(want) {
+		t.Errorf("time: got %v, want %v", ts, want)
 	}
 }
 
@@ -1,9 +1,232 @@
 package config
 
+import "time"
+
 // Config represents the clotilde configuration.
 type Config struct {
 	// Profiles is a map of named session profiles
 	Profiles map[string]Profile `json:"profiles,omitempty"`
+
+	// Defaults holds the lowest precedence values applied when neither flags,
+	// a profile, nor the session's settings set them
+	Defaults *Profile `json:"defaults,omitempty"`
+
+	// ArgPresets names bundles of claude CLI flags, added with --with <name>
+	ArgPresets map[string][]string `json:"argPresets,omitempty"`
+
+	// Naming customizes which session names are accepted
+	Naming *Naming `json:"naming,omitempty"`
+
+	// Expiry controls how sessions created with --expires are cleaned up
+	Expiry *Expiry `json:"expiry,omitempty"`
+
+	// AutoArchiveAfter archives sessions left unused this long (e.g. "30d")
+	// whenever clotilde runs; "off" turns a global setting off for a project
+	AutoArchiveAfter string `json:"autoArchiveAfter,omitempty"`
+
+	// Maintenance runs housekeeping (cache refresh, leftover cleanup, log
+	// rotation) in the background after commands
+	Maintenance *Maintenance `json:"maintenance,omitempty"`
+
+	// Picker remembers the session picker's preview layout (global config only)
+	Picker *Picker `json:"picker,omitempty"`
+
+	// Logging controls capture of Claude Code's stderr to per-session logs
+	Logging *Logging `json:"logging,omitempty"`
+
+	// Clear controls what happens to a conversation's transcript after /clear
+	Clear *Clear `json:"clear,omitempty"`
+
+	// Resume controls what 'clotilde resume' prints before launching claude
+	Resume *Resume `json:"resume,omitempty"`
+
+	// TerminalTitle sets the terminal title to "claude: <session>" while
+	// claude runs, as with --title
+	TerminalTitle *bool `json:"terminalTitle,omitempty"`
+
+	// EmptySessions controls removal of sessions left without a conversation
+	EmptySessions *EmptySessions `json:"emptySessions,omitempty"`
+
+	// ActiveSessions warns before launching claude when many sessions are
+	// already running
+	ActiveSessions *ActiveSessions `json:"activeSessions,omitempty"`
+
+	// Dashboard controls what the dashboard (clotilde without a subcommand) shows
+	Dashboard *Dashboard `json:"dashboard,omitempty"`
+
+	// Confirm controls when deleting sessions asks for confirmation
+	Confirm *Confirm `json:"confirm,omitempty"`
+
+	// Storage is where new projects keep their sessions: StorageProject
+	// (.claude/clotilde) or StorageData (global config only)
+	Storage string `json:"storage,omitempty"`
+
+	// ClaudeConfigDir relocates Claude Code's config directory (default
+	// ~/.claude) when CLAUDE_CONFIG_DIR isn't set (global config only)
+	ClaudeConfigDir string `json:"claudeConfigDir,omitempty"`
+
+	// Language selects the catalog user-facing messages are shown from (e.g.
+	// "pt-BR"); defaults to the locale from LC_ALL, LC_MESSAGES or LANG
+	// (global config only)
+	Language string `json:"language,omitempty"`
+
+	// Redact lists regular expressions whose matches are masked in contexts
+	// injected into claude and in exported transcripts
+	Redact []string `json:"redact,omitempty"`
+
+	// Encryption keeps contexts, custom output styles and backups encrypted on disk
+	Encryption *Encryption `json:"encryption,omitempty"`
+
+	// Team shares session metadata with teammates for 'clotilde list --team'
+	Team *Team `json:"team,omitempty"`
+
+	// Remotes names the hosts 'clotilde --remote' connects to (global config only)
+	Remotes map[string]Remote `json:"remotes,omitempty"`
+}
+
+// Encryption configures at-rest encryption with the key in GlobalKeyPath().
+type Encryption struct {
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+// Team configures sharing session metadata (never transcripts) through a
+// shared directory such as a synced folder, a mounted bucket or a git checkout.
+type Team struct {
+	// Dir is the shared directory; a leading ~/ is expanded
+	Dir string `json:"dir,omitempty"`
+
+	// Project names the project in Dir (default: the project directory's name)
+	Project string `json:"project,omitempty"`
+
+	// User is the name sessions are shared under (default: $USER)
+	User string `json:"user,omitempty"`
+}
+
+// Remote describes how to reach a host over ssh for 'clotilde --remote'.
+type Remote struct {
+	// Host is the ssh destination ([user@]hostname or an ~/.ssh/config alias)
+	Host string `json:"host"`
+
+	// SSHArgs are extra arguments for ssh, placed before the destination
+	SSHArgs []string `json:"sshArgs,omitempty"`
+
+	// Clotilde is the clotilde binary on the remote host (default "clotilde")
+	Clotilde string `json:"clotilde,omitempty"`
+}
+
+// DefaultLogMaxSizeKB is the size at which a session's claude.log is rotated
+const DefaultLogMaxSizeKB = 1024
+
+// Logging configures the per-session claude.log.
+type Logging struct {
+	// CaptureStderr tees Claude Code's stderr into claude.log in the session
+	// folder (still shown live), for 'clotilde logs'
+	CaptureStderr *bool `json:"captureStderr,omitempty"`
+
+	// MaxSizeKB rotates claude.log to claude.log.1 once it grows past this size
+	MaxSizeKB int `json:"maxSizeKB,omitempty"`
+}
+
+// DefaultMaintenanceInterval is how long each housekeeping task waits between
+// background runs
+const DefaultMaintenanceInterval = 6 * time.Hour
+
+// Maintenance configures the background housekeeping pass.
+type Maintenance struct {
+	// Background runs the housekeeping tasks that are due after a command
+	// finishes, within a short time budget (default off)
+	Background *bool `json:"background,omitempty"`
+
+	// Interval is how long each task waits between runs (e.g. "1h", "1d")
+	Interval string `json:"interval,omitempty"`
+}
+
+// Policies for the transcript a /clear leaves behind.
+const (
+	ClearKeep     = "keep"     // Leave it in Claude's storage (default)
+	ClearSnapshot = "snapshot" // Also copy it into the session folder
+	ClearPrune    = "prune"    // Delete it and its agent logs right away
+)
+
+// Clear configures how /clear detaches the previous conversation.
+type Clear struct {
+	Transcript string `json:"transcript,omitempty"` // ClearKeep, ClearSnapshot or ClearPrune
+}
+
+// Resume configures 'clotilde resume'.
+type Resume struct {
+	// Recap prints the last prompt and reply before resuming, as with --recap
+	Recap *bool `json:"recap,omitempty"`
+
+	// Prepare is a shell command run before claude resumes any session (e.g.
+	// "docker compose up -d"); its failure aborts the launch. A session's own
+	// prepare script takes precedence.
+	Prepare string `json:"prepare,omitempty"`
+
+	// Teardown is a shell command run after claude exits, the counterpart of
+	// Prepare
+	Teardown string `json:"teardown,omitempty"`
+}
+
+// EmptySessions configures what happens to sessions Claude Code exits from
+// without a conversation.
+type EmptySessions struct {
+	// AutoRemove deletes them when claude exits after starting, forking or
+	// resuming them (default true); 'clotilde prune --empty' still works
+	AutoRemove *bool `json:"autoRemove,omitempty"`
+}
+
+// ActiveSessions configures the warning about sessions running at once.
+type ActiveSessions struct {
+	// SoftLimit is how many sessions, across all registered projects, can be
+	// active before launching another one prints a warning (default 0: off).
+	// A negative value turns a global limit off for a project.
+	SoftLimit int `json:"softLimit,omitempty"`
+}
+
+// Dashboard configures the dashboard.
+type Dashboard struct {
+	// ShowIncognito lists running incognito sessions under "Recent Sessions"
+	// (default false)
+	ShowIncognito *bool `json:"showIncognito,omitempty"`
+}
+
+// DefaultTypedConfirmAboveKB is the combined transcript size above which
+// deleting a session requires typing its name
+const DefaultTypedConfirmAboveKB = 10 * 1024
+
+// Confirm configures the confirmation before sessions are deleted by
+// 'clotilde delete', 'clotilde prune' and the dashboard.
+type Confirm struct {
+	// SkipBelowTurns deletes sessions with fewer turns (Claude replies) than
+	// this without asking (default 0: always ask)
+	SkipBelowTurns int `json:"skipBelowTurns,omitempty"`
+
+	// TypedAboveKB requires typing the session name when its transcripts
+	// total more than this
+	TypedAboveKB int `json:"typedAboveKB,omitempty"`
+}
+
+// Picker holds the session picker's layout preferences. The picker saves them
+// to the global config when the preview pane is toggled or resized.
+type Picker struct {
+	HidePreview  bool `json:"hidePreview,omitempty"`
+	PreviewWidth int  `json:"previewWidth,omitempty"` // Percentage of the terminal width
+}
+
+// Expiry configures cleanup of expired sessions.
+type Expiry struct {
+	// AutoPrune deletes expired sessions whenever clotilde runs, instead of
+	// waiting for 'clotilde prune --expired'
+	AutoPrune *bool `json:"autoPrune,omitempty"`
+}
+
+// Naming configures session name validation. Zero values keep the defaults
+// (lowercase alphanumeric with hyphens, at most 64 characters).
+type Naming struct {
+	MaxLength  int    `json:"maxLength,omitempty"`  // Maximum name length (2-128)
+	Case       string `json:"case,omitempty"`       // "lower" (default) or "any"
+	ExtraChars string `json:"extraChars,omitempty"` // Additional allowed characters: "_" and/or "."
 }
 
 // Profile represents a named preset of session settings.
@@ -12,6 +235,7 @@ type Profile struct {
 	PermissionMode string       `json:"permissionMode,omitempty"`
 	Permissions    *Permissions `json:"permissions,omitempty"`
 	OutputStyle    string       `json:"outputStyle,omitempty"`
+	Launcher       []string     `json:"launcher,omitempty"` // Command template run instead of claude, e.g. ["my-wrapper", "{claude}", "{args}"]
 }
 
 // Permissions represents the permissions configuration for sessions.
@@ -1,9 +1,13 @@
 package config
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"maps"
 	"os"
+	"slices"
+	"time"
 
 	"github.com/fgrehm/clotilde/internal/util"
 )
@@ -63,3 +67,551 @@ func MergedProfiles(clotildeRoot string) (map[string]Profile, error) {
 	maps.Copy(merged, projectCfg.Profiles)
 	return merged, nil
 }
+
+// MergedArgPresets returns the "argPresets" of the global and project configs.
+// Project-level presets replace global ones with the same name. An empty
+// clotildeRoot returns the global presets only.
+func MergedArgPresets(clotildeRoot string) (map[string][]string, error) {
+	globalCfg, err := LoadGlobalOrDefault()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load global config: %w", err)
+	}
+	merged := make(map[string][]string)
+	maps.Copy(merged, globalCfg.ArgPresets)
+	if clotildeRoot == "" {
+		return merged, nil
+	}
+	projectCfg, err := LoadOrDefault(clotildeRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load project config: %w", err)
+	}
+	maps.Copy(merged, projectCfg.ArgPresets)
+	return merged, nil
+}
+
+// MergedDefaults returns the "defaults" block combining global and project configs.
+// Project-level values take precedence over global ones, field by field.
+func MergedDefaults(clotildeRoot string) (Profile, error) {
+	globalCfg, err := LoadGlobalOrDefault()
+	if err != nil {
+		return Profile{}, fmt.Errorf("failed to load global config: %w", err)
+	}
+	projectCfg, err := LoadOrDefault(clotildeRoot)
+	if err != nil {
+		return Profile{}, fmt.Errorf("failed to load project config: %w", err)
+	}
+
+	var merged Profile
+	for _, d := range []*Profile{globalCfg.Defaults, projectCfg.Defaults} {
+		if d == nil {
+			continue
+		}
+		if d.Model != "" {
+			merged.Model = d.Model
+		}
+		if d.PermissionMode != "" {
+			merged.PermissionMode = d.PermissionMode
+		}
+		if d.Permissions != nil {
+			merged.Permissions = d.Permissions
+		}
+		if len(d.Launcher) > 0 {
+			merged.Launcher = d.Launcher
+		}
+	}
+	return merged, nil
+}
+
+// MergedNaming returns the "naming" block combining global and project configs.
+// Project-level values take precedence over global ones, field by field.
+func MergedNaming(clotildeRoot string) (Naming, error) {
+	globalCfg, err := LoadGlobalOrDefault()
+	if err != nil {
+		return Naming{}, fmt.Errorf("failed to load global config: %w", err)
+	}
+	projectCfg, err := LoadOrDefault(clotildeRoot)
+	if err != nil {
+		return Naming{}, fmt.Errorf("failed to load project config: %w", err)
+	}
+
+	var merged Naming
+	for _, n := range []*Naming{globalCfg.Naming, projectCfg.Naming} {
+		if n == nil {
+			continue
+		}
+		if n.MaxLength != 0 {
+			merged.MaxLength = n.MaxLength
+		}
+		if n.Case != "" {
+			merged.Case = n.Case
+		}
+		if n.ExtraChars != "" {
+			merged.ExtraChars = n.ExtraChars
+		}
+	}
+	return merged, nil
+}
+
+// AutoPruneExpired reports whether expired sessions should be deleted automatically.
+// A project-level setting takes precedence over the global one.
+func AutoPruneExpired(clotildeRoot string) (bool, error) {
+	globalCfg, err := LoadGlobalOrDefault()
+	if err != nil {
+		return false, fmt.Errorf("failed to load global config: %w", err)
+	}
+	projectCfg, err := LoadOrDefault(clotildeRoot)
+	if err != nil {
+		return false, fmt.Errorf("failed to load project config: %w", err)
+	}
+
+	enabled := false
+	for _, e := range []*Expiry{globalCfg.Expiry, projectCfg.Expiry} {
+		if e != nil && e.AutoPrune != nil {
+			enabled = *e.AutoPrune
+		}
+	}
+	return enabled, nil
+}
+
+// AutoArchiveOff turns off a global "autoArchiveAfter" in a project config.
+const AutoArchiveOff = "off"
+
+// AutoArchiveAfter returns how long sessions may go unused before they're
+// archived automatically, and the setting as written for messages, or 0 when
+// auto-archiving is off. A project-level setting takes precedence over the
+// global one.
+func AutoArchiveAfter(clotildeRoot string) (time.Duration, string, error) {
+	globalCfg, err := LoadGlobalOrDefault()
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to load global config: %w", err)
+	}
+	projectCfg, err := LoadOrDefault(clotildeRoot)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to load project config: %w", err)
+	}
+
+	after := globalCfg.AutoArchiveAfter
+	if projectCfg.AutoArchiveAfter != "" {
+		after = projectCfg.AutoArchiveAfter
+	}
+	if after == "" || after == AutoArchiveOff {
+		return 0, "", nil
+	}
+	d, err := util.ParseDuration(after)
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid autoArchiveAfter: %w", err)
+	}
+	return d, after, nil
+}
+
+// MergedMaintenance returns whether background maintenance is enabled and how
+// often its tasks run, combining global and project configs. Project-level
+// values take precedence.
+func MergedMaintenance(clotildeRoot string) (bool, time.Duration, error) {
+	globalCfg, err := LoadGlobalOrDefault()
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to load global config: %w", err)
+	}
+	projectCfg, err := LoadOrDefault(clotildeRoot)
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to load project config: %w", err)
+	}
+
+	enabled, interval := false, ""
+	for _, m := range []*Maintenance{globalCfg.Maintenance, projectCfg.Maintenance} {
+		if m == nil {
+			continue
+		}
+		if m.Background != nil {
+			enabled = *m.Background
+		}
+		if m.Interval != "" {
+			interval = m.Interval
+		}
+	}
+	if interval == "" {
+		return enabled, DefaultMaintenanceInterval, nil
+	}
+	d, err := util.ParseDuration(interval)
+	if err != nil {
+		return false, 0, fmt.Errorf("invalid maintenance.interval: %w", err)
+	}
+	return enabled, d, nil
+}
+
+// EncryptionEnabled reports whether session data should be encrypted on disk.
+// A project-level setting takes precedence over the global one.
+func EncryptionEnabled(clotildeRoot string) (bool, error) {
+	globalCfg, err := LoadGlobalOrDefault()
+	if err != nil {
+		return false, fmt.Errorf("failed to load global config: %w", err)
+	}
+	projectCfg, err := LoadOrDefault(clotildeRoot)
+	if err != nil {
+		return false, fmt.Errorf("failed to load project config: %w", err)
+	}
+
+	enabled := false
+	for _, e := range []*Encryption{globalCfg.Encryption, projectCfg.Encryption} {
+		if e != nil && e.Enabled != nil {
+			enabled = *e.Enabled
+		}
+	}
+	return enabled, nil
+}
+
+// SaveProjectEncryption turns encryption on or off in the project config,
+// keeping the rest of the file intact.
+func SaveProjectEncryption(clotildeRoot string, enabled bool) error {
+	cfg, err := LoadOrDefault(clotildeRoot)
+	if err != nil {
+		return fmt.Errorf("failed to load project config: %w", err)
+	}
+	cfg.Encryption = &Encryption{Enabled: &enabled}
+	if err := util.WriteJSON(GetConfigPath(clotildeRoot), cfg); err != nil {
+		return fmt.Errorf("failed to save project config: %w", err)
+	}
+	return nil
+}
+
+// ResumeRecap reports whether 'resume' should print a recap of where the
+// conversation left off. A project-level setting takes precedence over the
+// global one.
+func ResumeRecap(clotildeRoot string) (bool, error) {
+	globalCfg, err := LoadGlobalOrDefault()
+	if err != nil {
+		return false, fmt.Errorf("failed to load global config: %w", err)
+	}
+	projectCfg, err := LoadOrDefault(clotildeRoot)
+	if err != nil {
+		return false, fmt.Errorf("failed to load project config: %w", err)
+	}
+
+	enabled := false
+	for _, r := range []*Resume{globalCfg.Resume, projectCfg.Resume} {
+		if r != nil && r.Recap != nil {
+			enabled = *r.Recap
+		}
+	}
+	return enabled, nil
+}
+
+// ResumeScripts returns the "resume.prepare" and "resume.teardown" commands,
+// each from the project config when set there, else the global one.
+func ResumeScripts(clotildeRoot string) (prepare, teardown string, err error) {
+	globalCfg, err := LoadGlobalOrDefault()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to load global config: %w", err)
+	}
+	projectCfg, err := LoadOrDefault(clotildeRoot)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to load project config: %w", err)
+	}
+
+	for _, r := range []*Resume{globalCfg.Resume, projectCfg.Resume} {
+		if r == nil {
+			continue
+		}
+		if r.Prepare != "" {
+			prepare = r.Prepare
+		}
+		if r.Teardown != "" {
+			teardown = r.Teardown
+		}
+	}
+	return prepare, teardown, nil
+}
+
+// TerminalTitle reports whether the terminal title is set to the session
+// name while claude runs. A project-level setting takes precedence over the
+// global one.
+func TerminalTitle(clotildeRoot string) (bool, error) {
+	globalCfg, err := LoadGlobalOrDefault()
+	if err != nil {
+		return false, fmt.Errorf("failed to load global config: %w", err)
+	}
+	projectCfg, err := LoadOrDefault(clotildeRoot)
+	if err != nil {
+		return false, fmt.Errorf("failed to load project config: %w", err)
+	}
+
+	enabled := false
+	for _, t := range []*bool{globalCfg.TerminalTitle, projectCfg.TerminalTitle} {
+		if t != nil {
+			enabled = *t
+		}
+	}
+	return enabled, nil
+}
+
+// AutoRemoveEmptySessions reports whether sessions left without a conversation
+// are deleted when claude exits (the default). A project-level setting takes
+// precedence over the global one.
+func AutoRemoveEmptySessions(clotildeRoot string) (bool, error) {
+	globalCfg, err := LoadGlobalOrDefault()
+	if err != nil {
+		return false, fmt.Errorf("failed to load global config: %w", err)
+	}
+	projectCfg, err := LoadOrDefault(clotildeRoot)
+	if err != nil {
+		return false, fmt.Errorf("failed to load project config: %w", err)
+	}
+
+	enabled := true
+	for _, e := range []*EmptySessions{globalCfg.EmptySessions, projectCfg.EmptySessions} {
+		if e != nil && e.AutoRemove != nil {
+			enabled = *e.AutoRemove
+		}
+	}
+	return enabled, nil
+}
+
+// ActiveSessionsSoftLimit returns how many sessions can be active before
+// launching another one warns, or 0 when the warning is off (the default). A
+// project-level setting takes precedence over the global one.
+func ActiveSessionsSoftLimit(clotildeRoot string) (int, error) {
+	globalCfg, err := LoadGlobalOrDefault()
+	if err != nil {
+		return 0, fmt.Errorf("failed to load global config: %w", err)
+	}
+	projectCfg, err := LoadOrDefault(clotildeRoot)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load project config: %w", err)
+	}
+
+	limit := 0
+	for _, a := range []*ActiveSessions{globalCfg.ActiveSessions, projectCfg.ActiveSessions} {
+		if a != nil && a.SoftLimit != 0 {
+			limit = a.SoftLimit
+		}
+	}
+	return max(limit, 0), nil
+}
+
+// DashboardShowsIncognito reports whether the dashboard lists incognito
+// sessions among the recent ones (off by default). A project-level setting
+// takes precedence over the global one.
+func DashboardShowsIncognito(clotildeRoot string) (bool, error) {
+	globalCfg, err := LoadGlobalOrDefault()
+	if err != nil {
+		return false, fmt.Errorf("failed to load global config: %w", err)
+	}
+	projectCfg, err := LoadOrDefault(clotildeRoot)
+	if err != nil {
+		return false, fmt.Errorf("failed to load project config: %w", err)
+	}
+
+	show := false
+	for _, d := range []*Dashboard{globalCfg.Dashboard, projectCfg.Dashboard} {
+		if d != nil && d.ShowIncognito != nil {
+			show = *d.ShowIncognito
+		}
+	}
+	return show, nil
+}
+
+// ClearTranscriptPolicy returns what to do with the transcript a /clear leaves
+// behind (ClearKeep by default). A project-level setting takes precedence over
+// the global one.
+func ClearTranscriptPolicy(clotildeRoot string) (string, error) {
+	globalCfg, err := LoadGlobalOrDefault()
+	if err != nil {
+		return ClearKeep, fmt.Errorf("failed to load global config: %w", err)
+	}
+	projectCfg, err := LoadOrDefault(clotildeRoot)
+	if err != nil {
+		return ClearKeep, fmt.Errorf("failed to load project config: %w", err)
+	}
+
+	policy := ClearKeep
+	for _, c := range []*Clear{globalCfg.Clear, projectCfg.Clear} {
+		if c != nil && c.Transcript != "" {
+			policy = c.Transcript
+		}
+	}
+	switch policy {
+	case ClearKeep, ClearSnapshot, ClearPrune:
+		return policy, nil
+	default:
+		return ClearKeep, fmt.Errorf("invalid clear.transcript %q (must be %q, %q or %q)", policy, ClearKeep, ClearSnapshot, ClearPrune)
+	}
+}
+
+// MergedLogging returns the "logging" block combining global and project
+// configs, with defaults filled in. Project-level values take precedence.
+func MergedLogging(clotildeRoot string) (Logging, error) {
+	globalCfg, err := LoadGlobalOrDefault()
+	if err != nil {
+		return Logging{}, fmt.Errorf("failed to load global config: %w", err)
+	}
+	projectCfg, err := LoadOrDefault(clotildeRoot)
+	if err != nil {
+		return Logging{}, fmt.Errorf("failed to load project config: %w", err)
+	}
+
+	captureStderr := false
+	merged := Logging{CaptureStderr: &captureStderr, MaxSizeKB: DefaultLogMaxSizeKB}
+	for _, l := range []*Logging{globalCfg.Logging, projectCfg.Logging} {
+		if l == nil {
+			continue
+		}
+		if l.CaptureStderr != nil {
+			captureStderr = *l.CaptureStderr
+		}
+		if l.MaxSizeKB > 0 {
+			merged.MaxSizeKB = l.MaxSizeKB
+		}
+	}
+	return merged, nil
+}
+
+// MergedConfirm returns the "confirm" block combining global and project
+// configs, with defaults filled in. Project-level values take precedence.
+func MergedConfirm(clotildeRoot string) (Confirm, error) {
+	merged := Confirm{TypedAboveKB: DefaultTypedConfirmAboveKB}
+	globalCfg, err := LoadGlobalOrDefault()
+	if err != nil {
+		return merged, fmt.Errorf("failed to load global config: %w", err)
+	}
+	projectCfg, err := LoadOrDefault(clotildeRoot)
+	if err != nil {
+		return merged, fmt.Errorf("failed to load project config: %w", err)
+	}
+
+	for _, c := range []*Confirm{globalCfg.Confirm, projectCfg.Confirm} {
+		if c == nil {
+			continue
+		}
+		if c.SkipBelowTurns > 0 {
+			merged.SkipBelowTurns = c.SkipBelowTurns
+		}
+		if c.TypedAboveKB > 0 {
+			merged.TypedAboveKB = c.TypedAboveKB
+		}
+	}
+	return merged, nil
+}
+
+// MergedTeam returns the "team" block combining global and project configs.
+// Project-level values take precedence; unset fields stay empty.
+func MergedTeam(clotildeRoot string) (Team, error) {
+	globalCfg, err := LoadGlobalOrDefault()
+	if err != nil {
+		return Team{}, fmt.Errorf("failed to load global config: %w", err)
+	}
+	projectCfg, err := LoadOrDefault(clotildeRoot)
+	if err != nil {
+		return Team{}, fmt.Errorf("failed to load project config: %w", err)
+	}
+
+	var merged Team
+	for _, t := range []*Team{globalCfg.Team, projectCfg.Team} {
+		if t == nil {
+			continue
+		}
+		if t.Dir != "" {
+			merged.Dir = t.Dir
+		}
+		if t.Project != "" {
+			merged.Project = t.Project
+		}
+		if t.User != "" {
+			merged.User = t.User
+		}
+	}
+	return merged, nil
+}
+
+// LookupRemote returns the remote named name in the global config. Names
+// that aren't configured are used as the ssh destination as-is.
+func LookupRemote(name string) (Remote, error) {
+	cfg, err := LoadGlobalOrDefault()
+	if err != nil {
+		return Remote{}, fmt.Errorf("failed to load global config: %w", err)
+	}
+	remote, ok := cfg.Remotes[name]
+	if !ok {
+		remote = Remote{Host: name}
+	}
+	if remote.Host == "" {
+		return Remote{}, fmt.Errorf("remote '%s' has no host configured", name)
+	}
+	if remote.Clotilde == "" {
+		remote.Clotilde = "clotilde"
+	}
+	return remote, nil
+}
+
+// GlobalPicker returns the picker layout preferences from the global config.
+func GlobalPicker() (Picker, error) {
+	cfg, err := LoadGlobalOrDefault()
+	if err != nil {
+		return Picker{}, fmt.Errorf("failed to load global config: %w", err)
+	}
+	if cfg.Picker == nil {
+		return Picker{}, nil
+	}
+	return *cfg.Picker, nil
+}
+
+// SaveGlobalPicker stores the picker layout preferences in the global config.
+// Only the "picker" key is rewritten, so the rest of the file keeps its keys
+// (including ones this version doesn't know) and formatting.
+func SaveGlobalPicker(picker Picker) error {
+	if err := setJSONKey(GlobalConfigPath(), "picker", picker); err != nil {
+		return fmt.Errorf("failed to save global config: %w", err)
+	}
+	return nil
+}
+
+// setJSONKey sets key to value in the JSON object stored at path, creating
+// the file when it doesn't exist. An existing value is replaced in place and
+// a new key is appended; the rest of the file is left byte for byte.
+func setJSONKey(path, key string, value any) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		data = []byte("{}\n")
+	}
+	encoded, err := json.MarshalIndent(value, "  ", "  ")
+	if err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return fmt.Errorf("%s is not a JSON object", path)
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		afterKey := dec.InputOffset()
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		if tok != key {
+			continue
+		}
+		end := int(dec.InputOffset())
+		start := end - len(raw)
+		if start < int(afterKey) {
+			return fmt.Errorf("failed to parse %s", path)
+		}
+		return util.WriteFile(path, slices.Concat(data[:start], encoded, data[end:]))
+	}
+
+	// Not set yet: add it after the last key, or as the only one
+	closing := bytes.LastIndexByte(data, '}')
+	body := bytes.TrimRight(data[:closing], " \t\r\n")
+	separator := ","
+	if bytes.HasSuffix(body, []byte("{")) {
+		separator = ""
+	}
+	entry := fmt.Sprintf("%s\n  %q: %s\n", separator, key, encoded)
+	return util.WriteFile(path, slices.Concat(body, []byte(entry), data[closing:]))
+}
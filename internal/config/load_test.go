@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -148,3 +150,477 @@ var _ = Describe("MergedProfiles", func() {
 		Expect(merged).To(BeEmpty())
 	})
 })
+
+var _ = Describe("MergedArgPresets", func() {
+	var clotildeRoot string
+
+	BeforeEach(func() {
+		tmpDir := GinkgoT().TempDir()
+		clotildeRoot = filepath.Join(tmpDir, "project", config.ClotildeDir)
+		Expect(util.EnsureDir(clotildeRoot)).To(Succeed())
+		GinkgoT().Setenv("XDG_CONFIG_HOME", filepath.Join(tmpDir, "xdg"))
+
+		Expect(util.WriteJSON(config.GlobalConfigPath(), &config.Config{ArgPresets: map[string][]string{
+			"debug":   {"--debug"},
+			"verbose": {"--verbose"},
+		}})).To(Succeed())
+		Expect(util.WriteJSON(config.GetConfigPath(clotildeRoot), &config.Config{ArgPresets: map[string][]string{
+			"debug": {"--debug", "api,hooks"},
+		}})).To(Succeed())
+	})
+
+	It("lets project presets replace global ones with the same name", func() {
+		merged, err := config.MergedArgPresets(clotildeRoot)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(merged).To(Equal(map[string][]string{
+			"debug":   {"--debug", "api,hooks"},
+			"verbose": {"--verbose"},
+		}))
+	})
+
+	It("returns the global presets without a project", func() {
+		merged, err := config.MergedArgPresets("")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(merged).To(HaveKeyWithValue("debug", []string{"--debug"}))
+	})
+})
+
+var _ = Describe("MergedDefaults", func() {
+	var tmpDir string
+	var clotildeRoot string
+
+	BeforeEach(func() {
+		tmpDir = GinkgoT().TempDir()
+		clotildeRoot = filepath.Join(tmpDir, "project", config.ClotildeDir)
+		Expect(util.EnsureDir(clotildeRoot)).To(Succeed())
+
+		globalDir := filepath.Join(tmpDir, "xdg")
+		Expect(os.MkdirAll(filepath.Join(globalDir, "clotilde"), 0o755)).To(Succeed())
+		GinkgoT().Setenv("XDG_CONFIG_HOME", globalDir)
+	})
+
+	It("returns an empty profile when no config defines defaults", func() {
+		merged, err := config.MergedDefaults(clotildeRoot)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(merged).To(Equal(config.Profile{}))
+	})
+
+	It("lets project defaults override global defaults field by field", func() {
+		globalData, err := json.Marshal(&config.Config{Defaults: &config.Profile{Model: "haiku", PermissionMode: "plan"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.WriteFile(config.GlobalConfigPath(), globalData, 0o644)).To(Succeed())
+
+		projectData, err := json.Marshal(&config.Config{Defaults: &config.Profile{Model: "sonnet"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.WriteFile(config.GetConfigPath(clotildeRoot), projectData, 0o644)).To(Succeed())
+
+		merged, err := config.MergedDefaults(clotildeRoot)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(merged.Model).To(Equal("sonnet"))
+		Expect(merged.PermissionMode).To(Equal("plan"))
+	})
+})
+
+var _ = Describe("AutoPruneExpired", func() {
+	var tmpDir string
+	var clotildeRoot string
+
+	BeforeEach(func() {
+		tmpDir = GinkgoT().TempDir()
+		clotildeRoot = filepath.Join(tmpDir, "project", config.ClotildeDir)
+		Expect(util.EnsureDir(clotildeRoot)).To(Succeed())
+
+		globalDir := filepath.Join(tmpDir, "xdg")
+		Expect(os.MkdirAll(filepath.Join(globalDir, "clotilde"), 0o755)).To(Succeed())
+		GinkgoT().Setenv("XDG_CONFIG_HOME", globalDir)
+	})
+
+	It("is disabled by default", func() {
+		enabled, err := config.AutoPruneExpired(clotildeRoot)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(enabled).To(BeFalse())
+	})
+
+	It("lets the project setting override the global one", func() {
+		Expect(os.WriteFile(config.GlobalConfigPath(), []byte(`{"expiry": {"autoPrune": true}}`), 0o644)).To(Succeed())
+
+		enabled, err := config.AutoPruneExpired(clotildeRoot)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(enabled).To(BeTrue())
+
+		Expect(os.WriteFile(config.GetConfigPath(clotildeRoot), []byte(`{"expiry": {"autoPrune": false}}`), 0o644)).To(Succeed())
+
+		enabled, err = config.AutoPruneExpired(clotildeRoot)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(enabled).To(BeFalse())
+	})
+})
+
+var _ = Describe("AutoArchiveAfter", func() {
+	var clotildeRoot string
+
+	BeforeEach(func() {
+		tmpDir := GinkgoT().TempDir()
+		clotildeRoot = filepath.Join(tmpDir, "project", config.ClotildeDir)
+		Expect(util.EnsureDir(clotildeRoot)).To(Succeed())
+
+		globalDir := filepath.Join(tmpDir, "xdg")
+		Expect(os.MkdirAll(filepath.Join(globalDir, "clotilde"), 0o755)).To(Succeed())
+		GinkgoT().Setenv("XDG_CONFIG_HOME", globalDir)
+	})
+
+	It("is off by default", func() {
+		after, _, err := config.AutoArchiveAfter(clotildeRoot)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(after).To(BeZero())
+	})
+
+	It("lets the project setting override the global one, or turn it off", func() {
+		Expect(os.WriteFile(config.GlobalConfigPath(), []byte(`{"autoArchiveAfter": "30d"}`), 0o644)).To(Succeed())
+
+		after, setting, err := config.AutoArchiveAfter(clotildeRoot)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(after).To(Equal(30 * 24 * time.Hour))
+		Expect(setting).To(Equal("30d"))
+
+		Expect(os.WriteFile(config.GetConfigPath(clotildeRoot), []byte(`{"autoArchiveAfter": "2w"}`), 0o644)).To(Succeed())
+		after, _, err = config.AutoArchiveAfter(clotildeRoot)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(after).To(Equal(14 * 24 * time.Hour))
+
+		Expect(os.WriteFile(config.GetConfigPath(clotildeRoot), []byte(`{"autoArchiveAfter": "off"}`), 0o644)).To(Succeed())
+		after, _, err = config.AutoArchiveAfter(clotildeRoot)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(after).To(BeZero())
+	})
+
+	It("rejects invalid periods", func() {
+		Expect(os.WriteFile(config.GetConfigPath(clotildeRoot), []byte(`{"autoArchiveAfter": "soon"}`), 0o644)).To(Succeed())
+
+		_, _, err := config.AutoArchiveAfter(clotildeRoot)
+		Expect(err).To(MatchError(ContainSubstring("invalid autoArchiveAfter")))
+	})
+})
+
+var _ = Describe("MergedMaintenance", func() {
+	var clotildeRoot string
+
+	BeforeEach(func() {
+		tmpDir := GinkgoT().TempDir()
+		clotildeRoot = filepath.Join(tmpDir, "project", config.ClotildeDir)
+		Expect(util.EnsureDir(clotildeRoot)).To(Succeed())
+
+		globalDir := filepath.Join(tmpDir, "xdg")
+		Expect(os.MkdirAll(filepath.Join(globalDir, "clotilde"), 0o755)).To(Succeed())
+		GinkgoT().Setenv("XDG_CONFIG_HOME", globalDir)
+	})
+
+	It("is off by default, with the default interval", func() {
+		enabled, interval, err := config.MergedMaintenance(clotildeRoot)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(enabled).To(BeFalse())
+		Expect(interval).To(Equal(config.DefaultMaintenanceInterval))
+	})
+
+	It("merges the global and project blocks, the project winning", func() {
+		Expect(os.WriteFile(config.GlobalConfigPath(), []byte(`{"maintenance": {"background": true, "interval": "1d"}}`), 0o644)).To(Succeed())
+		Expect(os.WriteFile(config.GetConfigPath(clotildeRoot), []byte(`{"maintenance": {"interval": "2h"}}`), 0o644)).To(Succeed())
+
+		enabled, interval, err := config.MergedMaintenance(clotildeRoot)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(enabled).To(BeTrue())
+		Expect(interval).To(Equal(2 * time.Hour))
+	})
+
+	It("rejects invalid intervals", func() {
+		Expect(os.WriteFile(config.GetConfigPath(clotildeRoot), []byte(`{"maintenance": {"interval": "often"}}`), 0o644)).To(Succeed())
+
+		_, _, err := config.MergedMaintenance(clotildeRoot)
+		Expect(err).To(MatchError(ContainSubstring("invalid maintenance.interval")))
+	})
+})
+
+var _ = Describe("DashboardShowsIncognito", func() {
+	var clotildeRoot string
+
+	BeforeEach(func() {
+		tmpDir := GinkgoT().TempDir()
+		clotildeRoot = filepath.Join(tmpDir, "project", config.ClotildeDir)
+		Expect(util.EnsureDir(clotildeRoot)).To(Succeed())
+
+		globalDir := filepath.Join(tmpDir, "xdg")
+		Expect(os.MkdirAll(filepath.Join(globalDir, "clotilde"), 0o755)).To(Succeed())
+		GinkgoT().Setenv("XDG_CONFIG_HOME", globalDir)
+	})
+
+	It("hides incognito sessions by default", func() {
+		show, err := config.DashboardShowsIncognito(clotildeRoot)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(show).To(BeFalse())
+	})
+
+	It("lets the project setting override the global one", func() {
+		Expect(os.WriteFile(config.GlobalConfigPath(), []byte(`{"dashboard": {"showIncognito": true}}`), 0o644)).To(Succeed())
+
+		show, err := config.DashboardShowsIncognito(clotildeRoot)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(show).To(BeTrue())
+
+		Expect(os.WriteFile(config.GetConfigPath(clotildeRoot), []byte(`{"dashboard": {"showIncognito": false}}`), 0o644)).To(Succeed())
+
+		show, err = config.DashboardShowsIncognito(clotildeRoot)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(show).To(BeFalse())
+	})
+})
+
+var _ = Describe("ActiveSessionsSoftLimit", func() {
+	var clotildeRoot string
+
+	BeforeEach(func() {
+		tmpDir := GinkgoT().TempDir()
+		clotildeRoot = filepath.Join(tmpDir, "project", config.ClotildeDir)
+		Expect(util.EnsureDir(clotildeRoot)).To(Succeed())
+
+		globalDir := filepath.Join(tmpDir, "xdg")
+		Expect(os.MkdirAll(filepath.Join(globalDir, "clotilde"), 0o755)).To(Succeed())
+		GinkgoT().Setenv("XDG_CONFIG_HOME", globalDir)
+	})
+
+	It("is off by default", func() {
+		limit, err := config.ActiveSessionsSoftLimit(clotildeRoot)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(limit).To(BeZero())
+	})
+
+	It("lets a project lower or turn off the global limit", func() {
+		Expect(os.WriteFile(config.GlobalConfigPath(), []byte(`{"activeSessions": {"softLimit": 8}}`), 0o644)).To(Succeed())
+
+		limit, err := config.ActiveSessionsSoftLimit(clotildeRoot)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(limit).To(Equal(8))
+
+		Expect(os.WriteFile(config.GetConfigPath(clotildeRoot), []byte(`{"activeSessions": {"softLimit": 3}}`), 0o644)).To(Succeed())
+		limit, err = config.ActiveSessionsSoftLimit(clotildeRoot)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(limit).To(Equal(3))
+
+		Expect(os.WriteFile(config.GetConfigPath(clotildeRoot), []byte(`{"activeSessions": {"softLimit": -1}}`), 0o644)).To(Succeed())
+		limit, err = config.ActiveSessionsSoftLimit(clotildeRoot)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(limit).To(BeZero())
+	})
+})
+
+var _ = Describe("MergedConfirm", func() {
+	var clotildeRoot string
+
+	BeforeEach(func() {
+		tmpDir := GinkgoT().TempDir()
+		clotildeRoot = filepath.Join(tmpDir, "project", config.ClotildeDir)
+		Expect(util.EnsureDir(clotildeRoot)).To(Succeed())
+
+		globalDir := filepath.Join(tmpDir, "xdg")
+		Expect(os.MkdirAll(filepath.Join(globalDir, "clotilde"), 0o755)).To(Succeed())
+		GinkgoT().Setenv("XDG_CONFIG_HOME", globalDir)
+	})
+
+	It("always asks and requires typing past 10 MB by default", func() {
+		confirm, err := config.MergedConfirm(clotildeRoot)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(confirm).To(Equal(config.Confirm{TypedAboveKB: config.DefaultTypedConfirmAboveKB}))
+	})
+
+	It("merges global and project settings field by field", func() {
+		Expect(os.WriteFile(config.GlobalConfigPath(), []byte(`{"confirm": {"skipBelowTurns": 5, "typedAboveKB": 2048}}`), 0o644)).To(Succeed())
+		Expect(os.WriteFile(config.GetConfigPath(clotildeRoot), []byte(`{"confirm": {"typedAboveKB": 1024}}`), 0o644)).To(Succeed())
+
+		confirm, err := config.MergedConfirm(clotildeRoot)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(confirm).To(Equal(config.Confirm{SkipBelowTurns: 5, TypedAboveKB: 1024}))
+	})
+})
+
+var _ = Describe("MergedLogging", func() {
+	var clotildeRoot string
+
+	BeforeEach(func() {
+		tmpDir := GinkgoT().TempDir()
+		clotildeRoot = filepath.Join(tmpDir, "project", config.ClotildeDir)
+		Expect(util.EnsureDir(clotildeRoot)).To(Succeed())
+
+		globalDir := filepath.Join(tmpDir, "xdg")
+		Expect(os.MkdirAll(filepath.Join(globalDir, "clotilde"), 0o755)).To(Succeed())
+		GinkgoT().Setenv("XDG_CONFIG_HOME", globalDir)
+	})
+
+	It("disables capture by default", func() {
+		logging, err := config.MergedLogging(clotildeRoot)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*logging.CaptureStderr).To(BeFalse())
+		Expect(logging.MaxSizeKB).To(Equal(config.DefaultLogMaxSizeKB))
+	})
+
+	It("merges global and project settings field by field", func() {
+		Expect(os.WriteFile(config.GlobalConfigPath(), []byte(`{"logging": {"captureStderr": true, "maxSizeKB": 256}}`), 0o644)).To(Succeed())
+		Expect(os.WriteFile(config.GetConfigPath(clotildeRoot), []byte(`{"logging": {"maxSizeKB": 64}}`), 0o644)).To(Succeed())
+
+		logging, err := config.MergedLogging(clotildeRoot)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*logging.CaptureStderr).To(BeTrue())
+		Expect(logging.MaxSizeKB).To(Equal(64))
+	})
+})
+
+var _ = Describe("MergedTeam", func() {
+	var clotildeRoot string
+
+	BeforeEach(func() {
+		tmpDir := GinkgoT().TempDir()
+		clotildeRoot = filepath.Join(tmpDir, "project", config.ClotildeDir)
+		Expect(util.EnsureDir(clotildeRoot)).To(Succeed())
+
+		globalDir := filepath.Join(tmpDir, "xdg")
+		Expect(os.MkdirAll(filepath.Join(globalDir, "clotilde"), 0o755)).To(Succeed())
+		GinkgoT().Setenv("XDG_CONFIG_HOME", globalDir)
+	})
+
+	It("is empty when not configured", func() {
+		team, err := config.MergedTeam(clotildeRoot)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(team).To(Equal(config.Team{}))
+	})
+
+	It("merges global and project settings field by field", func() {
+		Expect(os.WriteFile(config.GlobalConfigPath(), []byte(`{"team": {"user": "alice", "dir": "/mnt/global"}}`), 0o644)).To(Succeed())
+		Expect(os.WriteFile(config.GetConfigPath(clotildeRoot), []byte(`{"team": {"dir": "/mnt/shared", "project": "api"}}`), 0o644)).To(Succeed())
+
+		team, err := config.MergedTeam(clotildeRoot)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(team).To(Equal(config.Team{Dir: "/mnt/shared", Project: "api", User: "alice"}))
+	})
+})
+
+var _ = Describe("LookupRemote", func() {
+	BeforeEach(func() {
+		GinkgoT().Setenv("XDG_CONFIG_HOME", filepath.Join(GinkgoT().TempDir(), "xdg"))
+	})
+
+	It("uses unknown names as the ssh destination", func() {
+		remote, err := config.LookupRemote("me@devbox")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remote).To(Equal(config.Remote{Host: "me@devbox", Clotilde: "clotilde"}))
+	})
+
+	It("returns configured remotes", func() {
+		Expect(util.WriteJSON(config.GlobalConfigPath(), map[string]any{
+			"remotes": map[string]any{
+				"dev": map[string]any{"host": "me@devbox", "sshArgs": []string{"-p", "2222"}, "clotilde": "~/bin/clotilde"},
+			},
+		})).To(Succeed())
+
+		remote, err := config.LookupRemote("dev")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remote).To(Equal(config.Remote{Host: "me@devbox", SSHArgs: []string{"-p", "2222"}, Clotilde: "~/bin/clotilde"}))
+	})
+
+	It("rejects remotes without a host", func() {
+		Expect(util.WriteJSON(config.GlobalConfigPath(), map[string]any{
+			"remotes": map[string]any{"dev": map[string]any{"sshArgs": []string{"-v"}}},
+		})).To(Succeed())
+
+		_, err := config.LookupRemote("dev")
+		Expect(err).To(MatchError(ContainSubstring("no host")))
+	})
+})
+
+var _ = Describe("GlobalPicker", func() {
+	BeforeEach(func() {
+		GinkgoT().Setenv("XDG_CONFIG_HOME", filepath.Join(GinkgoT().TempDir(), "xdg"))
+	})
+
+	It("returns zero values when nothing is saved", func() {
+		picker, err := config.GlobalPicker()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(picker).To(Equal(config.Picker{}))
+	})
+
+	It("saves preferences without dropping other settings", func() {
+		Expect(util.WriteJSON(config.GlobalConfigPath(), map[string]any{
+			"defaults": map[string]string{"model": "opus"},
+		})).To(Succeed())
+
+		Expect(config.SaveGlobalPicker(config.Picker{HidePreview: true, PreviewWidth: 40})).To(Succeed())
+
+		picker, err := config.GlobalPicker()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(picker).To(Equal(config.Picker{HidePreview: true, PreviewWidth: 40}))
+
+		cfg, err := config.LoadGlobalOrDefault()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Defaults.Model).To(Equal("opus"))
+	})
+
+	It("rewrites only the picker key, keeping unknown keys and formatting", func() {
+		original := "{\n    \"future\": {\"x\": [1,2]},\n    \"defaults\": {\"model\": \"opus\"}\n}\n"
+		Expect(util.WriteFile(config.GlobalConfigPath(), []byte(original))).To(Succeed())
+
+		Expect(config.SaveGlobalPicker(config.Picker{PreviewWidth: 40})).To(Succeed())
+		data, err := os.ReadFile(config.GlobalConfigPath())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(HavePrefix("{\n    \"future\": {\"x\": [1,2]},\n    \"defaults\": {\"model\": \"opus\"},\n  \"picker\": {"))
+
+		Expect(config.SaveGlobalPicker(config.Picker{HidePreview: true})).To(Succeed())
+		updated, err := os.ReadFile(config.GlobalConfigPath())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(updated)).To(HavePrefix("{\n    \"future\": {\"x\": [1,2]},\n    \"defaults\": {\"model\": \"opus\"},\n  \"picker\": {"))
+		Expect(strings.Count(string(updated), `"picker"`)).To(Equal(1))
+
+		picker, err := config.GlobalPicker()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(picker).To(Equal(config.Picker{HidePreview: true}))
+	})
+
+	It("creates the global config when there is none", func() {
+		Expect(config.SaveGlobalPicker(config.Picker{PreviewWidth: 40})).To(Succeed())
+		picker, err := config.GlobalPicker()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(picker).To(Equal(config.Picker{PreviewWidth: 40}))
+	})
+})
+
+var _ = Describe("ClearTranscriptPolicy", func() {
+	var clotildeRoot string
+
+	BeforeEach(func() {
+		tmpDir := GinkgoT().TempDir()
+		clotildeRoot = filepath.Join(tmpDir, "project", config.ClotildeDir)
+		Expect(util.EnsureDir(clotildeRoot)).To(Succeed())
+
+		globalDir := filepath.Join(tmpDir, "xdg")
+		Expect(os.MkdirAll(filepath.Join(globalDir, "clotilde"), 0o755)).To(Succeed())
+		GinkgoT().Setenv("XDG_CONFIG_HOME", globalDir)
+	})
+
+	It("keeps transcripts by default", func() {
+		policy, err := config.ClearTranscriptPolicy(clotildeRoot)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(policy).To(Equal(config.ClearKeep))
+	})
+
+	It("lets the project setting override the global one", func() {
+		Expect(os.WriteFile(config.GlobalConfigPath(), []byte(`{"clear": {"transcript": "prune"}}`), 0o644)).To(Succeed())
+		Expect(os.WriteFile(config.GetConfigPath(clotildeRoot), []byte(`{"clear": {"transcript": "snapshot"}}`), 0o644)).To(Succeed())
+
+		policy, err := config.ClearTranscriptPolicy(clotildeRoot)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(policy).To(Equal(config.ClearSnapshot))
+	})
+
+	It("rejects unknown policies", func() {
+		Expect(os.WriteFile(config.GetConfigPath(clotildeRoot), []byte(`{"clear": {"transcript": "archive"}}`), 0o644)).To(Succeed())
+
+		_, err := config.ClearTranscriptPolicy(clotildeRoot)
+		Expect(err).To(MatchError(ContainSubstring(`invalid clear.transcript "archive"`)))
+	})
+})
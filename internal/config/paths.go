@@ -1,11 +1,11 @@
 package config
 
 import (
-	"errors"
-	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/fgrehm/clotilde/internal/errs"
 	"github.com/fgrehm/clotilde/internal/util"
 )
 
@@ -18,11 +18,16 @@ const (
 
 	// ConfigFile is the config file name
 	ConfigFile = "config.json"
+
+	// SessionsFileName is the checked-in file, at the project root, that
+	// declares a project's standard sessions
+	SessionsFileName = "clotilde.yaml"
 )
 
 // FindClotildeRoot searches for the .claude/clotilde directory by walking up
 // from the current working directory. Returns the absolute path to the
-// .claude/clotilde directory, or an error if not found.
+// .claude/clotilde directory (or to the storage a StoragePointerFile names),
+// or an error if not found.
 func FindClotildeRoot() (string, error) {
 	cwd, err := os.Getwd()
 	if err != nil {
@@ -49,12 +54,15 @@ func ClotildeRootFromPath(startPath string) (string, error) {
 		if err == nil && info.IsDir() {
 			return clotildePath, nil
 		}
+		if root, found, err := readStoragePointer(currentPath); err != nil || found {
+			return root, err
+		}
 
 		// Move up to parent directory
 		parentPath := filepath.Dir(currentPath)
 		if parentPath == currentPath {
 			// Reached filesystem root
-			return "", errors.New(".claude/clotilde not found in directory tree")
+			return "", errs.NotInProject(".claude/clotilde not found in directory tree")
 		}
 		currentPath = parentPath
 	}
@@ -86,6 +94,48 @@ func GlobalConfigPath() string {
 	return filepath.Join(configHome, "clotilde", ConfigFile)
 }
 
+// GlobalKeyPath returns the path to the encryption key, next to the global config.
+func GlobalKeyPath() string {
+	return filepath.Join(filepath.Dir(GlobalConfigPath()), "key")
+}
+
+// GlobalDataDir returns the directory for clotilde's global state (e.g. the
+// project registry). Respects $XDG_DATA_HOME if set, otherwise uses
+// ~/.local/share/clotilde.
+func GlobalDataDir() string {
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		home, _ := os.UserHomeDir()
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dataHome, "clotilde")
+}
+
+// ProjectRootOf returns the project root for a clotilde root: the parent of
+// .claude/clotilde, or the project recorded in storage kept outside it.
+func ProjectRootOf(clotildeRoot string) string {
+	if cached, ok := projectRoots.Load(clotildeRoot); ok {
+		return cached.(string)
+	}
+	projectRoot := filepath.Dir(filepath.Dir(clotildeRoot))
+	if data, err := os.ReadFile(filepath.Join(clotildeRoot, projectRootFile)); err == nil {
+		projectRoot = strings.TrimSpace(string(data))
+	}
+	projectRoots.Store(clotildeRoot, projectRoot)
+	return projectRoot
+}
+
+// ProjectClaudeDir returns the project's .claude directory, where Claude Code
+// looks for output styles, wherever the clotilde root is kept.
+func ProjectClaudeDir(clotildeRoot string) string {
+	return filepath.Join(ProjectRootOf(clotildeRoot), ".claude")
+}
+
+// SessionsFilePath returns the path of the project's clotilde.yaml.
+func SessionsFilePath(clotildeRoot string) string {
+	return filepath.Join(ProjectRootOf(clotildeRoot), SessionsFileName)
+}
+
 // EnsureClotildeStructure creates the .claude/clotilde directory structure
 // at the given path if it doesn't exist.
 func EnsureClotildeStructure(projectRoot string) error {
@@ -146,29 +196,19 @@ func ProjectRootFromPath(startPath string) string {
 
 // FindOrCreateClotildeRoot finds or creates the .claude/clotilde directory for the
 // current project. It resolves the project root first (which stops at $HOME), then
-// checks if .claude/clotilde exists there. This avoids the bug where an existing
-// ~/.claude/clotilde (from legacy usage) would shadow the correct project-local root.
+// checks if .claude/clotilde (or a StoragePointerFile) exists there. This avoids the
+// bug where an existing ~/.claude/clotilde (from legacy usage) would shadow the
+// correct project-local root. New storage goes where the global "storage" setting says.
 func FindOrCreateClotildeRoot() (string, error) {
 	projectRoot, err := FindProjectRoot()
 	if err != nil {
 		return "", err
 	}
 
-	clotildeRoot := filepath.Join(projectRoot, ClotildeDir)
-	if info, statErr := os.Stat(clotildeRoot); statErr == nil {
-		if !info.IsDir() {
-			return "", fmt.Errorf("%s exists and is not a directory", clotildeRoot)
-		}
-		return clotildeRoot, nil
-	} else if !os.IsNotExist(statErr) {
-		return "", fmt.Errorf("failed to stat clotilde root %s: %w", clotildeRoot, statErr)
+	if clotildeRoot, found, err := ProjectClotildeRoot(projectRoot); err != nil || found {
+		return clotildeRoot, err
 	}
-
-	if err := EnsureSessionsDir(projectRoot); err != nil {
-		return "", fmt.Errorf("failed to create clotilde structure: %w", err)
-	}
-
-	return clotildeRoot, nil
+	return newProjectStorage(projectRoot)
 }
 
 // IsInitialized checks if clotilde is initialized in the current directory tree.
@@ -240,6 +240,8 @@ var _ = Describe("FindOrCreateClotildeRoot", func() {
 
 	BeforeEach(func() {
 		tempDir = GinkgoT().TempDir()
+		// The global "storage" setting decides where new roots go
+		GinkgoT().Setenv("XDG_CONFIG_HOME", filepath.Join(tempDir, "xdg-config"))
 		var err error
 		originalWd, err = os.Getwd()
 		Expect(err).NotTo(HaveOccurred())
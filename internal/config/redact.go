@@ -0,0 +1,57 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// RedactedText replaces matches of the "redact" patterns.
+const RedactedText = "[REDACTED]"
+
+// Redactor masks matches of the "redact" patterns. A nil Redactor leaves
+// text unchanged.
+type Redactor struct {
+	patterns []*regexp.Regexp
+}
+
+// NewRedactor compiles patterns into a Redactor, or returns nil when there
+// are none.
+func NewRedactor(patterns []string) (*Redactor, error) {
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+	r := &Redactor{}
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redact pattern '%s': %w", pattern, err)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+	return r, nil
+}
+
+// LoadRedactor returns a Redactor for the "redact" patterns of the global and
+// project configs combined.
+func LoadRedactor(clotildeRoot string) (*Redactor, error) {
+	globalCfg, err := LoadGlobalOrDefault()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load global config: %w", err)
+	}
+	projectCfg, err := LoadOrDefault(clotildeRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load project config: %w", err)
+	}
+	return NewRedactor(append(append([]string{}, globalCfg.Redact...), projectCfg.Redact...))
+}
+
+// Redact returns text with every match replaced by RedactedText.
+func (r *Redactor) Redact(text string) string {
+	if r == nil {
+		return text
+	}
+	for _, re := range r.patterns {
+		text = re.ReplaceAllLiteralString(text, RedactedText)
+	}
+	return text
+}
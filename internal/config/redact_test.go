@@ -0,0 +1,44 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/util"
+)
+
+var _ = Describe("Redactor", func() {
+	var clotildeRoot string
+
+	BeforeEach(func() {
+		tmpDir := GinkgoT().TempDir()
+		clotildeRoot = filepath.Join(tmpDir, "project", config.ClotildeDir)
+		Expect(util.EnsureDir(clotildeRoot)).To(Succeed())
+		GinkgoT().Setenv("XDG_CONFIG_HOME", filepath.Join(tmpDir, "xdg"))
+	})
+
+	It("leaves text unchanged without patterns", func() {
+		redactor, err := config.LoadRedactor(clotildeRoot)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(redactor).To(BeNil())
+		Expect(redactor.Redact("token ghp_abc")).To(Equal("token ghp_abc"))
+	})
+
+	It("combines global and project patterns", func() {
+		Expect(util.WriteJSON(config.GlobalConfigPath(), map[string]any{"redact": []string{`ghp_[A-Za-z0-9]+`}})).To(Succeed())
+		Expect(os.WriteFile(config.GetConfigPath(clotildeRoot), []byte(`{"redact": ["password=\\S+"]}`), 0o644)).To(Succeed())
+
+		redactor, err := config.LoadRedactor(clotildeRoot)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(redactor.Redact("use ghp_abc123 with password=hunter2 $1")).To(Equal("use [REDACTED] with [REDACTED] $1"))
+	})
+
+	It("rejects invalid patterns", func() {
+		_, err := config.NewRedactor([]string{"("})
+		Expect(err).To(MatchError(ContainSubstring("invalid redact pattern '('")))
+	})
+})
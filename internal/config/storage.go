@@ -0,0 +1,136 @@
+package config
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/fgrehm/clotilde/internal/util"
+)
+
+// Storage modes for the global config's "storage" setting, which decides where
+// new projects keep their sessions.
+const (
+	StorageProject = "project" // .claude/clotilde inside the project (the default)
+	StorageData    = "data"    // Under GlobalDataDir(), found through StoragePointerFile
+)
+
+// StoragePointerFile, relative to the project root, holds the path of session
+// storage kept outside the project.
+const StoragePointerFile = ".claude/clotilde-storage"
+
+// projectRootFile, in storage kept outside a project, records the project it
+// belongs to.
+const projectRootFile = "project-root"
+
+// projectRoots caches ProjectRootOf, which a command calls for most paths it
+// builds. Only writeProjectRoot changes the project-root file.
+var projectRoots sync.Map
+
+// DataStorageDir returns where the sessions of projectRoot are kept in data
+// storage mode: a folder named after the project plus a hash of its path.
+func DataStorageDir(projectRoot string) string {
+	sum := sha256.Sum256([]byte(projectRoot))
+	return filepath.Join(GlobalDataDir(), fmt.Sprintf("%s-%x", filepath.Base(projectRoot), sum[:6]))
+}
+
+// EnsureDataStorage creates data storage for projectRoot and the pointer file
+// that leads to it, returning the new clotilde root.
+func EnsureDataStorage(projectRoot string) (string, error) {
+	clotildeRoot := DataStorageDir(projectRoot)
+	if err := util.EnsureDir(GetSessionsDir(clotildeRoot)); err != nil {
+		return "", fmt.Errorf("failed to create session storage: %w", err)
+	}
+	if err := writeProjectRoot(clotildeRoot, projectRoot); err != nil {
+		return "", fmt.Errorf("failed to create session storage: %w", err)
+	}
+
+	pointer := filepath.Join(projectRoot, StoragePointerFile)
+	if err := util.EnsureDir(filepath.Dir(pointer)); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", StoragePointerFile, err)
+	}
+	if err := os.WriteFile(pointer, []byte(clotildeRoot+"\n"), 0o644); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", StoragePointerFile, err)
+	}
+	return clotildeRoot, nil
+}
+
+// ProjectClotildeRoot returns the clotilde root of the project at projectRoot
+// without walking up: .claude/clotilde, or the storage named by its pointer
+// file. found is false when the project has neither.
+func ProjectClotildeRoot(projectRoot string) (root string, found bool, err error) {
+	local := filepath.Join(projectRoot, ClotildeDir)
+	if info, statErr := os.Stat(local); statErr == nil {
+		if !info.IsDir() {
+			return "", false, fmt.Errorf("%s exists and is not a directory", local)
+		}
+		return local, true, nil
+	} else if !os.IsNotExist(statErr) {
+		return "", false, fmt.Errorf("failed to stat clotilde root %s: %w", local, statErr)
+	}
+	return readStoragePointer(projectRoot)
+}
+
+// readStoragePointer resolves the project's StoragePointerFile, if any. A
+// relative path in it is relative to the project root, not the working
+// directory.
+func readStoragePointer(projectRoot string) (string, bool, error) {
+	pointer := filepath.Join(projectRoot, StoragePointerFile)
+	data, err := os.ReadFile(pointer)
+	if os.IsNotExist(err) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to read %s: %w", pointer, err)
+	}
+	root := strings.TrimSpace(string(data))
+	if root == "" {
+		return "", false, fmt.Errorf("%s is empty", pointer)
+	}
+	if !filepath.IsAbs(root) {
+		root = filepath.Join(projectRoot, root)
+	}
+	if !util.DirExists(root) {
+		return "", false, fmt.Errorf("session storage %s (from %s) doesn't exist", root, pointer)
+	}
+	return root, true, nil
+}
+
+// RelinkDataStorage records projectRoot as the project that storage kept
+// outside it belongs to, after the project was moved. Project-local roots
+// need nothing and are left alone.
+func RelinkDataStorage(clotildeRoot, projectRoot string) error {
+	if !util.FileExists(filepath.Join(clotildeRoot, projectRootFile)) {
+		return nil
+	}
+	return writeProjectRoot(clotildeRoot, projectRoot)
+}
+
+// writeProjectRoot records projectRoot in storage kept outside it.
+func writeProjectRoot(clotildeRoot, projectRoot string) error {
+	projectRoots.Delete(clotildeRoot)
+	return os.WriteFile(filepath.Join(clotildeRoot, projectRootFile), []byte(projectRoot+"\n"), 0o644)
+}
+
+// newProjectStorage creates session storage for a project that has none yet,
+// where the global config's "storage" setting says.
+func newProjectStorage(projectRoot string) (string, error) {
+	globalCfg, err := LoadGlobalOrDefault()
+	if err != nil {
+		return "", fmt.Errorf("failed to load global config: %w", err)
+	}
+	switch globalCfg.Storage {
+	case "", StorageProject:
+		if err := EnsureSessionsDir(projectRoot); err != nil {
+			return "", fmt.Errorf("failed to create clotilde structure: %w", err)
+		}
+		return filepath.Join(projectRoot, ClotildeDir), nil
+	case StorageData:
+		return EnsureDataStorage(projectRoot)
+	default:
+		return "", fmt.Errorf("invalid storage '%s' in global config (use %q or %q)", globalCfg.Storage, StorageProject, StorageData)
+	}
+}
@@ -0,0 +1,120 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/util"
+)
+
+var _ = Describe("Data storage", func() {
+	var (
+		tempDir     string
+		projectRoot string
+		originalWd  string
+	)
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = filepath.EvalSymlinks(GinkgoT().TempDir())
+		Expect(err).NotTo(HaveOccurred())
+		GinkgoT().Setenv("XDG_CONFIG_HOME", filepath.Join(tempDir, "config"))
+		GinkgoT().Setenv("XDG_DATA_HOME", filepath.Join(tempDir, "data"))
+
+		projectRoot = filepath.Join(tempDir, "work", "myapp")
+		Expect(util.EnsureDir(filepath.Join(projectRoot, "src"))).To(Succeed())
+
+		originalWd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Chdir(projectRoot)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.Chdir(originalWd)).To(Succeed())
+	})
+
+	useDataStorage := func() {
+		Expect(util.WriteJSON(config.GlobalConfigPath(), map[string]string{"storage": config.StorageData})).To(Succeed())
+	}
+
+	It("creates new roots under the data dir when the global config asks for it", func() {
+		useDataStorage()
+
+		root, err := config.FindOrCreateClotildeRoot()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(root).To(Equal(config.DataStorageDir(projectRoot)))
+		Expect(root).To(HavePrefix(filepath.Join(tempDir, "data", "clotilde", "myapp-")))
+		Expect(util.DirExists(config.GetSessionsDir(root))).To(BeTrue())
+		Expect(util.DirExists(filepath.Join(projectRoot, config.ClotildeDir))).To(BeFalse())
+
+		pointer, err := os.ReadFile(filepath.Join(projectRoot, config.StoragePointerFile))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(strings.TrimSpace(string(pointer))).To(Equal(root))
+	})
+
+	It("resolves the pointer file when walking up and maps the root back to the project", func() {
+		root, err := config.EnsureDataStorage(projectRoot)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(os.Chdir(filepath.Join(projectRoot, "src"))).To(Succeed())
+		found, err := config.FindClotildeRoot()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(Equal(root))
+
+		Expect(config.ProjectRootOf(root)).To(Equal(projectRoot))
+		Expect(config.ProjectClaudeDir(root)).To(Equal(filepath.Join(projectRoot, ".claude")))
+	})
+
+	It("keeps using existing project-local roots", func() {
+		useDataStorage()
+		Expect(config.EnsureClotildeStructure(projectRoot)).To(Succeed())
+
+		root, err := config.FindOrCreateClotildeRoot()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(root).To(Equal(filepath.Join(projectRoot, config.ClotildeDir)))
+		Expect(config.ProjectRootOf(root)).To(Equal(projectRoot))
+	})
+
+	It("resolves a relative pointer against the project root", func() {
+		root, err := config.EnsureDataStorage(projectRoot)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Rename(root, filepath.Join(projectRoot, "sessions-store"))).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(projectRoot, config.StoragePointerFile), []byte("sessions-store\n"), 0o644)).To(Succeed())
+
+		Expect(os.Chdir(filepath.Join(projectRoot, "src"))).To(Succeed())
+		found, err := config.FindClotildeRoot()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(Equal(filepath.Join(projectRoot, "sessions-store")))
+	})
+
+	It("maps the root back to the project it was moved to", func() {
+		root, err := config.EnsureDataStorage(projectRoot)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.ProjectRootOf(root)).To(Equal(projectRoot))
+
+		moved := filepath.Join(GinkgoT().TempDir(), "moved")
+		Expect(config.RelinkDataStorage(root, moved)).To(Succeed())
+		Expect(config.ProjectRootOf(root)).To(Equal(moved))
+	})
+
+	It("reports a pointer to missing storage", func() {
+		root, err := config.EnsureDataStorage(projectRoot)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.RemoveAll(root)).To(Succeed())
+
+		_, err = config.FindClotildeRoot()
+		Expect(err).To(MatchError(ContainSubstring("doesn't exist")))
+	})
+
+	It("rejects an unknown storage setting", func() {
+		Expect(util.WriteJSON(config.GlobalConfigPath(), map[string]string{"storage": "cloud"})).To(Succeed())
+
+		_, err := config.FindOrCreateClotildeRoot()
+		Expect(err).To(MatchError(ContainSubstring("invalid storage 'cloud'")))
+	})
+})
@@ -0,0 +1,259 @@
+// Package crypt implements clotilde's opt-in at-rest encryption: AES-256-GCM
+// with a random key kept in the global config directory (config.GlobalKeyPath).
+// Whole files are sealed with a magic header; short strings (session contexts)
+// are sealed into a "enc:v1:" prefixed base64 string so they fit in JSON.
+package crypt
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fgrehm/clotilde/internal/config"
+)
+
+const (
+	keySize      = 32
+	stringPrefix = "enc:v1:"
+)
+
+// fileMagic starts every sealed file.
+var fileMagic = []byte("clotilde-enc-v1\n")
+
+// ErrNoKey is returned when encrypted data needs a key that doesn't exist.
+var ErrNoKey = errors.New("no encryption key found (run 'clotilde encryption enable' to create one)")
+
+// LoadKey reads the key from config.GlobalKeyPath().
+func LoadKey() ([]byte, error) {
+	data, err := os.ReadFile(config.GlobalKeyPath())
+	if os.IsNotExist(err) {
+		return nil, ErrNoKey
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read encryption key: %w", err)
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(key) != keySize {
+		return nil, fmt.Errorf("invalid encryption key in %s", config.GlobalKeyPath())
+	}
+	return key, nil
+}
+
+// EnsureKey returns the existing key, creating a random one readable only by
+// the user when there is none. created reports whether a key was generated.
+func EnsureKey() (key []byte, created bool, err error) {
+	key, err = LoadKey()
+	if !errors.Is(err, ErrNoKey) {
+		return key, false, err
+	}
+
+	key = make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, false, err
+	}
+	path := config.GlobalKeyPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, false, fmt.Errorf("failed to create config directory: %w", err)
+	}
+	// O_EXCL so a key created concurrently is never overwritten
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to write encryption key: %w", err)
+	}
+	if _, err := f.WriteString(base64.StdEncoding.EncodeToString(key) + "\n"); err != nil {
+		_ = f.Close()
+		return nil, false, fmt.Errorf("failed to write encryption key: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return nil, false, fmt.Errorf("failed to write encryption key: %w", err)
+	}
+	return key, true, nil
+}
+
+// ProjectKey returns the key when encryption is enabled for the project at
+// clotildeRoot, or nil when it's disabled.
+func ProjectKey(clotildeRoot string) ([]byte, error) {
+	enabled, err := config.EncryptionEnabled(clotildeRoot)
+	if err != nil || !enabled {
+		return nil, err
+	}
+	return LoadKey()
+}
+
+// Seal encrypts data into the sealed file format.
+func Seal(key, data []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	out := append(bytes.Clone(fileMagic), nonce...)
+	return aead.Seal(out, nonce, data, fileMagic), nil
+}
+
+// Open decrypts data produced by Seal.
+func Open(key, data []byte) ([]byte, error) {
+	if !IsSealed(data) {
+		return nil, errors.New("data is not encrypted")
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	data = data[len(fileMagic):]
+	if len(data) < aead.NonceSize() {
+		return nil, errors.New("encrypted data is truncated")
+	}
+	plain, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], fileMagic)
+	if err != nil {
+		return nil, errors.New("failed to decrypt (wrong key or corrupted data)")
+	}
+	return plain, nil
+}
+
+// IsSealed reports whether data was produced by Seal.
+func IsSealed(data []byte) bool {
+	return bytes.HasPrefix(data, fileMagic)
+}
+
+// SealString encrypts s into a printable string.
+func SealString(key []byte, s string) (string, error) {
+	sealed, err := Seal(key, []byte(s))
+	if err != nil {
+		return "", err
+	}
+	return stringPrefix + base64.StdEncoding.EncodeToString(sealed[len(fileMagic):]), nil
+}
+
+// OpenString decrypts a string produced by SealString.
+func OpenString(key []byte, s string) (string, error) {
+	if !IsSealedString(s) {
+		return "", errors.New("value is not encrypted")
+	}
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(s, stringPrefix))
+	if err != nil {
+		return "", errors.New("encrypted value is corrupted")
+	}
+	plain, err := Open(key, append(bytes.Clone(fileMagic), raw...))
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
+
+// IsSealedString reports whether s was produced by SealString.
+func IsSealedString(s string) bool {
+	return strings.HasPrefix(s, stringPrefix)
+}
+
+// WriteFile writes data to path, sealed when encryption is enabled for the
+// project at clotildeRoot.
+func WriteFile(clotildeRoot, path string, data []byte, perm os.FileMode) error {
+	key, err := ProjectKey(clotildeRoot)
+	if err != nil {
+		return err
+	}
+	if key != nil {
+		if data, err = Seal(key, data); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(path, data, perm)
+}
+
+// ReadFile reads path, decrypting it when it's sealed.
+func ReadFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil || !IsSealed(data) {
+		return data, err
+	}
+	key, err := LoadKey()
+	if err != nil {
+		return nil, err
+	}
+	return Open(key, data)
+}
+
+// SealFile encrypts path in place unless it's already sealed.
+func SealFile(key []byte, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil || IsSealed(data) {
+		return err
+	}
+	sealed, err := Seal(key, data)
+	if err != nil {
+		return err
+	}
+	return replaceFile(path, sealed)
+}
+
+// UnsealFile decrypts path in place when it's sealed.
+func UnsealFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil || !IsSealed(data) {
+		return err
+	}
+	plain, err := ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return replaceFile(path, plain)
+}
+
+// Unsealed decrypts path in place for as long as a program needs to read it
+// (claude reading an output style) and returns a function that seals it
+// again. Missing and plain files are left alone and the function is a no-op.
+func Unsealed(path string) (reseal func() error, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil || !IsSealed(data) {
+		return func() error { return nil }, nil
+	}
+	key, err := LoadKey()
+	if err != nil {
+		return nil, err
+	}
+	plain, err := Open(key, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt %s: %w", path, err)
+	}
+	if err := replaceFile(path, plain); err != nil {
+		return nil, err
+	}
+	return func() error {
+		if err := SealFile(key, path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}, nil
+}
+
+// replaceFile rewrites path atomically, keeping its permissions.
+func replaceFile(path string, data []byte) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, info.Mode().Perm()); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
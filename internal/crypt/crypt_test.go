@@ -0,0 +1,120 @@
+package crypt_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/crypt"
+	"github.com/fgrehm/clotilde/internal/util"
+)
+
+var _ = Describe("crypt", func() {
+	var tmpDir string
+
+	BeforeEach(func() {
+		tmpDir = GinkgoT().TempDir()
+		GinkgoT().Setenv("XDG_CONFIG_HOME", filepath.Join(tmpDir, "xdg"))
+	})
+
+	Describe("keys", func() {
+		It("reports a missing key", func() {
+			_, err := crypt.LoadKey()
+			Expect(err).To(MatchError(crypt.ErrNoKey))
+		})
+
+		It("creates a private key once", func() {
+			key, created, err := crypt.EnsureKey()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(created).To(BeTrue())
+			Expect(key).To(HaveLen(32))
+
+			info, err := os.Stat(config.GlobalKeyPath())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.Mode().Perm()).To(Equal(os.FileMode(0o600)))
+
+			again, created, err := crypt.EnsureKey()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(created).To(BeFalse())
+			Expect(again).To(Equal(key))
+		})
+	})
+
+	Describe("sealing", func() {
+		var key []byte
+
+		BeforeEach(func() {
+			var err error
+			key, _, err = crypt.EnsureKey()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("round-trips data", func() {
+			sealed, err := crypt.Seal(key, []byte("secret prompt"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(crypt.IsSealed(sealed)).To(BeTrue())
+			Expect(string(sealed)).NotTo(ContainSubstring("secret prompt"))
+
+			plain, err := crypt.Open(key, sealed)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(plain)).To(Equal("secret prompt"))
+		})
+
+		It("fails with the wrong key", func() {
+			sealed, err := crypt.Seal(key, []byte("secret"))
+			Expect(err).NotTo(HaveOccurred())
+
+			other := make([]byte, 32)
+			_, err = crypt.Open(other, sealed)
+			Expect(err).To(MatchError(ContainSubstring("wrong key")))
+		})
+
+		It("round-trips strings", func() {
+			sealed, err := crypt.SealString(key, "ticket GH-123")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(crypt.IsSealedString(sealed)).To(BeTrue())
+
+			plain, err := crypt.OpenString(key, sealed)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(plain).To(Equal("ticket GH-123"))
+		})
+
+		It("decrypts a file while it's in use and seals it again", func() {
+			path := filepath.Join(tmpDir, "style.md")
+			Expect(os.WriteFile(path, []byte("be terse"), 0o644)).To(Succeed())
+			Expect(crypt.SealFile(key, path)).To(Succeed())
+
+			reseal, err := crypt.Unsealed(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(os.ReadFile(path)).To(Equal([]byte("be terse")))
+
+			Expect(reseal()).To(Succeed())
+			data, err := os.ReadFile(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(crypt.IsSealed(data)).To(BeTrue())
+			Expect(crypt.ReadFile(path)).To(Equal([]byte("be terse")))
+		})
+	})
+
+	Describe("WriteFile", func() {
+		It("seals only when the project enables encryption", func() {
+			clotildeRoot := filepath.Join(tmpDir, "project", config.ClotildeDir)
+			Expect(util.EnsureDir(clotildeRoot)).To(Succeed())
+			path := filepath.Join(tmpDir, "out.md")
+
+			Expect(crypt.WriteFile(clotildeRoot, path, []byte("plain"), 0o644)).To(Succeed())
+			Expect(os.ReadFile(path)).To(Equal([]byte("plain")))
+
+			_, _, err := crypt.EnsureKey()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.SaveProjectEncryption(clotildeRoot, true)).To(Succeed())
+			Expect(crypt.WriteFile(clotildeRoot, path, []byte("hidden"), 0o644)).To(Succeed())
+			data, err := os.ReadFile(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(crypt.IsSealed(data)).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,306 @@
+// Package daemon serves clotilde to editor extensions over JSON-RPC 2.0, one
+// message per line, so they can list, create and watch sessions from one
+// long-lived process instead of spawning the CLI for every query.
+//
+// Methods:
+//
+//	listSessions   -> session metadata, most recently used first
+//	createSession  {name, context?, model?, effortLevel?} -> session metadata
+//	resumeSession  {name, args?} -> {command, cwd} to run in a terminal
+//	watchEvents    -> {watching: true}, then "event" notifications carrying
+//	                  each new line of the event log
+//
+// Errors about sessions use code -32000 with the CLI's exit code in
+// data.exitCode (e.g. 3 for a session that doesn't exist).
+package daemon
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/fgrehm/clotilde/internal/app"
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/errs"
+	"github.com/fgrehm/clotilde/internal/events"
+	"github.com/fgrehm/clotilde/internal/session"
+)
+
+// JSON-RPC 2.0 error codes.
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeSessionError   = -32000 // a clotilde failure; data.exitCode says which
+)
+
+// maxMessageSize bounds a single request line.
+const maxMessageSize = 1024 * 1024
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"` // absent for notifications
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type notification struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    any    `json:"data,omitempty"`
+}
+
+func (e *rpcError) Error() string { return e.Message }
+
+// Server answers requests for one project.
+type Server struct {
+	root  string
+	store session.Store
+
+	// PollInterval is how often watchEvents checks the event log.
+	PollInterval time.Duration
+	// Executable is the clotilde binary resumeSession tells clients to run.
+	Executable string
+
+	mu       sync.Mutex // serializes writes to the client
+	enc      *json.Encoder
+	watching bool
+	watchers sync.WaitGroup
+}
+
+// New creates a server for the sessions under clotildeRoot.
+func New(clotildeRoot string) *Server {
+	executable, err := os.Executable()
+	if err != nil {
+		executable = "clotilde"
+	}
+	return &Server{
+		root:         clotildeRoot,
+		store:        session.NewFileStore(clotildeRoot),
+		PollInterval: 500 * time.Millisecond,
+		Executable:   executable,
+	}
+}
+
+// Serve reads requests from r and writes responses and notifications to w
+// until r ends or ctx is done. Requests are handled in order.
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer func() {
+		cancel()
+		s.watchers.Wait()
+	}()
+	s.enc = json.NewEncoder(w)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxMessageSize)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		if err := s.handle(ctx, line); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// handle answers one request line. It only fails when the client can't be
+// written to.
+func (s *Server) handle(ctx context.Context, line []byte) error {
+	var req request
+	if err := json.Unmarshal(line, &req); err != nil {
+		return s.send(response{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &rpcError{Code: codeParseError, Message: err.Error()}})
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return s.send(response{JSONRPC: "2.0", ID: idOrNull(req.ID), Error: &rpcError{Code: codeInvalidRequest, Message: "expected a JSON-RPC 2.0 request"}})
+	}
+
+	result, err := s.call(ctx, req.Method, req.Params)
+	if req.ID == nil {
+		return nil // notifications get no response
+	}
+	resp := response{JSONRPC: "2.0", ID: req.ID, Result: result}
+	if err != nil {
+		resp.Result = nil
+		resp.Error = toRPCError(err)
+	}
+	return s.send(resp)
+}
+
+func (s *Server) call(ctx context.Context, method string, params json.RawMessage) (any, error) {
+	switch method {
+	case "listSessions":
+		return s.listSessions()
+	case "createSession":
+		var p struct {
+			Name        string `json:"name"`
+			Context     string `json:"context"`
+			Model       string `json:"model"`
+			EffortLevel string `json:"effortLevel"`
+		}
+		if err := decodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		return s.createSession(p.Name, p.Context, p.Model, p.EffortLevel)
+	case "resumeSession":
+		var p struct {
+			Name string   `json:"name"`
+			Args []string `json:"args"`
+		}
+		if err := decodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		return s.resumeSession(p.Name, p.Args)
+	case "watchEvents":
+		return s.watchEvents(ctx)
+	default:
+		return nil, &rpcError{Code: codeMethodNotFound, Message: fmt.Sprintf("unknown method '%s'", method)}
+	}
+}
+
+func (s *Server) listSessions() ([]session.Metadata, error) {
+	sessions, err := s.store.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	slices.SortFunc(sessions, func(a, b *session.Session) int {
+		return b.Metadata.LastAccessed.Compare(a.Metadata.LastAccessed)
+	})
+	list := make([]session.Metadata, len(sessions))
+	for i, sess := range sessions {
+		list[i] = sess.Metadata
+	}
+	return list, nil
+}
+
+func (s *Server) createSession(name, sessionContext, model, effort string) (session.Metadata, error) {
+	if name == "" {
+		return session.Metadata{}, &rpcError{Code: codeInvalidParams, Message: "name is required"}
+	}
+	opts := app.CreateOptions{Context: sessionContext}
+	if model != "" || effort != "" {
+		opts.Settings = &session.Settings{Model: model, EffortLevel: effort}
+	}
+	sess, err := app.CreateSession(s.root, s.store, name, opts)
+	if err != nil {
+		return session.Metadata{}, err
+	}
+	return sess.Metadata, nil
+}
+
+// launch is how a client starts Claude Code on a session: Claude Code needs
+// a terminal, which the daemon's stdio is not.
+type launch struct {
+	Command []string `json:"command"`
+	Cwd     string   `json:"cwd"`
+}
+
+func (s *Server) resumeSession(name string, args []string) (launch, error) {
+	if name == "" {
+		return launch{}, &rpcError{Code: codeInvalidParams, Message: "name is required"}
+	}
+	if !s.store.Exists(name) {
+		return launch{}, errs.NotFound("session '%s' not found", name)
+	}
+	command := []string{s.Executable, "resume", name}
+	if len(args) > 0 {
+		command = append(append(command, "--"), args...)
+	}
+	return launch{Command: command, Cwd: config.ProjectRootOf(s.root)}, nil
+}
+
+// watchEvents starts sending an "event" notification for every event logged
+// from now on. Watching again is a no-op.
+func (s *Server) watchEvents(ctx context.Context) (map[string]bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.watching {
+		return map[string]bool{"watching": true}, nil
+	}
+
+	path := events.LogPath(s.root)
+	var offset int64
+	if info, err := os.Stat(path); err == nil {
+		offset = info.Size()
+	}
+
+	s.watching = true
+	s.watchers.Add(1)
+	go func() {
+		defer s.watchers.Done()
+		_ = events.Follow(ctx, path, offset, eventWriter{s}, s.PollInterval)
+	}()
+	return map[string]bool{"watching": true}, nil
+}
+
+// eventWriter turns the event log lines events.Follow copies into
+// notifications.
+type eventWriter struct{ s *Server }
+
+func (w eventWriter) Write(p []byte) (int, error) {
+	for line := range bytes.Lines(p) {
+		line = bytes.TrimSpace(line)
+		if !json.Valid(line) {
+			continue
+		}
+		if err := w.s.send(notification{JSONRPC: "2.0", Method: "event", Params: json.RawMessage(line)}); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+func (s *Server) send(msg any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.enc.Encode(msg)
+}
+
+func decodeParams(params json.RawMessage, v any) error {
+	if len(params) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(params, v); err != nil {
+		return &rpcError{Code: codeInvalidParams, Message: fmt.Sprintf("invalid params: %v", err)}
+	}
+	return nil
+}
+
+func toRPCError(err error) *rpcError {
+	var rpcErr *rpcError
+	if errors.As(err, &rpcErr) {
+		return rpcErr
+	}
+	return &rpcError{Code: codeSessionError, Message: err.Error(), Data: map[string]int{"exitCode": errs.ExitCode(err)}}
+}
+
+func idOrNull(id json.RawMessage) json.RawMessage {
+	if id == nil {
+		return json.RawMessage("null")
+	}
+	return id
+}
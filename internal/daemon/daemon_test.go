@@ -0,0 +1,161 @@
+package daemon_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/daemon"
+	"github.com/fgrehm/clotilde/internal/events"
+	"github.com/fgrehm/clotilde/internal/session"
+)
+
+// message is any line the daemon writes.
+type message struct {
+	ID     *int            `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int            `json:"code"`
+		Message string         `json:"message"`
+		Data    map[string]int `json:"data"`
+	} `json:"error"`
+}
+
+var _ = Describe("Server", func() {
+	var (
+		tempDir      string
+		clotildeRoot string
+		store        session.Store
+		requests     *io.PipeWriter
+		messages     chan message
+		done         chan error
+	)
+
+	BeforeEach(func() {
+		tempDir = GinkgoT().TempDir()
+		GinkgoT().Setenv("XDG_CONFIG_HOME", filepath.Join(tempDir, "xdg"))
+		Expect(config.EnsureClotildeStructure(tempDir)).To(Succeed())
+		clotildeRoot = filepath.Join(tempDir, config.ClotildeDir)
+		store = session.NewFileStore(clotildeRoot)
+
+		server := daemon.New(clotildeRoot)
+		server.PollInterval = 10 * time.Millisecond
+		server.Executable = "clotilde"
+
+		in, inW := io.Pipe()
+		outR, out := io.Pipe()
+		requests = inW
+		messages = make(chan message, 10)
+		done = make(chan error, 1)
+		go func() {
+			done <- server.Serve(context.Background(), in, out)
+			_ = out.Close()
+		}()
+		go func() {
+			defer GinkgoRecover()
+			scanner := bufio.NewScanner(outR)
+			for scanner.Scan() {
+				var msg message
+				Expect(json.Unmarshal(scanner.Bytes(), &msg)).To(Succeed())
+				messages <- msg
+			}
+		}()
+	})
+
+	AfterEach(func() {
+		_ = requests.Close()
+		Eventually(done, "2s").Should(Receive(BeNil()))
+	})
+
+	send := func(line string) {
+		_, err := io.WriteString(requests, line+"\n")
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	call := func(method, params string) message {
+		send(`{"jsonrpc":"2.0","id":1,"method":"` + method + `","params":` + params + `}`)
+		var msg message
+		Eventually(messages, "2s").Should(Receive(&msg))
+		Expect(msg.ID).NotTo(BeNil())
+		return msg
+	}
+
+	It("creates and lists sessions", func() {
+		msg := call("createSession", `{"name":"api-work","context":"GH-123","model":"sonnet"}`)
+		Expect(msg.Error).To(BeNil())
+		var created session.Metadata
+		Expect(json.Unmarshal(msg.Result, &created)).To(Succeed())
+		Expect(created.Name).To(Equal("api-work"))
+		Expect(created.PendingLaunch).To(BeTrue())
+
+		settings, err := store.LoadSettings("api-work")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(settings.Model).To(Equal("sonnet"))
+
+		msg = call("listSessions", `{}`)
+		var list []session.Metadata
+		Expect(json.Unmarshal(msg.Result, &list)).To(Succeed())
+		Expect(list).To(HaveLen(1))
+		Expect(list[0].Context).To(Equal("GH-123"))
+	})
+
+	It("reports session errors with the CLI's exit code", func() {
+		Expect(store.Create(session.NewSession("api-work", "uuid-1"))).To(Succeed())
+
+		msg := call("createSession", `{"name":"api-work"}`)
+		Expect(msg.Error).NotTo(BeNil())
+		Expect(msg.Error.Code).To(Equal(-32000))
+		Expect(msg.Error.Data["exitCode"]).To(Equal(4))
+
+		msg = call("resumeSession", `{"name":"missing"}`)
+		Expect(msg.Error.Data["exitCode"]).To(Equal(3))
+	})
+
+	It("tells the client how to resume a session", func() {
+		Expect(store.Create(session.NewSession("api-work", "uuid-1"))).To(Succeed())
+
+		msg := call("resumeSession", `{"name":"api-work","args":["--debug"]}`)
+		Expect(msg.Error).To(BeNil())
+		var launch struct {
+			Command []string `json:"command"`
+			Cwd     string   `json:"cwd"`
+		}
+		Expect(json.Unmarshal(msg.Result, &launch)).To(Succeed())
+		Expect(launch.Command).To(Equal([]string{"clotilde", "resume", "api-work", "--", "--debug"}))
+		Expect(launch.Cwd).To(Equal(tempDir))
+	})
+
+	It("rejects unknown methods and malformed lines", func() {
+		msg := call("explode", `{}`)
+		Expect(msg.Error.Code).To(Equal(-32601))
+
+		send(`not json`)
+		Eventually(messages, "2s").Should(Receive(&msg))
+		Expect(msg.Error.Code).To(Equal(-32700))
+	})
+
+	It("sends new events as notifications", func() {
+		events.Record(clotildeRoot, events.Event{Type: events.SessionCreated, Session: "before"})
+
+		msg := call("watchEvents", `{}`)
+		Expect(string(msg.Result)).To(MatchJSON(`{"watching":true}`))
+
+		events.Record(clotildeRoot, events.Event{Type: events.SessionDeleted, Session: "after"})
+		Eventually(messages, "2s").Should(Receive(&msg))
+		Expect(msg.ID).To(BeNil())
+		Expect(msg.Method).To(Equal("event"))
+		var event events.Event
+		Expect(json.Unmarshal(msg.Params, &event)).To(Succeed())
+		Expect(event.Session).To(Equal("after"))
+		Expect(event.Type).To(Equal(events.SessionDeleted))
+	})
+})
@@ -0,0 +1,82 @@
+// Package errs defines the kinds of failure clotilde reports through its exit
+// code, so scripts can branch on $? instead of matching error text.
+package errs
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Kind classifies an error.
+type Kind int
+
+const (
+	KindNotFound      Kind = iota + 1 // A session (or profile) doesn't exist
+	KindAlreadyExists                 // A session with that name exists
+	KindNotInProject                  // No .claude/clotilde found for the project
+	KindClaudeFailed                  // Claude Code couldn't start or exited with an error
+)
+
+// Exit codes for each kind. 1 is any other error; 2 is left out because
+// shells use it for usage errors.
+const (
+	ExitError         = 1
+	ExitNotFound      = 3
+	ExitAlreadyExists = 4
+	ExitNotInProject  = 5
+	ExitClaudeFailed  = 6
+)
+
+// Error is an error of a known kind. Its message is that of the wrapped error.
+type Error struct {
+	Kind Kind
+	Err  error
+}
+
+func (e *Error) Error() string { return e.Err.Error() }
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// NotFound formats an error of kind KindNotFound, as fmt.Errorf does.
+func NotFound(format string, a ...any) error {
+	return &Error{Kind: KindNotFound, Err: fmt.Errorf(format, a...)}
+}
+
+// AlreadyExists formats an error of kind KindAlreadyExists, as fmt.Errorf does.
+func AlreadyExists(format string, a ...any) error {
+	return &Error{Kind: KindAlreadyExists, Err: fmt.Errorf(format, a...)}
+}
+
+// NotInProject formats an error of kind KindNotInProject, as fmt.Errorf does.
+func NotInProject(format string, a ...any) error {
+	return &Error{Kind: KindNotInProject, Err: fmt.Errorf(format, a...)}
+}
+
+// ClaudeFailed formats an error of kind KindClaudeFailed, as fmt.Errorf does.
+func ClaudeFailed(format string, a ...any) error {
+	return &Error{Kind: KindClaudeFailed, Err: fmt.Errorf(format, a...)}
+}
+
+// ExitCode returns the process exit code for err: 0 for nil, the kind's code
+// for the outermost typed error, and ExitError otherwise.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var e *Error
+	if !errors.As(err, &e) {
+		return ExitError
+	}
+	switch e.Kind {
+	case KindNotFound:
+		return ExitNotFound
+	case KindAlreadyExists:
+		return ExitAlreadyExists
+	case KindNotInProject:
+		return ExitNotInProject
+	case KindClaudeFailed:
+		return ExitClaudeFailed
+	default:
+		return ExitError
+	}
+}
@@ -0,0 +1,37 @@
+package errs_test
+
+import (
+	"errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/errs"
+)
+
+var _ = Describe("Errs", func() {
+	It("keeps the formatted message", func() {
+		err := errs.NotFound("session '%s' not found", "auth")
+		Expect(err).To(MatchError("session 'auth' not found"))
+	})
+
+	It("unwraps to the error passed with %w", func() {
+		cause := errors.New("exit status 2")
+		err := errs.ClaudeFailed("claude failed: %w", cause)
+		Expect(errors.Is(err, cause)).To(BeTrue())
+	})
+
+	DescribeTable("maps kinds to exit codes",
+		func(err error, code int) {
+			Expect(errs.ExitCode(err)).To(Equal(code))
+		},
+		Entry("nil", nil, 0),
+		Entry("untyped", errors.New("boom"), errs.ExitError),
+		Entry("not found", errs.NotFound("x"), errs.ExitNotFound),
+		Entry("already exists", errs.AlreadyExists("x"), errs.ExitAlreadyExists),
+		Entry("not in project", errs.NotInProject("x"), errs.ExitNotInProject),
+		Entry("claude failed", errs.ClaudeFailed("x"), errs.ExitClaudeFailed),
+		Entry("wrapped", fmt.Errorf("failed to load session: %w", errs.NotFound("x")), errs.ExitNotFound),
+	)
+})
@@ -0,0 +1,165 @@
+// Package events appends clotilde's activity (sessions created, resumed,
+// forked and deleted, status and settings changes, hooks fired) to a newline-delimited JSON log, so editor
+// extensions and status bars can follow it instead of polling the store.
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fgrehm/clotilde/internal/config"
+)
+
+// Event types.
+const (
+	SessionCreated  = "session.created"
+	SessionForked   = "session.forked"
+	SessionResumed  = "session.resumed"
+	SessionDeleted  = "session.deleted"
+	SessionStatus   = "session.status"
+	SessionSettings = "session.settings"
+	HookFired       = "hook.fired"
+)
+
+// LogFile is the event log, kept with the (git-ignored) session folders. It
+// is rotated to LogFile+".1" once it reaches maxLogSize.
+const LogFile = ".events.jsonl"
+
+const maxLogSize = 1024 * 1024
+
+// Event is one line of the log.
+type Event struct {
+	Time      time.Time `json:"time"`
+	Type      string    `json:"type"`
+	Session   string    `json:"session,omitempty"`
+	SessionID string    `json:"sessionId,omitempty"`
+	Parent    string    `json:"parent,omitempty"` // session.forked: the session forked from
+	Hook      string    `json:"hook,omitempty"`   // hook.fired: the hook event, e.g. SessionStart
+	Source    string    `json:"source,omitempty"` // hook.fired: startup, resume, compact or clear
+	Status    string    `json:"status,omitempty"` // session.status: the new lifecycle status
+	From      string    `json:"from,omitempty"`   // session.status: the previous lifecycle status
+}
+
+// LogPath returns the project's event log.
+func LogPath(clotildeRoot string) string {
+	return filepath.Join(config.GetSessionsDir(clotildeRoot), LogFile)
+}
+
+// Record appends e to the project's event log, setting its time if unset.
+// Failures are ignored: the log is informational and must never break a
+// command or hook.
+func Record(clotildeRoot string, e Event) {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+
+	path := LogPath(clotildeRoot)
+	if info, err := os.Stat(path); err == nil && info.Size() >= maxLogSize {
+		_ = os.Rename(path, path+".1")
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return
+	}
+	defer func() { _ = f.Close() }()
+	// One write per event, so concurrent appends don't interleave
+	_, _ = f.Write(append(data, '\n'))
+}
+
+// Read returns the events of the project's log, oldest first, including the
+// rotated one. Lines that don't parse are skipped; a missing log is empty.
+func Read(clotildeRoot string) ([]Event, error) {
+	var recorded []Event
+	path := LogPath(clotildeRoot)
+	for _, p := range []string{path + ".1", path} {
+		f, err := os.Open(p)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			var e Event
+			if json.Unmarshal(scanner.Bytes(), &e) == nil {
+				recorded = append(recorded, e)
+			}
+		}
+		err = scanner.Err()
+		_ = f.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return recorded, nil
+}
+
+// Follow copies complete lines appended to the log at path after offset to
+// w, checking every interval until ctx is done. A rotated log is read again
+// from its start.
+func Follow(ctx context.Context, path string, offset int64, w io.Writer, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		info, err := os.Stat(path)
+		switch {
+		case err == nil && info.Size() < offset:
+			offset = 0
+			fallthrough
+		case err == nil && info.Size() > offset:
+			n, err := copyLines(path, offset, w)
+			if err != nil {
+				return err
+			}
+			offset += n
+		case err != nil && !os.IsNotExist(err):
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// copyLines writes the complete lines of the file after offset to w and
+// returns how many bytes it consumed. A partly written last line is left for
+// the next call.
+func copyLines(path string, offset int64, w io.Writer) (int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = f.Close() }()
+
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return 0, err
+	}
+	end := bytes.LastIndexByte(data, '\n') + 1
+	if end == 0 {
+		return 0, nil
+	}
+	if _, err := w.Write(data[:end]); err != nil {
+		return 0, err
+	}
+	return int64(end), nil
+}
@@ -0,0 +1,137 @@
+package events_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/events"
+)
+
+var _ = Describe("Events", func() {
+	var clotildeRoot string
+
+	BeforeEach(func() {
+		clotildeRoot = filepath.Join(GinkgoT().TempDir(), config.ClotildeDir)
+		Expect(os.MkdirAll(config.GetSessionsDir(clotildeRoot), 0o755)).To(Succeed())
+	})
+
+	readEvents := func() []events.Event {
+		f, err := os.Open(events.LogPath(clotildeRoot))
+		Expect(err).NotTo(HaveOccurred())
+		defer func() { _ = f.Close() }()
+
+		var recorded []events.Event
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			var e events.Event
+			Expect(json.Unmarshal(scanner.Bytes(), &e)).To(Succeed())
+			recorded = append(recorded, e)
+		}
+		return recorded
+	}
+
+	Describe("Record", func() {
+		It("appends one JSON line per event and sets the time", func() {
+			events.Record(clotildeRoot, events.Event{Type: events.SessionCreated, Session: "auth", SessionID: "uuid-1"})
+			events.Record(clotildeRoot, events.Event{Type: events.SessionForked, Session: "auth-2", Parent: "auth"})
+
+			recorded := readEvents()
+			Expect(recorded).To(HaveLen(2))
+			Expect(recorded[0].Type).To(Equal(events.SessionCreated))
+			Expect(recorded[0].SessionID).To(Equal("uuid-1"))
+			Expect(recorded[0].Time).To(BeTemporally("~", time.Now(), time.Minute))
+			Expect(recorded[1].Parent).To(Equal("auth"))
+		})
+
+		It("omits empty fields", func() {
+			events.Record(clotildeRoot, events.Event{Type: events.SessionDeleted, Session: "auth"})
+
+			data, err := os.ReadFile(events.LogPath(clotildeRoot))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).NotTo(ContainSubstring("parent"))
+			Expect(string(data)).NotTo(ContainSubstring("sessionId"))
+		})
+
+		It("rotates a full log", func() {
+			path := events.LogPath(clotildeRoot)
+			Expect(os.WriteFile(path, make([]byte, 1024*1024), 0o644)).To(Succeed())
+
+			events.Record(clotildeRoot, events.Event{Type: events.SessionCreated, Session: "auth"})
+
+			Expect(path + ".1").To(BeAnExistingFile())
+			Expect(readEvents()).To(HaveLen(1))
+		})
+	})
+
+	Describe("Read", func() {
+		It("returns rotated events first and skips malformed lines", func() {
+			path := events.LogPath(clotildeRoot)
+			Expect(os.WriteFile(path+".1", []byte(`{"type":"session.created","session":"auth"}`+"\nnot json\n"), 0o644)).To(Succeed())
+			events.Record(clotildeRoot, events.Event{Type: events.SessionResumed, Session: "auth"})
+
+			recorded, err := events.Read(clotildeRoot)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorded).To(HaveLen(2))
+			Expect(recorded[0].Type).To(Equal(events.SessionCreated))
+			Expect(recorded[1].Type).To(Equal(events.SessionResumed))
+		})
+
+		It("returns nothing without a log", func() {
+			recorded, err := events.Read(clotildeRoot)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorded).To(BeEmpty())
+		})
+	})
+
+	Describe("Follow", func() {
+		It("prints lines appended after the offset until cancelled", func() {
+			path := events.LogPath(clotildeRoot)
+			Expect(os.WriteFile(path, []byte("{\"old\":true}\n"), 0o644)).To(Succeed())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			out := gbytes.NewBuffer()
+			done := make(chan error)
+			go func() { done <- events.Follow(ctx, path, int64(len("{\"old\":true}\n")), out, 5*time.Millisecond) }()
+
+			events.Record(clotildeRoot, events.Event{Type: events.SessionResumed, Session: "auth"})
+			Eventually(out).Should(gbytes.Say(`"type":"session.resumed"`))
+
+			// A partial line waits for its newline
+			f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+			Expect(err).NotTo(HaveOccurred())
+			_, _ = f.WriteString(`{"type":"hook`)
+			Consistently(out, 50*time.Millisecond).ShouldNot(gbytes.Say("hook"))
+			_, _ = f.WriteString(".fired\"}\n")
+			_ = f.Close()
+			Eventually(out).Should(gbytes.Say(`"type":"hook.fired"`))
+
+			cancel()
+			Eventually(done).Should(Receive(BeNil()))
+			Expect(strings.Contains(string(out.Contents()), "old")).To(BeFalse())
+		})
+
+		It("starts over when the log is rotated", func() {
+			path := events.LogPath(clotildeRoot)
+			Expect(os.WriteFile(path, []byte(strings.Repeat("x", 100)+"\n"), 0o644)).To(Succeed())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			out := gbytes.NewBuffer()
+			go func() { _ = events.Follow(ctx, path, 101, out, 5*time.Millisecond) }()
+
+			Expect(os.Rename(path, path+".1")).To(Succeed())
+			events.Record(clotildeRoot, events.Event{Type: events.SessionDeleted, Session: "auth"})
+			Eventually(out).Should(gbytes.Say(`"type":"session.deleted"`))
+		})
+	})
+})
@@ -0,0 +1,123 @@
+package export
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Placeholders Anonymize puts in place of what it strips.
+const (
+	AnonymizedSecret  = "[SECRET]"
+	AnonymizedEmail   = "[EMAIL]"
+	AnonymizedProject = "[PROJECT]"
+	AnonymizedHome    = "~"
+	AnonymizedUserDir = "/home/user"
+)
+
+// secretPatterns match well-known API key and token formats. They're
+// heuristics: anything else secret needs a user-provided pattern.
+var secretPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`\bsk-ant-[A-Za-z0-9_-]{10,}`),                               // Anthropic
+	regexp.MustCompile(`\bsk-(proj-)?[A-Za-z0-9_-]{20,}`),                           // OpenAI and similar
+	regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{20,}`),                              // GitHub tokens
+	regexp.MustCompile(`\bgithub_pat_[A-Za-z0-9_]{20,}`),                            // GitHub fine-grained tokens
+	regexp.MustCompile(`\bglpat-[A-Za-z0-9_-]{20,}`),                                // GitLab
+	regexp.MustCompile(`\b(A3T[A-Z0-9]|AKIA|ASIA)[A-Z0-9]{16}\b`),                   // AWS access key IDs
+	regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}`),                           // Slack
+	regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}`),                                   // Google
+	regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]+`), // JWTs
+	regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`),
+}
+
+// labeledSecretPattern matches long values assigned to secret-sounding names
+// (token=..., "apiKey": "...", Bearer ...), keeping the name.
+var labeledSecretPattern = regexp.MustCompile(`(?i)\b(bearer|token|api[_-]?key|secret|password)(["']?\s*[:=]\s*["']?|\s+)[A-Za-z0-9._~+/=-]{16,}`)
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}`)
+
+	// userDirPattern matches home directories of any user, for paths outside
+	// the exporting user's home
+	userDirPattern = regexp.MustCompile(`(/home|/Users)/[^/\s"'\\]+`)
+)
+
+// Anonymizer strips what identifies a user or machine from transcript text:
+// API keys and tokens, email addresses, and file paths, which are rewritten
+// relative to the project ([PROJECT]) or home directory (~).
+type Anonymizer struct {
+	dirs []dirReplacement
+}
+
+// dirReplacement replaces a directory in paths, but not longer names that
+// start with it (/src/app must not turn /src/app-old into [PROJECT]-old).
+type dirReplacement struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// NewAnonymizer returns an Anonymizer for a project at projectRoot, exported
+// by a user whose home is homeDir. Either may be empty.
+func NewAnonymizer(projectRoot, homeDir string) *Anonymizer {
+	a := &Anonymizer{}
+	// The project usually lives under home, so it goes first
+	for _, dir := range []struct{ path, replacement string }{
+		{projectRoot, AnonymizedProject},
+		{homeDir, AnonymizedHome},
+	} {
+		if path := cleanRoot(dir.path); path != "" {
+			a.dirs = append(a.dirs, dirReplacement{
+				pattern:     regexp.MustCompile(regexp.QuoteMeta(path) + `([^\w.-]|$)`),
+				replacement: dir.replacement + "${1}",
+			})
+		}
+	}
+
+	// Claude Code names a project's folder after its encoded path
+	// (/home/jane/src/app becomes -home-jane-src-app). Other projects under
+	// home share its prefix, followed by a dash.
+	for _, dir := range []struct{ path, replacement, next string }{
+		{projectRoot, AnonymizedProject, `[^\w.-]`},
+		{homeDir, encodeProjectPath(AnonymizedUserDir), `[^\w.]`},
+	} {
+		if path := cleanRoot(dir.path); path != "" {
+			a.dirs = append(a.dirs, dirReplacement{
+				pattern:     regexp.MustCompile(`(^|[^\w.-])` + regexp.QuoteMeta(encodeProjectPath(path)) + `(` + dir.next + `|$)`),
+				replacement: "${1}" + dir.replacement + "${2}",
+			})
+		}
+	}
+	return a
+}
+
+// encodeProjectPath converts a path the way Claude Code names project
+// folders, like claude.EncodeProjectPath.
+func encodeProjectPath(path string) string {
+	return strings.NewReplacer("/", "-", ".", "-").Replace(path)
+}
+
+// Anonymize returns text with secrets, emails and paths replaced.
+func (a *Anonymizer) Anonymize(text string) string {
+	for _, re := range secretPatterns {
+		text = re.ReplaceAllLiteralString(text, AnonymizedSecret)
+	}
+	text = labeledSecretPattern.ReplaceAllString(text, "${1}${2}"+AnonymizedSecret)
+	text = emailPattern.ReplaceAllLiteralString(text, AnonymizedEmail)
+	for _, dir := range a.dirs {
+		text = dir.pattern.ReplaceAllString(text, dir.replacement)
+	}
+	return userDirPattern.ReplaceAllLiteralString(text, AnonymizedUserDir)
+}
+
+// cleanRoot returns dir without a trailing separator, or "" for the
+// filesystem root, which would match every path.
+func cleanRoot(dir string) string {
+	if dir == "" {
+		return ""
+	}
+	dir = filepath.Clean(dir)
+	if dir == string(filepath.Separator) || dir == "." {
+		return ""
+	}
+	return dir
+}
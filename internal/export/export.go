@@ -27,9 +27,22 @@ type ExportData struct {
 }
 
 // FilterTranscript reads JSONL from r and returns only user and assistant entries as raw JSON.
+func FilterTranscript(r io.Reader) ([]json.RawMessage, error) {
+	return readEntries(r, func(entryType string) bool {
+		return entryType == "user" || entryType == "assistant"
+	})
+}
+
+// ReadTranscript reads JSONL from r and returns every entry as raw JSON,
+// skipping malformed lines.
+func ReadTranscript(r io.Reader) ([]json.RawMessage, error) {
+	return readEntries(r, func(string) bool { return true })
+}
+
+// readEntries returns the entries of the JSONL in r whose type keep accepts.
 // Uses bufio.Reader instead of bufio.Scanner to handle arbitrarily long lines
 // (Claude transcripts can contain tool output blocks larger than 1MB).
-func FilterTranscript(r io.Reader) ([]json.RawMessage, error) {
+func readEntries(r io.Reader, keep func(entryType string) bool) ([]json.RawMessage, error) {
 	reader := bufio.NewReader(r)
 
 	var entries []json.RawMessage
@@ -42,7 +55,7 @@ func FilterTranscript(r io.Reader) ([]json.RawMessage, error) {
 			if len(line) > 0 {
 				var entry transcriptEntry
 				if jsonErr := json.Unmarshal(line, &entry); jsonErr == nil {
-					if entry.Type == "user" || entry.Type == "assistant" {
+					if keep(entry.Type) {
 						raw := make(json.RawMessage, len(line))
 						copy(raw, line)
 						entries = append(entries, raw)
@@ -65,6 +78,40 @@ func FilterTranscript(r io.Reader) ([]json.RawMessage, error) {
 	return entries, nil
 }
 
+// RedactEntries applies redact to every string in entries (message text, tool
+// inputs and results), leaving the JSON structure intact.
+func RedactEntries(entries []json.RawMessage, redact func(string) string) ([]json.RawMessage, error) {
+	redacted := make([]json.RawMessage, 0, len(entries))
+	for _, entry := range entries {
+		var value any
+		if err := json.Unmarshal(entry, &value); err != nil {
+			return nil, fmt.Errorf("redacting entry: %w", err)
+		}
+		raw, err := json.Marshal(redactValue(value, redact))
+		if err != nil {
+			return nil, fmt.Errorf("redacting entry: %w", err)
+		}
+		redacted = append(redacted, raw)
+	}
+	return redacted, nil
+}
+
+func redactValue(value any, redact func(string) string) any {
+	switch v := value.(type) {
+	case string:
+		return redact(v)
+	case []any:
+		for i := range v {
+			v[i] = redactValue(v[i], redact)
+		}
+	case map[string]any:
+		for k := range v {
+			v[k] = redactValue(v[k], redact)
+		}
+	}
+	return value
+}
+
 // BuildHTML assembles a self-contained HTML file from the session name and filtered entries.
 func BuildHTML(sessionName string, entries []json.RawMessage) (string, error) {
 	if entries == nil {
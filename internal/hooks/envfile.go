@@ -0,0 +1,137 @@
+package hooks
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/fgrehm/clotilde/internal/util"
+)
+
+// Environment variables the hooks read and write.
+const (
+	// EnvSessionName is set by clotilde on the claude processes it launches.
+	EnvSessionName = "CLOTILDE_SESSION_NAME"
+	// EnvClaudeEnvFile points at the file Claude Code sources into the
+	// environment of later hooks and tools.
+	EnvClaudeEnvFile = "CLAUDE_ENV_FILE"
+	// EnvSession is written to the env file so later hooks (and the
+	// statusline) know the session without EnvSessionName.
+	EnvSession = "CLOTILDE_SESSION"
+	// EnvHookExecuted marks the last event a hook handled, see Claim.
+	EnvHookExecuted = "CLOTILDE_HOOK_EXECUTED"
+)
+
+// EnvFile is Claude Code's CLAUDE_ENV_FILE: KEY=value lines sourced into the
+// environment of later hooks. The zero value stands for an unset
+// CLAUDE_ENV_FILE: reads find nothing and writes do nothing.
+type EnvFile string
+
+// Value returns the last value assigned to key (last wins, as when the file
+// is sourced), or "" if there's none.
+func (f EnvFile) Value(key string) string {
+	if f == "" {
+		return ""
+	}
+	content, err := os.ReadFile(string(f))
+	if err != nil {
+		return ""
+	}
+	return lastEnvValue(string(content), key)
+}
+
+// Set sets KEY=value, replacing any earlier assignment of the key so repeated
+// hooks (compact, clear, resume) don't pile up lines. The read-modify-write
+// runs under a lock because global and project hooks can fire concurrently.
+func (f EnvFile) Set(key, value string) error {
+	return f.edit(func(content string) (string, bool) {
+		return replaceEnvLine(content, key, value), true
+	})
+}
+
+// edit runs a read-modify-write cycle on the file under its lock: edit gets
+// the current content and returns the new one, or false to leave the file
+// alone.
+func (f EnvFile) edit(edit func(content string) (string, bool)) error {
+	if f == "" {
+		return nil
+	}
+	path := string(f)
+	return util.WithFileLock(path, func() error {
+		content, err := os.ReadFile(path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to read CLAUDE_ENV_FILE: %w", err)
+		}
+		updated, ok := edit(string(content))
+		if !ok {
+			return nil
+		}
+		if err := util.WriteFileAtomic(path, []byte(updated)); err != nil {
+			return fmt.Errorf("failed to write to CLAUDE_ENV_FILE: %w", err)
+		}
+		return nil
+	})
+}
+
+// Claim reports whether this invocation should handle the event identified
+// by marker, recording EnvHookExecuted=<marker> in the env file so that a
+// second invocation for the same event (from global + project hooks) is
+// skipped. Claude Code sets the variable after sourcing the env file, so
+// current (the variable's value in the hook's environment) is checked too,
+// in case Claude Code already re-sourced. Checking and recording happen under
+// the file's lock, so of two hooks firing at once exactly one runs.
+func (f EnvFile) Claim(current, marker string) bool {
+	if current == marker {
+		return false
+	}
+	claimed := true
+	_ = f.edit(func(content string) (string, bool) { //nolint:errcheck // without the env file, the hook just runs
+		if lastEnvValue(content, EnvHookExecuted) == marker {
+			claimed = false
+			return "", false
+		}
+		return replaceEnvLine(content, EnvHookExecuted, marker), true
+	})
+	return claimed
+}
+
+// lastEnvValue returns the last value assigned to key in env file content.
+func lastEnvValue(content, key string) string {
+	prefix := key + "="
+	var lastValue string
+	for line := range strings.SplitSeq(content, "\n") {
+		line = strings.TrimSpace(line)
+		if after, ok := strings.CutPrefix(line, prefix); ok {
+			lastValue = after
+		}
+	}
+	return lastValue
+}
+
+// replaceEnvLine returns content with the first KEY=... line set to value and
+// later ones dropped, appending the assignment if the key isn't present.
+// Other lines are kept as-is.
+func replaceEnvLine(content, key, value string) string {
+	prefix := key + "="
+	assignment := prefix + value
+
+	var lines []string
+	replaced := false
+	if content != "" {
+		for line := range strings.SplitSeq(strings.TrimSuffix(content, "\n"), "\n") {
+			switch {
+			case !strings.HasPrefix(strings.TrimSpace(line), prefix):
+				lines = append(lines, line)
+			case !replaced:
+				lines = append(lines, assignment)
+				replaced = true
+			}
+		}
+	}
+	if !replaced {
+		lines = append(lines, assignment)
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
@@ -0,0 +1,59 @@
+package hooks_test
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/hooks"
+)
+
+var _ = Describe("EnvFile", func() {
+	var envFile hooks.EnvFile
+
+	BeforeEach(func() {
+		envFile = hooks.EnvFile(filepath.Join(GinkgoT().TempDir(), "claude-env"))
+	})
+
+	It("replaces earlier assignments of a key, keeping other lines", func() {
+		Expect(os.WriteFile(string(envFile), []byte("CLOTILDE_SESSION=old\nexport FOO=bar\nCLOTILDE_SESSION=older\n"), 0o644)).To(Succeed())
+
+		Expect(envFile.Set(hooks.EnvSession, "work")).To(Succeed())
+		content, err := os.ReadFile(string(envFile))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(Equal("CLOTILDE_SESSION=work\nexport FOO=bar\n"))
+		Expect(envFile.Value(hooks.EnvSession)).To(Equal("work"))
+	})
+
+	It("does nothing when CLAUDE_ENV_FILE isn't set", func() {
+		var unset hooks.EnvFile
+		Expect(unset.Set(hooks.EnvSession, "work")).To(Succeed())
+		Expect(unset.Value(hooks.EnvSession)).To(BeEmpty())
+	})
+
+	Describe("Claim", func() {
+		It("lets exactly one of concurrent hooks handle an event", func() {
+			var claimed atomic.Int32
+			var wg sync.WaitGroup
+			for range 10 {
+				wg.Go(func() {
+					if envFile.Claim("", "uuid-1:startup") {
+						claimed.Add(1)
+					}
+				})
+			}
+			wg.Wait()
+
+			Expect(claimed.Load()).To(BeEquivalentTo(1))
+			Expect(envFile.Claim("", "uuid-1:clear")).To(BeTrue())
+		})
+
+		It("skips an event Claude Code already exported as handled", func() {
+			Expect(envFile.Claim("uuid-1:startup", "uuid-1:startup")).To(BeFalse())
+		})
+	})
+})
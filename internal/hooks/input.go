@@ -0,0 +1,124 @@
+// Package hooks implements clotilde's Claude Code hooks: it decodes the JSON
+// Claude Code passes them and handles the events, returning what the hook
+// command should print.
+//
+// Claude Code adds fields and may rename sources between releases, so the
+// parser is tolerant: unknown fields are ignored, fields can come under the
+// names of any known schema version, and unknown sources are reported rather
+// than rejected, letting a Claude Code update degrade gracefully instead of
+// breaking session tracking.
+package hooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// SessionStart sources.
+const (
+	SourceStartup = "startup"
+	SourceResume  = "resume"
+	SourceCompact = "compact"
+	SourceClear   = "clear"
+)
+
+// Schema versions of the SessionStart input, as detected by
+// ParseSessionStart.
+const (
+	// SchemaV1 has session_id, transcript_path and source.
+	SchemaV1 = 1
+	// SchemaV2 adds hook_event_name and cwd (and, in later releases, fields
+	// such as permission_mode that clotilde doesn't use).
+	SchemaV2 = 2
+)
+
+// fieldNames lists, for each field clotilde reads, the names it's accepted
+// under: the current one first, then spellings a Claude Code release could
+// plausibly switch to.
+var fieldNames = map[string][]string{
+	"session_id":      {"session_id", "sessionId"},
+	"transcript_path": {"transcript_path", "transcriptPath"},
+	"source":          {"source"},
+	"hook_event_name": {"hook_event_name", "hookEventName"},
+	"cwd":             {"cwd"},
+}
+
+// sourceAliases maps source values a Claude Code release could rename a
+// known source to onto the ones clotilde handles.
+var sourceAliases = map[string]string{
+	"resumed": SourceResume,
+}
+
+// SessionStartInput is the SessionStart hook input, normalized.
+type SessionStartInput struct {
+	SessionID      string
+	TranscriptPath string
+	// Source is one of the Source constants, or the value as sent (trimmed
+	// and lowercased) when it's not one clotilde knows.
+	Source    string
+	RawSource string // Source as sent
+	Event     string // hook_event_name; "" before SchemaV2
+	Cwd       string // "" before SchemaV2
+	Schema    int
+}
+
+// KnownSource reports whether Source is one clotilde handles. Callers should
+// treat other sources like a startup and log them.
+func (in *SessionStartInput) KnownSource() bool {
+	switch in.Source {
+	case SourceStartup, SourceResume, SourceCompact, SourceClear:
+		return true
+	}
+	return false
+}
+
+// ParseSessionStart decodes a SessionStart hook input. Only malformed JSON
+// (or a non-object) is an error: missing or mistyped fields are left empty.
+func ParseSessionStart(data []byte) (*SessionStartInput, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse hook input: %w", err)
+	}
+	if raw == nil {
+		return nil, fmt.Errorf("failed to parse hook input: not a JSON object")
+	}
+
+	in := &SessionStartInput{
+		SessionID:      stringField(raw, "session_id"),
+		TranscriptPath: stringField(raw, "transcript_path"),
+		RawSource:      stringField(raw, "source"),
+		Event:          stringField(raw, "hook_event_name"),
+		Cwd:            stringField(raw, "cwd"),
+		Schema:         SchemaV1,
+	}
+	if in.Event != "" || in.Cwd != "" {
+		in.Schema = SchemaV2
+	}
+	in.Source = normalizeSource(in.RawSource)
+	return in, nil
+}
+
+// stringField returns the first of the field's names holding a string.
+func stringField(raw map[string]json.RawMessage, field string) string {
+	for _, name := range fieldNames[field] {
+		value, ok := raw[name]
+		if !ok {
+			continue
+		}
+		var s string
+		if json.Unmarshal(value, &s) == nil {
+			return s
+		}
+	}
+	return ""
+}
+
+// normalizeSource maps a sent source to a Source constant where possible.
+func normalizeSource(source string) string {
+	source = strings.ToLower(strings.TrimSpace(source))
+	if alias, ok := sourceAliases[source]; ok {
+		return alias
+	}
+	return source
+}
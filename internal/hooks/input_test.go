@@ -0,0 +1,88 @@
+package hooks_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/hooks"
+)
+
+const (
+	fixtureID         = "0b2f6c1e-5d0a-4a4e-9a51-8f2d1c7e4b10"
+	fixtureTranscript = "/home/dev/.claude/projects/-home-dev-src-app/0b2f6c1e-5d0a-4a4e-9a51-8f2d1c7e4b10.jsonl"
+)
+
+func parseFixture(name string) *hooks.SessionStartInput {
+	data, err := os.ReadFile(filepath.Join("testdata", "sessionstart", name))
+	Expect(err).NotTo(HaveOccurred())
+	in, err := hooks.ParseSessionStart(data)
+	Expect(err).NotTo(HaveOccurred())
+	return in
+}
+
+var _ = Describe("ParseSessionStart", func() {
+	// The fixtures are hand-written, not captured from Claude Code: v1-* and
+	// v2-* follow the hook input documented for those schema versions (with
+	// made-up IDs and paths), and future-* are the kinds of changes the parser
+	// has to survive.
+	DescribeTable("compatibility with documented and future inputs",
+		func(fixture, source string, schema int, known bool) {
+			in := parseFixture(fixture)
+			Expect(in.Source).To(Equal(source))
+			Expect(in.Schema).To(Equal(schema))
+			Expect(in.KnownSource()).To(Equal(known))
+		},
+		Entry("startup before hook_event_name", "v1-startup.json", hooks.SourceStartup, hooks.SchemaV1, true),
+		Entry("resume", "v2-resume.json", hooks.SourceResume, hooks.SchemaV2, true),
+		Entry("clear", "v2-clear.json", hooks.SourceClear, hooks.SchemaV2, true),
+		Entry("compact", "v2-compact.json", hooks.SourceCompact, hooks.SchemaV2, true),
+		Entry("fields clotilde doesn't use", "v2-extra-fields.json", hooks.SourceStartup, hooks.SchemaV2, true),
+		Entry("renamed fields and source", "future-renamed.json", hooks.SourceResume, hooks.SchemaV2, true),
+		Entry("an unknown source", "future-unknown-source.json", "handoff", hooks.SchemaV2, false),
+		Entry("a mistyped field", "future-mistyped.json", hooks.SourceStartup, hooks.SchemaV2, true),
+	)
+
+	It("parses every fixture", func() {
+		fixtures, err := filepath.Glob(filepath.Join("testdata", "sessionstart", "*.json"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fixtures).NotTo(BeEmpty())
+		for _, fixture := range fixtures {
+			in := parseFixture(filepath.Base(fixture))
+			Expect(in.SessionID).NotTo(BeEmpty(), fixture)
+			Expect(in.RawSource).NotTo(BeEmpty(), fixture)
+		}
+	})
+
+	It("reads every field of the current schema", func() {
+		in := parseFixture("v2-resume.json")
+		Expect(*in).To(Equal(hooks.SessionStartInput{
+			SessionID:      fixtureID,
+			TranscriptPath: fixtureTranscript,
+			Source:         hooks.SourceResume,
+			RawSource:      "resume",
+			Event:          "SessionStart",
+			Cwd:            "/home/dev/src/app",
+			Schema:         hooks.SchemaV2,
+		}))
+	})
+
+	It("accepts renamed fields", func() {
+		in := parseFixture("future-renamed.json")
+		Expect(in.TranscriptPath).To(Equal(fixtureTranscript))
+		Expect(in.RawSource).To(Equal("Resumed"))
+	})
+
+	It("leaves mistyped fields empty", func() {
+		Expect(parseFixture("future-mistyped.json").TranscriptPath).To(BeEmpty())
+	})
+
+	It("rejects input that isn't a JSON object", func() {
+		_, err := hooks.ParseSessionStart([]byte("not json"))
+		Expect(err).To(MatchError(ContainSubstring("failed to parse hook input")))
+		_, err = hooks.ParseSessionStart([]byte("null"))
+		Expect(err).To(MatchError(ContainSubstring("not a JSON object")))
+	})
+})
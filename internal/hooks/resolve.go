@@ -0,0 +1,95 @@
+package hooks
+
+import (
+	"fmt"
+
+	"github.com/fgrehm/clotilde/internal/errs"
+	"github.com/fgrehm/clotilde/internal/session"
+)
+
+// Resolution records each step ExplainSessionName took, for 'why'.
+type Resolution struct {
+	Name  string
+	Steps []ResolutionStep
+}
+
+// ResolutionStep is one level of the fallback: what was checked and found.
+type ResolutionStep struct {
+	Source  string
+	Result  string
+	Matched bool
+}
+
+func (r *Resolution) step(source, result string, matched bool) {
+	r.Steps = append(r.Steps, ResolutionStep{Source: source, Result: result, Matched: matched})
+}
+
+// ExplainSessionName resolves the clotilde session a Claude Code session ID
+// belongs to, recording why each level of the fallback matched or didn't.
+// Levels after the matching one aren't checked:
+//  1. EnvSessionName, set when clotilde launched the claude process.
+//  2. EnvSession in the env file, written by an earlier hook.
+//  3. Reverse UUID lookup in the session store.
+//
+// getenv reads the hook's environment.
+func ExplainSessionName(getenv func(string) string, sessionID string, store session.Store) (Resolution, error) {
+	var r Resolution
+
+	if name := getenv(EnvSessionName); name != "" {
+		r.Name = name
+		r.step(EnvSessionName+" env var", fmt.Sprintf("set to '%s' (clotilde launched this claude process)", name), true)
+		return r, nil
+	}
+	r.step(EnvSessionName+" env var", "not set", false)
+
+	envFile := getenv(EnvClaudeEnvFile)
+	source := EnvSession + " in " + EnvClaudeEnvFile
+	if name := EnvFile(envFile).Value(EnvSession); name != "" {
+		r.Name = name
+		r.step(source, fmt.Sprintf("'%s' (written by an earlier hook to %s)", name, envFile), true)
+		return r, nil
+	}
+	if envFile == "" {
+		r.step(source, EnvClaudeEnvFile+" not set", false)
+	} else {
+		r.step(source, "not found in "+envFile, false)
+	}
+
+	name, previous, err := findSessionByUUID(store, sessionID)
+	source = "UUID lookup in the session store"
+	switch {
+	case err != nil:
+		r.step(source, err.Error(), false)
+		return r, err
+	case previous:
+		r.step(source, fmt.Sprintf("%s is an earlier UUID of '%s' (before /clear or /compact)", sessionID, name), true)
+	default:
+		r.step(source, fmt.Sprintf("%s is the current UUID of '%s'", sessionID, name), true)
+	}
+	r.Name = name
+	return r, nil
+}
+
+// findSessionByUUID searches for a session with the given UUID.
+// Checks both current sessionId and previousSessions; previous reports a
+// match in the latter.
+func findSessionByUUID(store session.Store, uuid string) (name string, previous bool, err error) {
+	sessions, err := store.List()
+	if err != nil {
+		return "", false, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	for _, sess := range sessions {
+		if sess.Metadata.SessionID == uuid {
+			return sess.Name, false, nil
+		}
+	}
+
+	for _, sess := range sessions {
+		if sess.Metadata.HasPreviousSessionID(uuid) {
+			return sess.Name, true, nil
+		}
+	}
+
+	return "", false, errs.NotFound("no session found with UUID %s", uuid)
+}
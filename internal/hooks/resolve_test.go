@@ -0,0 +1,68 @@
+package hooks_test
+
+import (
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/errs"
+	"github.com/fgrehm/clotilde/internal/hooks"
+	"github.com/fgrehm/clotilde/internal/session"
+)
+
+var _ = Describe("ExplainSessionName", func() {
+	var (
+		env    map[string]string
+		getenv func(string) string
+		store  session.Store
+	)
+
+	BeforeEach(func() {
+		env = map[string]string{}
+		getenv = func(key string) string { return env[key] }
+		store = session.NewFileStore(filepath.Join(GinkgoT().TempDir(), "clotilde"))
+
+		sess := session.NewSession("work", "uuid-1")
+		sess.RotateSessionID("uuid-2", session.RotationClear)
+		Expect(store.Create(sess)).To(Succeed())
+	})
+
+	It("prefers the session clotilde launched", func() {
+		env[hooks.EnvSessionName] = "launched"
+
+		r, err := hooks.ExplainSessionName(getenv, "uuid-2", store)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.Name).To(Equal("launched"))
+		Expect(r.Steps).To(HaveLen(1))
+	})
+
+	It("falls back to the name an earlier hook wrote to the env file", func() {
+		env[hooks.EnvClaudeEnvFile] = filepath.Join(GinkgoT().TempDir(), "claude-env")
+		Expect(hooks.EnvFile(env[hooks.EnvClaudeEnvFile]).Set(hooks.EnvSession, "from-env-file")).To(Succeed())
+
+		r, err := hooks.ExplainSessionName(getenv, "uuid-2", store)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.Name).To(Equal("from-env-file"))
+		Expect(r.Steps).To(HaveLen(2))
+		Expect(r.Steps[0].Matched).To(BeFalse())
+	})
+
+	It("looks up current and earlier session IDs", func() {
+		r, err := hooks.ExplainSessionName(getenv, "uuid-1", store)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.Name).To(Equal("work"))
+		Expect(r.Steps[2].Result).To(ContainSubstring("earlier UUID"))
+
+		r, err = hooks.ExplainSessionName(getenv, "uuid-2", store)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.Steps[2].Result).To(ContainSubstring("current UUID"))
+	})
+
+	It("reports unknown session IDs as not found", func() {
+		r, err := hooks.ExplainSessionName(getenv, "uuid-unknown", store)
+		Expect(errs.ExitCode(err)).To(Equal(errs.ExitNotFound))
+		Expect(r.Name).To(BeEmpty())
+		Expect(r.Steps).To(HaveLen(3))
+	})
+})
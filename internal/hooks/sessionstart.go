@@ -0,0 +1,263 @@
+package hooks
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/fgrehm/clotilde/internal/claude"
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/events"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/snippet"
+	"github.com/fgrehm/clotilde/internal/util"
+)
+
+// SessionStart handles SessionStart events for a clotilde project: it
+// registers transcripts, follows /clear and /compact to the new session ID
+// and renders the context injected into the conversation.
+type SessionStart struct {
+	ClotildeRoot string
+	Store        session.Store
+	// Getenv reads the hook's environment (os.Getenv when run by Claude Code).
+	Getenv func(string) string
+}
+
+// SessionStartResult is what handling an event did. The hook command prints
+// Output for Claude Code to pick up and Warnings on stderr.
+type SessionStartResult struct {
+	// Skipped is set when another invocation already handled the event.
+	Skipped bool
+	// Session is the clotilde session the event was applied to, if any.
+	Session string
+	// RotatedFrom is the session ID /clear or /compact moved Session away
+	// from, when this event did the rotation.
+	RotatedFrom string
+	// Output is the session's name and contexts.
+	Output string
+	// Warnings are problems that didn't stop the hook.
+	Warnings []string
+}
+
+func (r *SessionStartResult) warnf(format string, args ...any) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
+// Handle processes a SessionStart event. It never fails: problems are
+// reported as warnings, so a hook can't get in the way of the conversation.
+func (h *SessionStart) Handle(in *SessionStartInput) *SessionStartResult {
+	r := &SessionStartResult{}
+	envFile := EnvFile(h.Getenv(EnvClaudeEnvFile))
+
+	// Guard against double execution (global + per-project hooks, which can
+	// fire concurrently). Scoped to session_id:source so that different
+	// events (e.g. startup vs clear) are not blocked by a previous
+	// invocation's marker.
+	if !envFile.Claim(h.Getenv(EnvHookExecuted), in.SessionID+":"+in.Source) {
+		r.Skipped = true
+		return r
+	}
+
+	resolution, resolveErr := ExplainSessionName(h.Getenv, in.SessionID, h.Store)
+	events.Record(h.ClotildeRoot, events.Event{
+		Type:      events.HookFired,
+		Session:   resolution.Name,
+		SessionID: in.SessionID,
+		Hook:      "SessionStart",
+		Source:    in.Source,
+	})
+
+	// The session's transcripts are about to change (or be rotated)
+	if resolution.Name != "" {
+		if err := claude.InvalidateStatsCache(h.ClotildeRoot, resolution.Name); err != nil {
+			r.warnf("failed to invalidate transcript cache: %v", err)
+		}
+	}
+
+	if in.RawSource != "" && !in.KnownSource() {
+		r.warnf("unknown SessionStart source %q (Claude Code may be newer than clotilde), handling it as a startup", in.RawSource)
+	}
+	switch in.Source {
+	case SourceCompact:
+		// NOTE: Currently Claude Code does NOT create a new UUID for /compact
+		// (only /clear does). This is defensive programming in case Claude
+		// Code's behavior changes in the future.
+		h.rotate(r, envFile, in, resolution.Name, resolveErr, session.RotationCompact)
+	case SourceClear:
+		// Unlike /compact, /clear DOES create a new session UUID, and the old
+		// transcript belongs to a finished conversation, so it is detached
+		// according to the "clear.transcript" config.
+		h.rotate(r, envFile, in, resolution.Name, resolveErr, session.RotationClear)
+	default:
+		// Startup, resume, no source (older Claude Code) or one clotilde
+		// doesn't know
+		h.startOrResume(r, envFile, in)
+	}
+	return r
+}
+
+// startOrResume handles new session startup and session resumption, which
+// only concern sessions clotilde launched.
+func (h *SessionStart) startOrResume(r *SessionStartResult, envFile EnvFile, in *SessionStartInput) {
+	r.Session = h.Getenv(EnvSessionName)
+	if r.Session == "" {
+		return
+	}
+
+	if err := envFile.Set(EnvSession, r.Session); err != nil {
+		r.warnf("failed to write session name to env: %v", err)
+	}
+	if in.TranscriptPath != "" {
+		if err := h.saveTranscriptPath(r.Session, in); err != nil {
+			r.warnf("failed to save transcript path: %v", err)
+		}
+	}
+	h.output(r)
+}
+
+// rotate moves the resolved session to the hook's session ID, recording the
+// superseded ID and the reason in the session's history.
+func (h *SessionStart) rotate(r *SessionStartResult, envFile EnvFile, in *SessionStartInput, sessionName string, resolveErr error, reason string) {
+	if resolveErr != nil {
+		// Might be a non-clotilde session or a first compact without env
+		r.warnf("unable to resolve session name for %s: %v", reason, resolveErr)
+		return
+	}
+	if !h.Store.Exists(sessionName) {
+		r.warnf("session '%s' not found", sessionName)
+		return
+	}
+	r.Session = sessionName
+
+	// The hook's session ID is the idempotency key: a rotation already
+	// applied by a concurrent hook, or a late hook for a superseded ID,
+	// leaves the metadata alone.
+	err := session.UpdateLocked(h.ClotildeRoot, h.Store, sessionName, func(sess *session.Session) bool {
+		if sess.Metadata.SessionID == in.SessionID || sess.Metadata.HasPreviousSessionID(in.SessionID) {
+			return false
+		}
+		r.RotatedFrom = sess.RotateSessionID(in.SessionID, reason)
+		sess.Metadata.TranscriptPath = in.TranscriptPath
+		sess.UpdateLastAccessed()
+		if r.RotatedFrom != "" && reason == session.RotationClear {
+			h.detachClearedTranscript(r, sess)
+		}
+		return true
+	})
+	if err != nil {
+		r.warnf("%v", err)
+	}
+
+	// Persist session name for next operation
+	if err := envFile.Set(EnvSession, sessionName); err != nil {
+		r.warnf("failed to write session name to env: %v", err)
+	}
+	h.output(r)
+}
+
+// detachClearedTranscript applies the "clear.transcript" policy to the
+// transcript of the conversation /clear just ended (the session's latest
+// previous session), and notes what was done in that entry.
+func (h *SessionStart) detachClearedTranscript(r *SessionStartResult, sess *session.Session) {
+	policy, err := config.ClearTranscriptPolicy(h.ClotildeRoot)
+	if err != nil {
+		r.warnf("%v", err)
+		return
+	}
+	if policy == config.ClearKeep {
+		return
+	}
+
+	homeDir, err := util.HomeDir()
+	if err != nil {
+		return
+	}
+	entry := &sess.Metadata.PreviousSessions[len(sess.Metadata.PreviousSessions)-1]
+	oldTranscript := claude.PreviousTranscriptPath(homeDir, h.ClotildeRoot, *entry)
+
+	switch policy {
+	case config.ClearSnapshot:
+		if !util.FileExists(oldTranscript) {
+			return
+		}
+		if err := util.CopyFile(oldTranscript, claude.SnapshotPath(h.ClotildeRoot, sess.Name, entry.SessionID)); err != nil {
+			r.warnf("failed to snapshot transcript: %v", err)
+			return
+		}
+		entry.Detached = session.TranscriptSnapshot
+	case config.ClearPrune:
+		if _, err := claude.DeleteSessionData(h.ClotildeRoot, entry.SessionID, oldTranscript); err != nil {
+			r.warnf("failed to prune transcript: %v", err)
+			return
+		}
+		entry.Detached = session.TranscriptPruned
+	}
+}
+
+// saveTranscriptPath saves the transcript path and updates lastAccessed in a
+// single write. A late hook for a session ID the session has since moved
+// away from (with /clear) is ignored, so it can't point the session back at
+// the old transcript.
+func (h *SessionStart) saveTranscriptPath(sessionName string, in *SessionStartInput) error {
+	return session.UpdateLocked(h.ClotildeRoot, h.Store, sessionName, func(sess *session.Session) bool {
+		if sess.Metadata.HasPreviousSessionID(in.SessionID) {
+			return false
+		}
+		sess.Metadata.TranscriptPath = in.TranscriptPath
+		sess.UpdateLastAccessed()
+		return true
+	})
+}
+
+// output renders the session's name and contexts into r.Output, warning
+// when the contexts had to be left out.
+func (h *SessionStart) output(r *SessionStartResult) {
+	var b strings.Builder
+	if err := WriteContexts(&b, h.ClotildeRoot, h.Store, r.Session); err != nil {
+		r.warnf("session context not injected: %v", err)
+	}
+	r.Output = b.String()
+}
+
+// WriteContexts writes the session name and session context to w. Forks
+// also get their parent's name and current context, unless they opted out,
+// and snippets pending for the launch come last.
+// Contexts are passed through the config's "redact" patterns, and left out
+// (with the error returned) when those can't be loaded.
+func WriteContexts(w io.Writer, clotildeRoot string, store session.Store, sessionName string) error {
+	if sessionName == "" {
+		return nil
+	}
+
+	// Output session name
+	_, _ = fmt.Fprintf(w, "\nSession name: %s\n", sessionName)
+
+	redactor, err := config.LoadRedactor(clotildeRoot)
+	if err != nil {
+		return err
+	}
+
+	// Output session context from metadata
+	sess, err := store.Get(sessionName)
+	if err != nil {
+		return nil
+	}
+	if sess.Metadata.Context != "" {
+		_, _ = fmt.Fprintf(w, "Context: %s\n", redactor.Redact(sess.Metadata.Context))
+	}
+
+	if sess.Metadata.IsForkedSession && sess.Metadata.ParentSession != "" && !sess.Metadata.NoParentContext {
+		_, _ = fmt.Fprintf(w, "Forked from session: %s\n", sess.Metadata.ParentSession)
+		// The parent may have been deleted since; its context may have changed
+		parent, err := store.Get(sess.Metadata.ParentSession)
+		if err == nil && parent.Metadata.Context != "" && parent.Metadata.Context != sess.Metadata.Context {
+			_, _ = fmt.Fprintf(w, "Parent session context: %s\n", redactor.Redact(parent.Metadata.Context))
+		}
+	}
+
+	// Snippets picked with 'resume --snippet' for this launch
+	if pending := snippet.ReadPending(config.GetSessionDir(clotildeRoot, sessionName)); pending != "" {
+		_, _ = io.WriteString(w, redactor.Redact(pending))
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package hooks_test
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/hooks"
+	"github.com/fgrehm/clotilde/internal/session"
+)
+
+var _ = Describe("SessionStart", func() {
+	var (
+		clotildeRoot string
+		envFile      string
+		env          map[string]string
+		store        session.Store
+		handler      *hooks.SessionStart
+	)
+
+	BeforeEach(func() {
+		tempDir := GinkgoT().TempDir()
+		GinkgoT().Setenv("HOME", tempDir)
+		GinkgoT().Setenv("XDG_CONFIG_HOME", filepath.Join(tempDir, "xdg"))
+		clotildeRoot = filepath.Join(tempDir, ".claude", "clotilde")
+		envFile = filepath.Join(tempDir, "claude-env")
+		env = map[string]string{hooks.EnvClaudeEnvFile: envFile}
+		store = session.NewFileStore(clotildeRoot)
+		handler = &hooks.SessionStart{
+			ClotildeRoot: clotildeRoot,
+			Store:        store,
+			Getenv:       func(key string) string { return env[key] },
+		}
+
+		sess := session.NewSession("work", "uuid-1")
+		sess.Metadata.Context = "auth refactor"
+		sess.Metadata.TranscriptPath = "/transcripts/uuid-1.jsonl"
+		Expect(store.Create(sess)).To(Succeed())
+	})
+
+	Describe("startup and resume", func() {
+		It("saves the transcript and outputs the contexts of a session clotilde launched", func() {
+			env[hooks.EnvSessionName] = "work"
+
+			result := handler.Handle(&hooks.SessionStartInput{SessionID: "uuid-1", TranscriptPath: "/transcripts/new.jsonl", Source: hooks.SourceStartup})
+			Expect(result.Skipped).To(BeFalse())
+			Expect(result.Session).To(Equal("work"))
+			Expect(result.Warnings).To(BeEmpty())
+			Expect(result.Output).To(Equal("\nSession name: work\nContext: auth refactor\n"))
+
+			sess, err := store.Get("work")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sess.Metadata.TranscriptPath).To(Equal("/transcripts/new.jsonl"))
+			Expect(hooks.EnvFile(envFile).Value(hooks.EnvSession)).To(Equal("work"))
+		})
+
+		It("leaves sessions clotilde didn't launch alone", func() {
+			result := handler.Handle(&hooks.SessionStartInput{SessionID: "uuid-1", TranscriptPath: "/transcripts/new.jsonl", Source: hooks.SourceResume})
+			Expect(result.Session).To(BeEmpty())
+			Expect(result.Output).To(BeEmpty())
+
+			sess, err := store.Get("work")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sess.Metadata.TranscriptPath).To(Equal("/transcripts/uuid-1.jsonl"))
+		})
+
+		It("handles unknown sources as a startup, with a warning", func() {
+			env[hooks.EnvSessionName] = "work"
+
+			result := handler.Handle(&hooks.SessionStartInput{SessionID: "uuid-1", Source: "rewind", RawSource: "rewind"})
+			Expect(result.Session).To(Equal("work"))
+			Expect(result.Warnings).To(ConsistOf(ContainSubstring(`unknown SessionStart source "rewind"`)))
+		})
+
+		It("skips an event another invocation already handled", func() {
+			env[hooks.EnvSessionName] = "work"
+			in := &hooks.SessionStartInput{SessionID: "uuid-1", Source: hooks.SourceStartup}
+
+			Expect(handler.Handle(in).Skipped).To(BeFalse())
+			second := handler.Handle(in)
+			Expect(second.Skipped).To(BeTrue())
+			Expect(second.Output).To(BeEmpty())
+		})
+	})
+
+	Describe("clear", func() {
+		cleared := &hooks.SessionStartInput{SessionID: "uuid-2", TranscriptPath: "/transcripts/uuid-2.jsonl", Source: hooks.SourceClear}
+
+		It("moves the session found through the env file to the new session ID", func() {
+			Expect(hooks.EnvFile(envFile).Set(hooks.EnvSession, "work")).To(Succeed())
+
+			result := handler.Handle(cleared)
+			Expect(result.Session).To(Equal("work"))
+			Expect(result.RotatedFrom).To(Equal("uuid-1"))
+			Expect(result.Output).To(ContainSubstring("Session name: work"))
+
+			sess, err := store.Get("work")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sess.Metadata.SessionID).To(Equal("uuid-2"))
+			Expect(sess.Metadata.TranscriptPath).To(Equal(cleared.TranscriptPath))
+		})
+
+		It("warns when no session can be resolved", func() {
+			result := handler.Handle(cleared)
+			Expect(result.Session).To(BeEmpty())
+			Expect(result.RotatedFrom).To(BeEmpty())
+			Expect(result.Warnings).To(ConsistOf(ContainSubstring("unable to resolve session name for clear")))
+		})
+
+		It("applies a rotation once when hooks run concurrently", func() {
+			env[hooks.EnvSessionName] = "work"
+			// Every handler claims its own copy of the event
+			delete(env, hooks.EnvClaudeEnvFile)
+
+			var wg sync.WaitGroup
+			for range 10 {
+				wg.Go(func() {
+					Expect(handler.Handle(cleared).Warnings).To(BeEmpty())
+				})
+			}
+			wg.Wait()
+
+			// A late startup hook for the superseded ID must not undo the rotation
+			handler.Handle(&hooks.SessionStartInput{SessionID: "uuid-1", TranscriptPath: "/transcripts/uuid-1.jsonl", Source: hooks.SourceStartup})
+
+			sess, err := store.Get("work")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sess.Metadata.SessionID).To(Equal("uuid-2"))
+			Expect(sess.Metadata.TranscriptPath).To(Equal(cleared.TranscriptPath))
+			Expect(sess.Metadata.PreviousSessions).To(HaveLen(1))
+			Expect(sess.Metadata.PreviousSessions[0].SessionID).To(Equal("uuid-1"))
+		})
+	})
+
+	Describe("WriteContexts", func() {
+		It("adds the parent of a fork and its context", func() {
+			fork := session.NewSession("work-fork", "uuid-fork")
+			fork.Metadata.IsForkedSession = true
+			fork.Metadata.ParentSession = "work"
+			Expect(store.Create(fork)).To(Succeed())
+
+			var out strings.Builder
+			Expect(hooks.WriteContexts(&out, clotildeRoot, store, "work-fork")).To(Succeed())
+			Expect(out.String()).To(Equal("\nSession name: work-fork\nForked from session: work\nParent session context: auth refactor\n"))
+		})
+	})
+})
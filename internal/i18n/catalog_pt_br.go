@@ -0,0 +1,59 @@
+package i18n
+
+// portugueseBR is the Brazilian Portuguese catalog.
+var portugueseBR = map[Message]string{
+	ConfirmCancel:     "Cancelar",
+	ConfirmConfirm:    "Confirmar",
+	ConfirmHelp:       "(s/n, setas para navegar, enter para confirmar)",
+	ConfirmTypedHelp:  "(digite '%s' e pressione enter para confirmar, esc para cancelar)",
+	ConfirmTypePrompt: "Digite %s para confirmar:",
+
+	PlainFallback:        "Não foi possível iniciar a interface interativa (%v), usando texto simples",
+	PlainNothingToSelect: "(nada para selecionar)",
+	PlainSelectPrompt:    "Selecione 1-%d (Enter para cancelar): ",
+	PlainInvalidChoice:   "Opção inválida %q.",
+	PlainConfirmPrompt:   "%s [s/N]: ",
+	PlainTypedPrompt:     "%s Digite '%s' para confirmar: ",
+	PlainYesAnswers:      "s,sim,y,yes",
+	PlainContinue:        "Continuar?",
+	PlainApplyToAll:      "Aplicar a todos os restantes?",
+	SwitcherPrompt:       "Mudar para a sessão:",
+
+	DashboardTitle:               "Painel do Clotilde",
+	DashboardSessions:            "Sessões: %s",
+	DashboardTotal:               "%d no total",
+	DashboardForks:               "%d forks",
+	DashboardIncognito:           "%d anônimas",
+	DashboardExpired:             "%d expiradas",
+	DashboardActivityUnavailable: "Atividade indisponível: %v",
+	DashboardActivityLoading:     "Carregando atividade…",
+	DashboardNoActivity:          "nenhuma atividade",
+	DashboardTimeInClaude:        "%s no Claude",
+	DashboardBusiest:             "Mais usadas: %s",
+	DashboardModels:              "Modelos: %s",
+	DashboardQuickActions:        "Ações Rápidas",
+	DashboardRecentSessions:      "Sessões Recentes",
+	DashboardNoSessions:          "Nenhuma sessão ainda. Comece uma!",
+	DashboardOnlyIncognito:       "Só há sessões anônimas em execução.",
+	DashboardMore:                "...e mais %d",
+
+	MenuStart:      "Nova sessão",
+	MenuStartHint:  "Começar uma nova conversa",
+	MenuQuick:      "Pergunta rápida",
+	MenuQuickHint:  "Sessão anônima descartável, sem nome",
+	MenuResume:     "Retomar sessão",
+	MenuResumeHint: "Continuar uma sessão existente",
+	MenuFork:       "Fork de sessão",
+	MenuForkHint:   "Ramificar a partir de uma sessão existente",
+	MenuList:       "Listar sessões",
+	MenuListHint:   "Ver todas as sessões em uma tabela",
+	MenuDelete:     "Apagar sessão",
+	MenuDeleteHint: "Remover uma sessão",
+	MenuQuit:       "Sair",
+	MenuQuitHint:   "Fechar o painel",
+
+	NoSessionsFound:   "Nenhuma sessão encontrada.",
+	CreateSessionHint: "Crie uma sessão com:",
+	ErrNoSessions:     "nenhuma sessão encontrada (crie uma com 'clotilde start <nome>')",
+	ListHeader:        "Sessões (%d no total):",
+}
@@ -0,0 +1,12 @@
+package i18n
+
+// Catalogs exposes the catalogs to the consistency specs.
+var Catalogs = catalogs
+
+// English exposes the source catalog.
+var English = english
+
+// Language returns the tag of the language T translates to.
+func Language() string {
+	return current
+}
@@ -0,0 +1,98 @@
+// Package i18n translates the messages of clotilde's interactive screens
+// (dashboard, switcher, dialogs and their plain-text fallbacks) and a few
+// messages shared across commands; other output stays English. Messages are
+// identified by the constants in messages.go; the English catalog there is
+// the source every other catalog translates, and messages missing from a
+// translation fall back to it.
+//
+// To add a language, copy catalog_pt_br.go, translate its values (keeping
+// the fmt verbs in the same order) and register the catalog in catalogs.
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Message identifies a translatable message.
+type Message string
+
+// DefaultLanguage is used when no catalog matches the requested language.
+const DefaultLanguage = "en"
+
+// catalogs maps language tags to their messages.
+var catalogs = map[string]map[Message]string{
+	DefaultLanguage: english,
+	"pt-BR":         portugueseBR,
+}
+
+// current is the language T translates to, set once at startup.
+var current = DefaultLanguage
+
+// Languages returns the tags of the available catalogs, sorted.
+func Languages() []string {
+	var tags []string
+	for tag := range catalogs {
+		tags = append(tags, tag)
+	}
+	slices.Sort(tags)
+	return tags
+}
+
+// SetLanguage makes T translate to the catalog matching lang (see Resolve).
+func SetLanguage(lang string) {
+	current = Resolve(lang)
+}
+
+// Detect picks the language from the configured one, or else from the
+// locale environment in POSIX precedence (LC_ALL, LC_MESSAGES, LANG).
+func Detect(configured string) string {
+	for _, lang := range []string{configured, os.Getenv("LC_ALL"), os.Getenv("LC_MESSAGES"), os.Getenv("LANG")} {
+		if lang != "" {
+			return Resolve(lang)
+		}
+	}
+	return DefaultLanguage
+}
+
+// Resolve maps a language tag or locale name (e.g. "pt-BR", "pt_BR.UTF-8",
+// "pt") to the tag of the closest catalog: an exact match, or else one for
+// the same language. Anything else, including "C" and "POSIX", resolves to
+// DefaultLanguage.
+func Resolve(lang string) string {
+	lang, _, _ = strings.Cut(lang, ".") // Encoding
+	lang, _, _ = strings.Cut(lang, "@") // Modifier
+	lang = strings.ReplaceAll(lang, "_", "-")
+	base, _, _ := strings.Cut(lang, "-")
+
+	var sameBase string
+	for _, tag := range Languages() {
+		if strings.EqualFold(tag, lang) {
+			return tag
+		}
+		if tagBase, _, _ := strings.Cut(tag, "-"); sameBase == "" && strings.EqualFold(tagBase, base) {
+			sameBase = tag
+		}
+	}
+	if sameBase != "" {
+		return sameBase
+	}
+	return DefaultLanguage
+}
+
+// T returns the message in the current language, formatted with args when
+// there are any.
+func T(id Message, args ...any) string {
+	text, ok := catalogs[current][id]
+	if !ok {
+		if text, ok = english[id]; !ok {
+			text = string(id)
+		}
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(text, args...)
+	}
+	return text
+}
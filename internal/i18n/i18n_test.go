@@ -0,0 +1,75 @@
+package i18n_test
+
+import (
+	"regexp"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/i18n"
+)
+
+var _ = Describe("i18n", func() {
+	AfterEach(func() {
+		i18n.SetLanguage(i18n.DefaultLanguage)
+	})
+
+	Describe("Resolve", func() {
+		DescribeTable("maps tags and locale names to a catalog",
+			func(lang, want string) {
+				Expect(i18n.Resolve(lang)).To(Equal(want))
+			},
+			Entry("exact tag", "pt-BR", "pt-BR"),
+			Entry("locale name", "pt_BR.UTF-8", "pt-BR"),
+			Entry("case-insensitive", "PT-br", "pt-BR"),
+			Entry("same language", "pt_PT", "pt-BR"),
+			Entry("bare language", "pt", "pt-BR"),
+			Entry("English locale", "en_US.UTF-8", "en"),
+			Entry("C locale", "C", "en"),
+			Entry("unknown language", "de_DE", "en"),
+			Entry("empty", "", "en"),
+		)
+	})
+
+	Describe("Detect", func() {
+		BeforeEach(func() {
+			GinkgoT().Setenv("LC_ALL", "")
+			GinkgoT().Setenv("LC_MESSAGES", "")
+			GinkgoT().Setenv("LANG", "pt_BR.UTF-8")
+		})
+
+		It("prefers the configured language", func() {
+			Expect(i18n.Detect("en")).To(Equal("en"))
+		})
+
+		It("falls back to the locale, LC_ALL first", func() {
+			Expect(i18n.Detect("")).To(Equal("pt-BR"))
+			GinkgoT().Setenv("LC_ALL", "C")
+			Expect(i18n.Detect("")).To(Equal("en"))
+		})
+	})
+
+	Describe("T", func() {
+		It("translates and formats messages", func() {
+			Expect(i18n.T(i18n.DashboardTotal, 3)).To(Equal("3 total"))
+			i18n.SetLanguage("pt_BR")
+			Expect(i18n.Language()).To(Equal("pt-BR"))
+			Expect(i18n.T(i18n.DashboardTotal, 3)).To(Equal("3 no total"))
+		})
+
+		It("falls back to the message ID for unknown messages", func() {
+			Expect(i18n.T("no.such.message")).To(Equal("no.such.message"))
+		})
+	})
+
+	It("has every translation in the source catalog, with the same fmt verbs", func() {
+		verbs := regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+		for lang, catalog := range i18n.Catalogs {
+			for id, text := range catalog {
+				source, ok := i18n.English[id]
+				Expect(ok).To(BeTrue(), "%s: %s isn't in the English catalog", lang, id)
+				Expect(verbs.FindAllString(text, -1)).To(Equal(verbs.FindAllString(source, -1)), "%s: %s", lang, id)
+			}
+		}
+	})
+})
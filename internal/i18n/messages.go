@@ -0,0 +1,126 @@
+package i18n
+
+// Messages shown by the confirmation dialog.
+const (
+	ConfirmCancel     Message = "confirm.cancel"
+	ConfirmConfirm    Message = "confirm.confirm"
+	ConfirmHelp       Message = "confirm.help"
+	ConfirmTypedHelp  Message = "confirm.typed_help"
+	ConfirmTypePrompt Message = "confirm.type_prompt"
+)
+
+// Messages shown by the plain-text fallbacks of the TUIs.
+const (
+	PlainFallback        Message = "plain.fallback"
+	PlainNothingToSelect Message = "plain.nothing_to_select"
+	PlainSelectPrompt    Message = "plain.select_prompt"
+	PlainInvalidChoice   Message = "plain.invalid_choice"
+	PlainConfirmPrompt   Message = "plain.confirm_prompt"
+	PlainTypedPrompt     Message = "plain.typed_prompt"
+	PlainYesAnswers      Message = "plain.yes_answers"
+	PlainContinue        Message = "plain.continue"
+	PlainApplyToAll      Message = "plain.apply_to_all"
+	SwitcherPrompt       Message = "switcher.prompt"
+)
+
+// Messages shown by the dashboard.
+const (
+	DashboardTitle               Message = "dashboard.title"
+	DashboardSessions            Message = "dashboard.sessions"
+	DashboardTotal               Message = "dashboard.total"
+	DashboardForks               Message = "dashboard.forks"
+	DashboardIncognito           Message = "dashboard.incognito"
+	DashboardExpired             Message = "dashboard.expired"
+	DashboardActivityUnavailable Message = "dashboard.activity_unavailable"
+	DashboardActivityLoading     Message = "dashboard.activity_loading"
+	DashboardNoActivity          Message = "dashboard.no_activity"
+	DashboardTimeInClaude        Message = "dashboard.time_in_claude"
+	DashboardBusiest             Message = "dashboard.busiest"
+	DashboardModels              Message = "dashboard.models"
+	DashboardQuickActions        Message = "dashboard.quick_actions"
+	DashboardRecentSessions      Message = "dashboard.recent_sessions"
+	DashboardNoSessions          Message = "dashboard.no_sessions"
+	DashboardOnlyIncognito       Message = "dashboard.only_incognito"
+	DashboardMore                Message = "dashboard.more"
+
+	MenuStart      Message = "menu.start"
+	MenuStartHint  Message = "menu.start.hint"
+	MenuQuick      Message = "menu.quick"
+	MenuQuickHint  Message = "menu.quick.hint"
+	MenuResume     Message = "menu.resume"
+	MenuResumeHint Message = "menu.resume.hint"
+	MenuFork       Message = "menu.fork"
+	MenuForkHint   Message = "menu.fork.hint"
+	MenuList       Message = "menu.list"
+	MenuListHint   Message = "menu.list.hint"
+	MenuDelete     Message = "menu.delete"
+	MenuDeleteHint Message = "menu.delete.hint"
+	MenuQuit       Message = "menu.quit"
+	MenuQuitHint   Message = "menu.quit.hint"
+)
+
+// Messages shown by commands.
+const (
+	NoSessionsFound   Message = "sessions.none_found"
+	CreateSessionHint Message = "sessions.create_hint"
+	ErrNoSessions     Message = "sessions.err_none"
+	ListHeader        Message = "list.header"
+)
+
+// english is the source catalog: every Message must be in it.
+var english = map[Message]string{
+	ConfirmCancel:     "Cancel",
+	ConfirmConfirm:    "Confirm",
+	ConfirmHelp:       "(y/n, arrows to navigate, enter to confirm)",
+	ConfirmTypedHelp:  "(type '%s' and press enter to confirm, esc to cancel)",
+	ConfirmTypePrompt: "Type %s to confirm:",
+
+	PlainFallback:        "Can't start the interactive UI (%v), falling back to plain text",
+	PlainNothingToSelect: "(nothing to select)",
+	PlainSelectPrompt:    "Select 1-%d (Enter to cancel): ",
+	PlainInvalidChoice:   "Invalid choice %q.",
+	PlainConfirmPrompt:   "%s [y/N]: ",
+	PlainTypedPrompt:     "%s Type '%s' to confirm: ",
+	PlainYesAnswers:      "y,yes",
+	PlainContinue:        "Continue?",
+	PlainApplyToAll:      "Apply to all remaining?",
+	SwitcherPrompt:       "Switch to session:",
+
+	DashboardTitle:               "Clotilde Dashboard",
+	DashboardSessions:            "Sessions: %s",
+	DashboardTotal:               "%d total",
+	DashboardForks:               "%d forks",
+	DashboardIncognito:           "%d incognito",
+	DashboardExpired:             "%d expired",
+	DashboardActivityUnavailable: "Activity unavailable: %v",
+	DashboardActivityLoading:     "Loading activity…",
+	DashboardNoActivity:          "no activity",
+	DashboardTimeInClaude:        "%s in Claude",
+	DashboardBusiest:             "Busiest: %s",
+	DashboardModels:              "Models:  %s",
+	DashboardQuickActions:        "Quick Actions",
+	DashboardRecentSessions:      "Recent Sessions",
+	DashboardNoSessions:          "No sessions yet. Start one to get going!",
+	DashboardOnlyIncognito:       "Only incognito sessions are running.",
+	DashboardMore:                "...and %d more",
+
+	MenuStart:      "Start new session",
+	MenuStartHint:  "Create a new conversation",
+	MenuQuick:      "Quick question",
+	MenuQuickHint:  "Throwaway incognito session, no name needed",
+	MenuResume:     "Resume session",
+	MenuResumeHint: "Continue an existing session",
+	MenuFork:       "Fork session",
+	MenuForkHint:   "Branch from an existing session",
+	MenuList:       "List all sessions",
+	MenuListHint:   "View all sessions in a table",
+	MenuDelete:     "Delete session",
+	MenuDeleteHint: "Remove a session",
+	MenuQuit:       "Quit",
+	MenuQuitHint:   "Exit dashboard",
+
+	NoSessionsFound:   "No sessions found.",
+	CreateSessionHint: "Create a session with:",
+	ErrNoSessions:     "no sessions found (create one with 'clotilde start <name>')",
+	ListHeader:        "Sessions (%d total):",
+}
@@ -5,6 +5,11 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/fgrehm/clotilde/internal/claude"
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/crypt"
+	"github.com/fgrehm/clotilde/internal/session"
 )
 
 // OutputStyleType represents the type of output style
@@ -30,27 +35,44 @@ func IsBuiltIn(style string) bool {
 
 // StyleExists checks if a style file exists in standard locations
 func StyleExists(clotildeRoot, styleName string) bool {
-	// Check project level: .claude/output-styles/<name>.md
-	projectPath := filepath.Join(clotildeRoot, "..", "output-styles", styleName+".md")
-	if _, err := os.Stat(projectPath); err == nil {
-		return true
+	return StylePath(clotildeRoot, styleName) != ""
+}
+
+// StylePath returns the file of an existing style, checking the project level
+// (.claude/output-styles/<name>.md) before the user level
+// (~/.claude/output-styles/<name>.md), or "" when there's none. With an empty
+// clotildeRoot (outside a project) only the user level is checked.
+func StylePath(clotildeRoot, styleName string) string {
+	if clotildeRoot != "" {
+		projectPath := filepath.Join(config.ProjectClaudeDir(clotildeRoot), "output-styles", styleName+".md")
+		if _, err := os.Stat(projectPath); err == nil {
+			return projectPath
+		}
 	}
 
-	// Check user level: ~/.claude/output-styles/<name>.md
 	homeDir, err := os.UserHomeDir()
 	if err == nil {
-		userPath := filepath.Join(homeDir, ".claude", "output-styles", styleName+".md")
+		userPath := filepath.Join(claude.ConfigDir(homeDir), "output-styles", styleName+".md")
 		if _, err := os.Stat(userPath); err == nil {
-			return true
+			return userPath
 		}
 	}
 
-	return false
+	return ""
+}
+
+// ReferenceSource returns the provenance of a style referenced by name: built
+// in, or shared with the file it was found in (empty when it doesn't exist).
+func ReferenceSource(clotildeRoot, styleName string) *session.OutputStyleSource {
+	if IsBuiltIn(styleName) {
+		return &session.OutputStyleSource{Kind: session.OutputStyleBuiltin, Name: styleName}
+	}
+	return &session.OutputStyleSource{Kind: session.OutputStyleShared, Name: styleName, Path: StylePath(clotildeRoot, styleName)}
 }
 
 // GetCustomStylePath returns the path to a custom output style file
 func GetCustomStylePath(clotildeRoot, sessionName string) string {
-	return filepath.Join(clotildeRoot, "..", "output-styles", "clotilde", sessionName+".md")
+	return filepath.Join(config.ProjectClaudeDir(clotildeRoot), "output-styles", "clotilde", sessionName+".md")
 }
 
 // GetCustomStyleReference returns the reference string for settings.json
@@ -79,7 +101,7 @@ keep-coding-instructions: true
 `, GetCustomStyleReference(sessionName), sessionName, content)
 
 	// Write file
-	if err := os.WriteFile(stylePath, []byte(fileContent), 0o644); err != nil {
+	if err := crypt.WriteFile(clotildeRoot, stylePath, []byte(fileContent), 0o644); err != nil {
 		return fmt.Errorf("failed to write output style file: %w", err)
 	}
 
@@ -89,7 +111,7 @@ keep-coding-instructions: true
 // CreateCustomStyleFileFromFile creates a custom output style from a file, validating/injecting frontmatter
 func CreateCustomStyleFileFromFile(clotildeRoot, sessionName, sourceFilePath string) error {
 	// Read source file
-	content, err := os.ReadFile(sourceFilePath)
+	content, err := crypt.ReadFile(sourceFilePath)
 	if err != nil {
 		return fmt.Errorf("failed to read source file: %w", err)
 	}
@@ -134,7 +156,7 @@ keep-coding-instructions: true
 		return fmt.Errorf("failed to create output-styles directory: %w", err)
 	}
 
-	if err := os.WriteFile(stylePath, []byte(updatedFrontmatter), 0o644); err != nil {
+	if err := crypt.WriteFile(clotildeRoot, stylePath, []byte(updatedFrontmatter), 0o644); err != nil {
 		return fmt.Errorf("failed to write output style file: %w", err)
 	}
 
@@ -157,3 +179,29 @@ func DeleteCustomStyleFile(clotildeRoot, sessionName string) error {
 
 	return nil
 }
+
+// CustomStyleUsers returns the sessions other than sessionName whose settings
+// reference sessionName's custom style. Renames and manual edits of
+// settings.json can leave several sessions on one file, which must then
+// outlive the session it was created for.
+func CustomStyleUsers(store session.Store, sessionName string) ([]string, error) {
+	sessions, err := store.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	reference := GetCustomStyleReference(sessionName)
+	var users []string
+	for _, sess := range sessions {
+		if sess.Name == sessionName {
+			continue
+		}
+		settings, err := store.LoadSettings(sess.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load settings of '%s': %w", sess.Name, err)
+		}
+		if settings != nil && settings.OutputStyle == reference {
+			users = append(users, sess.Name)
+		}
+	}
+	return users, nil
+}
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/fgrehm/clotilde/internal/outputstyle"
+	"github.com/fgrehm/clotilde/internal/session"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -278,4 +279,63 @@ Content`
 			Expect(err).NotTo(HaveOccurred())
 		})
 	})
+
+	Describe("ReferenceSource", func() {
+		var clotildeRoot string
+
+		BeforeEach(func() {
+			tmpDir := GinkgoT().TempDir()
+			GinkgoT().Setenv("HOME", tmpDir)
+			clotildeRoot = filepath.Join(tmpDir, "project", ".claude", "clotilde")
+			Expect(os.MkdirAll(clotildeRoot, 0o755)).To(Succeed())
+		})
+
+		It("marks built-in styles", func() {
+			source := outputstyle.ReferenceSource(clotildeRoot, "Explanatory")
+			Expect(source.Kind).To(Equal(session.OutputStyleBuiltin))
+			Expect(source.Path).To(BeEmpty())
+		})
+
+		It("records the file of a shared style", func() {
+			stylePath := filepath.Join(filepath.Dir(clotildeRoot), "output-styles", "reviewer.md")
+			Expect(os.MkdirAll(filepath.Dir(stylePath), 0o755)).To(Succeed())
+			Expect(os.WriteFile(stylePath, []byte("Review."), 0o644)).To(Succeed())
+
+			source := outputstyle.ReferenceSource(clotildeRoot, "reviewer")
+			Expect(source.Kind).To(Equal(session.OutputStyleShared))
+			Expect(source.Name).To(Equal("reviewer"))
+			Expect(source.Path).To(Equal(stylePath))
+		})
+
+		It("leaves the path empty for missing shared styles", func() {
+			source := outputstyle.ReferenceSource(clotildeRoot, "missing")
+			Expect(source.Kind).To(Equal(session.OutputStyleShared))
+			Expect(source.Path).To(BeEmpty())
+		})
+	})
+
+	Describe("ParseStyleFile", func() {
+		It("splits frontmatter and body", func() {
+			style, err := outputstyle.ParseStyleFile("---\nname: reviewer\ndescription: Reviews\nkeep-coding-instructions: false\n---\n\nBe strict.\n")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(style.HasFrontmatter).To(BeTrue())
+			Expect(style.Field("name")).To(Equal("reviewer"))
+			Expect(style.Field("keep-coding-instructions")).To(Equal("false"))
+			Expect(style.Body).To(Equal("Be strict."))
+			Expect(style.Problems()).To(BeEmpty())
+		})
+
+		It("treats files without frontmatter as body only", func() {
+			style, err := outputstyle.ParseStyleFile("Be strict.\n")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(style.HasFrontmatter).To(BeFalse())
+			Expect(style.Body).To(Equal("Be strict."))
+			Expect(style.Problems()).To(ConsistOf(ContainSubstring("no frontmatter")))
+		})
+
+		It("rejects invalid YAML", func() {
+			_, err := outputstyle.ParseStyleFile("---\nname: [unclosed\n---\nBody\n")
+			Expect(err).To(MatchError(ContainSubstring("invalid frontmatter")))
+		})
+	})
 })
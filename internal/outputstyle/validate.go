@@ -0,0 +1,80 @@
+package outputstyle
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// frontmatterFields are the frontmatter keys Claude Code reads from an output
+// style file.
+var frontmatterFields = []string{"name", "description", "keep-coding-instructions"}
+
+// StyleFile is an output style file split into its frontmatter and body.
+type StyleFile struct {
+	HasFrontmatter bool
+	Fields         map[string]any // Parsed frontmatter
+	Body           string         // Markdown instructions after the frontmatter
+}
+
+// ParseStyleFile splits an output style file into frontmatter and body. A file
+// without a leading "---" is all body. Unclosed or malformed frontmatter is
+// an error.
+func ParseStyleFile(content string) (*StyleFile, error) {
+	if !strings.HasPrefix(content, "---") {
+		return &StyleFile{Body: strings.TrimSpace(content)}, nil
+	}
+
+	parts := strings.SplitN(content, "---", 3)
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("invalid frontmatter format (missing closing ---)")
+	}
+
+	fields := map[string]any{}
+	if err := yaml.Unmarshal([]byte(parts[1]), &fields); err != nil {
+		return nil, fmt.Errorf("invalid frontmatter: %w", err)
+	}
+	return &StyleFile{HasFrontmatter: true, Fields: fields, Body: strings.TrimSpace(parts[2])}, nil
+}
+
+// Field returns a frontmatter value as text, or "" when it's not set.
+func (f *StyleFile) Field(key string) string {
+	value, ok := f.Fields[key]
+	if !ok || value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+// Problems lists what would keep Claude Code from picking the style up as
+// written, or make it behave unexpectedly. Files without frontmatter are only
+// accepted by --output-style-file, which adds one.
+func (f *StyleFile) Problems() []string {
+	var problems []string
+	if !f.HasFrontmatter {
+		problems = append(problems, "no frontmatter (name, description); only --output-style-file accepts this")
+	} else {
+		for _, key := range []string{"name", "description"} {
+			if strings.TrimSpace(f.Field(key)) == "" {
+				problems = append(problems, fmt.Sprintf("frontmatter missing required field '%s'", key))
+			}
+		}
+		if value, ok := f.Fields["keep-coding-instructions"]; ok {
+			if _, isBool := value.(bool); !isBool {
+				problems = append(problems, fmt.Sprintf("'keep-coding-instructions' must be true or false, got '%v'", value))
+			}
+		}
+		for _, key := range slices.Sorted(maps.Keys(f.Fields)) {
+			if !slices.Contains(frontmatterFields, key) {
+				problems = append(problems, fmt.Sprintf("unknown frontmatter field '%s' (ignored)", key))
+			}
+		}
+	}
+	if f.Body == "" {
+		problems = append(problems, "no instructions after the frontmatter")
+	}
+	return problems
+}
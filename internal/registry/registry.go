@@ -0,0 +1,121 @@
+// Package registry maintains the global list of projects that use clotilde,
+// so sessions can be summarized across projects.
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/util"
+)
+
+// registryFile is the registry file name within config.GlobalDataDir().
+const registryFile = "projects.json"
+
+// Project is a project recorded in the registry.
+type Project struct {
+	Path     string    `json:"path"`     // Project root (parent of .claude/clotilde)
+	LastUsed time.Time `json:"lastUsed"` // Last time init or start ran in the project
+}
+
+// Registry is the set of known projects.
+type Registry struct {
+	Projects []Project `json:"projects"`
+}
+
+// Path returns the path to the registry file.
+func Path() string {
+	return filepath.Join(config.GlobalDataDir(), registryFile)
+}
+
+// Load reads the registry. A missing file yields an empty registry.
+func Load() (*Registry, error) {
+	reg := &Registry{}
+	if err := util.ReadJSON(Path(), reg); err != nil {
+		if os.IsNotExist(err) {
+			return reg, nil
+		}
+		return nil, fmt.Errorf("failed to read project registry: %w", err)
+	}
+	return reg, nil
+}
+
+// Save writes the registry atomically (write to a temp file, then rename) so
+// concurrent clotilde processes never observe a partial file.
+func (r *Registry) Save() error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path := Path()
+	if err := util.EnsureDir(filepath.Dir(path)); err != nil {
+		return fmt.Errorf("failed to create registry directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), registryFile+".*")
+	if err != nil {
+		return fmt.Errorf("failed to write project registry: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write project registry: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write project registry: %w", err)
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Touch records projectRoot as used now, adding it if missing.
+// Projects are kept sorted by LastUsed (most recent first).
+func (r *Registry) Touch(projectRoot string) {
+	now := time.Now()
+	found := false
+	for i := range r.Projects {
+		if r.Projects[i].Path == projectRoot {
+			r.Projects[i].LastUsed = now
+			found = true
+			break
+		}
+	}
+	if !found {
+		r.Projects = append(r.Projects, Project{Path: projectRoot, LastUsed: now})
+	}
+	sort.SliceStable(r.Projects, func(i, j int) bool {
+		return r.Projects[i].LastUsed.After(r.Projects[j].LastUsed)
+	})
+}
+
+// Remove drops projectRoot from the registry. Returns false if it was not registered.
+func (r *Registry) Remove(projectRoot string) bool {
+	for i, p := range r.Projects {
+		if p.Path == projectRoot {
+			r.Projects = append(r.Projects[:i], r.Projects[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Register records a project in the global registry.
+func Register(projectRoot string) error {
+	abs, err := filepath.Abs(projectRoot)
+	if err != nil {
+		return err
+	}
+
+	reg, err := Load()
+	if err != nil {
+		return err
+	}
+	reg.Touch(abs)
+	return reg.Save()
+}
@@ -0,0 +1,55 @@
+package registry_test
+
+import (
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/registry"
+)
+
+var _ = Describe("Registry", func() {
+	var dataHome string
+
+	BeforeEach(func() {
+		dataHome = GinkgoT().TempDir()
+		GinkgoT().Setenv("XDG_DATA_HOME", dataHome)
+	})
+
+	It("stores the registry under XDG_DATA_HOME", func() {
+		Expect(registry.Path()).To(Equal(filepath.Join(dataHome, "clotilde", "projects.json")))
+	})
+
+	It("returns an empty registry when the file does not exist", func() {
+		reg, err := registry.Load()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reg.Projects).To(BeEmpty())
+	})
+
+	It("registers projects once, most recently used first", func() {
+		Expect(registry.Register("/projects/one")).To(Succeed())
+		Expect(registry.Register("/projects/two")).To(Succeed())
+		Expect(registry.Register("/projects/one")).To(Succeed())
+
+		reg, err := registry.Load()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reg.Projects).To(HaveLen(2))
+		Expect(reg.Projects[0].Path).To(Equal("/projects/one"))
+		Expect(reg.Projects[1].Path).To(Equal("/projects/two"))
+	})
+
+	It("removes projects", func() {
+		Expect(registry.Register("/projects/one")).To(Succeed())
+
+		reg, err := registry.Load()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reg.Remove("/projects/one")).To(BeTrue())
+		Expect(reg.Remove("/projects/one")).To(BeFalse())
+		Expect(reg.Save()).To(Succeed())
+
+		reg, err = registry.Load()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reg.Projects).To(BeEmpty())
+	})
+})
@@ -0,0 +1,110 @@
+// Package scheduler runs housekeeping tasks that are due, within a time
+// budget, recording when each last ran so that repeated runs (e.g. after
+// every command) only do work once per interval.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fgrehm/clotilde/internal/util"
+)
+
+// StateFile records when each task last ran, in the directory given to New.
+const StateFile = ".maintenance.json"
+
+// Task is a unit of housekeeping. Run does the work, stopping early when ctx
+// is done, and returns a one-line summary of what changed ("" for nothing).
+type Task struct {
+	Name string
+	Run  func(ctx context.Context) (string, error)
+}
+
+// Result is the outcome of a task that ran.
+type Result struct {
+	Task    string
+	Summary string
+	Err     error
+}
+
+// taskState is what the state file keeps about a task.
+type taskState struct {
+	LastRun   time.Time `json:"lastRun"`
+	LastError string    `json:"lastError,omitempty"`
+}
+
+// Scheduler runs tasks, keeping their state in a directory.
+type Scheduler struct {
+	dir      string
+	interval time.Duration
+}
+
+// New returns a scheduler keeping its state in dir, whose tasks are due once
+// interval has passed since they last ran.
+func New(dir string, interval time.Duration) *Scheduler {
+	return &Scheduler{dir: dir, interval: interval}
+}
+
+// RunDue runs the tasks that are due, in order, until budget is spent. A task
+// cut short by the budget stays due; a task that failed waits for the next
+// interval like one that succeeded, so a persistent failure isn't retried
+// after every command. Returns nothing when another process is running the
+// tasks.
+func (s *Scheduler) RunDue(ctx context.Context, budget time.Duration, tasks []Task) ([]Result, error) {
+	return s.run(ctx, budget, tasks, false)
+}
+
+// RunAll runs every task now, due or not, without a time budget.
+func (s *Scheduler) RunAll(ctx context.Context, tasks []Task) ([]Result, error) {
+	return s.run(ctx, 0, tasks, true)
+}
+
+func (s *Scheduler) run(ctx context.Context, budget time.Duration, tasks []Task, all bool) ([]Result, error) {
+	if budget > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, budget)
+		defer cancel()
+	}
+
+	var results []Result
+	path := filepath.Join(s.dir, StateFile)
+	_, err := util.TryFileLock(path, func() error {
+		state := map[string]taskState{}
+		if err := util.ReadJSON(path, &state); err != nil && !os.IsNotExist(err) {
+			// A corrupt state file only means every task is due again
+			state = map[string]taskState{}
+		}
+
+		for _, task := range tasks {
+			if ctx.Err() != nil {
+				break
+			}
+			if !all && time.Now().Sub(state[task.Name].LastRun) < s.interval {
+				continue
+			}
+			summary, err := task.Run(ctx)
+			if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+				break
+			}
+			results = append(results, Result{Task: task.Name, Summary: summary, Err: err})
+			entry := taskState{LastRun: time.Now()}
+			if err != nil {
+				entry.LastError = err.Error()
+			}
+			state[task.Name] = entry
+		}
+
+		if len(results) == 0 {
+			return nil
+		}
+		if err := util.WriteJSON(path, state); err != nil {
+			return fmt.Errorf("failed to save maintenance state: %w", err)
+		}
+		return nil
+	})
+	return results, err
+}
@@ -0,0 +1,92 @@
+package scheduler_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/scheduler"
+)
+
+var _ = Describe("Scheduler", func() {
+	var (
+		stateDir string
+		runs     map[string]int
+	)
+
+	BeforeEach(func() {
+		stateDir = GinkgoT().TempDir()
+		runs = map[string]int{}
+	})
+
+	task := func(name string, err error) scheduler.Task {
+		return scheduler.Task{Name: name, Run: func(ctx context.Context) (string, error) {
+			runs[name]++
+			return "ran " + name, err
+		}}
+	}
+
+	It("runs each task once per interval", func() {
+		s := scheduler.New(stateDir, time.Hour)
+		tasks := []scheduler.Task{task("a", nil), task("b", errors.New("boom"))}
+
+		results, err := s.RunDue(context.Background(), time.Second, tasks)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(results).To(HaveLen(2))
+		Expect(results[0].Summary).To(Equal("ran a"))
+		Expect(results[1].Err).To(MatchError("boom"))
+
+		results, err = s.RunDue(context.Background(), time.Second, tasks)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(results).To(BeEmpty())
+		Expect(runs).To(Equal(map[string]int{"a": 1, "b": 1}))
+
+		_, err = scheduler.New(stateDir, 0).RunDue(context.Background(), time.Second, tasks)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(runs).To(Equal(map[string]int{"a": 2, "b": 2}))
+	})
+
+	It("runs every task on RunAll, due or not", func() {
+		s := scheduler.New(stateDir, time.Hour)
+		tasks := []scheduler.Task{task("a", nil)}
+		_, err := s.RunDue(context.Background(), time.Second, tasks)
+		Expect(err).NotTo(HaveOccurred())
+
+		results, err := s.RunAll(context.Background(), tasks)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(results).To(HaveLen(1))
+		Expect(runs["a"]).To(Equal(2))
+	})
+
+	It("leaves tasks the budget cut short due", func() {
+		slow := scheduler.Task{Name: "slow", Run: func(ctx context.Context) (string, error) {
+			runs["slow"]++
+			<-ctx.Done()
+			return "", ctx.Err()
+		}}
+		s := scheduler.New(stateDir, time.Hour)
+
+		results, err := s.RunDue(context.Background(), 10*time.Millisecond, []scheduler.Task{slow, task("a", nil)})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(results).To(BeEmpty())
+		Expect(runs).To(Equal(map[string]int{"slow": 1}))
+
+		_, err = s.RunDue(context.Background(), 10*time.Millisecond, []scheduler.Task{slow})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(runs["slow"]).To(Equal(2))
+	})
+
+	It("skips the run while another process holds the lock", func() {
+		Expect(os.WriteFile(filepath.Join(stateDir, scheduler.StateFile+".lock"), nil, 0o644)).To(Succeed())
+
+		results, err := scheduler.New(stateDir, 0).RunDue(context.Background(), time.Second, []scheduler.Task{task("a", nil)})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(results).To(BeEmpty())
+		Expect(runs).To(BeEmpty())
+	})
+})
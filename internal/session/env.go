@@ -0,0 +1,162 @@
+package session
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/errs"
+)
+
+// EnvFile holds a session's environment variables (KEY=VALUE lines), set in
+// claude's environment whenever the session is launched.
+const EnvFile = "env"
+
+var envKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ValidateEnvKey checks that key is usable as an environment variable name.
+func ValidateEnvKey(key string) error {
+	if !envKeyPattern.MatchString(key) {
+		return fmt.Errorf("invalid environment variable name '%s' (letters, digits and underscores, not starting with a digit)", key)
+	}
+	return nil
+}
+
+// ParseEnv parses KEY=VALUE lines. Blank lines and lines starting with # are
+// skipped, an "export " prefix is allowed, and values may be wrapped in
+// single or double quotes.
+func ParseEnv(data []byte) (map[string]string, error) {
+	env := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", n)
+		}
+		if err := ValidateEnvKey(key); err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		env[key] = unquoteEnvValue(strings.TrimSpace(value))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return env, nil
+}
+
+func unquoteEnvValue(value string) string {
+	if len(value) >= 2 {
+		first, last := value[0], value[len(value)-1]
+		if first == last && (first == '"' || first == '\'') {
+			return value[1 : len(value)-1]
+		}
+	}
+	return value
+}
+
+// quoteEnvValue wraps value in quotes when ParseEnv wouldn't read it back
+// as is. ParseEnv strips one pair of quotes without unescaping, so any
+// quote character works; single quotes are preferred as they read better
+// around double-quoted text.
+func quoteEnvValue(value string) string {
+	if value == strings.TrimSpace(value) && value == unquoteEnvValue(value) {
+		return value
+	}
+	if strings.Contains(value, "'") {
+		return `"` + value + `"`
+	}
+	return "'" + value + "'"
+}
+
+// FormatEnv renders env as KEY=VALUE lines sorted by key. Values ParseEnv
+// would change (surrounding whitespace or quotes) are wrapped in quotes.
+func FormatEnv(env map[string]string) []byte {
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	var buf bytes.Buffer
+	for _, key := range keys {
+		_, _ = fmt.Fprintf(&buf, "%s=%s\n", key, quoteEnvValue(env[key]))
+	}
+	return buf.Bytes()
+}
+
+// LoadEnv loads the session's env file (returns an empty map if not exists).
+func (fs *FileStore) LoadEnv(name string) (map[string]string, error) {
+	if err := ValidateStoredName(name); err != nil {
+		return nil, err
+	}
+
+	envPath := filepath.Join(config.GetSessionDir(fs.clotildeRoot, name), EnvFile)
+	data, err := os.ReadFile(envPath)
+	if os.IsNotExist(err) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read env file: %w", err)
+	}
+
+	env, err := ParseEnv(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid env file %s: %w", envPath, err)
+	}
+	return env, nil
+}
+
+// SaveEnv writes the session's env file, readable only by the user since it
+// may hold secrets. An empty env removes the file.
+func (fs *FileStore) SaveEnv(name string, env map[string]string) error {
+	if err := ValidateStoredName(name); err != nil {
+		return err
+	}
+
+	if !fs.Exists(name) {
+		return errs.NotFound("session '%s' not found", name)
+	}
+
+	envPath := filepath.Join(config.GetSessionDir(fs.clotildeRoot, name), EnvFile)
+	if len(env) == 0 {
+		if err := os.Remove(envPath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove env file: %w", err)
+		}
+		return nil
+	}
+
+	if err := validateEnv(env); err != nil {
+		return err
+	}
+	if err := os.WriteFile(envPath, FormatEnv(env), 0o600); err != nil {
+		return fmt.Errorf("failed to write env file: %w", err)
+	}
+	// WriteFile keeps the mode of an existing file
+	return os.Chmod(envPath, 0o600)
+}
+
+// validateEnv checks that env can be written as an env file.
+func validateEnv(env map[string]string) error {
+	for key, value := range env {
+		if err := ValidateEnvKey(key); err != nil {
+			return err
+		}
+		if strings.ContainsAny(value, "\r\n") {
+			return fmt.Errorf("value of %s spans several lines, which the env file can't hold", key)
+		}
+	}
+	return nil
+}
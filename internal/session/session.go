@@ -1,8 +1,14 @@
 package session
 
 import (
+	"encoding/json"
+	"fmt"
+	"os"
 	"slices"
+	"sync"
 	"time"
+
+	"github.com/fgrehm/clotilde/internal/util"
 )
 
 // Session represents a named Claude Code session.
@@ -13,17 +19,199 @@ type Session struct {
 
 // Metadata represents the session metadata stored in metadata.json.
 type Metadata struct {
-	Name                 string    `json:"name"`
-	SessionID            string    `json:"sessionId"`
-	TranscriptPath       string    `json:"transcriptPath,omitempty"`
-	Created              time.Time `json:"created"`
-	LastAccessed         time.Time `json:"lastAccessed"`
-	ParentSession        string    `json:"parentSession,omitempty"`
-	IsForkedSession      bool      `json:"isForkedSession"`
-	IsIncognito          bool      `json:"isIncognito"`
-	PreviousSessionIDs   []string  `json:"previousSessionIds,omitempty"`
-	Context              string    `json:"context,omitempty"`
-	HasCustomOutputStyle bool      `json:"hasCustomOutputStyle,omitempty"`
+	Name                 string             `json:"name"`
+	SessionID            string             `json:"sessionId"`
+	TranscriptPath       string             `json:"transcriptPath,omitempty"`
+	Created              time.Time          `json:"created"`
+	LastAccessed         time.Time          `json:"lastAccessed"`
+	ParentSession        string             `json:"parentSession,omitempty"`
+	NoParentContext      bool               `json:"noParentContext,omitempty"` // Fork opted out of inheriting and injecting the parent's context
+	IsForkedSession      bool               `json:"isForkedSession"`
+	IsIncognito          bool               `json:"isIncognito"`
+	PreviousSessions     []PreviousSession  `json:"previousSessions,omitempty"` // Superseded session IDs, oldest first
+	Context              string             `json:"context,omitempty"`
+	HasCustomOutputStyle bool               `json:"hasCustomOutputStyle,omitempty"`
+	OutputStyleSource    *OutputStyleSource `json:"outputStyleSource,omitempty"` // Where the output style came from; nil for none (or metadata written before it was recorded)
+	ExpiresAt            time.Time          `json:"expiresAt,omitzero"`
+	PendingLaunch        bool               `json:"pendingLaunch,omitempty"` // Created without launching Claude Code; no transcript yet
+	InitialPrompt        string             `json:"initialPrompt,omitempty"` // Sent as the first message when a pending session launches
+	LastExit             *ExitStatus        `json:"lastExit,omitempty"`
+	Status               string             `json:"status,omitempty"`   // Lifecycle status (Status*); empty for sessions written before it existed
+	Starred              bool               `json:"starred,omitempty"`  // Listed before the other sessions (toggled with 'clotilde star')
+	Launcher             []string           `json:"launcher,omitempty"` // Command template run instead of claude (see claude.Launch); from --launcher or the profile
+	Process              *Process           `json:"process,omitempty"`  // The clotilde process running Claude Code; set while active
+}
+
+// Lifecycle statuses, see Session.Status.
+const (
+	StatusActive   = "active"   // Claude Code is running for the session
+	StatusIdle     = "idle"     // Not running
+	StatusArchived = "archived" // Put away with 'clotilde archive' until resumed or unarchived
+	StatusExpired  = "expired"  // Past its expiry and kept because auto-prune is off
+	StatusBroken   = "broken"   // The last Claude Code run crashed
+)
+
+// Statuses lists the lifecycle statuses.
+var Statuses = []string{StatusActive, StatusIdle, StatusArchived, StatusExpired, StatusBroken}
+
+// Process identifies the clotilde process running Claude Code for a session,
+// so a session left active by a killed clotilde can be told apart from one in
+// use.
+type Process struct {
+	PID  int    `json:"pid"`
+	Host string `json:"host"`
+}
+
+// CurrentProcess returns the Process of this clotilde.
+func CurrentProcess() *Process {
+	return &Process{PID: os.Getpid(), Host: hostname()}
+}
+
+// Running reports whether the process still runs. Processes on other
+// machines (e.g. a project on a shared drive) can't be checked and count as
+// running.
+func (p *Process) Running() bool {
+	if p.Host != hostname() {
+		return true
+	}
+	return util.ProcessRunning(p.PID)
+}
+
+var hostname = sync.OnceValue(func() string {
+	name, _ := os.Hostname()
+	return name
+})
+
+// ExitStatus records how the last Claude Code run for a session ended.
+type ExitStatus struct {
+	Code   int       `json:"code"`
+	Signal string    `json:"signal,omitempty"` // Set when claude was killed by a signal
+	At     time.Time `json:"at"`
+}
+
+// Crashed reports whether the run ended with anything other than a clean exit
+// or a Ctrl+C interrupt.
+func (e ExitStatus) Crashed() bool {
+	return e.Code != 0 && e.Code != 130
+}
+
+// Summary describes the exit, e.g. "ok", "interrupted (130)" or "crashed (137)".
+func (e ExitStatus) Summary() string {
+	switch {
+	case e.Code == 0:
+		return "ok"
+	case !e.Crashed():
+		return fmt.Sprintf("interrupted (%d)", e.Code)
+	case e.Signal != "":
+		return fmt.Sprintf("crashed (%d, %s)", e.Code, e.Signal)
+	default:
+		return fmt.Sprintf("crashed (%d)", e.Code)
+	}
+}
+
+// Reasons a session moves to a new Claude Code session ID.
+const (
+	RotationClear   = "clear"
+	RotationCompact = "compact"
+)
+
+// What was done with a superseded transcript, see PreviousSession.Detached.
+const (
+	TranscriptSnapshot = "snapshot" // Copied into the session folder
+	TranscriptPruned   = "pruned"   // Deleted from Claude's storage
+)
+
+// PreviousSession records a Claude Code session ID the session moved away
+// from. Entries migrated from the old flat previousSessionIds list only have
+// SessionID set.
+type PreviousSession struct {
+	SessionID      string    `json:"sessionId"`
+	SupersededAt   time.Time `json:"supersededAt,omitzero"`
+	Reason         string    `json:"reason,omitempty"`         // RotationClear or RotationCompact
+	TranscriptPath string    `json:"transcriptPath,omitempty"` // Transcript path when it was superseded
+	Detached       string    `json:"detached,omitempty"`       // TranscriptSnapshot, TranscriptPruned, or empty when left in place
+}
+
+// legacyRotation is an entry of the "history" list that briefly recorded when
+// and why each ID of "previousSessionIds" was superseded.
+type legacyRotation struct {
+	SessionID    string    `json:"sessionId"`
+	SupersededAt time.Time `json:"supersededAt"`
+	Reason       string    `json:"reason"`
+	Transcript   string    `json:"transcript"`
+}
+
+// UnmarshalJSON migrates metadata written before previousSessions existed:
+// each ID of the legacy flat "previousSessionIds" list becomes an entry, and
+// the legacy "history" list fills in when and why it was superseded.
+// The migrated form is persisted on the next write.
+func (m *Metadata) UnmarshalJSON(data []byte) error {
+	type plain Metadata
+	var raw struct {
+		plain
+		LegacyPreviousSessionIDs []string         `json:"previousSessionIds"`
+		LegacyHistory            []legacyRotation `json:"history"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*m = Metadata(raw.plain)
+	for _, id := range raw.LegacyPreviousSessionIDs {
+		if id != "" && !m.HasPreviousSessionID(id) {
+			m.PreviousSessions = append(m.PreviousSessions, PreviousSession{SessionID: id})
+		}
+	}
+	for _, r := range raw.LegacyHistory {
+		if r.SessionID == "" {
+			continue
+		}
+		i := slices.IndexFunc(m.PreviousSessions, func(p PreviousSession) bool { return p.SessionID == r.SessionID })
+		if i < 0 {
+			m.PreviousSessions = append(m.PreviousSessions, PreviousSession{SessionID: r.SessionID})
+			i = len(m.PreviousSessions) - 1
+		}
+		p := &m.PreviousSessions[i]
+		if p.SupersededAt.IsZero() {
+			p.SupersededAt = r.SupersededAt
+		}
+		if p.Reason == "" {
+			p.Reason = r.Reason
+		}
+		if p.Detached == "" {
+			p.Detached = r.Transcript
+		}
+	}
+	return nil
+}
+
+// Output style kinds, see OutputStyleSource.
+const (
+	OutputStyleBuiltin = "builtin" // One of Claude Code's built-in styles
+	OutputStyleShared  = "shared"  // An existing project or user style, referenced by name
+	OutputStyleCustom  = "custom"  // The session's own style file, from inline content or --output-style-file
+)
+
+// OutputStyleSource records where a session's output style came from.
+type OutputStyleSource struct {
+	Kind string `json:"kind"`           // OutputStyleBuiltin, OutputStyleShared or OutputStyleCustom
+	Name string `json:"name,omitempty"` // Style name, for builtin and shared styles
+	Path string `json:"path,omitempty"` // The shared style's file, or the file a custom style was created from
+}
+
+// OwnsOutputStyle reports whether the session has its own output style file,
+// which is copied to forks and deleted with the session. Shared styles are
+// only referenced. Metadata written before provenance was recorded falls
+// back to HasCustomOutputStyle.
+func (m *Metadata) OwnsOutputStyle() bool {
+	if m.OutputStyleSource != nil {
+		return m.OutputStyleSource.Kind == OutputStyleCustom
+	}
+	return m.HasCustomOutputStyle
+}
+
+// HasPreviousSessionID reports whether id is one of the superseded session IDs.
+func (m *Metadata) HasPreviousSessionID(id string) bool {
+	return slices.ContainsFunc(m.PreviousSessions, func(p PreviousSession) bool { return p.SessionID == id })
 }
 
 // Settings represents Claude Code session-specific settings stored in settings.json.
@@ -71,15 +259,54 @@ func (s *Session) UpdateLastAccessed() {
 	s.Metadata.LastAccessed = time.Now()
 }
 
-// AddPreviousSessionID appends the current session ID to the history and updates to the new ID.
-// This is idempotent - won't add duplicates.
-func (s *Session) AddPreviousSessionID(newSessionID string) {
-	// Only add current ID to history if it's not empty and different from new ID
-	if s.Metadata.SessionID != "" && s.Metadata.SessionID != newSessionID {
-		if !slices.Contains(s.Metadata.PreviousSessionIDs, s.Metadata.SessionID) {
-			s.Metadata.PreviousSessionIDs = append(s.Metadata.PreviousSessionIDs, s.Metadata.SessionID)
-		}
+// Status returns the session's lifecycle status. Commands set it as sessions
+// are launched, exit, expire or get archived; sessions without one are idle,
+// and so are active ones whose clotilde process is gone (killed before it
+// could record the exit).
+func (s *Session) Status() string {
+	if s.Metadata.Status == "" {
+		return StatusIdle
+	}
+	if s.Metadata.Status == StatusActive && s.Metadata.Process != nil && !s.Metadata.Process.Running() {
+		return StatusIdle
 	}
+	return s.Metadata.Status
+}
+
+// IsExpired reports whether the session has an expiry set and it has passed.
+func (s *Session) IsExpired(now time.Time) bool {
+	return !s.Metadata.ExpiresAt.IsZero() && !now.Before(s.Metadata.ExpiresAt)
+}
 
+// SortStarredFirst sorts sessions with the starred ones first, each group
+// most recently accessed first.
+func SortStarredFirst(sessions []*Session) {
+	slices.SortStableFunc(sessions, func(a, b *Session) int {
+		if a.Metadata.Starred != b.Metadata.Starred {
+			if a.Metadata.Starred {
+				return -1
+			}
+			return 1
+		}
+		return b.Metadata.LastAccessed.Compare(a.Metadata.LastAccessed)
+	})
+}
+
+// RotateSessionID moves the session to a new Claude Code session ID, recording
+// the current ID, its transcript path, and when and why it was superseded in
+// PreviousSessions. Idempotent: an ID already recorded isn't added twice.
+// Returns the superseded ID, or "" when the ID didn't change.
+func (s *Session) RotateSessionID(newSessionID, reason string) string {
+	oldSessionID := s.Metadata.SessionID
 	s.Metadata.SessionID = newSessionID
+	if oldSessionID == "" || oldSessionID == newSessionID || s.Metadata.HasPreviousSessionID(oldSessionID) {
+		return ""
+	}
+	s.Metadata.PreviousSessions = append(s.Metadata.PreviousSessions, PreviousSession{
+		SessionID:      oldSessionID,
+		SupersededAt:   time.Now(),
+		Reason:         reason,
+		TranscriptPath: s.Metadata.TranscriptPath,
+	})
+	return oldSessionID
 }
@@ -1,6 +1,8 @@
 package session_test
 
 import (
+	"os"
+	"os/exec"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -57,4 +59,119 @@ var _ = Describe("Session", func() {
 			Expect(s.Metadata.LastAccessed).To(BeTemporally("~", time.Now(), time.Second))
 		})
 	})
+
+	Describe("IsExpired", func() {
+		It("is false when no expiry is set", func() {
+			s := session.NewSession("test", "uuid")
+			Expect(s.IsExpired(time.Now())).To(BeFalse())
+		})
+
+		It("is true once the expiry has passed", func() {
+			s := session.NewSession("test", "uuid")
+			s.Metadata.ExpiresAt = time.Now().Add(time.Hour)
+
+			Expect(s.IsExpired(time.Now())).To(BeFalse())
+			Expect(s.IsExpired(time.Now().Add(2 * time.Hour))).To(BeTrue())
+		})
+	})
+
+	Describe("Status", func() {
+		It("is idle for sessions without a recorded status", func() {
+			s := session.NewSession("test", "uuid")
+			Expect(s.Status()).To(Equal(session.StatusIdle))
+
+			s.Metadata.Status = session.StatusBroken
+			Expect(s.Status()).To(Equal(session.StatusBroken))
+		})
+
+		It("is idle for active sessions whose clotilde process is gone", func() {
+			s := session.NewSession("test", "uuid")
+			s.Metadata.Status = session.StatusActive
+			s.Metadata.Process = session.CurrentProcess()
+			Expect(s.Status()).To(Equal(session.StatusActive))
+
+			cmd := exec.Command(os.Args[0], "-test.run=^$")
+			Expect(cmd.Run()).To(Succeed())
+			s.Metadata.Process.PID = cmd.Process.Pid
+			Expect(s.Status()).To(Equal(session.StatusIdle))
+
+			// Processes on other machines can't be checked
+			s.Metadata.Process.Host = "elsewhere"
+			Expect(s.Status()).To(Equal(session.StatusActive))
+		})
+	})
+
+	Describe("RotateSessionID", func() {
+		It("records the superseded ID with its transcript and the reason", func() {
+			s := session.NewSession("test", "uuid-1")
+			s.Metadata.TranscriptPath = "/transcripts/uuid-1.jsonl"
+
+			Expect(s.RotateSessionID("uuid-2", session.RotationClear)).To(Equal("uuid-1"))
+			Expect(s.Metadata.SessionID).To(Equal("uuid-2"))
+			Expect(s.Metadata.PreviousSessions).To(HaveLen(1))
+			prev := s.Metadata.PreviousSessions[0]
+			Expect(prev.SessionID).To(Equal("uuid-1"))
+			Expect(prev.Reason).To(Equal(session.RotationClear))
+			Expect(prev.TranscriptPath).To(Equal("/transcripts/uuid-1.jsonl"))
+			Expect(prev.SupersededAt).To(BeTemporally("~", time.Now(), time.Second))
+			Expect(s.Metadata.HasPreviousSessionID("uuid-1")).To(BeTrue())
+		})
+
+		It("records nothing when the ID doesn't change", func() {
+			s := session.NewSession("test", "uuid-1")
+
+			Expect(s.RotateSessionID("uuid-1", session.RotationCompact)).To(BeEmpty())
+			Expect(s.Metadata.PreviousSessions).To(BeEmpty())
+		})
+
+		It("doesn't record an ID twice", func() {
+			s := session.NewSession("test", "uuid-1")
+			s.RotateSessionID("uuid-2", session.RotationClear)
+			s.RotateSessionID("uuid-1", session.RotationClear)
+
+			Expect(s.RotateSessionID("uuid-3", session.RotationClear)).To(BeEmpty())
+			Expect(s.Metadata.PreviousSessions).To(HaveLen(2))
+		})
+	})
+
+	Describe("SortStarredFirst", func() {
+		It("lists starred sessions first, each group most recent first", func() {
+			now := time.Now()
+			sessionAt := func(name string, ago time.Duration, starred bool) *session.Session {
+				s := session.NewSession(name, name+"-uuid")
+				s.Metadata.LastAccessed = now.Add(-ago)
+				s.Metadata.Starred = starred
+				return s
+			}
+			sessions := []*session.Session{
+				sessionAt("recent", time.Minute, false),
+				sessionAt("old-star", 48*time.Hour, true),
+				sessionAt("older", 24*time.Hour, false),
+				sessionAt("new-star", time.Hour, true),
+			}
+
+			session.SortStarredFirst(sessions)
+
+			var names []string
+			for _, s := range sessions {
+				names = append(names, s.Name)
+			}
+			Expect(names).To(Equal([]string{"new-star", "old-star", "recent", "older"}))
+		})
+	})
+
+	Describe("ExitStatus", func() {
+		It("summarizes clean exits, interrupts and crashes", func() {
+			Expect(session.ExitStatus{Code: 0}.Summary()).To(Equal("ok"))
+			Expect(session.ExitStatus{Code: 130}.Summary()).To(Equal("interrupted (130)"))
+			Expect(session.ExitStatus{Code: 1}.Summary()).To(Equal("crashed (1)"))
+			Expect(session.ExitStatus{Code: 137, Signal: "killed"}.Summary()).To(Equal("crashed (137, killed)"))
+		})
+
+		It("treats only errors other than Ctrl+C as crashes", func() {
+			Expect(session.ExitStatus{Code: 0}.Crashed()).To(BeFalse())
+			Expect(session.ExitStatus{Code: 130}.Crashed()).To(BeFalse())
+			Expect(session.ExitStatus{Code: 2}.Crashed()).To(BeTrue())
+		})
+	})
 })
@@ -0,0 +1,273 @@
+package session
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/errs"
+	"github.com/fgrehm/clotilde/internal/util"
+)
+
+// Compile-time check that SSHStore implements Store.
+var _ Store = (*SSHStore)(nil)
+
+// Exit codes the remote scripts use to report missing and existing sessions.
+const (
+	remoteExitNotFound = 3
+	remoteExitExists   = 4
+)
+
+// RemoteRunner runs a POSIX shell script on a remote host with stdin as its
+// input and returns what the script printed.
+type RemoteRunner func(script string, stdin []byte) ([]byte, error)
+
+// SSHStore implements Store for a clotilde root on another machine, running
+// small shell scripts there through a RemoteRunner (ssh by default). It
+// doesn't record events in the remote project's events log.
+type SSHStore struct {
+	sessionsDir string
+	run         RemoteRunner
+}
+
+// NewSSHStore creates an SSHStore for the project at projectPath on remote.
+// Relative paths (and paths starting with ~/) are relative to the remote
+// user's home directory.
+func NewSSHStore(remote config.Remote, projectPath string) *SSHStore {
+	return NewRemoteStore(projectPath, SSHRunner(remote))
+}
+
+// NewRemoteStore creates an SSHStore that runs its scripts with run.
+func NewRemoteStore(projectPath string, run RemoteRunner) *SSHStore {
+	projectPath = strings.TrimPrefix(projectPath, "~/")
+	if projectPath == "~" || projectPath == "" {
+		projectPath = "."
+	}
+	return &SSHStore{
+		sessionsDir: path.Join(projectPath, config.ClotildeDir, config.SessionsDir),
+		run:         run,
+	}
+}
+
+// SSHCommand builds the ssh command that runs command on remote. With tty set
+// ssh allocates a terminal, as claude needs when launched interactively.
+func SSHCommand(remote config.Remote, tty bool, command string) *exec.Cmd {
+	args := slices.Clone(remote.SSHArgs)
+	if tty {
+		args = append(args, "-t")
+	}
+	args = append(args, remote.Host, command)
+	return exec.Command("ssh", args...)
+}
+
+// SSHRunner returns a RemoteRunner that runs scripts on remote with ssh.
+func SSHRunner(remote config.Remote) RemoteRunner {
+	return func(script string, stdin []byte) ([]byte, error) {
+		cmd := SSHCommand(remote, false, "sh -c "+util.ShellQuote(script))
+		cmd.Stdin = bytes.NewReader(stdin)
+		out, err := cmd.Output()
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			if msg := strings.TrimSpace(string(exitErr.Stderr)); msg != "" {
+				return out, fmt.Errorf("%w: %s", err, msg)
+			}
+		}
+		return out, err
+	}
+}
+
+// List returns all sessions, sorted by lastAccessed (most recent first).
+func (s *SSHStore) List() ([]*Session, error) {
+	// Each session is printed as its name and metadata, NUL-terminated
+	script := "cd " + util.ShellQuote(s.sessionsDir) + ` 2>/dev/null || exit 0
+for d in */; do
+  [ -f "$d/metadata.json" ] || continue
+  printf '%s\0' "${d%/}"
+  cat "$d/metadata.json"
+  printf '\0'
+done`
+	out, err := s.run(script, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list remote sessions: %w", err)
+	}
+
+	fields := bytes.Split(out, []byte{0})
+	var sessions []*Session
+	for i := 0; i+1 < len(fields); i += 2 {
+		var metadata Metadata
+		if err := json.Unmarshal(fields[i+1], &metadata); err != nil {
+			// Skip sessions that can't be loaded
+			continue
+		}
+		sessions = append(sessions, &Session{Name: string(fields[i]), Metadata: metadata})
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].Metadata.LastAccessed.After(sessions[j].Metadata.LastAccessed)
+	})
+	return sessions, nil
+}
+
+// Get retrieves a session by name.
+func (s *SSHStore) Get(name string) (*Session, error) {
+	out, err := s.runInSession(name, `cat "$d/metadata.json"`, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var metadata Metadata
+	if err := json.Unmarshal(out, &metadata); err != nil {
+		return nil, fmt.Errorf("failed to read session metadata: %w", err)
+	}
+	return &Session{Name: name, Metadata: metadata}, nil
+}
+
+// Create creates a new session folder with metadata.
+func (s *SSHStore) Create(session *Session) error {
+	if err := validateRemoteName(session.Name); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(session.Metadata, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	script := fmt.Sprintf(`d=%s
+[ -e "$d" ] && exit %d
+mkdir -p "$d" && %s`, util.ShellQuote(path.Join(s.sessionsDir, session.Name)), remoteExitExists, writeRemoteFile(metadataFile))
+	if _, err := s.run(script, data); err != nil {
+		if remoteExitCode(err) == remoteExitExists {
+			return errs.AlreadyExists("session '%s' already exists", session.Name)
+		}
+		return fmt.Errorf("failed to create remote session: %w", err)
+	}
+	return nil
+}
+
+// Update updates session metadata.
+func (s *SSHStore) Update(session *Session) error {
+	data, err := json.MarshalIndent(session.Metadata, "", "  ")
+	if err != nil {
+		return err
+	}
+	if _, err := s.runInSession(session.Name, writeRemoteFile(metadataFile), data); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Delete removes a session folder and all its contents.
+func (s *SSHStore) Delete(name string) error {
+	_, err := s.runInSession(name, `rm -rf "$d"`, nil)
+	return err
+}
+
+// Exists checks if a session exists.
+func (s *SSHStore) Exists(name string) bool {
+	_, err := s.runInSession(name, "true", nil)
+	return err == nil
+}
+
+// LoadSettings loads settings.json for a session (returns nil if not exists).
+func (s *SSHStore) LoadSettings(name string) (*Settings, error) {
+	out, err := s.runInSession(name, `[ -f "$d/settings.json" ] && cat "$d/settings.json"; exit 0`, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(out)) == 0 {
+		return nil, nil
+	}
+
+	var settings Settings
+	if err := json.Unmarshal(out, &settings); err != nil {
+		return nil, fmt.Errorf("failed to read settings: %w", err)
+	}
+	return &settings, nil
+}
+
+// SaveSettings saves settings.json for a session.
+func (s *SSHStore) SaveSettings(name string, settings *Settings) error {
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = s.runInSession(name, writeRemoteFile(settingsFile), data)
+	return err
+}
+
+// LoadEnv loads the session's env file (returns an empty map if not exists).
+func (s *SSHStore) LoadEnv(name string) (map[string]string, error) {
+	out, err := s.runInSession(name, `[ -f "$d/env" ] && cat "$d/env"; exit 0`, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	env, err := ParseEnv(out)
+	if err != nil {
+		return nil, fmt.Errorf("invalid remote env file: %w", err)
+	}
+	return env, nil
+}
+
+// SaveEnv writes the session's env file, readable only by the user. An empty
+// env removes the file.
+func (s *SSHStore) SaveEnv(name string, env map[string]string) error {
+	if len(env) == 0 {
+		_, err := s.runInSession(name, `rm -f "$d/env"`, nil)
+		return err
+	}
+	if err := validateEnv(env); err != nil {
+		return err
+	}
+	_, err := s.runInSession(name, "umask 077 && "+writeRemoteFile(EnvFile), FormatEnv(env))
+	return err
+}
+
+// runInSession runs script with $d set to the session's folder, failing with
+// a not found error when the folder doesn't exist.
+func (s *SSHStore) runInSession(name, script string, stdin []byte) ([]byte, error) {
+	if err := validateRemoteName(name); err != nil {
+		return nil, err
+	}
+
+	full := fmt.Sprintf("d=%s\n[ -d \"$d\" ] || exit %d\n%s", util.ShellQuote(path.Join(s.sessionsDir, name)), remoteExitNotFound, script)
+	out, err := s.run(full, stdin)
+	if err != nil {
+		if remoteExitCode(err) == remoteExitNotFound {
+			return nil, errs.NotFound("session '%s' not found", name)
+		}
+		return nil, fmt.Errorf("remote command failed: %w", err)
+	}
+	return out, nil
+}
+
+// writeRemoteFile returns a script snippet that replaces file in the session
+// folder with stdin.
+func writeRemoteFile(file string) string {
+	return fmt.Sprintf(`cat > "$d/%[1]s.tmp" && mv "$d/%[1]s.tmp" "$d/%[1]s"`, file)
+}
+
+// validateRemoteName checks that name is a single path component. The remote
+// project's naming rules aren't known, so anything else is left to them.
+func validateRemoteName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, "/\x00") {
+		return fmt.Errorf("invalid session name '%s'", name)
+	}
+	return nil
+}
+
+// remoteExitCode returns the exit code of a failed remote script, or -1.
+func remoteExitCode(err error) int {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
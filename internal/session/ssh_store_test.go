@@ -0,0 +1,125 @@
+package session_test
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/errs"
+	"github.com/fgrehm/clotilde/internal/session"
+)
+
+var _ = Describe("SSHStore", func() {
+	var (
+		home         string
+		clotildeRoot string
+		store        *session.SSHStore
+		local        *session.FileStore
+	)
+
+	BeforeEach(func() {
+		home = GinkgoT().TempDir()
+		clotildeRoot = filepath.Join(home, "src", "app", config.ClotildeDir)
+		Expect(os.MkdirAll(config.GetSessionsDir(clotildeRoot), 0o755)).To(Succeed())
+
+		// Run the scripts locally from the fake home, as ssh would on the remote
+		run := func(script string, stdin []byte) ([]byte, error) {
+			cmd := exec.Command("sh", "-c", script)
+			cmd.Dir = home
+			cmd.Stdin = bytes.NewReader(stdin)
+			return cmd.Output()
+		}
+		store = session.NewRemoteStore("~/src/app", run)
+		local = session.NewFileStore(clotildeRoot)
+	})
+
+	It("lists sessions by last access", func() {
+		older := session.NewSession("older", "uuid-1")
+		older.Metadata.LastAccessed = time.Now().Add(-time.Hour)
+		Expect(local.Create(older)).To(Succeed())
+		Expect(local.Create(session.NewSession("newer", "uuid-2"))).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(config.GetSessionsDir(clotildeRoot), "broken"), 0o755)).To(Succeed())
+
+		sessions, err := store.List()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sessions).To(HaveLen(2))
+		Expect(sessions[0].Name).To(Equal("newer"))
+		Expect(sessions[1].Name).To(Equal("older"))
+		Expect(sessions[1].Metadata.SessionID).To(Equal("uuid-1"))
+	})
+
+	It("lists nothing when the project has no sessions", func() {
+		empty := session.NewRemoteStore("elsewhere", func(script string, stdin []byte) ([]byte, error) {
+			cmd := exec.Command("sh", "-c", script)
+			cmd.Dir = home
+			return cmd.Output()
+		})
+		sessions, err := empty.List()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sessions).To(BeEmpty())
+	})
+
+	It("creates, updates and deletes sessions", func() {
+		sess := session.NewSession("remote-one", "uuid-3")
+		Expect(store.Create(sess)).To(Succeed())
+		Expect(errs.ExitCode(store.Create(sess))).To(Equal(errs.ExitAlreadyExists))
+
+		got, err := local.Get("remote-one")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(got.Metadata.SessionID).To(Equal("uuid-3"))
+
+		sess.Metadata.Context = "remote work"
+		Expect(store.Update(sess)).To(Succeed())
+		got, err = store.Get("remote-one")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(got.Metadata.Context).To(Equal("remote work"))
+
+		Expect(store.Exists("remote-one")).To(BeTrue())
+		Expect(store.Delete("remote-one")).To(Succeed())
+		Expect(store.Exists("remote-one")).To(BeFalse())
+		Expect(local.Exists("remote-one")).To(BeFalse())
+	})
+
+	It("reports missing sessions as not found", func() {
+		_, err := store.Get("missing")
+		Expect(errs.ExitCode(err)).To(Equal(errs.ExitNotFound))
+		Expect(errs.ExitCode(store.Delete("missing"))).To(Equal(errs.ExitNotFound))
+	})
+
+	It("rejects names that aren't a single path component", func() {
+		_, err := store.Get("../escape")
+		Expect(err).To(MatchError(ContainSubstring("invalid session name")))
+	})
+
+	It("round-trips settings and env", func() {
+		Expect(local.Create(session.NewSession("configured", "uuid-4"))).To(Succeed())
+
+		settings, err := store.LoadSettings("configured")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(settings).To(BeNil())
+
+		Expect(store.SaveSettings("configured", &session.Settings{Model: "opus"})).To(Succeed())
+		settings, err = local.LoadSettings("configured")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(settings.Model).To(Equal("opus"))
+
+		Expect(store.SaveEnv("configured", map[string]string{"TOKEN": "secret"})).To(Succeed())
+		envPath := filepath.Join(config.GetSessionDir(clotildeRoot, "configured"), session.EnvFile)
+		info, err := os.Stat(envPath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0o600)))
+
+		env, err := store.LoadEnv("configured")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(env).To(Equal(map[string]string{"TOKEN": "secret"}))
+
+		Expect(store.SaveEnv("configured", nil)).To(Succeed())
+		Expect(envPath).NotTo(BeAnExistingFile())
+	})
+})
@@ -1,12 +1,20 @@
 package session
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
+	"sync"
 
 	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/crypt"
+	"github.com/fgrehm/clotilde/internal/errs"
+	"github.com/fgrehm/clotilde/internal/events"
+	"github.com/fgrehm/clotilde/internal/timing"
 	"github.com/fgrehm/clotilde/internal/util"
 )
 
@@ -43,22 +51,83 @@ type Store interface {
 
 	// SaveSettings saves settings.json for a session
 	SaveSettings(name string, settings *Settings) error
+
+	// LoadEnv loads the env file for a session (returns an empty map if not exists)
+	LoadEnv(name string) (map[string]string, error)
+
+	// SaveEnv saves the env file for a session, removing it when env is empty
+	SaveEnv(name string, env map[string]string) error
 }
 
 // FileStore implements Store using the filesystem.
 type FileStore struct {
 	clotildeRoot string
+	rules        NameRules
+	encrypt      bool // Seal contexts when writing metadata
 }
 
 // NewFileStore creates a new FileStore.
+// New session names are validated with the naming rules from config.json; an
+// invalid or unreadable config falls back to DefaultNameRules (commands that
+// create sessions report config errors via LoadNameRules). Existing sessions
+// only need a path-safe name, see ValidateStoredName.
 func NewFileStore(clotildeRoot string) *FileStore {
+	settings := loadStoreSettings(clotildeRoot)
 	return &FileStore{
 		clotildeRoot: clotildeRoot,
+		rules:        settings.rules,
+		encrypt:      settings.encrypt,
+	}
+}
+
+// storeSettings is what a FileStore reads from the global and project
+// configs.
+type storeSettings struct {
+	stamp   string // Identifies the config files read, see configStamp
+	rules   NameRules
+	encrypt bool
+}
+
+// storeSettingsCache keeps the storeSettings of each project, so the stores a
+// command creates along the way don't parse the configs again.
+var storeSettingsCache sync.Map
+
+// loadStoreSettings returns the storeSettings of the project at clotildeRoot,
+// reading the configs again only when one of them changed.
+func loadStoreSettings(clotildeRoot string) storeSettings {
+	stamp := configStamp(config.GlobalConfigPath(), config.GetConfigPath(clotildeRoot))
+	if cached, ok := storeSettingsCache.Load(clotildeRoot); ok && cached.(storeSettings).stamp == stamp {
+		return cached.(storeSettings)
+	}
+
+	settings := storeSettings{stamp: stamp}
+	var err error
+	if settings.rules, err = LoadNameRules(clotildeRoot); err != nil {
+		settings.rules = DefaultNameRules
+	}
+	settings.encrypt, _ = config.EncryptionEnabled(clotildeRoot) //nolint:errcheck // config errors are reported by the commands that read it
+	storeSettingsCache.Store(clotildeRoot, settings)
+	return settings
+}
+
+// configStamp identifies the current version of the files at paths by their
+// size and modification time, which is cheaper than reading them.
+func configStamp(paths ...string) string {
+	var stamp strings.Builder
+	for _, path := range paths {
+		if info, err := os.Stat(path); err == nil {
+			fmt.Fprintf(&stamp, "%s:%d:%d;", path, info.Size(), info.ModTime().UnixNano())
+		} else {
+			fmt.Fprintf(&stamp, "%s:-;", path)
+		}
 	}
+	return stamp.String()
 }
 
 // List returns all sessions, sorted by lastAccessed (most recent first).
 func (fs *FileStore) List() ([]*Session, error) {
+	defer timing.Track("store list")()
+
 	sessionsDir := config.GetSessionsDir(fs.clotildeRoot)
 
 	entries, err := os.ReadDir(sessionsDir)
@@ -93,13 +162,13 @@ func (fs *FileStore) List() ([]*Session, error) {
 
 // Get retrieves a session by name.
 func (fs *FileStore) Get(name string) (*Session, error) {
-	if err := ValidateName(name); err != nil {
+	if err := ValidateStoredName(name); err != nil {
 		return nil, err
 	}
 
 	sessionDir := config.GetSessionDir(fs.clotildeRoot, name)
 	if !util.DirExists(sessionDir) {
-		return nil, fmt.Errorf("session '%s' not found", name)
+		return nil, errs.NotFound("session '%s' not found", name)
 	}
 
 	metadataPath := filepath.Join(sessionDir, metadataFile)
@@ -107,6 +176,7 @@ func (fs *FileStore) Get(name string) (*Session, error) {
 	if err := util.ReadJSON(metadataPath, &metadata); err != nil {
 		return nil, fmt.Errorf("failed to read session metadata: %w", err)
 	}
+	metadata.Context = openContext(metadata.Context)
 
 	return &Session{
 		Name:     name,
@@ -116,12 +186,12 @@ func (fs *FileStore) Get(name string) (*Session, error) {
 
 // Create creates a new session folder structure with metadata.
 func (fs *FileStore) Create(session *Session) error {
-	if err := ValidateName(session.Name); err != nil {
+	if err := fs.rules.Validate(session.Name); err != nil {
 		return err
 	}
 
 	if fs.Exists(session.Name) {
-		return fmt.Errorf("session '%s' already exists", session.Name)
+		return errs.AlreadyExists("session '%s' already exists", session.Name)
 	}
 
 	sessionDir := config.GetSessionDir(fs.clotildeRoot, session.Name)
@@ -130,44 +200,101 @@ func (fs *FileStore) Create(session *Session) error {
 	}
 
 	metadataPath := filepath.Join(sessionDir, metadataFile)
-	if err := util.WriteJSON(metadataPath, session.Metadata); err != nil {
+	if err := fs.writeMetadata(metadataPath, session.Metadata); err != nil {
 		return fmt.Errorf("failed to write session metadata: %w", err)
 	}
 
+	event := events.Event{Type: events.SessionCreated, Session: session.Name, SessionID: session.Metadata.SessionID}
+	if session.Metadata.IsForkedSession {
+		event.Type, event.Parent = events.SessionForked, session.Metadata.ParentSession
+	}
+	events.Record(fs.clotildeRoot, event)
+
 	return nil
 }
 
 // Update updates session metadata.
 func (fs *FileStore) Update(session *Session) error {
-	if err := ValidateName(session.Name); err != nil {
+	if err := ValidateStoredName(session.Name); err != nil {
 		return err
 	}
 
 	if !fs.Exists(session.Name) {
-		return fmt.Errorf("session '%s' not found", session.Name)
+		return errs.NotFound("session '%s' not found", session.Name)
 	}
 
 	sessionDir := config.GetSessionDir(fs.clotildeRoot, session.Name)
 	metadataPath := filepath.Join(sessionDir, metadataFile)
-	if err := util.WriteJSON(metadataPath, session.Metadata); err != nil {
+	previous := &Session{Name: session.Name}
+	_ = util.ReadJSON(metadataPath, &previous.Metadata) //nolint:errcheck // unreadable metadata is overwritten below
+	if err := fs.writeMetadata(metadataPath, session.Metadata); err != nil {
 		return fmt.Errorf("failed to update session metadata: %w", err)
 	}
 
+	if from, to := previous.Status(), session.Status(); from != to {
+		events.Record(fs.clotildeRoot, events.Event{Type: events.SessionStatus, Session: session.Name, SessionID: session.Metadata.SessionID, Status: to, From: from})
+	}
 	return nil
 }
 
 // Delete removes a session folder and all its contents.
 func (fs *FileStore) Delete(name string) error {
-	if err := ValidateName(name); err != nil {
+	if err := ValidateStoredName(name); err != nil {
 		return err
 	}
 
 	if !fs.Exists(name) {
-		return fmt.Errorf("session '%s' not found", name)
+		return errs.NotFound("session '%s' not found", name)
+	}
+
+	event := events.Event{Type: events.SessionDeleted, Session: name}
+	if sess, err := fs.Get(name); err == nil {
+		event.SessionID = sess.Metadata.SessionID
 	}
 
 	sessionDir := config.GetSessionDir(fs.clotildeRoot, name)
-	return util.RemoveAll(sessionDir)
+	if err := util.RemoveAll(sessionDir); err != nil {
+		return err
+	}
+	events.Record(fs.clotildeRoot, event)
+	return nil
+}
+
+// writeMetadata writes metadata.json, sealing the context when encryption
+// is enabled.
+func (fs *FileStore) writeMetadata(path string, metadata Metadata) error {
+	if fs.encrypt && metadata.Context != "" && !crypt.IsSealedString(metadata.Context) {
+		key, err := crypt.LoadKey()
+		if err != nil {
+			return err
+		}
+		if metadata.Context, err = crypt.SealString(key, metadata.Context); err != nil {
+			return err
+		}
+	}
+	// Hooks of other sessions may be reading it concurrently
+	data, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return err
+	}
+	return util.WriteFileAtomic(path, data)
+}
+
+// openContext decrypts a sealed context. Without the key it stays sealed, so
+// rewriting the metadata never loses it.
+func openContext(context string) string {
+	if !crypt.IsSealedString(context) {
+		return context
+	}
+	key, err := crypt.LoadKey()
+	if err != nil {
+		return context
+	}
+	plain, err := crypt.OpenString(key, context)
+	if err != nil {
+		return context
+	}
+	return plain
 }
 
 // Exists checks if a session exists.
@@ -178,7 +305,7 @@ func (fs *FileStore) Exists(name string) bool {
 
 // LoadSettings loads settings.json for a session (returns nil if not exists).
 func (fs *FileStore) LoadSettings(name string) (*Settings, error) {
-	if err := ValidateName(name); err != nil {
+	if err := ValidateStoredName(name); err != nil {
 		return nil, err
 	}
 
@@ -199,16 +326,55 @@ func (fs *FileStore) LoadSettings(name string) (*Settings, error) {
 
 // SaveSettings saves settings.json for a session.
 func (fs *FileStore) SaveSettings(name string, settings *Settings) error {
-	if err := ValidateName(name); err != nil {
+	if err := ValidateStoredName(name); err != nil {
 		return err
 	}
 
 	if !fs.Exists(name) {
-		return fmt.Errorf("session '%s' not found", name)
+		return errs.NotFound("session '%s' not found", name)
 	}
 
 	sessionDir := config.GetSessionDir(fs.clotildeRoot, name)
 	settingsPath := filepath.Join(sessionDir, settingsFile)
 
-	return util.WriteJSON(settingsPath, settings)
+	previous, readErr := os.ReadFile(settingsPath)
+	if err := util.WriteJSON(settingsPath, settings); err != nil {
+		return err
+	}
+
+	// Only changes are recorded, not the settings a session is created with
+	if current, err := os.ReadFile(settingsPath); readErr == nil && err == nil && !bytes.Equal(previous, current) {
+		event := events.Event{Type: events.SessionSettings, Session: name}
+		if sess, err := fs.Get(name); err == nil {
+			event.SessionID = sess.Metadata.SessionID
+		}
+		events.Record(fs.clotildeRoot, event)
+	}
+	return nil
+}
+
+// UpdateLocked reads a session, applies update and writes it back if update
+// reports a change, holding a lock on the session so that hooks of claude
+// processes starting at the same time can't overwrite each other's changes.
+// Sessions are locked one at a time, so hooks can't deadlock.
+func UpdateLocked(clotildeRoot string, store Store, name string, update func(*Session) bool) error {
+	sess, err := store.Get(name)
+	if err != nil {
+		return fmt.Errorf("session '%s' not found: %w", name, err)
+	}
+	lockPath := filepath.Join(config.GetSessionDir(clotildeRoot, sess.Name), "hook")
+	return util.WithFileLock(lockPath, func() error {
+		// Re-read under the lock: another hook may have changed it since
+		sess, err := store.Get(name)
+		if err != nil {
+			return fmt.Errorf("session '%s' not found: %w", name, err)
+		}
+		if !update(sess) {
+			return nil
+		}
+		if err := store.Update(sess); err != nil {
+			return fmt.Errorf("failed to update session metadata: %w", err)
+		}
+		return nil
+	})
 }
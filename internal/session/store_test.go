@@ -1,13 +1,18 @@
 package session_test
 
 import (
+	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/crypt"
+	"github.com/fgrehm/clotilde/internal/errs"
+	"github.com/fgrehm/clotilde/internal/events"
 	"github.com/fgrehm/clotilde/internal/session"
 	"github.com/fgrehm/clotilde/internal/util"
 )
@@ -55,6 +60,49 @@ var _ = Describe("FileStore", func() {
 			err = store.Create(s)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("already exists"))
+			Expect(errs.ExitCode(err)).To(Equal(errs.ExitAlreadyExists))
+		})
+	})
+
+	Describe("Legacy previousSessionIds", func() {
+		It("migrates the flat ID list to previousSessions", func() {
+			sessionDir := config.GetSessionDir(clotildeRoot, "legacy")
+			Expect(util.EnsureDir(sessionDir)).To(Succeed())
+			legacy := `{"name": "legacy", "sessionId": "uuid-3", "previousSessionIds": ["uuid-1", "uuid-2"]}`
+			Expect(util.WriteFile(filepath.Join(sessionDir, "metadata.json"), []byte(legacy))).To(Succeed())
+
+			retrieved, err := store.Get("legacy")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(retrieved.Metadata.PreviousSessions).To(Equal([]session.PreviousSession{
+				{SessionID: "uuid-1"},
+				{SessionID: "uuid-2"},
+			}))
+
+			Expect(store.Update(retrieved)).To(Succeed())
+			data, err := util.ReadFile(filepath.Join(sessionDir, "metadata.json"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(ContainSubstring(`"previousSessions"`))
+			Expect(string(data)).NotTo(ContainSubstring(`"previousSessionIds"`))
+		})
+
+		It("fills in when and why from the rotation history list", func() {
+			sessionDir := config.GetSessionDir(clotildeRoot, "legacy")
+			Expect(util.EnsureDir(sessionDir)).To(Succeed())
+			legacy := `{"name": "legacy", "sessionId": "uuid-3", "previousSessionIds": ["uuid-1", "uuid-2"],
+				"history": [{"sessionId": "uuid-2", "supersededAt": "2026-01-02T03:04:05Z", "reason": "clear", "transcript": "snapshot"}]}`
+			Expect(util.WriteFile(filepath.Join(sessionDir, "metadata.json"), []byte(legacy))).To(Succeed())
+
+			retrieved, err := store.Get("legacy")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(retrieved.Metadata.PreviousSessions).To(Equal([]session.PreviousSession{
+				{SessionID: "uuid-1"},
+				{SessionID: "uuid-2", SupersededAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), Reason: session.RotationClear, Detached: session.TranscriptSnapshot},
+			}))
+
+			Expect(store.Update(retrieved)).To(Succeed())
+			data, err := util.ReadFile(filepath.Join(sessionDir, "metadata.json"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).NotTo(ContainSubstring(`"history"`))
 		})
 	})
 
@@ -71,6 +119,42 @@ var _ = Describe("FileStore", func() {
 			Expect(retrieved.Metadata.Context).To(Equal("working on ticket GH-123"))
 		})
 
+		It("should encrypt context on disk when encryption is enabled", func() {
+			GinkgoT().Setenv("XDG_CONFIG_HOME", filepath.Join(tempDir, "xdg"))
+			_, _, err := crypt.EnsureKey()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.SaveProjectEncryption(clotildeRoot, true)).To(Succeed())
+			store = session.NewFileStore(clotildeRoot)
+
+			s := session.NewSession("secret-session", "uuid-secret")
+			s.Metadata.Context = "acquisition of ACME"
+			Expect(store.Create(s)).To(Succeed())
+
+			data, err := os.ReadFile(filepath.Join(config.GetSessionDir(clotildeRoot, "secret-session"), "metadata.json"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).NotTo(ContainSubstring("ACME"))
+
+			retrieved, err := store.Get("secret-session")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(retrieved.Metadata.Context).To(Equal("acquisition of ACME"))
+		})
+
+		It("picks up encryption turned on after an earlier store read the config", func() {
+			GinkgoT().Setenv("XDG_CONFIG_HOME", filepath.Join(tempDir, "xdg"))
+			_, _, err := crypt.EnsureKey()
+			Expect(err).NotTo(HaveOccurred())
+			_ = session.NewFileStore(clotildeRoot)
+			Expect(config.SaveProjectEncryption(clotildeRoot, true)).To(Succeed())
+
+			s := session.NewSession("late-secret", "uuid-late")
+			s.Metadata.Context = "acquisition of ACME"
+			Expect(session.NewFileStore(clotildeRoot).Create(s)).To(Succeed())
+
+			data, err := os.ReadFile(filepath.Join(config.GetSessionDir(clotildeRoot, "late-secret"), "metadata.json"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).NotTo(ContainSubstring("ACME"))
+		})
+
 		It("should omit context from JSON when empty", func() {
 			s := session.NewSession("no-ctx-session", "uuid-no-ctx")
 
@@ -99,11 +183,27 @@ var _ = Describe("FileStore", func() {
 			Expect(retrieved.Metadata.LastAccessed).To(BeTemporally("~", s.Metadata.LastAccessed, time.Millisecond))
 		})
 
+		It("records status changes in the event log", func() {
+			s := session.NewSession("test-session", "uuid-123")
+			Expect(store.Create(s)).To(Succeed())
+
+			s.Metadata.Status = session.StatusArchived
+			Expect(store.Update(s)).To(Succeed())
+			s.UpdateLastAccessed()
+			Expect(store.Update(s)).To(Succeed())
+
+			data, err := os.ReadFile(events.LogPath(clotildeRoot))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(ContainSubstring(`"type":"session.status","session":"test-session","sessionId":"uuid-123","status":"archived","from":"idle"`))
+			Expect(strings.Count(string(data), "session.status")).To(Equal(1))
+		})
+
 		It("should error if session doesn't exist", func() {
 			s := session.NewSession("nonexistent", "uuid")
 			err := store.Update(s)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("not found"))
+			Expect(errs.ExitCode(err)).To(Equal(errs.ExitNotFound))
 		})
 	})
 
@@ -127,6 +227,36 @@ var _ = Describe("FileStore", func() {
 		})
 	})
 
+	Describe("Tightened naming rules", func() {
+		It("still lists, updates and deletes existing sessions", func() {
+			Expect(os.WriteFile(config.GetConfigPath(clotildeRoot), []byte(`{"naming": {"case": "any", "extraChars": "_"}}`), 0o644)).To(Succeed())
+			Expect(session.NewFileStore(clotildeRoot).Create(session.NewSession("My_Feature", "uuid-123"))).To(Succeed())
+
+			Expect(os.WriteFile(config.GetConfigPath(clotildeRoot), []byte(`{}`), 0o644)).To(Succeed())
+			store = session.NewFileStore(clotildeRoot)
+			Expect(store.Create(session.NewSession("Other_Feature", "uuid-456"))).To(MatchError(session.ErrInvalidName))
+
+			sessions, err := store.List()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sessions).To(HaveLen(1))
+			Expect(sessions[0].Name).To(Equal("My_Feature"))
+
+			sessions[0].Metadata.Context = "still here"
+			Expect(store.Update(sessions[0])).To(Succeed())
+			Expect(store.SaveEnv("My_Feature", map[string]string{"FOO": "bar"})).To(Succeed())
+			Expect(store.Delete("My_Feature")).To(Succeed())
+			Expect(store.Exists("My_Feature")).To(BeFalse())
+		})
+
+		It("rejects names that escape the sessions directory", func() {
+			for _, name := range []string{"", ".", "..", "../outside", "a/b"} {
+				_, err := store.Get(name)
+				Expect(err).To(MatchError(session.ErrInvalidName), name)
+				Expect(store.Delete(name)).To(MatchError(session.ErrInvalidName), name)
+			}
+		})
+	})
+
 	Describe("Exists", func() {
 		It("should return true if session exists", func() {
 			s := session.NewSession("test-session", "uuid-123")
@@ -201,6 +331,16 @@ var _ = Describe("FileStore", func() {
 			Expect(loaded.Permissions.Deny).To(ContainElement("Read(./.env)"))
 		})
 
+		It("records settings changes, not the first save, in the event log", func() {
+			Expect(store.SaveSettings("test-session", &session.Settings{Model: "sonnet"})).To(Succeed())
+			Expect(store.SaveSettings("test-session", &session.Settings{Model: "sonnet"})).To(Succeed())
+			Expect(store.SaveSettings("test-session", &session.Settings{Model: "opus"})).To(Succeed())
+
+			data, err := os.ReadFile(events.LogPath(clotildeRoot))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(strings.Count(string(data), `"type":"session.settings","session":"test-session","sessionId":"uuid-123"`)).To(Equal(1))
+		})
+
 		It("should return nil if settings don't exist", func() {
 			loaded, err := store.LoadSettings("test-session")
 			Expect(err).NotTo(HaveOccurred())
@@ -208,6 +348,62 @@ var _ = Describe("FileStore", func() {
 		})
 	})
 
+	Describe("Env operations", func() {
+		BeforeEach(func() {
+			s := session.NewSession("test-session", "uuid-123")
+			err := store.Create(s)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should save and load env", func() {
+			err := store.SaveEnv("test-session", map[string]string{"TOKEN": "secret", "API_URL": "http://x"})
+			Expect(err).NotTo(HaveOccurred())
+
+			env, err := store.LoadEnv("test-session")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env).To(Equal(map[string]string{"TOKEN": "secret", "API_URL": "http://x"}))
+		})
+
+		It("should return an empty env if the file doesn't exist", func() {
+			env, err := store.LoadEnv("test-session")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env).To(BeEmpty())
+		})
+
+		It("should reject multi-line values", func() {
+			err := store.SaveEnv("test-session", map[string]string{"KEY": "a\nb"})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should read back values with surrounding quotes or spaces exactly", func() {
+			env := map[string]string{
+				"PLAIN":   "a b",
+				"DOUBLE":  `"x"`,
+				"SINGLE":  "'x'",
+				"MIXED":   `'x"`,
+				"BOTH":    `"it's"`,
+				"LEADING": "  padded",
+				"TRAIL":   "padded\t ",
+				"QUOTE":   `"`,
+			}
+			Expect(store.SaveEnv("test-session", env)).To(Succeed())
+
+			loaded, err := store.LoadEnv("test-session")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(loaded).To(Equal(env))
+			Expect(string(session.FormatEnv(map[string]string{"PLAIN": "a b"}))).To(Equal("PLAIN=a b\n"))
+		})
+
+		It("should parse hand-edited files", func() {
+			env, err := session.ParseEnv([]byte("# comment\n\nexport A=1\nB = \"two words\"\nC='x=y'\nD=\n"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env).To(Equal(map[string]string{"A": "1", "B": "two words", "C": "x=y", "D": ""}))
+
+			_, err = session.ParseEnv([]byte("A=1\nnot an assignment\n"))
+			Expect(err).To(MatchError(ContainSubstring("line 2")))
+		})
+	})
+
 	Describe("File existence checks", func() {
 		It("should check if settings file exists", func() {
 			s := session.NewSession("test-session", "uuid-123")
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+
+	"github.com/fgrehm/clotilde/internal/config"
 )
 
 const (
@@ -13,6 +15,13 @@ const (
 
 	// MaxNameLength is the maximum allowed session name length
 	MaxNameLength = 64
+
+	// MaxConfigurableNameLength is the upper bound for naming.maxLength in config
+	MaxConfigurableNameLength = 128
+
+	// allowedExtraChars are the characters naming.extraChars may enable.
+	// Anything else (e.g. "/") could escape the sessions directory or break hooks.
+	allowedExtraChars = "_."
 )
 
 var (
@@ -26,19 +35,80 @@ var (
 	ErrInvalidName = errors.New("invalid session name")
 )
 
-// ValidateName checks if a session name is valid.
+// NameRules controls which session names are accepted.
+type NameRules struct {
+	MaxLength      int    // Maximum name length
+	AllowUppercase bool   // Accept uppercase letters (case policy "any")
+	ExtraChars     string // Characters allowed in addition to [a-z0-9-], a subset of "_."
+}
+
+// DefaultNameRules are the rules used when config.json has no "naming" block:
+// lowercase alphanumeric with hyphens, at most MaxNameLength characters.
+var DefaultNameRules = NameRules{MaxLength: MaxNameLength}
+
+// NameRulesFromConfig builds NameRules from a config "naming" block, filling in
+// defaults and rejecting unsafe values.
+func NameRulesFromConfig(naming config.Naming) (NameRules, error) {
+	rules := DefaultNameRules
+
+	if naming.MaxLength != 0 {
+		if naming.MaxLength < MinNameLength || naming.MaxLength > MaxConfigurableNameLength {
+			return rules, fmt.Errorf("naming.maxLength must be between %d and %d", MinNameLength, MaxConfigurableNameLength)
+		}
+		rules.MaxLength = naming.MaxLength
+	}
+
+	switch naming.Case {
+	case "", "lower":
+	case "any":
+		rules.AllowUppercase = true
+	default:
+		return rules, fmt.Errorf("naming.case must be \"lower\" or \"any\", got %q", naming.Case)
+	}
+
+	for _, r := range naming.ExtraChars {
+		if !strings.ContainsRune(allowedExtraChars, r) {
+			return rules, fmt.Errorf("naming.extraChars may only contain %q, got %q", allowedExtraChars, r)
+		}
+	}
+	rules.ExtraChars = naming.ExtraChars
+
+	return rules, nil
+}
+
+// LoadNameRules returns the naming rules from the global and project configs.
+func LoadNameRules(clotildeRoot string) (NameRules, error) {
+	naming, err := config.MergedNaming(clotildeRoot)
+	if err != nil {
+		return DefaultNameRules, err
+	}
+	return NameRulesFromConfig(naming)
+}
+
+// Validate checks if a session name is valid under these rules.
 // Returns an error if the name is invalid, with details about why.
-func ValidateName(name string) error {
+func (r NameRules) Validate(name string) error {
 	if len(name) < MinNameLength {
 		return fmt.Errorf("%w: name must be at least %d characters", ErrInvalidName, MinNameLength)
 	}
 
-	if len(name) > MaxNameLength {
-		return fmt.Errorf("%w: name must be at most %d characters", ErrInvalidName, MaxNameLength)
+	if len(name) > r.MaxLength {
+		return fmt.Errorf("%w: name must be at most %d characters", ErrInvalidName, r.MaxLength)
 	}
 
-	if !sessionNameRegex.MatchString(name) {
-		return fmt.Errorf("%w: name must be lowercase alphanumeric with hyphens, starting and ending with alphanumeric", ErrInvalidName)
+	if r.isDefaultCharset() {
+		if !sessionNameRegex.MatchString(name) {
+			return fmt.Errorf("%w: name must be lowercase alphanumeric with hyphens, starting and ending with alphanumeric", ErrInvalidName)
+		}
+	} else {
+		for _, c := range name {
+			if !r.allowed(c) {
+				return fmt.Errorf("%w: name contains disallowed character %q (allowed: %s)", ErrInvalidName, c, r.describeCharset())
+			}
+		}
+		if !isAlnum(rune(name[0])) || !isAlnum(rune(name[len(name)-1])) {
+			return fmt.Errorf("%w: name must start and end with an alphanumeric character", ErrInvalidName)
+		}
 	}
 
 	// Check for consecutive hyphens
@@ -48,3 +118,73 @@ func ValidateName(name string) error {
 
 	return nil
 }
+
+// ValidateStoredName checks that name can only address a folder directly
+// inside the sessions directory. Existing sessions are looked up with this
+// instead of NameRules, so tightening the naming config doesn't hide them.
+func ValidateStoredName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, "/\\\x00") {
+		return fmt.Errorf("%w: %q is not a usable session folder name", ErrInvalidName, name)
+	}
+	return nil
+}
+
+// Slugify converts arbitrary text (e.g. "My Feature!") into a name accepted by
+// these rules ("my-feature"): lowercased, runs of disallowed characters replaced
+// by a single hyphen, trimmed to MaxLength. Returns "" if nothing usable remains.
+func (r NameRules) Slugify(text string) string {
+	var b strings.Builder
+	pendingHyphen := false
+	for _, c := range strings.ToLower(text) {
+		if c == '-' || !r.allowed(c) {
+			pendingHyphen = b.Len() > 0
+			continue
+		}
+		if pendingHyphen {
+			b.WriteByte('-')
+			pendingHyphen = false
+		}
+		b.WriteRune(c)
+	}
+
+	slug := b.String()
+	if len(slug) > r.MaxLength {
+		slug = slug[:r.MaxLength]
+	}
+	slug = strings.TrimRight(slug, "-"+r.ExtraChars)
+	slug = strings.TrimLeft(slug, r.ExtraChars)
+	if len(slug) < MinNameLength {
+		return ""
+	}
+	return slug
+}
+
+func (r NameRules) isDefaultCharset() bool {
+	return !r.AllowUppercase && r.ExtraChars == ""
+}
+
+func (r NameRules) allowed(c rune) bool {
+	switch {
+	case c >= 'a' && c <= 'z', c >= '0' && c <= '9', c == '-':
+		return true
+	case c >= 'A' && c <= 'Z':
+		return r.AllowUppercase
+	default:
+		return strings.ContainsRune(r.ExtraChars, c)
+	}
+}
+
+func (r NameRules) describeCharset() string {
+	charset := "a-z, 0-9, -"
+	if r.AllowUppercase {
+		charset = "a-z, A-Z, 0-9, -"
+	}
+	for _, c := range r.ExtraChars {
+		charset += ", " + string(c)
+	}
+	return charset
+}
+
+func isAlnum(c rune) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
@@ -22,7 +22,7 @@ func FuzzValidateName(f *testing.F) {
 	}
 
 	f.Fuzz(func(t *testing.T, name string) {
-		err := session.ValidateName(name)
+		err := session.DefaultNameRules.Validate(name)
 
 		if err == nil {
 			// Valid names must satisfy all invariants
@@ -1,13 +1,16 @@
 package session_test
 
 import (
+	"strings"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	"github.com/fgrehm/clotilde/internal/config"
 	"github.com/fgrehm/clotilde/internal/session"
 )
 
-var _ = Describe("ValidateName", func() {
+var _ = Describe("DefaultNameRules.Validate", func() {
 	Context("valid names", func() {
 		It("should accept simple lowercase names", func() {
 			validNames := []string{
@@ -20,7 +23,7 @@ var _ = Describe("ValidateName", func() {
 			}
 
 			for _, name := range validNames {
-				err := session.ValidateName(name)
+				err := session.DefaultNameRules.Validate(name)
 				Expect(err).NotTo(HaveOccurred(), "name %s should be valid", name)
 			}
 		})
@@ -34,7 +37,7 @@ var _ = Describe("ValidateName", func() {
 			}
 
 			for _, name := range validNames {
-				err := session.ValidateName(name)
+				err := session.DefaultNameRules.Validate(name)
 				Expect(err).NotTo(HaveOccurred(), "name %s should be valid", name)
 			}
 		})
@@ -42,14 +45,14 @@ var _ = Describe("ValidateName", func() {
 		It("should accept maximum length names", func() {
 			name := "a123456789012345678901234567890123456789012345678901234567890123"
 			Expect(len(name)).To(Equal(64))
-			err := session.ValidateName(name)
+			err := session.DefaultNameRules.Validate(name)
 			Expect(err).NotTo(HaveOccurred())
 		})
 	})
 
 	Context("invalid names", func() {
 		It("should reject names that are too short", func() {
-			err := session.ValidateName("a")
+			err := session.DefaultNameRules.Validate("a")
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("at least 2 characters"))
 		})
@@ -57,7 +60,7 @@ var _ = Describe("ValidateName", func() {
 		It("should reject names that are too long", func() {
 			name := "a1234567890123456789012345678901234567890123456789012345678901234"
 			Expect(len(name)).To(Equal(65))
-			err := session.ValidateName(name)
+			err := session.DefaultNameRules.Validate(name)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("at most 64 characters"))
 		})
@@ -70,7 +73,7 @@ var _ = Describe("ValidateName", func() {
 			}
 
 			for _, name := range invalidNames {
-				err := session.ValidateName(name)
+				err := session.DefaultNameRules.Validate(name)
 				Expect(err).To(HaveOccurred(), "name %s should be invalid", name)
 			}
 		})
@@ -84,7 +87,7 @@ var _ = Describe("ValidateName", func() {
 			}
 
 			for _, name := range invalidNames {
-				err := session.ValidateName(name)
+				err := session.DefaultNameRules.Validate(name)
 				Expect(err).To(HaveOccurred(), "name %s should be invalid", name)
 			}
 		})
@@ -97,7 +100,7 @@ var _ = Describe("ValidateName", func() {
 			}
 
 			for _, name := range invalidNames {
-				err := session.ValidateName(name)
+				err := session.DefaultNameRules.Validate(name)
 				Expect(err).To(HaveOccurred(), "name %s should be invalid", name)
 				Expect(err.Error()).To(ContainSubstring("consecutive hyphens"))
 			}
@@ -113,9 +116,80 @@ var _ = Describe("ValidateName", func() {
 			}
 
 			for _, name := range invalidNames {
-				err := session.ValidateName(name)
+				err := session.DefaultNameRules.Validate(name)
 				Expect(err).To(HaveOccurred(), "name %s should be invalid", name)
 			}
 		})
 	})
 })
+
+var _ = Describe("NameRules", func() {
+	Describe("NameRulesFromConfig", func() {
+		It("uses the defaults for an empty config", func() {
+			rules, err := session.NameRulesFromConfig(config.Naming{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rules).To(Equal(session.DefaultNameRules))
+		})
+
+		It("rejects unsafe or out of range values", func() {
+			for _, naming := range []config.Naming{
+				{MaxLength: 1},
+				{MaxLength: session.MaxConfigurableNameLength + 1},
+				{Case: "upper"},
+				{ExtraChars: "/"},
+				{ExtraChars: " "},
+			} {
+				_, err := session.NameRulesFromConfig(naming)
+				Expect(err).To(HaveOccurred(), "naming %+v should be rejected", naming)
+			}
+		})
+	})
+
+	Describe("Validate", func() {
+		It("accepts uppercase and extra characters when configured", func() {
+			rules, err := session.NameRulesFromConfig(config.Naming{Case: "any", ExtraChars: "_."})
+			Expect(err).NotTo(HaveOccurred())
+
+			for _, name := range []string{"MySession", "feature_x", "v1.2"} {
+				Expect(rules.Validate(name)).To(Succeed(), "name %s should be valid", name)
+			}
+			for _, name := range []string{"_leading", "trailing.", "has space", "a--b"} {
+				Expect(rules.Validate(name)).To(MatchError(session.ErrInvalidName), "name %s should be invalid", name)
+			}
+		})
+
+		It("enforces a custom max length", func() {
+			rules, err := session.NameRulesFromConfig(config.Naming{MaxLength: 100})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(rules.Validate(strings.Repeat("a", 100))).To(Succeed())
+			Expect(rules.Validate(strings.Repeat("a", 101))).To(MatchError(ContainSubstring("at most 100 characters")))
+		})
+	})
+
+	Describe("Slugify", func() {
+		It("converts arbitrary text into a valid name", func() {
+			rules := session.DefaultNameRules
+			Expect(rules.Slugify("My Feature!")).To(Equal("my-feature"))
+			Expect(rules.Slugify("  fix: JIRA-123 / login  ")).To(Equal("fix-jira-123-login"))
+			Expect(rules.Slugify("snake_case--name")).To(Equal("snake-case-name"))
+		})
+
+		It("keeps configured extra characters", func() {
+			rules, err := session.NameRulesFromConfig(config.Naming{ExtraChars: "_"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rules.Slugify("_snake_case_")).To(Equal("snake_case"))
+		})
+
+		It("trims to the max length", func() {
+			slug := session.DefaultNameRules.Slugify(strings.Repeat("ab ", 40))
+			Expect(len(slug)).To(BeNumerically("<=", session.MaxNameLength))
+			Expect(session.DefaultNameRules.Validate(slug)).To(Succeed())
+		})
+
+		It("returns empty when nothing usable remains", func() {
+			Expect(session.DefaultNameRules.Slugify("!!!")).To(BeEmpty())
+			Expect(session.DefaultNameRules.Slugify("a")).To(BeEmpty())
+		})
+	})
+})
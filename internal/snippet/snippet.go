@@ -0,0 +1,187 @@
+// Package snippet stores reusable prompt snippets: short texts kept per
+// project (shared by its sessions) or globally, and injected into a session's
+// context when it's resumed with --snippet.
+package snippet
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/errs"
+)
+
+// Dir is the snippets directory name, within the clotilde root for project
+// snippets and next to the global config for global ones.
+const Dir = "snippets"
+
+// PendingFile holds, in a session's folder, the snippets to inject into the
+// launch being started. It's written before claude starts and removed after
+// it exits.
+const PendingFile = "pending-snippets.md"
+
+// ext is the file extension of stored snippets.
+const ext = ".md"
+
+// Scopes a snippet can be stored in.
+const (
+	ScopeProject = "project"
+	ScopeGlobal  = "global"
+)
+
+var nameRegex = regexp.MustCompile(`^[a-z0-9]([a-z0-9_-]*[a-z0-9])?$`)
+
+// Snippet is a stored snippet.
+type Snippet struct {
+	Name  string
+	Scope string // ScopeProject or ScopeGlobal
+	Text  string
+}
+
+// ValidateName checks that name is usable as a snippet name.
+func ValidateName(name string) error {
+	if !nameRegex.MatchString(name) {
+		return fmt.Errorf("invalid snippet name '%s' (lowercase letters, digits, hyphens and underscores)", name)
+	}
+	return nil
+}
+
+// ProjectDir returns the directory of the project's snippets.
+func ProjectDir(clotildeRoot string) string {
+	return filepath.Join(clotildeRoot, Dir)
+}
+
+// GlobalDir returns the directory of the global snippets.
+func GlobalDir() string {
+	return filepath.Join(filepath.Dir(config.GlobalConfigPath()), Dir)
+}
+
+// List returns the snippets visible from the project, sorted by name. A
+// project snippet hides a global one with the same name. Outside a project
+// (clotildeRoot is "") only global snippets are listed.
+func List(clotildeRoot string) ([]Snippet, error) {
+	byName := map[string]Snippet{}
+	if err := readDir(byName, GlobalDir(), ScopeGlobal); err != nil {
+		return nil, err
+	}
+	if clotildeRoot != "" {
+		if err := readDir(byName, ProjectDir(clotildeRoot), ScopeProject); err != nil {
+			return nil, err
+		}
+	}
+
+	snippets := make([]Snippet, 0, len(byName))
+	for _, s := range byName {
+		snippets = append(snippets, s)
+	}
+	slices.SortFunc(snippets, func(a, b Snippet) int { return strings.Compare(a.Name, b.Name) })
+	return snippets, nil
+}
+
+// readDir adds the snippets in dir to byName, replacing those already there.
+func readDir(byName map[string]Snippet, dir, scope string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read %s snippets: %w", scope, err)
+	}
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ext)
+		if !ok || entry.IsDir() || ValidateName(name) != nil {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return fmt.Errorf("failed to read snippet '%s': %w", name, err)
+		}
+		byName[name] = Snippet{Name: name, Scope: scope, Text: strings.TrimSpace(string(data))}
+	}
+	return nil
+}
+
+// Get returns the named snippet, preferring the project's over a global one.
+func Get(clotildeRoot, name string) (*Snippet, error) {
+	snippets, err := List(clotildeRoot)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range snippets {
+		if s.Name == name {
+			return &s, nil
+		}
+	}
+	return nil, errs.NotFound("snippet '%s' not found", name)
+}
+
+// Save writes a snippet to dir, replacing any snippet with the same name.
+func Save(dir, name, text string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return fmt.Errorf("snippet '%s' is empty", name)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create snippets directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name+ext), []byte(text+"\n"), 0o644); err != nil {
+		return fmt.Errorf("failed to save snippet '%s': %w", name, err)
+	}
+	return nil
+}
+
+// Delete removes a snippet from dir.
+func Delete(dir, name string) error {
+	err := os.Remove(filepath.Join(dir, name+ext))
+	if os.IsNotExist(err) {
+		return errs.NotFound("snippet '%s' not found", name)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete snippet '%s': %w", name, err)
+	}
+	return nil
+}
+
+// Resolve looks up the named snippets, in order, and renders them as the
+// text PendingFile holds.
+func Resolve(clotildeRoot string, names []string) (string, error) {
+	var b strings.Builder
+	for _, name := range names {
+		s, err := Get(clotildeRoot, name)
+		if err != nil {
+			return "", err
+		}
+		_, _ = fmt.Fprintf(&b, "Snippet %s:\n%s\n", s.Name, s.Text)
+	}
+	return b.String(), nil
+}
+
+// WritePending stores the rendered snippets for the session's next launch.
+func WritePending(sessionDir, text string) error {
+	if err := os.WriteFile(filepath.Join(sessionDir, PendingFile), []byte(text), 0o600); err != nil {
+		return fmt.Errorf("failed to write pending snippets: %w", err)
+	}
+	return nil
+}
+
+// ReadPending returns the snippets pending for the session's launch, or ""
+// when there are none.
+func ReadPending(sessionDir string) string {
+	data, err := os.ReadFile(filepath.Join(sessionDir, PendingFile))
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// ClearPending removes the session's pending snippets.
+func ClearPending(sessionDir string) {
+	_ = os.Remove(filepath.Join(sessionDir, PendingFile))
+}
@@ -0,0 +1,67 @@
+package snippet_test
+
+import (
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/snippet"
+)
+
+var _ = Describe("Snippets", func() {
+	var tempDir, clotildeRoot string
+
+	BeforeEach(func() {
+		tempDir = GinkgoT().TempDir()
+		GinkgoT().Setenv("XDG_CONFIG_HOME", filepath.Join(tempDir, "config"))
+		clotildeRoot = filepath.Join(tempDir, ".claude", "clotilde")
+	})
+
+	It("keeps global snippets next to the global config", func() {
+		Expect(snippet.GlobalDir()).To(Equal(filepath.Join(tempDir, "config", "clotilde", "snippets")))
+	})
+
+	It("lets project snippets hide global ones", func() {
+		Expect(snippet.Save(snippet.GlobalDir(), "review", "global review")).To(Succeed())
+		Expect(snippet.Save(snippet.GlobalDir(), "deploy", "global deploy")).To(Succeed())
+		Expect(snippet.Save(snippet.ProjectDir(clotildeRoot), "review", "project review")).To(Succeed())
+
+		snippets, err := snippet.List(clotildeRoot)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(snippets).To(Equal([]snippet.Snippet{
+			{Name: "deploy", Scope: snippet.ScopeGlobal, Text: "global deploy"},
+			{Name: "review", Scope: snippet.ScopeProject, Text: "project review"},
+		}))
+
+		global, err := snippet.List("")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(global).To(HaveLen(2))
+		Expect(global[1].Text).To(Equal("global review"))
+	})
+
+	It("renders the named snippets in order", func() {
+		Expect(snippet.Save(snippet.ProjectDir(clotildeRoot), "a", "first")).To(Succeed())
+		Expect(snippet.Save(snippet.ProjectDir(clotildeRoot), "b", "second\n")).To(Succeed())
+
+		text, err := snippet.Resolve(clotildeRoot, []string{"b", "a"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(text).To(Equal("Snippet b:\nsecond\nSnippet a:\nfirst\n"))
+
+		_, err = snippet.Resolve(clotildeRoot, []string{"missing"})
+		Expect(err).To(MatchError(ContainSubstring("snippet 'missing' not found")))
+	})
+
+	It("reports deleting unknown snippets", func() {
+		Expect(snippet.Delete(snippet.ProjectDir(clotildeRoot), "missing")).To(MatchError(ContainSubstring("not found")))
+	})
+
+	It("stores and clears pending snippets", func() {
+		sessionDir := GinkgoT().TempDir()
+		Expect(snippet.ReadPending(sessionDir)).To(BeEmpty())
+		Expect(snippet.WritePending(sessionDir, "Snippet a:\nfirst\n")).To(Succeed())
+		Expect(snippet.ReadPending(sessionDir)).To(Equal("Snippet a:\nfirst\n"))
+		snippet.ClearPending(sessionDir)
+		Expect(snippet.ReadPending(sessionDir)).To(BeEmpty())
+	})
+})
@@ -0,0 +1,201 @@
+// Package team shares session metadata with teammates through a shared
+// directory, so 'clotilde list --team' can show which named sessions exist
+// for a project. Only metadata is written there; transcripts, settings and
+// env files never leave the machine.
+package team
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/util"
+)
+
+// ErrNotConfigured is returned by Open when no team.dir is set.
+var ErrNotConfigured = errors.New("team sharing isn't configured (set team.dir in .claude/clotilde/config.json)")
+
+// Entry is the shared metadata of one session.
+type Entry struct {
+	Name         string    `json:"name"`
+	Context      string    `json:"context,omitempty"`
+	Parent       string    `json:"parent,omitempty"` // Set for forks
+	Created      time.Time `json:"created"`
+	LastAccessed time.Time `json:"lastAccessed"`
+}
+
+// Snapshot is what one user shares for a project.
+type Snapshot struct {
+	User      string    `json:"user"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	Sessions  []Entry   `json:"sessions"`
+}
+
+// Share is a resolved team config: the project's folder in the shared
+// directory and the user publishing to it.
+type Share struct {
+	Dir     string // Shared directory
+	Project string // Project folder within Dir
+	User    string // Snapshot file name within the project folder
+
+	// OmitContexts keeps contexts out of the snapshot; set when the project
+	// encrypts session data
+	OmitContexts bool
+
+	// Redactor masks the config's "redact" patterns in published text
+	Redactor *config.Redactor
+}
+
+// Open resolves the team config for the project at clotildeRoot, defaulting
+// the project to the project directory's name and the user to $USER.
+func Open(clotildeRoot string) (Share, error) {
+	cfg, err := config.MergedTeam(clotildeRoot)
+	if err != nil {
+		return Share{}, err
+	}
+	if cfg.Dir == "" {
+		return Share{}, ErrNotConfigured
+	}
+
+	share := Share{Dir: cfg.Dir, Project: cfg.Project, User: cfg.User}
+	if rest, ok := strings.CutPrefix(share.Dir, "~/"); ok {
+		homeDir, err := util.HomeDir()
+		if err != nil {
+			return Share{}, err
+		}
+		share.Dir = filepath.Join(homeDir, rest)
+	}
+	if share.Project == "" {
+		share.Project = filepath.Base(config.ProjectRootOf(clotildeRoot))
+	}
+	if share.User == "" {
+		share.User = currentUser()
+	}
+
+	if share.OmitContexts, err = config.EncryptionEnabled(clotildeRoot); err != nil {
+		return Share{}, err
+	}
+	if share.Redactor, err = config.LoadRedactor(clotildeRoot); err != nil {
+		return Share{}, err
+	}
+
+	if err := validateComponent("team.project", share.Project); err != nil {
+		return Share{}, err
+	}
+	if err := validateComponent("team.user", share.User); err != nil {
+		return Share{}, err
+	}
+	return share, nil
+}
+
+// Publish replaces the user's snapshot with sessions. Incognito sessions are
+// never shared, nor are contexts when OmitContexts is set. Names and contexts
+// go through the Redactor first.
+func (s Share) Publish(sessions []*session.Session, now time.Time) error {
+	snapshot := Snapshot{User: s.User, UpdatedAt: now, Sessions: []Entry{}}
+	for _, sess := range sessions {
+		if sess.Metadata.IsIncognito {
+			continue
+		}
+		entry := Entry{
+			Name:         s.Redactor.Redact(sess.Name),
+			Parent:       s.Redactor.Redact(sess.Metadata.ParentSession),
+			Created:      sess.Metadata.Created,
+			LastAccessed: sess.Metadata.LastAccessed,
+		}
+		if !s.OmitContexts {
+			entry.Context = s.Redactor.Redact(sess.Metadata.Context)
+		}
+		snapshot.Sessions = append(snapshot.Sessions, entry)
+	}
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write to a temp file, then rename, so teammates never read a partial file
+	projectDir := filepath.Join(s.Dir, s.Project)
+	if err := util.EnsureDir(projectDir); err != nil {
+		return fmt.Errorf("failed to create shared project directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(projectDir, "."+s.User+".*")
+	if err != nil {
+		return fmt.Errorf("failed to share sessions: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to share sessions: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to share sessions: %w", err)
+	}
+	// CreateTemp makes the file private; teammates need to read it
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return fmt.Errorf("failed to share sessions: %w", err)
+	}
+	return os.Rename(tmp.Name(), s.snapshotPath())
+}
+
+// Load returns every user's snapshot for the project, sorted by user. Files
+// that can't be parsed are skipped.
+func (s Share) Load() ([]Snapshot, error) {
+	entries, err := os.ReadDir(filepath.Join(s.Dir, s.Project))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read shared sessions: %w", err)
+	}
+
+	var snapshots []Snapshot
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || filepath.Ext(name) != ".json" {
+			continue
+		}
+		var snapshot Snapshot
+		if err := util.ReadJSON(filepath.Join(s.Dir, s.Project, name), &snapshot); err != nil {
+			continue
+		}
+		snapshot.User = strings.TrimSuffix(name, ".json")
+		snapshots = append(snapshots, snapshot)
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].User < snapshots[j].User })
+	return snapshots, nil
+}
+
+func (s Share) snapshotPath() string {
+	return filepath.Join(s.Dir, s.Project, s.User+".json")
+}
+
+// currentUser returns the login name, falling back to $USER and "unknown".
+func currentUser() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		// Windows usernames are DOMAIN\name
+		return filepath.Base(strings.ReplaceAll(u.Username, `\`, "/"))
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return "unknown"
+}
+
+// validateComponent checks that value can be used as a single file name.
+func validateComponent(field, value string) error {
+	if value == "." || value == ".." || strings.HasPrefix(value, ".") || strings.ContainsAny(value, `/\`+"\x00") {
+		return fmt.Errorf("invalid %s '%s' (must be a plain file name)", field, value)
+	}
+	return nil
+}
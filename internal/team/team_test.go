@@ -0,0 +1,110 @@
+package team_test
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/team"
+	"github.com/fgrehm/clotilde/internal/util"
+)
+
+var _ = Describe("Share", func() {
+	var (
+		clotildeRoot string
+		sharedDir    string
+	)
+
+	BeforeEach(func() {
+		tmpDir := GinkgoT().TempDir()
+		GinkgoT().Setenv("XDG_CONFIG_HOME", filepath.Join(tmpDir, "xdg"))
+		clotildeRoot = filepath.Join(tmpDir, "my-api", config.ClotildeDir)
+		Expect(util.EnsureDir(clotildeRoot)).To(Succeed())
+		sharedDir = filepath.Join(tmpDir, "shared")
+	})
+
+	configure := func(team map[string]string) {
+		Expect(util.WriteJSON(config.GetConfigPath(clotildeRoot), map[string]any{"team": team})).To(Succeed())
+	}
+
+	It("fails when no shared directory is configured", func() {
+		_, err := team.Open(clotildeRoot)
+		Expect(err).To(MatchError(team.ErrNotConfigured))
+	})
+
+	It("defaults the project to the directory name", func() {
+		configure(map[string]string{"dir": sharedDir, "user": "alice"})
+
+		share, err := team.Open(clotildeRoot)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(share).To(Equal(team.Share{Dir: sharedDir, Project: "my-api", User: "alice"}))
+	})
+
+	It("rejects names that aren't plain file names", func() {
+		configure(map[string]string{"dir": sharedDir, "user": "../alice"})
+
+		_, err := team.Open(clotildeRoot)
+		Expect(err).To(MatchError(ContainSubstring("invalid team.user")))
+	})
+
+	It("publishes metadata without incognito sessions and loads every user", func() {
+		configure(map[string]string{"dir": sharedDir, "user": "alice"})
+		share, err := team.Open(clotildeRoot)
+		Expect(err).NotTo(HaveOccurred())
+
+		visible := session.NewSession("auth-feature", "uuid-1")
+		visible.Metadata.Context = "GH-123"
+		hidden := session.NewIncognitoSession("secret", "uuid-2")
+		now := time.Now()
+		Expect(share.Publish([]*session.Session{visible, hidden}, now)).To(Succeed())
+
+		bob := team.Share{Dir: sharedDir, Project: "my-api", User: "bob"}
+		Expect(bob.Publish(nil, now)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(sharedDir, "my-api", "notes.txt"), []byte("hi"), 0o644)).To(Succeed())
+
+		snapshots, err := share.Load()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(snapshots).To(HaveLen(2))
+		Expect(snapshots[0].User).To(Equal("alice"))
+		Expect(snapshots[0].Sessions).To(HaveLen(1))
+		Expect(snapshots[0].Sessions[0].Name).To(Equal("auth-feature"))
+		Expect(snapshots[0].Sessions[0].Context).To(Equal("GH-123"))
+		Expect(snapshots[1].User).To(Equal("bob"))
+		Expect(snapshots[1].Sessions).To(BeEmpty())
+	})
+
+	It("redacts the config's redact patterns from published text", func() {
+		Expect(util.WriteJSON(config.GetConfigPath(clotildeRoot), map[string]any{
+			"team":   map[string]string{"dir": sharedDir, "user": "alice"},
+			"redact": []string{`sk-[a-z0-9]+`, `ACME`},
+		})).To(Succeed())
+		share, err := team.Open(clotildeRoot)
+		Expect(err).NotTo(HaveOccurred())
+
+		fork := session.NewSession("acme-fix", "uuid-1")
+		fork.Metadata.Context = "ACME outage, token sk-abc123"
+		fork.Metadata.ParentSession = "ACME-main"
+		Expect(share.Publish([]*session.Session{fork}, time.Now())).To(Succeed())
+
+		snapshots, err := share.Load()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(snapshots).To(HaveLen(1))
+		Expect(snapshots[0].Sessions).To(HaveLen(1))
+		entry := snapshots[0].Sessions[0]
+		Expect(entry.Name).To(Equal("acme-fix"))
+		Expect(entry.Context).To(Equal("[REDACTED] outage, token [REDACTED]"))
+		Expect(entry.Parent).To(Equal("[REDACTED]-main"))
+	})
+
+	It("loads nothing before anyone shares", func() {
+		share := team.Share{Dir: sharedDir, Project: "my-api", User: "alice"}
+		snapshots, err := share.Load()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(snapshots).To(BeEmpty())
+	})
+})
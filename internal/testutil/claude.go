@@ -4,28 +4,129 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fgrehm/clotilde/internal/util"
 )
 
+// FakeClaudeScenario scripts what a fake claude binary does when it's run,
+// in this order: record its arguments, sleep, write the transcript, run the
+// SessionStart hook, print to stderr and exit. The zero value only records
+// the arguments and exits 0.
+//
+// The session ID is taken from the arguments like Claude Code does: the
+// --session-id value, or else the --resume value.
+type FakeClaudeScenario struct {
+	// Sleep delays everything after recording the arguments, e.g. to look at
+	// a session while claude is running.
+	Sleep time.Duration
+
+	// Transcript lines are written to the session's transcript in Claude's
+	// projects directory (under CLAUDE_CONFIG_DIR or ~/.claude), as Claude
+	// Code does once a message is sent.
+	Transcript []string
+
+	// Hook is a shell command run the way Claude Code runs SessionStart
+	// hooks: with the hook's JSON input on stdin and CLAUDE_ENV_FILE set to
+	// FakeClaudeEnvFile(dir). Its stdout is appended to
+	// FakeClaudeHookOutput(dir).
+	Hook string
+	// HookSource is the input's source. Defaults to "resume" when claude was
+	// run with --resume and "startup" otherwise.
+	HookSource string
+	// HookSessionID is the input's session_id, e.g. a new UUID for a /clear.
+	// Defaults to the session ID from the arguments.
+	HookSessionID string
+
+	// Stderr is printed on stderr before exiting.
+	Stderr string
+	// ExitCode is the fake's exit code.
+	ExitCode int
+	// Signal, if set, kills the fake with that signal (e.g. "KILL") instead
+	// of exiting, as when claude crashes.
+	Signal string
+}
+
 // CreateFakeClaude creates a fake claude binary in the given directory
 // that captures all invocation arguments to a file.
 // Returns the path to the binary and the path to the args capture file.
 func CreateFakeClaude(dir string) (binaryPath, argsFile string, err error) {
+	return CreateFakeClaudeScenario(dir, FakeClaudeScenario{})
+}
+
+// CreateFakeClaudeScenario creates a fake claude binary in the given
+// directory that captures its arguments like CreateFakeClaude's and then
+// plays scenario. Creating another one in the same directory replaces it.
+func CreateFakeClaudeScenario(dir string, scenario FakeClaudeScenario) (binaryPath, argsFile string, err error) {
 	binaryPath = filepath.Join(dir, "claude")
 	argsFile = filepath.Join(dir, "claude-args.txt")
 
-	// Create a shell script that captures arguments
-	script := fmt.Sprintf(`#!/bin/bash
+	var script strings.Builder
+	fmt.Fprintf(&script, `#!/bin/bash
 echo "$@" > %s
-exit 0
-`, argsFile)
 
-	if err := os.WriteFile(binaryPath, []byte(script), 0o755); err != nil {
+session_id= source=startup prev=
+for arg in "$@"; do
+  case "$prev" in
+    --session-id) session_id="$arg" ;;
+    --resume) [ -z "$session_id" ] && session_id="$arg"; source=resume ;;
+  esac
+  prev="$arg"
+done
+transcript="${CLAUDE_CONFIG_DIR:-$HOME/.claude}/projects/$(pwd | tr '/.' '--')/$session_id.jsonl"
+`, util.ShellQuote(argsFile))
+
+	if scenario.Sleep > 0 {
+		fmt.Fprintf(&script, "sleep %s\n", strconv.FormatFloat(scenario.Sleep.Seconds(), 'f', -1, 64))
+	}
+	if len(scenario.Transcript) > 0 {
+		lines := make([]string, len(scenario.Transcript))
+		for i, line := range scenario.Transcript {
+			lines[i] = util.ShellQuote(line)
+		}
+		fmt.Fprintf(&script, "mkdir -p \"$(dirname \"$transcript\")\"\nprintf '%%s\\n' %s >> \"$transcript\"\n", strings.Join(lines, " "))
+	}
+	if scenario.Hook != "" {
+		if scenario.HookSessionID != "" {
+			fmt.Fprintf(&script, "session_id=%s\n", util.ShellQuote(scenario.HookSessionID))
+		}
+		if scenario.HookSource != "" {
+			fmt.Fprintf(&script, "source=%s\n", util.ShellQuote(scenario.HookSource))
+		}
+		fmt.Fprintf(&script, `printf '{"session_id":"%%s","transcript_path":"%%s","source":"%%s","hook_event_name":"SessionStart","cwd":"%%s"}' \
+  "$session_id" "$transcript" "$source" "$(pwd)" |
+  CLAUDE_ENV_FILE=%s sh -c %s >> %s
+`, util.ShellQuote(FakeClaudeEnvFile(dir)), util.ShellQuote(scenario.Hook), util.ShellQuote(FakeClaudeHookOutput(dir)))
+	}
+	if scenario.Stderr != "" {
+		fmt.Fprintf(&script, "printf '%%s\\n' %s >&2\n", util.ShellQuote(scenario.Stderr))
+	}
+	if scenario.Signal != "" {
+		fmt.Fprintf(&script, "kill -s %s $$\n", scenario.Signal)
+	}
+	fmt.Fprintf(&script, "exit %d\n", scenario.ExitCode)
+
+	if err := os.WriteFile(binaryPath, []byte(script.String()), 0o755); err != nil {
 		return "", "", err
 	}
 
 	return binaryPath, argsFile, nil
 }
 
+// FakeClaudeEnvFile returns the CLAUDE_ENV_FILE the fake claude in dir gives
+// its hook.
+func FakeClaudeEnvFile(dir string) string {
+	return filepath.Join(dir, "claude-env")
+}
+
+// FakeClaudeHookOutput returns the file collecting the stdout of the fake
+// claude's hook in dir, i.e. what Claude Code would add to the conversation.
+func FakeClaudeHookOutput(dir string) string {
+	return filepath.Join(dir, "claude-hook-output.txt")
+}
+
 // ReadClaudeArgs reads the captured arguments from the fake claude invocation.
 func ReadClaudeArgs(argsFile string) (string, error) {
 	content, err := os.ReadFile(argsFile)
@@ -0,0 +1,98 @@
+// Package timing measures the wall time of a command's major phases (listing
+// sessions, TUIs, the claude process) for the --timings flag.
+package timing
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+type phase struct {
+	name  string
+	total time.Duration
+	calls int
+}
+
+var (
+	mu      sync.Mutex
+	enabled bool
+	started time.Time
+	phases  []*phase
+)
+
+// Enable starts recording. Until it is called, Track is a no-op.
+func Enable() {
+	mu.Lock()
+	defer mu.Unlock()
+	enabled = true
+	started = time.Now()
+	phases = nil
+}
+
+// Track starts timing a phase and returns the function that stops it:
+//
+//	defer timing.Track("store list")()
+//
+// Repeated phases are added up.
+func Track(name string) func() {
+	mu.Lock()
+	on := enabled
+	mu.Unlock()
+	if !on {
+		return func() {}
+	}
+
+	start := time.Now()
+	return func() {
+		record(name, time.Since(start))
+	}
+}
+
+func record(name string, elapsed time.Duration) {
+	mu.Lock()
+	defer mu.Unlock()
+	for _, p := range phases {
+		if p.name == name {
+			p.total += elapsed
+			p.calls++
+			return
+		}
+	}
+	phases = append(phases, &phase{name: name, total: elapsed, calls: 1})
+}
+
+// Report writes the recorded phases, in the order they first ran, followed by
+// the total since Enable, and stops recording. Writes nothing when disabled.
+func Report(w io.Writer) {
+	mu.Lock()
+	defer mu.Unlock()
+	if !enabled {
+		return
+	}
+	enabled = false
+
+	_, _ = fmt.Fprintln(w, "Timings:")
+	for _, p := range phases {
+		line := fmt.Sprintf("  %-12s %10s", p.name, round(p.total))
+		if p.calls > 1 {
+			line += fmt.Sprintf(" (%d calls)", p.calls)
+		}
+		_, _ = fmt.Fprintln(w, line)
+	}
+	_, _ = fmt.Fprintf(w, "  %-12s %10s\n", "total", round(time.Since(started)))
+}
+
+// round keeps durations readable: microseconds below a millisecond,
+// milliseconds below a minute, and seconds above.
+func round(d time.Duration) time.Duration {
+	switch {
+	case d < time.Millisecond:
+		return d.Round(time.Microsecond)
+	case d < time.Minute:
+		return d.Round(time.Millisecond)
+	default:
+		return d.Round(time.Second)
+	}
+}
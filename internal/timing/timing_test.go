@@ -0,0 +1,48 @@
+package timing_test
+
+import (
+	"bytes"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/timing"
+)
+
+var _ = Describe("Timing", func() {
+	It("reports nothing when not enabled", func() {
+		timing.Track("store list")()
+
+		var out bytes.Buffer
+		timing.Report(&out)
+		Expect(out.String()).To(BeEmpty())
+	})
+
+	It("reports phases in the order they first ran, adding up repeats", func() {
+		timing.Enable()
+		stop := timing.Track("tui")
+		time.Sleep(2 * time.Millisecond)
+		stop()
+		timing.Track("store list")()
+		timing.Track("tui")()
+
+		var out bytes.Buffer
+		timing.Report(&out)
+		lines := bytes.Split(bytes.TrimSpace(out.Bytes()), []byte("\n"))
+		Expect(lines).To(HaveLen(4))
+		Expect(string(lines[0])).To(Equal("Timings:"))
+		Expect(string(lines[1])).To(MatchRegexp(`^  tui +\S+ \(2 calls\)$`))
+		Expect(string(lines[2])).To(MatchRegexp(`^  store list +\S+$`))
+		Expect(string(lines[3])).To(MatchRegexp(`^  total +\S+$`))
+	})
+
+	It("stops recording after reporting", func() {
+		timing.Enable()
+		timing.Report(&bytes.Buffer{})
+
+		var out bytes.Buffer
+		timing.Report(&out)
+		Expect(out.String()).To(BeEmpty())
+	})
+})
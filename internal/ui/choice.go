@@ -0,0 +1,195 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/fgrehm/clotilde/internal/i18n"
+	"github.com/fgrehm/clotilde/internal/timing"
+)
+
+// Choice-specific bindings
+var (
+	keyChoiceAll    = KeyBinding{Keys: []string{"a"}, Label: "a", Help: "apply to all remaining"}
+	keyChoiceCancel = KeyBinding{Keys: []string{"esc", "ctrl+c"}, Label: "esc", Help: "cancel"}
+)
+
+// ChoiceOption is one answer offered by a ChoiceModel. Key, when set, picks
+// the option directly.
+type ChoiceOption struct {
+	Key         string
+	Label       string
+	Description string
+}
+
+// ChoiceModel asks a question with several answers, e.g. how to resolve a
+// conflict. Arrows and enter pick an answer, as do the options' keys.
+type ChoiceModel struct {
+	Title     string
+	Details   []string
+	Options   []ChoiceOption
+	Cursor    int
+	Selected  int // Index into Options, -1 until chosen
+	Cancelled bool
+
+	// ApplyToAll is toggled with 'a' when AllowApplyToAll is set, so the
+	// caller can reuse the answer for the remaining questions
+	AllowApplyToAll bool
+	ApplyToAll      bool
+}
+
+// NewChoice creates a chooser between options.
+func NewChoice(title string, options []ChoiceOption) ChoiceModel {
+	return ChoiceModel{Title: title, Options: options, Selected: -1}
+}
+
+// WithDetails adds detail lines under the title
+func (m ChoiceModel) WithDetails(details []string) ChoiceModel {
+	m.Details = details
+	return m
+}
+
+// WithApplyToAll offers a toggle to reuse the answer for what follows
+func (m ChoiceModel) WithApplyToAll() ChoiceModel {
+	m.AllowApplyToAll = true
+	return m
+}
+
+// Init initializes the model (required by bubbletea)
+func (m ChoiceModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles keyboard input
+func (m ChoiceModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch {
+	case keyChoiceCancel.Matches(keyMsg):
+		m.Cancelled = true
+		return m, tea.Quit
+
+	case keySelect.Matches(keyMsg):
+		if len(m.Options) > 0 {
+			m.Selected = m.Cursor
+		}
+		return m, tea.Quit
+
+	case keyUp.Matches(keyMsg):
+		if len(m.Options) > 0 {
+			m.Cursor = (m.Cursor - 1 + len(m.Options)) % len(m.Options)
+		}
+		return m, nil
+
+	case keyDown.Matches(keyMsg):
+		if len(m.Options) > 0 {
+			m.Cursor = (m.Cursor + 1) % len(m.Options)
+		}
+		return m, nil
+
+	case m.AllowApplyToAll && keyChoiceAll.Matches(keyMsg):
+		m.ApplyToAll = !m.ApplyToAll
+		return m, nil
+	}
+
+	for i, opt := range m.Options {
+		if opt.Key != "" && keyMsg.String() == opt.Key {
+			m.Cursor = i
+			m.Selected = i
+			return m, tea.Quit
+		}
+	}
+	return m, nil
+}
+
+// View renders the question, the options with their keys, and a help line
+func (m ChoiceModel) View() string {
+	// Clear the chooser once done so it leaves nothing behind in the terminal
+	if m.Selected >= 0 || m.Cancelled {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(BoldStyle.Render(m.Title))
+	b.WriteString("\n")
+	for _, detail := range m.Details {
+		b.WriteString(DimStyle.Render("  • " + detail))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	selectedStyle := lipgloss.NewStyle().Foreground(SuccessColor).Bold(true)
+	for i, opt := range m.Options {
+		label := opt.Label
+		if opt.Key != "" {
+			label = fmt.Sprintf("[%s] %s", opt.Key, opt.Label)
+		}
+		if i == m.Cursor {
+			b.WriteString(selectedStyle.Render("> " + label))
+		} else {
+			b.WriteString("  " + label)
+		}
+		if opt.Description != "" {
+			b.WriteString(DimStyle.Render("  " + opt.Description))
+		}
+		b.WriteString("\n")
+	}
+
+	help := []KeyBinding{keySelect, keyChoiceCancel}
+	if m.AllowApplyToAll {
+		check := "[ ]"
+		if m.ApplyToAll {
+			check = "[x]"
+		}
+		b.WriteString("\n" + check + " apply to all remaining\n")
+		help = []KeyBinding{keySelect, keyChoiceAll, keyChoiceCancel}
+	}
+	b.WriteString("\n")
+	b.WriteString(DimStyle.Italic(true).Render(shortHelp(help...)))
+	b.WriteString("\n")
+	return b.String()
+}
+
+// RunChoice shows the chooser inline and returns the final model: Selected is
+// -1 when cancelled. Falls back to a numbered list when the TUI can't start.
+func RunChoice(model ChoiceModel) (ChoiceModel, error) {
+	defer timing.Track("tui")()
+
+	m, err := runProgram(model)
+	if err != nil {
+		return model, fmt.Errorf("failed to run chooser: %w", err)
+	}
+	if m == nil {
+		return runPlainChoice(model)
+	}
+	return m.(ChoiceModel), nil
+}
+
+// runPlainChoice is RunChoice's plain-text fallback.
+func runPlainChoice(model ChoiceModel) (ChoiceModel, error) {
+	options := make([]string, len(model.Options))
+	for i, option := range model.Options {
+		options[i] = option.Label
+		if option.Description != "" {
+			options[i] += " - " + option.Description
+		}
+	}
+	selected, err := plainSelect(model.Title, model.Details, options)
+	if err != nil {
+		return model, err
+	}
+	model.Selected = selected
+	model.Cancelled = selected < 0
+	if selected >= 0 && model.AllowApplyToAll {
+		if model.ApplyToAll, err = plainConfirm(i18n.T(i18n.PlainApplyToAll), ""); err != nil {
+			return model, err
+		}
+	}
+	return model, nil
+}
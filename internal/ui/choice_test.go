@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func choiceOptions() []ChoiceOption {
+	return []ChoiceOption{
+		{Key: "s", Label: "skip", Description: "leave it"},
+		{Key: "r", Label: "rename"},
+		{Label: "other"},
+	}
+}
+
+func TestChoice_KeyPicksOption(t *testing.T) {
+	m := NewChoice("Conflict", choiceOptions())
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	m = updated.(ChoiceModel)
+	if m.Selected != 1 || cmd == nil {
+		t.Errorf("Expected 'r' to pick the second option and quit, got %d", m.Selected)
+	}
+	if m.View() != "" {
+		t.Error("Expected the chooser to clear itself once done")
+	}
+}
+
+func TestChoice_ArrowsAndEnter(t *testing.T) {
+	m := NewChoice("Conflict", choiceOptions())
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyUp})
+	m = updated.(ChoiceModel)
+	if m.Cursor != 2 {
+		t.Errorf("Expected up to wrap to the last option, got %d", m.Cursor)
+	}
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(ChoiceModel)
+	if m.Selected != 2 || cmd == nil {
+		t.Errorf("Expected enter to pick the highlighted option, got %d", m.Selected)
+	}
+}
+
+func TestChoice_Cancel(t *testing.T) {
+	m := NewChoice("Conflict", choiceOptions())
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(ChoiceModel)
+	if !m.Cancelled || m.Selected != -1 {
+		t.Error("Expected esc to cancel without a selection")
+	}
+}
+
+func TestChoice_ApplyToAll(t *testing.T) {
+	m := NewChoice("Conflict", choiceOptions())
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	if updated.(ChoiceModel).ApplyToAll {
+		t.Error("Expected 'a' to do nothing unless enabled")
+	}
+
+	m = m.WithApplyToAll()
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	m = updated.(ChoiceModel)
+	if !m.ApplyToAll {
+		t.Error("Expected 'a' to toggle apply to all")
+	}
+	if !strings.Contains(m.View(), "[x] apply to all remaining") {
+		t.Errorf("Expected the toggle to be shown checked, got:\n%s", m.View())
+	}
+}
+
+func TestChoice_View(t *testing.T) {
+	view := NewChoice("Conflict", choiceOptions()).WithDetails([]string{"backup: alpha"}).View()
+
+	for _, want := range []string{"Conflict", "backup: alpha", "[s] skip", "leave it", "[r] rename", "other"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected view to contain %q, got:\n%s", want, view)
+		}
+	}
+}
@@ -6,6 +6,9 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/fgrehm/clotilde/internal/i18n"
+	"github.com/fgrehm/clotilde/internal/timing"
 )
 
 // ConfirmModel represents the confirmation dialog state
@@ -17,6 +20,11 @@ type ConfirmModel struct {
 	Confirmed   bool
 	Cancelled   bool
 	Focused     int // 0 = Cancel (default), 1 = Confirm
+
+	// TypedPhrase, when set, must be typed exactly before enter confirms
+	// (replaces the buttons and y/n shortcuts)
+	TypedPhrase string
+	Typed       string
 }
 
 // NewConfirm creates a new confirmation dialog
@@ -40,6 +48,13 @@ func (m ConfirmModel) WithDestructive() ConfirmModel {
 	return m
 }
 
+// WithTypedConfirmation requires the user to type phrase (e.g. the session name)
+// to confirm, guarding against muscle-memory enter presses on risky actions.
+func (m ConfirmModel) WithTypedConfirmation(phrase string) ConfirmModel {
+	m.TypedPhrase = phrase
+	return m
+}
+
 // Init initializes the model (required by bubbletea)
 func (m ConfirmModel) Init() tea.Cmd {
 	return nil
@@ -47,6 +62,10 @@ func (m ConfirmModel) Init() tea.Cmd {
 
 // Update handles keyboard input
 func (m ConfirmModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.TypedPhrase != "" {
+		return m.updateTyped(msg)
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch msg.String() {
@@ -83,6 +102,40 @@ func (m ConfirmModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// updateTyped handles input in typed confirmation mode, where letters are
+// part of the phrase rather than shortcuts.
+func (m ConfirmModel) updateTyped(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch keyMsg.Type {
+	case tea.KeyCtrlC, tea.KeyEsc:
+		m.Cancelled = true
+		return m, tea.Quit
+
+	case tea.KeyEnter:
+		if m.Typed == m.TypedPhrase {
+			m.Confirmed = true
+			return m, tea.Quit
+		}
+		return m, nil
+
+	case tea.KeyBackspace:
+		if runes := []rune(m.Typed); len(runes) > 0 {
+			m.Typed = string(runes[:len(runes)-1])
+		}
+		return m, nil
+
+	case tea.KeyRunes, tea.KeySpace:
+		m.Typed += string(keyMsg.Runes)
+		return m, nil
+	}
+
+	return m, nil
+}
+
 // View renders the confirmation dialog
 func (m ConfirmModel) View() string {
 	var b strings.Builder
@@ -109,18 +162,38 @@ func (m ConfirmModel) View() string {
 		}
 	}
 
+	helpStyle := DimStyle.Italic(true)
+	if m.TypedPhrase != "" {
+		b.WriteString("\n")
+		b.WriteString(m.renderTypedInput())
+		b.WriteString("\n\n")
+		b.WriteString(helpStyle.Render(i18n.T(i18n.ConfirmTypedHelp, m.TypedPhrase)))
+		return b.String()
+	}
+
 	// Buttons
 	b.WriteString("\n")
 	b.WriteString(m.renderButtons())
 
 	// Help text
 	b.WriteString("\n\n")
-	helpStyle := DimStyle.Italic(true)
-	b.WriteString(helpStyle.Render("(y/n, arrows to navigate, enter to confirm)"))
+	b.WriteString(helpStyle.Render(i18n.T(i18n.ConfirmHelp)))
 
 	return b.String()
 }
 
+// renderTypedInput renders the prompt and text typed so far. The input turns
+// red/green to show whether it matches the phrase.
+func (m ConfirmModel) renderTypedInput() string {
+	prompt := i18n.T(i18n.ConfirmTypePrompt, BoldStyle.Render(m.TypedPhrase))
+
+	inputStyle := lipgloss.NewStyle().Foreground(ErrorColor)
+	if m.Typed == m.TypedPhrase {
+		inputStyle = lipgloss.NewStyle().Foreground(SuccessColor)
+	}
+	return prompt + "\n> " + inputStyle.Render(m.Typed) + "█"
+}
+
 // renderButtons renders the Cancel/Confirm buttons
 func (m ConfirmModel) renderButtons() string {
 	// Unfocused buttons: dim text, no border
@@ -152,8 +225,8 @@ func (m ConfirmModel) renderButtons() string {
 			Foreground(lipgloss.Color("#FFFFFF"))
 	}
 
-	cancelBtn := "Cancel"
-	confirmBtn := "Confirm"
+	cancelBtn := i18n.T(i18n.ConfirmCancel)
+	confirmBtn := i18n.T(i18n.ConfirmConfirm)
 
 	var cancelRendered, confirmRendered string
 	if m.Focused == 0 {
@@ -169,13 +242,25 @@ func (m ConfirmModel) renderButtons() string {
 	return lipgloss.JoinHorizontal(lipgloss.Center, cancelRendered, "    ", confirmRendered)
 }
 
-// RunConfirm runs the confirmation dialog and returns true if confirmed
+// RunConfirm runs the confirmation dialog and returns true if confirmed.
+// Falls back to a y/N (or typed phrase) prompt when the TUI can't start.
 func RunConfirm(model ConfirmModel) (bool, error) {
-	p := tea.NewProgram(model, tea.WithAltScreen())
-	m, err := p.Run()
+	defer timing.Track("tui")()
+
+	m, err := runProgram(model, tea.WithAltScreen())
 	if err != nil {
 		return false, fmt.Errorf("failed to run confirmation dialog: %w", err)
 	}
+	if m == nil {
+		_, _ = fmt.Fprintln(plainOutput, model.Title)
+		if model.Message != "" {
+			_, _ = fmt.Fprintln(plainOutput, model.Message)
+		}
+		for _, detail := range model.Details {
+			_, _ = fmt.Fprintln(plainOutput, "  "+detail)
+		}
+		return plainConfirm(i18n.T(i18n.PlainContinue), model.TypedPhrase)
+	}
 
 	finalModel := m.(ConfirmModel)
 	return finalModel.Confirmed, nil
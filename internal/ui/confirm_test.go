@@ -178,3 +178,73 @@ func TestConfirmView_WithDetails(t *testing.T) {
 		t.Error("View should contain second detail")
 	}
 }
+
+func typeText(model ConfirmModel, text string) ConfirmModel {
+	for _, r := range text {
+		updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		model = updated.(ConfirmModel)
+	}
+	return model
+}
+
+func TestConfirmTyped_ShortcutsAreInput(t *testing.T) {
+	model := NewConfirm("Delete", "Confirm?").WithTypedConfirmation("yn-test")
+	m := typeText(model, "yn")
+
+	if m.Confirmed || m.Cancelled {
+		t.Error("Expected y/n to be typed, not treated as shortcuts")
+	}
+	if m.Typed != "yn" {
+		t.Errorf("Expected typed text 'yn', got '%s'", m.Typed)
+	}
+}
+
+func TestConfirmTyped_EnterRequiresMatch(t *testing.T) {
+	model := NewConfirm("Delete", "Confirm?").WithTypedConfirmation("auth")
+	m := typeText(model, "aut")
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(ConfirmModel)
+	if m.Confirmed || cmd != nil {
+		t.Error("Expected enter to be ignored while the phrase does not match")
+	}
+
+	m = typeText(m, "h")
+	updated, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(ConfirmModel)
+	if !m.Confirmed || cmd == nil {
+		t.Error("Expected enter to confirm once the phrase matches")
+	}
+}
+
+func TestConfirmTyped_Backspace(t *testing.T) {
+	model := typeText(NewConfirm("Delete", "Confirm?").WithTypedConfirmation("auth"), "authx")
+
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	m := updated.(ConfirmModel)
+	if m.Typed != "auth" {
+		t.Errorf("Expected typed text 'auth' after backspace, got '%s'", m.Typed)
+	}
+}
+
+func TestConfirmTyped_View(t *testing.T) {
+	view := NewConfirm("Delete", "Confirm?").WithTypedConfirmation("auth").View()
+
+	if !strings.Contains(view, "Type") || !strings.Contains(view, "auth") {
+		t.Error("View should ask the user to type the phrase")
+	}
+	if strings.Contains(view, "Cancel") {
+		t.Error("View should not show buttons in typed mode")
+	}
+}
+
+func TestConfirmTyped_HelpNamesThePhrase(t *testing.T) {
+	view := NewConfirm("Prune", "Delete 12 sessions?").WithTypedConfirmation("prune").View()
+
+	if !strings.Contains(view, "type 'prune' and press enter") {
+		t.Errorf("Help should name the phrase to type, got:\n%s", view)
+	}
+	if strings.Contains(view, "the name") {
+		t.Error("Help should not assume the phrase is a name")
+	}
+}
@@ -1,27 +1,63 @@
 package ui
 
 import (
+	"cmp"
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"github.com/fgrehm/clotilde/internal/i18n"
 	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/timing"
+	"github.com/fgrehm/clotilde/internal/util"
 )
 
 // DashboardModel represents the main dashboard state
 type DashboardModel struct {
-	Sessions    []*session.Session
-	Cursor      int
-	Selected    string // Selected action ID
-	Cancelled   bool
-	Width       int
-	Height      int
-	recentLimit int // How many recent sessions to show
-	menuItems   []MenuItem
+	Sessions        []*session.Session
+	Cursor          int
+	Selected        string // Selected action ID
+	Cancelled       bool
+	ShowHelp        bool // Show the help overlay
+	Width           int
+	Height          int
+	recentLimit     int             // How many recent sessions to show
+	recentIncognito bool            // List incognito sessions among the recent ones
+	yolo            map[string]bool // Sessions running with bypassPermissions
+	menuItems       []MenuItem
+
+	activityLoader func() (ActivityStats, error)
+	activity       *ActivityStats // nil while loading
+	activityErr    error
 }
 
+// ActivityStats summarizes recent Claude Code usage for the dashboard.
+type ActivityStats struct {
+	Period     string            // Human-readable window, e.g. "Last 7 days"
+	ActiveTime time.Duration     // Total time across all sessions
+	Busiest    []SessionActivity // Most active sessions first
+	Models     map[string]int    // Assistant messages per model family
+}
+
+// SessionActivity is one session's share of ActivityStats.
+type SessionActivity struct {
+	Name       string
+	ActiveTime time.Duration
+}
+
+// activityLoadedMsg delivers the result of the activity loader
+type activityLoadedMsg struct {
+	stats ActivityStats
+	err   error
+}
+
+// busiestLimit caps how many sessions the activity panel lists
+const busiestLimit = 3
+
 // MenuItem represents a menu action
 type MenuItem struct {
 	ID          string
@@ -36,19 +72,48 @@ func NewDashboard(sessions []*session.Session) DashboardModel {
 		Cursor:      0,
 		recentLimit: 5,
 		menuItems: []MenuItem{
-			{ID: "start", Label: "Start new session", Description: "Create a new conversation"},
-			{ID: "resume", Label: "Resume session", Description: "Continue an existing session"},
-			{ID: "fork", Label: "Fork session", Description: "Branch from an existing session"},
-			{ID: "list", Label: "List all sessions", Description: "View all sessions in a table"},
-			{ID: "delete", Label: "Delete session", Description: "Remove a session"},
-			{ID: "quit", Label: "Quit", Description: "Exit dashboard"},
+			{ID: "start", Label: i18n.T(i18n.MenuStart), Description: i18n.T(i18n.MenuStartHint)},
+			{ID: "quick", Label: i18n.T(i18n.MenuQuick), Description: i18n.T(i18n.MenuQuickHint)},
+			{ID: "resume", Label: i18n.T(i18n.MenuResume), Description: i18n.T(i18n.MenuResumeHint)},
+			{ID: "fork", Label: i18n.T(i18n.MenuFork), Description: i18n.T(i18n.MenuForkHint)},
+			{ID: "list", Label: i18n.T(i18n.MenuList), Description: i18n.T(i18n.MenuListHint)},
+			{ID: "delete", Label: i18n.T(i18n.MenuDelete), Description: i18n.T(i18n.MenuDeleteHint)},
+			{ID: "quit", Label: i18n.T(i18n.MenuQuit), Description: i18n.T(i18n.MenuQuitHint)},
 		},
 	}
 }
 
-// Init initializes the model (required by bubbletea)
+// WithActivityStats shows an activity panel fed by loader. The loader runs in
+// the background when the dashboard starts, so the menu stays responsive
+// while transcripts are read.
+func (m DashboardModel) WithActivityStats(loader func() (ActivityStats, error)) DashboardModel {
+	m.activityLoader = loader
+	return m
+}
+
+// WithIncognitoInRecent lists incognito sessions under "Recent Sessions",
+// which leaves them out by default since they're gone once they exit.
+func (m DashboardModel) WithIncognitoInRecent(show bool) DashboardModel {
+	m.recentIncognito = show
+	return m
+}
+
+// WithYolo flags the named sessions as running with bypassPermissions
+func (m DashboardModel) WithYolo(yolo map[string]bool) DashboardModel {
+	m.yolo = yolo
+	return m
+}
+
+// Init starts loading activity stats, if configured
 func (m DashboardModel) Init() tea.Cmd {
-	return nil
+	if m.activityLoader == nil {
+		return nil
+	}
+	loader := m.activityLoader
+	return func() tea.Msg {
+		stats, err := loader()
+		return activityLoadedMsg{stats: stats, err: err}
+	}
 }
 
 // Update handles keyboard input
@@ -59,45 +124,73 @@ func (m DashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.Height = msg.Height
 		return m, nil
 
+	case activityLoadedMsg:
+		m.activity, m.activityErr = &msg.stats, msg.err
+		return m, nil
+
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c", "q", "esc":
+		// Any key closes the help overlay (ctrl+c still quits)
+		if m.ShowHelp {
+			if msg.String() == "ctrl+c" {
+				m.Cancelled = true
+				return m, tea.Quit
+			}
+			m.ShowHelp = false
+			return m, nil
+		}
+
+		switch {
+		case dashboardKeyQuit.Matches(msg):
 			m.Cancelled = true
 			return m, tea.Quit
 
-		case "enter", " ":
+		case keyHelp.Matches(msg):
+			m.ShowHelp = true
+
+		case keySelect.Matches(msg):
 			if m.Cursor < len(m.menuItems) {
 				m.Selected = m.menuItems[m.Cursor].ID
 			}
 			return m, tea.Quit
 
-		case "up", "k":
+		case keyUp.Matches(msg):
 			if m.Cursor > 0 {
 				m.Cursor--
 			}
-			return m, nil
 
-		case "down", "j":
+		case keyDown.Matches(msg):
 			if m.Cursor < len(m.menuItems)-1 {
 				m.Cursor++
 			}
-			return m, nil
 
-		case "home", "g":
+		case keyTop.Matches(msg):
 			m.Cursor = 0
-			return m, nil
 
-		case "end", "G":
+		case keyBottom.Matches(msg):
 			m.Cursor = len(m.menuItems) - 1
-			return m, nil
 		}
 	}
 
 	return m, nil
 }
 
+// dashboardKeyQuit also accepts esc since the dashboard has no filter to clear
+var dashboardKeyQuit = KeyBinding{Keys: []string{"q", "esc", "ctrl+c"}, Label: "q/esc", Help: "quit"}
+
+// helpSections lists the dashboard's keybindings for the help overlay
+func (m DashboardModel) helpSections() []HelpSection {
+	return []HelpSection{
+		{Title: "Navigation", Bindings: []KeyBinding{keyUp, keyDown, keyTop, keyBottom}},
+		{Title: "Actions", Bindings: []KeyBinding{keySelect, dashboardKeyQuit, keyHelp}},
+	}
+}
+
 // View renders the dashboard
 func (m DashboardModel) View() string {
+	if m.ShowHelp {
+		return renderHelpOverlay("Dashboard", m.helpSections(), m.Width, m.Height)
+	}
+
 	var b strings.Builder
 
 	// Title
@@ -105,12 +198,17 @@ func (m DashboardModel) View() string {
 		Bold(true).
 		Foreground(SuccessColor).
 		Padding(1, 0)
-	b.WriteString(titleStyle.Render("Clotilde Dashboard"))
+	b.WriteString(titleStyle.Render(i18n.T(i18n.DashboardTitle)))
 	b.WriteString("\n\n")
 
 	// Stats summary placeholder
 	b.WriteString(m.renderStats())
-	b.WriteString("\n\n")
+	b.WriteString("\n")
+	if m.activityLoader != nil {
+		b.WriteString(m.renderActivity())
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
 
 	// Quick actions menu
 	b.WriteString(m.renderMenu())
@@ -122,7 +220,7 @@ func (m DashboardModel) View() string {
 
 	// Help text
 	helpStyle := DimStyle.Italic(true)
-	b.WriteString(helpStyle.Render("(↑/↓ or j/k to navigate, enter to select, q to quit)"))
+	b.WriteString(helpStyle.Render(shortHelp(keyUp, keyDown, keySelect, keyHelp, dashboardKeyQuit)))
 
 	return b.String()
 }
@@ -132,6 +230,8 @@ func (m DashboardModel) renderStats() string {
 	total := len(m.Sessions)
 	forks := 0
 	incognito := 0
+	expired := 0
+	now := time.Now()
 
 	for _, sess := range m.Sessions {
 		if sess.Metadata.IsForkedSession {
@@ -140,6 +240,9 @@ func (m DashboardModel) renderStats() string {
 		if sess.Metadata.IsIncognito {
 			incognito++
 		}
+		if sess.IsExpired(now) {
+			expired++
+		}
 	}
 
 	statsStyle := lipgloss.NewStyle().
@@ -147,17 +250,77 @@ func (m DashboardModel) renderStats() string {
 		Bold(true)
 
 	var stats []string
-	stats = append(stats, statsStyle.Render(fmt.Sprintf("%d total", total)))
+	stats = append(stats, statsStyle.Render(i18n.T(i18n.DashboardTotal, total)))
 	if forks > 0 {
 		forkStyle := lipgloss.NewStyle().Foreground(ForkColor)
-		stats = append(stats, forkStyle.Render(fmt.Sprintf("%d forks", forks)))
+		stats = append(stats, forkStyle.Render(i18n.T(i18n.DashboardForks, forks)))
 	}
 	if incognito > 0 {
 		incognitoStyle := lipgloss.NewStyle().Foreground(IncognitoColor)
-		stats = append(stats, incognitoStyle.Render(fmt.Sprintf("%d incognito", incognito)))
+		stats = append(stats, incognitoStyle.Render(i18n.T(i18n.DashboardIncognito, incognito)))
+	}
+	if expired > 0 {
+		expiredStyle := lipgloss.NewStyle().Foreground(WarningColor)
+		stats = append(stats, expiredStyle.Render(i18n.T(i18n.DashboardExpired, expired)))
 	}
 
-	return "Sessions: " + strings.Join(stats, " · ")
+	return i18n.T(i18n.DashboardSessions, strings.Join(stats, " · "))
+}
+
+// renderActivity renders the activity panel: time spent, busiest sessions,
+// and model usage
+func (m DashboardModel) renderActivity() string {
+	switch {
+	case m.activityErr != nil:
+		return DimStyle.Render(i18n.T(i18n.DashboardActivityUnavailable, m.activityErr))
+	case m.activity == nil:
+		return DimStyle.Italic(true).Render(i18n.T(i18n.DashboardActivityLoading))
+	}
+
+	stats := m.activity
+	if stats.ActiveTime == 0 && len(stats.Models) == 0 {
+		return stats.Period + ": " + DimStyle.Render(i18n.T(i18n.DashboardNoActivity))
+	}
+
+	timeStyle := lipgloss.NewStyle().Foreground(InfoColor).Bold(true)
+	lines := []string{stats.Period + ": " + i18n.T(i18n.DashboardTimeInClaude, timeStyle.Render(util.FormatDuration(stats.ActiveTime)))}
+
+	if len(stats.Busiest) > 0 {
+		var busiest []string
+		for _, sa := range stats.Busiest[:min(len(stats.Busiest), busiestLimit)] {
+			busiest = append(busiest, sa.Name+" "+DimStyle.Render(util.FormatDuration(sa.ActiveTime)))
+		}
+		lines = append(lines, "  "+i18n.T(i18n.DashboardBusiest, strings.Join(busiest, " · ")))
+	}
+
+	if len(stats.Models) > 0 {
+		lines = append(lines, "  "+i18n.T(i18n.DashboardModels, formatModelShares(stats.Models)))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// formatModelShares renders model usage as percentages, most used first,
+// e.g. "opus 62% · sonnet 38%"
+func formatModelShares(models map[string]int) string {
+	total := 0
+	names := make([]string, 0, len(models))
+	for name, n := range models {
+		total += n
+		names = append(names, name)
+	}
+	slices.SortFunc(names, func(a, b string) int {
+		if c := cmp.Compare(models[b], models[a]); c != 0 {
+			return c
+		}
+		return cmp.Compare(a, b)
+	})
+
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s %d%%", name, models[name]*100/total)
+	}
+	return strings.Join(parts, " · ")
 }
 
 // renderMenu renders the quick actions menu
@@ -165,7 +328,7 @@ func (m DashboardModel) renderMenu() string {
 	var b strings.Builder
 
 	headerStyle := BoldStyle
-	b.WriteString(headerStyle.Render("Quick Actions"))
+	b.WriteString(headerStyle.Render(i18n.T(i18n.DashboardQuickActions)))
 	b.WriteString("\n\n")
 
 	for i, item := range m.menuItems {
@@ -189,23 +352,33 @@ func (m DashboardModel) renderMenu() string {
 // renderRecentSessions renders the recent sessions list
 func (m DashboardModel) renderRecentSessions() string {
 	if len(m.Sessions) == 0 {
-		return DimStyle.Italic(true).Render("No sessions yet. Start one to get going!")
+		return DimStyle.Italic(true).Render(i18n.T(i18n.DashboardNoSessions))
+	}
+
+	recent := m.Sessions
+	if !m.recentIncognito {
+		recent = slices.DeleteFunc(slices.Clone(recent), func(sess *session.Session) bool {
+			return sess.Metadata.IsIncognito
+		})
+		if len(recent) == 0 {
+			return DimStyle.Italic(true).Render(i18n.T(i18n.DashboardOnlyIncognito))
+		}
 	}
 
 	var b strings.Builder
 
 	headerStyle := BoldStyle
-	b.WriteString(headerStyle.Render("Recent Sessions"))
+	b.WriteString(headerStyle.Render(i18n.T(i18n.DashboardRecentSessions)))
 	b.WriteString("\n\n")
 
 	// Show up to recentLimit sessions
-	limit := min(len(m.Sessions), m.recentLimit)
+	limit := min(len(recent), m.recentLimit)
 
 	for i := range limit {
-		sess := m.Sessions[i]
+		sess := recent[i]
 
 		// Format session line
-		name := sess.Name
+		name := sess.Name + starIndicator(sess.Metadata.Starred)
 		typeIndicator := ""
 		if sess.Metadata.IsForkedSession {
 			typeStyle := lipgloss.NewStyle().Foreground(ForkColor)
@@ -214,25 +387,42 @@ func (m DashboardModel) renderRecentSessions() string {
 			typeStyle := lipgloss.NewStyle().Foreground(IncognitoColor)
 			typeIndicator = typeStyle.Render(" [incognito]")
 		}
+		typeIndicator += statusIndicator(sess.Status())
+		if m.yolo[sess.Name] {
+			typeIndicator += yoloIndicator()
+		}
 
 		fmt.Fprintf(&b, "  • %s%s\n", name, typeIndicator)
 	}
 
-	if len(m.Sessions) > limit {
+	if len(recent) > limit {
 		moreStyle := DimStyle.Italic(true)
-		b.WriteString(moreStyle.Render(fmt.Sprintf("\n  ...and %d more", len(m.Sessions)-limit)))
+		b.WriteString(moreStyle.Render("\n  " + i18n.T(i18n.DashboardMore, len(recent)-limit)))
 	}
 
 	return b.String()
 }
 
-// RunDashboard runs the dashboard and returns the selected action
+// RunDashboard runs the dashboard and returns the selected action. Falls back
+// to a numbered menu when the TUI can't start.
 func RunDashboard(model DashboardModel) (string, error) {
-	p := tea.NewProgram(model, tea.WithAltScreen())
-	m, err := p.Run()
+	defer timing.Track("tui")()
+
+	m, err := runProgram(model, tea.WithAltScreen())
 	if err != nil {
 		return "", fmt.Errorf("failed to run dashboard: %w", err)
 	}
+	if m == nil {
+		labels := make([]string, len(model.menuItems))
+		for i, item := range model.menuItems {
+			labels[i] = item.Label
+		}
+		selected, err := plainSelect("Clotilde Dashboard", nil, labels)
+		if err != nil || selected < 0 {
+			return "", err
+		}
+		return model.menuItems[selected].ID, nil
+	}
 
 	finalModel := m.(DashboardModel)
 	if finalModel.Cancelled {
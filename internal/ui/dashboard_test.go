@@ -1,9 +1,11 @@
 package ui
 
 import (
+	"errors"
 	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 
@@ -217,6 +219,31 @@ func TestDashboardView_RecentSessionsLimit(t *testing.T) {
 	}
 }
 
+func TestDashboardView_RecentSessionsIncognito(t *testing.T) {
+	sessions := []*session.Session{
+		session.NewIncognitoSession("ghost", "uuid-1"),
+		session.NewSession("regular", "uuid-2"),
+	}
+
+	recent := NewDashboard(sessions).renderRecentSessions()
+	if strings.Contains(recent, "ghost") {
+		t.Error("Recent sessions should leave incognito sessions out by default")
+	}
+	if !strings.Contains(recent, "regular") {
+		t.Error("Recent sessions should list regular sessions")
+	}
+
+	recent = NewDashboard(sessions).WithIncognitoInRecent(true).renderRecentSessions()
+	if !strings.Contains(recent, "ghost") {
+		t.Error("Recent sessions should list incognito sessions when enabled")
+	}
+
+	recent = NewDashboard(sessions[:1]).renderRecentSessions()
+	if !strings.Contains(recent, "Only incognito sessions") {
+		t.Errorf("Expected a note when only incognito sessions exist, got %q", recent)
+	}
+}
+
 func TestDashboardMenuItems(t *testing.T) {
 	model := NewDashboard([]*session.Session{})
 
@@ -226,7 +253,7 @@ func TestDashboardMenuItems(t *testing.T) {
 	}
 
 	// Check for expected menu items
-	expectedIDs := []string{"start", "resume", "fork", "list", "delete", "quit"}
+	expectedIDs := []string{"start", "quick", "resume", "fork", "list", "delete", "quit"}
 	actualIDs := make([]string, len(model.menuItems))
 	for i, item := range model.menuItems {
 		actualIDs[i] = item.ID
@@ -239,3 +266,52 @@ func TestDashboardMenuItems(t *testing.T) {
 		}
 	}
 }
+
+func TestDashboardActivity(t *testing.T) {
+	model := NewDashboard(nil)
+	if model.Init() != nil {
+		t.Error("Expected no startup command without an activity loader")
+	}
+	if strings.Contains(model.View(), "activity") {
+		t.Error("Activity panel should only show when a loader is set")
+	}
+
+	model = model.WithActivityStats(func() (ActivityStats, error) {
+		return ActivityStats{
+			Period:     "Last 7 days",
+			ActiveTime: 3*time.Hour + 12*time.Minute,
+			Busiest: []SessionActivity{
+				{Name: "auth-feature", ActiveTime: 2 * time.Hour},
+				{Name: "bugfix", ActiveTime: time.Hour},
+			},
+			Models: map[string]int{"opus": 3, "sonnet": 1},
+		}, nil
+	})
+	if !strings.Contains(model.View(), "Loading activity") {
+		t.Error("Expected a loading placeholder before stats arrive")
+	}
+
+	// The loader runs as a command so the menu stays responsive
+	cmd := model.Init()
+	if cmd == nil {
+		t.Fatal("Expected Init to start loading activity")
+	}
+	updated, _ := model.Update(cmd())
+	view := updated.(DashboardModel).View()
+
+	for _, want := range []string{"Last 7 days: 3h 12m in Claude", "auth-feature 2h", "bugfix 1h", "opus 75% · sonnet 25%"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected %q in view:\n%s", want, view)
+		}
+	}
+}
+
+func TestDashboardActivity_Error(t *testing.T) {
+	model := NewDashboard(nil).WithActivityStats(func() (ActivityStats, error) {
+		return ActivityStats{}, errors.New("no home directory")
+	})
+	updated, _ := model.Update(model.Init()())
+	if !strings.Contains(updated.(DashboardModel).View(), "Activity unavailable: no home directory") {
+		t.Error("Expected the loader error in the view")
+	}
+}
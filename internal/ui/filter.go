@@ -0,0 +1,199 @@
+package ui
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// newFilterInput creates the text input behind the "/" filter in the table
+// and picker. The cursor doesn't blink, so the components need no tick loop.
+func newFilterInput() textinput.Model {
+	input := textinput.New()
+	input.Prompt = ""
+	input.Cursor.SetMode(cursor.CursorStatic)
+	return input
+}
+
+// syncFilterInput loads text into input when they differ (e.g. after the
+// filter was cleared with esc) and focuses it.
+func syncFilterInput(input *textinput.Model, text string) {
+	if input.Value() != text {
+		input.SetValue(text)
+		input.CursorEnd()
+	}
+	input.Focus()
+}
+
+// updateFilterInput forwards a key to the filter input and returns the new
+// filter text. Editing works on runes, so multi-byte input and paste are safe.
+func updateFilterInput(input *textinput.Model, text string, msg tea.KeyMsg) string {
+	syncFilterInput(input, text)
+	*input, _ = input.Update(msg)
+	return input.Value()
+}
+
+// renderFilterInput renders the filter text, with the input's cursor while
+// the user is typing.
+func renderFilterInput(input textinput.Model, text string, active bool) string {
+	if !active {
+		return text
+	}
+	syncFilterInput(&input, text)
+	return input.View()
+}
+
+// findFold returns the byte offsets of the first case-insensitive match of
+// sub in s at or after from, or -1, -1. Runes are compared with Unicode case
+// folding, so offsets always fall on rune boundaries of s.
+func findFold(s, sub string, from int) (start, end int) {
+	if sub == "" {
+		return -1, -1
+	}
+	for i := from; i < len(s); {
+		if j, ok := hasPrefixFold(s[i:], sub); ok {
+			return i, i + j
+		}
+		_, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+	}
+	return -1, -1
+}
+
+// hasPrefixFold reports whether s starts with prefix (ignoring case) and, if
+// so, how many bytes of s the match covers.
+func hasPrefixFold(s, prefix string) (int, bool) {
+	n := 0
+	for _, want := range prefix {
+		if n >= len(s) {
+			return 0, false
+		}
+		got, size := utf8.DecodeRuneInString(s[n:])
+		if !strings.EqualFold(string(got), string(want)) {
+			return 0, false
+		}
+		n += size
+	}
+	return n, true
+}
+
+// containsFold reports whether s contains sub, ignoring case.
+func containsFold(s, sub string) bool {
+	start, _ := findFold(s, sub, 0)
+	return start >= 0
+}
+
+// highlightMatches renders s with base, underlining every case-insensitive
+// match of filter in the warning color. s must be unstyled.
+func highlightMatches(s, filter string, base lipgloss.Style) string {
+	if filter == "" {
+		return base.Render(s)
+	}
+
+	match := base.Foreground(WarningColor).Underline(true)
+	var b strings.Builder
+	pos := 0
+	for {
+		start, end := findFold(s, filter, pos)
+		if start < 0 {
+			break
+		}
+		if start > pos {
+			b.WriteString(base.Render(s[pos:start]))
+		}
+		b.WriteString(match.Render(s[start:end]))
+		pos = end
+	}
+	if pos < len(s) {
+		b.WriteString(base.Render(s[pos:]))
+	}
+	return b.String()
+}
+
+// filterQuery is a parsed filter. Terms like "name:auth" or
+// `context:"login bug"` only match the named field; the rest of the text
+// matches as before. A term whose prefix doesn't name a field (e.g. a URL)
+// stays part of the free text.
+type filterQuery struct {
+	Free   string            // Text matched as a whole, "" for none
+	Fields map[string]string // Text each field must contain, by normalized field name
+}
+
+// parseFilterQuery splits text into terms scoped to one of fields and free
+// text. Without scoped terms the free text is text unchanged, so plain
+// filters keep matching as a single substring. An empty scoped term (while
+// typing "name:") matches everything.
+func parseFilterQuery(text string, fields []string) filterQuery {
+	known := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		known[normalizeFilterField(field)] = true
+	}
+
+	q := filterQuery{Fields: map[string]string{}}
+	var free []string
+	for _, term := range splitFilterTerms(text) {
+		key, value, ok := strings.Cut(term, ":")
+		if ok && key != "" && known[normalizeFilterField(key)] {
+			q.Fields[normalizeFilterField(key)] = strings.Trim(value, `"`)
+			continue
+		}
+		free = append(free, term)
+	}
+	if len(q.Fields) == 0 {
+		q.Free = text
+	} else {
+		q.Free = strings.Join(free, " ")
+	}
+	return q
+}
+
+// splitFilterTerms splits text on spaces outside double quotes.
+func splitFilterTerms(text string) []string {
+	var terms []string
+	var term strings.Builder
+	quoted := false
+	for _, r := range text {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			term.WriteRune(r)
+		case r == ' ' && !quoted:
+			if term.Len() > 0 {
+				terms = append(terms, term.String())
+				term.Reset()
+			}
+		default:
+			term.WriteRune(r)
+		}
+	}
+	if term.Len() > 0 {
+		terms = append(terms, term.String())
+	}
+	return terms
+}
+
+// normalizeFilterField makes field names match regardless of case, spaces,
+// hyphens and underscores ("Last Used", "last-used", "lastused").
+func normalizeFilterField(field string) string {
+	return strings.NewReplacer(" ", "", "-", "", "_", "").Replace(strings.ToLower(field))
+}
+
+// matchesField reports whether value satisfies the term scoped to field, if
+// there is one.
+func (q filterQuery) matchesField(field, value string) bool {
+	text, ok := q.Fields[normalizeFilterField(field)]
+	return !ok || text == "" || containsFold(value, text)
+}
+
+// highlightFor returns the text to highlight in field: its scoped term, or
+// the free text.
+func (q filterQuery) highlightFor(field string) string {
+	if text, ok := q.Fields[normalizeFilterField(field)]; ok {
+		return text
+	}
+	return q.Free
+}
@@ -0,0 +1,214 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/fgrehm/clotilde/internal/session"
+)
+
+func TestFindFold(t *testing.T) {
+	tests := []struct {
+		s, sub     string
+		start, end int
+	}{
+		{"auth-feature", "FEAT", 5, 9},
+		{"Ärger-fix", "ärg", 0, 4},
+		{"日本語-session", "本語", 3, 9},
+		{"auth-feature", "xyz", -1, -1},
+		{"auth-feature", "", -1, -1},
+	}
+	for _, tt := range tests {
+		start, end := findFold(tt.s, tt.sub, 0)
+		if start != tt.start || end != tt.end {
+			t.Errorf("findFold(%q, %q) = %d, %d; want %d, %d", tt.s, tt.sub, start, end, tt.start, tt.end)
+		}
+	}
+}
+
+func TestHighlightMatches(t *testing.T) {
+	if got := highlightMatches("plain", "", lipgloss.NewStyle()); got != "plain" {
+		t.Errorf("Expected unstyled text without a filter, got %q", got)
+	}
+
+	// Tests render without colors, so only the text itself is visible
+	got := highlightMatches("fix-ÄRGER-ärger", "ärger", lipgloss.NewStyle())
+	if got != "fix-ÄRGER-ärger" {
+		t.Errorf("Highlighting should not change the text, got %q", got)
+	}
+}
+
+func TestTableFiltering_MultiByteInput(t *testing.T) {
+	m := NewTable([]string{"Name"}, [][]string{{"café-refactor"}, {"cafe-plain"}})
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	m = updated.(TableModel)
+
+	// Pasted or IME input arrives as several runes in one message
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("café")})
+	m = updated.(TableModel)
+	if m.FilterText != "café" {
+		t.Fatalf("Expected FilterText 'café', got %q", m.FilterText)
+	}
+	if rows := m.filteredRows(); len(rows) != 1 || rows[0][0] != "café-refactor" {
+		t.Errorf("Expected only the accented row to match, got %v", rows)
+	}
+
+	// Backspace removes the whole rune, leaving valid UTF-8
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	m = updated.(TableModel)
+	if m.FilterText != "caf" {
+		t.Errorf("Expected FilterText 'caf' after backspace, got %q", m.FilterText)
+	}
+}
+
+func TestPickerFiltering_UnicodeCaseInsensitive(t *testing.T) {
+	sessions := []*session.Session{
+		session.NewSession("ärger-fix", "uuid-1"),
+		session.NewSession("other", "uuid-2"),
+	}
+	m := NewPicker(sessions, "Select")
+	m.FilterText = "ÄRG"
+
+	filtered := m.filteredSessions()
+	if len(filtered) != 1 || filtered[0].Name != "ärger-fix" {
+		t.Errorf("Expected 'ärger-fix' to match 'ÄRG', got %d sessions", len(filtered))
+	}
+	if !strings.Contains(m.View(), "ärger-fix") {
+		t.Error("View should show the matching session")
+	}
+}
+
+func TestPickerFiltering_AllFields(t *testing.T) {
+	withContext := session.NewSession("bugfix", "uuid-1")
+	withContext.Metadata.Context = "working on ticket GH-123"
+	fork := session.NewSession("experiment", "uuid-2")
+	fork.Metadata.IsForkedSession = true
+	fork.Metadata.ParentSession = "auth-feature"
+
+	m := NewPicker([]*session.Session{withContext, fork}, "Select")
+	m.FilterText = "gh-123"
+	if len(m.filteredSessions()) != 0 {
+		t.Error("Context should not match while filtering by name only")
+	}
+	if !strings.Contains(m.View(), "Filter [name]") {
+		t.Error("Prompt should show the name-only scope")
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlF})
+	m = updated.(PickerModel)
+	if !m.SearchAllFields {
+		t.Fatal("Expected ctrl+f to switch to all fields")
+	}
+	filtered := m.filteredSessions()
+	if len(filtered) != 1 || filtered[0].Name != "bugfix" {
+		t.Errorf("Expected context to match, got %d sessions", len(filtered))
+	}
+	view := m.View()
+	if !strings.Contains(view, "Filter [all fields]") || !strings.Contains(view, "(context)") {
+		t.Errorf("Expected scope prompt and match hint, got:\n%s", view)
+	}
+
+	m.FilterText = "auth"
+	filtered = m.filteredSessions()
+	if len(filtered) != 1 || filtered[0].Name != "experiment" {
+		t.Errorf("Expected parent session to match, got %d sessions", len(filtered))
+	}
+}
+
+func TestPickerFiltering_ScopeToggleWhileTyping(t *testing.T) {
+	m := NewPicker([]*session.Session{session.NewSession("a", "uuid")}, "Select")
+	m.Filtering = true
+	m.FilterText = "abc"
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlF})
+	m = updated.(PickerModel)
+	if !m.SearchAllFields || m.FilterText != "abc" || !m.Filtering {
+		t.Error("Expected ctrl+f to switch scope without touching the filter text")
+	}
+}
+
+func TestParseFilterQuery(t *testing.T) {
+	fields := []string{"Name", "Last Used", "Model"}
+	tests := []struct {
+		text   string
+		free   string
+		fields map[string]string
+	}{
+		{"auth bug", "auth bug", map[string]string{}},
+		{"name:auth", "", map[string]string{"name": "auth"}},
+		{"MODEL:opus refactor last-used:2d", "refactor", map[string]string{"model": "opus", "lastused": "2d"}},
+		{`name:"login bug" x`, "x", map[string]string{"name": "login bug"}},
+		{"name:a name:b", "", map[string]string{"name": "b"}},
+		{"http://example.com", "http://example.com", map[string]string{}},
+		{"name:", "", map[string]string{"name": ""}},
+	}
+	for _, tt := range tests {
+		q := parseFilterQuery(tt.text, fields)
+		if q.Free != tt.free || len(q.Fields) != len(tt.fields) {
+			t.Errorf("parseFilterQuery(%q) = %+v; want free %q and fields %v", tt.text, q, tt.free, tt.fields)
+			continue
+		}
+		for field, want := range tt.fields {
+			if got := q.Fields[field]; got != want {
+				t.Errorf("parseFilterQuery(%q) field %s = %q; want %q", tt.text, field, got, want)
+			}
+		}
+	}
+}
+
+func TestTableFiltering_ColumnScoped(t *testing.T) {
+	m := NewTable([]string{"Name", "Model"}, [][]string{
+		{"opus-notes", "sonnet"},
+		{"auth", "opus"},
+		{"auth-fork", "sonnet"},
+	})
+
+	m.FilterText = "opus"
+	if rows := m.filteredRows(); len(rows) != 2 {
+		t.Errorf("Expected unscoped text to match any cell, got %v", rows)
+	}
+
+	m.FilterText = "model:opus"
+	if rows := m.filteredRows(); len(rows) != 1 || rows[0][0] != "auth" {
+		t.Errorf("Expected only the opus model row, got %v", rows)
+	}
+
+	m.FilterText = "name:auth model:sonnet"
+	if rows := m.filteredRows(); len(rows) != 1 || rows[0][0] != "auth-fork" {
+		t.Errorf("Expected terms to combine, got %v", rows)
+	}
+
+	m.FilterText = "model:"
+	if rows := m.filteredRows(); len(rows) != 3 {
+		t.Errorf("Expected an empty term to match every row, got %v", rows)
+	}
+}
+
+func TestPickerFiltering_FieldScoped(t *testing.T) {
+	parent := session.NewSession("auth", "uuid-1")
+	parent.Metadata.Context = "fork me"
+	fork := session.NewSession("auth-retry", "uuid-2")
+	fork.Metadata.IsForkedSession = true
+	fork.Metadata.ParentSession = "auth"
+	other := session.NewSession("fork-docs", "uuid-3")
+
+	m := NewPicker([]*session.Session{parent, fork, other}, "Select")
+	m.FilterText = "type:fork"
+	if filtered := m.filteredSessions(); len(filtered) != 1 || filtered[0].Name != "auth-retry" {
+		t.Errorf("Expected only the fork, got %d sessions", len(filtered))
+	}
+
+	m.FilterText = "type:session auth"
+	if filtered := m.filteredSessions(); len(filtered) != 1 || filtered[0].Name != "auth" {
+		t.Errorf("Expected free text to still match names, got %d sessions", len(filtered))
+	}
+
+	m.FilterText = "context:fork"
+	if filtered := m.filteredSessions(); len(filtered) != 1 || filtered[0].Name != "auth" {
+		t.Errorf("Expected context to match without ctrl+f when scoped, got %d sessions", len(filtered))
+	}
+}
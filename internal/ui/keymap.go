@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// KeyBinding ties the keys that trigger an action to its help text. Components
+// match input against their bindings, so help generated from them always shows
+// the keys that actually work.
+type KeyBinding struct {
+	Keys  []string // As reported by tea.KeyMsg.String()
+	Label string   // How the keys are shown in help (e.g. "↑/k")
+	Help  string
+}
+
+// Matches reports whether msg is one of the binding's keys.
+func (b KeyBinding) Matches(msg tea.KeyMsg) bool {
+	return slices.Contains(b.Keys, msg.String())
+}
+
+// HelpSection is a titled group of bindings shown in the help overlay.
+type HelpSection struct {
+	Title    string
+	Bindings []KeyBinding
+}
+
+// Bindings shared by the list-style components (table, picker, dashboard)
+var (
+	keyUp     = KeyBinding{Keys: []string{"up", "k"}, Label: "↑/k", Help: "move up"}
+	keyDown   = KeyBinding{Keys: []string{"down", "j"}, Label: "↓/j", Help: "move down"}
+	keyTop    = KeyBinding{Keys: []string{"home", "g"}, Label: "g/home", Help: "jump to first"}
+	keyBottom = KeyBinding{Keys: []string{"end", "G"}, Label: "G/end", Help: "jump to last"}
+	keySelect = KeyBinding{Keys: []string{"enter", " "}, Label: "enter", Help: "select"}
+	keyFilter = KeyBinding{Keys: []string{"/"}, Label: "/", Help: "filter"}
+	keyBack   = KeyBinding{Keys: []string{"esc"}, Label: "esc", Help: "clear filter, or cancel"}
+	keyQuit   = KeyBinding{Keys: []string{"q", "ctrl+c"}, Label: "q", Help: "quit"}
+	keyHelp   = KeyBinding{Keys: []string{"?"}, Label: "?", Help: "toggle help"}
+	keySort   = KeyBinding{Keys: []string{"1", "2", "3", "4", "5", "6", "7", "8", "9"}, Label: "1-9", Help: "sort by column (again to reverse)"}
+
+	keySortMenu      = KeyBinding{Keys: []string{"s"}, Label: "s", Help: "choose the sort column and direction"}
+	keySortDirection = KeyBinding{Keys: []string{"tab", "left", "right", "h", "l"}, Label: "tab/←/→", Help: "ascending / descending"}
+	keySortApply     = KeyBinding{Keys: []string{"enter", " "}, Label: "enter", Help: "sort"}
+	keySortCancel    = KeyBinding{Keys: []string{"esc", "s", "q"}, Label: "esc", Help: "close without sorting"}
+
+	keyFilterApply  = KeyBinding{Keys: []string{"enter"}, Label: "enter", Help: "apply filter"}
+	keyFilterCancel = KeyBinding{Keys: []string{"esc"}, Label: "esc", Help: "clear filter"}
+	keyFilterDelete = KeyBinding{Keys: []string{"backspace"}, Label: "backspace", Help: "delete character"}
+	keyFilterWord   = KeyBinding{Keys: []string{"ctrl+w", "alt+backspace"}, Label: "ctrl+w", Help: "delete word"}
+)
+
+// filterHelpSection documents the keys available while typing a filter
+var filterHelpSection = HelpSection{
+	Title:    "While filtering",
+	Bindings: []KeyBinding{keyFilterApply, keyFilterCancel, keyFilterDelete, keyFilterWord},
+}
+
+// shortHelp renders bindings as a single footer line, e.g. "enter select · ? help · q quit".
+func shortHelp(bindings ...KeyBinding) string {
+	parts := make([]string, len(bindings))
+	for i, b := range bindings {
+		parts[i] = b.Label + " " + b.Help
+	}
+	return strings.Join(parts, " · ")
+}
+
+// renderHelpOverlay renders a full-screen keybinding reference. The box is
+// centered when the terminal size is known (width and height > 0).
+func renderHelpOverlay(title string, sections []HelpSection, width, height int) string {
+	labelWidth := 0
+	for _, section := range sections {
+		for _, b := range section.Bindings {
+			labelWidth = max(labelWidth, lipgloss.Width(b.Label))
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(BoldStyle.Render(title + " · Keyboard shortcuts"))
+	for _, section := range sections {
+		b.WriteString("\n\n")
+		b.WriteString(InfoStyle.Render(section.Title))
+		for _, binding := range section.Bindings {
+			label := lipgloss.NewStyle().Width(labelWidth).Render(binding.Label)
+			fmt.Fprintf(&b, "\n  %s  %s", BoldStyle.Render(label), binding.Help)
+		}
+	}
+	b.WriteString("\n\n")
+	b.WriteString(DimStyle.Italic(true).Render("Press any key to close"))
+
+	box := BoxStyle.BorderForeground(InfoColor).Padding(1, 2).Render(b.String())
+	if width > 0 && height > 0 {
+		return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, box)
+	}
+	return box
+}
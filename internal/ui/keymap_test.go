@@ -0,0 +1,101 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/fgrehm/clotilde/internal/session"
+)
+
+var helpKey = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}}
+
+func TestKeyBindingMatches(t *testing.T) {
+	if !keyUp.Matches(tea.KeyMsg{Type: tea.KeyUp}) {
+		t.Error("Expected up arrow to match keyUp")
+	}
+	if !keyUp.Matches(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'k'}}) {
+		t.Error("Expected 'k' to match keyUp")
+	}
+	if keyUp.Matches(tea.KeyMsg{Type: tea.KeyDown}) {
+		t.Error("Expected down arrow not to match keyUp")
+	}
+}
+
+func TestShortHelp(t *testing.T) {
+	got := shortHelp(keySelect, keyHelp)
+	if got != "enter select · ? toggle help" {
+		t.Errorf("Unexpected short help: %q", got)
+	}
+}
+
+func TestHelpOverlay_ListsEveryBinding(t *testing.T) {
+	m := NewTable([]string{"Name"}, [][]string{{"a"}}).WithSorting()
+	sections := m.helpSections()
+	view := renderHelpOverlay("Table", sections, 0, 0)
+
+	for _, section := range sections {
+		for _, b := range section.Bindings {
+			if !strings.Contains(view, b.Help) {
+				t.Errorf("Overlay should describe %q", b.Help)
+			}
+		}
+	}
+}
+
+func TestTableHelpToggle(t *testing.T) {
+	m := NewTable([]string{"Name"}, [][]string{{"a"}})
+
+	updated, _ := m.Update(helpKey)
+	m = updated.(TableModel)
+	if !m.ShowHelp {
+		t.Fatal("Expected '?' to open the help overlay")
+	}
+	if !strings.Contains(m.View(), "Keyboard shortcuts") {
+		t.Error("Expected the view to render the help overlay")
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	m = updated.(TableModel)
+	if m.ShowHelp || m.Cancelled || cmd != nil {
+		t.Error("Expected any key to close the overlay without acting on it")
+	}
+}
+
+func TestTableHelpKeyIsFilterTextWhileFiltering(t *testing.T) {
+	m := NewTable([]string{"Name"}, [][]string{{"a"}})
+	m.Filtering = true
+
+	updated, _ := m.Update(helpKey)
+	m = updated.(TableModel)
+	if m.ShowHelp || m.FilterText != "?" {
+		t.Error("Expected '?' to be typed into the filter")
+	}
+}
+
+func TestPickerHelpToggle(t *testing.T) {
+	m := NewPicker([]*session.Session{session.NewSession("a", "uuid")}, "Pick")
+
+	updated, _ := m.Update(helpKey)
+	m = updated.(PickerModel)
+	if !m.ShowHelp || !strings.Contains(m.View(), "Keyboard shortcuts") {
+		t.Error("Expected '?' to open the picker help overlay")
+	}
+}
+
+func TestDashboardHelpToggle(t *testing.T) {
+	m := NewDashboard(nil)
+
+	updated, _ := m.Update(helpKey)
+	m = updated.(DashboardModel)
+	if !m.ShowHelp || !strings.Contains(m.View(), "Keyboard shortcuts") {
+		t.Error("Expected '?' to open the dashboard help overlay")
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(DashboardModel)
+	if m.ShowHelp || m.Cancelled {
+		t.Error("Expected esc to close the overlay without quitting")
+	}
+}
@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// visibleRange returns the [start, end) slice of a list of total items that
+// fits in limit lines while keeping the cursor roughly centered. A limit of 0
+// or less shows everything (terminal size unknown).
+func visibleRange(cursor, total, limit int) (start, end int) {
+	if limit <= 0 || total <= limit {
+		return 0, total
+	}
+	start = max(cursor-limit/2, 0)
+	end = start + limit
+	if end > total {
+		end = total
+		start = max(end-limit, 0)
+	}
+	return start, end
+}
+
+// scrollIndicator describes which part of a list is visible, e.g. "11-20 of 42".
+// Returns "" when the whole list is shown.
+func scrollIndicator(start, end, total int) string {
+	if start == 0 && end == total {
+		return ""
+	}
+	return fmt.Sprintf("%d-%d of %d", start+1, end, total)
+}
+
+// truncateWidth shortens s to at most width terminal cells, marking the cut
+// with "…". Wide runes (CJK, emoji) count as two cells. s must be unstyled.
+func truncateWidth(s string, width int) string {
+	if lipgloss.Width(s) <= width {
+		return s
+	}
+	if width <= 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	used := 0
+	for _, r := range s {
+		w := lipgloss.Width(string(r))
+		if used+w > width-1 {
+			break
+		}
+		b.WriteRune(r)
+		used += w
+	}
+	return b.String() + "…"
+}
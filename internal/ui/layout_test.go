@@ -0,0 +1,51 @@
+package ui
+
+import "testing"
+
+func TestVisibleRange(t *testing.T) {
+	tests := []struct {
+		cursor, total, limit int
+		start, end           int
+	}{
+		{0, 5, 0, 0, 5},  // unknown size
+		{3, 5, 10, 0, 5}, // everything fits
+		{0, 20, 10, 0, 10},
+		{12, 20, 10, 7, 17},
+		{19, 20, 10, 10, 20},
+	}
+	for _, tt := range tests {
+		start, end := visibleRange(tt.cursor, tt.total, tt.limit)
+		if start != tt.start || end != tt.end {
+			t.Errorf("visibleRange(%d, %d, %d) = %d, %d; want %d, %d",
+				tt.cursor, tt.total, tt.limit, start, end, tt.start, tt.end)
+		}
+	}
+}
+
+func TestScrollIndicator(t *testing.T) {
+	if got := scrollIndicator(0, 5, 5); got != "" {
+		t.Errorf("Expected no indicator for a fully visible list, got %q", got)
+	}
+	if got := scrollIndicator(10, 20, 42); got != "11-20 of 42" {
+		t.Errorf("Unexpected indicator: %q", got)
+	}
+}
+
+func TestTruncateWidth(t *testing.T) {
+	tests := []struct {
+		in    string
+		width int
+		want  string
+	}{
+		{"short", 10, "short"},
+		{"exactly-10", 10, "exactly-10"},
+		{"much-too-long", 8, "much-to…"},
+		{"日本語のセッション", 7, "日本語…"},
+		{"anything", 0, ""},
+	}
+	for _, tt := range tests {
+		if got := truncateWidth(tt.in, tt.width); got != tt.want {
+			t.Errorf("truncateWidth(%q, %d) = %q; want %q", tt.in, tt.width, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/glamour/styles"
+)
+
+// markdownWidth is the column markdown is wrapped at.
+const markdownWidth = 80
+
+// RenderMarkdown renders markdown for the terminal. With styled false (output
+// redirected), it uses glamour's plain style, which keeps the structure but
+// emits no escape codes, and drops the padding glamour adds to each line.
+func RenderMarkdown(markdown string, styled bool) (string, error) {
+	style := glamour.WithStandardStyle(styles.NoTTYStyle)
+	if styled {
+		style = glamour.WithAutoStyle()
+	}
+	renderer, err := glamour.NewTermRenderer(style, glamour.WithWordWrap(markdownWidth))
+	if err != nil {
+		return "", err
+	}
+	rendered, err := renderer.Render(markdown)
+	if err != nil || styled {
+		return rendered, err
+	}
+	lines := strings.Split(rendered, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return strings.Join(lines, "\n"), nil
+}
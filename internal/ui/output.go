@@ -1,6 +1,10 @@
 package ui
 
-import "fmt"
+import (
+	"fmt"
+	"os"
+	"strings"
+)
 
 // Success renders a success message with a green checkmark
 func Success(msg string) string {
@@ -19,3 +23,28 @@ func Info(msg string) string {
 	icon := InfoStyle.Render("ℹ")
 	return fmt.Sprintf("%s %s", icon, msg)
 }
+
+// Command renders a command about to run, prefixed with an arrow
+func Command(line string) string {
+	return fmt.Sprintf("%s %s", commandArrow(), line)
+}
+
+// commandArrow is "→", or "->" when the locale isn't UTF-8: such terminals
+// (and logs read as Latin-1) would show the arrow as "â†’"
+func commandArrow() string {
+	if utf8Locale() {
+		return "→"
+	}
+	return "->"
+}
+
+// utf8Locale reports whether the locale's character set, from the first of
+// LC_ALL, LC_CTYPE and LANG that is set, is UTF-8
+func utf8Locale() bool {
+	for _, key := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if value := strings.ToLower(os.Getenv(key)); value != "" {
+			return strings.Contains(value, "utf-8") || strings.Contains(value, "utf8")
+		}
+	}
+	return false
+}
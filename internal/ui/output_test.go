@@ -43,3 +43,24 @@ func TestInfo(t *testing.T) {
 		t.Error("Info() did not include info icon")
 	}
 }
+
+func TestCommand(t *testing.T) {
+	tests := []struct {
+		lcAll, lang string
+		want        string
+	}{
+		{"", "en_US.UTF-8", "→ claude --resume x"},
+		{"", "pt_BR.utf8", "→ claude --resume x"},
+		{"C", "en_US.UTF-8", "-> claude --resume x"},
+		{"", "en_US.ISO-8859-1", "-> claude --resume x"},
+		{"", "", "-> claude --resume x"},
+	}
+	for _, tt := range tests {
+		t.Setenv("LC_ALL", tt.lcAll)
+		t.Setenv("LC_CTYPE", "")
+		t.Setenv("LANG", tt.lang)
+		if got := Command("claude --resume x"); got != tt.want {
+			t.Errorf("Command() with LC_ALL=%q LANG=%q = %q, want %q", tt.lcAll, tt.lang, got, tt.want)
+		}
+	}
+}
@@ -2,13 +2,17 @@ package ui
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
 	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/timing"
+	"github.com/fgrehm/clotilde/internal/util"
 )
 
 // PickerModel represents the session picker state
@@ -21,20 +25,91 @@ type PickerModel struct {
 	FilterText  string
 	Filtering   bool
 	ShowPreview bool // Show preview pane with session metadata
+	ShowHelp    bool // Show the help overlay
+
+	// SearchAllFields makes the filter match context and parent session too,
+	// not just the session name (toggled with ctrl+f)
+	SearchAllFields bool
+
+	// PreviewPercent is the share of the terminal width given to the preview pane
+	PreviewPercent int
+
+	// Yolo names the sessions running with bypassPermissions, flagged in red
+	Yolo map[string]bool
+
+	// Starred holds the stars toggled with f, by session name: the new value
+	// for the caller to save
+	Starred map[string]bool
+
+	previewEnabled bool // Preview pane can be toggled (set by WithPreview)
+	starring       bool // Sessions can be starred (set by WithStarring)
+	width          int  // Terminal size, 0 until known
+	height         int
+	filterInput    textinput.Model
 }
 
+// Preview pane sizing, in percent of the terminal width
+const (
+	DefaultPreviewPercent = 50
+	MinPreviewPercent     = 20
+	MaxPreviewPercent     = 80
+	previewPercentStep    = 10
+)
+
+// pickerMinSplitWidth is the narrowest terminal that still fits the list and
+// preview side by side; below it the preview is hidden automatically.
+const pickerMinSplitWidth = 70
+
+// Picker-specific bindings
+var (
+	keyTogglePreview = KeyBinding{Keys: []string{"p"}, Label: "p", Help: "toggle preview"}
+	keyShrinkPreview = KeyBinding{Keys: []string{"<", "["}, Label: "</[", Help: "shrink preview"}
+	keyGrowPreview   = KeyBinding{Keys: []string{">", "]"}, Label: ">/]", Help: "grow preview"}
+	keyFilterScope   = KeyBinding{Keys: []string{"ctrl+f"}, Label: "ctrl+f", Help: "search name only / all fields"}
+	keyStar          = KeyBinding{Keys: []string{"f"}, Label: "f", Help: "star / unstar (starred sessions sort first)"}
+)
+
 // NewPicker creates a new session picker
 func NewPicker(sessions []*session.Session, title string) PickerModel {
 	return PickerModel{
-		Sessions: sessions,
-		Title:    title,
-		Cursor:   0,
+		Sessions:    sessions,
+		Title:       title,
+		Cursor:      0,
+		filterInput: newFilterInput(),
 	}
 }
 
 // WithPreview enables the preview pane
 func (m PickerModel) WithPreview() PickerModel {
 	m.ShowPreview = true
+	m.previewEnabled = true
+	if m.PreviewPercent == 0 {
+		m.PreviewPercent = DefaultPreviewPercent
+	}
+	return m
+}
+
+// WithYolo flags the named sessions as running with bypassPermissions
+func (m PickerModel) WithYolo(yolo map[string]bool) PickerModel {
+	m.Yolo = yolo
+	return m
+}
+
+// WithStarring lets f star and unstar sessions, recording the changes in
+// Starred
+func (m PickerModel) WithStarring() PickerModel {
+	m.starring = true
+	return m
+}
+
+// WithPreviewLayout applies saved layout preferences to a picker with preview.
+// A zero percent keeps the default; out-of-range values are clamped.
+func (m PickerModel) WithPreviewLayout(visible bool, percent int) PickerModel {
+	m.ShowPreview = visible
+	if percent == 0 {
+		percent = DefaultPreviewPercent
+	}
+	m.PreviewPercent = min(max(percent, MinPreviewPercent), MaxPreviewPercent)
 	return m
 }
 
@@ -46,52 +121,58 @@ func (m PickerModel) Init() tea.Cmd {
 // Update handles keyboard input
 func (m PickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		return m, nil
+
 	case tea.KeyMsg:
+		// Any key closes the help overlay (ctrl+c still quits)
+		if m.ShowHelp {
+			if msg.String() == "ctrl+c" {
+				m.Cancelled = true
+				return m, tea.Quit
+			}
+			m.ShowHelp = false
+			return m, nil
+		}
+
+		// Works while typing too (ctrl+f isn't text)
+		if keyFilterScope.Matches(msg) {
+			m.SearchAllFields = !m.SearchAllFields
+			m.Cursor = 0
+			return m, nil
+		}
+
 		// Handle filter mode separately
 		if m.Filtering {
-			switch msg.String() {
-			case "esc":
+			switch {
+			case keyFilterCancel.Matches(msg):
 				// Exit filter mode, clear filter
 				m.Filtering = false
 				m.FilterText = ""
 				m.Cursor = 0
-				return m, nil
 
-			case "enter":
+			case keyFilterApply.Matches(msg):
 				// Exit filter mode, keep filter
 				m.Filtering = false
-				return m, nil
-
-			case "backspace":
-				if len(m.FilterText) > 0 {
-					m.FilterText = m.FilterText[:len(m.FilterText)-1]
-					m.Cursor = 0 // Reset cursor when filter changes
-				}
-				return m, nil
 
 			default:
-				// Add character to filter
-				if len(msg.Runes) == 1 {
-					m.FilterText += string(msg.Runes[0])
+				// Typing, backspace, and cursor movement go to the text input
+				if text := updateFilterInput(&m.filterInput, m.FilterText, msg); text != m.FilterText {
+					m.FilterText = text
 					m.Cursor = 0 // Reset cursor when filter changes
 				}
-				return m, nil
 			}
+			return m, nil
 		}
 
 		// Normal mode (not filtering)
-		switch msg.String() {
-		case "ctrl+c":
+		switch {
+		case keyQuit.Matches(msg):
 			m.Cancelled = true
 			return m, tea.Quit
 
-		case "q":
-			if !m.Filtering {
-				m.Cancelled = true
-				return m, tea.Quit
-			}
-
-		case "esc":
+		case keyBack.Matches(msg):
 			if m.FilterText != "" {
 				// Clear existing filter
 				m.FilterText = ""
@@ -101,50 +182,102 @@ func (m PickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.Cancelled = true
 			return m, tea.Quit
 
-		case "/":
+		case keyFilter.Matches(msg):
 			// Enter filter mode
 			m.Filtering = true
-			return m, nil
+			syncFilterInput(&m.filterInput, m.FilterText)
+
+		case keyHelp.Matches(msg):
+			m.ShowHelp = true
+
+		case m.previewEnabled && keyTogglePreview.Matches(msg):
+			m.ShowPreview = !m.ShowPreview
+
+		case m.previewEnabled && keyShrinkPreview.Matches(msg):
+			m.PreviewPercent = max(m.PreviewPercent-previewPercentStep, MinPreviewPercent)
+			m.ShowPreview = true
+
+		case m.previewEnabled && keyGrowPreview.Matches(msg):
+			m.PreviewPercent = min(m.PreviewPercent+previewPercentStep, MaxPreviewPercent)
+			m.ShowPreview = true
 
-		case "enter", " ":
+		case m.starring && keyStar.Matches(msg):
+			filtered := m.filteredSessions()
+			if len(filtered) > 0 {
+				m.toggleStar(filtered[m.Cursor])
+			}
+
+		case keySelect.Matches(msg):
 			filtered := m.filteredSessions()
 			if len(filtered) > 0 {
 				m.Selected = filtered[m.Cursor]
 			}
 			return m, tea.Quit
 
-		case "up", "k":
+		case keyUp.Matches(msg):
 			if m.Cursor > 0 {
 				m.Cursor--
 			}
-			return m, nil
 
-		case "down", "j":
+		case keyDown.Matches(msg):
 			filtered := m.filteredSessions()
 			if m.Cursor < len(filtered)-1 {
 				m.Cursor++
 			}
-			return m, nil
 
-		case "home", "g":
+		case keyTop.Matches(msg):
 			m.Cursor = 0
-			return m, nil
 
-		case "end", "G":
+		case keyBottom.Matches(msg):
 			filtered := m.filteredSessions()
 			if len(filtered) > 0 {
 				m.Cursor = len(filtered) - 1
 			}
-			return m, nil
 		}
 	}
 
 	return m, nil
 }
 
+// toggleStar stars or unstars sess and re-sorts the sessions, starred first,
+// keeping the cursor on sess
+func (m *PickerModel) toggleStar(sess *session.Session) {
+	sess.Metadata.Starred = !sess.Metadata.Starred
+	if m.Starred == nil {
+		m.Starred = map[string]bool{}
+	}
+	m.Starred[sess.Name] = sess.Metadata.Starred
+
+	m.Sessions = slices.Clone(m.Sessions)
+	session.SortStarredFirst(m.Sessions)
+	m.Cursor = max(slices.Index(m.filteredSessions(), sess), 0)
+}
+
+// helpSections lists the picker's keybindings for the help overlay
+func (m PickerModel) helpSections() []HelpSection {
+	sections := []HelpSection{
+		{Title: "Navigation", Bindings: []KeyBinding{keyUp, keyDown, keyTop, keyBottom}},
+		{Title: "Actions", Bindings: []KeyBinding{keySelect, keyFilter, keyBack, keyQuit, keyHelp}},
+	}
+	sections[1].Bindings = append(sections[1].Bindings, keyFilterScope)
+	if m.starring {
+		sections[1].Bindings = append(sections[1].Bindings, keyStar)
+	}
+	if m.previewEnabled {
+		sections = append(sections, HelpSection{
+			Title:    "Preview",
+			Bindings: []KeyBinding{keyTogglePreview, keyShrinkPreview, keyGrowPreview},
+		})
+	}
+	return append(sections, filterHelpSection)
+}
+
 // View renders the session picker
 func (m PickerModel) View() string {
-	if m.ShowPreview {
+	if m.ShowHelp {
+		return renderHelpOverlay(m.Title, m.helpSections(), m.width, m.height)
+	}
+	if m.ShowPreview && !m.tooNarrowForPreview() {
 		return m.viewWithPreview()
 	}
 	return m.viewSimple()
@@ -161,16 +294,9 @@ func (m PickerModel) viewSimple() string {
 
 	// Filter input (if active or has text)
 	if m.Filtering || m.FilterText != "" {
-		filterPrefix := "Filter: "
-		if m.Filtering {
-			filterPrefix = "Filter (type to search): "
-		}
 		filterStyle := InfoStyle
-		b.WriteString(filterStyle.Render(filterPrefix))
-		b.WriteString(m.FilterText)
-		if m.Filtering {
-			b.WriteString("█") // Cursor
-		}
+		b.WriteString(filterStyle.Render(m.filterPrompt()))
+		b.WriteString(renderFilterInput(m.filterInput, m.FilterText, m.Filtering))
 		b.WriteString("\n\n")
 	}
 
@@ -190,28 +316,17 @@ func (m PickerModel) viewSimple() string {
 		return b.String()
 	}
 
-	// Session list
-	for i, sess := range filtered {
+	// Session list (paginated once the terminal size is known)
+	start, end := visibleRange(m.Cursor, len(filtered), m.visibleRows())
+	for i := start; i < end; i++ {
+		sess := filtered[i]
 		cursor := " "
 		if m.Cursor == i {
 			cursor = ">"
 		}
 
-		// Build session line
-		sessionLine := m.formatSessionLine(sess)
-
-		// Highlight matching text
-		if m.FilterText != "" {
-			sessionLine = m.highlightMatch(sessionLine, m.FilterText)
-		}
-
-		// Highlight selected
-		if m.Cursor == i {
-			sessionLine = lipgloss.NewStyle().
-				Foreground(SuccessColor).
-				Bold(true).
-				Render(sessionLine)
-		}
+		// Build session line, highlighting the selection and filter matches
+		sessionLine := m.formatSessionLine(sess, m.lineStyle(i))
 
 		fmt.Fprintf(&b, "%s %s\n", cursor, sessionLine)
 	}
@@ -219,15 +334,59 @@ func (m PickerModel) viewSimple() string {
 	// Help text
 	b.WriteString("\n")
 	helpStyle := DimStyle.Italic(true)
-	if m.FilterText != "" {
-		b.WriteString(helpStyle.Render("(Esc to clear filter, / to edit, ↑/↓ to navigate, enter to select)"))
-	} else {
-		b.WriteString(helpStyle.Render("(/ to filter, ↑/↓ or j/k to navigate, enter to select, q to quit)"))
+	var help string
+	switch {
+	case m.FilterText != "":
+		help = shortHelp(keyFilterCancel, keyFilter, keySelect, keyHelp)
+	case m.previewEnabled && m.ShowPreview:
+		// Preview is on but doesn't fit
+		help = "preview hidden (too narrow) · " + shortHelp(keySelect, keyFilter, keyHelp, keyQuit)
+	case m.previewEnabled:
+		help = shortHelp(keySelect, keyFilter, keyTogglePreview, keyHelp, keyQuit)
+	default:
+		help = shortHelp(keySelect, keyFilter, keyHelp, keyQuit)
 	}
+	if position := scrollIndicator(start, end, len(filtered)); position != "" {
+		help = position + " · " + help
+	}
+	b.WriteString(helpStyle.Render(help))
 
+	if m.width > 0 {
+		// Cut long lines instead of letting the terminal wrap them
+		return lipgloss.NewStyle().MaxWidth(m.width).Render(b.String())
+	}
 	return b.String()
 }
 
+// visibleRows returns how many sessions fit in the list, or 0 when the
+// terminal size is unknown.
+func (m PickerModel) visibleRows() int {
+	if m.height == 0 {
+		return 0
+	}
+	overhead := 4 // title, blank line, blank line, help
+	if m.Filtering || m.FilterText != "" {
+		overhead += 2
+	}
+	return max(m.height-overhead, 1)
+}
+
+// tooNarrowForPreview reports whether the terminal is known to be too narrow
+// to show the list and preview side by side.
+func (m PickerModel) tooNarrowForPreview() bool {
+	return m.width > 0 && m.width < pickerMinSplitWidth
+}
+
+// paneWidths splits the terminal width between the list and preview panes
+// (2 columns go to the spacer). Returns zeros when the width is unknown.
+func (m PickerModel) paneWidths() (list, preview int) {
+	if m.width == 0 {
+		return 0, 0
+	}
+	preview = m.width * m.PreviewPercent / 100
+	return m.width - preview - 2, preview
+}
+
 // viewWithPreview renders the picker with a preview pane (split view)
 func (m PickerModel) viewWithPreview() string {
 	filtered := m.filteredSessions()
@@ -243,6 +402,12 @@ func (m PickerModel) viewWithPreview() string {
 		previewPane = DimStyle.Italic(true).Render("No session selected")
 	}
 
+	// Fit panes to the terminal once its size is known
+	if listWidth, previewWidth := m.paneWidths(); listWidth > 0 {
+		listPane = lipgloss.NewStyle().Width(listWidth).MaxWidth(listWidth).Render(listPane)
+		previewPane = lipgloss.NewStyle().MaxWidth(previewWidth).Render(previewPane)
+	}
+
 	// Join panes side by side
 	return lipgloss.JoinHorizontal(
 		lipgloss.Top,
@@ -263,16 +428,9 @@ func (m PickerModel) renderListPane(filtered []*session.Session) string {
 
 	// Filter input (if active or has text)
 	if m.Filtering || m.FilterText != "" {
-		filterPrefix := "Filter: "
-		if m.Filtering {
-			filterPrefix = "Filter: "
-		}
 		filterStyle := InfoStyle
-		b.WriteString(filterStyle.Render(filterPrefix))
-		b.WriteString(m.FilterText)
-		if m.Filtering {
-			b.WriteString("█")
-		}
+		b.WriteString(filterStyle.Render(m.filterPrompt()))
+		b.WriteString(renderFilterInput(m.filterInput, m.FilterText, m.Filtering))
 		b.WriteString("\n\n")
 	}
 
@@ -289,14 +447,12 @@ func (m PickerModel) renderListPane(filtered []*session.Session) string {
 		return b.String()
 	}
 
-	// Session list (limit to visible area)
-	const maxVisible = 10
-	start := max(m.Cursor-maxVisible/2, 0)
-	end := start + maxVisible
-	if end > len(filtered) {
-		end = len(filtered)
-		start = max(end-maxVisible, 0)
+	// Session list (limit to visible area; 10 rows until the size is known)
+	limit := m.visibleRows()
+	if limit == 0 {
+		limit = 10
 	}
+	start, end := visibleRange(m.Cursor, len(filtered), limit)
 
 	for i := start; i < end; i++ {
 		sess := filtered[i]
@@ -306,15 +462,7 @@ func (m PickerModel) renderListPane(filtered []*session.Session) string {
 		}
 
 		// Build session line with "last used" info
-		sessionLine := m.formatSessionLineWithTime(sess)
-
-		// Highlight selected
-		if m.Cursor == i {
-			sessionLine = lipgloss.NewStyle().
-				Foreground(SuccessColor).
-				Bold(true).
-				Render(sessionLine)
-		}
+		sessionLine := m.formatSessionLineWithTime(sess, m.lineStyle(i))
 
 		fmt.Fprintf(&b, "%s %s\n", cursor, sessionLine)
 	}
@@ -322,7 +470,11 @@ func (m PickerModel) renderListPane(filtered []*session.Session) string {
 	// Help text
 	b.WriteString("\n")
 	helpStyle := DimStyle.Italic(true)
-	b.WriteString(helpStyle.Render("↑/↓ navigate · / filter · enter select · q quit"))
+	help := shortHelp(keySelect, keyFilter, keyTogglePreview, keyHelp, keyQuit)
+	if position := scrollIndicator(start, end, len(filtered)); position != "" {
+		help = position + " · " + help
+	}
+	b.WriteString(helpStyle.Render(help))
 
 	return b.String()
 }
@@ -338,7 +490,7 @@ func (m PickerModel) renderPreviewPane(sess *session.Session) string {
 	} else if sess.Metadata.IsIncognito {
 		nameStyle = lipgloss.NewStyle().Foreground(IncognitoColor).Bold(true)
 	}
-	lines = append(lines, nameStyle.Render(sess.Name))
+	lines = append(lines, nameStyle.Render(sess.Name)+starIndicator(sess.Metadata.Starred))
 	lines = append(lines, "")
 
 	// Session type
@@ -352,9 +504,26 @@ func (m PickerModel) renderPreviewPane(sess *session.Session) string {
 		typeValue := lipgloss.NewStyle().Foreground(IncognitoColor).Render("Incognito")
 		lines = append(lines, DimStyle.Render(typeLabel))
 		lines = append(lines, "  "+typeValue)
+		lines = append(lines, DimStyle.Render("  Auto-deletes on exit"))
 	}
 	lines = append(lines, "")
 
+	if m.Yolo[sess.Name] {
+		lines = append(lines, DimStyle.Render("Permission mode:"))
+		lines = append(lines, "  "+lipgloss.NewStyle().Foreground(ErrorColor).Bold(true).Render("bypassPermissions (yolo)"))
+		lines = append(lines, "")
+	}
+
+	if sess.Metadata.Context != "" {
+		lines = append(lines, DimStyle.Render("Context:"))
+		lines = append(lines, "  "+highlightMatches(sess.Metadata.Context, m.contextFilter(), lipgloss.NewStyle()))
+		lines = append(lines, "")
+	}
+
+	lines = append(lines, DimStyle.Render("Status:"))
+	lines = append(lines, "  "+RenderStatus(sess.Status()))
+	lines = append(lines, "")
+
 	// Timestamps
 	lines = append(lines, DimStyle.Render("Created:"))
 	lines = append(lines, "  "+sess.Metadata.Created.Format("2006-01-02 15:04"))
@@ -363,12 +532,49 @@ func (m PickerModel) renderPreviewPane(sess *session.Session) string {
 	lines = append(lines, DimStyle.Render("Last accessed:"))
 	lines = append(lines, "  "+formatTimeAgo(sess.Metadata.LastAccessed))
 
-	return InfoBoxStyle.Render(strings.Join(lines, "\n"))
+	if !sess.Metadata.ExpiresAt.IsZero() {
+		now := time.Now()
+		expires := sess.Metadata.ExpiresAt.Format("2006-01-02 15:04")
+		if sess.IsExpired(now) {
+			expires = lipgloss.NewStyle().Foreground(WarningColor).Render(expires + " (expired)")
+		} else {
+			expires += DimStyle.Render(" (in " + util.FormatDuration(sess.Metadata.ExpiresAt.Sub(now)) + ")")
+		}
+		lines = append(lines, "")
+		lines = append(lines, DimStyle.Render("Expires:"))
+		lines = append(lines, "  "+expires)
+	}
+
+	if exit := sess.Metadata.LastExit; exit != nil {
+		summary := exit.Summary()
+		if exit.Crashed() {
+			summary = lipgloss.NewStyle().Foreground(ErrorColor).Render(summary)
+		}
+		lines = append(lines, "")
+		lines = append(lines, DimStyle.Render("Last exit:"))
+		lines = append(lines, "  "+summary+" "+formatTimeAgo(exit.At))
+	}
+
+	box := InfoBoxStyle
+	if _, previewWidth := m.paneWidths(); previewWidth > 0 {
+		// lipgloss widths exclude the border
+		box = box.Width(previewWidth - 2)
+	}
+	return box.Render(strings.Join(lines, "\n"))
+}
+
+// lineStyle returns the style for the session name on row i
+func (m PickerModel) lineStyle(i int) lipgloss.Style {
+	if m.Cursor == i {
+		return lipgloss.NewStyle().Foreground(SuccessColor).Bold(true)
+	}
+	return lipgloss.NewStyle()
 }
 
-// formatSessionLine formats a single session for display
-func (m PickerModel) formatSessionLine(sess *session.Session) string {
-	name := sess.Name
+// formatSessionLine formats a single session for display, rendering the name
+// with style and highlighting filter matches in it
+func (m PickerModel) formatSessionLine(sess *session.Session, style lipgloss.Style) string {
+	name := highlightMatches(sess.Name, m.filterQuery().highlightFor("name"), style) + starIndicator(sess.Metadata.Starred)
 
 	// Add type indicator
 	typeIndicator := ""
@@ -379,21 +585,71 @@ func (m PickerModel) formatSessionLine(sess *session.Session) string {
 		typeStyle := lipgloss.NewStyle().Foreground(IncognitoColor)
 		typeIndicator = typeStyle.Render(" [incognito]")
 	}
+	typeIndicator += statusIndicator(sess.Status())
+	if m.Yolo[sess.Name] {
+		typeIndicator += yoloIndicator()
+	}
 
-	return name + typeIndicator
+	return name + typeIndicator + m.matchHint(sess)
+}
+
+// contextFilter returns the filter to highlight in the preview's context
+func (m PickerModel) contextFilter() string {
+	query := m.filterQuery()
+	if _, ok := query.Fields["context"]; ok || m.SearchAllFields {
+		return query.highlightFor("context")
+	}
+	return ""
 }
 
-// filteredSessions returns sessions that match the current filter
+// pickerFilterFields are the fields filter terms can be scoped to, as in
+// "type:fork status:idle"
+var pickerFilterFields = []string{"name", "context", "parent", "status", "type"}
+
+// filterQuery parses the filter text
+func (m PickerModel) filterQuery() filterQuery {
+	return parseFilterQuery(m.FilterText, pickerFilterFields)
+}
+
+// matchesScopedTerms reports whether sess satisfies every term of query
+// scoped to a field
+func matchesScopedTerms(sess *session.Session, query filterQuery) bool {
+	sessionType := "session"
+	if sess.Metadata.IsForkedSession {
+		sessionType = "fork"
+	} else if sess.Metadata.IsIncognito {
+		sessionType = "incognito"
+	}
+	return query.matchesField("name", sess.Name) &&
+		query.matchesField("context", sess.Metadata.Context) &&
+		query.matchesField("parent", sess.Metadata.ParentSession) &&
+		query.matchesField("status", string(sess.Status())) &&
+		query.matchesField("type", sessionType)
+}
+
+// filterPrompt labels the filter input with the fields being searched
+func (m PickerModel) filterPrompt() string {
+	if m.SearchAllFields {
+		return "Filter [all fields]: "
+	}
+	return "Filter [name]: "
+}
+
+// filteredSessions returns sessions that match the current filter: every
+// scoped term, and the rest of the text in the name (or any field when
+// searching all fields)
 func (m PickerModel) filteredSessions() []*session.Session {
 	if m.FilterText == "" {
 		return m.Sessions
 	}
 
+	query := m.filterQuery()
 	var filtered []*session.Session
-	lowerFilter := strings.ToLower(m.FilterText)
-
 	for _, sess := range m.Sessions {
-		if strings.Contains(strings.ToLower(sess.Name), lowerFilter) {
+		if !matchesScopedTerms(sess, query) {
+			continue
+		}
+		if query.Free == "" || containsFold(sess.Name, query.Free) || m.matchedField(sess) != "" {
 			filtered = append(filtered, sess)
 		}
 	}
@@ -401,16 +657,36 @@ func (m PickerModel) filteredSessions() []*session.Session {
 	return filtered
 }
 
-// highlightMatch highlights the matching part of the text (simple version)
-func (m PickerModel) highlightMatch(text, filter string) string {
-	// For now, just return the text as-is
-	// A full implementation would highlight the matching substring
-	return text
+// matchedField returns the name of the first non-name field matching the
+// filter when searching all fields, or "".
+func (m PickerModel) matchedField(sess *session.Session) string {
+	free := m.filterQuery().Free
+	if !m.SearchAllFields || free == "" {
+		return ""
+	}
+	switch {
+	case containsFold(sess.Metadata.Context, free):
+		return "context"
+	case containsFold(sess.Metadata.ParentSession, free):
+		return "parent"
+	}
+	return ""
+}
+
+// matchHint notes which field matched when it isn't the (highlighted) name
+func (m PickerModel) matchHint(sess *session.Session) string {
+	if containsFold(sess.Name, m.filterQuery().Free) {
+		return ""
+	}
+	if field := m.matchedField(sess); field != "" {
+		return DimStyle.Render(" (" + field + ")")
+	}
+	return ""
 }
 
 // formatSessionLineWithTime formats a session line with "last used" time
-func (m PickerModel) formatSessionLineWithTime(sess *session.Session) string {
-	name := sess.Name
+func (m PickerModel) formatSessionLineWithTime(sess *session.Session, style lipgloss.Style) string {
+	name := highlightMatches(sess.Name, m.filterQuery().highlightFor("name"), style) + starIndicator(sess.Metadata.Starred)
 
 	// Add type indicator
 	if sess.Metadata.IsForkedSession {
@@ -420,11 +696,15 @@ func (m PickerModel) formatSessionLineWithTime(sess *session.Session) string {
 		typeStyle := lipgloss.NewStyle().Foreground(IncognitoColor)
 		name += typeStyle.Render(" [inc]")
 	}
+	name += statusIndicator(sess.Status())
+	if m.Yolo[sess.Name] {
+		name += yoloIndicator()
+	}
 
 	// Add time ago
 	timeAgo := DimStyle.Render(" · " + formatTimeAgo(sess.Metadata.LastAccessed))
 
-	return name + timeAgo
+	return name + m.matchHint(sess) + timeAgo
 }
 
 // formatTimeAgo formats a time as "X ago" (e.g., "2 hours ago", "just now")
@@ -449,18 +729,29 @@ func formatTimeAgo(t time.Time) string {
 	}
 }
 
-// RunPicker runs the session picker and returns the selected session
-func RunPicker(model PickerModel) (*session.Session, error) {
-	p := tea.NewProgram(model, tea.WithAltScreen())
-	m, err := p.Run()
+// RunPickerModel runs the session picker and returns its final state: the
+// selection (nil Selected with Cancelled set when the user backed out) and the
+// preview layout the user left it in. Falls back to a numbered list when the
+// TUI can't start.
+func RunPickerModel(model PickerModel) (PickerModel, error) {
+	defer timing.Track("tui")()
+
+	m, err := runProgram(model, tea.WithAltScreen())
 	if err != nil {
-		return nil, fmt.Errorf("failed to run picker: %w", err)
+		return model, fmt.Errorf("failed to run picker: %w", err)
 	}
-
-	finalModel := m.(PickerModel)
-	if finalModel.Cancelled {
-		return nil, nil
+	if m == nil {
+		sessions := model.filteredSessions()
+		selected, err := plainSelect(model.Title, nil, plainSessionLines(sessions))
+		if err != nil {
+			return model, err
+		}
+		if selected < 0 {
+			model.Cancelled = true
+		} else {
+			model.Selected = sessions[selected]
+		}
+		return model, nil
 	}
-
-	return finalModel.Selected, nil
+	return m.(PickerModel), nil
 }
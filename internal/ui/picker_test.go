@@ -1,10 +1,13 @@
 package ui
 
 import (
+	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 
 	"github.com/fgrehm/clotilde/internal/session"
 )
@@ -216,3 +219,161 @@ func TestPickerView_IncognitoIndicator(t *testing.T) {
 		t.Error("View should indicate incognito session")
 	}
 }
+
+func pickerKey(r rune) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}}
+}
+
+func TestPickerPreview_ToggleAndResize(t *testing.T) {
+	model := NewPicker([]*session.Session{session.NewSession("test1", "uuid-1")}, "Select").WithPreview()
+
+	updated, _ := model.Update(pickerKey('p'))
+	model = updated.(PickerModel)
+	if model.ShowPreview {
+		t.Error("Expected 'p' to hide the preview")
+	}
+
+	// Resizing brings the preview back
+	updated, _ = model.Update(pickerKey('>'))
+	model = updated.(PickerModel)
+	if !model.ShowPreview || model.PreviewPercent != DefaultPreviewPercent+previewPercentStep {
+		t.Errorf("Expected visible preview at %d%%, got %v at %d%%",
+			DefaultPreviewPercent+previewPercentStep, model.ShowPreview, model.PreviewPercent)
+	}
+
+	for range 10 {
+		updated, _ = model.Update(pickerKey('<'))
+		model = updated.(PickerModel)
+	}
+	if model.PreviewPercent != MinPreviewPercent {
+		t.Errorf("Expected preview to stop shrinking at %d%%, got %d%%", MinPreviewPercent, model.PreviewPercent)
+	}
+}
+
+func TestPickerPreview_KeysIgnoredWithoutPreview(t *testing.T) {
+	model := NewPicker([]*session.Session{session.NewSession("test1", "uuid-1")}, "Select")
+
+	updated, _ := model.Update(pickerKey('p'))
+	model = updated.(PickerModel)
+	if model.ShowPreview {
+		t.Error("Expected 'p' to do nothing on a picker without preview")
+	}
+}
+
+func TestPickerPreview_Layout(t *testing.T) {
+	model := NewPicker(nil, "Select").WithPreview().WithPreviewLayout(false, 95)
+	if model.ShowPreview || model.PreviewPercent != MaxPreviewPercent {
+		t.Errorf("Expected hidden preview clamped to %d%%, got %v at %d%%", MaxPreviewPercent, model.ShowPreview, model.PreviewPercent)
+	}
+}
+
+func TestPickerPreview_HiddenOnNarrowTerminal(t *testing.T) {
+	sess := session.NewSession("test1", "uuid-1")
+	model := NewPicker([]*session.Session{sess}, "Select").WithPreview()
+
+	updated, _ := model.Update(tea.WindowSizeMsg{Width: 50, Height: 20})
+	view := updated.(PickerModel).View()
+	if strings.Contains(view, "Created:") {
+		t.Error("Preview should be hidden on a narrow terminal")
+	}
+	if !strings.Contains(view, "too narrow") {
+		t.Error("View should explain why the preview is hidden")
+	}
+
+	updated, _ = model.Update(tea.WindowSizeMsg{Width: 100, Height: 20})
+	view = updated.(PickerModel).View()
+	if !strings.Contains(view, "Created:") {
+		t.Error("Preview should be shown on a wide terminal")
+	}
+	for line := range strings.SplitSeq(view, "\n") {
+		if w := lipgloss.Width(line); w > 100 {
+			t.Errorf("Line wider than the terminal (%d > 100): %q", w, line)
+		}
+	}
+}
+
+func TestPickerPreview_IncognitoAndExpiry(t *testing.T) {
+	ghost := session.NewIncognitoSession("ghost", "uuid-1")
+	expiring := session.NewSession("expiring", "uuid-2")
+	expiring.Metadata.ExpiresAt = time.Now().Add(3*time.Hour + 30*time.Second)
+	model := NewPicker([]*session.Session{ghost, expiring}, "Select").WithPreview()
+
+	if preview := model.renderPreviewPane(ghost); !strings.Contains(preview, "Auto-deletes on exit") {
+		t.Errorf("Incognito preview should note it auto-deletes, got %q", preview)
+	}
+	if preview := model.renderPreviewPane(expiring); !strings.Contains(preview, "(in 3h") {
+		t.Errorf("Expiring preview should count down, got %q", preview)
+	}
+}
+
+func TestPickerView_PaginatesToTerminalHeight(t *testing.T) {
+	var sessions []*session.Session
+	for i := range 30 {
+		sessions = append(sessions, session.NewSession(fmt.Sprintf("session-%02d", i), "uuid"))
+	}
+	model := NewPicker(sessions, "Select")
+	updated, _ := model.Update(tea.WindowSizeMsg{Width: 80, Height: 12})
+	view := updated.(PickerModel).View()
+
+	if lines := strings.Count(view, "\n") + 1; lines > 12 {
+		t.Errorf("Expected view to fit 12 lines, got %d", lines)
+	}
+	if !strings.Contains(view, "1-8 of 30") {
+		t.Errorf("Expected a scroll indicator, got:\n%s", view)
+	}
+}
+
+func TestPickerStar_TogglesAndSortsFirst(t *testing.T) {
+	now := time.Now()
+	var sessions []*session.Session
+	for i, name := range []string{"recent", "older", "oldest"} {
+		sess := session.NewSession(name, "uuid-"+name)
+		sess.Metadata.LastAccessed = now.Add(-time.Duration(i) * time.Hour)
+		sessions = append(sessions, sess)
+	}
+	model := NewPicker(sessions, "Select").WithStarring()
+
+	names := func(m PickerModel) string {
+		var names []string
+		for _, sess := range m.Sessions {
+			names = append(names, sess.Name)
+		}
+		return strings.Join(names, ",")
+	}
+
+	// Star "oldest": it moves to the top and the cursor follows it
+	m := model
+	m.Cursor = 2
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}})
+	m = updated.(PickerModel)
+	if got := names(m); got != "oldest,recent,older" {
+		t.Errorf("Expected starred session first, got %s", got)
+	}
+	if m.Cursor != 0 {
+		t.Errorf("Expected cursor to follow the starred session, got %d", m.Cursor)
+	}
+	if starred, ok := m.Starred["oldest"]; !ok || !starred {
+		t.Errorf("Expected the star to be recorded, got %v", m.Starred)
+	}
+	if !strings.Contains(m.View(), "★") {
+		t.Error("Expected starred sessions to be marked")
+	}
+
+	// Unstar it: back to its place by last use
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}})
+	m = updated.(PickerModel)
+	if got := names(m); got != "recent,older,oldest" {
+		t.Errorf("Expected last-used order after unstarring, got %s", got)
+	}
+	if m.Starred["oldest"] {
+		t.Errorf("Expected the unstar to be recorded, got %v", m.Starred)
+	}
+}
+
+func TestPickerStar_IgnoredWithoutStarring(t *testing.T) {
+	sessions := []*session.Session{session.NewSession("test1", "uuid-1")}
+	updated, _ := NewPicker(sessions, "Select").Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}})
+	if m := updated.(PickerModel); m.Starred != nil || sessions[0].Metadata.Starred {
+		t.Error("Expected f to do nothing without WithStarring")
+	}
+}
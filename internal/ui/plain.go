@@ -0,0 +1,157 @@
+package ui
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/fgrehm/clotilde/internal/i18n"
+	"github.com/fgrehm/clotilde/internal/session"
+)
+
+// The plain-text fallbacks read answers from plainInput and write prompts to
+// plainOutput (stderr, keeping stdout for command output). Tests replace them.
+var (
+	plainInput  io.Reader = os.Stdin
+	plainOutput io.Writer = os.Stderr
+	plainReader *bufio.Reader
+)
+
+// runProgram runs a TUI and returns its final model, or nil when it can't
+// start (TERM=dumb, or bubbletea failing to set up the terminal), in which
+// case the caller falls back to plain-text prompts. Quitting with ctrl+c or a
+// kill is returned as an error, not a reason to fall back.
+func runProgram(model tea.Model, opts ...tea.ProgramOption) (tea.Model, error) {
+	if os.Getenv("TERM") == "dumb" {
+		return nil, nil
+	}
+	m, err := tea.NewProgram(model, opts...).Run()
+	if err == nil || errors.Is(err, tea.ErrInterrupted) || errors.Is(err, tea.ErrProgramKilled) {
+		return m, err
+	}
+	_, _ = fmt.Fprintln(plainOutput, Warning(i18n.T(i18n.PlainFallback, err)))
+	return nil, nil
+}
+
+// readPlainLine reads one answer from plainInput, trimmed. The reader is
+// shared so that answers piped in for several prompts aren't lost to
+// buffering. Returns io.EOF when input ends without an answer.
+func readPlainLine() (string, error) {
+	if plainReader == nil {
+		plainReader = bufio.NewReader(plainInput)
+	}
+	line, err := plainReader.ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// plainSelect prints numbered options under title and returns the index of
+// the one picked by number, asking again on invalid answers. Returns -1 when
+// the answer is empty or "q", or input ends.
+func plainSelect(title string, details, options []string) (int, error) {
+	if title != "" {
+		_, _ = fmt.Fprintln(plainOutput, title)
+	}
+	for _, detail := range details {
+		_, _ = fmt.Fprintln(plainOutput, detail)
+	}
+	if len(options) == 0 {
+		_, _ = fmt.Fprintln(plainOutput, "  "+i18n.T(i18n.PlainNothingToSelect))
+		return -1, nil
+	}
+	for i, option := range options {
+		_, _ = fmt.Fprintf(plainOutput, "  %2d. %s\n", i+1, option)
+	}
+
+	for {
+		_, _ = fmt.Fprint(plainOutput, i18n.T(i18n.PlainSelectPrompt, len(options)))
+		answer, err := readPlainLine()
+		if errors.Is(err, io.EOF) {
+			_, _ = fmt.Fprintln(plainOutput)
+			return -1, nil
+		}
+		if err != nil {
+			return -1, fmt.Errorf("failed to read input: %w", err)
+		}
+		if answer == "" || strings.EqualFold(answer, "q") {
+			return -1, nil
+		}
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(options) {
+			return n - 1, nil
+		}
+		_, _ = fmt.Fprintln(plainOutput, i18n.T(i18n.PlainInvalidChoice, answer))
+	}
+}
+
+// plainConfirm asks question and reports whether it was answered yes, or
+// with the exact phrase when one is required.
+func plainConfirm(question, phrase string) (bool, error) {
+	if phrase != "" {
+		_, _ = fmt.Fprint(plainOutput, i18n.T(i18n.PlainTypedPrompt, question, phrase))
+	} else {
+		_, _ = fmt.Fprint(plainOutput, i18n.T(i18n.PlainConfirmPrompt, question))
+	}
+	answer, err := readPlainLine()
+	if errors.Is(err, io.EOF) {
+		_, _ = fmt.Fprintln(plainOutput)
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read input: %w", err)
+	}
+	if phrase != "" {
+		return answer == phrase, nil
+	}
+	return slices.Contains(strings.Split(i18n.T(i18n.PlainYesAnswers), ","), strings.ToLower(answer)), nil
+}
+
+// plainSessionLines labels sessions for plainSelect.
+func plainSessionLines(sessions []*session.Session) []string {
+	lines := make([]string, len(sessions))
+	for i, sess := range sessions {
+		lines[i] = fmt.Sprintf("%s%s (%s)", sess.Name, starIndicator(sess.Metadata.Starred), formatTimeAgo(sess.Metadata.LastAccessed))
+	}
+	return lines
+}
+
+// plainTableLines aligns rows into columns for plainSelect, with the header
+// line to print above them.
+func plainTableLines(headers []string, rows [][]string) (string, []string) {
+	widths := make([]int, len(headers))
+	for i, header := range headers {
+		widths[i] = lipgloss.Width(header)
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			if i < len(widths) {
+				widths[i] = max(widths[i], lipgloss.Width(cell))
+			}
+		}
+	}
+	format := func(cells []string) string {
+		padded := make([]string, len(cells))
+		for i, cell := range cells {
+			if i < len(widths) {
+				cell += strings.Repeat(" ", widths[i]-lipgloss.Width(cell))
+			}
+			padded[i] = cell
+		}
+		return strings.TrimRight(strings.Join(padded, "  "), " ")
+	}
+
+	lines := make([]string, len(rows))
+	for i, row := range rows {
+		lines[i] = format(row)
+	}
+	return "      " + format(headers), lines
+}
@@ -0,0 +1,123 @@
+package ui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/fgrehm/clotilde/internal/i18n"
+)
+
+// withPlainIO makes the Run* functions use the plain-text fallbacks, reading
+// input and returning what they printed.
+func withPlainIO(t *testing.T, input string) *bytes.Buffer {
+	t.Helper()
+	t.Setenv("TERM", "dumb")
+	var out bytes.Buffer
+	oldInput, oldOutput, oldReader := plainInput, plainOutput, plainReader
+	plainInput, plainOutput, plainReader = strings.NewReader(input), &out, nil
+	t.Cleanup(func() { plainInput, plainOutput, plainReader = oldInput, oldOutput, oldReader })
+	return &out
+}
+
+func TestPlainSwitcher(t *testing.T) {
+	out := withPlainIO(t, "x\n9\n2\n")
+	sessions := switcherSessions(3)
+
+	selected, err := RunSwitcher(NewSwitcher(sessions))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if selected != sessions[1] {
+		t.Errorf("expected session-2, got %v", selected)
+	}
+	for _, want := range []string{"1. session-1", "3. session-3", `Invalid choice "x"`, `Invalid choice "9"`} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func TestPlainPickerCancelsOnEmptyAnswerOrEOF(t *testing.T) {
+	for _, input := range []string{"\n", ""} {
+		withPlainIO(t, input)
+		model, err := RunPickerModel(NewPicker(switcherSessions(2), "Pick"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !model.Cancelled || model.Selected != nil {
+			t.Errorf("input %q: expected a cancelled picker, got %+v", input, model)
+		}
+	}
+}
+
+func TestPlainTable(t *testing.T) {
+	out := withPlainIO(t, "2\n")
+	model := NewTable([]string{"Name", "Model"}, [][]string{{"alpha", "opus"}, {"b", "haiku"}})
+
+	row, err := RunTable(model)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(row, ",") != "b,haiku" {
+		t.Errorf("expected the second row, got %v", row)
+	}
+	if !strings.Contains(out.String(), "Name   Model") || !strings.Contains(out.String(), "2. b      haiku") {
+		t.Errorf("expected aligned columns:\n%s", out)
+	}
+}
+
+func TestPlainDashboard(t *testing.T) {
+	withPlainIO(t, "1\n")
+	model := NewDashboard(nil)
+
+	action, err := RunDashboard(model)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if action != model.menuItems[0].ID {
+		t.Errorf("expected %q, got %q", model.menuItems[0].ID, action)
+	}
+}
+
+func TestPlainConfirm(t *testing.T) {
+	withPlainIO(t, "yes\n")
+	if ok, err := RunConfirm(NewConfirm("Delete?", "Gone for good")); err != nil || !ok {
+		t.Errorf("expected yes to confirm, got %v %v", ok, err)
+	}
+
+	withPlainIO(t, "y\nwork\n")
+	model := NewConfirm("Delete?", "Gone for good").WithTypedConfirmation("work")
+	if ok, err := RunConfirm(model); err != nil || ok {
+		t.Errorf("expected y not to confirm a typed confirmation, got %v %v", ok, err)
+	}
+	if ok, err := RunConfirm(model); err != nil || !ok {
+		t.Errorf("expected the phrase to confirm, got %v %v", ok, err)
+	}
+}
+
+func TestPlainChoice(t *testing.T) {
+	withPlainIO(t, "2\ny\n")
+	model := NewChoice("Conflict", []ChoiceOption{{Key: "k", Label: "Keep"}, {Key: "o", Label: "Overwrite"}}).WithApplyToAll()
+
+	model, err := RunChoice(model)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if model.Selected != 1 || model.Cancelled || !model.ApplyToAll {
+		t.Errorf("expected Overwrite applied to all, got %+v", model)
+	}
+}
+
+func TestPlainConfirmTranslated(t *testing.T) {
+	out := withPlainIO(t, "sim\n")
+	i18n.SetLanguage("pt-BR")
+	t.Cleanup(func() { i18n.SetLanguage(i18n.DefaultLanguage) })
+
+	if ok, err := RunConfirm(NewConfirm("Apagar?", "")); err != nil || !ok {
+		t.Errorf("expected sim to confirm, got %v %v", ok, err)
+	}
+	if !strings.Contains(out.String(), "Continuar? [s/N]") {
+		t.Errorf("expected a translated prompt:\n%s", out)
+	}
+}
@@ -0,0 +1,250 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ProgressReporter receives per-item updates from a long-running operation.
+type ProgressReporter interface {
+	Start(item string)
+	Done(item, detail string)
+	Fail(item string, err error)
+}
+
+// progressVisibleItems caps how many items the interactive view lists at once
+const progressVisibleItems = 10
+
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+type progressStatus int
+
+const (
+	progressRunning progressStatus = iota
+	progressDone
+	progressFailed
+)
+
+type progressItem struct {
+	Label  string
+	Detail string
+	Status progressStatus
+}
+
+// Progress messages sent from the worker goroutine to the program
+type (
+	progressStartMsg    struct{ item string }
+	progressDoneMsg     struct{ item, detail string }
+	progressFailMsg     struct{ item, detail string }
+	progressFinishedMsg struct{}
+	progressTickMsg     struct{}
+)
+
+// ProgressModel renders a spinner, per-item status, and a summary line.
+type ProgressModel struct {
+	Title    string
+	Items    []progressItem
+	Finished bool
+	frame    int
+	started  time.Time
+}
+
+// NewProgress creates a progress view with the given title.
+func NewProgress(title string) ProgressModel {
+	return ProgressModel{Title: title, started: time.Now()}
+}
+
+// Init starts the spinner
+func (m ProgressModel) Init() tea.Cmd {
+	return progressTick()
+}
+
+func progressTick() tea.Cmd {
+	return tea.Tick(100*time.Millisecond, func(time.Time) tea.Msg { return progressTickMsg{} })
+}
+
+// Update applies progress messages from the worker
+func (m ProgressModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case progressTickMsg:
+		if m.Finished {
+			return m, nil
+		}
+		m.frame = (m.frame + 1) % len(spinnerFrames)
+		return m, progressTick()
+
+	case progressStartMsg:
+		m.Items = append(m.Items, progressItem{Label: msg.item, Status: progressRunning})
+
+	case progressDoneMsg:
+		m.setStatus(msg.item, progressDone, msg.detail)
+
+	case progressFailMsg:
+		m.setStatus(msg.item, progressFailed, msg.detail)
+
+	case progressFinishedMsg:
+		m.Finished = true
+		return m, tea.Quit
+
+	case tea.KeyMsg:
+		// The terminal is in raw mode, so ctrl+c arrives as a key
+		if msg.String() == "ctrl+c" {
+			return m, tea.Interrupt
+		}
+	}
+
+	return m, nil
+}
+
+// setStatus updates the most recent item with the given label, adding it if
+// the worker reported completion without a Start.
+func (m *ProgressModel) setStatus(label string, status progressStatus, detail string) {
+	for i := len(m.Items) - 1; i >= 0; i-- {
+		if m.Items[i].Label == label && m.Items[i].Status == progressRunning {
+			m.Items[i].Status = status
+			m.Items[i].Detail = detail
+			return
+		}
+	}
+	m.Items = append(m.Items, progressItem{Label: label, Status: status, Detail: detail})
+}
+
+// View renders the title with spinner, recent items, and (when finished) the summary
+func (m ProgressModel) View() string {
+	var b strings.Builder
+
+	if m.Finished {
+		b.WriteString(BoldStyle.Render(m.Title))
+	} else {
+		b.WriteString(InfoStyle.Render(spinnerFrames[m.frame]) + " " + BoldStyle.Render(m.Title))
+	}
+	b.WriteString("\n")
+
+	items := m.Items
+	if hidden := len(items) - progressVisibleItems; hidden > 0 {
+		b.WriteString(DimStyle.Render(fmt.Sprintf("  ...%d earlier item(s)", hidden)))
+		b.WriteString("\n")
+		items = items[hidden:]
+	}
+	for _, item := range items {
+		b.WriteString("  " + m.renderItem(item) + "\n")
+	}
+
+	if m.Finished {
+		b.WriteString(DimStyle.Render(m.summary()))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func (m ProgressModel) renderItem(item progressItem) string {
+	var symbol string
+	switch item.Status {
+	case progressRunning:
+		symbol = lipgloss.NewStyle().Foreground(InfoColor).Render(spinnerFrames[m.frame])
+	case progressDone:
+		symbol = lipgloss.NewStyle().Foreground(SuccessColor).Render("✓")
+	case progressFailed:
+		symbol = lipgloss.NewStyle().Foreground(ErrorColor).Render("✗")
+	}
+
+	line := symbol + " " + item.Label
+	if item.Detail != "" {
+		line += DimStyle.Render(" · " + item.Detail)
+	}
+	return line
+}
+
+func (m ProgressModel) summary() string {
+	var done, failed int
+	for _, item := range m.Items {
+		switch item.Status {
+		case progressDone:
+			done++
+		case progressFailed:
+			failed++
+		}
+	}
+	return formatProgressSummary(done, failed, time.Since(m.started))
+}
+
+// formatProgressSummary formats the closing line, e.g. "3 done, 1 failed (1.2s)".
+func formatProgressSummary(done, failed int, elapsed time.Duration) string {
+	summary := fmt.Sprintf("%d done", done)
+	if failed > 0 {
+		summary += fmt.Sprintf(", %d failed", failed)
+	}
+	return fmt.Sprintf("%s (%s)", summary, elapsed.Round(100*time.Millisecond))
+}
+
+// programReporter forwards updates to a running bubbletea program
+type programReporter struct {
+	program *tea.Program
+}
+
+func (r programReporter) Start(item string) { r.program.Send(progressStartMsg{item: item}) }
+func (r programReporter) Done(item, detail string) {
+	r.program.Send(progressDoneMsg{item: item, detail: detail})
+}
+
+func (r programReporter) Fail(item string, err error) {
+	r.program.Send(progressFailMsg{item: item, detail: err.Error()})
+}
+
+// plainReporter prints one line per finished item (for scripts/pipes)
+type plainReporter struct {
+	out          io.Writer
+	done, failed int
+}
+
+func (r *plainReporter) Start(string) {}
+
+func (r *plainReporter) Done(item, detail string) {
+	r.done++
+	if detail != "" {
+		item += " (" + detail + ")"
+	}
+	_, _ = fmt.Fprintf(r.out, "  ✓ %s\n", item)
+}
+
+func (r *plainReporter) Fail(item string, err error) {
+	r.failed++
+	_, _ = fmt.Fprintf(r.out, "  ✗ %s: %v\n", item, err)
+}
+
+// RunWithProgress runs work while showing its progress on out. When interactive
+// is true a spinner with per-item status is rendered, otherwise one plain line
+// is printed per finished item. Both end with a summary line. Returns work's
+// error, or an "interrupted" error when ctrl+c stops the interactive view.
+func RunWithProgress(out io.Writer, title string, interactive bool, work func(ProgressReporter) error) error {
+	started := time.Now()
+
+	if !interactive {
+		_, _ = fmt.Fprintln(out, title)
+		r := &plainReporter{out: out}
+		err := work(r)
+		_, _ = fmt.Fprintln(out, formatProgressSummary(r.done, r.failed, time.Since(started)))
+		return err
+	}
+
+	p := tea.NewProgram(NewProgress(title), tea.WithOutput(out))
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- work(programReporter{program: p})
+		p.Send(progressFinishedMsg{})
+	}()
+
+	if _, err := p.Run(); err != nil {
+		if errors.Is(err, tea.ErrInterrupted) {
+			return fmt.Errorf("interrupted")
+		}
+		return fmt.Errorf("failed to run progress display: %w", err)
+	}
+	return <-errCh
+}
@@ -0,0 +1,94 @@
+package ui
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestProgressUpdate_TracksItems(t *testing.T) {
+	model := NewProgress("Working")
+
+	updated, _ := model.Update(progressStartMsg{item: "alpha"})
+	updated, _ = updated.(ProgressModel).Update(progressDoneMsg{item: "alpha", detail: "2 transcript(s)"})
+	updated, _ = updated.(ProgressModel).Update(progressStartMsg{item: "beta"})
+	updated, _ = updated.(ProgressModel).Update(progressFailMsg{item: "beta", detail: "boom"})
+	m := updated.(ProgressModel)
+
+	if len(m.Items) != 2 {
+		t.Fatalf("Expected 2 items, got %d", len(m.Items))
+	}
+	if m.Items[0].Status != progressDone || m.Items[0].Detail != "2 transcript(s)" {
+		t.Errorf("Expected alpha done with detail, got %+v", m.Items[0])
+	}
+	if m.Items[1].Status != progressFailed {
+		t.Errorf("Expected beta failed, got %+v", m.Items[1])
+	}
+}
+
+func TestProgressUpdate_FinishedQuits(t *testing.T) {
+	updated, cmd := NewProgress("Working").Update(progressFinishedMsg{})
+	m := updated.(ProgressModel)
+
+	if !m.Finished {
+		t.Error("Expected Finished after progressFinishedMsg")
+	}
+	if cmd == nil {
+		t.Error("Expected quit command when finished")
+	}
+}
+
+func TestProgressUpdate_CtrlCInterrupts(t *testing.T) {
+	_, cmd := NewProgress("Working").Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+	if cmd == nil {
+		t.Fatal("Expected a command for ctrl+c")
+	}
+	if _, ok := cmd().(tea.InterruptMsg); !ok {
+		t.Errorf("Expected ctrl+c to interrupt, got %T", cmd())
+	}
+}
+
+func TestProgressView_SummaryAndTruncation(t *testing.T) {
+	m := NewProgress("Working")
+	for i := range progressVisibleItems + 3 {
+		m.Items = append(m.Items, progressItem{Label: "item-" + string(rune('a'+i)), Status: progressDone})
+	}
+	m.Finished = true
+	view := m.View()
+
+	if !strings.Contains(view, "3 earlier item(s)") {
+		t.Error("View should collapse items beyond the visible limit")
+	}
+	if strings.Contains(view, "item-a") {
+		t.Error("View should hide the oldest items")
+	}
+	if !strings.Contains(view, "13 done") {
+		t.Errorf("View should end with a summary, got:\n%s", view)
+	}
+}
+
+func TestRunWithProgress_Plain(t *testing.T) {
+	var out bytes.Buffer
+	wantErr := errors.New("one failed")
+
+	err := RunWithProgress(&out, "Deleting", false, func(p ProgressReporter) error {
+		p.Start("alpha")
+		p.Done("alpha", "ok")
+		p.Start("beta")
+		p.Fail("beta", errors.New("boom"))
+		return wantErr
+	})
+
+	if !errors.Is(err, wantErr) {
+		t.Errorf("Expected work error to be returned, got %v", err)
+	}
+	output := out.String()
+	for _, want := range []string{"Deleting", "✓ alpha (ok)", "✗ beta: boom", "1 done, 1 failed"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+}
@@ -1,6 +1,10 @@
 package ui
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/fgrehm/clotilde/internal/session"
+)
 
 // Color palette
 var (
@@ -60,3 +64,44 @@ var (
 			BorderForeground(InfoColor).
 			Padding(1, 2)
 )
+
+// yoloIndicator tags sessions that run with bypassPermissions
+func yoloIndicator() string {
+	return ErrorStyle.Render(" [yolo]")
+}
+
+// starIndicator marks starred sessions
+func starIndicator(starred bool) string {
+	if !starred {
+		return ""
+	}
+	return lipgloss.NewStyle().Foreground(WarningColor).Render(" ★")
+}
+
+// RenderStatus colors a session lifecycle status: active in green, broken in
+// red, expired in yellow and archived dimmed.
+func RenderStatus(status string) string {
+	return statusStyle(status).Render(status)
+}
+
+// statusIndicator tags sessions whose lifecycle status isn't idle
+func statusIndicator(status string) string {
+	if status == session.StatusIdle {
+		return ""
+	}
+	return statusStyle(status).Render(" [" + status + "]")
+}
+
+func statusStyle(status string) lipgloss.Style {
+	switch status {
+	case session.StatusActive:
+		return lipgloss.NewStyle().Foreground(SuccessColor)
+	case session.StatusBroken:
+		return lipgloss.NewStyle().Foreground(ErrorColor)
+	case session.StatusExpired:
+		return lipgloss.NewStyle().Foreground(WarningColor)
+	case session.StatusArchived:
+		return DimStyle
+	}
+	return lipgloss.NewStyle()
+}
@@ -0,0 +1,144 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/fgrehm/clotilde/internal/i18n"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/timing"
+)
+
+// MaxSwitcherSessions is the most sessions the switcher offers, one per number key
+const MaxSwitcherSessions = 9
+
+// Switcher-specific bindings
+var (
+	keySwitchPrev   = KeyBinding{Keys: []string{"left", "h", "shift+tab"}, Label: "←/h", Help: "previous"}
+	keySwitchNext   = KeyBinding{Keys: []string{"right", "l", "tab"}, Label: "→/l", Help: "next"}
+	keySwitchNumber = KeyBinding{Keys: []string{"1", "2", "3", "4", "5", "6", "7", "8", "9"}, Label: "1-9", Help: "resume instantly"}
+	keySwitchCancel = KeyBinding{Keys: []string{"q", "esc", "ctrl+c"}, Label: "esc", Help: "cancel"}
+)
+
+// SwitcherModel is a compact two-line chooser for recent sessions, like
+// alt-tab: number keys pick a session immediately, arrows and enter also work.
+type SwitcherModel struct {
+	Sessions  []*session.Session // Most recent first, at most MaxSwitcherSessions
+	Cursor    int
+	Selected  *session.Session
+	Cancelled bool
+}
+
+// NewSwitcher creates a switcher for the given sessions (most recent first).
+// Only the first MaxSwitcherSessions are shown.
+func NewSwitcher(sessions []*session.Session) SwitcherModel {
+	if len(sessions) > MaxSwitcherSessions {
+		sessions = sessions[:MaxSwitcherSessions]
+	}
+	return SwitcherModel{Sessions: sessions}
+}
+
+// Init initializes the model (required by bubbletea)
+func (m SwitcherModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles keyboard input
+func (m SwitcherModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch {
+	case keySwitchCancel.Matches(keyMsg):
+		m.Cancelled = true
+		return m, tea.Quit
+
+	case keySwitchNumber.Matches(keyMsg):
+		if i := int(keyMsg.Runes[0] - '1'); i < len(m.Sessions) {
+			m.Cursor = i
+			m.Selected = m.Sessions[i]
+			return m, tea.Quit
+		}
+
+	case keySelect.Matches(keyMsg):
+		if len(m.Sessions) > 0 {
+			m.Selected = m.Sessions[m.Cursor]
+		}
+		return m, tea.Quit
+
+	case keySwitchPrev.Matches(keyMsg), keyUp.Matches(keyMsg):
+		if len(m.Sessions) > 0 {
+			m.Cursor = (m.Cursor - 1 + len(m.Sessions)) % len(m.Sessions)
+		}
+
+	case keySwitchNext.Matches(keyMsg), keyDown.Matches(keyMsg):
+		if len(m.Sessions) > 0 {
+			m.Cursor = (m.Cursor + 1) % len(m.Sessions)
+		}
+	}
+
+	return m, nil
+}
+
+// View renders the session row and a details line for the highlighted session
+func (m SwitcherModel) View() string {
+	// Clear the chooser once done so it leaves nothing behind in the terminal
+	if m.Selected != nil || m.Cancelled {
+		return ""
+	}
+	if len(m.Sessions) == 0 {
+		return DimStyle.Italic(true).Render("No sessions to switch to") + "\n"
+	}
+
+	items := make([]string, len(m.Sessions))
+	for i, sess := range m.Sessions {
+		label := fmt.Sprintf("%d %s", i+1, sess.Name)
+		if i == m.Cursor {
+			items[i] = lipgloss.NewStyle().Foreground(SuccessColor).Bold(true).Render("[" + label + "]")
+		} else {
+			items[i] = " " + label + " "
+		}
+	}
+
+	sess := m.Sessions[m.Cursor]
+	details := []string{formatTimeAgo(sess.Metadata.LastAccessed)}
+	if sess.Metadata.IsForkedSession {
+		details = append(details, "fork of "+sess.Metadata.ParentSession)
+	}
+	if sess.Metadata.Context != "" {
+		details = append(details, sess.Metadata.Context)
+	}
+	details = append(details, shortHelp(keySwitchNumber, keySelect, keySwitchCancel))
+
+	return strings.Join(items, " ") + "\n" + DimStyle.Render(strings.Join(details, " · ")) + "\n"
+}
+
+// RunSwitcher shows the switcher inline, below the prompt rather than on the
+// alternate screen, and returns the chosen session, or nil if cancelled.
+// Falls back to a numbered list when the TUI can't start.
+func RunSwitcher(model SwitcherModel) (*session.Session, error) {
+	defer timing.Track("tui")()
+
+	m, err := runProgram(model)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run switcher: %w", err)
+	}
+	if m == nil {
+		selected, err := plainSelect(i18n.T(i18n.SwitcherPrompt), nil, plainSessionLines(model.Sessions))
+		if err != nil || selected < 0 {
+			return nil, err
+		}
+		return model.Sessions[selected], nil
+	}
+
+	finalModel := m.(SwitcherModel)
+	if finalModel.Cancelled {
+		return nil, nil
+	}
+	return finalModel.Selected, nil
+}
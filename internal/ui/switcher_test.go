@@ -0,0 +1,98 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/fgrehm/clotilde/internal/session"
+)
+
+func switcherSessions(n int) []*session.Session {
+	sessions := make([]*session.Session, n)
+	for i := range n {
+		sessions[i] = session.NewSession(fmt.Sprintf("session-%d", i+1), "uuid")
+	}
+	return sessions
+}
+
+func TestNewSwitcher_LimitsSessions(t *testing.T) {
+	m := NewSwitcher(switcherSessions(12))
+	if len(m.Sessions) != MaxSwitcherSessions {
+		t.Errorf("Expected %d sessions, got %d", MaxSwitcherSessions, len(m.Sessions))
+	}
+}
+
+func TestSwitcher_NumberSelectsInstantly(t *testing.T) {
+	m := NewSwitcher(switcherSessions(3))
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'2'}})
+	m = updated.(SwitcherModel)
+	if m.Selected == nil || m.Selected.Name != "session-2" || cmd == nil {
+		t.Error("Expected '2' to select the second session and quit")
+	}
+	if m.View() != "" {
+		t.Error("Expected the chooser to clear itself once done")
+	}
+}
+
+func TestSwitcher_NumberOutOfRangeIsIgnored(t *testing.T) {
+	m := NewSwitcher(switcherSessions(3))
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'7'}})
+	m = updated.(SwitcherModel)
+	if m.Selected != nil || cmd != nil {
+		t.Error("Expected '7' to do nothing with three sessions")
+	}
+}
+
+func TestSwitcher_NavigationWrapsAndEnterSelects(t *testing.T) {
+	m := NewSwitcher(switcherSessions(3))
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	m = updated.(SwitcherModel)
+	if m.Cursor != 2 {
+		t.Errorf("Expected left from the first session to wrap to 2, got %d", m.Cursor)
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m = updated.(SwitcherModel)
+	if m.Cursor != 0 {
+		t.Errorf("Expected tab to wrap back to 0, got %d", m.Cursor)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(SwitcherModel)
+	if m.Selected == nil || m.Selected.Name != "session-1" {
+		t.Error("Expected enter to select the highlighted session")
+	}
+}
+
+func TestSwitcher_EscCancels(t *testing.T) {
+	m := NewSwitcher(switcherSessions(3))
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(SwitcherModel)
+	if !m.Cancelled || m.Selected != nil {
+		t.Error("Expected esc to cancel")
+	}
+}
+
+func TestSwitcherView(t *testing.T) {
+	sessions := switcherSessions(2)
+	sessions[1].Metadata.Context = "GH-123"
+	m := NewSwitcher(sessions)
+	m.Cursor = 1
+
+	view := m.View()
+	if lines := strings.Count(view, "\n"); lines != 2 {
+		t.Errorf("Expected a two-line view, got %d lines:\n%s", lines, view)
+	}
+	if !strings.Contains(view, "1 session-1") || !strings.Contains(view, "[2 session-2]") {
+		t.Errorf("Expected numbered sessions with the highlight on the second, got:\n%s", view)
+	}
+	if !strings.Contains(view, "GH-123") {
+		t.Error("Expected the highlighted session's context in the details line")
+	}
+}
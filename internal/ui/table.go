@@ -2,10 +2,14 @@ package ui
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/fgrehm/clotilde/internal/timing"
 )
 
 // TableModel represents a table with headers, rows, and cursor navigation
@@ -16,21 +20,37 @@ type TableModel struct {
 	Selected       int      // -1 if cancelled
 	SelectedRow    []string // actual selected row data
 	Cancelled      bool
-	SortColumn     int    // -1 for no sort, 0+ for column index
-	SortAscending  bool   // true for ascending, false for descending
-	FilterText     string // current filter text
-	Filtering      bool   // whether in filter mode
-	sortingEnabled bool   // whether sorting is enabled
+	SortColumn     int       // -1 for no sort, 0+ for column index
+	SortAscending  bool      // true for ascending, false for descending
+	FilterText     string    // current filter text
+	Filtering      bool      // whether in filter mode
+	ShowHelp       bool      // whether the help overlay is shown
+	sortingEnabled bool      // whether sorting is enabled
+	sortMenu       *sortMenu // open sort menu, nil when closed
+	width, height  int       // terminal size, 0 until known
+	filterInput    textinput.Model
+}
+
+// sortMenu is the column chooser opened with s: the highlighted column and
+// the direction to sort it in.
+type sortMenu struct {
+	cursor    int
+	ascending bool
 }
 
+// maxSortKeyColumn is the last column with a number key (1-9); the sort menu
+// reaches every column.
+const maxSortKeyColumn = 9
+
 // NewTable creates a new table model
 func NewTable(headers []string, rows [][]string) TableModel {
 	return TableModel{
-		Headers:    headers,
-		Rows:       rows,
-		Cursor:     0,
-		Selected:   -1,
-		SortColumn: -1, // No sorting by default
+		Headers:     headers,
+		Rows:        rows,
+		Cursor:      0,
+		Selected:    -1,
+		SortColumn:  -1, // No sorting by default
+		filterInput: newFilterInput(),
 	}
 }
 
@@ -40,6 +60,35 @@ func (m TableModel) WithSorting() TableModel {
 	return m
 }
 
+// TableView is the part of a table's state worth restoring when it's shown
+// again. The sort column is named by its header, so a saved view survives
+// columns being added or reordered.
+type TableView struct {
+	SortColumn    string `json:"sortColumn,omitempty"` // "" for no sort
+	SortAscending bool   `json:"sortAscending,omitempty"`
+	Filter        string `json:"filter,omitempty"`
+}
+
+// WithView restores a view saved from TableView. A sort column no longer in
+// the headers, or sorting being disabled, leaves the rows unsorted.
+func (m TableModel) WithView(view TableView) TableModel {
+	m.FilterText = view.Filter
+	if i := slices.Index(m.Headers, view.SortColumn); m.sortingEnabled && i >= 0 {
+		m.SortColumn, m.SortAscending = i, view.SortAscending
+		m.sortRows()
+	}
+	return m
+}
+
+// TableView returns the table's current sort and filter.
+func (m TableModel) TableView() TableView {
+	view := TableView{Filter: m.FilterText}
+	if m.SortColumn >= 0 && m.SortColumn < len(m.Headers) {
+		view.SortColumn, view.SortAscending = m.Headers[m.SortColumn], m.SortAscending
+	}
+	return view
+}
+
 // Init initializes the model (required by bubbletea)
 func (m TableModel) Init() tea.Cmd {
 	return nil
@@ -48,52 +97,55 @@ func (m TableModel) Init() tea.Cmd {
 // Update handles keyboard input
 func (m TableModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		return m, nil
+
 	case tea.KeyMsg:
+		// Any key closes the help overlay (ctrl+c still quits)
+		if m.ShowHelp {
+			if msg.String() == "ctrl+c" {
+				m.Cancelled = true
+				return m, tea.Quit
+			}
+			m.ShowHelp = false
+			return m, nil
+		}
+
+		if m.sortMenu != nil {
+			return m.updateSortMenu(msg)
+		}
+
 		// Handle filter mode separately
 		if m.Filtering {
-			switch msg.String() {
-			case "esc":
+			switch {
+			case keyFilterCancel.Matches(msg):
 				// Exit filter mode, clear filter
 				m.Filtering = false
 				m.FilterText = ""
 				m.Cursor = 0
-				return m, nil
 
-			case "enter":
+			case keyFilterApply.Matches(msg):
 				// Exit filter mode, keep filter
 				m.Filtering = false
-				return m, nil
-
-			case "backspace":
-				if len(m.FilterText) > 0 {
-					m.FilterText = m.FilterText[:len(m.FilterText)-1]
-					m.Cursor = 0 // Reset cursor when filter changes
-				}
-				return m, nil
 
 			default:
-				// Add character to filter
-				if len(msg.Runes) == 1 {
-					m.FilterText += string(msg.Runes[0])
+				// Typing, backspace, and cursor movement go to the text input
+				if text := updateFilterInput(&m.filterInput, m.FilterText, msg); text != m.FilterText {
+					m.FilterText = text
 					m.Cursor = 0 // Reset cursor when filter changes
 				}
-				return m, nil
 			}
+			return m, nil
 		}
 
 		// Normal mode (not filtering)
-		switch msg.String() {
-		case "ctrl+c":
+		switch {
+		case keyQuit.Matches(msg):
 			m.Cancelled = true
 			return m, tea.Quit
 
-		case "q":
-			if !m.Filtering {
-				m.Cancelled = true
-				return m, tea.Quit
-			}
-
-		case "esc":
+		case keyBack.Matches(msg):
 			if m.FilterText != "" {
 				// Clear existing filter
 				m.FilterText = ""
@@ -103,12 +155,15 @@ func (m TableModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.Cancelled = true
 			return m, tea.Quit
 
-		case "/":
+		case keyFilter.Matches(msg):
 			// Enter filter mode
 			m.Filtering = true
-			return m, nil
+			syncFilterInput(&m.filterInput, m.FilterText)
+
+		case keyHelp.Matches(msg):
+			m.ShowHelp = true
 
-		case "enter", " ":
+		case keySelect.Matches(msg):
 			filtered := m.filteredRows()
 			if len(filtered) > 0 && m.Cursor < len(filtered) {
 				m.Selected = m.Cursor
@@ -116,57 +171,118 @@ func (m TableModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, tea.Quit
 
-		case "up", "k":
+		case keyUp.Matches(msg):
 			if m.Cursor > 0 {
 				m.Cursor--
 			}
-			return m, nil
 
-		case "down", "j":
+		case keyDown.Matches(msg):
 			filtered := m.filteredRows()
 			if m.Cursor < len(filtered)-1 {
 				m.Cursor++
 			}
-			return m, nil
 
-		case "home", "g":
+		case keyTop.Matches(msg):
 			m.Cursor = 0
-			return m, nil
 
-		case "end", "G":
+		case keyBottom.Matches(msg):
 			filtered := m.filteredRows()
 			if len(filtered) > 0 {
 				m.Cursor = len(filtered) - 1
 			}
-			return m, nil
 
-		default:
-			// Handle number keys for sorting (1, 2, 3... for columns)
-			if m.sortingEnabled && len(msg.Runes) == 1 {
-				r := msg.Runes[0]
-				if r >= '1' && r <= '9' {
-					colIndex := int(r - '1')
-					if colIndex < len(m.Headers) {
-						// Toggle sort direction if same column, otherwise set ascending
-						if m.SortColumn == colIndex {
-							m.SortAscending = !m.SortAscending
-						} else {
-							m.SortColumn = colIndex
-							m.SortAscending = true
-						}
-						m.sortRows()
-						m.Cursor = 0 // Reset cursor after sort
-					}
-					return m, nil
+		case m.sortingEnabled && keySortMenu.Matches(msg) && len(m.Headers) > 0:
+			// Start from the current sort, if any
+			m.sortMenu = &sortMenu{cursor: max(m.SortColumn, 0), ascending: m.SortColumn < 0 || m.SortAscending}
+
+		case m.sortingEnabled && keySort.Matches(msg):
+			// Number keys sort by column (1, 2, 3...)
+			colIndex := int(msg.Runes[0] - '1')
+			if colIndex < len(m.Headers) {
+				// Toggle sort direction if same column, otherwise set ascending
+				if m.SortColumn == colIndex {
+					m.SortAscending = !m.SortAscending
+				} else {
+					m.SortColumn = colIndex
+					m.SortAscending = true
 				}
+				m.sortRows()
+				m.Cursor = 0 // Reset cursor after sort
 			}
 		}
 	}
 	return m, nil
 }
 
+// updateSortMenu handles keys while the sort menu is open
+func (m TableModel) updateSortMenu(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	menu := *m.sortMenu
+	switch {
+	case msg.String() == "ctrl+c":
+		m.Cancelled = true
+		return m, tea.Quit
+
+	case keySortCancel.Matches(msg):
+		m.sortMenu = nil
+		return m, nil
+
+	case keySortApply.Matches(msg):
+		m.SortColumn, m.SortAscending = menu.cursor, menu.ascending
+		m.sortRows()
+		m.Cursor = 0
+		m.sortMenu = nil
+		return m, nil
+
+	case keySortDirection.Matches(msg):
+		menu.ascending = !menu.ascending
+
+	case keyUp.Matches(msg):
+		menu.cursor = max(menu.cursor-1, 0)
+
+	case keyDown.Matches(msg):
+		menu.cursor = min(menu.cursor+1, len(m.Headers)-1)
+
+	case keyTop.Matches(msg):
+		menu.cursor = 0
+
+	case keyBottom.Matches(msg):
+		menu.cursor = len(m.Headers) - 1
+	}
+	m.sortMenu = &menu
+	return m, nil
+}
+
+// helpSections lists the table's keybindings for the help overlay
+func (m TableModel) helpSections() []HelpSection {
+	actions := []KeyBinding{keySelect, keyFilter}
+	if m.sortingEnabled {
+		actions = append(actions, keySortMenu, keySort)
+	}
+	actions = append(actions, keyBack, keyQuit, keyHelp)
+
+	sections := []HelpSection{
+		{Title: "Navigation", Bindings: []KeyBinding{keyUp, keyDown, keyTop, keyBottom}},
+		{Title: "Actions", Bindings: actions},
+		filterHelpSection,
+	}
+	if m.sortingEnabled {
+		sections = append(sections, HelpSection{
+			Title:    "In the sort menu",
+			Bindings: []KeyBinding{keySortDirection, keySortApply, keySortCancel},
+		})
+	}
+	return sections
+}
+
 // View renders the table
 func (m TableModel) View() string {
+	if m.ShowHelp {
+		return renderHelpOverlay("Table", m.helpSections(), m.width, m.height)
+	}
+	if m.sortMenu != nil {
+		return m.renderSortMenu()
+	}
+
 	var b strings.Builder
 
 	// Filter input (if active or has text)
@@ -177,10 +293,7 @@ func (m TableModel) View() string {
 		}
 		filterStyle := InfoStyle
 		b.WriteString(filterStyle.Render(filterPrefix))
-		b.WriteString(m.FilterText)
-		if m.Filtering {
-			b.WriteString("█") // Cursor
-		}
+		b.WriteString(renderFilterInput(m.filterInput, m.FilterText, m.Filtering))
 		b.WriteString("\n\n")
 	}
 
@@ -213,20 +326,20 @@ func (m TableModel) View() string {
 	b.WriteString(separator)
 	b.WriteString("\n")
 
-	// Render rows
-	for i, row := range filtered {
+	// Render the rows that fit on screen
+	start, end := visibleRange(m.Cursor, len(filtered), m.visibleRows())
+	for i := start; i < end; i++ {
+		row := filtered[i]
 		cursor := " "
 		if m.Cursor == i {
 			cursor = ">"
 		}
 
-		rowStr := m.renderRow(row, widths)
+		style := lipgloss.NewStyle()
 		if m.Cursor == i {
-			rowStr = lipgloss.NewStyle().
-				Foreground(SuccessColor).
-				Bold(true).
-				Render(rowStr)
+			style = style.Foreground(SuccessColor).Bold(true)
 		}
+		rowStr := m.renderRow(row, widths, style)
 
 		fmt.Fprintf(&b, "%s %s\n", cursor, rowStr)
 	}
@@ -234,18 +347,71 @@ func (m TableModel) View() string {
 	// Help text
 	b.WriteString("\n")
 	helpStyle := DimStyle.Italic(true)
+	var help string
 	switch {
 	case m.FilterText != "":
-		b.WriteString(helpStyle.Render("(Esc to clear filter, / to edit, ↑/↓ to navigate, enter to select)"))
+		help = shortHelp(keyFilterCancel, keyFilter, keySelect, keyHelp)
 	case m.sortingEnabled:
-		b.WriteString(helpStyle.Render("(↑/↓ or j/k to navigate, / to filter, 1-9 to sort, enter to select, q to quit)"))
+		help = shortHelp(keySelect, keyFilter, KeyBinding{Label: keySortMenu.Label, Help: "sort"}, keyHelp, keyQuit)
 	default:
-		b.WriteString(helpStyle.Render("(↑/↓ or j/k to navigate, / to filter, enter to select, q to quit)"))
+		help = shortHelp(keySelect, keyFilter, keyHelp, keyQuit)
 	}
+	if position := scrollIndicator(start, end, len(filtered)); position != "" {
+		help = position + " · " + help
+	}
+	b.WriteString(helpStyle.Render(help))
 
 	return b.String()
 }
 
+// renderSortMenu renders the sort menu: the columns, with the highlighted
+// one marked, and the direction to sort in. The box is centered when the
+// terminal size is known.
+func (m TableModel) renderSortMenu() string {
+	menu := m.sortMenu
+	var b strings.Builder
+	b.WriteString(BoldStyle.Render("Sort by"))
+	b.WriteString("\n")
+	for i, header := range m.Headers {
+		line := "  " + header
+		if i == menu.cursor {
+			line = lipgloss.NewStyle().Foreground(SuccessColor).Bold(true).Render("> " + header)
+		}
+		if i == m.SortColumn {
+			line += DimStyle.Render(" (current)")
+		}
+		b.WriteString("\n" + line)
+	}
+
+	direction := "ascending ↑"
+	if !menu.ascending {
+		direction = "descending ↓"
+	}
+	b.WriteString("\n\n" + InfoStyle.Render("Direction: ") + direction)
+	b.WriteString("\n\n")
+	b.WriteString(DimStyle.Italic(true).Render(shortHelp(
+		KeyBinding{Label: keyUp.Label + " " + keyDown.Label, Help: "column"}, keySortDirection, keySortApply, KeyBinding{Label: keySortCancel.Label, Help: "cancel"})))
+
+	box := BoxStyle.BorderForeground(InfoColor).Padding(1, 2).Render(b.String())
+	if m.width > 0 && m.height > 0 {
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box)
+	}
+	return box
+}
+
+// visibleRows returns how many data rows fit in the terminal, or 0 when the
+// size is unknown.
+func (m TableModel) visibleRows() int {
+	if m.height == 0 {
+		return 0
+	}
+	overhead := 4 // header, separator, blank line, help
+	if m.Filtering || m.FilterText != "" {
+		overhead += 2
+	}
+	return max(m.height-overhead, 1)
+}
+
 // calculateColumnWidths determines the width of each column
 func (m TableModel) calculateColumnWidths() []int {
 	if len(m.Headers) == 0 {
@@ -263,23 +429,55 @@ func (m TableModel) calculateColumnWidths() []int {
 			headerText += " ↑" // Both ↑ and ↓ are same width
 		}
 
-		// Add column number hint if sorting is enabled
-		if m.sortingEnabled {
+		// Add column number hint for the columns number keys sort
+		if m.sortingEnabled && i < maxSortKeyColumn {
 			headerText = fmt.Sprintf("%s [%d]", headerText, i+1)
 		}
 
-		widths[i] = len(headerText)
+		widths[i] = lipgloss.Width(headerText)
 	}
 
 	// Check row widths
 	for _, row := range m.Rows {
 		for i, cell := range row {
-			if i < len(widths) && len(cell) > widths[i] {
-				widths[i] = len(cell)
+			if i < len(widths) {
+				widths[i] = max(widths[i], lipgloss.Width(cell))
 			}
 		}
 	}
 
+	return m.fitColumnWidths(widths)
+}
+
+// minColumnWidth is the narrowest a column is shrunk to when fitting the terminal
+const minColumnWidth = 4
+
+// fitColumnWidths shrinks the widest columns until a row (cursor, cells, and
+// separators) fits the terminal width. Cells are truncated when rendered.
+func (m TableModel) fitColumnWidths(widths []int) []int {
+	if m.width == 0 {
+		return widths
+	}
+
+	available := m.width - 2 - 2*(len(widths)-1)
+	total := 0
+	for _, w := range widths {
+		total += w
+	}
+
+	for total > available {
+		widest := 0
+		for i, w := range widths {
+			if w > widths[widest] {
+				widest = i
+			}
+		}
+		if widths[widest] <= minColumnWidth {
+			break // Can't shrink further; let the terminal cut it
+		}
+		widths[widest]--
+		total--
+	}
 	return widths
 }
 
@@ -299,12 +497,12 @@ func (m TableModel) renderHeaderRow(widths []int) string {
 			}
 		}
 
-		// Add column number hint if sorting is enabled
-		if m.sortingEnabled {
+		// Add column number hint for the columns number keys sort
+		if m.sortingEnabled && i < maxSortKeyColumn {
 			headerText = fmt.Sprintf("%s [%d]", headerText, i+1)
 		}
 
-		cell := BoldStyle.Render(padRight(headerText, width))
+		cell := BoldStyle.Render(padRight(truncateWidth(headerText, width), width))
 		cells = append(cells, cell)
 	}
 	return strings.Join(cells, "  ")
@@ -319,40 +517,74 @@ func (m TableModel) renderSeparator(widths []int) string {
 	return DimStyle.Render(strings.Join(parts, "  "))
 }
 
-// renderRow renders a single data row
-func (m TableModel) renderRow(row []string, widths []int) string {
+// renderRow renders a single data row with style, highlighting filter matches
+func (m TableModel) renderRow(row []string, widths []int, style lipgloss.Style) string {
+	query := m.filterQuery()
 	var cells []string
 	for i, cell := range row {
 		if i < len(widths) {
 			width := widths[i]
-			cells = append(cells, padRight(cell, width))
+			text := highlightMatches(truncateWidth(cell, width), query.highlightFor(m.header(i)), style)
+			cells = append(cells, padRight(text, width))
 		}
 	}
 	return strings.Join(cells, "  ")
 }
 
-// filteredRows returns rows that match the current filter
+// filterQuery parses the filter text, with terms scoped to columns by header
+// (e.g. "model:opus", "last-used:2d")
+func (m TableModel) filterQuery() filterQuery {
+	return parseFilterQuery(m.FilterText, m.Headers)
+}
+
+// header returns the header of column i, or "" past the last one
+func (m TableModel) header(i int) string {
+	if i < len(m.Headers) {
+		return m.Headers[i]
+	}
+	return ""
+}
+
+// filteredRows returns rows that match the current filter: every scoped term
+// in its column, and the rest of the text in any cell
 func (m TableModel) filteredRows() [][]string {
 	if m.FilterText == "" {
 		return m.Rows
 	}
 
+	query := m.filterQuery()
 	var filtered [][]string
-	lowerFilter := strings.ToLower(m.FilterText)
-
 	for _, row := range m.Rows {
-		// Check if any cell in the row matches the filter
-		for _, cell := range row {
-			if strings.Contains(strings.ToLower(cell), lowerFilter) {
-				filtered = append(filtered, row)
-				break // Only add row once even if multiple cells match
-			}
+		if m.rowMatches(row, query) {
+			filtered = append(filtered, row)
 		}
 	}
 
 	return filtered
 }
 
+// rowMatches reports whether row satisfies query
+func (m TableModel) rowMatches(row []string, query filterQuery) bool {
+	for i, header := range m.Headers {
+		cell := ""
+		if i < len(row) {
+			cell = row[i]
+		}
+		if !query.matchesField(header, cell) {
+			return false
+		}
+	}
+	if query.Free == "" {
+		return true
+	}
+	for _, cell := range row {
+		if containsFold(cell, query.Free) {
+			return true
+		}
+	}
+	return false
+}
+
 // sortRows sorts the rows based on SortColumn and SortAscending
 func (m *TableModel) sortRows() {
 	if m.SortColumn < 0 || m.SortColumn >= len(m.Headers) {
@@ -387,26 +619,47 @@ func (m *TableModel) sortRows() {
 	}
 }
 
-// padRight pads a string with spaces to reach the desired width
+// padRight pads a string with spaces to reach the desired display width
 func padRight(s string, width int) string {
-	if len(s) >= width {
+	w := lipgloss.Width(s)
+	if w >= width {
 		return s
 	}
-	return s + strings.Repeat(" ", width-len(s))
+	return s + strings.Repeat(" ", width-w)
 }
 
-// RunTable runs the table and returns the selected row data (or nil if cancelled)
+// RunTable runs the table and returns the selected row data (or nil if
+// cancelled). Falls back to a numbered list of rows when the TUI can't start.
 func RunTable(model TableModel) ([]string, error) {
-	p := tea.NewProgram(model, tea.WithAltScreen())
-	m, err := p.Run()
-	if err != nil {
-		return nil, fmt.Errorf("failed to run table: %w", err)
+	finalModel, err := RunTableModel(model)
+	if err != nil || finalModel.Cancelled {
+		return nil, err
 	}
+	return finalModel.SelectedRow, nil
+}
 
-	finalModel := m.(TableModel)
-	if finalModel.Cancelled {
-		return nil, nil
-	}
+// RunTableModel runs the table and returns its final state, for callers that
+// need more than the selected row (e.g. to save its view).
+func RunTableModel(model TableModel) (TableModel, error) {
+	defer timing.Track("tui")()
 
-	return finalModel.SelectedRow, nil
+	m, err := runProgram(model, tea.WithAltScreen())
+	if err != nil {
+		return model, fmt.Errorf("failed to run table: %w", err)
+	}
+	if m == nil {
+		rows := model.filteredRows()
+		header, lines := plainTableLines(model.Headers, rows)
+		selected, err := plainSelect(header, nil, lines)
+		if err != nil {
+			return model, err
+		}
+		if selected < 0 {
+			model.Cancelled = true
+		} else {
+			model.Selected, model.SelectedRow = selected, rows[selected]
+		}
+		return model, nil
+	}
+	return m.(TableModel), nil
 }
@@ -1,10 +1,12 @@
 package ui
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
 func TestNewTable(t *testing.T) {
@@ -298,6 +300,83 @@ func TestTableSorting_Descending(t *testing.T) {
 	}
 }
 
+func TestTableSortMenu_ChoosesColumnAndDirection(t *testing.T) {
+	rows := [][]string{
+		{"alpha", "20"},
+		{"beta", "10"},
+		{"gamma", "30"},
+	}
+	model := NewTable([]string{"Name", "Value"}, rows).WithSorting()
+	press := func(m TableModel, msg tea.KeyMsg) TableModel {
+		updated, _ := m.Update(msg)
+		return updated.(TableModel)
+	}
+
+	m := press(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	if m.sortMenu == nil {
+		t.Fatal("Expected 's' to open the sort menu")
+	}
+	if view := m.View(); !strings.Contains(view, "Sort by") || !strings.Contains(view, "ascending") {
+		t.Errorf("Expected the sort menu in the view, got:\n%s", view)
+	}
+
+	m = press(m, tea.KeyMsg{Type: tea.KeyDown})
+	m = press(m, tea.KeyMsg{Type: tea.KeyTab})
+	m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+
+	if m.sortMenu != nil {
+		t.Error("Expected enter to close the sort menu")
+	}
+	if m.SortColumn != 1 || m.SortAscending {
+		t.Errorf("Expected a descending sort on column 1, got column %d ascending=%v", m.SortColumn, m.SortAscending)
+	}
+	if m.Rows[0][0] != "gamma" || m.Rows[2][0] != "beta" {
+		t.Errorf("Expected rows sorted by value, descending, got %v", m.Rows)
+	}
+	if m.Selected != -1 {
+		t.Error("Expected enter in the sort menu not to select a row")
+	}
+}
+
+func TestTableSortMenu_CancelKeepsSort(t *testing.T) {
+	rows := [][]string{{"beta", "1"}, {"alpha", "2"}}
+	model := NewTable([]string{"Name", "Value"}, rows).WithSorting()
+
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	updated, _ = updated.(TableModel).Update(tea.KeyMsg{Type: tea.KeyDown})
+	updated, cmd := updated.(TableModel).Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m := updated.(TableModel)
+
+	if m.sortMenu != nil || m.Cancelled || cmd != nil {
+		t.Error("Expected esc to close only the sort menu")
+	}
+	if m.SortColumn != -1 || m.Rows[0][0] != "beta" {
+		t.Error("Expected the rows to stay unsorted")
+	}
+}
+
+func TestTableSortMenu_ReachesColumnsPastNine(t *testing.T) {
+	headers := make([]string, 11)
+	row := make([]string, 11)
+	for i := range headers {
+		headers[i] = fmt.Sprintf("C%d", i+1)
+		row[i] = "x"
+	}
+	model := NewTable(headers, [][]string{row}).WithSorting()
+
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	updated, _ = updated.(TableModel).Update(tea.KeyMsg{Type: tea.KeyEnd})
+	updated, _ = updated.(TableModel).Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m := updated.(TableModel)
+
+	if m.SortColumn != 10 {
+		t.Errorf("Expected the last column to be sortable from the menu, got %d", m.SortColumn)
+	}
+	if view := m.View(); strings.Contains(view, "[10]") || !strings.Contains(view, "[9]") {
+		t.Error("Expected number hints only for the columns number keys sort")
+	}
+}
+
 func TestTableSorting_DifferentColumn(t *testing.T) {
 	rows := [][]string{
 		{"alpha", "30"},
@@ -542,3 +621,78 @@ func TestTableView_WithFilter(t *testing.T) {
 		t.Error("View should contain filter text 'alpha'")
 	}
 }
+
+func TestTableView_FitsTerminalWidth(t *testing.T) {
+	m := NewTable(
+		[]string{"Name", "Description"},
+		[][]string{{"auth-feature", strings.Repeat("very long description ", 10)}},
+	)
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 60, Height: 20})
+	view := updated.(TableModel).View()
+
+	for line := range strings.SplitSeq(view, "\n") {
+		if w := lipgloss.Width(line); w > 60 {
+			t.Errorf("Line wider than the terminal (%d > 60): %q", w, line)
+		}
+	}
+	if !strings.Contains(view, "auth-feature") {
+		t.Error("Narrow columns should keep their full content")
+	}
+	if !strings.Contains(view, "…") {
+		t.Error("Truncated cells should end with an ellipsis")
+	}
+}
+
+func TestTableView_PaginatesToTerminalHeight(t *testing.T) {
+	var rows [][]string
+	for i := range 30 {
+		rows = append(rows, []string{fmt.Sprintf("row-%02d", i)})
+	}
+	m := NewTable([]string{"Name"}, rows)
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 80, Height: 10})
+	m = updated.(TableModel)
+
+	view := m.View()
+	if lines := strings.Count(view, "\n") + 1; lines > 10 {
+		t.Errorf("Expected view to fit 10 lines, got %d", lines)
+	}
+	if !strings.Contains(view, "1-6 of 30") {
+		t.Errorf("Expected a scroll indicator, got:\n%s", view)
+	}
+
+	m.Cursor = 29
+	view = m.View()
+	if !strings.Contains(view, "row-29") || strings.Contains(view, "row-00") {
+		t.Error("Expected the visible rows to follow the cursor")
+	}
+}
+
+func TestTableView_RoundTrip(t *testing.T) {
+	rows := [][]string{{"b", "2"}, {"a", "1"}, {"c", "3"}}
+	m := NewTable([]string{"Name", "Last Used"}, rows).WithSorting()
+	if view := m.TableView(); view != (TableView{}) {
+		t.Errorf("Expected an empty view for a fresh table, got %+v", view)
+	}
+
+	m = m.WithView(TableView{SortColumn: "Last Used", Filter: "a"})
+	if m.SortColumn != 1 || m.SortAscending || m.FilterText != "a" {
+		t.Errorf("Expected the view to be restored, got column %d ascending %v filter %q", m.SortColumn, m.SortAscending, m.FilterText)
+	}
+	if m.Rows[0][0] != "c" {
+		t.Errorf("Expected rows sorted descending by Last Used, got %v", m.Rows)
+	}
+	if view := m.TableView(); view != (TableView{SortColumn: "Last Used", Filter: "a"}) {
+		t.Errorf("Expected the restored view back, got %+v", view)
+	}
+}
+
+func TestTableView_UnknownColumn(t *testing.T) {
+	m := NewTable([]string{"Name"}, [][]string{{"b"}, {"a"}}).WithSorting()
+	m = m.WithView(TableView{SortColumn: "Gone", SortAscending: true, Filter: "x"})
+	if m.SortColumn != -1 || m.Rows[0][0] != "b" {
+		t.Error("Expected a missing sort column to leave the rows unsorted")
+	}
+	if m.FilterText != "x" {
+		t.Error("Expected the filter to be restored anyway")
+	}
+}
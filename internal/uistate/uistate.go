@@ -0,0 +1,70 @@
+// Package uistate remembers how interactive views were left (e.g. a table's
+// sort and filter) so they open the same way next time. Unlike the config,
+// it's written by clotilde, not by users.
+package uistate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/ui"
+	"github.com/fgrehm/clotilde/internal/util"
+)
+
+// stateFile is the UI state file name within config.GlobalDataDir().
+const stateFile = "ui-state.json"
+
+// Keys of the tables whose view is remembered.
+const (
+	SessionsTable = "sessions" // The session list opened from the dashboard
+)
+
+// State is the remembered state of every view.
+type State struct {
+	Tables map[string]ui.TableView `json:"tables,omitempty"` // By table key
+}
+
+// Path returns the path to the UI state file.
+func Path() string {
+	return filepath.Join(config.GlobalDataDir(), stateFile)
+}
+
+// Load reads the UI state. A missing file yields an empty state.
+func Load() (*State, error) {
+	state := &State{}
+	if err := util.ReadJSON(Path(), state); err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return nil, fmt.Errorf("failed to read UI state: %w", err)
+	}
+	return state, nil
+}
+
+// Save writes the UI state.
+func (s *State) Save() error {
+	if err := util.WriteJSON(Path(), s); err != nil {
+		return fmt.Errorf("failed to save UI state: %w", err)
+	}
+	return nil
+}
+
+// TableView returns the view saved for the table key, or the zero view.
+func (s *State) TableView(key string) ui.TableView {
+	return s.Tables[key]
+}
+
+// SetTableView records the view of the table key, forgetting it when it's
+// the zero view.
+func (s *State) SetTableView(key string, view ui.TableView) {
+	if view == (ui.TableView{}) {
+		delete(s.Tables, key)
+		return
+	}
+	if s.Tables == nil {
+		s.Tables = map[string]ui.TableView{}
+	}
+	s.Tables[key] = view
+}
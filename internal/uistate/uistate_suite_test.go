@@ -0,0 +1,13 @@
+package uistate_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestUIState(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "UI State Suite")
+}
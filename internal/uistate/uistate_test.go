@@ -0,0 +1,57 @@
+package uistate_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/ui"
+	"github.com/fgrehm/clotilde/internal/uistate"
+)
+
+var _ = Describe("UI state", func() {
+	var dataHome string
+
+	BeforeEach(func() {
+		dataHome = GinkgoT().TempDir()
+		GinkgoT().Setenv("XDG_DATA_HOME", dataHome)
+	})
+
+	It("stores the state under XDG_DATA_HOME", func() {
+		Expect(uistate.Path()).To(Equal(filepath.Join(dataHome, "clotilde", "ui-state.json")))
+	})
+
+	It("returns an empty state when the file does not exist", func() {
+		state, err := uistate.Load()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(state.TableView(uistate.SessionsTable)).To(Equal(ui.TableView{}))
+	})
+
+	It("saves and restores table views", func() {
+		view := ui.TableView{SortColumn: "Last Used", Filter: "model:opus"}
+		state, err := uistate.Load()
+		Expect(err).NotTo(HaveOccurred())
+		state.SetTableView(uistate.SessionsTable, view)
+		Expect(state.Save()).To(Succeed())
+
+		state, err = uistate.Load()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(state.TableView(uistate.SessionsTable)).To(Equal(view))
+	})
+
+	It("forgets reset views", func() {
+		state := &uistate.State{}
+		state.SetTableView(uistate.SessionsTable, ui.TableView{Filter: "auth"})
+		state.SetTableView(uistate.SessionsTable, ui.TableView{})
+		Expect(state.Tables).To(BeEmpty())
+	})
+
+	It("reports unreadable state files", func() {
+		Expect(os.MkdirAll(filepath.Dir(uistate.Path()), 0o755)).To(Succeed())
+		Expect(os.WriteFile(uistate.Path(), []byte("{"), 0o644)).To(Succeed())
+		_, err := uistate.Load()
+		Expect(err).To(MatchError(ContainSubstring("failed to read UI state")))
+	})
+})
@@ -2,6 +2,8 @@ package util
 
 import (
 	"fmt"
+	"regexp"
+	"strconv"
 	"time"
 )
 
@@ -64,3 +66,61 @@ func FormatRelativeTime(t time.Time) string {
 		return t.Format("2006-01-02")
 	}
 }
+
+// FormatDuration formats a duration in hours and minutes for display.
+// Examples: 30s -> "<1m", 45m -> "45m", 3h12m -> "3h 12m", 2h -> "2h"
+func FormatDuration(d time.Duration) string {
+	if d < time.Minute {
+		return "<1m"
+	}
+	hours := int(d.Hours())
+	mins := int(d.Minutes()) % 60
+	switch {
+	case hours == 0:
+		return fmt.Sprintf("%dm", mins)
+	case mins == 0:
+		return fmt.Sprintf("%dh", hours)
+	default:
+		return fmt.Sprintf("%dh %dm", hours, mins)
+	}
+}
+
+// dayUnitRe matches a leading day or week component (e.g. "2w", "7d").
+var dayUnitRe = regexp.MustCompile(`^(\d+)([wd])`)
+
+// ParseDuration parses a positive duration such as "7d", "2w", "1d12h", or "90m".
+// It accepts time.ParseDuration units plus d (days) and w (weeks), which must
+// come first.
+func ParseDuration(s string) (time.Duration, error) {
+	var total time.Duration
+	rest := s
+	for {
+		m := dayUnitRe.FindStringSubmatch(rest)
+		if m == nil {
+			break
+		}
+		n, err := strconv.Atoi(m[1])
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		unit := 24 * time.Hour
+		if m[2] == "w" {
+			unit *= 7
+		}
+		total += time.Duration(n) * unit
+		rest = rest[len(m[0]):]
+	}
+
+	if rest != "" {
+		d, err := time.ParseDuration(rest)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q (examples: 12h, 7d, 2w)", s)
+		}
+		total += d
+	}
+
+	if total <= 0 {
+		return 0, fmt.Errorf("invalid duration %q: must be positive", s)
+	}
+	return total, nil
+}
@@ -97,3 +97,32 @@ var _ = Describe("FormatRelativeTime", func() {
 		Expect(result).To(MatchRegexp(`^\d{4}-\d{2}-\d{2}$`))
 	})
 })
+
+var _ = Describe("FormatDuration", func() {
+	It("should format hours and minutes", func() {
+		Expect(util.FormatDuration(30 * time.Second)).To(Equal("<1m"))
+		Expect(util.FormatDuration(45 * time.Minute)).To(Equal("45m"))
+		Expect(util.FormatDuration(2 * time.Hour)).To(Equal("2h"))
+		Expect(util.FormatDuration(3*time.Hour + 12*time.Minute)).To(Equal("3h 12m"))
+	})
+})
+
+var _ = Describe("ParseDuration", func() {
+	It("should parse days and weeks", func() {
+		Expect(util.ParseDuration("7d")).To(Equal(7 * 24 * time.Hour))
+		Expect(util.ParseDuration("2w")).To(Equal(14 * 24 * time.Hour))
+		Expect(util.ParseDuration("1d12h")).To(Equal(36 * time.Hour))
+	})
+
+	It("should parse standard Go durations", func() {
+		Expect(util.ParseDuration("90m")).To(Equal(90 * time.Minute))
+		Expect(util.ParseDuration("12h")).To(Equal(12 * time.Hour))
+	})
+
+	It("should reject invalid or non-positive durations", func() {
+		for _, input := range []string{"", "abc", "7x", "0d", "-1h", "d7"} {
+			_, err := util.ParseDuration(input)
+			Expect(err).To(HaveOccurred(), "input %q", input)
+		}
+	})
+})
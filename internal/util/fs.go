@@ -3,6 +3,7 @@ package util
 import (
 	"encoding/json"
 	"errors"
+	"io/fs"
 	"os"
 	"path/filepath"
 )
@@ -114,3 +115,27 @@ func ReadFile(path string) ([]byte, error) {
 func RemoveAll(path string) error {
 	return os.RemoveAll(path)
 }
+
+// DirSize returns the total size in bytes of all regular files under path.
+// Returns 0 and no error if path does not exist.
+func DirSize(path string) (int64, error) {
+	var total int64
+	err := filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		total += info.Size()
+		return nil
+	})
+	return total, err
+}
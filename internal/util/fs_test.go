@@ -257,3 +257,28 @@ var _ = Describe("RemoveAll", func() {
 		Expect(util.FileExists(testFile)).To(BeFalse())
 	})
 })
+
+var _ = Describe("DirSize", func() {
+	var tempDir string
+
+	BeforeEach(func() {
+		tempDir = GinkgoT().TempDir()
+	})
+
+	It("should sum the sizes of nested files", func() {
+		err := os.MkdirAll(filepath.Join(tempDir, "a", "b"), 0o755)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.WriteFile(filepath.Join(tempDir, "a", "one.txt"), []byte("12345"), 0o644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(tempDir, "a", "b", "two.txt"), []byte("123"), 0o644)).To(Succeed())
+
+		size, err := util.DirSize(tempDir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(size).To(Equal(int64(8)))
+	})
+
+	It("should return zero for a missing directory", func() {
+		size, err := util.DirSize(filepath.Join(tempDir, "missing"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(size).To(BeZero())
+	})
+})
@@ -0,0 +1,104 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	lockTimeout       = 5 * time.Second
+	lockRetryInterval = 10 * time.Millisecond
+	staleLockAge      = 30 * time.Second // Older locks were left by a crashed process
+)
+
+// WithFileLock runs fn while holding an exclusive lock on path, taken by
+// creating path+".lock". Works on every platform (no flock), which is enough
+// for the short read-modify-write cycles it guards.
+// Returns an error if the lock can't be taken within a few seconds.
+func WithFileLock(path string, fn func() error) error {
+	lockPath := path + ".lock"
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		locked, err := tryLock(lockPath)
+		if err != nil {
+			return err
+		}
+		if locked {
+			break
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for lock %s", lockPath)
+		}
+		time.Sleep(lockRetryInterval)
+	}
+	defer func() { _ = os.Remove(lockPath) }()
+
+	return fn()
+}
+
+// TryFileLock runs fn holding the same lock as WithFileLock, or returns false
+// without running it when another process holds the lock, for work that can
+// just as well be left to that process.
+func TryFileLock(path string, fn func() error) (bool, error) {
+	lockPath := path + ".lock"
+	locked, err := tryLock(lockPath)
+	if err != nil || !locked {
+		return false, err
+	}
+	defer func() { _ = os.Remove(lockPath) }()
+
+	return true, fn()
+}
+
+// tryLock creates lockPath, removing it first when it's stale. Returns false
+// when it's held.
+func tryLock(lockPath string) (bool, error) {
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, DefaultFileMode)
+		if err == nil {
+			_ = f.Close()
+			return true, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return false, fmt.Errorf("failed to create lock file: %w", err)
+		}
+		info, err := os.Stat(lockPath)
+		if err != nil || time.Since(info.ModTime()) <= staleLockAge {
+			return false, nil
+		}
+		_ = os.Remove(lockPath)
+	}
+}
+
+// WriteFileAtomic replaces a file's content through a temporary file and a
+// rename, so readers never see a partially written file. Each write gets its
+// own temporary file, so concurrent writers can't mix their contents.
+func WriteFileAtomic(path string, content []byte) error {
+	if err := ensureDirForFile(path); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	_, err = f.Write(content)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp, DefaultFileMode)
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package util_test
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/util"
+)
+
+var _ = Describe("WithFileLock", func() {
+	var path string
+
+	BeforeEach(func() {
+		path = filepath.Join(GinkgoT().TempDir(), "counter")
+		Expect(os.WriteFile(path, []byte("0"), 0o644)).To(Succeed())
+	})
+
+	increment := func() error {
+		return util.WithFileLock(path, func() error {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			n, _ := strconv.Atoi(string(data))
+			return util.WriteFileAtomic(path, []byte(strconv.Itoa(n+1)))
+		})
+	}
+
+	It("serializes concurrent read-modify-write cycles", func() {
+		var wg sync.WaitGroup
+		for range 20 {
+			wg.Go(func() {
+				defer GinkgoRecover()
+				Expect(increment()).To(Succeed())
+			})
+		}
+		wg.Wait()
+
+		data, err := os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(Equal("20"))
+		Expect(path + ".lock").NotTo(BeAnExistingFile())
+	})
+
+	It("breaks a stale lock left by a crashed process", func() {
+		lockPath := path + ".lock"
+		Expect(os.WriteFile(lockPath, nil, 0o644)).To(Succeed())
+		old := time.Now().Add(-time.Hour)
+		Expect(os.Chtimes(lockPath, old, old)).To(Succeed())
+
+		Expect(increment()).To(Succeed())
+	})
+})
+
+var _ = Describe("TryFileLock", func() {
+	It("skips the work while another process holds the lock", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "work")
+		ran := false
+		Expect(os.WriteFile(path+".lock", nil, 0o644)).To(Succeed())
+		locked, err := util.TryFileLock(path, func() error { ran = true; return nil })
+		Expect(err).NotTo(HaveOccurred())
+		Expect(locked).To(BeFalse())
+		Expect(ran).To(BeFalse())
+
+		Expect(os.Remove(path + ".lock")).To(Succeed())
+		locked, err = util.TryFileLock(path, func() error { ran = true; return nil })
+		Expect(err).NotTo(HaveOccurred())
+		Expect(locked).To(BeTrue())
+		Expect(ran).To(BeTrue())
+		Expect(path + ".lock").NotTo(BeAnExistingFile())
+	})
+})
+
+var _ = Describe("WriteFileAtomic", func() {
+	It("leaves one writer's complete content when writes race", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "file")
+		contents := []string{strings.Repeat("a", 64*1024), strings.Repeat("b", 1024)}
+
+		var wg sync.WaitGroup
+		for i := range 20 {
+			wg.Go(func() {
+				defer GinkgoRecover()
+				Expect(util.WriteFileAtomic(path, []byte(contents[i%2]))).To(Succeed())
+			})
+		}
+		wg.Wait()
+
+		data, err := os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(contents).To(ContainElement(string(data)))
+		leftovers, err := filepath.Glob(path + ".*.tmp")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(leftovers).To(BeEmpty())
+	})
+})
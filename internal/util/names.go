@@ -5,6 +5,7 @@ import (
 	"math/rand/v2"
 	"os/exec"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 )
@@ -117,3 +118,40 @@ func GenerateUniqueRandomName(existingNames []string) string {
 
 	return fmt.Sprintf("%s-%d", GenerateRandomName(), rand.IntN(1000))
 }
+
+// withSuffix appends suffix to base, trimming base so the result fits maxLen.
+func withSuffix(base, suffix string, maxLen int) string {
+	if len(base)+len(suffix) > maxLen {
+		base = strings.TrimRight(base[:max(maxLen-len(suffix), 0)], "-")
+	}
+	return base + suffix
+}
+
+// NumberedName returns the first "<base>-N" (N >= 2) not in existingNames,
+// trimming base so the result fits maxLen.
+func NumberedName(base string, existingNames []string, maxLen int) string {
+	for i := 2; ; i++ {
+		candidate := withSuffix(base, fmt.Sprintf("-%d", i), maxLen)
+		if !slices.Contains(existingNames, candidate) {
+			return candidate
+		}
+	}
+}
+
+// DatedName returns "<base>-<mon><day>" for the given date (e.g. "auth-jan15"),
+// or a numbered variant of it if that is taken too.
+func DatedName(base string, existingNames []string, maxLen int, date time.Time) string {
+	dated := withSuffix(base, "-"+strings.ToLower(date.Format("Jan2")), maxLen)
+	if !slices.Contains(existingNames, dated) {
+		return dated
+	}
+	return NumberedName(dated, existingNames, maxLen)
+}
+
+// SuggestNames returns available near-names for a taken name: the next two
+// numbered variants and a dated variant (e.g. "auth-2", "auth-3", "auth-jan15").
+func SuggestNames(base string, existingNames []string, maxLen int, date time.Time) []string {
+	first := NumberedName(base, existingNames, maxLen)
+	second := NumberedName(base, append(slices.Clone(existingNames), first), maxLen)
+	return []string{first, second, DatedName(base, existingNames, maxLen, date)}
+}
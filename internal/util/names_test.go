@@ -219,3 +219,36 @@ func TestSanitizeBranchName_Truncation(t *testing.T) {
 		t.Errorf("Expected truncation to 62 chars, got %d: %q", len(result), result)
 	}
 }
+
+func TestNumberedName(t *testing.T) {
+	if got := NumberedName("auth", []string{"auth"}, 64); got != "auth-2" {
+		t.Errorf("NumberedName = %q, want auth-2", got)
+	}
+	if got := NumberedName("auth", []string{"auth", "auth-2", "auth-3"}, 64); got != "auth-4" {
+		t.Errorf("NumberedName = %q, want auth-4", got)
+	}
+	if got := NumberedName("abcdefgh", nil, 8); got != "abcdef-2" {
+		t.Errorf("NumberedName should trim to max length, got %q", got)
+	}
+}
+
+func TestDatedName(t *testing.T) {
+	date := time.Date(2026, time.January, 15, 10, 0, 0, 0, time.UTC)
+
+	if got := DatedName("auth", nil, 64, date); got != "auth-jan15" {
+		t.Errorf("DatedName = %q, want auth-jan15", got)
+	}
+	if got := DatedName("auth", []string{"auth-jan15"}, 64, date); got != "auth-jan15-2" {
+		t.Errorf("DatedName = %q, want auth-jan15-2", got)
+	}
+}
+
+func TestSuggestNames(t *testing.T) {
+	date := time.Date(2026, time.January, 15, 10, 0, 0, 0, time.UTC)
+
+	got := SuggestNames("auth", []string{"auth", "auth-2"}, 64, date)
+	want := []string{"auth-3", "auth-4", "auth-jan15"}
+	if !slices.Equal(got, want) {
+		t.Errorf("SuggestNames = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,28 @@
+package util_test
+
+import (
+	"os"
+	"os/exec"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/util"
+)
+
+var _ = Describe("ProcessRunning", func() {
+	It("is true for a running process", func() {
+		Expect(util.ProcessRunning(os.Getpid())).To(BeTrue())
+	})
+
+	It("is false once the process exited", func() {
+		cmd := exec.Command(os.Args[0], "-test.run=^$")
+		Expect(cmd.Run()).To(Succeed())
+		Expect(util.ProcessRunning(cmd.Process.Pid)).To(BeFalse())
+	})
+
+	It("is false for invalid PIDs", func() {
+		Expect(util.ProcessRunning(0)).To(BeFalse())
+		Expect(util.ProcessRunning(-1)).To(BeFalse())
+	})
+})
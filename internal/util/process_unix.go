@@ -0,0 +1,23 @@
+//go:build !windows
+
+package util
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// ProcessRunning reports whether a process with pid exists on this machine.
+func ProcessRunning(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	// Signal 0 only checks the process exists; EPERM means it's someone else's
+	err = p.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
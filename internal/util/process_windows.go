@@ -0,0 +1,19 @@
+//go:build windows
+
+package util
+
+import "os"
+
+// ProcessRunning reports whether a process with pid exists on this machine.
+func ProcessRunning(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	// Opening a handle fails once the process is gone
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	_ = p.Release()
+	return true
+}
@@ -0,0 +1,56 @@
+package util_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/util"
+)
+
+var _ = Describe("ShellQuote", func() {
+	It("leaves safe strings alone", func() {
+		Expect(util.ShellQuote("src/my-project_1.2~")).To(Equal("src/my-project_1.2~"))
+	})
+
+	It("quotes spaces and metacharacters", func() {
+		Expect(util.ShellQuote("my project")).To(Equal("'my project'"))
+		Expect(util.ShellQuote("a;b")).To(Equal("'a;b'"))
+		Expect(util.ShellQuote("")).To(Equal("''"))
+	})
+
+	It("quotes a leading tilde so it isn't expanded", func() {
+		Expect(util.ShellQuote("~/src/app")).To(Equal("'~/src/app'"))
+		Expect(util.ShellQuote("~")).To(Equal("'~'"))
+	})
+
+	It("escapes single quotes", func() {
+		Expect(util.ShellQuote("it's")).To(Equal(`'it'\''s'`))
+	})
+})
+
+var _ = Describe("ShellJoin", func() {
+	It("quotes the arguments that need it", func() {
+		Expect(util.ShellJoin([]string{"claude", "--resume", "uuid-1", "fix the bug's cause", ""})).
+			To(Equal(`claude --resume uuid-1 'fix the bug'\''s cause' ''`))
+	})
+
+	It("round-trips through ShellSplit", func() {
+		args := []string{"claude", "--allowed-tools", "Bash(npm:*),Read", `say "hi"`, "a\\b"}
+		Expect(util.ShellSplit(util.ShellJoin(args))).To(Equal(args))
+	})
+})
+
+var _ = Describe("ShellSplit", func() {
+	It("splits on whitespace", func() {
+		Expect(util.ShellSplit("  wrapper {claude}\t{args} ")).To(Equal([]string{"wrapper", "{claude}", "{args}"}))
+	})
+
+	It("honors quotes and escapes", func() {
+		Expect(util.ShellSplit(`run "my tool" 'it''s' a\ b ""`)).To(Equal([]string{"run", "my tool", "its", "a b", ""}))
+	})
+
+	It("rejects unterminated quotes", func() {
+		_, err := util.ShellSplit(`run "oops`)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,301 @@
+// Package web serves a minimal HTML interface to a project's sessions for
+// `clotilde serve`: list and inspect them, read transcripts, archive, delete,
+// and copy the command that resumes one.
+package web
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fgrehm/clotilde/internal/app"
+	"github.com/fgrehm/clotilde/internal/claude"
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/export"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/util"
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+// Server handles the dashboard's requests for one project.
+type Server struct {
+	root     string
+	store    session.Store
+	homeDir  string
+	token    string // required by every form that changes something
+	pages    *template.Template
+	resumeCd string // project root, for the copyable resume command
+}
+
+// New creates a server for the sessions under clotildeRoot.
+func New(clotildeRoot string) (*Server, error) {
+	homeDir, err := util.HomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("could not determine home directory: %w", err)
+	}
+
+	if _, err := session.LoadNameRules(clotildeRoot); err != nil {
+		return nil, fmt.Errorf("invalid naming config: %w", err)
+	}
+
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return nil, fmt.Errorf("failed to generate form token: %w", err)
+	}
+
+	pages, err := template.New("").Funcs(template.FuncMap{
+		"relative": util.FormatRelativeTime,
+		"size":     util.FormatSize,
+		"time":     func(t time.Time) string { return t.Format("2006-01-02 15:04") },
+	}).ParseFS(templateFS, "templates/*.html")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse templates: %w", err)
+	}
+
+	return &Server{
+		root:     clotildeRoot,
+		store:    session.NewFileStore(clotildeRoot),
+		homeDir:  homeDir,
+		token:    hex.EncodeToString(token),
+		pages:    pages,
+		resumeCd: config.ProjectRootOf(clotildeRoot),
+	}, nil
+}
+
+// Handler returns the dashboard's routes for a server listening on host.
+// Requests naming another host are refused, and so are forms posted from
+// other sites.
+func (s *Server) Handler(host string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.handleList)
+	mux.HandleFunc("GET /sessions/{name}", s.handleSession)
+	mux.HandleFunc("GET /sessions/{name}/transcript", s.handleTranscript)
+	mux.HandleFunc("POST /sessions/{name}/delete", s.handleDelete)
+	mux.HandleFunc("POST /sessions/{name}/archive", s.handleArchive(true))
+	mux.HandleFunc("POST /sessions/{name}/unarchive", s.handleArchive(false))
+
+	protected := http.NewCrossOriginProtection().Handler(mux)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !allowedHost(r.Host, host) {
+			http.Error(w, "unexpected Host header; open the dashboard through localhost or the address it listens on", http.StatusMisdirectedRequest)
+			return
+		}
+		protected.ServeHTTP(w, r)
+	})
+}
+
+// allowedHost reports whether a request's Host header names the server:
+// localhost, an IP address, or the host it listens on. Any other name may be
+// a site that pointed its domain at this address (DNS rebinding) to read or
+// delete sessions from the user's browser.
+func allowedHost(requestHost, listenHost string) bool {
+	name := requestHost
+	if host, _, err := net.SplitHostPort(requestHost); err == nil {
+		name = host
+	}
+	name = strings.TrimSuffix(strings.Trim(name, "[]"), ".")
+	return strings.EqualFold(name, "localhost") || net.ParseIP(name) != nil || strings.EqualFold(name, listenHost)
+}
+
+// sessionRow is a session as the list page shows it.
+type sessionRow struct {
+	*session.Session
+	ResumeCommand string
+}
+
+func (s *Server) handleList(w http.ResponseWriter, r *http.Request) {
+	sessions, err := s.store.List()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to list sessions: %v", err), http.StatusInternalServerError)
+		return
+	}
+	slices.SortFunc(sessions, func(a, b *session.Session) int {
+		return b.Metadata.LastAccessed.Compare(a.Metadata.LastAccessed)
+	})
+
+	rows := make([]sessionRow, len(sessions))
+	for i, sess := range sessions {
+		rows[i] = sessionRow{Session: sess, ResumeCommand: s.resumeCommand(sess.Name)}
+	}
+	s.render(w, "list.html", map[string]any{
+		"Project":  s.resumeCd,
+		"Sessions": rows,
+		"Deleted":  r.URL.Query().Get("deleted"),
+		"Archived": r.URL.Query().Get("archived"),
+	})
+}
+
+// transcriptFile is one of a session's transcripts on the session page.
+type transcriptFile struct {
+	Path   string
+	Size   int64
+	Exists bool
+}
+
+func (s *Server) handleSession(w http.ResponseWriter, r *http.Request) {
+	sess, ok := s.session(w, r)
+	if !ok {
+		return
+	}
+
+	var transcripts []transcriptFile
+	for _, path := range claude.SessionTranscriptPaths(s.homeDir, s.root, sess) {
+		file := transcriptFile{Path: path}
+		if info, err := os.Stat(path); err == nil {
+			file.Size, file.Exists = info.Size(), true
+		}
+		transcripts = append(transcripts, file)
+	}
+
+	settings, err := s.store.LoadSettings(sess.Name)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to load settings: %v", err), http.StatusInternalServerError)
+		return
+	}
+	var settingsJSON string
+	if settings != nil {
+		data, _ := json.MarshalIndent(settings, "", "  ")
+		settingsJSON = string(data)
+	}
+
+	var forks []string
+	if sessions, err := s.store.List(); err == nil {
+		for _, other := range sessions {
+			if other.Metadata.IsForkedSession && other.Metadata.ParentSession == sess.Name {
+				forks = append(forks, other.Name)
+			}
+		}
+	}
+
+	s.render(w, "session.html", map[string]any{
+		"Session":       sess,
+		"ResumeCommand": s.resumeCommand(sess.Name),
+		"Transcripts":   transcripts,
+		"Settings":      settingsJSON,
+		"Forks":         forks,
+		"Token":         s.token,
+	})
+}
+
+func (s *Server) handleTranscript(w http.ResponseWriter, r *http.Request) {
+	sess, ok := s.session(w, r)
+	if !ok {
+		return
+	}
+
+	var entries []json.RawMessage
+	for _, path := range claude.SessionTranscriptPaths(s.homeDir, s.root, sess) {
+		f, err := os.Open(path)
+		if err != nil {
+			continue // previous transcript deleted or not yet written
+		}
+		filtered, err := export.FilterTranscript(f)
+		_ = f.Close()
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to read transcript %s: %v", path, err), http.StatusInternalServerError)
+			return
+		}
+		entries = append(entries, filtered...)
+	}
+
+	html, err := export.BuildHTML(sess.Name, entries)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to build transcript: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write([]byte(html))
+}
+
+func (s *Server) handleDelete(w http.ResponseWriter, r *http.Request) {
+	if !s.checkToken(w, r) {
+		return
+	}
+	sess, ok := s.session(w, r)
+	if !ok {
+		return
+	}
+
+	if _, err := app.DeleteSession(s.root, s.store, sess); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/?deleted="+url.QueryEscape(sess.Name), http.StatusSeeOther)
+}
+
+// handleArchive archives the session, or with archived false unarchives it.
+func (s *Server) handleArchive(archived bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.checkToken(w, r) {
+			return
+		}
+		sess, ok := s.session(w, r)
+		if !ok {
+			return
+		}
+
+		if err := app.CheckArchive(sess, archived); err != nil {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		if err := app.ArchiveSession(s.store, sess, archived); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if archived {
+			http.Redirect(w, r, "/?archived="+url.QueryEscape(sess.Name), http.StatusSeeOther)
+			return
+		}
+		http.Redirect(w, r, "/sessions/"+url.PathEscape(sess.Name), http.StatusSeeOther)
+	}
+}
+
+// checkToken answers 403 unless the posted form carries the page's token.
+func (s *Server) checkToken(w http.ResponseWriter, r *http.Request) bool {
+	if subtle.ConstantTimeCompare([]byte(r.PostFormValue("token")), []byte(s.token)) != 1 {
+		http.Error(w, "invalid form token; reload the page and try again", http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+// session loads the session named in the path, answering 404 when it
+// doesn't exist.
+func (s *Server) session(w http.ResponseWriter, r *http.Request) (*session.Session, bool) {
+	name := r.PathValue("name")
+	if session.ValidateStoredName(name) != nil || !s.store.Exists(name) {
+		http.Error(w, fmt.Sprintf("session '%s' not found", name), http.StatusNotFound)
+		return nil, false
+	}
+	sess, err := s.store.Get(name)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to load session: %v", err), http.StatusInternalServerError)
+		return nil, false
+	}
+	return sess, true
+}
+
+// resumeCommand is the shell command that resumes name from anywhere.
+func (s *Server) resumeCommand(name string) string {
+	return fmt.Sprintf("cd %s && clotilde resume %s", util.ShellQuote(s.resumeCd), name)
+}
+
+func (s *Server) render(w http.ResponseWriter, page string, data map[string]any) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := s.pages.ExecuteTemplate(w, page, data); err != nil {
+		http.Error(w, fmt.Sprintf("failed to render page: %v", err), http.StatusInternalServerError)
+	}
+}
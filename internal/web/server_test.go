@@ -0,0 +1,180 @@
+package web_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/session"
+	"github.com/fgrehm/clotilde/internal/web"
+)
+
+var _ = Describe("Server", func() {
+	var (
+		tempDir string
+		store   session.Store
+		server  *httptest.Server
+		client  *http.Client
+	)
+
+	BeforeEach(func() {
+		tempDir = GinkgoT().TempDir()
+		GinkgoT().Setenv("HOME", filepath.Join(tempDir, "home"))
+		GinkgoT().Setenv("XDG_CONFIG_HOME", filepath.Join(tempDir, "xdg"))
+		Expect(config.EnsureClotildeStructure(tempDir)).To(Succeed())
+		clotildeRoot := filepath.Join(tempDir, config.ClotildeDir)
+		store = session.NewFileStore(clotildeRoot)
+
+		transcript := filepath.Join(tempDir, "uuid-work.jsonl")
+		Expect(os.WriteFile(transcript, []byte(`{"type":"user","message":{"role":"user","content":"hello there"}}`+"\n"), 0o644)).To(Succeed())
+		sess := session.NewSession("work", "uuid-work")
+		sess.Metadata.Context = "GH-123"
+		sess.Metadata.TranscriptPath = transcript
+		Expect(store.Create(sess)).To(Succeed())
+
+		s, err := web.New(clotildeRoot)
+		Expect(err).NotTo(HaveOccurred())
+		server = httptest.NewServer(s.Handler("127.0.0.1"))
+		DeferCleanup(server.Close)
+
+		// Redirects are checked, not followed
+		client = &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	})
+
+	get := func(path string) (int, string) {
+		resp, err := client.Get(server.URL + path)
+		Expect(err).NotTo(HaveOccurred())
+		defer func() { _ = resp.Body.Close() }()
+		body, err := io.ReadAll(resp.Body)
+		Expect(err).NotTo(HaveOccurred())
+		return resp.StatusCode, string(body)
+	}
+
+	It("lists sessions with their resume command", func() {
+		status, body := get("/")
+		Expect(status).To(Equal(http.StatusOK))
+		Expect(body).To(ContainSubstring(`<a href="/sessions/work">work</a>`))
+		Expect(body).To(ContainSubstring("GH-123"))
+		Expect(body).To(ContainSubstring("clotilde resume work"))
+	})
+
+	It("shows a session and its transcripts", func() {
+		status, body := get("/sessions/work")
+		Expect(status).To(Equal(http.StatusOK))
+		Expect(body).To(ContainSubstring("uuid-work"))
+		Expect(body).To(ContainSubstring("uuid-work.jsonl"))
+
+		status, body = get("/sessions/work/transcript")
+		Expect(status).To(Equal(http.StatusOK))
+		Expect(body).To(ContainSubstring("<html"))
+	})
+
+	It("answers 404 for unknown or invalid names", func() {
+		status, _ := get("/sessions/missing")
+		Expect(status).To(Equal(http.StatusNotFound))
+		status, _ = get("/sessions/..%2Fescape")
+		Expect(status).To(Equal(http.StatusNotFound))
+	})
+
+	It("deletes a session only with the page's form token", func() {
+		resp, err := client.PostForm(server.URL+"/sessions/work/delete", url.Values{"token": {"forged"}})
+		Expect(err).NotTo(HaveOccurred())
+		_ = resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusForbidden))
+		Expect(store.Exists("work")).To(BeTrue())
+
+		_, body := get("/sessions/work")
+		token := regexp.MustCompile(`name="token" value="([0-9a-f]+)"`).FindStringSubmatch(body)
+		Expect(token).To(HaveLen(2))
+
+		resp, err = client.PostForm(server.URL+"/sessions/work/delete", url.Values{"token": {token[1]}})
+		Expect(err).NotTo(HaveOccurred())
+		_ = resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusSeeOther))
+		Expect(resp.Header.Get("Location")).To(Equal("/?deleted=work"))
+		Expect(store.Exists("work")).To(BeFalse())
+
+		_, body = get("/?deleted=work")
+		Expect(body).To(ContainSubstring("Deleted session 'work'"))
+		Expect(strings.Contains(body, `href="/sessions/work"`)).To(BeFalse())
+	})
+
+	It("archives and unarchives a session", func() {
+		_, body := get("/sessions/work")
+		token := regexp.MustCompile(`name="token" value="([0-9a-f]+)"`).FindStringSubmatch(body)
+		Expect(token).To(HaveLen(2))
+
+		resp, err := client.PostForm(server.URL+"/sessions/work/archive", url.Values{"token": {token[1]}})
+		Expect(err).NotTo(HaveOccurred())
+		_ = resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusSeeOther))
+		Expect(resp.Header.Get("Location")).To(Equal("/?archived=work"))
+		sess, err := store.Get("work")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sess.Status()).To(Equal(session.StatusArchived))
+
+		_, body = get("/sessions/work")
+		Expect(body).To(ContainSubstring("Unarchive session"))
+		resp, err = client.PostForm(server.URL+"/sessions/work/unarchive", url.Values{"token": {token[1]}})
+		Expect(err).NotTo(HaveOccurred())
+		_ = resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusSeeOther))
+		sess, err = store.Get("work")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sess.Status()).To(Equal(session.StatusIdle))
+	})
+
+	It("refuses to archive a running session", func() {
+		sess, err := store.Get("work")
+		Expect(err).NotTo(HaveOccurred())
+		sess.Metadata.Status = session.StatusActive
+		Expect(store.Update(sess)).To(Succeed())
+
+		_, body := get("/sessions/work")
+		Expect(body).NotTo(ContainSubstring("Archive session"))
+		token := regexp.MustCompile(`name="token" value="([0-9a-f]+)"`).FindStringSubmatch(body)
+		resp, err := client.PostForm(server.URL+"/sessions/work/archive", url.Values{"token": {token[1]}})
+		Expect(err).NotTo(HaveOccurred())
+		_ = resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusConflict))
+	})
+
+	It("refuses requests for other hosts, which a rebound domain would send", func() {
+		req, err := http.NewRequest(http.MethodGet, server.URL+"/", nil)
+		Expect(err).NotTo(HaveOccurred())
+		req.Host = "attacker.example:8787"
+		resp, err := client.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		_ = resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusMisdirectedRequest))
+
+		req.Host = "localhost:8787"
+		resp, err = client.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		_ = resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+	})
+
+	It("refuses forms posted from other sites", func() {
+		_, body := get("/sessions/work")
+		token := regexp.MustCompile(`name="token" value="([0-9a-f]+)"`).FindStringSubmatch(body)
+		req, err := http.NewRequest(http.MethodPost, server.URL+"/sessions/work/delete", strings.NewReader(url.Values{"token": {token[1]}}.Encode()))
+		Expect(err).NotTo(HaveOccurred())
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("Origin", "http://attacker.example")
+		resp, err := client.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		_ = resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusForbidden))
+		Expect(store.Exists("work")).To(BeTrue())
+	})
+})
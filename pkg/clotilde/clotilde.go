@@ -0,0 +1,293 @@
+// Package clotilde is the Go API for managing a project's named Claude Code
+// sessions, for programs (bots, TUIs, web dashboards) that would otherwise
+// shell out to the CLI. It runs the same operations as the clotilde commands
+// and reads and writes the same .claude/clotilde directory.
+//
+// Profiles and the project's default model/effort are applied by the CLI
+// only; sessions launched through this package use their own settings.json.
+package clotilde
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fgrehm/clotilde/internal/app"
+	"github.com/fgrehm/clotilde/internal/config"
+	"github.com/fgrehm/clotilde/internal/errs"
+	"github.com/fgrehm/clotilde/internal/session"
+)
+
+// Session is a named Claude Code session of the project.
+type Session struct {
+	Name               string
+	SessionID          string   // Claude Code's session ID
+	PreviousSessionIDs []string // IDs superseded by /clear, oldest first
+	Created            time.Time
+	LastAccessed       time.Time
+	Parent             string    // The session it was forked from; empty for other sessions
+	Incognito          bool      // Deleted when Claude Code exits
+	Context            string    // e.g. "working on ticket GH-123"
+	ExpiresAt          time.Time // Zero when the session never expires
+	Pending            bool      // Not launched yet; Claude Code starts on the first Resume
+	Status             string    // "active", "idle", "archived", "expired" or "broken"
+	Starred            bool
+}
+
+// Settings are the Claude Code settings of a session, written to its
+// settings.json.
+type Settings struct {
+	Model       string
+	EffortLevel string
+	OutputStyle string
+	Permissions Permissions
+}
+
+// Permissions are the tool permission rules of a session's settings.
+type Permissions struct {
+	Allow                        []string
+	Ask                          []string
+	Deny                         []string
+	AdditionalDirectories        []string
+	DefaultMode                  string
+	DisableBypassPermissionsMode string
+}
+
+// CreateOptions configure a new session.
+type CreateOptions struct {
+	Context   string    // e.g. "working on ticket GH-123"
+	ExpiresAt time.Time // Zero means the session never expires
+	Settings  *Settings // Written to the session's settings.json when set
+}
+
+// ResumeOptions adjust how a session is resumed.
+type ResumeOptions struct {
+	Context  string   // Replaces the session's context when set
+	Args     []string // Extra claude CLI arguments
+	Snippets string   // Text injected into the session's context for this launch only
+}
+
+// ForkOptions configure a new fork.
+type ForkOptions struct {
+	Context         string // Defaults to the parent's context unless NoParentContext
+	NoParentContext bool
+	ExpiresAt       time.Time
+}
+
+// DeleteResult reports what Delete removed.
+type DeleteResult struct {
+	Transcripts []string // Transcript file paths that were deleted
+	AgentLogs   []string // Agent log file paths that were deleted
+	Detached    []string // Forks turned into regular sessions
+	StyleUsers  []string // Other sessions using its custom output style, which was kept
+	Warnings    []string // Cleanup that failed without stopping the deletion
+}
+
+// Project is the clotilde session storage of one project.
+type Project struct {
+	root      string
+	store     session.Store
+	claudeBin string
+}
+
+// Option configures a Project when it's opened.
+type Option func(*Project)
+
+// WithClaudeBinary sets the claude executable that Resume runs, "claude"
+// (from PATH) by default.
+func WithClaudeBinary(path string) Option {
+	return func(p *Project) { p.claudeBin = path }
+}
+
+// Open finds the project's .claude/clotilde directory from dir upwards, as the
+// CLI does from the working directory. The error satisfies IsNotInProject
+// when there is none.
+func Open(dir string, opts ...Option) (*Project, error) {
+	root, err := config.ClotildeRootFromPath(dir)
+	if err != nil {
+		return nil, err
+	}
+	p := &Project{root: root, store: session.NewFileStore(root), claudeBin: "claude"}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p, nil
+}
+
+// Init creates .claude/clotilde in projectDir if needed and opens it.
+func Init(projectDir string, opts ...Option) (*Project, error) {
+	if err := config.EnsureClotildeStructure(projectDir); err != nil {
+		return nil, fmt.Errorf("failed to initialize session storage: %w", err)
+	}
+	return Open(projectDir, opts...)
+}
+
+// Root returns the project's .claude/clotilde directory.
+func (p *Project) Root() string {
+	return p.root
+}
+
+// List returns every session of the project.
+func (p *Project) List() ([]*Session, error) {
+	sessions, err := p.store.List()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*Session, len(sessions))
+	for i, sess := range sessions {
+		result[i] = fromSession(sess)
+	}
+	return result, nil
+}
+
+// Get returns the named session.
+func (p *Project) Get(name string) (*Session, error) {
+	sess, err := p.get(name)
+	if err != nil {
+		return nil, err
+	}
+	return fromSession(sess), nil
+}
+
+func (p *Project) get(name string) (*session.Session, error) {
+	if !p.store.Exists(name) {
+		return nil, errs.NotFound("session '%s' not found", name)
+	}
+	return p.store.Get(name)
+}
+
+// Create creates a session without launching Claude Code, like
+// 'clotilde start --no-launch': it starts on its first Resume. The name must
+// follow the project's naming rules.
+func (p *Project) Create(name string, opts CreateOptions) (*Session, error) {
+	sess, err := app.CreateSession(p.root, p.store, name, app.CreateOptions{
+		Context:   opts.Context,
+		ExpiresAt: opts.ExpiresAt,
+		Settings:  opts.Settings.toSettings(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fromSession(sess), nil
+}
+
+// Fork creates forkName from parent without launching Claude Code; it starts
+// from the parent on its first Resume. The fork gets copies of the parent's
+// settings, custom output style and env file.
+func (p *Project) Fork(parent, forkName string, opts ForkOptions) (*Session, error) {
+	rules, err := session.LoadNameRules(p.root)
+	if err != nil {
+		return nil, fmt.Errorf("invalid naming config: %w", err)
+	}
+	if err := rules.Validate(forkName); err != nil {
+		return nil, err
+	}
+	if p.store.Exists(forkName) {
+		return nil, errs.AlreadyExists("session '%s' already exists", forkName)
+	}
+
+	parentSess, err := p.get(parent)
+	if err != nil {
+		return nil, err
+	}
+	fork, err := app.ForkSession(p.root, p.store, parentSess, forkName, app.ForkOptions{
+		Context:         opts.Context,
+		NoParentContext: opts.NoParentContext,
+		ExpiresAt:       opts.ExpiresAt,
+		Pending:         true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fromSession(fork), nil
+}
+
+// Resume launches Claude Code on the named session, attached to this
+// process's terminal, and returns when it exits.
+func (p *Project) Resume(name string, opts ResumeOptions) error {
+	sess, err := p.get(name)
+	if err != nil {
+		return err
+	}
+	return app.ResumeSession(p.root, p.store, sess, app.ResumeOptions{
+		Context:  opts.Context,
+		Args:     opts.Args,
+		Snippets: opts.Snippets,
+		Claude:   p.claudeBin,
+	})
+}
+
+// Delete deletes the named session with its Claude Code transcripts and
+// agent logs. Its forks become regular sessions.
+func (p *Project) Delete(name string) (*DeleteResult, error) {
+	sess, err := p.get(name)
+	if err != nil {
+		return nil, err
+	}
+	result, err := app.DeleteSession(p.root, p.store, sess)
+	if result == nil {
+		return nil, err
+	}
+	return &DeleteResult{
+		Transcripts: result.Transcripts,
+		AgentLogs:   result.AgentLogs,
+		Detached:    result.Detached,
+		StyleUsers:  result.StyleUsers,
+		Warnings:    result.Warnings,
+	}, err
+}
+
+func fromSession(sess *session.Session) *Session {
+	m := sess.Metadata
+	previous := make([]string, len(m.PreviousSessions))
+	for i, p := range m.PreviousSessions {
+		previous[i] = p.SessionID
+	}
+	return &Session{
+		Name:               sess.Name,
+		SessionID:          m.SessionID,
+		PreviousSessionIDs: previous,
+		Created:            m.Created,
+		LastAccessed:       m.LastAccessed,
+		Parent:             m.ParentSession,
+		Incognito:          m.IsIncognito,
+		Context:            m.Context,
+		ExpiresAt:          m.ExpiresAt,
+		Pending:            m.PendingLaunch,
+		Status:             sess.Status(),
+		Starred:            m.Starred,
+	}
+}
+
+func (s *Settings) toSettings() *session.Settings {
+	if s == nil {
+		return nil
+	}
+	return &session.Settings{
+		Model:       s.Model,
+		EffortLevel: s.EffortLevel,
+		OutputStyle: s.OutputStyle,
+		Permissions: session.Permissions(s.Permissions),
+	}
+}
+
+// IsNotFound reports whether err is about a session that doesn't exist.
+func IsNotFound(err error) bool {
+	return isKind(err, errs.KindNotFound)
+}
+
+// IsAlreadyExists reports whether err is about a session name that's taken.
+func IsAlreadyExists(err error) bool {
+	return isKind(err, errs.KindAlreadyExists)
+}
+
+// IsNotInProject reports whether err is about a directory without clotilde
+// session storage.
+func IsNotInProject(err error) bool {
+	return isKind(err, errs.KindNotInProject)
+}
+
+func isKind(err error, kind errs.Kind) bool {
+	var e *errs.Error
+	return errors.As(err, &e) && e.Kind == kind
+}
@@ -0,0 +1,13 @@
+package clotilde_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestClotilde(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Clotilde API Suite")
+}
@@ -0,0 +1,95 @@
+package clotilde_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fgrehm/clotilde/internal/testutil"
+	"github.com/fgrehm/clotilde/pkg/clotilde"
+)
+
+var _ = Describe("Project", func() {
+	var (
+		tempDir string
+		project *clotilde.Project
+	)
+
+	BeforeEach(func() {
+		tempDir = GinkgoT().TempDir()
+		GinkgoT().Setenv("HOME", filepath.Join(tempDir, "home"))
+		GinkgoT().Setenv("XDG_CONFIG_HOME", filepath.Join(tempDir, "xdg"))
+
+		var err error
+		project, err = clotilde.Init(tempDir)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("opens the project from a subdirectory", func() {
+		subdir := filepath.Join(tempDir, "src", "pkg")
+		Expect(os.MkdirAll(subdir, 0o755)).To(Succeed())
+
+		opened, err := clotilde.Open(subdir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(opened.Root()).To(Equal(project.Root()))
+	})
+
+	It("reports directories without session storage", func() {
+		_, err := clotilde.Open(GinkgoT().TempDir())
+		Expect(clotilde.IsNotInProject(err)).To(BeTrue())
+	})
+
+	It("creates, lists, forks and deletes sessions", func() {
+		sess, err := project.Create("api-work", clotilde.CreateOptions{
+			Context:  "GH-123",
+			Settings: &clotilde.Settings{Model: "sonnet"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sess.SessionID).NotTo(BeEmpty())
+		Expect(sess.Pending).To(BeTrue())
+		Expect(sess.Status).To(Equal("idle"))
+
+		_, err = project.Create("api-work", clotilde.CreateOptions{})
+		Expect(clotilde.IsAlreadyExists(err)).To(BeTrue())
+		_, err = project.Create("Not Valid", clotilde.CreateOptions{})
+		Expect(err).To(HaveOccurred())
+
+		fork, err := project.Fork("api-work", "api-spike", clotilde.ForkOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fork.Parent).To(Equal("api-work"))
+		Expect(fork.Context).To(Equal("GH-123"))
+
+		sessions, err := project.List()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sessions).To(HaveLen(2))
+
+		result, err := project.Delete("api-work")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Detached).To(ConsistOf("api-spike"))
+
+		_, err = project.Get("api-work")
+		Expect(clotilde.IsNotFound(err)).To(BeTrue())
+		_, err = project.Delete("api-work")
+		Expect(clotilde.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("launches claude on Resume", func() {
+		binary, argsFile, err := testutil.CreateFakeClaude(tempDir)
+		Expect(err).NotTo(HaveOccurred())
+		project, err := clotilde.Open(tempDir, clotilde.WithClaudeBinary(binary))
+		Expect(err).NotTo(HaveOccurred())
+
+		sess, err := project.Create("api-work", clotilde.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(project.Resume("api-work", clotilde.ResumeOptions{Args: []string{"--debug"}})).To(Succeed())
+
+		args, err := testutil.ReadClaudeArgs(argsFile)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(args).To(ContainSubstring(sess.SessionID))
+		Expect(args).To(ContainSubstring("--debug"))
+
+		Expect(project.Resume("missing", clotilde.ResumeOptions{})).To(Satisfy(clotilde.IsNotFound))
+	})
+})